@@ -1,17 +1,61 @@
 package main
 
 import (
+	"ads-service/internal/config"
 	"ads-service/internal/migrations"
 	adRepository "ads-service/internal/repository/ad"
 	adFileRepository "ads-service/internal/repository/adFile"
+	apiKeyRepository "ads-service/internal/repository/apikey"
+	appealRepository "ads-service/internal/repository/appeal"
 	authRepository "ads-service/internal/repository/auth"
+	favoriteRepository "ads-service/internal/repository/favorite"
+	fingerprintRepository "ads-service/internal/repository/fingerprint"
+	historyRepository "ads-service/internal/repository/history"
+	idempotencyRepository "ads-service/internal/repository/idempotency"
+	lockoutRepository "ads-service/internal/repository/lockout"
+	messageRepository "ads-service/internal/repository/message"
+	notificationRepository "ads-service/internal/repository/notification"
+	rejectionRepository "ads-service/internal/repository/rejection"
+	reportRepository "ads-service/internal/repository/report"
+	ruleHitRepository "ads-service/internal/repository/rulehit"
+	savedSearchRepository "ads-service/internal/repository/savedsearch"
+	statisticsRepository "ads-service/internal/repository/statistics"
+	twoFactorRepository "ads-service/internal/repository/twofactor"
 	userRepository "ads-service/internal/repository/user"
 	adminHandler "ads-service/internal/rest/handlers/admin"
+	apiKeyHandler "ads-service/internal/rest/handlers/apikey"
+	appealHandler "ads-service/internal/rest/handlers/appeal"
 	authHandler "ads-service/internal/rest/handlers/auth"
+	duplicateHandler "ads-service/internal/rest/handlers/duplicate"
+	eventsHandler "ads-service/internal/rest/handlers/events"
+	exportHandler "ads-service/internal/rest/handlers/export"
+	favoriteHandler "ads-service/internal/rest/handlers/favorite"
+	healthHandler "ads-service/internal/rest/handlers/health"
+	messageHandler "ads-service/internal/rest/handlers/message"
+	notificationHandler "ads-service/internal/rest/handlers/notification"
+	preModerationHandler "ads-service/internal/rest/handlers/premoderation"
+	rejectionHandler "ads-service/internal/rest/handlers/rejection"
+	reportHandler "ads-service/internal/rest/handlers/report"
+	savedSearchHandler "ads-service/internal/rest/handlers/savedsearch"
+	twoFactorHandler "ads-service/internal/rest/handlers/twofactor"
 	userHandler "ads-service/internal/rest/handlers/user"
 	mv "ads-service/internal/rest/middleware"
 	adminService "ads-service/internal/usecase/admin"
+	apiKeyService "ads-service/internal/usecase/apikey"
+	appealService "ads-service/internal/usecase/appeal"
 	authService "ads-service/internal/usecase/auth"
+	duplicateService "ads-service/internal/usecase/duplicate"
+	exportService "ads-service/internal/usecase/export"
+	favoriteService "ads-service/internal/usecase/favorite"
+	idempotencyService "ads-service/internal/usecase/idempotency"
+	lockoutService "ads-service/internal/usecase/lockout"
+	messageService "ads-service/internal/usecase/message"
+	notificationService "ads-service/internal/usecase/notification"
+	preModerationService "ads-service/internal/usecase/premoderation"
+	rejectionService "ads-service/internal/usecase/rejection"
+	reportService "ads-service/internal/usecase/report"
+	savedSearchService "ads-service/internal/usecase/savedsearch"
+	twoFactorService "ads-service/internal/usecase/twofactor"
 	userService "ads-service/internal/usecase/user"
 	customLogger "ads-service/pkg/logger"
 	"context"
@@ -19,72 +63,212 @@ import (
 
 	"ads-service/internal/rest"
 	"ads-service/pkg/db"
+	"ads-service/pkg/eventbus"
+	"ads-service/pkg/jwtkeys"
+	"ads-service/pkg/notifier"
+	"ads-service/pkg/tracing"
 	"errors"
+	"io/fs"
 	"log"
+	"log/slog"
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 
 	_ "ads-service/docs"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/dig"
 )
 
 func main() {
-	if err := godotenv.Load(".env"); err != nil {
-		log.Println("Error loading .env file, using default values")
+	// .env is optional, the environment and flags may configure everything
+	if err := godotenv.Load(".env"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Println("Error loading .env file:", err)
 		os.Exit(1)
 	}
-	log.Println("Env vars loaded successfully")
-	var (
-		port = os.Getenv("PORT")
-		host = os.Getenv("HOST")
-		dsn  = os.Getenv("DATABASE_URL")
-	)
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Println("Error loading configuration:", err)
+		os.Exit(1)
+	}
+	log.Printf("Configuration loaded:\n%s", cfg)
 
-	if err := migrations.New("file://internal/migrations", dsn); err != nil {
+	if err = migrations.New("file://internal/migrations", cfg.Database.URL.Value()); err != nil {
 		log.Println("Error running migrations:", err)
 		os.Exit(1)
 	}
 
-	if err := execute(host, port, dsn); err != nil {
+	if err = execute(cfg); err != nil {
 		log.Println(err)
 		os.Exit(1)
 	}
 }
 
-func execute(host, port, dsn string) error {
+func execute(cfg config.Config) error {
+	// canceled by SIGTERM or Ctrl+C, stops the server and the background jobs
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	// canceled last, so that the shutdown itself is logged
+	logCtx, stopLogger := context.WithCancel(context.Background())
+	defer stopLogger()
+
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		Exporter:     cfg.Tracing.Exporter,
+		OTLPEndpoint: cfg.Tracing.OTLPEndpoint,
+		ServiceName:  cfg.Tracing.ServiceName,
+	})
+	if err != nil {
+		log.Println("failed to set up tracing:", err)
+		return errors.New("failed to set up tracing")
+	}
+	defer func() {
+		// ctx is already canceled here, the spans of the last requests still get a few seconds
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			log.Println("failed to flush traces:", err)
+		}
+	}()
+
 	deps := []interface{}{
-		func() (customLogger.Logger, error) {
-			return customLogger.NewLogger(context.TODO())
+		func(cfg config.Config) (customLogger.Logger, error) {
+			logger, err := customLogger.New(logCtx, customLogger.Options{
+				Dir:         cfg.Log.Dir,
+				Level:       cfg.Log.Level,
+				Format:      cfg.Log.Format,
+				RotateEvery: cfg.Log.RotateEvery,
+				Retention:   cfg.Log.Retention,
+				MaxSize:     int64(cfg.Log.MaxSizeMB) << 20,
+				MaxBackups:  cfg.Log.MaxBackups,
+				Stdout:      cfg.Log.Stdout,
+			})
+			if err != nil {
+				return customLogger.Logger{}, err
+			}
+			// the log package and code without a logger at hand write to the same files
+			slog.SetDefault(logger.Slog())
+			return logger, nil
+		},
+		func() config.Config {
+			return cfg
 		},
-		func() (db.Pool, error) {
-			return db.NewDB(dsn)
+		func(cfg config.Config) (db.Pool, error) {
+			return db.NewDB(cfg.Database.URL.Value())
 		},
+		db.NewTxManager,
 		func() *gin.Engine {
 			return gin.New()
 		},
+		func(cfg config.Config) (*jwtkeys.KeySet, error) {
+			return jwtkeys.New(cfg.Auth.KeysDir, cfg.Auth.SigningKeyID, cfg.Auth.JWTSecret.Value())
+		},
+		func(cfg config.Config) (notifier.Channels, error) {
+			return notifier.NewChannels(cfg.Notify.File, cfg.Notify.SMTPAddr, cfg.Notify.SMTPFrom)
+		},
+		// use cases notify through the notification center, which picks the user's channels
+		func(center notificationService.NotificationService) notifier.Notifier {
+			return center
+		},
+		eventbus.New,
+		func(cfg config.Config) (preModerationService.Rules, error) {
+			return preModerationService.LoadRules(cfg.Moderation.RulesFile)
+		},
+		func(cfg config.Config) duplicateService.Config {
+			return duplicateService.Config{
+				RepostWindow: cfg.Moderation.RepostWindow,
+				MaxCompared:  cfg.Moderation.MaxCompared,
+			}
+		},
+		func(cfg config.Config) authService.Config {
+			return authService.Config{
+				AccessTokenLifetime:  cfg.Auth.AccessTokenLifetime,
+				RefreshTokenLifetime: cfg.Auth.RefreshTokenLifetime,
+			}
+		},
+		func(cfg config.Config) twoFactorService.Config {
+			return twoFactorService.Config{Issuer: cfg.Auth.TOTPIssuer}
+		},
+		func(cfg config.Config) idempotencyService.Config {
+			return idempotencyService.Config{TTL: cfg.Idempotency.TTL}
+		},
+		func(cfg config.Config) mv.Config {
+			return mv.Config{
+				AdminMFARequired:   cfg.Auth.AdminMFARequired,
+				IdempotencyMaxBody: int64(cfg.Idempotency.MaxBodyMB) << 20,
+			}
+		},
+		func(cfg config.Config) rest.Config {
+			return rest.Config{TrustedProxies: cfg.HTTP.TrustedProxies}
+		},
 		authHandler.NewAuthHandler,
 		userHandler.NewUserHandler,
 		adminHandler.NewAdminHandler,
+		twoFactorHandler.NewTwoFactorHandler,
+		apiKeyHandler.NewAPIKeyHandler,
+		favoriteHandler.NewFavoriteHandler,
+		savedSearchHandler.NewSavedSearchHandler,
+		messageHandler.NewMessageHandler,
+		notificationHandler.NewNotificationHandler,
+		reportHandler.NewReportHandler,
+		preModerationHandler.NewPreModerationHandler,
+		duplicateHandler.NewDuplicateHandler,
+		rejectionHandler.NewRejectionHandler,
+		appealHandler.NewAppealHandler,
+		exportHandler.NewExportHandler,
+		eventsHandler.NewEventsHandler,
+		healthHandler.NewHealthHandler,
 
 		authService.NewAuthService,
 		adminService.NewAdminService,
 		userService.NewUserService,
+		twoFactorService.NewTwoFactorService,
+		apiKeyService.NewAPIKeyService,
+		favoriteService.NewFavoriteService,
+		savedSearchService.NewSavedSearchService,
+		messageService.NewMessageService,
+		notificationService.NewNotificationService,
+		reportService.NewReportService,
+		preModerationService.NewPreModerationService,
+		duplicateService.NewDuplicateService,
+		rejectionService.NewRejectionService,
+		appealService.NewAppealService,
+		exportService.NewExportService,
+		idempotencyService.NewIdempotencyService,
+		lockoutService.NewLockoutService,
 
 		authRepository.NewAuthRepo,
 		userRepository.NewUserRepo,
 		adFileRepository.NewAdFileRepo,
 		adRepository.NewAdRepo,
+		lockoutRepository.NewLockoutRepo,
+		twoFactorRepository.NewTwoFactorRepo,
+		apiKeyRepository.NewAPIKeyRepo,
+		favoriteRepository.NewFavoriteRepo,
+		savedSearchRepository.NewSavedSearchRepo,
+		messageRepository.NewMessageRepo,
+		notificationRepository.NewNotificationRepo,
+		reportRepository.NewReportRepo,
+		ruleHitRepository.NewRuleHitRepo,
+		fingerprintRepository.NewFingerprintRepo,
+		rejectionRepository.NewRejectionRepo,
+		historyRepository.NewHistoryRepo,
+		appealRepository.NewAppealRepo,
+		statisticsRepository.NewStatisticsRepo,
+		idempotencyRepository.NewIdempotencyRepo,
 
 		mv.NewMiddleware,
 
 		http.NewServeMux,
 		rest.NewServer,
-		func(server *rest.Server) *http.Server {
+		func(server *rest.Server, cfg config.Config) *http.Server {
 			return &http.Server{
-				Addr:              net.JoinHostPort(host, port),
+				Addr:              net.JoinHostPort(cfg.HTTP.Host, cfg.HTTP.Port),
 				Handler:           server,
 				ReadHeaderTimeout: 10 * time.Second,
 			}
@@ -99,7 +283,7 @@ func execute(host, port, dsn string) error {
 		}
 	}
 
-	err := container.Invoke(func(server *rest.Server) {
+	err = container.Invoke(func(server *rest.Server) {
 		server.Init()
 	})
 	if err != nil {
@@ -107,8 +291,66 @@ func execute(host, port, dsn string) error {
 		return errors.New("failed to initialize server")
 	}
 
-	err = container.Invoke(func(server *http.Server) error {
-		return server.ListenAndServe()
+	err = container.Invoke(func(pool db.Pool, statsRepo statisticsRepository.StatisticsRepository) error {
+		if err := db.RegisterMetrics(prometheus.DefaultRegisterer, pool); err != nil {
+			return err
+		}
+		return adminService.RegisterMetrics(prometheus.DefaultRegisterer, statsRepo, cfg.Metrics.CacheTTL)
+	})
+	if err != nil {
+		log.Println("failed to register metrics:", err)
+		return errors.New("failed to register metrics")
+	}
+
+	// the jobs use the database, the pool is closed only after they stop
+	var jobs sync.WaitGroup
+	if cfg.Metrics.Addr != "" {
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			if err := serveMetrics(ctx, cfg.Metrics.Addr); err != nil {
+				log.Println("metrics server failed:", err)
+			}
+		}()
+	}
+	err = container.Invoke(func(searches savedSearchService.SavedSearchService) {
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			searches.RunDigests(ctx, cfg.SavedSearch.DigestInterval)
+		}()
+	})
+	if err != nil {
+		log.Println("failed to start saved search digest:", err)
+		return errors.New("failed to start saved search digest")
+	}
+
+	err = container.Invoke(func(keys idempotencyService.IdempotencyService) {
+		// expired keys are taken over anyway, the cleanup only keeps the table small
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			keys.RunCleanup(ctx, cfg.Idempotency.TTL)
+		}()
+	})
+	if err != nil {
+		log.Println("failed to start idempotency key cleanup:", err)
+		return errors.New("failed to start idempotency key cleanup")
+	}
+
+	err = container.Invoke(func(server *http.Server, health *healthHandler.HealthHandler, bus eventbus.Bus,
+		pool db.Pool, logger customLogger.Logger) error {
+		defer func() {
+			// a failed server leaves ctx running, the jobs have to be stopped here
+			stop()
+			jobs.Wait()
+			pool.Close()
+			stopLogger()
+			logger.Wait()
+		}()
+		// event streams never finish on their own, closing the bus ends them
+		server.RegisterOnShutdown(bus.Close)
+		return serve(ctx, server, health, cfg.HTTP.ShutdownDelay, cfg.HTTP.ShutdownTimeout)
 	})
 
 	if err != nil {
@@ -117,3 +359,51 @@ func execute(host, port, dsn string) error {
 	}
 	return nil
 }
+
+// serve runs the server until ctx is canceled. Then readiness fails, the server keeps serving for delay
+// while load balancers notice it, and in-flight requests get the timeout to finish. Connections still open
+// after it are closed.
+func serve(ctx context.Context, server *http.Server, health *healthHandler.HealthHandler,
+	delay, timeout time.Duration) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+	health.Drain()
+	log.Println("shutting down, serving for", delay, "while readiness fails")
+	time.Sleep(delay)
+	log.Println("waiting for in-flight requests up to", timeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Println("requests did not finish in time, closing connections:", err)
+		return server.Close()
+	}
+	return nil
+}
+
+// serveMetrics serves the Prometheus metrics on their own listener until ctx is canceled, so they are not
+// reachable through the public port.
+func serveMetrics(ctx context.Context, addr string) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           promhttp.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	stop := context.AfterFunc(ctx, func() {
+		_ = server.Close()
+	})
+	defer stop()
+
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
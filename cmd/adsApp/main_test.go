@@ -1,7 +1,16 @@
 package main
 
 import (
+	"ads-service/internal/config"
+	healthHandler "ads-service/internal/rest/handlers/health"
+	"ads-service/pkg/db"
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
 )
 
 func TestExecute_InvalidDSN(t *testing.T) {
@@ -10,8 +19,39 @@ func TestExecute_InvalidDSN(t *testing.T) {
 			t.Errorf("ожидалась паника, но её не было")
 		}
 	}()
-	err := execute("127.0.0.1", "8080", "")
+	cfg := config.Default()
+	cfg.HTTP.Host = "127.0.0.1"
+	err := execute(cfg)
 	if err == nil {
 		t.Errorf("ожидалась ошибка, но err == nil")
 	}
 }
+
+func TestServe_Shutdown(t *testing.T) {
+	health := healthHandler.NewHealthHandler(new(db.MockPool))
+	server := &http.Server{Addr: "127.0.0.1:0", ReadHeaderTimeout: time.Second}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+	go func() {
+		done <- serve(ctx, server, health, 10*time.Millisecond, time.Second)
+	}()
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("ожидалась остановка без ошибки, получено: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("сервер не остановился")
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	health.Readyz(c)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("после остановки readyz должен возвращать 503, получено: %d", w.Code)
+	}
+}
@@ -0,0 +1,277 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/netip"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const fileEnv = "CONFIG_FILE"
+
+// Default returns the settings used when nothing overrides them.
+func Default() Config {
+	return Config{
+		HTTP: HTTP{
+			Port:            "8080",
+			ShutdownDelay:   5 * time.Second,
+			ShutdownTimeout: 15 * time.Second,
+		},
+		Auth: Auth{
+			TOTPIssuer:           "GoAds",
+			AccessTokenLifetime:  15 * time.Minute,
+			RefreshTokenLifetime: 30 * 24 * time.Hour,
+		},
+		Moderation: Moderation{
+			MaxCompared: 1000,
+		},
+		SavedSearch: SavedSearch{
+			DigestInterval: 24 * time.Hour,
+		},
+		Idempotency: Idempotency{
+			TTL:       24 * time.Hour,
+			MaxBodyMB: 10,
+		},
+		Log: Log{
+			Dir:         "storage/logs",
+			Level:       "info",
+			Format:      "text",
+			RotateEvery: 24 * time.Hour,
+			Retention:   7 * 24 * time.Hour,
+			MaxSizeMB:   100,
+			Stdout:      true,
+		},
+		Tracing: Tracing{
+			Exporter:    "none",
+			ServiceName: "ads-service",
+		},
+		Metrics: Metrics{
+			Addr:     ":9090",
+			CacheTTL: 30 * time.Second,
+		},
+	}
+}
+
+// Load reads the configuration from the file, the environment and the command line args and validates it.
+func Load(args []string) (Config, error) {
+	cfg := Default()
+	fields := fieldsOf(&cfg)
+
+	flags := flag.NewFlagSet("ads-service", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	path := flags.String("config", os.Getenv(fileEnv), "path to the YAML configuration file")
+	values := make(map[string]*string, len(fields))
+	for _, f := range fields {
+		values[f.env] = flags.String(flagName(f.env), "", f.env)
+	}
+	if err := flags.Parse(args); err != nil {
+		return Config{}, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	if *path != "" {
+		if err := readFile(*path, &cfg); err != nil {
+			return Config{}, err
+		}
+	}
+
+	var errs []error
+	for _, f := range fields {
+		if value, ok := os.LookupEnv(f.env); ok && value != "" {
+			errs = append(errs, f.set(value))
+		}
+	}
+	flags.Visit(func(fl *flag.Flag) {
+		if fl.Name != "config" {
+			errs = append(errs, fields[envName(fl.Name)].set(fl.Value.String()))
+		}
+	})
+	if err := errors.Join(errs...); err != nil {
+		return Config{}, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+func readFile(path string, cfg *Config) error {
+	// #nosec G304 -- путь задаётся администратором при запуске
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrReadingFile, err)
+	}
+	if err = yaml.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("%w: %w", ErrReadingFile, err)
+	}
+	return nil
+}
+
+// Validate reports every invalid setting at once.
+func (c Config) Validate() error {
+	var errs []error
+	if port, err := strconv.Atoi(c.HTTP.Port); err != nil || port <= 0 || port > 65535 {
+		errs = append(errs, fmt.Errorf("PORT must be a number between 1 and 65535, got %q", c.HTTP.Port))
+	}
+	if c.Database.URL == "" {
+		errs = append(errs, errors.New("DATABASE_URL is required"))
+	}
+	if c.Auth.JWTSecret == "" && c.Auth.KeysDir == "" {
+		errs = append(errs, errors.New("JWT_SECRET_KEY or JWT_KEYS_DIR is required"))
+	}
+	if c.Auth.AccessTokenLifetime < time.Minute || c.Auth.RefreshTokenLifetime < time.Minute {
+		errs = append(errs, errors.New("token lifetimes must be at least a minute"))
+	}
+	if c.HTTP.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("SHUTDOWN_TIMEOUT must be positive"))
+	}
+	if c.HTTP.ShutdownDelay < 0 {
+		errs = append(errs, errors.New("SHUTDOWN_DELAY must not be negative"))
+	}
+	for _, proxy := range c.HTTP.TrustedProxies {
+		if _, err := netip.ParsePrefix(proxy); err != nil {
+			if _, err = netip.ParseAddr(proxy); err != nil {
+				errs = append(errs, fmt.Errorf("TRUSTED_PROXIES must list IPs or CIDRs, got %q", proxy))
+			}
+		}
+	}
+	if c.SavedSearch.DigestInterval <= 0 {
+		errs = append(errs, errors.New("DIGEST_INTERVAL must be positive"))
+	}
+	if c.Idempotency.TTL <= 0 || c.Idempotency.MaxBodyMB <= 0 {
+		errs = append(errs, errors.New("IDEMPOTENCY_KEY_TTL and IDEMPOTENCY_MAX_BODY_MB must be positive"))
+	}
+	if err := c.Log.validate(); err != nil {
+		errs = append(errs, err)
+	}
+	switch c.Tracing.Exporter {
+	case "none", "stdout", "otlp":
+	default:
+		errs = append(errs, fmt.Errorf("TRACING_EXPORTER must be none, stdout or otlp, got %q", c.Tracing.Exporter))
+	}
+	if c.Metrics.CacheTTL < 0 {
+		errs = append(errs, errors.New("METRICS_CACHE_TTL must not be negative"))
+	}
+	if c.Moderation.RepostWindow < 0 {
+		errs = append(errs, errors.New("REPOST_BLOCK_WINDOW must not be negative"))
+	}
+	if c.Moderation.MaxCompared <= 0 {
+		errs = append(errs, errors.New("DUPLICATE_MAX_COMPARED must be positive"))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	return nil
+}
+
+func (l Log) validate() error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(l.Level)); err != nil {
+		return fmt.Errorf("LOG_LEVEL must be debug, info, warn or error, got %q", l.Level)
+	}
+	if l.Format != "text" && l.Format != "json" {
+		return fmt.Errorf("LOG_FORMAT must be text or json, got %q", l.Format)
+	}
+	if l.RotateEvery <= 0 || l.Retention <= 0 || l.MaxSizeMB <= 0 || l.MaxBackups < 0 {
+		return errors.New("LOG_ROTATE_EVERY, LOG_RETENTION and LOG_MAX_SIZE_MB must be positive")
+	}
+	return nil
+}
+
+// String renders the config as YAML with secrets redacted, it is safe to log.
+func (c Config) String() string {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+// field - a setting that can be overridden by an environment variable and a flag.
+type field struct {
+	value reflect.Value
+	env   string
+	unit  string
+}
+
+func fieldsOf(cfg *Config) map[string]field {
+	fields := make(map[string]field)
+	var walk func(v reflect.Value)
+	walk = func(v reflect.Value) {
+		for i := range v.NumField() {
+			sf := v.Type().Field(i)
+			if sf.Type.Kind() == reflect.Struct {
+				walk(v.Field(i))
+				continue
+			}
+			if env := sf.Tag.Get("env"); env != "" {
+				fields[env] = field{value: v.Field(i), env: env, unit: sf.Tag.Get("unit")}
+			}
+		}
+	}
+	walk(reflect.ValueOf(cfg).Elem())
+	return fields
+}
+
+func (f field) set(raw string) error {
+	switch f.value.Interface().(type) {
+	case time.Duration:
+		d, err := f.duration(raw)
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.env, err)
+		}
+		f.value.SetInt(int64(d))
+	case int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.env, err)
+		}
+		f.value.SetInt(int64(n))
+	case bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.env, err)
+		}
+		f.value.SetBool(b)
+	case []string:
+		var list []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		f.value.Set(reflect.ValueOf(list))
+	default:
+		f.value.SetString(raw)
+	}
+	return nil
+}
+
+// duration parses "15m", a plain number is counted in the unit of the field, seconds by default.
+func (f field) duration(raw string) (time.Duration, error) {
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return time.ParseDuration(raw)
+	}
+	if f.unit == "minute" {
+		return time.Duration(n) * time.Minute, nil
+	}
+	return time.Duration(n) * time.Second, nil
+}
+
+func flagName(env string) string {
+	return strings.ReplaceAll(strings.ToLower(env), "_", "-")
+}
+
+func envName(flagName string) string {
+	return strings.ReplaceAll(strings.ToUpper(flagName), "-", "_")
+}
@@ -0,0 +1,131 @@
+package config
+
+import "time"
+
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+var (
+	ErrInvalidConfig = Error("invalid configuration")
+	ErrReadingFile   = Error("error reading configuration file")
+)
+
+// Config - settings of the service. Every field is read, from lowest to highest priority, from its default,
+// the YAML file given by -config or CONFIG_FILE, the environment variable of its env tag and the flag
+// named after it, e.g. -database-url for DATABASE_URL. Durations are written like "15m".
+type Config struct {
+	HTTP        HTTP        `yaml:"http"`
+	Database    Database    `yaml:"database"`
+	Auth        Auth        `yaml:"auth"`
+	Moderation  Moderation  `yaml:"moderation"`
+	Notify      Notify      `yaml:"notify"`
+	SavedSearch SavedSearch `yaml:"saved_search"`
+	Idempotency Idempotency `yaml:"idempotency"`
+	Log         Log         `yaml:"log"`
+	Tracing     Tracing     `yaml:"tracing"`
+	Metrics     Metrics     `yaml:"metrics"`
+}
+
+// HTTP - TrustedProxies are the IPs or CIDRs of the proxies whose X-Forwarded-For is believed,
+// comma-separated in the environment. None are trusted by default. On shutdown the server keeps accepting
+// requests for ShutdownDelay after readiness fails, so load balancers stop routing to it first.
+type HTTP struct {
+	Host            string        `yaml:"host" env:"HOST"`
+	Port            string        `yaml:"port" env:"PORT"`
+	ShutdownDelay   time.Duration `yaml:"shutdown_delay" env:"SHUTDOWN_DELAY"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+	TrustedProxies  []string      `yaml:"trusted_proxies" env:"TRUSTED_PROXIES"`
+}
+
+type Database struct {
+	URL Secret `yaml:"url" env:"DATABASE_URL"`
+}
+
+// Auth - without KeysDir tokens are signed with HS256 and JWTSecret. Token lifetimes in the environment
+// may also be given in minutes as plain numbers.
+type Auth struct {
+	JWTSecret            Secret        `yaml:"jwt_secret" env:"JWT_SECRET_KEY"`
+	KeysDir              string        `yaml:"keys_dir" env:"JWT_KEYS_DIR"`
+	SigningKeyID         string        `yaml:"signing_key_id" env:"JWT_SIGNING_KEY_ID"`
+	TOTPIssuer           string        `yaml:"totp_issuer" env:"TOTP_ISSUER"`
+	AccessTokenLifetime  time.Duration `yaml:"access_token_lifetime" env:"ACCESS_TOKEN_LIFETIME" unit:"minute"`
+	RefreshTokenLifetime time.Duration `yaml:"refresh_token_lifetime" env:"REFRESH_TOKEN_LIFETIME" unit:"minute"`
+	AdminMFARequired     bool          `yaml:"admin_2fa_required" env:"ADMIN_2FA_REQUIRED"`
+}
+
+// Moderation - RepostWindow is how long an exact repost is refused, 0 turns the check off. The duplicate search
+// compares an ad with at most MaxCompared others, exact copies and the latest first.
+type Moderation struct {
+	RulesFile    string        `yaml:"rules_file" env:"MODERATION_RULES_FILE"`
+	RepostWindow time.Duration `yaml:"repost_window" env:"REPOST_BLOCK_WINDOW"`
+	MaxCompared  int           `yaml:"duplicate_max_compared" env:"DUPLICATE_MAX_COMPARED"`
+}
+
+// Notify - notifications are written to File, stdout when empty, and emails go to the SMTP server at SMTPAddr.
+type Notify struct {
+	File     string `yaml:"file" env:"NOTIFY_FILE"`
+	SMTPAddr string `yaml:"smtp_addr" env:"SMTP_ADDR"`
+	SMTPFrom string `yaml:"smtp_from" env:"SMTP_FROM"`
+}
+
+type SavedSearch struct {
+	DigestInterval time.Duration `yaml:"digest_interval" env:"DIGEST_INTERVAL"`
+}
+
+// Idempotency - TTL is how long the response of a POST sent with an Idempotency-Key is replayed.
+// Such a request is buffered to be fingerprinted, a body over MaxBodyMB is refused.
+type Idempotency struct {
+	TTL       time.Duration `yaml:"ttl" env:"IDEMPOTENCY_KEY_TTL"`
+	MaxBodyMB int           `yaml:"max_body_mb" env:"IDEMPOTENCY_MAX_BODY_MB"`
+}
+
+// Secret - value hidden when the config is printed or marshaled, Value returns it.
+type Secret string
+
+const redacted = "[REDACTED]"
+
+func (s Secret) Value() string {
+	return string(s)
+}
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Log - Level is debug, info, warn or error, Format is text or json. A new file is started every RotateEvery
+// and after MaxSizeMB, files older than Retention or beyond MaxBackups (0 keeps all) are removed.
+type Log struct {
+	Dir         string        `yaml:"dir" env:"LOG_DIR"`
+	Level       string        `yaml:"level" env:"LOG_LEVEL"`
+	Format      string        `yaml:"format" env:"LOG_FORMAT"`
+	RotateEvery time.Duration `yaml:"rotate_every" env:"LOG_ROTATE_EVERY"`
+	Retention   time.Duration `yaml:"retention" env:"LOG_RETENTION"`
+	MaxSizeMB   int           `yaml:"max_size_mb" env:"LOG_MAX_SIZE_MB"`
+	MaxBackups  int           `yaml:"max_backups" env:"LOG_MAX_BACKUPS"`
+	Stdout      bool          `yaml:"stdout" env:"LOG_STDOUT"`
+}
+
+// Tracing - Exporter is none, stdout or otlp. OTLPEndpoint is the full URL of the collector such as
+// http://localhost:4318/v1/traces, the standard OTEL_EXPORTER_OTLP_* variables apply when it is empty.
+type Tracing struct {
+	Exporter     string `yaml:"exporter" env:"TRACING_EXPORTER"`
+	OTLPEndpoint string `yaml:"otlp_endpoint" env:"TRACING_OTLP_ENDPOINT"`
+	ServiceName  string `yaml:"service_name" env:"OTEL_SERVICE_NAME"`
+}
+
+// Metrics - Prometheus metrics are served on their own listener at Addr, an empty Addr turns them off.
+// The ad counts are read from the database at most once per CacheTTL.
+type Metrics struct {
+	Addr     string        `yaml:"addr" env:"METRICS_ADDR"`
+	CacheTTL time.Duration `yaml:"cache_ttl" env:"METRICS_CACHE_TTL"`
+}
@@ -18,13 +18,18 @@ const (
 type Ad struct {
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
+	Rejection       *Rejection // filled only for the author's own rejected ads
 	Status          Status
 	Title           string
 	Description     string
 	RejectionReason string
 	AuthorID        string
+	Location        string
 	CategoryID      int
+	Price           int
 	ID              int
+	FavoriteCount   int // filled only for the author's own ads
+	Version         int // bumped by every write, a write based on another version is refused
 	IsActive        bool
 }
 
@@ -43,14 +48,23 @@ type AdFilter struct {
 	DateTo     time.Time
 	Status     string
 	UserID     string
+	Text       string // case-insensitive substring of title or description
+	Location   string // case-insensitive exact match
 	CategoryID int
+	PriceMin   int
+	PriceMax   int
 	Limit      int
 	Page       int
 }
 
 type AdStatistics struct {
-	Total     int
-	Published int
-	Pending   int
-	Rejected  int
+	// RejectionCodes - rejected ads by rejection code, ads rejected before codes were introduced
+	// are counted under "none".
+	RejectionCodes map[string]int
+	// Moderation - activity within the requested period, the counters below cover all ads.
+	Moderation ModerationStatistics
+	Total      int
+	Published  int
+	Pending    int
+	Rejected   int
 }
@@ -0,0 +1,42 @@
+package entities
+
+import (
+	"slices"
+	"time"
+)
+
+// APIKeyScope - what an API key is allowed to do.
+type APIKeyScope string
+
+// The only allowed API key scopes.
+const (
+	ScopeAdsRead  APIKeyScope = "ads:read"
+	ScopeAdsWrite APIKeyScope = "ads:write"
+)
+
+// APIKey - long-lived credential for integrations. Only the hash of the key is stored,
+// zero ExpiresAt means the key does not expire.
+type APIKey struct {
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	LastUsedAt time.Time
+	RevokedAt  time.Time
+	UserID     string
+	Name       string
+	Prefix     string
+	Hash       string
+	Scopes     []APIKeyScope
+	ID         int
+}
+
+// IsActive reports whether the key is neither revoked nor expired at the given moment.
+func (k *APIKey) IsActive(now time.Time) bool {
+	if !k.RevokedAt.IsZero() {
+		return false
+	}
+	return k.ExpiresAt.IsZero() || k.ExpiresAt.After(now)
+}
+
+func (k *APIKey) HasScope(scope APIKeyScope) bool {
+	return slices.Contains(k.Scopes, scope)
+}
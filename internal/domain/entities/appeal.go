@@ -0,0 +1,55 @@
+package entities
+
+import "time"
+
+// AppealStatus - pending until a moderator upholds the rejection or overturns it.
+type AppealStatus string
+
+const (
+	AppealPending    AppealStatus = "pending"
+	AppealUpheld     AppealStatus = "upheld"
+	AppealOverturned AppealStatus = "overturned"
+)
+
+// Appeal - the author's objection to the rejection of their ad. RejectedBy is empty when
+// pre-moderation rejected the ad.
+type Appeal struct {
+	CreatedAt    time.Time
+	ReviewedAt   time.Time
+	Status       AppealStatus
+	AuthorID     string
+	RejectedBy   string
+	ReviewedBy   string
+	Message      string
+	DecisionNote string
+	ID           int
+	AdID         int
+}
+
+// ModerationAction - what happened to an ad in its moderation history.
+type ModerationAction string
+
+const (
+	ActionSubmitted        ModerationAction = "submitted"
+	ActionApproved         ModerationAction = "approved"
+	ActionRejected         ModerationAction = "rejected"
+	ActionAppealed         ModerationAction = "appealed"
+	ActionAppealUpheld     ModerationAction = "appeal_upheld"
+	ActionAppealOverturned ModerationAction = "appeal_overturned"
+)
+
+// ModerationEvent - an entry of the moderation history of an ad. ActorID is empty for pre-moderation,
+// RejectionCode is set for rejections.
+type ModerationEvent struct {
+	CreatedAt     time.Time
+	Action        ModerationAction
+	ActorID       string
+	RejectionCode string
+	Note          string
+	ID            int
+	AdID          int
+}
+
+func (s AppealStatus) IsDecision() bool {
+	return s == AppealUpheld || s == AppealOverturned
+}
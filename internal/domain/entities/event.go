@@ -0,0 +1,25 @@
+package entities
+
+import "time"
+
+// EventType - kind of a real-time event pushed to a user.
+type EventType string
+
+const (
+	EventAdApproved   EventType = "ad.approved"
+	EventAdRejected   EventType = "ad.rejected"
+	EventNewMessage   EventType = "message.new"
+	EventPriceChanged EventType = "favorite.price_changed"
+)
+
+// EventData - event payload, kept as plain JSON-friendly values so that events can be
+// forwarded to an external broker without extra mapping.
+type EventData map[string]interface{}
+
+// Event - something that happened to be delivered to a single user (UserID).
+type Event struct {
+	CreatedAt time.Time
+	Data      EventData
+	Type      EventType
+	UserID    string
+}
@@ -0,0 +1,13 @@
+package entities
+
+import "time"
+
+// Favorite - ad bookmarked by a user. Ad is nil when the ad was deleted, Title then holds
+// the title saved at bookmarking time. Available is false for deleted, unpublished or inactive ads.
+type Favorite struct {
+	CreatedAt time.Time
+	Ad        *Ad
+	Title     string
+	AdID      int
+	Available bool
+}
@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+// Fingerprint - hashes of an ad taken when it is submitted. AdStatus, AdTitle and AuthorID describe the ad.
+type Fingerprint struct {
+	SubmittedAt time.Time
+	AdStatus    Status
+	AdTitle     string
+	AuthorID    string
+	ContentHash string // equal for the same words in the same order
+	ImageHashes []uint64
+	TextHash    uint64
+	AdID        int
+}
+
+// DuplicateCandidate - an ad similar to the one under moderation, similarities go from 0 to 1.
+type DuplicateCandidate struct {
+	Status          Status
+	Title           string
+	AuthorID        string
+	TextSimilarity  float64
+	ImageSimilarity float64 // of the most similar pair of images, 0 when one of the ads has none
+	AdID            int
+	SameAuthor      bool
+	Exact           bool // same title and description
+}
@@ -0,0 +1,22 @@
+package entities
+
+import "time"
+
+// IdempotentRequest - a POST request sent with an Idempotency-Key and, once it has finished, its response.
+// Status is 0 while the first request is still running. Headers are the response headers replayed with Body.
+type IdempotentRequest struct {
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+	UserID      string
+	Key         string
+	Fingerprint string
+	ContentType string
+	Body        []byte
+	Headers     map[string]string
+	Status      int
+}
+
+// Done reports whether the response is stored and can be replayed.
+func (r *IdempotentRequest) Done() bool {
+	return r.Status != 0
+}
@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+// LockoutKind - what a failed login counter is keyed by.
+type LockoutKind string
+
+// The only allowed lockout kinds.
+const (
+	LockoutByPhone LockoutKind = "phone"
+	LockoutByIP    LockoutKind = "ip"
+)
+
+// LoginAttempt - failed login counter for a single phone number or client IP.
+// LockedUntil is zero when the key is not locked.
+type LoginAttempt struct {
+	LastFailureAt time.Time
+	LockedUntil   time.Time
+	Kind          LockoutKind
+	Key           string
+	Failures      int
+}
+
+// IsLocked reports whether the key is still locked at the given moment.
+func (a *LoginAttempt) IsLocked(now time.Time) bool {
+	return a.LockedUntil.After(now)
+}
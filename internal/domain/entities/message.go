@@ -0,0 +1,41 @@
+package entities
+
+import "time"
+
+// Conversation - a chat between a buyer and the author of an ad.
+// AdID is 0 when the ad was deleted, such a conversation is locked.
+type Conversation struct {
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	BuyerID     string
+	SellerID    string
+	AdTitle     string
+	ID          int
+	AdID        int
+	UnreadCount int // messages of the other side not read by the requesting user
+	Locked      bool
+	Blocked     bool // the seller blocked the buyer
+}
+
+// Message - a message in a conversation, ReadAt is zero until the recipient opens the conversation.
+type Message struct {
+	CreatedAt      time.Time
+	ReadAt         time.Time
+	SenderID       string
+	Body           string
+	ConversationID int
+	ID             int64
+}
+
+// Counterpart returns the other participant of the conversation.
+func (c *Conversation) Counterpart(userID string) string {
+	if userID == c.BuyerID {
+		return c.SellerID
+	}
+	return c.BuyerID
+}
+
+// HasParticipant reports whether the user is the buyer or the seller.
+func (c *Conversation) HasParticipant(userID string) bool {
+	return userID == c.BuyerID || userID == c.SellerID
+}
@@ -0,0 +1,42 @@
+package entities
+
+import "time"
+
+// NotificationKind - what a notification is about.
+type NotificationKind string
+
+const (
+	NotificationModeration  NotificationKind = "moderation"
+	NotificationMessage     NotificationKind = "message"
+	NotificationSavedSearch NotificationKind = "saved_search"
+)
+
+// Notification - a message addressed to a single user.
+// Phone and Email are filled in by the dispatcher for the channel that delivers it.
+type Notification struct {
+	CreatedAt time.Time
+	ReadAt    time.Time
+	Kind      NotificationKind
+	UserID    string
+	Subject   string
+	Body      string
+	Phone     string
+	Email     string
+	ID        int
+}
+
+// NotificationPreferences - channels the user wants to be notified through.
+// SMS goes to the account phone, email to EmailAddress.
+type NotificationPreferences struct {
+	UpdatedAt    time.Time
+	UserID       string
+	EmailAddress string
+	InApp        bool
+	SMS          bool
+	Email        bool
+}
+
+// DefaultNotificationPreferences - used until the user changes anything.
+func DefaultNotificationPreferences(userID string) *NotificationPreferences {
+	return &NotificationPreferences{UserID: userID, InApp: true}
+}
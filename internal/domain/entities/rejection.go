@@ -0,0 +1,43 @@
+package entities
+
+import "time"
+
+// DefaultLanguage - every rejection template has a text in it, notifications are sent in it.
+const DefaultLanguage = "en"
+
+// Rejection codes used by the service itself, their templates cannot be deactivated.
+const (
+	RejectionRulesViolation = "rules_violation" // pre-moderation
+	RejectionAuthorBanned   = "author_banned"   // abuse reports
+)
+
+// RejectionTemplate - admin-managed reason of a rejection. Texts maps a language code to the text
+// shown to the author.
+type RejectionTemplate struct {
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Texts     map[string]string
+	Code      string
+	IsActive  bool
+}
+
+// Rejection - structured reason of a rejected ad: the template code, its texts and an optional
+// moderator's note.
+type Rejection struct {
+	Texts map[string]string
+	Code  string
+	Note  string
+}
+
+// IsSystem tells whether the service rejects ads with the template on its own.
+func (t *RejectionTemplate) IsSystem() bool {
+	return t.Code == RejectionRulesViolation || t.Code == RejectionAuthorBanned
+}
+
+// Reason renders the rejection in the default language, the note follows the template text.
+func (r *Rejection) Reason() string {
+	if r.Note == "" {
+		return r.Texts[DefaultLanguage]
+	}
+	return r.Texts[DefaultLanguage] + " " + r.Note
+}
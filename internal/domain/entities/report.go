@@ -0,0 +1,50 @@
+package entities
+
+import "time"
+
+// ReportReason - why a user flags an ad.
+type ReportReason string
+
+const (
+	ReasonScam          ReportReason = "scam"
+	ReasonProhibited    ReportReason = "prohibited"
+	ReasonOffensive     ReportReason = "offensive"
+	ReasonDuplicate     ReportReason = "duplicate"
+	ReasonWrongCategory ReportReason = "wrong_category"
+	ReasonOther         ReportReason = "other"
+)
+
+// ReportStatus - open until a moderator resolves it with one of the other statuses.
+type ReportStatus string
+
+const (
+	ReportOpen       ReportStatus = "open"
+	ReportDismissed  ReportStatus = "dismissed"
+	ReportAdRemoved  ReportStatus = "ad_removed"
+	ReportUserBanned ReportStatus = "user_banned"
+)
+
+// Report - an abuse report on an ad. AdTitle, AdAuthorID and OpenReports describe the ad
+// for the moderation queue and are empty once the ad is deleted.
+type Report struct {
+	CreatedAt   time.Time
+	ResolvedAt  time.Time
+	Reason      ReportReason
+	Status      ReportStatus
+	ReporterID  string
+	ResolvedBy  string
+	Comment     string
+	AdTitle     string
+	AdAuthorID  string
+	ID          int
+	AdID        int
+	OpenReports int
+}
+
+func (r ReportReason) IsValid() bool {
+	switch r {
+	case ReasonScam, ReasonProhibited, ReasonOffensive, ReasonDuplicate, ReasonWrongCategory, ReasonOther:
+		return true
+	}
+	return false
+}
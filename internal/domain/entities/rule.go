@@ -0,0 +1,32 @@
+package entities
+
+import "time"
+
+// RuleDecision - what pre-moderation did with a submitted ad.
+type RuleDecision string
+
+const (
+	DecisionApprove RuleDecision = "approve"
+	DecisionReject  RuleDecision = "reject"
+	DecisionReview  RuleDecision = "review" // the ad stays pending for a moderator
+)
+
+// Pre-moderation rules.
+const (
+	RuleBannedWord     = "banned_word"
+	RulePhone          = "phone"
+	RuleLink           = "link"
+	RuleCaps           = "caps"
+	RuleDuplicateTitle = "duplicate_title"
+)
+
+// RuleHit - a pre-moderation rule that matched a submitted ad, Score is added to the ad's risk score.
+type RuleHit struct {
+	CreatedAt time.Time
+	Decision  RuleDecision
+	Rule      string
+	Detail    string
+	ID        int
+	AdID      int
+	Score     int
+}
@@ -0,0 +1,36 @@
+package entities
+
+import "time"
+
+// NotifyMode - how the owner of a saved search learns about new matches.
+type NotifyMode string
+
+const (
+	NotifyInstant NotifyMode = "instant"
+	NotifyDaily   NotifyMode = "daily"
+)
+
+// SavedSearch - a stored ad filter, zero values of the criteria mean "any".
+type SavedSearch struct {
+	CreatedAt  time.Time
+	UserID     string
+	Name       string
+	Text       string
+	Location   string
+	Mode       NotifyMode
+	CategoryID int
+	PriceMin   int
+	PriceMax   int
+	ID         int
+}
+
+// SearchMatch - a newly approved ad that matched a saved search and is waiting for the digest.
+type SearchMatch struct {
+	MatchedAt  time.Time
+	UserID     string
+	SearchName string
+	AdTitle    string
+	SearchID   int
+	AdID       int
+	AdPrice    int
+}
@@ -0,0 +1,101 @@
+package entities
+
+import "time"
+
+// Granularity - length of a period of the statistics time series.
+type Granularity string
+
+const (
+	GranularityDay   Granularity = "day"
+	GranularityWeek  Granularity = "week"
+	GranularityMonth Granularity = "month"
+)
+
+func (g Granularity) IsValid() bool {
+	return g == GranularityDay || g == GranularityWeek || g == GranularityMonth
+}
+
+const (
+	statisticsDay = 24 * time.Hour
+	// DefaultStatisticsPeriod ends with today, MaxStatisticsPeriod keeps a daily series about 2 years long.
+	DefaultStatisticsPeriod = 30 * statisticsDay
+	MaxStatisticsPeriod     = 731 * statisticsDay
+)
+
+// StatisticsFilter - statistics cover [From, To).
+type StatisticsFilter struct {
+	From        time.Time
+	To          time.Time
+	Granularity Granularity
+}
+
+// WithDefaults fills the defaults: daily granularity, To at the end of the day of now in UTC and From
+// DefaultStatisticsPeriod before To.
+func (f StatisticsFilter) WithDefaults(now time.Time) StatisticsFilter {
+	if f.Granularity == "" {
+		f.Granularity = GranularityDay
+	}
+	if f.To.IsZero() {
+		f.To = now.UTC().Truncate(statisticsDay).Add(statisticsDay)
+	}
+	if f.From.IsZero() {
+		f.From = f.To.Add(-DefaultStatisticsPeriod)
+	}
+	f.From, f.To = f.From.UTC(), f.To.UTC()
+	return f
+}
+
+// IsValid reports whether the period is not empty and not longer than MaxStatisticsPeriod.
+func (f StatisticsFilter) IsValid() bool {
+	return f.Granularity.IsValid() && f.From.Before(f.To) && f.To.Sub(f.From) <= MaxStatisticsPeriod
+}
+
+// ModerationStatistics - moderation activity in a period. Decision times run from the latest
+// submission of the ad to the decision, decisions of ads submitted before submissions were recorded
+// are left out of the medians.
+type ModerationStatistics struct {
+	From                  time.Time
+	To                    time.Time
+	Series                []StatisticsPoint
+	Categories            []CategoryStatistics
+	Moderators            []ModeratorStatistics
+	BacklogAge            []BacklogBucket // ads waiting for a decision now, regardless of the period
+	Granularity           Granularity
+	ApprovalRate          float64 // approved share of the decisions, 0 without decisions
+	MedianDecisionSeconds float64
+	Decisions             int
+	NewUsers              int
+}
+
+// StatisticsPoint - counters of the period starting at Period.
+type StatisticsPoint struct {
+	Period    time.Time
+	Submitted int
+	Approved  int
+	Rejected  int
+	NewUsers  int
+}
+
+// CategoryStatistics - decisions on the ads of a category in the period and its current backlog.
+type CategoryStatistics struct {
+	Title     string
+	ID        int
+	Submitted int
+	Approved  int
+	Rejected  int
+	Pending   int
+}
+
+// ModeratorStatistics - decisions of a moderator, ModeratorID is empty for pre-moderation.
+type ModeratorStatistics struct {
+	ModeratorID           string
+	MedianDecisionSeconds float64
+	Approved              int
+	Rejected              int
+}
+
+// BacklogBucket - ads waiting for a decision since their latest submission for the time in Label.
+type BacklogBucket struct {
+	Label string
+	Count int
+}
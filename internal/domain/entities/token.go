@@ -7,3 +7,11 @@ type Token struct {
 	Token     string
 	UserID    string
 }
+
+// AuthTokens - result of a login step. When a second factor is required,
+// only MFAToken is set and it has to be exchanged together with a TOTP code.
+type AuthTokens struct {
+	AccessToken  string
+	RefreshToken string
+	MFAToken     string
+}
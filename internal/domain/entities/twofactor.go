@@ -0,0 +1,20 @@
+package entities
+
+import "time"
+
+// TOTP - time-based one-time password settings of a user.
+// Enabled is false until the user confirms the first code from the authenticator app.
+type TOTP struct {
+	CreatedAt    time.Time
+	ConfirmedAt  time.Time
+	UserID       string
+	Secret       string
+	LastUsedStep int64
+	Enabled      bool
+}
+
+// TOTPEnrollment - data shown once to set up an authenticator app.
+type TOTPEnrollment struct {
+	Secret string
+	URI    string
+}
@@ -14,6 +14,7 @@ const (
 type User struct {
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
+	BannedAt     time.Time
 	Role         Role
 	FName        string
 	LName        string
@@ -0,0 +1,135 @@
+// Package errs holds the domain error model shared by the repository, use-case and transport layers.
+//
+// Every sentinel in repoerr, usecaseerr and pkgerr is an *Error that wraps one of the kinds below,
+// so errors.Is(err, errs.NotFound) holds for any not-found error however deep it is wrapped.
+// The REST layer maps the kind to the HTTP status and exposes the code to clients.
+package errs
+
+import "errors"
+
+// Kind - class of a domain error, the transport maps it to a status.
+type Kind string
+
+func (k Kind) Error() string {
+	return string(k)
+}
+
+const (
+	NotFound        Kind = "not_found"
+	Forbidden       Kind = "forbidden"
+	Validation      Kind = "validation"
+	Conflict        Kind = "conflict"
+	Unauthorized    Kind = "unauthorized"
+	TooManyRequests Kind = "too_many_requests"
+	TooLarge        Kind = "payload_too_large"
+	Internal        Kind = "internal"
+	// the write is based on an outdated version, or does not name the version at all
+	PreconditionFailed   Kind = "precondition_failed"
+	PreconditionRequired Kind = "precondition_required"
+)
+
+// Error - domain error with a kind and a stable machine-readable code.
+// Field is set for validation errors about a single input field.
+type Error struct {
+	kind  Kind
+	code  string
+	field string
+	msg   string
+}
+
+// New creates a domain error, code must stay stable since clients rely on it.
+func New(kind Kind, code, msg string) *Error {
+	return &Error{kind: kind, code: code, msg: msg}
+}
+
+// NewField creates a validation error about the given input field.
+func NewField(code, field, msg string) *Error {
+	return &Error{kind: Validation, code: code, field: field, msg: msg}
+}
+
+// Invalid reports a malformed request parameter, the code is derived from the field name.
+func Invalid(field, msg string) *Error {
+	return NewField("invalid_"+field, field, msg)
+}
+
+func (e *Error) Error() string {
+	return e.msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.kind
+}
+
+func (e *Error) Kind() Kind {
+	return e.kind
+}
+
+func (e *Error) Code() string {
+	return e.code
+}
+
+func (e *Error) Field() string {
+	return e.field
+}
+
+// StaleError - a write based on an outdated version. It carries the current state and its version,
+// so the client can retry from them.
+type StaleError struct {
+	err     *Error
+	Current any
+	Version int
+}
+
+func NewStale(err *Error, current any, version int) *StaleError {
+	return &StaleError{err: err, Current: current, Version: version}
+}
+
+func (e *StaleError) Error() string {
+	return e.err.Error()
+}
+
+func (e *StaleError) Unwrap() error {
+	return e.err
+}
+
+var (
+	ErrInvalidBody  = New(Validation, "invalid_body", "invalid request body")
+	ErrUnauthorized = New(Unauthorized, "unauthorized", "unauthorized")
+	ErrForbidden    = New(Forbidden, "forbidden", "forbidden")
+	ErrInternal     = New(Internal, "internal", "internal error")
+)
+
+// KindOf returns the kind of the outermost domain error in the chain, errors outside the model are internal.
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.kind
+	}
+	return Internal
+}
+
+// FieldErrors collects the field-level validation errors anywhere in the chain, including joined errors.
+func FieldErrors(err error) []*Error {
+	var fields []*Error
+	walk(err, func(err error) {
+		if e, ok := err.(*Error); ok && e.field != "" { //nolint:errorlint // walk already unwraps
+			fields = append(fields, e)
+		}
+	})
+	return fields
+}
+
+func walk(err error, fn func(error)) {
+	if err == nil {
+		return
+	}
+	fn(err)
+	switch u := err.(type) { //nolint:errorlint // walks the chain by hand
+	case interface{ Unwrap() error }:
+		walk(u.Unwrap(), fn)
+	case interface{ Unwrap() []error }:
+		for _, e := range u.Unwrap() {
+			walk(e, fn)
+		}
+	}
+}
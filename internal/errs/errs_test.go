@@ -0,0 +1,47 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKind(t *testing.T) {
+	notFound := New(NotFound, "ad_not_found", "ad not found")
+	failed := New(Internal, "getting_ad", "error getting ad")
+
+	wrapped := fmt.Errorf("failed to get ad: %w", notFound)
+	assert.ErrorIs(t, wrapped, NotFound)
+	assert.ErrorIs(t, wrapped, notFound)
+	assert.Equal(t, NotFound, KindOf(wrapped))
+
+	// the outermost domain error decides, a cause of another kind stays reachable with errors.Is
+	replaced := fmt.Errorf("%w: %w", failed, notFound)
+	assert.Equal(t, Internal, KindOf(replaced))
+	assert.ErrorIs(t, replaced, notFound)
+
+	assert.Equal(t, Internal, KindOf(errors.New("boom")))
+}
+
+func TestFieldErrors(t *testing.T) {
+	title := NewField("title_required", "title", "title is required")
+	price := Invalid("price", "price cannot be negative")
+	err := fmt.Errorf("%w: %w", New(Validation, "invalid_params", "invalid parameters"), errors.Join(title, price))
+
+	assert.Equal(t, []*Error{title, price}, FieldErrors(err))
+	assert.Equal(t, "invalid_price", price.Code())
+	assert.Empty(t, FieldErrors(errors.New("boom")))
+}
+
+func TestStaleError(t *testing.T) {
+	modified := New(PreconditionFailed, "ad_modified", "ad was modified since it was read")
+	err := fmt.Errorf("failed to update ad: %w", NewStale(modified, "current", 4))
+
+	var stale *StaleError
+	assert.ErrorAs(t, err, &stale)
+	assert.Equal(t, 4, stale.Version)
+	assert.ErrorIs(t, err, modified)
+	assert.Equal(t, PreconditionFailed, KindOf(err))
+}
@@ -1,13 +1,10 @@
 package utilserr
 
-type Error string
-
-func (e Error) Error() string {
-	return string(e)
-}
+import "ads-service/internal/errs"
 
 var (
-	ErrTitleRequired    = Error("title is required")
-	ErrLocationRequired = Error("location is required")
-	ErrCategoryRequired = Error("category is required")
+	ErrTitleRequired    = errs.NewField("title_required", "title", "title is required")
+	ErrLocationRequired = errs.NewField("location_required", "location", "location is required")
+	ErrCategoryRequired = errs.NewField("category_required", "category_id", "category is required")
+	ErrNegativePrice    = errs.NewField("negative_price", "price", "price cannot be negative")
 )
@@ -1,37 +1,38 @@
 package repoerr
 
-type Error string
-
-func (e Error) Error() string {
-	return string(e)
-}
+import "ads-service/internal/errs"
 
 var (
-	ErrSelection         = Error("error selecting ads from database")
-	ErrInsert            = Error("error inserting ad into database")
-	ErrUpdate            = Error("error updating ad in database")
-	ErrDelete            = Error("error deleting ad from database")
-	ErrNoRows            = Error("no rows found in database for the query")
-	ErrAdNotFound        = Error("no such ad in database")
-	ErrUserNotHaveAds    = Error("user does not have any ads")
-	ErrApproval          = Error("error approving ad")
-	ErrRejection         = Error("error rejecting ad")
-	ErrGettingStatistics = Error("error getting ad statistics")
+	ErrSelection         = errs.New(errs.Internal, "selection", "error selecting ads from database")
+	ErrInsert            = errs.New(errs.Internal, "insert", "error inserting ad into database")
+	ErrUpdate            = errs.New(errs.Internal, "update", "error updating ad in database")
+	ErrDelete            = errs.New(errs.Internal, "delete", "error deleting ad from database")
+	ErrNoRows            = errs.New(errs.NotFound, "no_rows", "no rows found in database for the query")
+	ErrAdNotFound        = errs.New(errs.NotFound, "ad_not_found", "no such ad in database")
+	ErrAdModified        = errs.New(errs.Conflict, "ad_modified", "ad was modified or deleted since it was read")
+	ErrUserNotHaveAds    = errs.New(errs.NotFound, "user_not_have_ads", "user does not have any ads")
+	ErrApproval          = errs.New(errs.Internal, "approval", "error approving ad")
+	ErrRejection         = errs.New(errs.Internal, "rejection", "error rejecting ad")
+	ErrGettingStatistics = errs.New(errs.Internal, "getting_statistics", "error getting ad statistics")
 
-	ErrGettingAllAds          = Error("error getting all ads from database")
-	ErrGettingAdByID          = Error("error getting ad by ID from database")
-	ErrGettingAdsByUserID     = Error("error getting ads by user ID from database")
-	ErrGettingAdsByCategoryID = Error("error getting ads by category ID from database")
-	ErrGettingAdsByStatus     = Error("error getting ads by status from database")
-	ErrGettingAdsByDate       = Error("error getting ads by date from database")
-	ErrGettingAdsByTitle      = Error("error getting ads by title from database")
-	ErrGettingAdsByLocation   = Error("error getting ads by location from database")
-	ErrScan                   = Error("error scanning ad from database")
+	ErrGettingAllAds      = errs.New(errs.Internal, "getting_all_ads", "error getting all ads from database")
+	ErrGettingAdByID      = errs.New(errs.Internal, "getting_ad_by_id", "error getting ad by ID from database")
+	ErrGettingAdsByUserID = errs.New(errs.Internal, "getting_ads_by_user_id",
+		"error getting ads by user ID from database")
+	ErrGettingAdsByCategoryID = errs.New(errs.Internal, "getting_ads_by_category_id",
+		"error getting ads by category ID from database")
+	ErrGettingAdsByStatus = errs.New(errs.Internal, "getting_ads_by_status",
+		"error getting ads by status from database")
+	ErrGettingAdsByDate     = errs.New(errs.Internal, "getting_ads_by_date", "error getting ads by date from database")
+	ErrGettingAdsByTitle    = errs.New(errs.Internal, "getting_ads_by_title", "error getting ads by title from database")
+	ErrGettingAdsByLocation = errs.New(errs.Internal, "getting_ads_by_location",
+		"error getting ads by location from database")
+	ErrScan = errs.New(errs.Internal, "scan", "error scanning ad from database")
 
-	ErrFileSelection = Error("error selecting ad files from database")
-	ErrFileInsertion = Error("error inserting ad file into database")
-	ErrFileDeletion  = Error("error deleting ad file from database")
-	ErrFileNotFound  = Error("ad file not found in database")
+	ErrFileSelection = errs.New(errs.Internal, "file_selection", "error selecting ad files from database")
+	ErrFileInsertion = errs.New(errs.Internal, "file_insertion", "error inserting ad file into database")
+	ErrFileDeletion  = errs.New(errs.Internal, "file_deletion", "error deleting ad file from database")
+	ErrFileNotFound  = errs.New(errs.NotFound, "file_not_found", "ad file not found in database")
 
-	ErrJSONUnmarshal = Error("error unmarshalling JSON data from database")
+	ErrJSONUnmarshal = errs.New(errs.Internal, "json_unmarshal", "error unmarshalling JSON data from database")
 )
@@ -0,0 +1,10 @@
+package repoerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrAPIKeyNotFound = errs.New(errs.NotFound, "api_key_not_found", "api key not found")
+	ErrAPIKeyInsert   = errs.New(errs.Internal, "api_key_insert", "failed to insert api key")
+	ErrAPIKeySelect   = errs.New(errs.Internal, "api_key_select", "failed to select api key")
+	ErrAPIKeyUpdate   = errs.New(errs.Internal, "api_key_update", "failed to update api key")
+)
@@ -0,0 +1,14 @@
+package repoerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrAppealPending  = errs.New(errs.Conflict, "appeal_pending", "ad already has a pending appeal")
+	ErrAppealNotFound = errs.New(errs.NotFound, "appeal_not_found", "appeal not found")
+	ErrAppealReviewed = errs.New(errs.Conflict, "appeal_reviewed", "appeal is already reviewed")
+	ErrAppealInsert   = errs.New(errs.Internal, "appeal_insert", "failed to insert appeal")
+	ErrAppealSelect   = errs.New(errs.Internal, "appeal_select", "failed to select appeals")
+	ErrAppealUpdate   = errs.New(errs.Internal, "appeal_update", "failed to update appeal")
+	ErrHistoryInsert  = errs.New(errs.Internal, "history_insert", "failed to insert moderation event")
+	ErrHistorySelect  = errs.New(errs.Internal, "history_select", "failed to select moderation history")
+)
@@ -1,15 +1,17 @@
 package repoerr
 
+import "ads-service/internal/errs"
+
 var (
-	ErrUserExists         = Error("user already exists")
-	ErrInvalidCredentials = Error("invalid credentials")
-	ErrUserSelectFailed   = Error("failed to select user")
-	ErrUserInsertFailed   = Error("failed to insert user")
+	ErrUserExists         = errs.New(errs.Conflict, "user_exists", "user already exists")
+	ErrInvalidCredentials = errs.New(errs.Unauthorized, "invalid_credentials", "invalid credentials")
+	ErrUserSelectFailed   = errs.New(errs.Internal, "user_select_failed", "failed to select user")
+	ErrUserInsertFailed   = errs.New(errs.Internal, "user_insert_failed", "failed to insert user")
 
-	ErrCreatingToken      = Error("failed to create refresh token")
-	ErrTokenNotFound      = Error("refresh token not found")
-	ErrTokenUpdateFailed  = Error("failed to update refresh token")
-	ErrTokenDeleteFailed  = Error("failed to delete refresh token")
-	ErrTokenSelectFailed  = Error("failed to select refresh token")
-	ErrTokenAlreadyExists = Error("refresh token already exists")
+	ErrCreatingToken      = errs.New(errs.Internal, "creating_token", "failed to create refresh token")
+	ErrTokenNotFound      = errs.New(errs.NotFound, "token_not_found", "refresh token not found")
+	ErrTokenUpdateFailed  = errs.New(errs.Internal, "token_update_failed", "failed to update refresh token")
+	ErrTokenDeleteFailed  = errs.New(errs.Internal, "token_delete_failed", "failed to delete refresh token")
+	ErrTokenSelectFailed  = errs.New(errs.Internal, "token_select_failed", "failed to select refresh token")
+	ErrTokenAlreadyExists = errs.New(errs.Conflict, "token_already_exists", "refresh token already exists")
 )
@@ -0,0 +1,10 @@
+package repoerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrFavoriteNotFound = errs.New(errs.NotFound, "favorite_not_found", "favorite not found")
+	ErrFavoriteInsert   = errs.New(errs.Internal, "favorite_insert", "failed to insert favorite")
+	ErrFavoriteDelete   = errs.New(errs.Internal, "favorite_delete", "failed to delete favorite")
+	ErrFavoriteSelect   = errs.New(errs.Internal, "favorite_select", "failed to select favorites")
+)
@@ -0,0 +1,9 @@
+package repoerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrFingerprintNotFound = errs.New(errs.NotFound, "fingerprint_not_found", "fingerprint not found")
+	ErrFingerprintSave     = errs.New(errs.Internal, "fingerprint_save", "failed to save fingerprint")
+	ErrFingerprintSelect   = errs.New(errs.Internal, "fingerprint_select", "failed to select fingerprints")
+)
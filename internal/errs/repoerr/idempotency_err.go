@@ -0,0 +1,10 @@
+package repoerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrIdempotencyKeySave     = errs.New(errs.Internal, "idempotency_key_save", "failed to save idempotency key")
+	ErrIdempotencyKeySelect   = errs.New(errs.Internal, "idempotency_key_select", "failed to select idempotency key")
+	ErrIdempotencyKeyDelete   = errs.New(errs.Internal, "idempotency_key_delete", "failed to delete idempotency key")
+	ErrIdempotencyKeyNotFound = errs.New(errs.NotFound, "idempotency_key_not_found", "idempotency key not found")
+)
@@ -0,0 +1,11 @@
+package repoerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrLoginAttemptSelect = errs.New(errs.Internal, "login_attempt_select", "failed to select login attempts")
+	ErrLoginAttemptSave   = errs.New(errs.Internal, "login_attempt_save", "failed to save login attempt")
+	ErrLoginAttemptDelete = errs.New(errs.Internal, "login_attempt_delete", "failed to delete login attempt")
+	ErrLockoutNotFound    = errs.New(errs.NotFound, "lockout_not_found", "lockout not found")
+	ErrLockedOut          = errs.New(errs.TooManyRequests, "locked_out", "login attempts are locked")
+)
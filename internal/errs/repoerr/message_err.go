@@ -0,0 +1,16 @@
+package repoerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrConversationNotFound = errs.New(errs.NotFound, "conversation_not_found", "conversation not found")
+	ErrConversationInsert   = errs.New(errs.Internal, "conversation_insert", "failed to insert conversation")
+	ErrConversationSelect   = errs.New(errs.Internal, "conversation_select", "failed to select conversations")
+	ErrMessageInsert        = errs.New(errs.Internal, "message_insert", "failed to insert message")
+	ErrMessageSelect        = errs.New(errs.Internal, "message_select", "failed to select messages")
+	ErrMessageUpdate        = errs.New(errs.Internal, "message_update", "failed to update messages")
+	ErrBlockNotFound        = errs.New(errs.NotFound, "block_not_found", "block not found")
+	ErrBlockInsert          = errs.New(errs.Internal, "block_insert", "failed to insert block")
+	ErrBlockDelete          = errs.New(errs.Internal, "block_delete", "failed to delete block")
+	ErrBlockSelect          = errs.New(errs.Internal, "block_select", "failed to select block")
+)
@@ -0,0 +1,12 @@
+package repoerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrNotificationNotFound = errs.New(errs.NotFound, "notification_not_found", "notification not found")
+	ErrNotificationInsert   = errs.New(errs.Internal, "notification_insert", "failed to insert notification")
+	ErrNotificationSelect   = errs.New(errs.Internal, "notification_select", "failed to select notifications")
+	ErrNotificationUpdate   = errs.New(errs.Internal, "notification_update", "failed to update notifications")
+	ErrPreferencesSelect    = errs.New(errs.Internal, "preferences_select", "failed to select notification preferences")
+	ErrPreferencesUpsert    = errs.New(errs.Internal, "preferences_upsert", "failed to save notification preferences")
+)
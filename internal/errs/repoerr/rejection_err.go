@@ -0,0 +1,14 @@
+package repoerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrRejectionTemplateNotFound = errs.New(errs.NotFound, "rejection_template_not_found", "rejection template not found")
+	ErrRejectionTemplateExists   = errs.New(errs.Conflict, "rejection_template_exists",
+		"rejection template already exists")
+	ErrRejectionTemplateInsert = errs.New(errs.Internal, "rejection_template_insert",
+		"failed to insert rejection template")
+	ErrRejectionTemplateUpdate = errs.New(errs.Internal, "rejection_template_update",
+		"failed to update rejection template")
+	ErrRejectionSelect = errs.New(errs.Internal, "rejection_select", "failed to select rejections")
+)
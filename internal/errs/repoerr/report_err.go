@@ -0,0 +1,11 @@
+package repoerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrReportDuplicate = errs.New(errs.Conflict, "report_duplicate", "ad already reported by this user")
+	ErrReportNotFound  = errs.New(errs.NotFound, "report_not_found", "report not found")
+	ErrReportInsert    = errs.New(errs.Internal, "report_insert", "failed to insert report")
+	ErrReportSelect    = errs.New(errs.Internal, "report_select", "failed to select reports")
+	ErrReportUpdate    = errs.New(errs.Internal, "report_update", "failed to update reports")
+)
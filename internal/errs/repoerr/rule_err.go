@@ -0,0 +1,8 @@
+package repoerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrRuleHitInsert = errs.New(errs.Internal, "rule_hit_insert", "failed to insert rule hits")
+	ErrRuleHitSelect = errs.New(errs.Internal, "rule_hit_select", "failed to select rule hits")
+)
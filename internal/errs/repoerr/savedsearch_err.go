@@ -0,0 +1,12 @@
+package repoerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrSavedSearchNotFound = errs.New(errs.NotFound, "saved_search_not_found", "saved search not found")
+	ErrSavedSearchInsert   = errs.New(errs.Internal, "saved_search_insert", "failed to insert saved search")
+	ErrSavedSearchSelect   = errs.New(errs.Internal, "saved_search_select", "failed to select saved searches")
+	ErrSavedSearchDelete   = errs.New(errs.Internal, "saved_search_delete", "failed to delete saved search")
+	ErrSavedSearchMatch    = errs.New(errs.Internal, "saved_search_match", "failed to match saved searches")
+	ErrSavedSearchNotify   = errs.New(errs.Internal, "saved_search_notify", "failed to mark matches as notified")
+)
@@ -1,5 +1,7 @@
 package repoerr
 
+import "ads-service/internal/errs"
+
 var (
-	ErrSavingToken = Error("Refresh token not saved")
+	ErrSavingToken = errs.New(errs.Internal, "saving_token", "Refresh token not saved")
 )
@@ -0,0 +1,14 @@
+package repoerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrTOTPNotFound         = errs.New(errs.NotFound, "totp_not_found", "two-factor settings not found")
+	ErrTOTPSelect           = errs.New(errs.Internal, "totp_select", "failed to select two-factor settings")
+	ErrTOTPSave             = errs.New(errs.Internal, "totp_save", "failed to save two-factor settings")
+	ErrTOTPDelete           = errs.New(errs.Internal, "totp_delete", "failed to delete two-factor settings")
+	ErrTOTPCodeReused       = errs.New(errs.Unauthorized, "totp_code_reused", "two-factor code already used")
+	ErrRecoveryCodesSave    = errs.New(errs.Internal, "recovery_codes_save", "failed to save recovery codes")
+	ErrRecoveryCodeNotFound = errs.New(errs.NotFound, "recovery_code_not_found", "recovery code not found or already used")
+	ErrRecoveryCodeUse      = errs.New(errs.Internal, "recovery_code_use", "failed to use recovery code")
+)
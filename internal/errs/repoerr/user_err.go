@@ -1,6 +1,9 @@
 package repoerr
 
+import "ads-service/internal/errs"
+
 var (
-	ErrUserNotFound = Error("user not found")
-	ErrCreationUser = Error("user creation failed")
+	ErrUserNotFound = errs.New(errs.NotFound, "user_not_found", "user not found")
+	ErrCreationUser = errs.New(errs.Internal, "creation_user", "user creation failed")
+	ErrUserBan      = errs.New(errs.Internal, "user_ban", "failed to ban user")
 )
@@ -1,12 +1,15 @@
 package usecaseerr
 
+import "ads-service/internal/errs"
+
 var (
-	ErrAccessDenied      = Error("access denied")
-	ErrGettingAllAds     = Error("error getting all ads from database")
-	ErrGettingAdByID     = Error("error getting ad by ID from database")
-	ErrNoAds             = Error("no ads found")
-	ErrDeletingAd        = Error("error deleting ad from database")
-	ErrApprovingAd       = Error("error approving ad")
-	ErrRejectingAd       = Error("error rejecting ad")
-	ErrGettingStatistics = Error("error getting ad statistics")
+	ErrAccessDenied      = errs.New(errs.Forbidden, "access_denied", "access denied")
+	ErrGettingAllAds     = errs.New(errs.Internal, "getting_all_ads", "error getting all ads from database")
+	ErrGettingAdByID     = errs.New(errs.Internal, "getting_ad_by_id", "error getting ad by ID from database")
+	ErrNoAds             = errs.New(errs.NotFound, "no_ads", "no ads found")
+	ErrDeletingAd        = errs.New(errs.Internal, "deleting_ad", "error deleting ad from database")
+	ErrApprovingAd       = errs.New(errs.Internal, "approving_ad", "error approving ad")
+	ErrRejectingAd       = errs.New(errs.Internal, "rejecting_ad", "error rejecting ad")
+	ErrGettingStatistics = errs.New(errs.Internal, "getting_statistics", "error getting ad statistics")
+	ErrInvalidStatistics = errs.New(errs.Validation, "invalid_statistics", "invalid statistics period or granularity")
 )
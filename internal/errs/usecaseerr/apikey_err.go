@@ -0,0 +1,15 @@
+package usecaseerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrInvalidAPIKey     = errs.New(errs.Unauthorized, "invalid_api_key", "invalid api key")
+	ErrAPIKeyScope       = errs.New(errs.Forbidden, "api_key_scope", "api key does not have the required scope")
+	ErrInvalidAPIKeyData = errs.New(errs.Validation, "invalid_api_key_data", "invalid api key name, scopes or expiry")
+	ErrTooManyAPIKeys    = errs.New(errs.Conflict, "too_many_api_keys", "api key limit reached")
+	ErrAPIKeyNotFound    = errs.New(errs.NotFound, "api_key_not_found", "api key not found")
+	ErrCreatingAPIKey    = errs.New(errs.Internal, "creating_api_key", "error creating api key")
+	ErrGettingAPIKeys    = errs.New(errs.Internal, "getting_api_keys", "error getting api keys")
+	ErrRevokingAPIKey    = errs.New(errs.Internal, "revoking_api_key", "error revoking api key")
+	ErrCheckingAPIKey    = errs.New(errs.Internal, "checking_api_key", "error checking api key")
+)
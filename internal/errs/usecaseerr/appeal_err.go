@@ -0,0 +1,20 @@
+package usecaseerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrInvalidAppeal = errs.New(errs.Validation, "invalid_appeal",
+		"appeal message is required and must be at most 2000 characters")
+	ErrAdNotRejected   = errs.New(errs.Conflict, "ad_not_rejected", "ad is not rejected")
+	ErrAppealPending   = errs.New(errs.Conflict, "appeal_pending", "ad already has a pending appeal")
+	ErrAppealLimit     = errs.New(errs.Conflict, "appeal_limit", "appeal limit for this ad is reached")
+	ErrAppealNotFound  = errs.New(errs.NotFound, "appeal_not_found", "appeal not found")
+	ErrAppealReviewed  = errs.New(errs.Conflict, "appeal_reviewed", "appeal is already reviewed")
+	ErrInvalidDecision = errs.New(errs.Validation, "invalid_decision", "decision must be upheld or overturned")
+	ErrSameModerator   = errs.New(errs.Forbidden, "same_moderator",
+		"the moderator who rejected the ad cannot review its appeal")
+	ErrCreatingAppeal   = errs.New(errs.Internal, "creating_appeal", "error creating appeal")
+	ErrGettingAppeals   = errs.New(errs.Internal, "getting_appeals", "error getting appeals")
+	ErrReviewingAppeal  = errs.New(errs.Internal, "reviewing_appeal", "error reviewing appeal")
+	ErrGettingAdHistory = errs.New(errs.Internal, "getting_ad_history", "error getting moderation history")
+)
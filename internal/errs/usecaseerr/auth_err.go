@@ -0,0 +1,14 @@
+package usecaseerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrInvalidCredentials = errs.New(errs.Unauthorized, "invalid_credentials", "invalid credentials")
+	ErrTooManyAttempts    = errs.New(errs.TooManyRequests, "too_many_attempts",
+		"too many failed login attempts, try again later")
+	ErrCheckLockout    = errs.New(errs.Internal, "check_lockout", "error checking login lockout")
+	ErrGettingLockouts = errs.New(errs.Internal, "getting_lockouts", "error getting lockouts")
+	ErrClearingLockout = errs.New(errs.Internal, "clearing_lockout", "error clearing lockout")
+	ErrLockoutNotFound = errs.New(errs.NotFound, "lockout_not_found", "lockout not found")
+	ErrUserBanned      = errs.New(errs.Forbidden, "user_banned", "user is banned")
+)
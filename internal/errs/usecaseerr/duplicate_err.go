@@ -0,0 +1,10 @@
+package usecaseerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrRepost              = errs.New(errs.Conflict, "repost", "the same ad was submitted recently")
+	ErrFingerprintNotFound = errs.New(errs.NotFound, "fingerprint_not_found", "ad has not been submitted yet")
+	ErrFingerprinting      = errs.New(errs.Internal, "fingerprinting", "error fingerprinting ad")
+	ErrGettingDuplicates   = errs.New(errs.Internal, "getting_duplicates", "error getting duplicates")
+)
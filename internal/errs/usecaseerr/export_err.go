@@ -0,0 +1,7 @@
+package usecaseerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrExporting = errs.New(errs.Internal, "exporting", "error exporting data")
+)
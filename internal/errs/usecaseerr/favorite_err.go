@@ -0,0 +1,12 @@
+package usecaseerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrAdNotAvailable   = errs.New(errs.NotFound, "ad_not_available", "ad is not available")
+	ErrFavoriteOwnAd    = errs.New(errs.Validation, "favorite_own_ad", "cannot add own ad to favorites")
+	ErrFavoriteNotFound = errs.New(errs.NotFound, "favorite_not_found", "favorite not found")
+	ErrAddingFavorite   = errs.New(errs.Internal, "adding_favorite", "error adding favorite")
+	ErrRemovingFavorite = errs.New(errs.Internal, "removing_favorite", "error removing favorite")
+	ErrGettingFavorites = errs.New(errs.Internal, "getting_favorites", "error getting favorites")
+)
@@ -0,0 +1,11 @@
+package usecaseerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrIdempotencyKeyReused = errs.New(errs.Conflict, "idempotency_key_reused",
+		"idempotency key was already used with a different request")
+	ErrIdempotencyKeyInProgress = errs.New(errs.Conflict, "idempotency_key_in_progress",
+		"a request with this idempotency key is still in progress")
+	ErrIdempotency = errs.New(errs.Internal, "idempotency", "error checking idempotency key")
+)
@@ -0,0 +1,41 @@
+package usecaseerr
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs"
+	"ads-service/internal/errs/repoerr"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// AdLookup maps a failed lookup of an ad by ID: a missing ad is ErrAdNotFound, any other failure
+// is ErrGettingAdByID wrapping the cause.
+func AdLookup(err error) error {
+	if errors.Is(err, repoerr.ErrAdNotFound) {
+		return ErrAdNotFound
+	}
+	return fmt.Errorf("%w: %w", ErrGettingAdByID, err)
+}
+
+// UserLookup maps a failed lookup of a user by ID the same way, with ErrUserNotFound and ErrGettingUser.
+func UserLookup(err error) error {
+	if errors.Is(err, repoerr.ErrUserNotFound) {
+		return ErrUserNotFound
+	}
+	return fmt.Errorf("%w: %w", ErrGettingUser, err)
+}
+
+// AdWrite maps a failed write of the ad: failed wrapping the cause, or, when another writer got in between
+// the read and the write, an *errs.StaleError with the ad as it is now, read again through get.
+func AdWrite(ctx context.Context, adID int, err, failed error,
+	get func(ctx context.Context, id int) (*entities.Ad, error)) error {
+	if !errors.Is(err, repoerr.ErrAdModified) {
+		return fmt.Errorf("%w: %w", failed, err)
+	}
+	current, getErr := get(ctx, adID)
+	if getErr != nil {
+		return AdLookup(getErr)
+	}
+	return errs.NewStale(ErrAdModified, current, current.Version)
+}
@@ -0,0 +1,16 @@
+package usecaseerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrInvalidMessage       = errs.New(errs.Validation, "invalid_message", "message must be 1 to 2000 characters")
+	ErrMessageOwnAd         = errs.New(errs.Validation, "message_own_ad", "cannot start a conversation about own ad")
+	ErrConversationNotFound = errs.New(errs.NotFound, "conversation_not_found", "conversation not found")
+	ErrConversationLocked   = errs.New(errs.Conflict, "conversation_locked", "conversation is locked, the ad was deleted")
+	ErrUserBlocked          = errs.New(errs.Forbidden, "user_blocked", "the seller has blocked you")
+	ErrNotBlocked           = errs.New(errs.NotFound, "not_blocked", "user is not blocked")
+	ErrSendingMessage       = errs.New(errs.Internal, "sending_message", "error sending message")
+	ErrGettingConversations = errs.New(errs.Internal, "getting_conversations", "error getting conversations")
+	ErrGettingMessages      = errs.New(errs.Internal, "getting_messages", "error getting messages")
+	ErrBlockingUser         = errs.New(errs.Internal, "blocking_user", "error blocking user")
+)
@@ -0,0 +1,14 @@
+package usecaseerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrNotificationNotFound = errs.New(errs.NotFound, "notification_not_found", "notification not found")
+	ErrInvalidPreferences   = errs.New(errs.Validation, "invalid_preferences",
+		"email notifications require a valid email address")
+	ErrNotifying             = errs.New(errs.Internal, "notifying", "error delivering notification")
+	ErrGettingNotifications  = errs.New(errs.Internal, "getting_notifications", "error getting notifications")
+	ErrUpdatingNotifications = errs.New(errs.Internal, "updating_notifications", "error updating notifications")
+	ErrGettingPreferences    = errs.New(errs.Internal, "getting_preferences", "error getting notification preferences")
+	ErrUpdatingPreferences   = errs.New(errs.Internal, "updating_preferences", "error updating notification preferences")
+)
@@ -0,0 +1,21 @@
+package usecaseerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrInvalidRejectionTemplate = errs.New(errs.Validation, "invalid_rejection_template",
+		"invalid rejection template: bad code or no English text")
+	ErrRejectionTemplateNotFound = errs.New(errs.NotFound, "rejection_template_not_found", "rejection template not found")
+	ErrRejectionTemplateExists   = errs.New(errs.Conflict, "rejection_template_exists",
+		"rejection template already exists")
+	ErrRejectionTemplateInactive = errs.NewField("rejection_template_inactive", "code",
+		"rejection template is deactivated")
+	ErrUnknownRejectionCode    = errs.NewField("unknown_rejection_code", "code", "unknown rejection code")
+	ErrSystemRejectionTemplate = errs.New(errs.Validation, "system_rejection_template",
+		"rejection template is used by the service and cannot be deactivated")
+	ErrInvalidRejectionNote      = errs.NewField("invalid_rejection_note", "note", "rejection note is too long")
+	ErrGettingRejectionTemplates = errs.New(errs.Internal, "getting_rejection_templates",
+		"error getting rejection templates")
+	ErrGettingRejections       = errs.New(errs.Internal, "getting_rejections", "error getting rejection reasons")
+	ErrSavingRejectionTemplate = errs.New(errs.Internal, "saving_rejection_template", "error saving rejection template")
+)
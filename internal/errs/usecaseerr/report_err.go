@@ -0,0 +1,18 @@
+package usecaseerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrInvalidReport = errs.New(errs.Validation, "invalid_report",
+		"invalid report: unknown reason or comment too long")
+	ErrReportOwnAd       = errs.New(errs.Validation, "report_own_ad", "cannot report own ad")
+	ErrAlreadyReported   = errs.New(errs.Conflict, "already_reported", "you have already reported this ad")
+	ErrReportNotFound    = errs.New(errs.NotFound, "report_not_found", "report not found")
+	ErrReportResolved    = errs.New(errs.Conflict, "report_resolved", "report is already resolved")
+	ErrInvalidResolution = errs.New(errs.Validation, "invalid_resolution",
+		"resolution must be dismissed, ad_removed or user_banned")
+	ErrCannotBanAdmin  = errs.New(errs.Forbidden, "cannot_ban_admin", "admins cannot be banned")
+	ErrCreatingReport  = errs.New(errs.Internal, "creating_report", "error creating report")
+	ErrGettingReports  = errs.New(errs.Internal, "getting_reports", "error getting reports")
+	ErrResolvingReport = errs.New(errs.Internal, "resolving_report", "error resolving report")
+)
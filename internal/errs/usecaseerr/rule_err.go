@@ -0,0 +1,9 @@
+package usecaseerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrInvalidRules    = errs.New(errs.Validation, "invalid_rules", "invalid pre-moderation rules")
+	ErrScreeningAd     = errs.New(errs.Internal, "screening_ad", "error screening ad")
+	ErrGettingRuleHits = errs.New(errs.Internal, "getting_rule_hits", "error getting rule hits")
+)
@@ -0,0 +1,14 @@
+package usecaseerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrInvalidSavedSearch    = errs.New(errs.Validation, "invalid_saved_search", "invalid saved search")
+	ErrTooManySavedSearches  = errs.New(errs.Conflict, "too_many_saved_searches", "too many saved searches")
+	ErrSavedSearchNotFound   = errs.New(errs.NotFound, "saved_search_not_found", "saved search not found")
+	ErrCreatingSavedSearch   = errs.New(errs.Internal, "creating_saved_search", "error creating saved search")
+	ErrGettingSavedSearches  = errs.New(errs.Internal, "getting_saved_searches", "error getting saved searches")
+	ErrDeletingSavedSearch   = errs.New(errs.Internal, "deleting_saved_search", "error deleting saved search")
+	ErrMatchingSavedSearches = errs.New(errs.Internal, "matching_saved_searches", "error matching saved searches")
+	ErrSendingDigest         = errs.New(errs.Internal, "sending_digest", "error sending saved search digest")
+)
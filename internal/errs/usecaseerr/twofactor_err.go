@@ -0,0 +1,14 @@
+package usecaseerr
+
+import "ads-service/internal/errs"
+
+var (
+	ErrTwoFactorNotEnabled = errs.New(errs.Validation, "two_factor_not_enabled",
+		"two-factor authentication is not enabled")
+	ErrTwoFactorAlreadyEnabled = errs.New(errs.Conflict, "two_factor_already_enabled",
+		"two-factor authentication is already enabled")
+	ErrTwoFactorRequired    = errs.New(errs.Unauthorized, "two_factor_required", "two-factor authentication required")
+	ErrInvalidTwoFactorCode = errs.New(errs.Unauthorized, "invalid_two_factor_code", "invalid two-factor code")
+	ErrTwoFactorSetup       = errs.New(errs.Internal, "two_factor_setup", "error setting up two-factor authentication")
+	ErrTwoFactorCheck       = errs.New(errs.Internal, "two_factor_check", "error checking two-factor authentication")
+)
@@ -1,25 +1,31 @@
 package usecaseerr
 
-type Error string
-
-func (e Error) Error() string {
-	return string(e)
-}
+import "ads-service/internal/errs"
 
 var (
-	ErrInvalidUserData   = Error("invalid user data provided")
-	ErrUserAlreadyExists = Error("user already exists")
-	ErrCheckUserExists   = Error("error checking if user exists")
-	ErrUserNotHaveAds    = Error("user does not have any ads")
+	ErrInvalidUserData   = errs.New(errs.Validation, "invalid_user_data", "invalid user data provided")
+	ErrUserAlreadyExists = errs.New(errs.Conflict, "user_already_exists", "user already exists")
+	ErrCheckUserExists   = errs.New(errs.Internal, "check_user_exists", "error checking if user exists")
+	ErrUserNotHaveAds    = errs.New(errs.NotFound, "user_not_have_ads", "user does not have any ads")
+
+	ErrTokenGeneration      = errs.New(errs.Internal, "token_generation", "error generating token")
+	ErrInvalidToken         = errs.New(errs.Unauthorized, "invalid_token", "invalid token provided")
+	ErrTokenExpired         = errs.New(errs.Unauthorized, "token_expired", "token has expired")
+	ErrInvalidTokenDuration = errs.New(errs.Validation, "invalid_token_duration", "invalid token duration provided")
 
-	ErrTokenGeneration = Error("error generating token")
-	ErrInvalidToken    = Error("invalid token provided")
-	ErrTokenExpired    = Error("token has expired")
-	ErrInvalidTokenDuration = Error("invalid token duration provided")
+	ErrFileNotAllowed = errs.New(errs.Validation, "file_not_allowed", "file type not allowed for upload")
+	ErrAdNotFound     = errs.New(errs.NotFound, "ad_not_found", "ad not found")
+	ErrAdModified     = errs.New(errs.PreconditionFailed, "ad_modified", "ad was modified since it was read")
+	ErrGettingUser    = errs.New(errs.Internal, "getting_user", "error getting user from database")
+	ErrUserNotFound   = errs.New(errs.NotFound, "user_not_found", "user not found")
+	ErrInvalidParams  = errs.New(errs.Validation, "invalid_params", "invalid parameters")
 
-	ErrFileNotAllowed = Error("file type not allowed for upload")
-	ErrAdNotFound     = Error("ad not found")
-	ErrGettingUser    = Error("error getting user from database")
-	ErrUserNotFound   = Error("user not found")
-	ErrInvalidParams  = Error("invalid parameters")
+	ErrCreatingAd      = errs.New(errs.Internal, "creating_ad", "error creating ad")
+	ErrUpdatingAd      = errs.New(errs.Internal, "updating_ad", "error updating ad")
+	ErrSubmittingAd    = errs.New(errs.Internal, "submitting_ad", "error submitting ad for moderation")
+	ErrGettingUserAds  = errs.New(errs.Internal, "getting_user_ads", "error getting user ads")
+	ErrAddingImage     = errs.New(errs.Internal, "adding_image", "error adding image to ad")
+	ErrDeletingImage   = errs.New(errs.Internal, "deleting_image", "error deleting ad image")
+	ErrGettingImages   = errs.New(errs.Internal, "getting_images", "error getting ad images")
+	ErrRegisteringUser = errs.New(errs.Internal, "registering_user", "error registering user")
 )
@@ -0,0 +1,15 @@
+CREATE TYPE rule_decision AS ENUM ('approve', 'reject', 'review');
+
+-- Pre-moderation rules that matched a submitted ad. Every submission adds its own rows,
+-- decision is what the rules engine did with the ad on that submission.
+CREATE TABLE IF NOT EXISTS ad_rule_hits (
+    id SERIAL PRIMARY KEY,
+    ad_id INTEGER NOT NULL REFERENCES ads(id) ON DELETE CASCADE,
+    rule VARCHAR(50) NOT NULL,
+    detail VARCHAR(255) NOT NULL DEFAULT '',
+    score INTEGER NOT NULL,
+    decision rule_decision NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_ad_rule_hits_ad ON ad_rule_hits(ad_id, created_at DESC);
@@ -0,0 +1,12 @@
+-- Hashes of an ad taken on every submission to find reposts and near-duplicates.
+-- content_hash is SHA-256 of the normalized title and description, text_hash its SimHash,
+-- image_hashes the dHash of every image. 64-bit hashes are stored as signed BIGINT.
+CREATE TABLE IF NOT EXISTS ad_fingerprints (
+    ad_id INTEGER PRIMARY KEY REFERENCES ads(id) ON DELETE CASCADE,
+    content_hash CHAR(64) NOT NULL,
+    text_hash BIGINT NOT NULL,
+    image_hashes BIGINT[] NOT NULL DEFAULT '{}',
+    submitted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_ad_fingerprints_content ON ad_fingerprints(content_hash);
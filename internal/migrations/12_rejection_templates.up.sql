@@ -0,0 +1,30 @@
+-- Admin-managed rejection reasons. texts maps a language code to the text shown to the author,
+-- "en" is required. Templates are deactivated instead of deleted, rejected ads keep referring to them.
+CREATE TABLE IF NOT EXISTS rejection_templates (
+    code VARCHAR(50) PRIMARY KEY,
+    texts JSONB NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- rules_violation and author_banned are used by pre-moderation and abuse reports.
+INSERT INTO rejection_templates(code, texts) VALUES
+    ('prohibited_item', '{"en": "The item is not allowed on the platform.", "ru": "Товар запрещён к размещению на площадке."}'),
+    ('misleading', '{"en": "The title, description or price is misleading.", "ru": "Заголовок, описание или цена вводят в заблуждение."}'),
+    ('contact_details', '{"en": "Contact details and links are not allowed in the ad text.", "ru": "Контакты и ссылки в тексте объявления запрещены."}'),
+    ('poor_photos', '{"en": "The photos are missing, blurry or do not show the item.", "ru": "Фотографии отсутствуют, размыты или не показывают товар."}'),
+    ('wrong_category', '{"en": "The ad is placed in the wrong category.", "ru": "Объявление размещено не в той категории."}'),
+    ('duplicate', '{"en": "The same ad is already published.", "ru": "Такое объявление уже опубликовано."}'),
+    ('offensive', '{"en": "The ad contains offensive content.", "ru": "Объявление содержит оскорбительный контент."}'),
+    ('rules_violation', '{"en": "The ad violates the placement rules.", "ru": "Объявление нарушает правила размещения."}'),
+    ('author_banned', '{"en": "The author was banned after abuse reports.", "ru": "Автор заблокирован по жалобам пользователей."}')
+ON CONFLICT (code) DO NOTHING;
+
+-- rejection_reason keeps the English text with the note for notifications and older clients.
+ALTER TABLE ads ALTER COLUMN rejection_reason DROP DEFAULT;
+UPDATE ads SET rejection_reason = NULL WHERE rejection_reason = 'empty';
+ALTER TABLE ads ADD COLUMN IF NOT EXISTS rejection_code VARCHAR(50) REFERENCES rejection_templates(code);
+ALTER TABLE ads ADD COLUMN IF NOT EXISTS rejection_note TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_ads_rejection_code ON ads(rejection_code) WHERE status = 'rejected';
@@ -0,0 +1,34 @@
+CREATE TYPE moderation_action AS ENUM ('approved', 'rejected', 'appealed', 'appeal_upheld', 'appeal_overturned');
+CREATE TYPE appeal_status AS ENUM ('pending', 'upheld', 'overturned');
+
+-- Moderation history of an ad. actor_id is NULL for decisions of pre-moderation,
+-- for appeals it is the author.
+CREATE TABLE IF NOT EXISTS moderation_events (
+    id SERIAL PRIMARY KEY,
+    ad_id INTEGER NOT NULL REFERENCES ads(id) ON DELETE CASCADE,
+    action moderation_action NOT NULL,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    rejection_code VARCHAR(50) REFERENCES rejection_templates(code),
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_moderation_events_ad ON moderation_events(ad_id, created_at);
+
+-- An author's appeal against a rejection. rejected_by is the moderator who rejected the ad,
+-- NULL when pre-moderation did, they can't review the appeal.
+CREATE TABLE IF NOT EXISTS appeals (
+    id SERIAL PRIMARY KEY,
+    ad_id INTEGER NOT NULL REFERENCES ads(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    message TEXT NOT NULL,
+    status appeal_status NOT NULL DEFAULT 'pending',
+    rejected_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    decision_note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at TIMESTAMP
+);
+
+-- one appeal at a time per ad
+CREATE UNIQUE INDEX IF NOT EXISTS idx_appeals_pending ON appeals(ad_id) WHERE status = 'pending';
@@ -0,0 +1,6 @@
+-- Submissions join the moderation history, statistics measure the wait from the latest submission
+-- to the decision and the age of the backlog.
+ALTER TYPE moderation_action ADD VALUE IF NOT EXISTS 'submitted' BEFORE 'approved';
+
+CREATE INDEX IF NOT EXISTS idx_moderation_events_created ON moderation_events(created_at);
+CREATE INDEX IF NOT EXISTS idx_users_created ON users(created_at);
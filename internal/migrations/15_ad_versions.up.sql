@@ -0,0 +1,2 @@
+-- Every write of an ad bumps the version, a write based on an older version is refused.
+ALTER TABLE ads ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,2 @@
+-- Response headers replayed with the stored body, such as Location and ETag.
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS headers JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,12 @@
+CREATE TYPE lockout_kind AS ENUM ('phone', 'ip');
+
+CREATE TABLE IF NOT EXISTS login_attempts (
+    kind lockout_kind NOT NULL,
+    attempt_key VARCHAR(64) NOT NULL,
+    failures INTEGER NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP,
+    last_failure_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (kind, attempt_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_attempts_locked_until ON login_attempts(locked_until);
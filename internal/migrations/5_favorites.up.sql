@@ -0,0 +1,10 @@
+-- No foreign key to ads: a favorite outlives the ad and is shown as unavailable.
+CREATE TABLE IF NOT EXISTS favorites (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    ad_id INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL, -- snapshot, shown when the ad is gone
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, ad_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_favorites_ad ON favorites(ad_id);
@@ -0,0 +1,33 @@
+ALTER TABLE ads ADD COLUMN IF NOT EXISTS price BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE ads ADD COLUMN IF NOT EXISTS location VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE TYPE notify_mode AS ENUM ('instant', 'daily');
+
+-- Zero values mean "any": category_id = 0, price_min = 0, empty text and so on.
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id SERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    category_id INTEGER NOT NULL DEFAULT 0,
+    price_min BIGINT NOT NULL DEFAULT 0,
+    price_max BIGINT NOT NULL DEFAULT 0,
+    text VARCHAR(255) NOT NULL DEFAULT '',
+    location VARCHAR(255) NOT NULL DEFAULT '',
+    mode notify_mode NOT NULL DEFAULT 'instant',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_searches_user ON saved_searches(user_id);
+
+-- One row per (search, ad): an ad approved again after editing is not announced twice.
+-- notified_at stays NULL until the match is sent, daily searches collect matches here for the digest.
+CREATE TABLE IF NOT EXISTS saved_search_matches (
+    search_id INTEGER NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    ad_id INTEGER NOT NULL REFERENCES ads(id) ON DELETE CASCADE,
+    matched_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    notified_at TIMESTAMP,
+    PRIMARY KEY (search_id, ad_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_search_matches_pending
+    ON saved_search_matches(search_id) WHERE notified_at IS NULL;
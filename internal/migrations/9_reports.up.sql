@@ -0,0 +1,22 @@
+CREATE TYPE report_reason AS ENUM ('scam', 'prohibited', 'offensive', 'duplicate', 'wrong_category', 'other');
+CREATE TYPE report_status AS ENUM ('open', 'dismissed', 'ad_removed', 'user_banned');
+
+-- One report per user and ad. ad_id has no foreign key on purpose: reports outlive a removed ad
+-- as the moderation record, ad IDs are never reused.
+CREATE TABLE IF NOT EXISTS reports (
+    id SERIAL PRIMARY KEY,
+    ad_id INTEGER NOT NULL,
+    reporter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason report_reason NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    status report_status NOT NULL DEFAULT 'open',
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP,
+    UNIQUE (ad_id, reporter_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reports_open ON reports(ad_id) WHERE status = 'open';
+
+-- Banned users cannot log in, their ads are taken out of the catalog.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS banned_at TIMESTAMP;
@@ -3,10 +3,10 @@ package ad
 import (
 	"ads-service/internal/domain/entities"
 	"ads-service/internal/errs/repoerr"
+	"ads-service/pkg/db"
 	"context"
 	"errors"
 	"github.com/jackc/pgx/v5"
-	"log"
 	"strconv"
 )
 
@@ -14,15 +14,15 @@ func (r adRepo) Create(ctx context.Context, ad *entities.Ad) error {
 	_, err := r.db.Exec(ctx, `
         INSERT INTO ads(
             author_id, title, description, category_id, 
-            status, is_active, created_at, updated_at
-        ) VALUES($1, $2, $3, $4, $5, $6, $7, $8);`,
+            status, is_active, created_at, updated_at, price, location
+        ) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);`,
 		ad.AuthorID, ad.Title, ad.Description, ad.CategoryID,
-		ad.Status, ad.IsActive, ad.CreatedAt, ad.UpdatedAt)
+		ad.Status, ad.IsActive, ad.CreatedAt, ad.UpdatedAt, ad.Price, ad.Location)
 	if err != nil {
-		r.logger.ERROR("while inserting into ads:", err)
+		r.logger.ErrorContext(ctx, "while inserting into ads:", err)
 		return repoerr.ErrInsert
 	}
-	r.logger.INFO("Ad created successfully", ad.ID)
+	r.logger.InfoContext(ctx, "Ad created successfully", ad.ID)
 	return nil
 }
 
@@ -31,20 +31,20 @@ func (r adRepo) GetByID(ctx context.Context, id int) (*entities.Ad, error) {
 	err := r.db.QueryRow(ctx, `
 		SELECT 
 		    id, author_id, title, description, category_id, 
-			status, is_active, created_at, updated_at
+			status, is_active, created_at, updated_at, price, location, version
 		FROM ads
 		WHERE id = $1`, id).
 		Scan(&ad.ID, &ad.AuthorID, &ad.Title, &ad.Description, &ad.CategoryID, &ad.Status,
-			&ad.IsActive, &ad.CreatedAt, &ad.UpdatedAt)
+			&ad.IsActive, &ad.CreatedAt, &ad.UpdatedAt, &ad.Price, &ad.Location, &ad.Version)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			r.logger.ERROR("No ad found with ID: ", id)
+			r.logger.ErrorContext(ctx, "No ad found with ID: ", id)
 			return nil, repoerr.ErrAdNotFound
 		}
-		r.logger.ERROR("Error selecting ad: ", err)
+		r.logger.ErrorContext(ctx, "Error selecting ad: ", err)
 		return nil, repoerr.ErrGettingAdByID
 	}
-	r.logger.INFO("AD retrived by ID successfully, ID: ", ad.ID)
+	r.logger.InfoContext(ctx, "AD retrived by ID successfully, ID: ", ad.ID)
 	return &ad, nil
 }
 
@@ -52,11 +52,11 @@ func (r adRepo) GetByUserID(ctx context.Context, userID string) ([]entities.Ad,
 	rows, err := r.db.Query(ctx, `
 		SELECT 
 		    id, author_id, title, description, category_id, 
-			status, is_active, created_at, updated_at
+			status, is_active, created_at, updated_at, price, location, version
 		FROM ads
 		WHERE author_id = $1`, userID)
 	if err != nil {
-		r.logger.ERROR("Error getting user ads: ", err)
+		r.logger.ErrorContext(ctx, "Error getting user ads: ", err)
 		return nil, repoerr.ErrGettingAdsByUserID
 	}
 	defer rows.Close()
@@ -65,18 +65,18 @@ func (r adRepo) GetByUserID(ctx context.Context, userID string) ([]entities.Ad,
 	for rows.Next() {
 		var ad entities.Ad
 		if err = rows.Scan(&ad.ID, &ad.AuthorID, &ad.Title, &ad.Description, &ad.CategoryID, &ad.Status,
-			&ad.IsActive, &ad.CreatedAt, &ad.UpdatedAt); err != nil {
-			log.Println("Scan error:", err)
+			&ad.IsActive, &ad.CreatedAt, &ad.UpdatedAt, &ad.Price, &ad.Location, &ad.Version); err != nil {
+			r.logger.ErrorContext(ctx, "Scan error: ", err)
 			return nil, repoerr.ErrScan
 		}
 		ads = append(ads, ad)
 	}
 
 	if err = rows.Err(); err != nil {
-		r.logger.ERROR("Error iterating rows:", err)
+		r.logger.ErrorContext(ctx, "Error iterating rows:", err)
 		return nil, repoerr.ErrScan
 	}
-	r.logger.INFO("ADs retrived by user ID successfully, user ID: ", userID)
+	r.logger.InfoContext(ctx, "ADs retrived by user ID successfully, user ID: ", userID)
 	return ads, nil
 }
 
@@ -84,11 +84,11 @@ func (r adRepo) GetAll(ctx context.Context) ([]entities.Ad, error) {
 	rows, err := r.db.Query(ctx, `
 		SELECT
 		    id, author_id, title, description, category_id, 
-			status, is_active, created_at, updated_at
+			status, is_active, created_at, updated_at, price, location, version
 		FROM ads
 	`)
 	if err != nil {
-		r.logger.ERROR("Error getting ads:", err)
+		r.logger.ErrorContext(ctx, "Error getting ads:", err)
 		return nil, repoerr.ErrGettingAllAds
 	}
 
@@ -98,38 +98,58 @@ func (r adRepo) GetAll(ctx context.Context) ([]entities.Ad, error) {
 	for rows.Next() {
 		var ad entities.Ad
 		if err = rows.Scan(&ad.ID, &ad.AuthorID, &ad.Title, &ad.Description, &ad.CategoryID, &ad.Status,
-			&ad.IsActive, &ad.CreatedAt, &ad.UpdatedAt); err != nil {
-			r.logger.ERROR("Scan error: ", err)
+			&ad.IsActive, &ad.CreatedAt, &ad.UpdatedAt, &ad.Price, &ad.Location, &ad.Version); err != nil {
+			r.logger.ErrorContext(ctx, "Scan error: ", err)
 			return nil, repoerr.ErrScan
 		}
 		ads = append(ads, ad)
 	}
 
 	if err = rows.Err(); err != nil {
-		r.logger.ERROR("Error iterating rows: ", err)
+		r.logger.ErrorContext(ctx, "Error iterating rows: ", err)
 		return nil, repoerr.ErrScan
 	}
-	r.logger.INFO("ADs retrived successfully ")
+	r.logger.InfoContext(ctx, "ADs retrived successfully ")
 	return ads, nil
 }
 
+// Update saves the ad if it still has the version it was read with and bumps the version.
 func (r adRepo) Update(ctx context.Context, ad *entities.Ad) error {
 	row, err := r.db.Exec(ctx, `
 		UPDATE ads
 		SET title = $1, description = $2, category_id = $3,
-			status = $4, is_active = $5, updated_at = $6
-		WHERE id = $7;`, ad.Title, ad.Description, ad.CategoryID,
-		ad.Status, ad.IsActive, ad.UpdatedAt, ad.ID)
+			status = $4, is_active = $5, updated_at = $6, price = $7, location = $8, version = version + 1
+		WHERE id = $9 AND version = $10;`, ad.Title, ad.Description, ad.CategoryID,
+		ad.Status, ad.IsActive, ad.UpdatedAt, ad.Price, ad.Location, ad.ID, ad.Version)
 	if err != nil {
-		r.logger.ERROR("Error updating ad: ", err)
+		r.logger.ErrorContext(ctx, "Error updating ad: ", err)
 		return repoerr.ErrUpdate
 	}
 
 	if row.RowsAffected() == 0 {
-		r.logger.ERROR("No ad found with ID: ", ad.ID)
-		return repoerr.ErrAdNotFound
+		r.logger.ErrorContext(ctx, "Ad was modified or deleted, ID: ", ad.ID)
+		return repoerr.ErrAdModified
+	}
+	ad.Version++
+	r.logger.InfoContext(ctx, "AD updated successfully, ID: ", ad.ID)
+	return nil
+}
+
+// Touch bumps the version of the ad after a change of its images, like Update it checks the version first.
+func (r adRepo) Touch(ctx context.Context, ad *entities.Ad) error {
+	row, err := r.db.Exec(ctx, `
+		UPDATE ads
+		SET updated_at = $1, version = version + 1
+		WHERE id = $2 AND version = $3;`, ad.UpdatedAt, ad.ID, ad.Version)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error touching ad: ", err)
+		return repoerr.ErrUpdate
+	}
+	if row.RowsAffected() == 0 {
+		r.logger.ErrorContext(ctx, "Ad was modified or deleted, ID: ", ad.ID)
+		return repoerr.ErrAdModified
 	}
-	r.logger.INFO("AD updated successfully, ID: ", ad.ID)
+	ad.Version++
 	return nil
 }
 
@@ -138,49 +158,58 @@ func (r adRepo) Delete(ctx context.Context, id int) error {
 		DELETE FROM ads
 		WHERE id = $1;`, id)
 	if err != nil {
-		r.logger.ERROR("Error deleting ad: ", err)
+		r.logger.ErrorContext(ctx, "Error deleting ad: ", err)
 		return repoerr.ErrDelete
 	}
 	if row.RowsAffected() == 0 {
-		r.logger.ERROR("No ad found with ID: ", id)
+		r.logger.ErrorContext(ctx, "No ad found with ID: ", id)
 		return repoerr.ErrAdNotFound
 	}
-	r.logger.INFO("Ad deleted successfully: ", id)
+	r.logger.InfoContext(ctx, "Ad deleted successfully: ", id)
 	return nil
 }
 
 func (r adRepo) Approve(ctx context.Context, id int, ad *entities.Ad) error {
 	row, err := r.db.Exec(ctx, `
 		UPDATE ads
-		SET status = $1, is_active = $2, updated_at = $3
-		WHERE id = $4;`,
-		ad.Status, ad.IsActive, ad.UpdatedAt, id)
+		SET status = $1, is_active = $2, updated_at = $3, version = version + 1
+		WHERE id = $4 AND version = $5;`,
+		ad.Status, ad.IsActive, ad.UpdatedAt, id, ad.Version)
 	if err != nil {
-		r.logger.ERROR("Error approving ad: ", err)
+		r.logger.ErrorContext(ctx, "Error approving ad: ", err)
 		return repoerr.ErrApproval
 	}
 	if row.RowsAffected() == 0 {
-		r.logger.ERROR("No ad found with ID: ", id)
-		return repoerr.ErrAdNotFound
+		r.logger.ErrorContext(ctx, "Ad was modified or deleted, ID: ", id)
+		return repoerr.ErrAdModified
 	}
-	r.logger.INFO("Ad approved successfully, ID: ", id)
+	ad.Version++
+	r.logger.InfoContext(ctx, "Ad approved successfully, ID: ", id)
 	return nil
 }
 
 func (r adRepo) Reject(ctx context.Context, id int, ad *entities.Ad) error {
+	var code *string
+	note := ""
+	if ad.Rejection != nil {
+		code, note = &ad.Rejection.Code, ad.Rejection.Note
+	}
 	row, err := r.db.Exec(ctx, `
 		UPDATE ads
-		SET status = $1, rejection_reason = $2, is_active = $3, updated_at = $4
-		WHERE id = $5;`, ad.Status, ad.RejectionReason, ad.IsActive, ad.UpdatedAt, id)
+		SET status = $1, rejection_reason = $2, rejection_code = $3, rejection_note = $4,
+			is_active = $5, updated_at = $6, version = version + 1
+		WHERE id = $7 AND version = $8;`,
+		ad.Status, ad.RejectionReason, code, note, ad.IsActive, ad.UpdatedAt, id, ad.Version)
 	if err != nil {
-		r.logger.ERROR("Error rejecting ad: ", err)
+		r.logger.ErrorContext(ctx, "Error rejecting ad: ", err)
 		return repoerr.ErrRejection
 	}
 	if row.RowsAffected() == 0 {
-		r.logger.ERROR("No ad found with ID: ", id)
-		return repoerr.ErrAdNotFound
+		r.logger.ErrorContext(ctx, "Ad was modified or deleted, ID: ", id)
+		return repoerr.ErrAdModified
 	}
-	r.logger.INFO("Ad rejected successfully, ID: ", id)
+	ad.Version++
+	r.logger.InfoContext(ctx, "Ad rejected successfully, ID: ", id)
 	return nil
 }
 
@@ -201,21 +230,88 @@ func (r adRepo) GetStatistics(ctx context.Context) (entities.AdStatistics, error
 		&statistics.Rejected,
 	)
 	if err != nil {
-		r.logger.ERROR("Error getting ad statistics: ", err)
+		r.logger.ErrorContext(ctx, "Error getting ad statistics: ", err)
 		return statistics, repoerr.ErrGettingStatistics
 	}
-	r.logger.INFO("Statistics retrieved successfully ")
+	r.logger.InfoContext(ctx, "Statistics retrieved successfully ")
 	return statistics, nil
 }
 
 func (r adRepo) Filter(ctx context.Context, filter *entities.AdFilter) ([]entities.Ad, error) {
+	conditions, args := filterConditions(filter)
 	query := `
 		SELECT
 			id, author_id, title, description, category_id,
-			status, is_active, created_at, updated_at
+			status, is_active, created_at, updated_at, price, location, version
 		FROM ads
-		WHERE 1=1
-	`
+		WHERE 1=1` + conditions
+	argIdx := len(args) + 1
+
+	if filter.Limit > 0 {
+		query += " LIMIT $" + strconv.Itoa(argIdx)
+		args = append(args, filter.Limit)
+		argIdx++
+	}
+	if filter.Page > 0 && filter.Limit > 0 {
+		offset := (filter.Page - 1) * filter.Limit
+		query += " OFFSET $" + strconv.Itoa(argIdx)
+		args = append(args, offset)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Ошибка при фильтрации объявлений: ", err)
+		return nil, repoerr.ErrGettingAllAds
+	}
+	defer rows.Close()
+
+	var ads []entities.Ad
+	for rows.Next() {
+		var ad entities.Ad
+		if err = rows.Scan(&ad.ID, &ad.AuthorID, &ad.Title, &ad.Description, &ad.CategoryID, &ad.Status,
+			&ad.IsActive, &ad.CreatedAt, &ad.UpdatedAt, &ad.Price, &ad.Location, &ad.Version); err != nil {
+			r.logger.ErrorContext(ctx, "Ошибка сканирования: ", err)
+			return nil, repoerr.ErrScan
+		}
+		ads = append(ads, ad)
+	}
+	if err = rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Ошибка при обходе строк: ", err)
+		return nil, repoerr.ErrScan
+	}
+	r.logger.InfoContext(ctx, "Объявления успешно отфильтрованы")
+	return ads, nil
+}
+
+// Stream calls fn for every ad matching the filter in ID order. Limit and Page are ignored, the ads are read
+// through a cursor instead.
+func (r adRepo) Stream(ctx context.Context, filter *entities.AdFilter, fn func(*entities.Ad) error) error {
+	conditions, args := filterConditions(filter)
+	query := `
+		SELECT
+			id, author_id, title, description, category_id,
+			status, is_active, created_at, updated_at, price, location, version
+		FROM ads
+		WHERE 1=1` + conditions + `
+		ORDER BY id`
+	err := db.Stream(ctx, r.db, db.DefaultFetchSize, func(rows pgx.Rows) error {
+		var ad entities.Ad
+		if err := rows.Scan(&ad.ID, &ad.AuthorID, &ad.Title, &ad.Description, &ad.CategoryID, &ad.Status,
+			&ad.IsActive, &ad.CreatedAt, &ad.UpdatedAt, &ad.Price, &ad.Location, &ad.Version); err != nil {
+			return err
+		}
+		return fn(&ad)
+	}, query, args...)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error streaming ads: ", err)
+		return repoerr.ErrGettingAllAds
+	}
+	return nil
+}
+
+// filterConditions returns the AND conditions of the filter without Limit and Page, numbered from $1.
+func filterConditions(filter *entities.AdFilter) (string, []interface{}) {
+	var query string
 	var args []interface{}
 	argIdx := 1
 
@@ -244,38 +340,24 @@ func (r adRepo) Filter(ctx context.Context, filter *entities.AdFilter) ([]entiti
 		args = append(args, filter.CategoryID)
 		argIdx++
 	}
-	if filter.Limit > 0 {
-		query += " LIMIT $" + strconv.Itoa(argIdx)
-		args = append(args, filter.Limit)
+	if filter.PriceMin > 0 {
+		query += " AND price >= $" + strconv.Itoa(argIdx)
+		args = append(args, filter.PriceMin)
 		argIdx++
 	}
-	if filter.Page > 0 && filter.Limit > 0 {
-		offset := (filter.Page - 1) * filter.Limit
-		query += " OFFSET $" + strconv.Itoa(argIdx)
-		args = append(args, offset)
-	}
-
-	rows, err := r.db.Query(ctx, query, args...)
-	if err != nil {
-		r.logger.ERROR("Ошибка при фильтрации объявлений: ", err)
-		return nil, repoerr.ErrGettingAllAds
+	if filter.PriceMax > 0 {
+		query += " AND price <= $" + strconv.Itoa(argIdx)
+		args = append(args, filter.PriceMax)
+		argIdx++
 	}
-	defer rows.Close()
-
-	var ads []entities.Ad
-	for rows.Next() {
-		var ad entities.Ad
-		if err = rows.Scan(&ad.ID, &ad.AuthorID, &ad.Title, &ad.Description, &ad.CategoryID, &ad.Status,
-			&ad.IsActive, &ad.CreatedAt, &ad.UpdatedAt); err != nil {
-			r.logger.ERROR("Ошибка сканирования: ", err)
-			return nil, repoerr.ErrScan
-		}
-		ads = append(ads, ad)
+	if filter.Text != "" {
+		query += " AND strpos(lower(title || ' ' || description), lower($" + strconv.Itoa(argIdx) + ")) > 0"
+		args = append(args, filter.Text)
+		argIdx++
 	}
-	if err = rows.Err(); err != nil {
-		r.logger.ERROR("Ошибка при обходе строк: ", err)
-		return nil, repoerr.ErrScan
+	if filter.Location != "" {
+		query += " AND lower(location) = lower($" + strconv.Itoa(argIdx) + ")"
+		args = append(args, filter.Location)
 	}
-	r.logger.INFO("Объявления успешно отфильтрованы")
-	return ads, nil
+	return query, args
 }
@@ -92,6 +92,8 @@ func TestAdRepo_GetByID(t *testing.T) {
 			mock.Anything, // created_at
 			mock.Anything, // updated_at
 			mock.Anything, // rejection_reason
+			mock.Anything, // location
+			mock.Anything, // version
 		).Return(pgx.ErrNoRows)
 
 		ad, err := pool.GetByID(context.Background(), 1)
@@ -120,7 +122,9 @@ func TestAdRepo_GetByID(t *testing.T) {
 			mock.Anything, // is_active
 			mock.Anything, // created_at
 			mock.Anything, // updated_at
+			mock.Anything, // price
 			mock.Anything, // location
+			mock.Anything, // version
 		).Return(nil)
 
 		ad, err := pool.GetByID(context.Background(), 1)
@@ -140,7 +144,8 @@ func TestAdRepo_GetByID(t *testing.T) {
 			Return(mockRow)
 		mockRow.On("Scan",
 			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
-			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything).
 			Return(pgx.ErrNoRows)
 
 		ad, err := pool.GetByID(context.Background(), -1)
@@ -180,7 +185,8 @@ func TestAdRepo_GetByUserID(t *testing.T) {
 		mockRows.On("Next").Return(true).Once()
 		mockRows.On("Next").Return(false).Once()
 		mockRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
-			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything).Return(nil).Once()
 		mockRows.On("Err").Return(nil)
 		mockRows.On("Close").Return()
 
@@ -220,7 +226,8 @@ func TestAdRepo_GetAll(t *testing.T) {
 		mockRows.On("Next").Return(true).Once()
 		mockRows.On("Next").Return(false).Once()
 		mockRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
-			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything).
 			Return(nil).Once()
 		mockRows.On("Err").Return(nil)
 		mockRows.On("Close").Return()
@@ -250,14 +257,17 @@ func TestAdRepo_Update(t *testing.T) {
 		defer mockPool.AssertExpectations(t)
 
 		pool := &adRepo{db: mockPool}
-		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
-			Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.MatchedBy(func(args []interface{}) bool {
+			return len(args) == 10 && args[8] == 1 && args[9] == 3
+		})).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
 
-		err := pool.Update(context.Background(), &entities.Ad{})
+		ad := &entities.Ad{ID: 1, Version: 3}
+		err := pool.Update(context.Background(), ad)
 		assert.Nil(t, err)
+		assert.Equal(t, 4, ad.Version)
 	})
 
-	t.Run("not found at update ad", func(t *testing.T) {
+	t.Run("modified at update ad", func(t *testing.T) {
 		mockPool := new(db.MockPool)
 		defer mockPool.AssertExpectations(t)
 
@@ -267,7 +277,36 @@ func TestAdRepo_Update(t *testing.T) {
 			Return(tag, nil)
 
 		err := pool.Update(context.Background(), &entities.Ad{ID: 1})
-		assert.Equal(t, repoerr.ErrAdNotFound, err)
+		assert.Equal(t, repoerr.ErrAdModified, err)
+	})
+}
+
+func TestAdRepo_Touch(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		pool := &adRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.MatchedBy(func(args []interface{}) bool {
+			return len(args) == 3 && args[1] == 1 && args[2] == 3
+		})).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+
+		ad := &entities.Ad{ID: 1, Version: 3}
+		assert.NoError(t, pool.Touch(context.Background(), ad))
+		assert.Equal(t, 4, ad.Version)
+	})
+
+	t.Run("modified", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		pool := &adRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("UPDATE 0"), nil)
+
+		ad := &entities.Ad{ID: 1, Version: 3}
+		assert.Equal(t, repoerr.ErrAdModified, pool.Touch(context.Background(), ad))
+		assert.Equal(t, 3, ad.Version)
 	})
 }
 
@@ -339,7 +378,7 @@ func TestAdRepo_Approve(t *testing.T) {
 		assert.Nil(t, err)
 	})
 
-	t.Run("not found at approve ad", func(t *testing.T) {
+	t.Run("modified at approve ad", func(t *testing.T) {
 		mockPool := new(db.MockPool)
 		defer mockPool.AssertExpectations(t)
 
@@ -349,7 +388,7 @@ func TestAdRepo_Approve(t *testing.T) {
 			Return(tag, nil)
 
 		err := pool.Approve(context.Background(), 1, &entities.Ad{ID: 1})
-		assert.Equal(t, repoerr.ErrAdNotFound, err)
+		assert.Equal(t, repoerr.ErrAdModified, err)
 	})
 }
 
@@ -380,7 +419,7 @@ func TestAdRepo_Reject(t *testing.T) {
 		assert.Nil(t, err)
 	})
 
-	t.Run("not found at reject ad", func(t *testing.T) {
+	t.Run("modified at reject ad", func(t *testing.T) {
 		mockPool := new(db.MockPool)
 		defer mockPool.AssertExpectations(t)
 
@@ -390,7 +429,7 @@ func TestAdRepo_Reject(t *testing.T) {
 			Return(tag, nil)
 
 		err := pool.Reject(context.Background(), 1, &entities.Ad{ID: 1})
-		assert.Equal(t, repoerr.ErrAdNotFound, err)
+		assert.Equal(t, repoerr.ErrAdModified, err)
 	})
 }
 
@@ -458,7 +497,8 @@ func TestAdRepo_Filter(t *testing.T) {
 		mockRows.On("Next").Return(true).Once()
 		mockRows.On("Next").Return(false).Once()
 		mockRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
-			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything, mock.Anything).
 			Return(nil).Once()
 		mockRows.On("Err").Return(nil)
 		mockRows.On("Close").Return()
@@ -481,7 +521,7 @@ func TestAdRepo_Filter(t *testing.T) {
 		mockRows.On("Next").Return(true).Once()
 		mockRows.On("Scan", mock.Anything, mock.Anything, mock.Anything,
 			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
-			mock.Anything).
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(errors.New("scan error")).Once()
 		mockRows.On("Close").Return()
 
@@ -509,3 +549,52 @@ func TestAdRepo_Filter(t *testing.T) {
 		assert.Equal(t, repoerr.ErrScan, err)
 	})
 }
+
+func TestAdRepo_Stream(t *testing.T) {
+	t.Run("success at stream ads", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockTx := new(db.MockTx)
+		mockRows := new(db.MockRows)
+		defer mockTx.AssertExpectations(t)
+		defer mockRows.AssertExpectations(t)
+
+		pool := &adRepo{db: mockPool}
+		mockPool.On("Begin", mock.Anything).Return(mockTx, nil)
+		mockTx.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(pgconn.CommandTag{}, nil)
+		mockTx.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+		mockTx.On("Rollback", mock.Anything).Return(nil)
+		mockRows.On("Next").Return(true).Once()
+		mockRows.On("Next").Return(false).Once()
+		mockRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(0).(*int) = 7
+			}).Return(nil).Once()
+		mockRows.On("Err").Return(nil)
+		mockRows.On("Close").Return()
+
+		var ids []int
+		err := pool.Stream(context.Background(), &entities.AdFilter{Status: "approved"}, func(ad *entities.Ad) error {
+			ids = append(ids, ad.ID)
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []int{7}, ids)
+	})
+
+	t.Run("error at stream ads", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockTx := new(db.MockTx)
+		defer mockTx.AssertExpectations(t)
+
+		pool := &adRepo{db: mockPool}
+		mockPool.On("Begin", mock.Anything).Return(mockTx, nil)
+		mockTx.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.CommandTag{}, errors.New("db error"))
+		mockTx.On("Rollback", mock.Anything).Return(nil)
+
+		err := pool.Stream(context.Background(), &entities.AdFilter{}, func(*entities.Ad) error { return nil })
+		assert.Equal(t, repoerr.ErrGettingAllAds, err)
+	})
+}
@@ -67,6 +67,11 @@ func (m *MockAdRepo) Update(ctx context.Context, ad *entities.Ad) error {
 	return args.Error(0)
 }
 
+func (m *MockAdRepo) Touch(ctx context.Context, ad *entities.Ad) error {
+	args := m.Called(ctx, ad)
+	return args.Error(0)
+}
+
 func (m *MockAdRepo) Delete(ctx context.Context, id int) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -94,3 +99,8 @@ func (m *MockAdRepo) Filter(ctx context.Context, filter *entities.AdFilter) ([]e
 	}
 	return nil, args.Error(1)
 }
+
+func (m *MockAdRepo) Stream(ctx context.Context, filter *entities.AdFilter, fn func(*entities.Ad) error) error {
+	args := m.Called(ctx, filter, fn)
+	return args.Error(0)
+}
@@ -12,12 +12,16 @@ type AdRepository interface {
 	GetByID(ctx context.Context, id int) (*entities.Ad, error)
 	GetByUserID(ctx context.Context, userID string) ([]entities.Ad, error)
 	GetAll(ctx context.Context) ([]entities.Ad, error)
+	// Update, Touch, Approve and Reject write only the version the ad was read with and bump it,
+	// a changed or deleted ad gives repoerr.ErrAdModified.
 	Update(ctx context.Context, ad *entities.Ad) error
+	Touch(ctx context.Context, ad *entities.Ad) error
 	Delete(ctx context.Context, id int) error
 	Approve(ctx context.Context, id int, ad *entities.Ad) error
 	Reject(ctx context.Context, id int, ad *entities.Ad) error
 	GetStatistics(ctx context.Context) (entities.AdStatistics, error)
 	Filter(ctx context.Context, filter *entities.AdFilter) ([]entities.Ad, error)
+	Stream(ctx context.Context, filter *entities.AdFilter, fn func(*entities.Ad) error) error
 }
 
 type adRepo struct {
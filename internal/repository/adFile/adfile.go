@@ -17,36 +17,30 @@ func (r adFileRepo) Create(ctx context.Context, file *entities.AdFile) (int, err
 	row := r.db.QueryRow(ctx, insertQuery, file.AdID, file.FileName, file.URL)
 	err := row.Scan(&fileID)
 	if err != nil {
-		r.logger.ERROR("Error scanning fileID:", err)
+		r.logger.ErrorContext(ctx, "Error scanning fileID:", err)
 		return -1, repoerr.ErrFileInsertion
 	}
-	r.logger.INFO("Successfully created ad file with ID:", file.AdID)
+	r.logger.InfoContext(ctx, "Successfully created ad file with ID:", file.AdID)
 	return fileID, nil
 }
 
+// Delete removes the file of the ad in one statement, a file of another ad is not found.
 func (r adFileRepo) Delete(ctx context.Context, file *entities.AdFile) (string, error) {
 	var (
-		selectQuery = `SELECT url FROM ad_files WHERE id = $1`
-		deleteQuery = `DELETE FROM ad_files WHERE url=$1 AND ad_id = $2;`
+		deleteQuery = `DELETE FROM ad_files WHERE id = $1 AND ad_id = $2 RETURNING url;`
 		url         string
 	)
 
-	row := r.db.QueryRow(ctx, selectQuery, file.ID)
-	err := row.Scan(&url)
-	if err != nil {
+	row := r.db.QueryRow(ctx, deleteQuery, file.ID, file.AdID)
+	if err := row.Scan(&url); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			r.logger.ERROR("No ad file found with ID: ", file.ID)
+			r.logger.ErrorContext(ctx, "No ad file found with ID: ", file.ID)
 			return "", repoerr.ErrFileNotFound
 		}
-		r.logger.ERROR("Error selecting ad file: ", err)
-		return "", repoerr.ErrSelection
-	}
-
-	if _, err := r.db.Exec(ctx, deleteQuery, url, file.AdID); err != nil {
-		r.logger.ERROR("Error deleting ad file :", err)
+		r.logger.ErrorContext(ctx, "Error deleting ad file :", err)
 		return "", repoerr.ErrFileDeletion
 	}
-	r.logger.INFO("Deleted ad file successfully", file)
+	r.logger.InfoContext(ctx, "Deleted ad file successfully", file)
 	return url, nil
 }
 
@@ -58,7 +52,7 @@ func (r adFileRepo) GetAll(ctx context.Context, adID int) ([]entities.AdFile, er
 
 	rows, err := r.db.Query(ctx, selectQuery, adID)
 	if err != nil {
-		r.logger.ERROR("Error selecting ad files:", err)
+		r.logger.ErrorContext(ctx, "Error selecting ad files:", err)
 		return nil, repoerr.ErrFileSelection
 	}
 	defer rows.Close()
@@ -66,17 +60,17 @@ func (r adFileRepo) GetAll(ctx context.Context, adID int) ([]entities.AdFile, er
 	for rows.Next() {
 		var file entities.AdFile
 		if err := rows.Scan(&file.ID, &file.AdID, &file.FileName, &file.URL, &file.CreatedAt); err != nil {
-			r.logger.ERROR("Error scanning ad file:", err)
+			r.logger.ErrorContext(ctx, "Error scanning ad file:", err)
 			return nil, repoerr.ErrJSONUnmarshal
 		}
 		files = append(files, file)
 	}
 
 	if err := rows.Err(); err != nil {
-		r.logger.ERROR("Error iterating over ad files:", err)
+		r.logger.ErrorContext(ctx, "Error iterating over ad files:", err)
 		return nil, repoerr.ErrSelection
 	}
-	r.logger.INFO("Retrieved all ad files", len(files))
+	r.logger.InfoContext(ctx, "Retrieved all ad files", len(files))
 
 	return files, nil
 }
@@ -9,7 +9,6 @@ import (
 	"context"
 	"errors"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
@@ -126,17 +125,7 @@ func TestAdFileRepo_Delete(t *testing.T) {
 		mockRow.On("Scan", mock.AnythingOfType("*string")).Run(func(args mock.Arguments) {
 			*(args[0].(*string)) = "http://example.com/file.jpg"
 		}).Return(nil)
-		mockPool.On("QueryRow", mock.Anything, mock.Anything,
-			mock.MatchedBy(func(arg interface{}) bool {
-				if args, ok := arg.([]interface{}); ok && len(args) == 1 {
-					if id, ok := args[0].(int); ok {
-						return id == file.ID
-					}
-				}
-				return false
-			})).Return(mockRow)
-		mockPool.On("Exec", mock.Anything, mock.Anything, []interface{}{"http://example.com/file.jpg", 1}).
-			Return(pgconn.NewCommandTag("DELETE 1"), nil)
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, []interface{}{1, 1}).Return(mockRow)
 
 		url, err := repo.Delete(context.Background(), file)
 		assert.NoError(t, err)
@@ -152,13 +141,26 @@ func TestAdFileRepo_Delete(t *testing.T) {
 
 		mockRow := new(db.MockRow)
 		mockRow.On("Scan", mock.Anything).Return(pgx.ErrNoRows)
-		mockPool.On("QueryRow", mock.Anything, mock.Anything,
-			mock.MatchedBy(func(args []interface{}) bool {
-				return len(args) == 1 && args[0] == file.ID
-			})).Return(mockRow)
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, []interface{}{1, 1}).Return(mockRow)
 
 		url, err := repo.Delete(context.Background(), file)
 		assert.Equal(t, "", url)
 		assert.Equal(t, repoerr.ErrFileNotFound, err)
 	})
+
+	t.Run("delete error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := NewAdFileRepo(mockPool, customLogger.Logger{})
+		file := &entities.AdFile{ID: 1, AdID: 1}
+
+		mockRow := new(db.MockRow)
+		mockRow.On("Scan", mock.Anything).Return(errors.New("connection reset"))
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, []interface{}{1, 1}).Return(mockRow)
+
+		url, err := repo.Delete(context.Background(), file)
+		assert.Equal(t, "", url)
+		assert.Equal(t, repoerr.ErrFileDeletion, err)
+	})
 }
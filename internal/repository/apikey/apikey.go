@@ -0,0 +1,138 @@
+package apikey
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const selectKey = `
+	SELECT id, user_id, name, prefix, key_hash, scopes, expires_at, last_used_at, revoked_at, created_at
+	FROM api_keys`
+
+func (r *apiKeyRepo) Create(ctx context.Context, key *entities.APIKey) (int, error) {
+	var (
+		id        int
+		expiresAt *time.Time
+	)
+	if !key.ExpiresAt.IsZero() {
+		expiresAt = &key.ExpiresAt
+	}
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO api_keys(user_id, name, prefix, key_hash, scopes, expires_at)
+		VALUES($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`,
+		key.UserID, key.Name, key.Prefix, key.Hash, key.Scopes, expiresAt).Scan(&id, &key.CreatedAt)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error inserting api key: ", err)
+		return 0, repoerr.ErrAPIKeyInsert
+	}
+	r.logger.InfoContext(ctx, "API key created for user: ", key.UserID)
+	return id, nil
+}
+
+func (r *apiKeyRepo) GetByPrefix(ctx context.Context, prefix string) (*entities.APIKey, error) {
+	key, err := scanKey(r.db.QueryRow(ctx, selectKey+`
+		WHERE prefix = $1`, prefix))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repoerr.ErrAPIKeyNotFound
+		}
+		r.logger.ErrorContext(ctx, "Error selecting api key: ", err)
+		return nil, repoerr.ErrAPIKeySelect
+	}
+	return key, nil
+}
+
+func (r *apiKeyRepo) GetByUser(ctx context.Context, userID string) ([]entities.APIKey, error) {
+	rows, err := r.db.Query(ctx, selectKey+`
+		WHERE user_id = $1
+		ORDER BY created_at DESC`, userID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error selecting api keys: ", err)
+		return nil, repoerr.ErrAPIKeySelect
+	}
+	defer rows.Close()
+
+	var keys []entities.APIKey
+	for rows.Next() {
+		key, err := scanKey(rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning api key: ", err)
+			return nil, repoerr.ErrScan
+		}
+		keys = append(keys, *key)
+	}
+	if err = rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating api keys: ", err)
+		return nil, repoerr.ErrScan
+	}
+	return keys, nil
+}
+
+func (r *apiKeyRepo) CountActive(ctx context.Context, userID string, now time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM api_keys
+		WHERE user_id = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > $2)`,
+		userID, now).Scan(&count)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error counting api keys: ", err)
+		return 0, repoerr.ErrAPIKeySelect
+	}
+	return count, nil
+}
+
+func (r *apiKeyRepo) Revoke(ctx context.Context, userID string, id int) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE api_keys
+		SET revoked_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;`, id, userID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error revoking api key: ", err)
+		return repoerr.ErrAPIKeyUpdate
+	}
+	if tag.RowsAffected() == 0 {
+		return repoerr.ErrAPIKeyNotFound
+	}
+	r.logger.InfoContext(ctx, "API key revoked: ", id)
+	return nil
+}
+
+func (r *apiKeyRepo) TouchLastUsed(ctx context.Context, id int, at time.Time) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE api_keys
+		SET last_used_at = $2
+		WHERE id = $1;`, id, at)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error updating api key last use: ", err)
+		return repoerr.ErrAPIKeyUpdate
+	}
+	return nil
+}
+
+func scanKey(row pgx.Row) (*entities.APIKey, error) {
+	var (
+		key                              entities.APIKey
+		expiresAt, lastUsedAt, revokedAt *time.Time
+	)
+	if err := row.Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.Hash, &key.Scopes,
+		&expiresAt, &lastUsedAt, &revokedAt, &key.CreatedAt); err != nil {
+		return nil, err
+	}
+	if expiresAt != nil {
+		key.ExpiresAt = *expiresAt
+	}
+	if lastUsedAt != nil {
+		key.LastUsedAt = *lastUsedAt
+	}
+	if revokedAt != nil {
+		key.RevokedAt = *revokedAt
+	}
+	return &key, nil
+}
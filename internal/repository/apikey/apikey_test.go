@@ -0,0 +1,207 @@
+//nolint:all // testpackage
+package apikey
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/pkg/db"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAPIKeyRepo_Create(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &apiKeyRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 7
+		}).Return(nil)
+
+		id, err := repo.Create(context.Background(), &entities.APIKey{
+			UserID:    "user-1",
+			Scopes:    []entities.APIKeyScope{entities.ScopeAdsRead},
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 7, id)
+	})
+
+	t.Run("insert error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &apiKeyRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+		id, err := repo.Create(context.Background(), &entities.APIKey{UserID: "user-1"})
+		assert.Equal(t, 0, id)
+		assert.Equal(t, repoerr.ErrAPIKeyInsert, err)
+	})
+}
+
+func TestAPIKeyRepo_GetByPrefix(t *testing.T) {
+	scanArgs := []interface{}{mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything}
+
+	t.Run("not found", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &apiKeyRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", scanArgs...).Return(pgx.ErrNoRows)
+
+		key, err := repo.GetByPrefix(context.Background(), "abcd1234")
+		assert.Nil(t, key)
+		assert.Equal(t, repoerr.ErrAPIKeyNotFound, err)
+	})
+
+	t.Run("select error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &apiKeyRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", scanArgs...).Return(errors.New("db error"))
+
+		key, err := repo.GetByPrefix(context.Background(), "abcd1234")
+		assert.Nil(t, key)
+		assert.Equal(t, repoerr.ErrAPIKeySelect, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &apiKeyRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", scanArgs...).Return(nil)
+
+		key, err := repo.GetByPrefix(context.Background(), "abcd1234")
+		assert.NoError(t, err)
+		assert.NotNil(t, key)
+	})
+}
+
+func TestAPIKeyRepo_GetByUser(t *testing.T) {
+	t.Run("query error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &apiKeyRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).
+			Return(new(db.MockRows), errors.New("db error"))
+
+		keys, err := repo.GetByUser(context.Background(), "user-1")
+		assert.Nil(t, keys)
+		assert.Equal(t, repoerr.ErrAPIKeySelect, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRows := new(db.MockRows)
+		defer mockPool.AssertExpectations(t)
+		defer mockRows.AssertExpectations(t)
+
+		repo := &apiKeyRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+		mockRows.On("Next").Return(true).Once()
+		mockRows.On("Next").Return(false).Once()
+		mockRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		mockRows.On("Err").Return(nil)
+		mockRows.On("Close").Return()
+
+		keys, err := repo.GetByUser(context.Background(), "user-1")
+		assert.NoError(t, err)
+		assert.Len(t, keys, 1)
+	})
+}
+
+func TestAPIKeyRepo_Revoke(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &apiKeyRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("UPDATE 0"), nil)
+
+		err := repo.Revoke(context.Background(), "user-1", 1)
+		assert.Equal(t, repoerr.ErrAPIKeyNotFound, err)
+	})
+
+	t.Run("exec error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &apiKeyRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.CommandTag{}, errors.New("db error"))
+
+		err := repo.Revoke(context.Background(), "user-1", 1)
+		assert.Equal(t, repoerr.ErrAPIKeyUpdate, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &apiKeyRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+
+		err := repo.Revoke(context.Background(), "user-1", 1)
+		assert.NoError(t, err)
+	})
+}
+
+func TestAPIKeyRepo_CountActive(t *testing.T) {
+	mockPool := new(db.MockPool)
+	mockRow := new(db.MockRow)
+	defer mockPool.AssertExpectations(t)
+	defer mockRow.AssertExpectations(t)
+
+	repo := &apiKeyRepo{db: mockPool}
+	mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+	mockRow.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*int) = 3
+	}).Return(nil)
+
+	count, err := repo.CountActive(context.Background(), "user-1", time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
+func TestAPIKeyRepo_TouchLastUsed(t *testing.T) {
+	mockPool := new(db.MockPool)
+	defer mockPool.AssertExpectations(t)
+
+	repo := &apiKeyRepo{db: mockPool}
+	mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+		Return(pgconn.CommandTag{}, errors.New("db error"))
+
+	err := repo.TouchLastUsed(context.Background(), 1, time.Now())
+	assert.Equal(t, repoerr.ErrAPIKeyUpdate, err)
+}
@@ -0,0 +1,52 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package apikey
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAPIKeyRepo struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepo) Create(ctx context.Context, key *entities.APIKey) (int, error) {
+	args := m.Called(ctx, key)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAPIKeyRepo) GetByPrefix(ctx context.Context, prefix string) (*entities.APIKey, error) {
+	args := m.Called(ctx, prefix)
+	if key, ok := args.Get(0).(*entities.APIKey); ok {
+		return key, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAPIKeyRepo) GetByUser(ctx context.Context, userID string) ([]entities.APIKey, error) {
+	args := m.Called(ctx, userID)
+	if keys, ok := args.Get(0).([]entities.APIKey); ok {
+		return keys, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAPIKeyRepo) CountActive(ctx context.Context, userID string, now time.Time) (int, error) {
+	args := m.Called(ctx, userID, now)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAPIKeyRepo) Revoke(ctx context.Context, userID string, id int) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepo) TouchLastUsed(ctx context.Context, id int, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+var _ APIKeyRepository = (*MockAPIKeyRepo)(nil)
@@ -0,0 +1,28 @@
+package apikey
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/pkg/db"
+	customLogger "ads-service/pkg/logger"
+	"context"
+	"time"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *entities.APIKey) (int, error)
+	GetByPrefix(ctx context.Context, prefix string) (*entities.APIKey, error)
+	GetByUser(ctx context.Context, userID string) ([]entities.APIKey, error)
+	// CountActive counts keys that are not revoked and not expired at the moment.
+	CountActive(ctx context.Context, userID string, now time.Time) (int, error)
+	Revoke(ctx context.Context, userID string, id int) error
+	TouchLastUsed(ctx context.Context, id int, at time.Time) error
+}
+
+type apiKeyRepo struct {
+	db     db.Pool
+	logger customLogger.Logger
+}
+
+func NewAPIKeyRepo(pool db.Pool, logTool customLogger.Logger) APIKeyRepository {
+	return &apiKeyRepo{db: pool, logger: logTool}
+}
@@ -0,0 +1,122 @@
+package appeal
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const selectAppeal = `
+	SELECT id, ad_id, author_id, message, status, COALESCE(rejected_by::text, ''),
+		COALESCE(reviewed_by::text, ''), decision_note, created_at, reviewed_at
+	FROM appeals`
+
+func (r *appealRepo) Create(ctx context.Context, appeal *entities.Appeal) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO appeals(ad_id, author_id, message, rejected_by)
+		SELECT $1, $2, $3, (
+			SELECT actor_id FROM moderation_events
+			WHERE ad_id = $1 AND action = 'rejected'
+			ORDER BY created_at DESC, id DESC
+			LIMIT 1
+		)
+		ON CONFLICT (ad_id) WHERE status = 'pending' DO NOTHING
+		RETURNING id, COALESCE(rejected_by::text, ''), created_at;`,
+		appeal.AdID, appeal.AuthorID, appeal.Message).
+		Scan(&appeal.ID, &appeal.RejectedBy, &appeal.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repoerr.ErrAppealPending
+		}
+		r.logger.ErrorContext(ctx, "Error inserting appeal: ", err)
+		return repoerr.ErrAppealInsert
+	}
+	appeal.Status = entities.AppealPending
+	return nil
+}
+
+func (r *appealRepo) GetByID(ctx context.Context, id int) (*entities.Appeal, error) {
+	appeal, err := scanAppeal(r.db.QueryRow(ctx, selectAppeal+`
+		WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repoerr.ErrAppealNotFound
+		}
+		r.logger.ErrorContext(ctx, "Error selecting appeal: ", err)
+		return nil, repoerr.ErrAppealSelect
+	}
+	return appeal, nil
+}
+
+func (r *appealRepo) GetByAd(ctx context.Context, adID int) ([]entities.Appeal, error) {
+	return r.list(ctx, selectAppeal+`
+		WHERE ad_id = $1
+		ORDER BY created_at, id`, adID)
+}
+
+func (r *appealRepo) ListPending(ctx context.Context) ([]entities.Appeal, error) {
+	return r.list(ctx, selectAppeal+`
+		WHERE status = 'pending'
+		ORDER BY created_at, id`)
+}
+
+func (r *appealRepo) Review(ctx context.Context, appeal *entities.Appeal) error {
+	err := r.db.QueryRow(ctx, `
+		UPDATE appeals
+		SET status = $2, reviewed_by = $3, decision_note = $4, reviewed_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'pending'
+		RETURNING reviewed_at;`, appeal.ID, appeal.Status, appeal.ReviewedBy, appeal.DecisionNote).
+		Scan(&appeal.ReviewedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repoerr.ErrAppealReviewed
+		}
+		r.logger.ErrorContext(ctx, "Error updating appeal: ", err)
+		return repoerr.ErrAppealUpdate
+	}
+	return nil
+}
+
+func (r *appealRepo) list(ctx context.Context, query string, args ...any) ([]entities.Appeal, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error selecting appeals: ", err)
+		return nil, repoerr.ErrAppealSelect
+	}
+	defer rows.Close()
+
+	var appeals []entities.Appeal
+	for rows.Next() {
+		appeal, err := scanAppeal(rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning appeal: ", err)
+			return nil, repoerr.ErrScan
+		}
+		appeals = append(appeals, *appeal)
+	}
+	if err = rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating appeals: ", err)
+		return nil, repoerr.ErrScan
+	}
+	return appeals, nil
+}
+
+func scanAppeal(row pgx.Row) (*entities.Appeal, error) {
+	var (
+		appeal     entities.Appeal
+		reviewedAt *time.Time
+	)
+	err := row.Scan(&appeal.ID, &appeal.AdID, &appeal.AuthorID, &appeal.Message, &appeal.Status, &appeal.RejectedBy,
+		&appeal.ReviewedBy, &appeal.DecisionNote, &appeal.CreatedAt, &reviewedAt)
+	if err != nil {
+		return nil, err
+	}
+	if reviewedAt != nil {
+		appeal.ReviewedAt = *reviewedAt
+	}
+	return &appeal, nil
+}
@@ -0,0 +1,151 @@
+//nolint:all // testpackage
+package appeal
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/pkg/db"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAppealRepo_Create(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &appealRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 3
+			*args.Get(1).(*string) = "moderator"
+		}).Return(nil)
+
+		appeal := &entities.Appeal{AdID: 1, AuthorID: "author", Message: "the photos are mine"}
+		assert.NoError(t, repo.Create(context.Background(), appeal))
+		assert.Equal(t, 3, appeal.ID)
+		assert.Equal(t, "moderator", appeal.RejectedBy)
+		assert.Equal(t, entities.AppealPending, appeal.Status)
+	})
+
+	t.Run("pending appeal exists", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &appealRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything).Return(pgx.ErrNoRows)
+
+		err := repo.Create(context.Background(), &entities.Appeal{AdID: 1})
+		assert.Equal(t, repoerr.ErrAppealPending, err)
+	})
+}
+
+func TestAppealRepo_GetByID(t *testing.T) {
+	t.Run("reviewed", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		reviewedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		repo := &appealRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 3
+			*args.Get(4).(*entities.AppealStatus) = entities.AppealUpheld
+			*args.Get(9).(**time.Time) = &reviewedAt
+		}).Return(nil)
+
+		appeal, err := repo.GetByID(context.Background(), 3)
+		assert.NoError(t, err)
+		assert.Equal(t, entities.AppealUpheld, appeal.Status)
+		assert.Equal(t, reviewedAt, appeal.ReviewedAt)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &appealRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(pgx.ErrNoRows)
+
+		appeal, err := repo.GetByID(context.Background(), 3)
+		assert.Nil(t, appeal)
+		assert.Equal(t, repoerr.ErrAppealNotFound, err)
+	})
+}
+
+func TestAppealRepo_ListPending(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRows := new(db.MockRows)
+		defer mockPool.AssertExpectations(t)
+		defer mockRows.AssertExpectations(t)
+
+		repo := &appealRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+		mockRows.On("Next").Return(true).Once()
+		mockRows.On("Next").Return(false).Once()
+		mockRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 3
+		}).Return(nil).Once()
+		mockRows.On("Err").Return(nil)
+		mockRows.On("Close").Return()
+
+		appeals, err := repo.ListPending(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, appeals, 1)
+		assert.Equal(t, 3, appeals[0].ID)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &appealRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(new(db.MockRows), errors.New("db error"))
+
+		appeals, err := repo.ListPending(context.Background())
+		assert.Nil(t, appeals)
+		assert.Equal(t, repoerr.ErrAppealSelect, err)
+	})
+}
+
+func TestAppealRepo_Review(t *testing.T) {
+	for name, tc := range map[string]struct {
+		scanErr error
+		want    error
+	}{
+		"success":          {},
+		"already reviewed": {scanErr: pgx.ErrNoRows, want: repoerr.ErrAppealReviewed},
+		"db error":         {scanErr: errors.New("db error"), want: repoerr.ErrAppealUpdate},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockPool := new(db.MockPool)
+			mockRow := new(db.MockRow)
+			defer mockPool.AssertExpectations(t)
+
+			repo := &appealRepo{db: mockPool}
+			mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+			mockRow.On("Scan", mock.Anything).Return(tc.scanErr)
+
+			err := repo.Review(context.Background(), &entities.Appeal{ID: 3, Status: entities.AppealUpheld})
+			assert.Equal(t, tc.want, err)
+		})
+	}
+}
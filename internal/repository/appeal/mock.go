@@ -0,0 +1,49 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package appeal
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAppealRepo struct {
+	mock.Mock
+}
+
+func (m *MockAppealRepo) Create(ctx context.Context, appeal *entities.Appeal) error {
+	args := m.Called(ctx, appeal)
+	return args.Error(0)
+}
+
+func (m *MockAppealRepo) GetByID(ctx context.Context, id int) (*entities.Appeal, error) {
+	args := m.Called(ctx, id)
+	if appeal, ok := args.Get(0).(*entities.Appeal); ok {
+		return appeal, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAppealRepo) GetByAd(ctx context.Context, adID int) ([]entities.Appeal, error) {
+	args := m.Called(ctx, adID)
+	if appeals, ok := args.Get(0).([]entities.Appeal); ok {
+		return appeals, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAppealRepo) ListPending(ctx context.Context) ([]entities.Appeal, error) {
+	args := m.Called(ctx)
+	if appeals, ok := args.Get(0).([]entities.Appeal); ok {
+		return appeals, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAppealRepo) Review(ctx context.Context, appeal *entities.Appeal) error {
+	args := m.Called(ctx, appeal)
+	return args.Error(0)
+}
+
+var _ AppealRepository = (*MockAppealRepo)(nil)
@@ -0,0 +1,29 @@
+package appeal
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/pkg/db"
+	customLogger "ads-service/pkg/logger"
+	"context"
+)
+
+type AppealRepository interface {
+	// Create saves a pending appeal, RejectedBy is taken from the last rejection in the moderation history.
+	Create(ctx context.Context, appeal *entities.Appeal) error
+	GetByID(ctx context.Context, id int) (*entities.Appeal, error)
+	// GetByAd returns all appeals of the ad, oldest first.
+	GetByAd(ctx context.Context, adID int) ([]entities.Appeal, error)
+	// ListPending returns the appeals waiting for review, oldest first.
+	ListPending(ctx context.Context) ([]entities.Appeal, error)
+	// Review saves the decision of a pending appeal.
+	Review(ctx context.Context, appeal *entities.Appeal) error
+}
+
+type appealRepo struct {
+	db     db.Pool
+	logger customLogger.Logger
+}
+
+func NewAppealRepo(pool db.Pool, logTool customLogger.Logger) AppealRepository {
+	return &appealRepo{db: pool, logger: logTool}
+}
@@ -0,0 +1,163 @@
+package favorite
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"context"
+	"time"
+)
+
+func (r *favoriteRepo) Add(ctx context.Context, userID string, adID int, title string) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO favorites(user_id, ad_id, title)
+		VALUES($1, $2, $3)
+		ON CONFLICT (user_id, ad_id) DO NOTHING;`, userID, adID, title)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error inserting favorite: ", err)
+		return repoerr.ErrFavoriteInsert
+	}
+	r.logger.InfoContext(ctx, "Ad ", adID, " added to favorites of user: ", userID)
+	return nil
+}
+
+func (r *favoriteRepo) Delete(ctx context.Context, userID string, adID int) error {
+	tag, err := r.db.Exec(ctx, `
+		DELETE FROM favorites
+		WHERE user_id = $1 AND ad_id = $2;`, userID, adID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error deleting favorite: ", err)
+		return repoerr.ErrFavoriteDelete
+	}
+	if tag.RowsAffected() == 0 {
+		return repoerr.ErrFavoriteNotFound
+	}
+	r.logger.InfoContext(ctx, "Ad ", adID, " removed from favorites of user: ", userID)
+	return nil
+}
+
+func (r *favoriteRepo) GetByUser(ctx context.Context, userID string) ([]entities.Favorite, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT
+			f.ad_id, f.title, f.created_at,
+			a.id, a.author_id, a.title, a.description, a.category_id,
+			a.status, a.is_active, a.created_at, a.updated_at, a.price, a.location
+		FROM favorites f
+		LEFT JOIN ads a ON a.id = f.ad_id
+		WHERE f.user_id = $1
+		ORDER BY f.created_at DESC`, userID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error selecting favorites: ", err)
+		return nil, repoerr.ErrFavoriteSelect
+	}
+	defer rows.Close()
+
+	var favorites []entities.Favorite
+	for rows.Next() {
+		var (
+			fav                  entities.Favorite
+			adID, categoryID     *int
+			price                *int
+			authorID, title      *string
+			description, place   *string
+			status               *entities.Status
+			isActive             *bool
+			createdAt, updatedAt *time.Time
+		)
+		if err = rows.Scan(&fav.AdID, &fav.Title, &fav.CreatedAt,
+			&adID, &authorID, &title, &description, &categoryID,
+			&status, &isActive, &createdAt, &updatedAt, &price, &place); err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning favorite: ", err)
+			return nil, repoerr.ErrScan
+		}
+		// the ad row is missing when the ad was deleted
+		if adID != nil {
+			fav.Ad = &entities.Ad{
+				CreatedAt:   deref(createdAt),
+				UpdatedAt:   deref(updatedAt),
+				Status:      deref(status),
+				Title:       deref(title),
+				Description: deref(description),
+				AuthorID:    deref(authorID),
+				Location:    deref(place),
+				CategoryID:  deref(categoryID),
+				Price:       deref(price),
+				ID:          *adID,
+				IsActive:    deref(isActive),
+			}
+			fav.Title = fav.Ad.Title
+			fav.Available = fav.Ad.Status == entities.StatusApproved && fav.Ad.IsActive
+		}
+		favorites = append(favorites, fav)
+	}
+	if err = rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating favorites: ", err)
+		return nil, repoerr.ErrScan
+	}
+	return favorites, nil
+}
+
+func (r *favoriteRepo) CountByAds(ctx context.Context, adIDs []int) (map[int]int, error) {
+	counts := make(map[int]int, len(adIDs))
+	if len(adIDs) == 0 {
+		return counts, nil
+	}
+	rows, err := r.db.Query(ctx, `
+		SELECT ad_id, COUNT(*)
+		FROM favorites
+		WHERE ad_id = ANY($1)
+		GROUP BY ad_id`, adIDs)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error counting favorites: ", err)
+		return nil, repoerr.ErrFavoriteSelect
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var adID, count int
+		if err = rows.Scan(&adID, &count); err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning favorite count: ", err)
+			return nil, repoerr.ErrScan
+		}
+		counts[adID] = count
+	}
+	if err = rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating favorite counts: ", err)
+		return nil, repoerr.ErrScan
+	}
+	return counts, nil
+}
+
+func (r *favoriteRepo) GetUserIDsByAd(ctx context.Context, adID int) ([]string, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT user_id
+		FROM favorites
+		WHERE ad_id = $1`, adID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error selecting favorite owners: ", err)
+		return nil, repoerr.ErrFavoriteSelect
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err = rows.Scan(&userID); err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning favorite owner: ", err)
+			return nil, repoerr.ErrScan
+		}
+		userIDs = append(userIDs, userID)
+	}
+	if err = rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating favorite owners: ", err)
+		return nil, repoerr.ErrScan
+	}
+	return userIDs, nil
+}
+
+func deref[T any](v *T) T {
+	var zero T
+	if v == nil {
+		return zero
+	}
+	return *v
+}
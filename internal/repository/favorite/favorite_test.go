@@ -0,0 +1,214 @@
+//nolint:all // testpackage
+package favorite
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/pkg/db"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var favoriteScanArgs = []interface{}{mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+	mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+	mock.Anything, mock.Anything, mock.Anything, mock.Anything}
+
+func TestFavoriteRepo_Add(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &favoriteRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+
+		err := repo.Add(context.Background(), "user-1", 1, "Car")
+		assert.NoError(t, err)
+	})
+
+	t.Run("exec error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &favoriteRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.CommandTag{}, errors.New("db error"))
+
+		err := repo.Add(context.Background(), "user-1", 1, "Car")
+		assert.Equal(t, repoerr.ErrFavoriteInsert, err)
+	})
+}
+
+func TestFavoriteRepo_Delete(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &favoriteRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("DELETE 0"), nil)
+
+		err := repo.Delete(context.Background(), "user-1", 1)
+		assert.Equal(t, repoerr.ErrFavoriteNotFound, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &favoriteRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("DELETE 1"), nil)
+
+		err := repo.Delete(context.Background(), "user-1", 1)
+		assert.NoError(t, err)
+	})
+}
+
+func TestFavoriteRepo_GetByUser(t *testing.T) {
+	t.Run("deleted ad is unavailable", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRows := new(db.MockRows)
+		defer mockPool.AssertExpectations(t)
+		defer mockRows.AssertExpectations(t)
+
+		repo := &favoriteRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+		mockRows.On("Next").Return(true).Once()
+		mockRows.On("Next").Return(false).Once()
+		mockRows.On("Scan", favoriteScanArgs...).Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 5
+			*args.Get(1).(*string) = "Old title"
+		}).Return(nil).Once()
+		mockRows.On("Err").Return(nil)
+		mockRows.On("Close").Return()
+
+		favorites, err := repo.GetByUser(context.Background(), "user-1")
+		assert.NoError(t, err)
+		assert.Len(t, favorites, 1)
+		assert.Nil(t, favorites[0].Ad)
+		assert.False(t, favorites[0].Available)
+		assert.Equal(t, "Old title", favorites[0].Title)
+	})
+
+	t.Run("published ad is available", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRows := new(db.MockRows)
+		defer mockPool.AssertExpectations(t)
+		defer mockRows.AssertExpectations(t)
+
+		repo := &favoriteRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+		mockRows.On("Next").Return(true).Once()
+		mockRows.On("Next").Return(false).Once()
+		mockRows.On("Scan", favoriteScanArgs...).Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 5
+			*args.Get(1).(*string) = "Old title"
+			id, title, active := 5, "New title", true
+			status := entities.StatusApproved
+			now := time.Now()
+			*args.Get(3).(**int) = &id
+			*args.Get(5).(**string) = &title
+			*args.Get(8).(**entities.Status) = &status
+			*args.Get(9).(**bool) = &active
+			*args.Get(10).(**time.Time) = &now
+		}).Return(nil).Once()
+		mockRows.On("Err").Return(nil)
+		mockRows.On("Close").Return()
+
+		favorites, err := repo.GetByUser(context.Background(), "user-1")
+		assert.NoError(t, err)
+		assert.True(t, favorites[0].Available)
+		assert.Equal(t, "New title", favorites[0].Title)
+		assert.Equal(t, 5, favorites[0].Ad.ID)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &favoriteRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).
+			Return(new(db.MockRows), errors.New("db error"))
+
+		favorites, err := repo.GetByUser(context.Background(), "user-1")
+		assert.Nil(t, favorites)
+		assert.Equal(t, repoerr.ErrFavoriteSelect, err)
+	})
+
+	t.Run("scan error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRows := new(db.MockRows)
+		defer mockPool.AssertExpectations(t)
+		defer mockRows.AssertExpectations(t)
+
+		repo := &favoriteRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+		mockRows.On("Next").Return(true).Once()
+		mockRows.On("Scan", favoriteScanArgs...).Return(errors.New("scan error")).Once()
+		mockRows.On("Close").Return()
+
+		favorites, err := repo.GetByUser(context.Background(), "user-1")
+		assert.Nil(t, favorites)
+		assert.Equal(t, repoerr.ErrScan, err)
+	})
+}
+
+func TestFavoriteRepo_CountByAds(t *testing.T) {
+	t.Run("no ads", func(t *testing.T) {
+		repo := &favoriteRepo{db: new(db.MockPool)}
+
+		counts, err := repo.CountByAds(context.Background(), nil)
+		assert.NoError(t, err)
+		assert.Empty(t, counts)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRows := new(db.MockRows)
+		defer mockPool.AssertExpectations(t)
+		defer mockRows.AssertExpectations(t)
+
+		repo := &favoriteRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+		mockRows.On("Next").Return(true).Once()
+		mockRows.On("Next").Return(false).Once()
+		mockRows.On("Scan", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 1
+			*args.Get(1).(*int) = 3
+		}).Return(nil).Once()
+		mockRows.On("Err").Return(nil)
+		mockRows.On("Close").Return()
+
+		counts, err := repo.CountByAds(context.Background(), []int{1, 2})
+		assert.NoError(t, err)
+		assert.Equal(t, map[int]int{1: 3}, counts)
+	})
+}
+
+func TestFavoriteRepo_GetUserIDsByAd(t *testing.T) {
+	mockPool := new(db.MockPool)
+	mockRows := new(db.MockRows)
+	defer mockPool.AssertExpectations(t)
+	defer mockRows.AssertExpectations(t)
+
+	repo := &favoriteRepo{db: mockPool}
+	mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+	mockRows.On("Next").Return(true).Once()
+	mockRows.On("Next").Return(false).Once()
+	mockRows.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*string) = "buyer"
+	}).Return(nil).Once()
+	mockRows.On("Err").Return(nil)
+	mockRows.On("Close").Return()
+
+	userIDs, err := repo.GetUserIDsByAd(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"buyer"}, userIDs)
+}
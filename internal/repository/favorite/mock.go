@@ -0,0 +1,49 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package favorite
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockFavoriteRepo struct {
+	mock.Mock
+}
+
+func (m *MockFavoriteRepo) Add(ctx context.Context, userID string, adID int, title string) error {
+	args := m.Called(ctx, userID, adID, title)
+	return args.Error(0)
+}
+
+func (m *MockFavoriteRepo) Delete(ctx context.Context, userID string, adID int) error {
+	args := m.Called(ctx, userID, adID)
+	return args.Error(0)
+}
+
+func (m *MockFavoriteRepo) GetByUser(ctx context.Context, userID string) ([]entities.Favorite, error) {
+	args := m.Called(ctx, userID)
+	if favorites, ok := args.Get(0).([]entities.Favorite); ok {
+		return favorites, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockFavoriteRepo) CountByAds(ctx context.Context, adIDs []int) (map[int]int, error) {
+	args := m.Called(ctx, adIDs)
+	if counts, ok := args.Get(0).(map[int]int); ok {
+		return counts, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockFavoriteRepo) GetUserIDsByAd(ctx context.Context, adID int) ([]string, error) {
+	args := m.Called(ctx, adID)
+	if userIDs, ok := args.Get(0).([]string); ok {
+		return userIDs, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+var _ FavoriteRepository = (*MockFavoriteRepo)(nil)
@@ -0,0 +1,27 @@
+package favorite
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/pkg/db"
+	customLogger "ads-service/pkg/logger"
+	"context"
+)
+
+type FavoriteRepository interface {
+	// Add is idempotent, adding the same ad twice keeps the first record.
+	Add(ctx context.Context, userID string, adID int, title string) error
+	Delete(ctx context.Context, userID string, adID int) error
+	GetByUser(ctx context.Context, userID string) ([]entities.Favorite, error)
+	// CountByAds returns favorite counts keyed by ad ID, ads without favorites are absent.
+	CountByAds(ctx context.Context, adIDs []int) (map[int]int, error)
+	GetUserIDsByAd(ctx context.Context, adID int) ([]string, error)
+}
+
+type favoriteRepo struct {
+	db     db.Pool
+	logger customLogger.Logger
+}
+
+func NewFavoriteRepo(pool db.Pool, logTool customLogger.Logger) FavoriteRepository {
+	return &favoriteRepo{db: pool, logger: logTool}
+}
@@ -0,0 +1,127 @@
+package fingerprint
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const selectFingerprint = `
+		SELECT f.ad_id, a.author_id, a.title, a.status, f.content_hash, f.text_hash, f.image_hashes, f.submitted_at
+		FROM ad_fingerprints f
+		JOIN ads a ON a.id = f.ad_id`
+
+func (r *fingerprintRepo) Save(ctx context.Context, fp *entities.Fingerprint) error {
+	hashes := toBigint(append([]uint64{fp.TextHash}, fp.ImageHashes...))
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO ad_fingerprints (ad_id, content_hash, text_hash, image_hashes)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (ad_id) DO UPDATE
+		SET content_hash = EXCLUDED.content_hash, text_hash = EXCLUDED.text_hash,
+			image_hashes = EXCLUDED.image_hashes, submitted_at = CURRENT_TIMESTAMP
+		RETURNING submitted_at;`,
+		fp.AdID, fp.ContentHash, hashes[0], hashes[1:]).Scan(&fp.SubmittedAt)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error saving fingerprint: ", err)
+		return repoerr.ErrFingerprintSave
+	}
+	return nil
+}
+
+func (r *fingerprintRepo) GetByAd(ctx context.Context, adID int) (*entities.Fingerprint, error) {
+	fp, err := scanFingerprint(r.db.QueryRow(ctx, selectFingerprint+`
+		WHERE f.ad_id = $1;`, adID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repoerr.ErrFingerprintNotFound
+		}
+		r.logger.ErrorContext(ctx, "Error selecting fingerprint: ", err)
+		return nil, repoerr.ErrFingerprintSelect
+	}
+	return fp, nil
+}
+
+func (r *fingerprintRepo) FindRepost(ctx context.Context, fp *entities.Fingerprint, since time.Time) (int, error) {
+	// idx_ad_fingerprints_content narrows the lookup to the copies of the text
+	var adID int
+	err := r.db.QueryRow(ctx, `
+		SELECT f.ad_id
+		FROM ad_fingerprints f
+		JOIN ads a ON a.id = f.ad_id
+		WHERE f.content_hash = $1 AND a.author_id = $2 AND f.ad_id <> $3 AND a.status <> 'rejected'
+			AND f.submitted_at > $4
+		LIMIT 1;`, fp.ContentHash, fp.AuthorID, fp.AdID, since).Scan(&adID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil
+		}
+		r.logger.ErrorContext(ctx, "Error selecting repost: ", err)
+		return 0, repoerr.ErrFingerprintSelect
+	}
+	return adID, nil
+}
+
+func (r *fingerprintRepo) ListCandidates(ctx context.Context, adID int, authorID, contentHash string,
+	limit int) ([]entities.Fingerprint, error) {
+	rows, err := r.db.Query(ctx, selectFingerprint+`
+		WHERE f.ad_id <> $1 AND (a.author_id = $2 OR (a.status = 'approved' AND a.is_active))
+		ORDER BY f.content_hash = $3 DESC, f.submitted_at DESC
+		LIMIT $4;`, adID, authorID, contentHash, limit)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error selecting fingerprints: ", err)
+		return nil, repoerr.ErrFingerprintSelect
+	}
+	defer rows.Close()
+
+	var fps []entities.Fingerprint
+	for rows.Next() {
+		fp, err := scanFingerprint(rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning fingerprint: ", err)
+			return nil, repoerr.ErrScan
+		}
+		fps = append(fps, *fp)
+	}
+	if err = rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating fingerprints: ", err)
+		return nil, repoerr.ErrScan
+	}
+	return fps, nil
+}
+
+func scanFingerprint(row pgx.Row) (*entities.Fingerprint, error) {
+	var (
+		fp       entities.Fingerprint
+		textHash int64
+		images   []int64
+	)
+	err := row.Scan(&fp.AdID, &fp.AuthorID, &fp.AdTitle, &fp.AdStatus, &fp.ContentHash, &textHash, &images,
+		&fp.SubmittedAt)
+	if err != nil {
+		return nil, err
+	}
+	hashes := fromBigint(append([]int64{textHash}, images...))
+	fp.TextHash, fp.ImageHashes = hashes[0], hashes[1:]
+	return &fp, nil
+}
+
+// PostgreSQL has no unsigned BIGINT, the hash bits are stored as is.
+func toBigint(hashes []uint64) []int64 {
+	values := make([]int64, len(hashes))
+	for i, h := range hashes {
+		values[i] = int64(h) //nolint:gosec // bits, not a quantity
+	}
+	return values
+}
+
+func fromBigint(values []int64) []uint64 {
+	hashes := make([]uint64, len(values))
+	for i, v := range values {
+		hashes[i] = uint64(v) //nolint:gosec // bits, not a quantity
+	}
+	return hashes
+}
@@ -0,0 +1,165 @@
+//nolint:all // testpackage
+package fingerprint
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/pkg/db"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var fingerprintScanArgs = []interface{}{mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+	mock.Anything, mock.Anything, mock.Anything, mock.Anything}
+
+func TestFingerprintRepo_Save(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		now := time.Now()
+		repo := &fingerprintRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(0).(*time.Time) = now
+		}).Return(nil)
+
+		fp := &entities.Fingerprint{AdID: 7, ContentHash: "abc", TextHash: ^uint64(0), ImageHashes: []uint64{1}}
+		assert.NoError(t, repo.Save(context.Background(), fp))
+		assert.Equal(t, now, fp.SubmittedAt)
+	})
+
+	t.Run("insert error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &fingerprintRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything).Return(errors.New("db error"))
+
+		err := repo.Save(context.Background(), &entities.Fingerprint{AdID: 7})
+		assert.Equal(t, repoerr.ErrFingerprintSave, err)
+	})
+}
+
+func TestFingerprintRepo_GetByAd(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &fingerprintRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", fingerprintScanArgs...).Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 7
+			*args.Get(5).(*int64) = -1
+			*args.Get(6).(*[]int64) = []int64{-2, 3}
+		}).Return(nil)
+
+		fp, err := repo.GetByAd(context.Background(), 7)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, fp.AdID)
+		assert.Equal(t, ^uint64(0), fp.TextHash)
+		assert.Equal(t, []uint64{^uint64(1), 3}, fp.ImageHashes)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &fingerprintRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", fingerprintScanArgs...).Return(pgx.ErrNoRows)
+
+		fp, err := repo.GetByAd(context.Background(), 7)
+		assert.Nil(t, fp)
+		assert.Equal(t, repoerr.ErrFingerprintNotFound, err)
+	})
+}
+
+func TestFingerprintRepo_FindRepost(t *testing.T) {
+	fp := &entities.Fingerprint{AdID: 7, AuthorID: "author", ContentHash: "hash"}
+
+	t.Run("found", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &fingerprintRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 3
+		}).Return(nil)
+
+		adID, err := repo.FindRepost(context.Background(), fp, time.Now().Add(-time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, adID)
+	})
+
+	t.Run("none", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &fingerprintRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything).Return(pgx.ErrNoRows)
+
+		adID, err := repo.FindRepost(context.Background(), fp, time.Now().Add(-time.Hour))
+		assert.NoError(t, err)
+		assert.Zero(t, adID)
+	})
+}
+
+func TestFingerprintRepo_ListCandidates(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRows := new(db.MockRows)
+		defer mockPool.AssertExpectations(t)
+		defer mockRows.AssertExpectations(t)
+
+		repo := &fingerprintRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+		mockRows.On("Next").Return(true).Once()
+		mockRows.On("Next").Return(false).Once()
+		mockRows.On("Scan", fingerprintScanArgs...).Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 8
+		}).Return(nil).Once()
+		mockRows.On("Err").Return(nil)
+		mockRows.On("Close").Return()
+
+		fps, err := repo.ListCandidates(context.Background(), 7, "author", "hash", 100)
+		assert.NoError(t, err)
+		assert.Len(t, fps, 1)
+		assert.Equal(t, 8, fps[0].AdID)
+		assert.Empty(t, fps[0].ImageHashes)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &fingerprintRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).
+			Return(new(db.MockRows), errors.New("db error"))
+
+		fps, err := repo.ListCandidates(context.Background(), 7, "author", "hash", 100)
+		assert.Nil(t, fps)
+		assert.Equal(t, repoerr.ErrFingerprintSelect, err)
+	})
+}
@@ -0,0 +1,44 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package fingerprint
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockFingerprintRepo struct {
+	mock.Mock
+}
+
+func (m *MockFingerprintRepo) Save(ctx context.Context, fp *entities.Fingerprint) error {
+	args := m.Called(ctx, fp)
+	return args.Error(0)
+}
+
+func (m *MockFingerprintRepo) GetByAd(ctx context.Context, adID int) (*entities.Fingerprint, error) {
+	args := m.Called(ctx, adID)
+	if fp, ok := args.Get(0).(*entities.Fingerprint); ok {
+		return fp, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockFingerprintRepo) FindRepost(ctx context.Context, fp *entities.Fingerprint,
+	since time.Time) (int, error) {
+	args := m.Called(ctx, fp, since)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockFingerprintRepo) ListCandidates(ctx context.Context, adID int, authorID, contentHash string,
+	limit int) ([]entities.Fingerprint, error) {
+	args := m.Called(ctx, adID, authorID, contentHash, limit)
+	if fps, ok := args.Get(0).([]entities.Fingerprint); ok {
+		return fps, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+var _ FingerprintRepository = (*MockFingerprintRepo)(nil)
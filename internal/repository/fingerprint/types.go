@@ -0,0 +1,31 @@
+package fingerprint
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/pkg/db"
+	customLogger "ads-service/pkg/logger"
+	"context"
+	"time"
+)
+
+type FingerprintRepository interface {
+	// Save replaces the fingerprint of the ad, SubmittedAt becomes the current time.
+	Save(ctx context.Context, fp *entities.Fingerprint) error
+	GetByAd(ctx context.Context, adID int) (*entities.Fingerprint, error)
+	// FindRepost returns the ID of another ad of the author with the same content hash submitted after since
+	// and not rejected, 0 when there is none.
+	FindRepost(ctx context.Context, fp *entities.Fingerprint, since time.Time) (int, error)
+	// ListCandidates returns up to limit fingerprints of the other ads of the author and of active ads of other
+	// users, exact copies of contentHash first and then the most recently submitted.
+	ListCandidates(ctx context.Context, adID int, authorID, contentHash string,
+		limit int) ([]entities.Fingerprint, error)
+}
+
+type fingerprintRepo struct {
+	db     db.Pool
+	logger customLogger.Logger
+}
+
+func NewFingerprintRepo(pool db.Pool, logTool customLogger.Logger) FingerprintRepository {
+	return &fingerprintRepo{db: pool, logger: logTool}
+}
@@ -0,0 +1,75 @@
+package history
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/pkg/db"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (r *historyRepo) Record(ctx context.Context, event *entities.ModerationEvent) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO moderation_events(ad_id, action, actor_id, rejection_code, note)
+		VALUES($1, $2, NULLIF($3, '')::uuid, NULLIF($4, ''), $5)
+		RETURNING id, created_at;`,
+		event.AdID, event.Action, event.ActorID, event.RejectionCode, event.Note).
+		Scan(&event.ID, &event.CreatedAt)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error inserting moderation event: ", err)
+		return repoerr.ErrHistoryInsert
+	}
+	return nil
+}
+
+func (r *historyRepo) GetByAd(ctx context.Context, adID int) ([]entities.ModerationEvent, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, ad_id, action, COALESCE(actor_id::text, ''), COALESCE(rejection_code, ''), note, created_at
+		FROM moderation_events
+		WHERE ad_id = $1
+		ORDER BY created_at, id;`, adID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error selecting moderation history: ", err)
+		return nil, repoerr.ErrHistorySelect
+	}
+	defer rows.Close()
+
+	var events []entities.ModerationEvent
+	for rows.Next() {
+		var event entities.ModerationEvent
+		if err = rows.Scan(&event.ID, &event.AdID, &event.Action, &event.ActorID, &event.RejectionCode, &event.Note,
+			&event.CreatedAt); err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning moderation event: ", err)
+			return nil, repoerr.ErrScan
+		}
+		events = append(events, event)
+	}
+	if err = rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating moderation history: ", err)
+		return nil, repoerr.ErrScan
+	}
+	return events, nil
+}
+
+func (r *historyRepo) StreamDecisions(ctx context.Context, filter entities.StatisticsFilter,
+	fn func(*entities.ModerationEvent) error) error {
+	err := db.Stream(ctx, r.db, db.DefaultFetchSize, func(rows pgx.Rows) error {
+		var event entities.ModerationEvent
+		if err := rows.Scan(&event.ID, &event.AdID, &event.Action, &event.ActorID, &event.RejectionCode,
+			&event.Note, &event.CreatedAt); err != nil {
+			return err
+		}
+		return fn(&event)
+	}, `
+		SELECT id, ad_id, action, COALESCE(actor_id::text, ''), COALESCE(rejection_code, ''), note, created_at
+		FROM moderation_events
+		WHERE action IN ('approved', 'rejected', 'appeal_upheld', 'appeal_overturned')
+			AND created_at >= $1 AND created_at < $2
+		ORDER BY created_at, id`, filter.From, filter.To)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error streaming moderation decisions: ", err)
+		return repoerr.ErrHistorySelect
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+//nolint:all // testpackage
+package history
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/pkg/db"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHistoryRepo_Record(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &historyRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 5
+		}).Return(nil)
+
+		event := &entities.ModerationEvent{AdID: 1, Action: entities.ActionRejected, RejectionCode: "duplicate"}
+		assert.NoError(t, repo.Record(context.Background(), event))
+		assert.Equal(t, 5, event.ID)
+	})
+
+	t.Run("insert error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &historyRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+		err := repo.Record(context.Background(), &entities.ModerationEvent{AdID: 1})
+		assert.Equal(t, repoerr.ErrHistoryInsert, err)
+	})
+}
+
+func TestHistoryRepo_GetByAd(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRows := new(db.MockRows)
+		defer mockPool.AssertExpectations(t)
+		defer mockRows.AssertExpectations(t)
+
+		repo := &historyRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+		mockRows.On("Next").Return(true).Once()
+		mockRows.On("Next").Return(false).Once()
+		mockRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(2).(*entities.ModerationAction) = entities.ActionAppealed
+			*args.Get(3).(*string) = "author"
+		}).Return(nil).Once()
+		mockRows.On("Err").Return(nil)
+		mockRows.On("Close").Return()
+
+		events, err := repo.GetByAd(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, []entities.ModerationEvent{{Action: entities.ActionAppealed, ActorID: "author"}}, events)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &historyRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).
+			Return(new(db.MockRows), errors.New("db error"))
+
+		events, err := repo.GetByAd(context.Background(), 1)
+		assert.Nil(t, events)
+		assert.Equal(t, repoerr.ErrHistorySelect, err)
+	})
+}
+
+func TestHistoryRepo_StreamDecisions(t *testing.T) {
+	filter := entities.StatisticsFilter{From: time.Now().Add(-time.Hour), To: time.Now()}
+
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockTx := new(db.MockTx)
+		mockRows := new(db.MockRows)
+		defer mockTx.AssertExpectations(t)
+		defer mockRows.AssertExpectations(t)
+
+		repo := &historyRepo{db: mockPool}
+		mockPool.On("Begin", mock.Anything).Return(mockTx, nil)
+		mockTx.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(pgconn.CommandTag{}, nil)
+		mockTx.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+		mockTx.On("Rollback", mock.Anything).Return(nil)
+		mockRows.On("Next").Return(true).Once()
+		mockRows.On("Next").Return(false).Once()
+		mockRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(2).(*entities.ModerationAction) = entities.ActionApproved
+		}).Return(nil).Once()
+		mockRows.On("Err").Return(nil)
+		mockRows.On("Close").Return()
+
+		var actions []entities.ModerationAction
+		err := repo.StreamDecisions(context.Background(), filter, func(event *entities.ModerationEvent) error {
+			actions = append(actions, event.Action)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []entities.ModerationAction{entities.ActionApproved}, actions)
+	})
+
+	t.Run("callback error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockTx := new(db.MockTx)
+		mockRows := new(db.MockRows)
+
+		repo := &historyRepo{db: mockPool}
+		mockPool.On("Begin", mock.Anything).Return(mockTx, nil)
+		mockTx.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(pgconn.CommandTag{}, nil)
+		mockTx.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+		mockTx.On("Rollback", mock.Anything).Return(nil)
+		mockRows.On("Next").Return(true).Once()
+		mockRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything).Return(nil).Once()
+		mockRows.On("Close").Return()
+
+		err := repo.StreamDecisions(context.Background(), filter, func(*entities.ModerationEvent) error {
+			return errors.New("client gone")
+		})
+		assert.Equal(t, repoerr.ErrHistorySelect, err)
+	})
+}
@@ -0,0 +1,34 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package history
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockHistoryRepo struct {
+	mock.Mock
+}
+
+func (m *MockHistoryRepo) Record(ctx context.Context, event *entities.ModerationEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockHistoryRepo) GetByAd(ctx context.Context, adID int) ([]entities.ModerationEvent, error) {
+	args := m.Called(ctx, adID)
+	if events, ok := args.Get(0).([]entities.ModerationEvent); ok {
+		return events, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockHistoryRepo) StreamDecisions(ctx context.Context, filter entities.StatisticsFilter,
+	fn func(*entities.ModerationEvent) error) error {
+	args := m.Called(ctx, filter, fn)
+	return args.Error(0)
+}
+
+var _ HistoryRepository = (*MockHistoryRepo)(nil)
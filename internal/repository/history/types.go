@@ -0,0 +1,26 @@
+package history
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/pkg/db"
+	customLogger "ads-service/pkg/logger"
+	"context"
+)
+
+type HistoryRepository interface {
+	Record(ctx context.Context, event *entities.ModerationEvent) error
+	// GetByAd returns the moderation history of the ad, oldest first.
+	GetByAd(ctx context.Context, adID int) ([]entities.ModerationEvent, error)
+	// StreamDecisions calls fn for every approval, rejection and appeal decision of the period, oldest first.
+	StreamDecisions(ctx context.Context, filter entities.StatisticsFilter,
+		fn func(*entities.ModerationEvent) error) error
+}
+
+type historyRepo struct {
+	db     db.Pool
+	logger customLogger.Logger
+}
+
+func NewHistoryRepo(pool db.Pool, logTool customLogger.Logger) HistoryRepository {
+	return &historyRepo{db: pool, logger: logTool}
+}
@@ -0,0 +1,88 @@
+package idempotency
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (r *idempotencyRepo) Reserve(ctx context.Context, req *entities.IdempotentRequest,
+	pendingBefore time.Time) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		INSERT INTO idempotency_keys (user_id, idempotency_key, fingerprint, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, idempotency_key) DO UPDATE
+		SET fingerprint = EXCLUDED.fingerprint, status = NULL, content_type = '', body = NULL, headers = '{}',
+			created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+			OR (idempotency_keys.status IS NULL AND idempotency_keys.created_at < $6);`,
+		req.UserID, req.Key, req.Fingerprint, req.CreatedAt, req.ExpiresAt, pendingBefore)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error saving idempotency key: ", err)
+		return false, repoerr.ErrIdempotencyKeySave
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *idempotencyRepo) Get(ctx context.Context, userID, key string) (*entities.IdempotentRequest, error) {
+	req := entities.IdempotentRequest{UserID: userID, Key: key}
+	var status *int
+	err := r.db.QueryRow(ctx, `
+		SELECT fingerprint, status, content_type, body, headers, created_at, expires_at
+		FROM idempotency_keys
+		WHERE user_id = $1 AND idempotency_key = $2`, userID, key).
+		Scan(&req.Fingerprint, &status, &req.ContentType, &req.Body, &req.Headers, &req.CreatedAt, &req.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repoerr.ErrIdempotencyKeyNotFound
+		}
+		r.logger.ErrorContext(ctx, "Error selecting idempotency key: ", err)
+		return nil, repoerr.ErrIdempotencyKeySelect
+	}
+	if status != nil {
+		req.Status = *status
+	}
+	return &req, nil
+}
+
+func (r *idempotencyRepo) Complete(ctx context.Context, req *entities.IdempotentRequest) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE idempotency_keys
+		SET status = $1, content_type = $2, body = $3, headers = $4
+		WHERE user_id = $5 AND idempotency_key = $6 AND fingerprint = $7;`,
+		req.Status, req.ContentType, req.Body, req.Headers, req.UserID, req.Key, req.Fingerprint)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error saving idempotent response: ", err)
+		return repoerr.ErrIdempotencyKeySave
+	}
+	if tag.RowsAffected() == 0 {
+		return repoerr.ErrIdempotencyKeyNotFound
+	}
+	return nil
+}
+
+func (r *idempotencyRepo) Delete(ctx context.Context, userID, key string) error {
+	_, err := r.db.Exec(ctx, `
+		DELETE FROM idempotency_keys
+		WHERE user_id = $1 AND idempotency_key = $2 AND status IS NULL;`, userID, key)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error deleting idempotency key: ", err)
+		return repoerr.ErrIdempotencyKeyDelete
+	}
+	return nil
+}
+
+func (r *idempotencyRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `
+		DELETE FROM idempotency_keys
+		WHERE expires_at <= $1;`, now)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error deleting expired idempotency keys: ", err)
+		return 0, repoerr.ErrIdempotencyKeyDelete
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,145 @@
+//nolint:all // testpackage
+package idempotency
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/pkg/db"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIdempotencyRepo_Reserve(t *testing.T) {
+	req := &entities.IdempotentRequest{UserID: "u1", Key: "k1", Fingerprint: "f1"}
+
+	t.Run("reserved", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &idempotencyRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+
+		ok, err := repo.Reserve(context.Background(), req, time.Now())
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("taken", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &idempotencyRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("INSERT 0 0"), nil)
+
+		ok, err := repo.Reserve(context.Background(), req, time.Now())
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("exec error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &idempotencyRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.CommandTag{}, errors.New("db error"))
+
+		_, err := repo.Reserve(context.Background(), req, time.Now())
+		assert.Equal(t, repoerr.ErrIdempotencyKeySave, err)
+	})
+}
+
+func TestIdempotencyRepo_Get(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &idempotencyRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything).Return(pgx.ErrNoRows)
+
+		req, err := repo.Get(context.Background(), "u1", "k1")
+		assert.Nil(t, req)
+		assert.Equal(t, repoerr.ErrIdempotencyKeyNotFound, err)
+	})
+
+	t.Run("select error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &idempotencyRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+		_, err := repo.Get(context.Background(), "u1", "k1")
+		assert.Equal(t, repoerr.ErrIdempotencyKeySelect, err)
+	})
+
+	t.Run("running request", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &idempotencyRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything).Return(nil)
+
+		req, err := repo.Get(context.Background(), "u1", "k1")
+		assert.NoError(t, err)
+		assert.Equal(t, "k1", req.Key)
+		assert.False(t, req.Done())
+	})
+}
+
+func TestIdempotencyRepo_Complete(t *testing.T) {
+	req := &entities.IdempotentRequest{UserID: "u1", Key: "k1", Fingerprint: "f1", Status: 201}
+
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &idempotencyRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+
+		assert.NoError(t, repo.Complete(context.Background(), req))
+	})
+
+	t.Run("taken over", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &idempotencyRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("UPDATE 0"), nil)
+
+		assert.Equal(t, repoerr.ErrIdempotencyKeyNotFound, repo.Complete(context.Background(), req))
+	})
+}
+
+func TestIdempotencyRepo_DeleteExpired(t *testing.T) {
+	mockPool := new(db.MockPool)
+	defer mockPool.AssertExpectations(t)
+
+	repo := &idempotencyRepo{db: mockPool}
+	mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+		Return(pgconn.NewCommandTag("DELETE 3"), nil)
+
+	deleted, err := repo.DeleteExpired(context.Background(), time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+}
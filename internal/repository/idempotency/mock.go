@@ -0,0 +1,45 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package idempotency
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIdempotencyRepo struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyRepo) Reserve(ctx context.Context, req *entities.IdempotentRequest,
+	pendingBefore time.Time) (bool, error) {
+	args := m.Called(ctx, req, pendingBefore)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockIdempotencyRepo) Get(ctx context.Context, userID, key string) (*entities.IdempotentRequest, error) {
+	args := m.Called(ctx, userID, key)
+	if req, ok := args.Get(0).(*entities.IdempotentRequest); ok {
+		return req, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockIdempotencyRepo) Complete(ctx context.Context, req *entities.IdempotentRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepo) Delete(ctx context.Context, userID, key string) error {
+	args := m.Called(ctx, userID, key)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+var _ IdempotencyRepository = (*MockIdempotencyRepo)(nil)
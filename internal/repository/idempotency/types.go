@@ -0,0 +1,29 @@
+package idempotency
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/pkg/db"
+	customLogger "ads-service/pkg/logger"
+	"context"
+	"time"
+)
+
+type IdempotencyRepository interface {
+	// Reserve stores the request as running unless the key is taken. An expired key, or one left running
+	// since before pendingBefore, is taken over. It reports whether the key was reserved.
+	Reserve(ctx context.Context, req *entities.IdempotentRequest, pendingBefore time.Time) (bool, error)
+	Get(ctx context.Context, userID, key string) (*entities.IdempotentRequest, error)
+	// Complete stores the response of the running request.
+	Complete(ctx context.Context, req *entities.IdempotentRequest) error
+	Delete(ctx context.Context, userID, key string) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type idempotencyRepo struct {
+	db     db.Pool
+	logger customLogger.Logger
+}
+
+func NewIdempotencyRepo(pool db.Pool, logTool customLogger.Logger) IdempotencyRepository {
+	return &idempotencyRepo{db: pool, logger: logTool}
+}
@@ -0,0 +1,114 @@
+package lockout
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (r *lockoutRepo) Reserve(ctx context.Context, kind entities.LockoutKind, key string,
+	now, resetBefore time.Time) (*entities.LoginAttempt, error) {
+	attempt := entities.LoginAttempt{Kind: kind, Key: key}
+	var lockedUntil *time.Time
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO login_attempts(kind, attempt_key, failures, last_failure_at)
+		VALUES($1, $2, 1, $3)
+		ON CONFLICT (kind, attempt_key) DO UPDATE
+		SET failures = CASE
+				WHEN login_attempts.last_failure_at < $4 THEN 1
+				ELSE login_attempts.failures + 1
+			END,
+			last_failure_at = EXCLUDED.last_failure_at
+		WHERE login_attempts.locked_until IS NULL OR login_attempts.locked_until <= $3
+		RETURNING failures, locked_until, last_failure_at;`, kind, key, now, resetBefore).
+		Scan(&attempt.Failures, &lockedUntil, &attempt.LastFailureAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repoerr.ErrLockedOut
+		}
+		r.logger.ErrorContext(ctx, "Error saving login attempt: ", err)
+		return nil, repoerr.ErrLoginAttemptSave
+	}
+	if lockedUntil != nil {
+		attempt.LockedUntil = *lockedUntil
+	}
+	return &attempt, nil
+}
+
+func (r *lockoutRepo) Release(ctx context.Context, kind entities.LockoutKind, key string,
+	lockedUntil time.Time) error {
+	var until *time.Time
+	if !lockedUntil.IsZero() {
+		until = &lockedUntil
+	}
+	_, err := r.db.Exec(ctx, `
+		UPDATE login_attempts
+		SET failures = GREATEST(failures - 1, 0),
+			locked_until = CASE WHEN locked_until = $3 THEN NULL ELSE locked_until END
+		WHERE kind = $1 AND attempt_key = $2;`, kind, key, until)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error releasing login attempt: ", err)
+		return repoerr.ErrLoginAttemptSave
+	}
+	return nil
+}
+
+func (r *lockoutRepo) Lock(ctx context.Context, kind entities.LockoutKind, key string, until time.Time) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE login_attempts
+		SET locked_until = GREATEST(COALESCE(locked_until, $3), $3)
+		WHERE kind = $1 AND attempt_key = $2;`, kind, key, until)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error locking login attempts: ", err)
+		return repoerr.ErrLoginAttemptSave
+	}
+	return nil
+}
+
+func (r *lockoutRepo) Delete(ctx context.Context, kind entities.LockoutKind, key string) error {
+	tag, err := r.db.Exec(ctx, `
+		DELETE FROM login_attempts
+		WHERE kind = $1 AND attempt_key = $2;`, kind, key)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error deleting login attempt: ", err)
+		return repoerr.ErrLoginAttemptDelete
+	}
+	if tag.RowsAffected() == 0 {
+		return repoerr.ErrLockoutNotFound
+	}
+	r.logger.InfoContext(ctx, "Login attempts cleared for ", kind, ": ", key)
+	return nil
+}
+
+func (r *lockoutRepo) GetLocked(ctx context.Context, now time.Time) ([]entities.LoginAttempt, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT kind, attempt_key, failures, locked_until, last_failure_at
+		FROM login_attempts
+		WHERE locked_until > $1
+		ORDER BY locked_until DESC`, now)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error selecting lockouts: ", err)
+		return nil, repoerr.ErrLoginAttemptSelect
+	}
+	defer rows.Close()
+
+	var attempts []entities.LoginAttempt
+	for rows.Next() {
+		var attempt entities.LoginAttempt
+		if err = rows.Scan(&attempt.Kind, &attempt.Key, &attempt.Failures,
+			&attempt.LockedUntil, &attempt.LastFailureAt); err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning lockout: ", err)
+			return nil, repoerr.ErrScan
+		}
+		attempts = append(attempts, attempt)
+	}
+	if err = rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating lockouts: ", err)
+		return nil, repoerr.ErrScan
+	}
+	return attempts, nil
+}
@@ -0,0 +1,192 @@
+//nolint:all // testpackage
+package lockout
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/pkg/db"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLockoutRepo_Reserve(t *testing.T) {
+	t.Run("query error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &lockoutRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+		now := time.Now()
+		_, err := repo.Reserve(context.Background(), entities.LockoutByPhone, "+79999999999",
+			now, now.Add(-time.Minute))
+		assert.Equal(t, repoerr.ErrLoginAttemptSave, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &lockoutRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		now := time.Now()
+		attempt, err := repo.Reserve(context.Background(), entities.LockoutByPhone, "+79999999999",
+			now, now.Add(-time.Minute))
+		assert.NoError(t, err)
+		assert.Equal(t, "+79999999999", attempt.Key)
+	})
+
+	t.Run("locked", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &lockoutRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything).Return(pgx.ErrNoRows)
+
+		now := time.Now()
+		attempt, err := repo.Reserve(context.Background(), entities.LockoutByIP, "10.0.0.1", now, now)
+		assert.Nil(t, attempt)
+		assert.Equal(t, repoerr.ErrLockedOut, err)
+	})
+}
+
+func TestLockoutRepo_Release(t *testing.T) {
+	t.Run("without a lock", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &lockoutRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything,
+			[]interface{}{entities.LockoutByIP, "10.0.0.1", (*time.Time)(nil)}).Return(pgconn.CommandTag{}, nil)
+
+		assert.NoError(t, repo.Release(context.Background(), entities.LockoutByIP, "10.0.0.1", time.Time{}))
+	})
+
+	t.Run("exec error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &lockoutRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.CommandTag{}, errors.New("db error"))
+
+		err := repo.Release(context.Background(), entities.LockoutByIP, "10.0.0.1", time.Now())
+		assert.Equal(t, repoerr.ErrLoginAttemptSave, err)
+	})
+}
+
+func TestLockoutRepo_Lock(t *testing.T) {
+	mockPool := new(db.MockPool)
+	defer mockPool.AssertExpectations(t)
+
+	repo := &lockoutRepo{db: mockPool}
+	mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+		Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+
+	assert.NoError(t, repo.Lock(context.Background(), entities.LockoutByIP, "10.0.0.1", time.Now()))
+}
+
+func TestLockoutRepo_Delete(t *testing.T) {
+	t.Run("exec error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &lockoutRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.CommandTag{}, errors.New("db error"))
+
+		err := repo.Delete(context.Background(), entities.LockoutByIP, "10.0.0.1")
+		assert.Equal(t, repoerr.ErrLoginAttemptDelete, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &lockoutRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("DELETE 0"), nil)
+
+		err := repo.Delete(context.Background(), entities.LockoutByIP, "10.0.0.1")
+		assert.Equal(t, repoerr.ErrLockoutNotFound, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &lockoutRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("DELETE 1"), nil)
+
+		err := repo.Delete(context.Background(), entities.LockoutByIP, "10.0.0.1")
+		assert.NoError(t, err)
+	})
+}
+
+func TestLockoutRepo_GetLocked(t *testing.T) {
+	t.Run("query error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &lockoutRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).
+			Return(new(db.MockRows), errors.New("db error"))
+
+		attempts, err := repo.GetLocked(context.Background(), time.Now())
+		assert.Nil(t, attempts)
+		assert.Equal(t, repoerr.ErrLoginAttemptSelect, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRows := new(db.MockRows)
+		defer mockPool.AssertExpectations(t)
+		defer mockRows.AssertExpectations(t)
+
+		repo := &lockoutRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+		mockRows.On("Next").Return(true).Once()
+		mockRows.On("Next").Return(false).Once()
+		mockRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil).Once()
+		mockRows.On("Err").Return(nil)
+		mockRows.On("Close").Return()
+
+		attempts, err := repo.GetLocked(context.Background(), time.Now())
+		assert.NoError(t, err)
+		assert.Len(t, attempts, 1)
+	})
+
+	t.Run("scan error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRows := new(db.MockRows)
+		defer mockPool.AssertExpectations(t)
+		defer mockRows.AssertExpectations(t)
+
+		repo := &lockoutRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+		mockRows.On("Next").Return(true).Once()
+		mockRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(errors.New("scan error")).Once()
+		mockRows.On("Close").Return()
+
+		attempts, err := repo.GetLocked(context.Background(), time.Now())
+		assert.Nil(t, attempts)
+		assert.Equal(t, repoerr.ErrScan, err)
+	})
+}
@@ -0,0 +1,49 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package lockout
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLockoutRepo struct {
+	mock.Mock
+}
+
+func (m *MockLockoutRepo) Reserve(ctx context.Context, kind entities.LockoutKind, key string,
+	now, resetBefore time.Time) (*entities.LoginAttempt, error) {
+	args := m.Called(ctx, kind, key, now, resetBefore)
+	if attempt, ok := args.Get(0).(*entities.LoginAttempt); ok {
+		return attempt, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockLockoutRepo) Release(ctx context.Context, kind entities.LockoutKind, key string,
+	lockedUntil time.Time) error {
+	args := m.Called(ctx, kind, key, lockedUntil)
+	return args.Error(0)
+}
+
+func (m *MockLockoutRepo) Lock(ctx context.Context, kind entities.LockoutKind, key string, until time.Time) error {
+	args := m.Called(ctx, kind, key, until)
+	return args.Error(0)
+}
+
+func (m *MockLockoutRepo) Delete(ctx context.Context, kind entities.LockoutKind, key string) error {
+	args := m.Called(ctx, kind, key)
+	return args.Error(0)
+}
+
+func (m *MockLockoutRepo) GetLocked(ctx context.Context, now time.Time) ([]entities.LoginAttempt, error) {
+	args := m.Called(ctx, now)
+	if attempts, ok := args.Get(0).([]entities.LoginAttempt); ok {
+		return attempts, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+var _ LockoutRepository = (*MockLockoutRepo)(nil)
@@ -0,0 +1,33 @@
+package lockout
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/pkg/db"
+	customLogger "ads-service/pkg/logger"
+	"context"
+	"time"
+)
+
+type LockoutRepository interface {
+	// Reserve counts an attempt for the key before its credentials are checked and returns the counter, the
+	// attempt stays counted as a failure unless it is released. The upsert locks the row, so concurrent attempts
+	// are counted one after another. A locked key is not counted and gives ErrLockedOut. The count starts over
+	// when the previous failure is older than resetBefore.
+	Reserve(ctx context.Context, kind entities.LockoutKind, key string,
+		now, resetBefore time.Time) (*entities.LoginAttempt, error)
+	// Release takes back a reserved attempt that turned out well, with the lock until lockedUntil it set.
+	Release(ctx context.Context, kind entities.LockoutKind, key string, lockedUntil time.Time) error
+	// Lock locks the key until the given time, a longer lock already set is kept.
+	Lock(ctx context.Context, kind entities.LockoutKind, key string, until time.Time) error
+	Delete(ctx context.Context, kind entities.LockoutKind, key string) error
+	GetLocked(ctx context.Context, now time.Time) ([]entities.LoginAttempt, error)
+}
+
+type lockoutRepo struct {
+	db     db.Pool
+	logger customLogger.Logger
+}
+
+func NewLockoutRepo(pool db.Pool, logTool customLogger.Logger) LockoutRepository {
+	return &lockoutRepo{db: pool, logger: logTool}
+}
@@ -0,0 +1,218 @@
+package message
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const selectConversation = `
+	SELECT c.id, c.ad_id, c.ad_title, c.buyer_id, c.seller_id, c.created_at, c.updated_at,
+		EXISTS (SELECT 1 FROM user_blocks b WHERE b.blocker_id = c.seller_id AND b.blocked_id = c.buyer_id)`
+
+func (r *messageRepo) GetOrCreateConversation(ctx context.Context, conv *entities.Conversation) (int, error) {
+	var id int
+	// DO UPDATE instead of DO NOTHING so that RETURNING also yields the existing row
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO conversations(ad_id, ad_title, buyer_id, seller_id)
+		VALUES($1, $2, $3, $4)
+		ON CONFLICT (ad_id, buyer_id) DO UPDATE SET ad_title = EXCLUDED.ad_title
+		RETURNING id`, conv.AdID, conv.AdTitle, conv.BuyerID, conv.SellerID).Scan(&id)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error inserting conversation: ", err)
+		return 0, repoerr.ErrConversationInsert
+	}
+	return id, nil
+}
+
+func (r *messageRepo) GetConversation(ctx context.Context, id int) (*entities.Conversation, error) {
+	conv, err := scanConversation(r.db.QueryRow(ctx, selectConversation+`
+		FROM conversations c
+		WHERE c.id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repoerr.ErrConversationNotFound
+		}
+		r.logger.ErrorContext(ctx, "Error selecting conversation: ", err)
+		return nil, repoerr.ErrConversationSelect
+	}
+	return conv, nil
+}
+
+func (r *messageRepo) ListConversations(ctx context.Context, userID string,
+	limit, offset int) ([]entities.Conversation, error) {
+	rows, err := r.db.Query(ctx, selectConversation+`,
+			(SELECT COUNT(*) FROM messages m
+			WHERE m.conversation_id = c.id AND m.sender_id <> $1 AND m.read_at IS NULL)
+		FROM conversations c
+		WHERE c.buyer_id = $1 OR c.seller_id = $1
+		ORDER BY c.updated_at DESC, c.id DESC
+		LIMIT $2 OFFSET $3`, userID, limit, offset)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error selecting conversations: ", err)
+		return nil, repoerr.ErrConversationSelect
+	}
+	defer rows.Close()
+
+	var conversations []entities.Conversation
+	for rows.Next() {
+		var unread int
+		conv, err := scanConversation(rows, &unread)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning conversation: ", err)
+			return nil, repoerr.ErrScan
+		}
+		conv.UnreadCount = unread
+		conversations = append(conversations, *conv)
+	}
+	if err = rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating conversations: ", err)
+		return nil, repoerr.ErrScan
+	}
+	return conversations, nil
+}
+
+func (r *messageRepo) AddMessage(ctx context.Context, msg *entities.Message) error {
+	err := r.db.QueryRow(ctx, `
+		WITH msg AS (
+			INSERT INTO messages(conversation_id, sender_id, body)
+			VALUES($1, $2, $3)
+			RETURNING id, created_at
+		), touched AS (
+			UPDATE conversations SET updated_at = (SELECT created_at FROM msg)
+			WHERE id = $1
+		)
+		SELECT id, created_at FROM msg`,
+		msg.ConversationID, msg.SenderID, msg.Body).Scan(&msg.ID, &msg.CreatedAt)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error inserting message: ", err)
+		return repoerr.ErrMessageInsert
+	}
+	return nil
+}
+
+func (r *messageRepo) ListMessages(ctx context.Context, conversationID int,
+	beforeID int64, limit int) ([]entities.Message, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, conversation_id, sender_id, body, created_at, read_at
+		FROM messages
+		WHERE conversation_id = $1 AND ($2::bigint = 0 OR id < $2)
+		ORDER BY id DESC
+		LIMIT $3`, conversationID, beforeID, limit)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error selecting messages: ", err)
+		return nil, repoerr.ErrMessageSelect
+	}
+	defer rows.Close()
+
+	var messages []entities.Message
+	for rows.Next() {
+		var (
+			msg    entities.Message
+			readAt *time.Time
+		)
+		if err = rows.Scan(&msg.ID, &msg.ConversationID, &msg.SenderID, &msg.Body,
+			&msg.CreatedAt, &readAt); err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning message: ", err)
+			return nil, repoerr.ErrScan
+		}
+		if readAt != nil {
+			msg.ReadAt = *readAt
+		}
+		messages = append(messages, msg)
+	}
+	if err = rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating messages: ", err)
+		return nil, repoerr.ErrScan
+	}
+	return messages, nil
+}
+
+func (r *messageRepo) MarkRead(ctx context.Context, conversationID int, readerID string) (int64, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE messages
+		SET read_at = CURRENT_TIMESTAMP
+		WHERE conversation_id = $1 AND sender_id <> $2 AND read_at IS NULL;`, conversationID, readerID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error marking messages as read: ", err)
+		return 0, repoerr.ErrMessageUpdate
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *messageRepo) UnreadCount(ctx context.Context, userID string) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM messages m
+		JOIN conversations c ON c.id = m.conversation_id
+		WHERE (c.buyer_id = $1 OR c.seller_id = $1) AND m.sender_id <> $1 AND m.read_at IS NULL`,
+		userID).Scan(&count)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error counting unread messages: ", err)
+		return 0, repoerr.ErrMessageSelect
+	}
+	return count, nil
+}
+
+func (r *messageRepo) Block(ctx context.Context, blockerID, blockedID string) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO user_blocks(blocker_id, blocked_id)
+		VALUES($1, $2)
+		ON CONFLICT (blocker_id, blocked_id) DO NOTHING;`, blockerID, blockedID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error inserting block: ", err)
+		return repoerr.ErrBlockInsert
+	}
+	r.logger.InfoContext(ctx, "User ", blockedID, " blocked by: ", blockerID)
+	return nil
+}
+
+func (r *messageRepo) Unblock(ctx context.Context, blockerID, blockedID string) error {
+	tag, err := r.db.Exec(ctx, `
+		DELETE FROM user_blocks
+		WHERE blocker_id = $1 AND blocked_id = $2;`, blockerID, blockedID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error deleting block: ", err)
+		return repoerr.ErrBlockDelete
+	}
+	if tag.RowsAffected() == 0 {
+		return repoerr.ErrBlockNotFound
+	}
+	r.logger.InfoContext(ctx, "User ", blockedID, " unblocked by: ", blockerID)
+	return nil
+}
+
+func (r *messageRepo) IsBlocked(ctx context.Context, blockerID, blockedID string) (bool, error) {
+	var blocked bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2)`,
+		blockerID, blockedID).Scan(&blocked)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error selecting block: ", err)
+		return false, repoerr.ErrBlockSelect
+	}
+	return blocked, nil
+}
+
+func scanConversation(row pgx.Row, extra ...interface{}) (*entities.Conversation, error) {
+	var (
+		conv entities.Conversation
+		adID *int
+	)
+	dest := append([]interface{}{&conv.ID, &adID, &conv.AdTitle, &conv.BuyerID, &conv.SellerID,
+		&conv.CreatedAt, &conv.UpdatedAt, &conv.Blocked}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	if adID == nil {
+		conv.Locked = true
+	} else {
+		conv.AdID = *adID
+	}
+	return &conv, nil
+}
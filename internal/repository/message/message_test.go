@@ -0,0 +1,201 @@
+//nolint:all // testpackage
+package message
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/pkg/db"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var conversationScanArgs = []interface{}{mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+	mock.Anything, mock.Anything, mock.Anything, mock.Anything}
+
+func TestMessageRepo_GetConversation(t *testing.T) {
+	t.Run("deleted ad locks the conversation", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &messageRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", conversationScanArgs...).Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 3
+			*args.Get(3).(*string) = "buyer"
+		}).Return(nil)
+
+		conv, err := repo.GetConversation(context.Background(), 3)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, conv.ID)
+		assert.Equal(t, 0, conv.AdID)
+		assert.True(t, conv.Locked)
+	})
+
+	t.Run("existing ad", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &messageRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", conversationScanArgs...).Run(func(args mock.Arguments) {
+			adID := 7
+			*args.Get(1).(**int) = &adID
+		}).Return(nil)
+
+		conv, err := repo.GetConversation(context.Background(), 3)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, conv.AdID)
+		assert.False(t, conv.Locked)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &messageRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", conversationScanArgs...).Return(pgx.ErrNoRows)
+
+		conv, err := repo.GetConversation(context.Background(), 3)
+		assert.Nil(t, conv)
+		assert.Equal(t, repoerr.ErrConversationNotFound, err)
+	})
+}
+
+func TestMessageRepo_ListConversations(t *testing.T) {
+	mockPool := new(db.MockPool)
+	mockRows := new(db.MockRows)
+	defer mockPool.AssertExpectations(t)
+	defer mockRows.AssertExpectations(t)
+
+	repo := &messageRepo{db: mockPool}
+	mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+	mockRows.On("Next").Return(true).Once()
+	mockRows.On("Next").Return(false).Once()
+	mockRows.On("Scan", append(conversationScanArgs, mock.Anything)...).Run(func(args mock.Arguments) {
+		*args.Get(8).(*int) = 2
+	}).Return(nil).Once()
+	mockRows.On("Err").Return(nil)
+	mockRows.On("Close").Return()
+
+	conversations, err := repo.ListConversations(context.Background(), "buyer", 20, 0)
+	assert.NoError(t, err)
+	assert.Len(t, conversations, 1)
+	assert.Equal(t, 2, conversations[0].UnreadCount)
+}
+
+func TestMessageRepo_AddMessage(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &messageRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(0).(*int64) = 11
+		}).Return(nil)
+
+		msg := &entities.Message{ConversationID: 1, SenderID: "buyer", Body: "Hi"}
+		assert.NoError(t, repo.AddMessage(context.Background(), msg))
+		assert.Equal(t, int64(11), msg.ID)
+	})
+
+	t.Run("insert error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &messageRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+		err := repo.AddMessage(context.Background(), &entities.Message{})
+		assert.Equal(t, repoerr.ErrMessageInsert, err)
+	})
+}
+
+func TestMessageRepo_ListMessages(t *testing.T) {
+	mockPool := new(db.MockPool)
+	mockRows := new(db.MockRows)
+	defer mockPool.AssertExpectations(t)
+	defer mockRows.AssertExpectations(t)
+
+	readAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	repo := &messageRepo{db: mockPool}
+	mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+	mockRows.On("Next").Return(true).Twice()
+	mockRows.On("Next").Return(false).Once()
+	mockRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(5).(**time.Time) = &readAt
+	}).Return(nil).Once()
+	mockRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything).Return(nil).Once()
+	mockRows.On("Err").Return(nil)
+	mockRows.On("Close").Return()
+
+	messages, err := repo.ListMessages(context.Background(), 1, 0, 50)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 2)
+	assert.Equal(t, readAt, messages[0].ReadAt)
+	assert.True(t, messages[1].ReadAt.IsZero())
+}
+
+func TestMessageRepo_MarkRead(t *testing.T) {
+	mockPool := new(db.MockPool)
+	defer mockPool.AssertExpectations(t)
+
+	repo := &messageRepo{db: mockPool}
+	mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+		Return(pgconn.NewCommandTag("UPDATE 3"), nil)
+
+	n, err := repo.MarkRead(context.Background(), 1, "seller")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+}
+
+func TestMessageRepo_Unblock(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &messageRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("DELETE 0"), nil)
+
+		err := repo.Unblock(context.Background(), "seller", "buyer")
+		assert.Equal(t, repoerr.ErrBlockNotFound, err)
+	})
+}
+
+func TestMessageRepo_IsBlocked(t *testing.T) {
+	mockPool := new(db.MockPool)
+	mockRow := new(db.MockRow)
+	defer mockPool.AssertExpectations(t)
+	defer mockRow.AssertExpectations(t)
+
+	repo := &messageRepo{db: mockPool}
+	mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+	mockRow.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*bool) = true
+	}).Return(nil)
+
+	blocked, err := repo.IsBlocked(context.Background(), "seller", "buyer")
+	assert.NoError(t, err)
+	assert.True(t, blocked)
+}
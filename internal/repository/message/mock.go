@@ -0,0 +1,76 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package message
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMessageRepo struct {
+	mock.Mock
+}
+
+func (m *MockMessageRepo) GetOrCreateConversation(ctx context.Context, conv *entities.Conversation) (int, error) {
+	args := m.Called(ctx, conv)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMessageRepo) GetConversation(ctx context.Context, id int) (*entities.Conversation, error) {
+	args := m.Called(ctx, id)
+	if conv, ok := args.Get(0).(*entities.Conversation); ok {
+		return conv, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockMessageRepo) ListConversations(ctx context.Context, userID string,
+	limit, offset int) ([]entities.Conversation, error) {
+	args := m.Called(ctx, userID, limit, offset)
+	if conversations, ok := args.Get(0).([]entities.Conversation); ok {
+		return conversations, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockMessageRepo) AddMessage(ctx context.Context, msg *entities.Message) error {
+	args := m.Called(ctx, msg)
+	return args.Error(0)
+}
+
+func (m *MockMessageRepo) ListMessages(ctx context.Context, conversationID int,
+	beforeID int64, limit int) ([]entities.Message, error) {
+	args := m.Called(ctx, conversationID, beforeID, limit)
+	if messages, ok := args.Get(0).([]entities.Message); ok {
+		return messages, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockMessageRepo) MarkRead(ctx context.Context, conversationID int, readerID string) (int64, error) {
+	args := m.Called(ctx, conversationID, readerID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockMessageRepo) UnreadCount(ctx context.Context, userID string) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMessageRepo) Block(ctx context.Context, blockerID, blockedID string) error {
+	args := m.Called(ctx, blockerID, blockedID)
+	return args.Error(0)
+}
+
+func (m *MockMessageRepo) Unblock(ctx context.Context, blockerID, blockedID string) error {
+	args := m.Called(ctx, blockerID, blockedID)
+	return args.Error(0)
+}
+
+func (m *MockMessageRepo) IsBlocked(ctx context.Context, blockerID, blockedID string) (bool, error) {
+	args := m.Called(ctx, blockerID, blockedID)
+	return args.Bool(0), args.Error(1)
+}
+
+var _ MessageRepository = (*MockMessageRepo)(nil)
@@ -0,0 +1,34 @@
+package message
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/pkg/db"
+	customLogger "ads-service/pkg/logger"
+	"context"
+)
+
+type MessageRepository interface {
+	// GetOrCreateConversation returns the ID of the buyer's conversation about the ad, creating it when needed.
+	GetOrCreateConversation(ctx context.Context, conv *entities.Conversation) (int, error)
+	GetConversation(ctx context.Context, id int) (*entities.Conversation, error)
+	// ListConversations returns conversations of the user newest first, with unread counts for that user.
+	ListConversations(ctx context.Context, userID string, limit, offset int) ([]entities.Conversation, error)
+	AddMessage(ctx context.Context, msg *entities.Message) error
+	// ListMessages returns up to limit messages older than beforeID (all when 0), newest first.
+	ListMessages(ctx context.Context, conversationID int, beforeID int64, limit int) ([]entities.Message, error)
+	// MarkRead marks messages of the other participant as read and returns how many were updated.
+	MarkRead(ctx context.Context, conversationID int, readerID string) (int64, error)
+	UnreadCount(ctx context.Context, userID string) (int, error)
+	Block(ctx context.Context, blockerID, blockedID string) error
+	Unblock(ctx context.Context, blockerID, blockedID string) error
+	IsBlocked(ctx context.Context, blockerID, blockedID string) (bool, error)
+}
+
+type messageRepo struct {
+	db     db.Pool
+	logger customLogger.Logger
+}
+
+func NewMessageRepo(pool db.Pool, logTool customLogger.Logger) MessageRepository {
+	return &messageRepo{db: pool, logger: logTool}
+}
@@ -0,0 +1,136 @@
+package notification
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (r *notificationRepo) Create(ctx context.Context, n *entities.Notification) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO notifications(user_id, kind, subject, body)
+		VALUES($1, $2, $3, $4)
+		RETURNING id, created_at`, n.UserID, n.Kind, n.Subject, n.Body).Scan(&n.ID, &n.CreatedAt)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error inserting notification: ", err)
+		return repoerr.ErrNotificationInsert
+	}
+	return nil
+}
+
+func (r *notificationRepo) ListByUser(ctx context.Context, userID string, unreadOnly bool,
+	limit, offset int) ([]entities.Notification, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, user_id, kind, subject, body, created_at, read_at
+		FROM notifications
+		WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+		ORDER BY id DESC
+		LIMIT $3 OFFSET $4`, userID, unreadOnly, limit, offset)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error selecting notifications: ", err)
+		return nil, repoerr.ErrNotificationSelect
+	}
+	defer rows.Close()
+
+	var notifications []entities.Notification
+	for rows.Next() {
+		var (
+			n      entities.Notification
+			readAt *time.Time
+		)
+		if err = rows.Scan(&n.ID, &n.UserID, &n.Kind, &n.Subject, &n.Body, &n.CreatedAt, &readAt); err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning notification: ", err)
+			return nil, repoerr.ErrScan
+		}
+		if readAt != nil {
+			n.ReadAt = *readAt
+		}
+		notifications = append(notifications, n)
+	}
+	if err = rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating notifications: ", err)
+		return nil, repoerr.ErrScan
+	}
+	return notifications, nil
+}
+
+func (r *notificationRepo) CountUnread(ctx context.Context, userID string) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM notifications
+		WHERE user_id = $1 AND read_at IS NULL`, userID).Scan(&count)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error counting unread notifications: ", err)
+		return 0, repoerr.ErrNotificationSelect
+	}
+	return count, nil
+}
+
+func (r *notificationRepo) MarkRead(ctx context.Context, userID string, id int) error {
+	// COALESCE keeps the first read time and still reports the row as found
+	tag, err := r.db.Exec(ctx, `
+		UPDATE notifications
+		SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP)
+		WHERE id = $1 AND user_id = $2;`, id, userID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error marking notification as read: ", err)
+		return repoerr.ErrNotificationUpdate
+	}
+	if tag.RowsAffected() == 0 {
+		return repoerr.ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (r *notificationRepo) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE notifications
+		SET read_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND read_at IS NULL;`, userID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error marking notifications as read: ", err)
+		return 0, repoerr.ErrNotificationUpdate
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *notificationRepo) GetPreferences(ctx context.Context,
+	userID string) (*entities.NotificationPreferences, error) {
+	prefs := entities.NotificationPreferences{UserID: userID}
+	err := r.db.QueryRow(ctx, `
+		SELECT in_app, sms, email, email_address, updated_at
+		FROM notification_preferences
+		WHERE user_id = $1`, userID).
+		Scan(&prefs.InApp, &prefs.SMS, &prefs.Email, &prefs.EmailAddress, &prefs.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entities.DefaultNotificationPreferences(userID), nil
+		}
+		r.logger.ErrorContext(ctx, "Error selecting notification preferences: ", err)
+		return nil, repoerr.ErrPreferencesSelect
+	}
+	return &prefs, nil
+}
+
+func (r *notificationRepo) SavePreferences(ctx context.Context, prefs *entities.NotificationPreferences) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO notification_preferences(user_id, in_app, sms, email, email_address)
+		VALUES($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE SET
+			in_app = EXCLUDED.in_app,
+			sms = EXCLUDED.sms,
+			email = EXCLUDED.email,
+			email_address = EXCLUDED.email_address,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING updated_at`,
+		prefs.UserID, prefs.InApp, prefs.SMS, prefs.Email, prefs.EmailAddress).Scan(&prefs.UpdatedAt)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error saving notification preferences: ", err)
+		return repoerr.ErrPreferencesUpsert
+	}
+	return nil
+}
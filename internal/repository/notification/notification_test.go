@@ -0,0 +1,180 @@
+//nolint:all // testpackage
+package notification
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/pkg/db"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNotificationRepo_Create(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &notificationRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 4
+		}).Return(nil)
+
+		n := &entities.Notification{UserID: "user", Kind: entities.NotificationMessage, Subject: "Hi"}
+		assert.NoError(t, repo.Create(context.Background(), n))
+		assert.Equal(t, 4, n.ID)
+	})
+
+	t.Run("insert error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &notificationRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+		err := repo.Create(context.Background(), &entities.Notification{})
+		assert.Equal(t, repoerr.ErrNotificationInsert, err)
+	})
+}
+
+func TestNotificationRepo_ListByUser(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRows := new(db.MockRows)
+		defer mockPool.AssertExpectations(t)
+		defer mockRows.AssertExpectations(t)
+
+		readAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		repo := &notificationRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+		mockRows.On("Next").Return(true).Twice()
+		mockRows.On("Next").Return(false).Once()
+		mockRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(6).(**time.Time) = &readAt
+		}).Return(nil).Once()
+		mockRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything).Return(nil).Once()
+		mockRows.On("Err").Return(nil)
+		mockRows.On("Close").Return()
+
+		notifications, err := repo.ListByUser(context.Background(), "user", false, 20, 0)
+		assert.NoError(t, err)
+		assert.Len(t, notifications, 2)
+		assert.Equal(t, readAt, notifications[0].ReadAt)
+		assert.True(t, notifications[1].ReadAt.IsZero())
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &notificationRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).
+			Return(new(db.MockRows), errors.New("db error"))
+
+		notifications, err := repo.ListByUser(context.Background(), "user", true, 20, 0)
+		assert.Nil(t, notifications)
+		assert.Equal(t, repoerr.ErrNotificationSelect, err)
+	})
+}
+
+func TestNotificationRepo_MarkRead(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &notificationRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+
+		assert.NoError(t, repo.MarkRead(context.Background(), "user", 4))
+	})
+
+	t.Run("someone else's notification", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &notificationRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("UPDATE 0"), nil)
+
+		err := repo.MarkRead(context.Background(), "user", 4)
+		assert.Equal(t, repoerr.ErrNotificationNotFound, err)
+	})
+}
+
+func TestNotificationRepo_MarkAllRead(t *testing.T) {
+	mockPool := new(db.MockPool)
+	defer mockPool.AssertExpectations(t)
+
+	repo := &notificationRepo{db: mockPool}
+	mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+		Return(pgconn.NewCommandTag("UPDATE 5"), nil)
+
+	n, err := repo.MarkAllRead(context.Background(), "user")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+}
+
+func TestNotificationRepo_GetPreferences(t *testing.T) {
+	t.Run("saved", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &notificationRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(1).(*bool) = true
+				*args.Get(3).(*string) = "user@example.com"
+			}).Return(nil)
+
+		prefs, err := repo.GetPreferences(context.Background(), "user")
+		assert.NoError(t, err)
+		assert.False(t, prefs.InApp)
+		assert.True(t, prefs.SMS)
+		assert.Equal(t, "user@example.com", prefs.EmailAddress)
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &notificationRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(pgx.ErrNoRows)
+
+		prefs, err := repo.GetPreferences(context.Background(), "user")
+		assert.NoError(t, err)
+		assert.Equal(t, entities.DefaultNotificationPreferences("user"), prefs)
+	})
+}
+
+func TestNotificationRepo_SavePreferences(t *testing.T) {
+	mockPool := new(db.MockPool)
+	mockRow := new(db.MockRow)
+	defer mockPool.AssertExpectations(t)
+
+	repo := &notificationRepo{db: mockPool}
+	mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+	mockRow.On("Scan", mock.Anything).Return(errors.New("db error"))
+
+	err := repo.SavePreferences(context.Background(), &entities.NotificationPreferences{UserID: "user"})
+	assert.Equal(t, repoerr.ErrPreferencesUpsert, err)
+}
@@ -0,0 +1,29 @@
+package notification
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/pkg/db"
+	customLogger "ads-service/pkg/logger"
+	"context"
+)
+
+type NotificationRepository interface {
+	Create(ctx context.Context, n *entities.Notification) error
+	// ListByUser returns notifications of the user newest first.
+	ListByUser(ctx context.Context, userID string, unreadOnly bool, limit, offset int) ([]entities.Notification, error)
+	CountUnread(ctx context.Context, userID string) (int, error)
+	MarkRead(ctx context.Context, userID string, id int) error
+	MarkAllRead(ctx context.Context, userID string) (int64, error)
+	// GetPreferences returns the defaults when the user has not saved any preferences.
+	GetPreferences(ctx context.Context, userID string) (*entities.NotificationPreferences, error)
+	SavePreferences(ctx context.Context, prefs *entities.NotificationPreferences) error
+}
+
+type notificationRepo struct {
+	db     db.Pool
+	logger customLogger.Logger
+}
+
+func NewNotificationRepo(pool db.Pool, logTool customLogger.Logger) NotificationRepository {
+	return &notificationRepo{db: pool, logger: logTool}
+}
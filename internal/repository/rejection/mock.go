@@ -0,0 +1,57 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package rejection
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRejectionRepo struct {
+	mock.Mock
+}
+
+func (m *MockRejectionRepo) GetTemplates(ctx context.Context) ([]entities.RejectionTemplate, error) {
+	args := m.Called(ctx)
+	if templates, ok := args.Get(0).([]entities.RejectionTemplate); ok {
+		return templates, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRejectionRepo) GetTemplate(ctx context.Context, code string) (*entities.RejectionTemplate, error) {
+	args := m.Called(ctx, code)
+	if template, ok := args.Get(0).(*entities.RejectionTemplate); ok {
+		return template, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRejectionRepo) CreateTemplate(ctx context.Context, template *entities.RejectionTemplate) error {
+	args := m.Called(ctx, template)
+	return args.Error(0)
+}
+
+func (m *MockRejectionRepo) UpdateTemplate(ctx context.Context, template *entities.RejectionTemplate) error {
+	args := m.Called(ctx, template)
+	return args.Error(0)
+}
+
+func (m *MockRejectionRepo) GetByAds(ctx context.Context, adIDs []int) (map[int]entities.Rejection, error) {
+	args := m.Called(ctx, adIDs)
+	if rejections, ok := args.Get(0).(map[int]entities.Rejection); ok {
+		return rejections, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRejectionRepo) CountByCode(ctx context.Context) (map[string]int, error) {
+	args := m.Called(ctx)
+	if counts, ok := args.Get(0).(map[string]int); ok {
+		return counts, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+var _ RejectionRepository = (*MockRejectionRepo)(nil)
@@ -0,0 +1,157 @@
+package rejection
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (r *rejectionRepo) GetTemplates(ctx context.Context) ([]entities.RejectionTemplate, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT code, texts, is_active, created_at, updated_at
+		FROM rejection_templates
+		ORDER BY code;`)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error selecting rejection templates: ", err)
+		return nil, repoerr.ErrRejectionSelect
+	}
+	defer rows.Close()
+
+	var templates []entities.RejectionTemplate
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning rejection template: ", err)
+			return nil, repoerr.ErrScan
+		}
+		templates = append(templates, *template)
+	}
+	if err = rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating rejection templates: ", err)
+		return nil, repoerr.ErrScan
+	}
+	return templates, nil
+}
+
+func (r *rejectionRepo) GetTemplate(ctx context.Context, code string) (*entities.RejectionTemplate, error) {
+	template, err := scanTemplate(r.db.QueryRow(ctx, `
+		SELECT code, texts, is_active, created_at, updated_at
+		FROM rejection_templates
+		WHERE code = $1;`, code))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repoerr.ErrRejectionTemplateNotFound
+		}
+		r.logger.ErrorContext(ctx, "Error selecting rejection template: ", err)
+		return nil, repoerr.ErrRejectionSelect
+	}
+	return template, nil
+}
+
+func (r *rejectionRepo) CreateTemplate(ctx context.Context, template *entities.RejectionTemplate) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO rejection_templates(code, texts, is_active)
+		VALUES($1, $2, $3)
+		ON CONFLICT (code) DO NOTHING
+		RETURNING created_at, updated_at;`, template.Code, template.Texts, template.IsActive).
+		Scan(&template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repoerr.ErrRejectionTemplateExists
+		}
+		r.logger.ErrorContext(ctx, "Error inserting rejection template: ", err)
+		return repoerr.ErrRejectionTemplateInsert
+	}
+	return nil
+}
+
+func (r *rejectionRepo) UpdateTemplate(ctx context.Context, template *entities.RejectionTemplate) error {
+	err := r.db.QueryRow(ctx, `
+		UPDATE rejection_templates
+		SET texts = $2, is_active = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE code = $1
+		RETURNING created_at, updated_at;`, template.Code, template.Texts, template.IsActive).
+		Scan(&template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repoerr.ErrRejectionTemplateNotFound
+		}
+		r.logger.ErrorContext(ctx, "Error updating rejection template: ", err)
+		return repoerr.ErrRejectionTemplateUpdate
+	}
+	return nil
+}
+
+func (r *rejectionRepo) GetByAds(ctx context.Context, adIDs []int) (map[int]entities.Rejection, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT a.id, a.rejection_code, a.rejection_note, t.texts
+		FROM ads a
+		JOIN rejection_templates t ON t.code = a.rejection_code
+		WHERE a.id = ANY($1) AND a.status = 'rejected';`, adIDs)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error selecting ad rejections: ", err)
+		return nil, repoerr.ErrRejectionSelect
+	}
+	defer rows.Close()
+
+	rejections := make(map[int]entities.Rejection, len(adIDs))
+	for rows.Next() {
+		var (
+			adID      int
+			rejection entities.Rejection
+		)
+		if err = rows.Scan(&adID, &rejection.Code, &rejection.Note, &rejection.Texts); err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning ad rejection: ", err)
+			return nil, repoerr.ErrScan
+		}
+		rejections[adID] = rejection
+	}
+	if err = rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating ad rejections: ", err)
+		return nil, repoerr.ErrScan
+	}
+	return rejections, nil
+}
+
+func (r *rejectionRepo) CountByCode(ctx context.Context) (map[string]int, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT COALESCE(rejection_code, 'none'), COUNT(*)
+		FROM ads
+		WHERE status = 'rejected'
+		GROUP BY 1;`)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error counting rejection codes: ", err)
+		return nil, repoerr.ErrRejectionSelect
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var (
+			code  string
+			count int
+		)
+		if err = rows.Scan(&code, &count); err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning rejection code count: ", err)
+			return nil, repoerr.ErrScan
+		}
+		counts[code] = count
+	}
+	if err = rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating rejection code counts: ", err)
+		return nil, repoerr.ErrScan
+	}
+	return counts, nil
+}
+
+func scanTemplate(row pgx.Row) (*entities.RejectionTemplate, error) {
+	var template entities.RejectionTemplate
+	if err := row.Scan(&template.Code, &template.Texts, &template.IsActive, &template.CreatedAt,
+		&template.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
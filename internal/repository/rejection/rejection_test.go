@@ -0,0 +1,169 @@
+//nolint:all // testpackage
+package rejection
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/pkg/db"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRejectionRepo_GetTemplate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &rejectionRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(0).(*string) = "poor_photos"
+				*args.Get(1).(*map[string]string) = map[string]string{"en": "Bad photos."}
+				*args.Get(2).(*bool) = true
+			}).Return(nil)
+
+		template, err := repo.GetTemplate(context.Background(), "poor_photos")
+		assert.NoError(t, err)
+		assert.Equal(t, "poor_photos", template.Code)
+		assert.Equal(t, "Bad photos.", template.Texts["en"])
+		assert.True(t, template.IsActive)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &rejectionRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(pgx.ErrNoRows)
+
+		template, err := repo.GetTemplate(context.Background(), "unknown")
+		assert.Nil(t, template)
+		assert.Equal(t, repoerr.ErrRejectionTemplateNotFound, err)
+	})
+}
+
+func TestRejectionRepo_CreateTemplate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &rejectionRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything).Return(nil)
+
+		err := repo.CreateTemplate(context.Background(), &entities.RejectionTemplate{Code: "spam"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("already exists", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &rejectionRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything).Return(pgx.ErrNoRows)
+
+		err := repo.CreateTemplate(context.Background(), &entities.RejectionTemplate{Code: "spam"})
+		assert.Equal(t, repoerr.ErrRejectionTemplateExists, err)
+	})
+}
+
+func TestRejectionRepo_UpdateTemplate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		scanErr error
+		want    error
+	}{
+		"success":   {},
+		"not found": {scanErr: pgx.ErrNoRows, want: repoerr.ErrRejectionTemplateNotFound},
+		"db error":  {scanErr: errors.New("db error"), want: repoerr.ErrRejectionTemplateUpdate},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockPool := new(db.MockPool)
+			mockRow := new(db.MockRow)
+			defer mockPool.AssertExpectations(t)
+
+			repo := &rejectionRepo{db: mockPool}
+			mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+			mockRow.On("Scan", mock.Anything, mock.Anything).Return(tc.scanErr)
+
+			err := repo.UpdateTemplate(context.Background(), &entities.RejectionTemplate{Code: "spam"})
+			assert.Equal(t, tc.want, err)
+		})
+	}
+}
+
+func TestRejectionRepo_GetByAds(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRows := new(db.MockRows)
+		defer mockPool.AssertExpectations(t)
+		defer mockRows.AssertExpectations(t)
+
+		repo := &rejectionRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+		mockRows.On("Next").Return(true).Once()
+		mockRows.On("Next").Return(false).Once()
+		mockRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(0).(*int) = 3
+				*args.Get(1).(*string) = "duplicate"
+				*args.Get(2).(*string) = "see ad #1"
+			}).Return(nil).Once()
+		mockRows.On("Err").Return(nil)
+		mockRows.On("Close").Return()
+
+		rejections, err := repo.GetByAds(context.Background(), []int{3, 4})
+		assert.NoError(t, err)
+		assert.Equal(t, map[int]entities.Rejection{3: {Code: "duplicate", Note: "see ad #1"}}, rejections)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &rejectionRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).
+			Return(new(db.MockRows), errors.New("db error"))
+
+		rejections, err := repo.GetByAds(context.Background(), []int{3})
+		assert.Nil(t, rejections)
+		assert.Equal(t, repoerr.ErrRejectionSelect, err)
+	})
+}
+
+func TestRejectionRepo_CountByCode(t *testing.T) {
+	mockPool := new(db.MockPool)
+	mockRows := new(db.MockRows)
+	defer mockPool.AssertExpectations(t)
+	defer mockRows.AssertExpectations(t)
+
+	repo := &rejectionRepo{db: mockPool}
+	mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+	mockRows.On("Next").Return(true).Twice()
+	mockRows.On("Next").Return(false).Once()
+	codes := []string{"none", "poor_photos"}
+	mockRows.On("Scan", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*string) = codes[0]
+		*args.Get(1).(*int) = len(codes)
+		codes = codes[1:]
+	}).Return(nil).Twice()
+	mockRows.On("Err").Return(nil)
+	mockRows.On("Close").Return()
+
+	counts, err := repo.CountByCode(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"none": 2, "poor_photos": 1}, counts)
+}
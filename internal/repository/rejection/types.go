@@ -0,0 +1,30 @@
+package rejection
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/pkg/db"
+	customLogger "ads-service/pkg/logger"
+	"context"
+)
+
+type RejectionRepository interface {
+	GetTemplates(ctx context.Context) ([]entities.RejectionTemplate, error)
+	GetTemplate(ctx context.Context, code string) (*entities.RejectionTemplate, error)
+	CreateTemplate(ctx context.Context, template *entities.RejectionTemplate) error
+	// UpdateTemplate replaces the texts and the active flag, the code stays.
+	UpdateTemplate(ctx context.Context, template *entities.RejectionTemplate) error
+	// GetByAds returns the structured reasons of the rejected ads among adIDs by ad ID.
+	// Ads rejected before codes were introduced are left out.
+	GetByAds(ctx context.Context, adIDs []int) (map[int]entities.Rejection, error)
+	// CountByCode counts rejected ads by rejection code, ads without a code are counted under "none".
+	CountByCode(ctx context.Context) (map[string]int, error)
+}
+
+type rejectionRepo struct {
+	db     db.Pool
+	logger customLogger.Logger
+}
+
+func NewRejectionRepo(pool db.Pool, logTool customLogger.Logger) RejectionRepository {
+	return &rejectionRepo{db: pool, logger: logTool}
+}
@@ -0,0 +1,42 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package report
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReportRepo struct {
+	mock.Mock
+}
+
+func (m *MockReportRepo) Create(ctx context.Context, report *entities.Report, threshold int) (bool, error) {
+	args := m.Called(ctx, report, threshold)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockReportRepo) GetByID(ctx context.Context, id int) (*entities.Report, error) {
+	args := m.Called(ctx, id)
+	if report, ok := args.Get(0).(*entities.Report); ok {
+		return report, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockReportRepo) List(ctx context.Context, status entities.ReportStatus) ([]entities.Report, error) {
+	args := m.Called(ctx, status)
+	if reports, ok := args.Get(0).([]entities.Report); ok {
+		return reports, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockReportRepo) Resolve(ctx context.Context, adID int, status entities.ReportStatus,
+	adminID string) (int64, error) {
+	args := m.Called(ctx, adID, status, adminID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+var _ ReportRepository = (*MockReportRepo)(nil)
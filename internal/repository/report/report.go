@@ -0,0 +1,130 @@
+package report
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const selectReport = `
+	SELECT r.id, r.ad_id, r.reporter_id, r.reason, r.comment, r.status, r.resolved_by,
+		r.created_at, r.resolved_at, COALESCE(a.title, ''), COALESCE(a.author_id::text, ''),
+		(SELECT COUNT(*) FROM reports o WHERE o.ad_id = r.ad_id AND o.status = 'open') AS open_reports
+	FROM reports r
+	LEFT JOIN ads a ON a.id = r.ad_id`
+
+func (r *reportRepo) Create(ctx context.Context, report *entities.Report, threshold int) (bool, error) {
+	// the count below runs on a snapshot taken after the lock, a report committed meanwhile is not missed
+	if _, err := r.db.Exec(ctx, `SELECT 1 FROM ads WHERE id = $1 FOR UPDATE;`, report.AdID); err != nil {
+		r.logger.ErrorContext(ctx, "Error locking reported ad: ", err)
+		return false, repoerr.ErrReportInsert
+	}
+	var hidden bool
+	// the counting subquery does not see the row inserted by the same statement, hence + 1
+	err := r.db.QueryRow(ctx, `
+		WITH inserted AS (
+			INSERT INTO reports(ad_id, reporter_id, reason, comment)
+			VALUES($1, $2, $3, $4)
+			ON CONFLICT (ad_id, reporter_id) DO NOTHING
+			RETURNING id, created_at
+		), hidden AS (
+			UPDATE ads SET status = 'pending', is_active = FALSE, updated_at = CURRENT_TIMESTAMP,
+				version = version + 1
+			WHERE id = $1 AND status = 'approved' AND is_active
+				AND EXISTS (SELECT 1 FROM inserted)
+				AND (SELECT COUNT(*) FROM reports WHERE ad_id = $1 AND status = 'open') + 1 >= $5
+			RETURNING id
+		)
+		SELECT id, created_at, EXISTS (SELECT 1 FROM hidden) FROM inserted`,
+		report.AdID, report.ReporterID, report.Reason, report.Comment, threshold).
+		Scan(&report.ID, &report.CreatedAt, &hidden)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, repoerr.ErrReportDuplicate
+		}
+		r.logger.ErrorContext(ctx, "Error inserting report: ", err)
+		return false, repoerr.ErrReportInsert
+	}
+	report.Status = entities.ReportOpen
+	if hidden {
+		r.logger.InfoContext(ctx, "Ad ", report.AdID, " sent back to moderation after reports")
+	}
+	return hidden, nil
+}
+
+func (r *reportRepo) GetByID(ctx context.Context, id int) (*entities.Report, error) {
+	report, err := scanReport(r.db.QueryRow(ctx, selectReport+`
+		WHERE r.id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repoerr.ErrReportNotFound
+		}
+		r.logger.ErrorContext(ctx, "Error selecting report: ", err)
+		return nil, repoerr.ErrReportSelect
+	}
+	return report, nil
+}
+
+func (r *reportRepo) List(ctx context.Context, status entities.ReportStatus) ([]entities.Report, error) {
+	rows, err := r.db.Query(ctx, selectReport+`
+		WHERE r.status = $1 AND (r.status <> 'open' OR a.id IS NOT NULL)
+		ORDER BY open_reports DESC, r.ad_id, r.created_at`, status)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error selecting reports: ", err)
+		return nil, repoerr.ErrReportSelect
+	}
+	defer rows.Close()
+
+	var reports []entities.Report
+	for rows.Next() {
+		report, err := scanReport(rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning report: ", err)
+			return nil, repoerr.ErrScan
+		}
+		reports = append(reports, *report)
+	}
+	if err = rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating reports: ", err)
+		return nil, repoerr.ErrScan
+	}
+	return reports, nil
+}
+
+func (r *reportRepo) Resolve(ctx context.Context, adID int, status entities.ReportStatus,
+	adminID string) (int64, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE reports
+		SET status = $2, resolved_by = $3, resolved_at = CURRENT_TIMESTAMP
+		WHERE ad_id = $1 AND status = 'open';`, adID, status, adminID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error resolving reports: ", err)
+		return 0, repoerr.ErrReportUpdate
+	}
+	r.logger.InfoContext(ctx, "Reports on ad ", adID, " resolved as ", status, " by: ", adminID)
+	return tag.RowsAffected(), nil
+}
+
+func scanReport(row pgx.Row) (*entities.Report, error) {
+	var (
+		report     entities.Report
+		resolvedBy *string
+		resolvedAt *time.Time
+	)
+	err := row.Scan(&report.ID, &report.AdID, &report.ReporterID, &report.Reason, &report.Comment, &report.Status,
+		&resolvedBy, &report.CreatedAt, &resolvedAt, &report.AdTitle, &report.AdAuthorID, &report.OpenReports)
+	if err != nil {
+		return nil, err
+	}
+	if resolvedBy != nil {
+		report.ResolvedBy = *resolvedBy
+	}
+	if resolvedAt != nil {
+		report.ResolvedAt = *resolvedAt
+	}
+	return &report, nil
+}
@@ -0,0 +1,173 @@
+//nolint:all // testpackage
+package report
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/pkg/db"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var reportScanArgs = []interface{}{mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+	mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+	mock.Anything, mock.Anything}
+
+func TestReportRepo_Create(t *testing.T) {
+	t.Run("threshold reached", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &reportRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(pgconn.CommandTag{}, nil)
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 9
+			*args.Get(2).(*bool) = true
+		}).Return(nil)
+
+		report := &entities.Report{AdID: 1, ReporterID: "buyer", Reason: entities.ReasonScam}
+		hidden, err := repo.Create(context.Background(), report, 3)
+		assert.NoError(t, err)
+		assert.True(t, hidden)
+		assert.Equal(t, 9, report.ID)
+		assert.Equal(t, entities.ReportOpen, report.Status)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &reportRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(pgconn.CommandTag{}, nil)
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything).Return(pgx.ErrNoRows)
+
+		hidden, err := repo.Create(context.Background(), &entities.Report{AdID: 1}, 3)
+		assert.False(t, hidden)
+		assert.Equal(t, repoerr.ErrReportDuplicate, err)
+	})
+
+	t.Run("insert error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &reportRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(pgconn.CommandTag{}, nil)
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+		_, err := repo.Create(context.Background(), &entities.Report{AdID: 1}, 3)
+		assert.Equal(t, repoerr.ErrReportInsert, err)
+	})
+
+	t.Run("lock error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &reportRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(pgconn.CommandTag{}, errors.New("timeout"))
+
+		_, err := repo.Create(context.Background(), &entities.Report{AdID: 1}, 3)
+		assert.Equal(t, repoerr.ErrReportInsert, err)
+	})
+}
+
+func TestReportRepo_GetByID(t *testing.T) {
+	t.Run("resolved", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &reportRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", reportScanArgs...).Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 4
+			*args.Get(5).(*entities.ReportStatus) = entities.ReportUserBanned
+			admin := "admin"
+			*args.Get(6).(**string) = &admin
+		}).Return(nil)
+
+		report, err := repo.GetByID(context.Background(), 4)
+		assert.NoError(t, err)
+		assert.Equal(t, "admin", report.ResolvedBy)
+		assert.Equal(t, entities.ReportUserBanned, report.Status)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &reportRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", reportScanArgs...).Return(pgx.ErrNoRows)
+
+		report, err := repo.GetByID(context.Background(), 4)
+		assert.Nil(t, report)
+		assert.Equal(t, repoerr.ErrReportNotFound, err)
+	})
+}
+
+func TestReportRepo_List(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRows := new(db.MockRows)
+		defer mockPool.AssertExpectations(t)
+		defer mockRows.AssertExpectations(t)
+
+		repo := &reportRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+		mockRows.On("Next").Return(true).Once()
+		mockRows.On("Next").Return(false).Once()
+		mockRows.On("Scan", reportScanArgs...).Run(func(args mock.Arguments) {
+			*args.Get(1).(*int) = 7
+			*args.Get(11).(*int) = 3
+		}).Return(nil).Once()
+		mockRows.On("Err").Return(nil)
+		mockRows.On("Close").Return()
+
+		reports, err := repo.List(context.Background(), entities.ReportOpen)
+		assert.NoError(t, err)
+		assert.Len(t, reports, 1)
+		assert.Equal(t, 7, reports[0].AdID)
+		assert.Equal(t, 3, reports[0].OpenReports)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &reportRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).
+			Return(new(db.MockRows), errors.New("db error"))
+
+		reports, err := repo.List(context.Background(), entities.ReportOpen)
+		assert.Nil(t, reports)
+		assert.Equal(t, repoerr.ErrReportSelect, err)
+	})
+}
+
+func TestReportRepo_Resolve(t *testing.T) {
+	mockPool := new(db.MockPool)
+	defer mockPool.AssertExpectations(t)
+
+	repo := &reportRepo{db: mockPool}
+	mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+		Return(pgconn.NewCommandTag("UPDATE 3"), nil)
+
+	n, err := repo.Resolve(context.Background(), 7, entities.ReportDismissed, "admin")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+}
@@ -0,0 +1,30 @@
+package report
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/pkg/db"
+	customLogger "ads-service/pkg/logger"
+	"context"
+)
+
+type ReportRepository interface {
+	// Create saves the report and, when the ad now has threshold open reports, sends a published ad
+	// back to moderation. It reports whether the ad was hidden. The ad stays locked until the transaction of ctx
+	// ends, so concurrent reports are counted one after another; outside of a transaction the count may race.
+	Create(ctx context.Context, report *entities.Report, threshold int) (bool, error)
+	GetByID(ctx context.Context, id int) (*entities.Report, error)
+	// List returns reports with the given status, ads with most open reports first.
+	// Open reports of deleted ads are skipped, there is nothing left to moderate.
+	List(ctx context.Context, status entities.ReportStatus) ([]entities.Report, error)
+	// Resolve closes all open reports of the ad and returns how many were closed.
+	Resolve(ctx context.Context, adID int, status entities.ReportStatus, adminID string) (int64, error)
+}
+
+type reportRepo struct {
+	db     db.Pool
+	logger customLogger.Logger
+}
+
+func NewReportRepo(pool db.Pool, logTool customLogger.Logger) ReportRepository {
+	return &reportRepo{db: pool, logger: logTool}
+}
@@ -0,0 +1,29 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package rulehit
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRuleHitRepo struct {
+	mock.Mock
+}
+
+func (m *MockRuleHitRepo) Save(ctx context.Context, adID int, decision entities.RuleDecision,
+	hits []entities.RuleHit) error {
+	args := m.Called(ctx, adID, decision, hits)
+	return args.Error(0)
+}
+
+func (m *MockRuleHitRepo) GetByAd(ctx context.Context, adID int) ([]entities.RuleHit, error) {
+	args := m.Called(ctx, adID)
+	if hits, ok := args.Get(0).([]entities.RuleHit); ok {
+		return hits, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+var _ RuleHitRepository = (*MockRuleHitRepo)(nil)
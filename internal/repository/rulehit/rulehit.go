@@ -0,0 +1,60 @@
+package rulehit
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"context"
+)
+
+func (r *ruleHitRepo) Save(ctx context.Context, adID int, decision entities.RuleDecision,
+	hits []entities.RuleHit) error {
+	if len(hits) == 0 {
+		return nil
+	}
+	rules := make([]string, len(hits))
+	details := make([]string, len(hits))
+	scores := make([]int32, len(hits))
+	for i, hit := range hits {
+		rules[i], details[i], scores[i] = hit.Rule, hit.Detail, int32(hit.Score)
+	}
+
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO ad_rule_hits (ad_id, rule, detail, score, decision)
+		SELECT $1, h.rule, h.detail, h.score, $5
+		FROM unnest($2::text[], $3::text[], $4::int[]) AS h(rule, detail, score);`,
+		adID, rules, details, scores, decision)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error saving rule hits: ", err)
+		return repoerr.ErrRuleHitInsert
+	}
+	return nil
+}
+
+func (r *ruleHitRepo) GetByAd(ctx context.Context, adID int) ([]entities.RuleHit, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, ad_id, rule, detail, score, decision, created_at
+		FROM ad_rule_hits
+		WHERE ad_id = $1
+		ORDER BY created_at DESC, id;`, adID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error selecting rule hits: ", err)
+		return nil, repoerr.ErrRuleHitSelect
+	}
+	defer rows.Close()
+
+	var hits []entities.RuleHit
+	for rows.Next() {
+		var hit entities.RuleHit
+		if err = rows.Scan(&hit.ID, &hit.AdID, &hit.Rule, &hit.Detail, &hit.Score, &hit.Decision,
+			&hit.CreatedAt); err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning rule hit: ", err)
+			return nil, repoerr.ErrScan
+		}
+		hits = append(hits, hit)
+	}
+	if err = rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating rule hits: ", err)
+		return nil, repoerr.ErrScan
+	}
+	return hits, nil
+}
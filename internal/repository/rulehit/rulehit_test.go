@@ -0,0 +1,93 @@
+//nolint:all // testpackage
+package rulehit
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/pkg/db"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRuleHitRepo_Save(t *testing.T) {
+	hits := []entities.RuleHit{
+		{Rule: entities.RulePhone, Detail: "phone number in the text", Score: 40},
+		{Rule: entities.RuleCaps, Detail: "80% capital letters", Score: 20},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &ruleHitRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("INSERT 0 2"), nil)
+
+		assert.NoError(t, repo.Save(context.Background(), 7, entities.DecisionReview, hits))
+	})
+
+	t.Run("no hits", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &ruleHitRepo{db: mockPool}
+		assert.NoError(t, repo.Save(context.Background(), 7, entities.DecisionApprove, nil))
+	})
+
+	t.Run("insert error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &ruleHitRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.CommandTag{}, errors.New("db error"))
+
+		err := repo.Save(context.Background(), 7, entities.DecisionReview, hits)
+		assert.Equal(t, repoerr.ErrRuleHitInsert, err)
+	})
+}
+
+func TestRuleHitRepo_GetByAd(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRows := new(db.MockRows)
+		defer mockPool.AssertExpectations(t)
+		defer mockRows.AssertExpectations(t)
+
+		repo := &ruleHitRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+		mockRows.On("Next").Return(true).Once()
+		mockRows.On("Next").Return(false).Once()
+		mockRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(2).(*string) = entities.RuleLink
+			*args.Get(4).(*int) = 40
+		}).Return(nil).Once()
+		mockRows.On("Err").Return(nil)
+		mockRows.On("Close").Return()
+
+		hits, err := repo.GetByAd(context.Background(), 7)
+		assert.NoError(t, err)
+		assert.Len(t, hits, 1)
+		assert.Equal(t, entities.RuleLink, hits[0].Rule)
+		assert.Equal(t, 40, hits[0].Score)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &ruleHitRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).
+			Return(new(db.MockRows), errors.New("db error"))
+
+		hits, err := repo.GetByAd(context.Background(), 7)
+		assert.Nil(t, hits)
+		assert.Equal(t, repoerr.ErrRuleHitSelect, err)
+	})
+}
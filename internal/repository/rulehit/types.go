@@ -0,0 +1,24 @@
+package rulehit
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/pkg/db"
+	customLogger "ads-service/pkg/logger"
+	"context"
+)
+
+type RuleHitRepository interface {
+	// Save records the hits of one submission, all of them get the same decision.
+	Save(ctx context.Context, adID int, decision entities.RuleDecision, hits []entities.RuleHit) error
+	// GetByAd returns the hits of every submission of the ad, newest first.
+	GetByAd(ctx context.Context, adID int) ([]entities.RuleHit, error)
+}
+
+type ruleHitRepo struct {
+	db     db.Pool
+	logger customLogger.Logger
+}
+
+func NewRuleHitRepo(pool db.Pool, logTool customLogger.Logger) RuleHitRepository {
+	return &ruleHitRepo{db: pool, logger: logTool}
+}
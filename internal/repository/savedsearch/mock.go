@@ -0,0 +1,59 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package savedsearch
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSavedSearchRepo struct {
+	mock.Mock
+}
+
+func (m *MockSavedSearchRepo) Create(ctx context.Context, search *entities.SavedSearch) error {
+	args := m.Called(ctx, search)
+	return args.Error(0)
+}
+
+func (m *MockSavedSearchRepo) GetByUser(ctx context.Context, userID string) ([]entities.SavedSearch, error) {
+	args := m.Called(ctx, userID)
+	if searches, ok := args.Get(0).([]entities.SavedSearch); ok {
+		return searches, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockSavedSearchRepo) CountByUser(ctx context.Context, userID string) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockSavedSearchRepo) Delete(ctx context.Context, userID string, id int) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockSavedSearchRepo) MatchAd(ctx context.Context, ad *entities.Ad) ([]entities.SavedSearch, error) {
+	args := m.Called(ctx, ad)
+	if searches, ok := args.Get(0).([]entities.SavedSearch); ok {
+		return searches, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockSavedSearchRepo) PendingDigest(ctx context.Context) ([]entities.SearchMatch, error) {
+	args := m.Called(ctx)
+	if matches, ok := args.Get(0).([]entities.SearchMatch); ok {
+		return matches, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockSavedSearchRepo) MarkNotified(ctx context.Context, searchID int, adIDs []int) error {
+	args := m.Called(ctx, searchID, adIDs)
+	return args.Error(0)
+}
+
+var _ SavedSearchRepository = (*MockSavedSearchRepo)(nil)
@@ -0,0 +1,161 @@
+package savedsearch
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const searchColumns = `s.id, s.user_id, s.name, s.category_id, s.price_min, s.price_max,
+	s.text, s.location, s.mode, s.created_at`
+
+func (r *savedSearchRepo) Create(ctx context.Context, search *entities.SavedSearch) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO saved_searches(user_id, name, category_id, price_min, price_max, text, location, mode)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at`,
+		search.UserID, search.Name, search.CategoryID, search.PriceMin, search.PriceMax,
+		search.Text, search.Location, search.Mode).Scan(&search.ID, &search.CreatedAt)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error inserting saved search: ", err)
+		return repoerr.ErrSavedSearchInsert
+	}
+	r.logger.InfoContext(ctx, "Saved search created for user: ", search.UserID)
+	return nil
+}
+
+func (r *savedSearchRepo) GetByUser(ctx context.Context, userID string) ([]entities.SavedSearch, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+searchColumns+`
+		FROM saved_searches s
+		WHERE s.user_id = $1
+		ORDER BY s.created_at DESC`, userID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error selecting saved searches: ", err)
+		return nil, repoerr.ErrSavedSearchSelect
+	}
+	return r.collect(ctx, rows)
+}
+
+func (r *savedSearchRepo) CountByUser(ctx context.Context, userID string) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM saved_searches
+		WHERE user_id = $1`, userID).Scan(&count)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error counting saved searches: ", err)
+		return 0, repoerr.ErrSavedSearchSelect
+	}
+	return count, nil
+}
+
+func (r *savedSearchRepo) Delete(ctx context.Context, userID string, id int) error {
+	tag, err := r.db.Exec(ctx, `
+		DELETE FROM saved_searches
+		WHERE id = $1 AND user_id = $2;`, id, userID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error deleting saved search: ", err)
+		return repoerr.ErrSavedSearchDelete
+	}
+	if tag.RowsAffected() == 0 {
+		return repoerr.ErrSavedSearchNotFound
+	}
+	r.logger.InfoContext(ctx, "Saved search ", id, " deleted by user: ", userID)
+	return nil
+}
+
+// MatchAd uses the same criteria semantics as the ad Filter: substring text search over
+// title and description, case-insensitive exact location, inclusive price bounds.
+func (r *savedSearchRepo) MatchAd(ctx context.Context, ad *entities.Ad) ([]entities.SavedSearch, error) {
+	rows, err := r.db.Query(ctx, `
+		WITH matched AS (
+			INSERT INTO saved_search_matches(search_id, ad_id)
+			SELECT s.id, $1
+			FROM saved_searches s
+			WHERE s.user_id <> $2
+				AND (s.category_id = 0 OR s.category_id = $3)
+				AND (s.price_min = 0 OR $4 >= s.price_min)
+				AND (s.price_max = 0 OR $4 <= s.price_max)
+				AND (s.location = '' OR lower(s.location) = lower($5))
+				AND (s.text = '' OR strpos(lower($6 || ' ' || $7), lower(s.text)) > 0)
+			ON CONFLICT (search_id, ad_id) DO NOTHING
+			RETURNING search_id
+		)
+		SELECT `+searchColumns+`
+		FROM saved_searches s
+		JOIN matched m ON m.search_id = s.id`,
+		ad.ID, ad.AuthorID, ad.CategoryID, ad.Price, ad.Location, ad.Title, ad.Description)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error matching saved searches: ", err)
+		return nil, repoerr.ErrSavedSearchMatch
+	}
+	return r.collect(ctx, rows)
+}
+
+func (r *savedSearchRepo) PendingDigest(ctx context.Context) ([]entities.SearchMatch, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT m.search_id, s.user_id, s.name, m.ad_id, a.title, a.price, m.matched_at
+		FROM saved_search_matches m
+		JOIN saved_searches s ON s.id = m.search_id
+		JOIN ads a ON a.id = m.ad_id
+		WHERE m.notified_at IS NULL
+			AND s.mode = 'daily'
+			AND a.status = 'approved' AND a.is_active
+		ORDER BY s.user_id, m.search_id, m.matched_at`)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error selecting pending matches: ", err)
+		return nil, repoerr.ErrSavedSearchSelect
+	}
+	defer rows.Close()
+
+	var matches []entities.SearchMatch
+	for rows.Next() {
+		var m entities.SearchMatch
+		if err = rows.Scan(&m.SearchID, &m.UserID, &m.SearchName, &m.AdID, &m.AdTitle,
+			&m.AdPrice, &m.MatchedAt); err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning pending match: ", err)
+			return nil, repoerr.ErrScan
+		}
+		matches = append(matches, m)
+	}
+	if err = rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating pending matches: ", err)
+		return nil, repoerr.ErrScan
+	}
+	return matches, nil
+}
+
+func (r *savedSearchRepo) MarkNotified(ctx context.Context, searchID int, adIDs []int) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE saved_search_matches
+		SET notified_at = CURRENT_TIMESTAMP
+		WHERE search_id = $1 AND ad_id = ANY($2) AND notified_at IS NULL;`, searchID, adIDs)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error marking matches as notified: ", err)
+		return repoerr.ErrSavedSearchNotify
+	}
+	return nil
+}
+
+func (r *savedSearchRepo) collect(ctx context.Context, rows pgx.Rows) ([]entities.SavedSearch, error) {
+	defer rows.Close()
+
+	var searches []entities.SavedSearch
+	for rows.Next() {
+		var s entities.SavedSearch
+		if err := rows.Scan(&s.ID, &s.UserID, &s.Name, &s.CategoryID, &s.PriceMin, &s.PriceMax,
+			&s.Text, &s.Location, &s.Mode, &s.CreatedAt); err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning saved search: ", err)
+			return nil, repoerr.ErrScan
+		}
+		searches = append(searches, s)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating saved searches: ", err)
+		return nil, repoerr.ErrScan
+	}
+	return searches, nil
+}
@@ -0,0 +1,173 @@
+//nolint:all // testpackage
+package savedsearch
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/pkg/db"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var searchScanArgs = []interface{}{mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+	mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything}
+
+func TestSavedSearchRepo_Create(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &savedSearchRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 7
+		}).Return(nil)
+
+		search := &entities.SavedSearch{UserID: "user-1", Name: "Cheap cars", Mode: entities.NotifyInstant}
+		err := repo.Create(context.Background(), search)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, search.ID)
+	})
+
+	t.Run("insert error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &savedSearchRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+		err := repo.Create(context.Background(), &entities.SavedSearch{})
+		assert.Equal(t, repoerr.ErrSavedSearchInsert, err)
+	})
+}
+
+func TestSavedSearchRepo_Delete(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &savedSearchRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("DELETE 0"), nil)
+
+		err := repo.Delete(context.Background(), "user-1", 1)
+		assert.Equal(t, repoerr.ErrSavedSearchNotFound, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &savedSearchRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("DELETE 1"), nil)
+
+		err := repo.Delete(context.Background(), "user-1", 1)
+		assert.NoError(t, err)
+	})
+}
+
+func TestSavedSearchRepo_MatchAd(t *testing.T) {
+	t.Run("returns new matches", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRows := new(db.MockRows)
+		defer mockPool.AssertExpectations(t)
+		defer mockRows.AssertExpectations(t)
+
+		repo := &savedSearchRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+		mockRows.On("Next").Return(true).Once()
+		mockRows.On("Next").Return(false).Once()
+		mockRows.On("Scan", searchScanArgs...).Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = 3
+			*args.Get(8).(*entities.NotifyMode) = entities.NotifyDaily
+		}).Return(nil).Once()
+		mockRows.On("Err").Return(nil)
+		mockRows.On("Close").Return()
+
+		searches, err := repo.MatchAd(context.Background(), &entities.Ad{ID: 1, Title: "BMW"})
+		assert.NoError(t, err)
+		assert.Len(t, searches, 1)
+		assert.Equal(t, 3, searches[0].ID)
+		assert.Equal(t, entities.NotifyDaily, searches[0].Mode)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &savedSearchRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).
+			Return(new(db.MockRows), errors.New("db error"))
+
+		searches, err := repo.MatchAd(context.Background(), &entities.Ad{ID: 1})
+		assert.Nil(t, searches)
+		assert.Equal(t, repoerr.ErrSavedSearchMatch, err)
+	})
+
+	t.Run("scan error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRows := new(db.MockRows)
+		defer mockPool.AssertExpectations(t)
+		defer mockRows.AssertExpectations(t)
+
+		repo := &savedSearchRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+		mockRows.On("Next").Return(true).Once()
+		mockRows.On("Scan", searchScanArgs...).Return(errors.New("scan error")).Once()
+		mockRows.On("Close").Return()
+
+		searches, err := repo.MatchAd(context.Background(), &entities.Ad{ID: 1})
+		assert.Nil(t, searches)
+		assert.Equal(t, repoerr.ErrScan, err)
+	})
+}
+
+func TestSavedSearchRepo_PendingDigest(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRows := new(db.MockRows)
+		defer mockPool.AssertExpectations(t)
+		defer mockRows.AssertExpectations(t)
+
+		repo := &savedSearchRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+		mockRows.On("Next").Return(true).Once()
+		mockRows.On("Next").Return(false).Once()
+		mockRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(1).(*string) = "user-1"
+			*args.Get(3).(*int) = 10
+		}).Return(nil).Once()
+		mockRows.On("Err").Return(nil)
+		mockRows.On("Close").Return()
+
+		matches, err := repo.PendingDigest(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, []entities.SearchMatch{{UserID: "user-1", AdID: 10}}, matches)
+	})
+}
+
+func TestSavedSearchRepo_MarkNotified(t *testing.T) {
+	t.Run("exec error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &savedSearchRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.CommandTag{}, errors.New("db error"))
+
+		err := repo.MarkNotified(context.Background(), 1, []int{1, 2})
+		assert.Equal(t, repoerr.ErrSavedSearchNotify, err)
+	})
+}
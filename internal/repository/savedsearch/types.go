@@ -0,0 +1,30 @@
+package savedsearch
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/pkg/db"
+	customLogger "ads-service/pkg/logger"
+	"context"
+)
+
+type SavedSearchRepository interface {
+	Create(ctx context.Context, search *entities.SavedSearch) error
+	GetByUser(ctx context.Context, userID string) ([]entities.SavedSearch, error)
+	CountByUser(ctx context.Context, userID string) (int, error)
+	Delete(ctx context.Context, userID string, id int) error
+	// MatchAd records the ad against every saved search it satisfies and returns only the searches
+	// that had not matched it before. Searches of the ad author are skipped.
+	MatchAd(ctx context.Context, ad *entities.Ad) ([]entities.SavedSearch, error)
+	// PendingDigest returns unsent matches of daily searches whose ads are still published.
+	PendingDigest(ctx context.Context) ([]entities.SearchMatch, error)
+	MarkNotified(ctx context.Context, searchID int, adIDs []int) error
+}
+
+type savedSearchRepo struct {
+	db     db.Pool
+	logger customLogger.Logger
+}
+
+func NewSavedSearchRepo(pool db.Pool, logTool customLogger.Logger) SavedSearchRepository {
+	return &savedSearchRepo{db: pool, logger: logTool}
+}
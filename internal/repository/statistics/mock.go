@@ -0,0 +1,67 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package statistics
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStatisticsRepo struct {
+	mock.Mock
+}
+
+func (m *MockStatisticsRepo) GetSummary(ctx context.Context,
+	filter entities.StatisticsFilter) (entities.ModerationStatistics, error) {
+	args := m.Called(ctx, filter)
+	if summary, ok := args.Get(0).(entities.ModerationStatistics); ok {
+		return summary, args.Error(1)
+	}
+	return entities.ModerationStatistics{}, args.Error(1)
+}
+
+func (m *MockStatisticsRepo) GetSeries(ctx context.Context,
+	filter entities.StatisticsFilter) ([]entities.StatisticsPoint, error) {
+	args := m.Called(ctx, filter)
+	if points, ok := args.Get(0).([]entities.StatisticsPoint); ok {
+		return points, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockStatisticsRepo) GetCategories(ctx context.Context,
+	filter entities.StatisticsFilter) ([]entities.CategoryStatistics, error) {
+	args := m.Called(ctx, filter)
+	if categories, ok := args.Get(0).([]entities.CategoryStatistics); ok {
+		return categories, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockStatisticsRepo) GetModerators(ctx context.Context,
+	filter entities.StatisticsFilter) ([]entities.ModeratorStatistics, error) {
+	args := m.Called(ctx, filter)
+	if moderators, ok := args.Get(0).([]entities.ModeratorStatistics); ok {
+		return moderators, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockStatisticsRepo) GetBacklogAge(ctx context.Context) ([]entities.BacklogBucket, error) {
+	args := m.Called(ctx)
+	if buckets, ok := args.Get(0).([]entities.BacklogBucket); ok {
+		return buckets, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockStatisticsRepo) CountAdsByStatus(ctx context.Context) (map[entities.Status]int, error) {
+	args := m.Called(ctx)
+	if counts, ok := args.Get(0).(map[entities.Status]int); ok {
+		return counts, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+var _ StatisticsRepository = (*MockStatisticsRepo)(nil)
@@ -0,0 +1,218 @@
+package statistics
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"context"
+)
+
+// decisions - approvals and rejections in the period with the wait since the latest submission before them,
+// NULL for ads submitted before submissions were recorded.
+const decisions = `
+	SELECT e.actor_id, e.action, e.created_at - (
+		SELECT MAX(s.created_at) FROM moderation_events s
+		WHERE s.ad_id = e.ad_id AND s.action = 'submitted' AND s.created_at <= e.created_at
+	) AS waited
+	FROM moderation_events e
+	WHERE e.action IN ('approved', 'rejected') AND e.created_at >= $1 AND e.created_at < $2`
+
+// waiting - pending ads with the time of their latest submission. Ads submitted before submissions were
+// recorded fall back to their fingerprint, drafts have neither and are left out.
+const waiting = `
+	SELECT a.id, a.category_id, COALESCE(MAX(e.created_at), f.submitted_at) AS submitted_at
+	FROM ads a
+	LEFT JOIN moderation_events e ON e.ad_id = a.id AND e.action = 'submitted'
+	LEFT JOIN ad_fingerprints f ON f.ad_id = a.id
+	WHERE a.status = 'pending'
+	GROUP BY a.id, a.category_id, f.submitted_at
+	HAVING COALESCE(MAX(e.created_at), f.submitted_at) IS NOT NULL`
+
+// backlogLabels name the buckets of GetBacklogAge in the order of its columns.
+var backlogLabels = []string{"<1h", "1h-1d", "1d-3d", "3d-7d", ">7d"}
+
+func (r *statisticsRepo) GetSummary(ctx context.Context,
+	filter entities.StatisticsFilter) (entities.ModerationStatistics, error) {
+	var summary entities.ModerationStatistics
+	err := r.db.QueryRow(ctx, `
+		WITH decisions AS (`+decisions+`)
+		SELECT
+			COALESCE(COUNT(*) FILTER (WHERE action = 'approved')::float8 / NULLIF(COUNT(*), 0), 0),
+			COALESCE(EXTRACT(EPOCH FROM percentile_cont(0.5) WITHIN GROUP (ORDER BY waited))::float8, 0),
+			COUNT(*),
+			(SELECT COUNT(*) FROM users WHERE created_at >= $1 AND created_at < $2)
+		FROM decisions;`, filter.From, filter.To).
+		Scan(&summary.ApprovalRate, &summary.MedianDecisionSeconds, &summary.Decisions, &summary.NewUsers)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error getting moderation summary: ", err)
+		return entities.ModerationStatistics{}, repoerr.ErrGettingStatistics
+	}
+	return summary, nil
+}
+
+func (r *statisticsRepo) GetSeries(ctx context.Context,
+	filter entities.StatisticsFilter) ([]entities.StatisticsPoint, error) {
+	rows, err := r.db.Query(ctx, `
+		WITH periods AS (
+			SELECT generate_series(date_trunc($3, $1::timestamp), $2::timestamp - interval '1 microsecond',
+				('1 ' || $3)::interval) AS period
+		), activity AS (
+			SELECT date_trunc($3, created_at) AS period,
+				COUNT(*) FILTER (WHERE action = 'submitted') AS submitted,
+				COUNT(*) FILTER (WHERE action = 'approved') AS approved,
+				COUNT(*) FILTER (WHERE action = 'rejected') AS rejected
+			FROM moderation_events
+			WHERE created_at >= $1 AND created_at < $2
+			GROUP BY 1
+		), signups AS (
+			SELECT date_trunc($3, created_at) AS period, COUNT(*) AS new_users
+			FROM users
+			WHERE created_at >= $1 AND created_at < $2
+			GROUP BY 1
+		)
+		SELECT p.period, COALESCE(a.submitted, 0), COALESCE(a.approved, 0), COALESCE(a.rejected, 0),
+			COALESCE(s.new_users, 0)
+		FROM periods p
+		LEFT JOIN activity a ON a.period = p.period
+		LEFT JOIN signups s ON s.period = p.period
+		ORDER BY p.period;`, filter.From, filter.To, string(filter.Granularity))
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error getting statistics series: ", err)
+		return nil, repoerr.ErrGettingStatistics
+	}
+	defer rows.Close()
+
+	var points []entities.StatisticsPoint
+	for rows.Next() {
+		var point entities.StatisticsPoint
+		if err = rows.Scan(&point.Period, &point.Submitted, &point.Approved, &point.Rejected,
+			&point.NewUsers); err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning statistics point: ", err)
+			return nil, repoerr.ErrScan
+		}
+		points = append(points, point)
+	}
+	if err = rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating statistics series: ", err)
+		return nil, repoerr.ErrScan
+	}
+	return points, nil
+}
+
+func (r *statisticsRepo) GetCategories(ctx context.Context,
+	filter entities.StatisticsFilter) ([]entities.CategoryStatistics, error) {
+	rows, err := r.db.Query(ctx, `
+		WITH waiting AS (`+waiting+`), activity AS (
+			SELECT a.category_id,
+				COUNT(*) FILTER (WHERE e.action = 'submitted') AS submitted,
+				COUNT(*) FILTER (WHERE e.action = 'approved') AS approved,
+				COUNT(*) FILTER (WHERE e.action = 'rejected') AS rejected
+			FROM moderation_events e
+			JOIN ads a ON a.id = e.ad_id
+			WHERE e.created_at >= $1 AND e.created_at < $2
+			GROUP BY a.category_id
+		)
+		SELECT c.title, c.id, COALESCE(a.submitted, 0), COALESCE(a.approved, 0), COALESCE(a.rejected, 0),
+			(SELECT COUNT(*) FROM waiting w WHERE w.category_id = c.id)
+		FROM categories c
+		LEFT JOIN activity a ON a.category_id = c.id
+		ORDER BY c.title;`, filter.From, filter.To)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error getting category statistics: ", err)
+		return nil, repoerr.ErrGettingStatistics
+	}
+	defer rows.Close()
+
+	var categories []entities.CategoryStatistics
+	for rows.Next() {
+		var category entities.CategoryStatistics
+		if err = rows.Scan(&category.Title, &category.ID, &category.Submitted, &category.Approved,
+			&category.Rejected, &category.Pending); err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning category statistics: ", err)
+			return nil, repoerr.ErrScan
+		}
+		categories = append(categories, category)
+	}
+	if err = rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating category statistics: ", err)
+		return nil, repoerr.ErrScan
+	}
+	return categories, nil
+}
+
+func (r *statisticsRepo) GetModerators(ctx context.Context,
+	filter entities.StatisticsFilter) ([]entities.ModeratorStatistics, error) {
+	rows, err := r.db.Query(ctx, `
+		WITH decisions AS (`+decisions+`)
+		SELECT COALESCE(actor_id::text, ''),
+			COALESCE(EXTRACT(EPOCH FROM percentile_cont(0.5) WITHIN GROUP (ORDER BY waited))::float8, 0),
+			COUNT(*) FILTER (WHERE action = 'approved'),
+			COUNT(*) FILTER (WHERE action = 'rejected')
+		FROM decisions
+		GROUP BY actor_id
+		ORDER BY COUNT(*) DESC, 1;`, filter.From, filter.To)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error getting moderator statistics: ", err)
+		return nil, repoerr.ErrGettingStatistics
+	}
+	defer rows.Close()
+
+	var moderators []entities.ModeratorStatistics
+	for rows.Next() {
+		var moderator entities.ModeratorStatistics
+		if err = rows.Scan(&moderator.ModeratorID, &moderator.MedianDecisionSeconds, &moderator.Approved,
+			&moderator.Rejected); err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning moderator statistics: ", err)
+			return nil, repoerr.ErrScan
+		}
+		moderators = append(moderators, moderator)
+	}
+	if err = rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating moderator statistics: ", err)
+		return nil, repoerr.ErrScan
+	}
+	return moderators, nil
+}
+
+func (r *statisticsRepo) GetBacklogAge(ctx context.Context) ([]entities.BacklogBucket, error) {
+	counts := make([]int, len(backlogLabels))
+	err := r.db.QueryRow(ctx, `
+		WITH waiting AS (`+waiting+`)
+		SELECT
+			COUNT(*) FILTER (WHERE age < interval '1 hour'),
+			COUNT(*) FILTER (WHERE age >= interval '1 hour' AND age < interval '1 day'),
+			COUNT(*) FILTER (WHERE age >= interval '1 day' AND age < interval '3 days'),
+			COUNT(*) FILTER (WHERE age >= interval '3 days' AND age < interval '7 days'),
+			COUNT(*) FILTER (WHERE age >= interval '7 days')
+		FROM (SELECT LOCALTIMESTAMP - submitted_at AS age FROM waiting) w;`).
+		Scan(&counts[0], &counts[1], &counts[2], &counts[3], &counts[4])
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error getting backlog age: ", err)
+		return nil, repoerr.ErrGettingStatistics
+	}
+
+	buckets := make([]entities.BacklogBucket, 0, len(backlogLabels))
+	for i, label := range backlogLabels {
+		buckets = append(buckets, entities.BacklogBucket{Label: label, Count: counts[i]})
+	}
+	return buckets, nil
+}
+
+func (r *statisticsRepo) CountAdsByStatus(ctx context.Context) (map[entities.Status]int, error) {
+	var pending, approved, rejected int
+	err := r.db.QueryRow(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE status = 'pending'),
+			COUNT(*) FILTER (WHERE status = 'approved'),
+			COUNT(*) FILTER (WHERE status = 'rejected')
+		FROM ads;`).
+		Scan(&pending, &approved, &rejected)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error counting ads by status: ", err)
+		return nil, repoerr.ErrGettingStatistics
+	}
+	return map[entities.Status]int{
+		entities.StatusPending:  pending,
+		entities.StatusApproved: approved,
+		entities.StatusRejected: rejected,
+	}, nil
+}
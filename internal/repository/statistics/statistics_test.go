@@ -0,0 +1,226 @@
+//nolint:all // testpackage
+package statistics
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/pkg/db"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var filter = entities.StatisticsFilter{
+	From:        time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	To:          time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+	Granularity: entities.GranularityWeek,
+}
+
+func TestStatisticsRepo_GetSummary(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &statisticsRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(0).(*float64) = 0.75
+				*args.Get(1).(*float64) = 5400
+				*args.Get(2).(*int) = 8
+				*args.Get(3).(*int) = 3
+			}).Return(nil)
+
+		summary, err := repo.GetSummary(context.Background(), filter)
+		assert.NoError(t, err)
+		assert.Equal(t, entities.ModerationStatistics{ApprovalRate: 0.75, MedianDecisionSeconds: 5400, Decisions: 8,
+			NewUsers: 3}, summary)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+
+		repo := &statisticsRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(errors.New("db error"))
+
+		_, err := repo.GetSummary(context.Background(), filter)
+		assert.Equal(t, repoerr.ErrGettingStatistics, err)
+	})
+}
+
+func TestStatisticsRepo_GetSeries(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRows := new(db.MockRows)
+		defer mockPool.AssertExpectations(t)
+		defer mockRows.AssertExpectations(t)
+
+		repo := &statisticsRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+		mockRows.On("Next").Return(true).Once()
+		mockRows.On("Next").Return(false).Once()
+		mockRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(0).(*time.Time) = filter.From
+				*args.Get(1).(*int) = 4
+				*args.Get(2).(*int) = 2
+				*args.Get(3).(*int) = 1
+			}).Return(nil)
+		mockRows.On("Err").Return(nil)
+		mockRows.On("Close").Return()
+
+		points, err := repo.GetSeries(context.Background(), filter)
+		assert.NoError(t, err)
+		assert.Equal(t, []entities.StatisticsPoint{{Period: filter.From, Submitted: 4, Approved: 2, Rejected: 1}}, points)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+
+		repo := &statisticsRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(new(db.MockRows), errors.New("db error"))
+
+		_, err := repo.GetSeries(context.Background(), filter)
+		assert.Equal(t, repoerr.ErrGettingStatistics, err)
+	})
+}
+
+func TestStatisticsRepo_GetCategories(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRows := new(db.MockRows)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &statisticsRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+		mockRows.On("Next").Return(true).Once()
+		mockRows.On("Next").Return(false).Once()
+		mockRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(0).(*string) = "cars"
+			*args.Get(1).(*int) = 1
+			*args.Get(5).(*int) = 6
+		}).Return(nil)
+		mockRows.On("Err").Return(nil)
+		mockRows.On("Close").Return()
+
+		categories, err := repo.GetCategories(context.Background(), filter)
+		assert.NoError(t, err)
+		assert.Equal(t, []entities.CategoryStatistics{{Title: "cars", ID: 1, Pending: 6}}, categories)
+	})
+
+	t.Run("scan error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRows := new(db.MockRows)
+
+		repo := &statisticsRepo{db: mockPool}
+		mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+		mockRows.On("Next").Return(true).Once()
+		mockRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything).Return(errors.New("scan error"))
+		mockRows.On("Close").Return()
+
+		_, err := repo.GetCategories(context.Background(), filter)
+		assert.Equal(t, repoerr.ErrScan, err)
+	})
+}
+
+func TestStatisticsRepo_GetModerators(t *testing.T) {
+	mockPool := new(db.MockPool)
+	mockRows := new(db.MockRows)
+	defer mockPool.AssertExpectations(t)
+
+	repo := &statisticsRepo{db: mockPool}
+	mockPool.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(mockRows, nil)
+	mockRows.On("Next").Return(true).Twice()
+	mockRows.On("Next").Return(false).Once()
+	mockRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*int) = 3
+		}).Return(nil)
+	mockRows.On("Err").Return(nil)
+	mockRows.On("Close").Return()
+
+	moderators, err := repo.GetModerators(context.Background(), filter)
+	assert.NoError(t, err)
+	assert.Len(t, moderators, 2)
+	assert.Equal(t, 3, moderators[0].Approved)
+}
+
+func TestStatisticsRepo_GetBacklogAge(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &statisticsRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(0).(*int) = 2
+				*args.Get(4).(*int) = 1
+			}).Return(nil)
+
+		buckets, err := repo.GetBacklogAge(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, []entities.BacklogBucket{
+			{Label: "<1h", Count: 2}, {Label: "1h-1d"}, {Label: "1d-3d"}, {Label: "3d-7d"}, {Label: ">7d", Count: 1},
+		}, buckets)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+
+		repo := &statisticsRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(errors.New("db error"))
+
+		_, err := repo.GetBacklogAge(context.Background())
+		assert.Equal(t, repoerr.ErrGettingStatistics, err)
+	})
+}
+
+func TestStatisticsRepo_CountAdsByStatus(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &statisticsRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(0).(*int) = 4
+				*args.Get(1).(*int) = 9
+			}).Return(nil)
+
+		counts, err := repo.CountAdsByStatus(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, map[entities.Status]int{
+			entities.StatusPending: 4, entities.StatusApproved: 9, entities.StatusRejected: 0,
+		}, counts)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+
+		repo := &statisticsRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+		_, err := repo.CountAdsByStatus(context.Background())
+		assert.Equal(t, repoerr.ErrGettingStatistics, err)
+	})
+}
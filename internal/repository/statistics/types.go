@@ -0,0 +1,32 @@
+package statistics
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/pkg/db"
+	customLogger "ads-service/pkg/logger"
+	"context"
+)
+
+// StatisticsRepository aggregates the moderation history, ads and users for the period of the filter.
+type StatisticsRepository interface {
+	// GetSummary fills the decision counters, the approval rate, the median decision time and new users.
+	GetSummary(ctx context.Context, filter entities.StatisticsFilter) (entities.ModerationStatistics, error)
+	// GetSeries returns a point for every period between From and To, empty periods included.
+	GetSeries(ctx context.Context, filter entities.StatisticsFilter) ([]entities.StatisticsPoint, error)
+	// GetCategories returns every category ordered by title.
+	GetCategories(ctx context.Context, filter entities.StatisticsFilter) ([]entities.CategoryStatistics, error)
+	// GetModerators returns the moderators with decisions in the period, the busiest first.
+	GetModerators(ctx context.Context, filter entities.StatisticsFilter) ([]entities.ModeratorStatistics, error)
+	GetBacklogAge(ctx context.Context) ([]entities.BacklogBucket, error)
+	// CountAdsByStatus returns the number of ads in every status, drafts are pending.
+	CountAdsByStatus(ctx context.Context) (map[entities.Status]int, error)
+}
+
+type statisticsRepo struct {
+	db     db.Pool
+	logger customLogger.Logger
+}
+
+func NewStatisticsRepo(pool db.Pool, logTool customLogger.Logger) StatisticsRepository {
+	return &statisticsRepo{db: pool, logger: logTool}
+}
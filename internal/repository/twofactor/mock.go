@@ -0,0 +1,48 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package twofactor
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTwoFactorRepo struct {
+	mock.Mock
+}
+
+func (m *MockTwoFactorRepo) Get(ctx context.Context, userID string) (*entities.TOTP, error) {
+	args := m.Called(ctx, userID)
+	if totp, ok := args.Get(0).(*entities.TOTP); ok {
+		return totp, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTwoFactorRepo) Save(ctx context.Context, totp *entities.TOTP) error {
+	args := m.Called(ctx, totp)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepo) Delete(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepo) MarkUsed(ctx context.Context, userID string, step int64) error {
+	args := m.Called(ctx, userID, step)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepo) ReplaceRecoveryCodes(ctx context.Context, userID string, hashes []string) error {
+	args := m.Called(ctx, userID, hashes)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepo) UseRecoveryCode(ctx context.Context, userID, hash string) error {
+	args := m.Called(ctx, userID, hash)
+	return args.Error(0)
+}
+
+var _ TwoFactorRepository = (*MockTwoFactorRepo)(nil)
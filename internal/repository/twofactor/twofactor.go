@@ -0,0 +1,113 @@
+package twofactor
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (r *twoFactorRepo) Get(ctx context.Context, userID string) (*entities.TOTP, error) {
+	var (
+		totp        entities.TOTP
+		confirmedAt *time.Time
+	)
+	err := r.db.QueryRow(ctx, `
+		SELECT user_id, secret, enabled, last_used_step, created_at, confirmed_at
+		FROM user_totp
+		WHERE user_id = $1`, userID).
+		Scan(&totp.UserID, &totp.Secret, &totp.Enabled, &totp.LastUsedStep, &totp.CreatedAt, &confirmedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repoerr.ErrTOTPNotFound
+		}
+		r.logger.ErrorContext(ctx, "Error selecting two-factor settings: ", err)
+		return nil, repoerr.ErrTOTPSelect
+	}
+	if confirmedAt != nil {
+		totp.ConfirmedAt = *confirmedAt
+	}
+	return &totp, nil
+}
+
+func (r *twoFactorRepo) Save(ctx context.Context, totp *entities.TOTP) error {
+	var confirmedAt *time.Time
+	if !totp.ConfirmedAt.IsZero() {
+		confirmedAt = &totp.ConfirmedAt
+	}
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO user_totp(user_id, secret, enabled, last_used_step, created_at, confirmed_at)
+		VALUES($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret,
+			enabled = EXCLUDED.enabled,
+			last_used_step = EXCLUDED.last_used_step,
+			created_at = EXCLUDED.created_at,
+			confirmed_at = EXCLUDED.confirmed_at;`,
+		totp.UserID, totp.Secret, totp.Enabled, totp.LastUsedStep, totp.CreatedAt, confirmedAt)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error saving two-factor settings: ", err)
+		return repoerr.ErrTOTPSave
+	}
+	r.logger.InfoContext(ctx, "Two-factor settings saved for user: ", totp.UserID)
+	return nil
+}
+
+func (r *twoFactorRepo) Delete(ctx context.Context, userID string) error {
+	_, err := r.db.Exec(ctx, `
+		WITH codes AS (DELETE FROM totp_recovery_codes WHERE user_id = $1)
+		DELETE FROM user_totp WHERE user_id = $1;`, userID)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error deleting two-factor settings: ", err)
+		return repoerr.ErrTOTPDelete
+	}
+	r.logger.InfoContext(ctx, "Two-factor settings deleted for user: ", userID)
+	return nil
+}
+
+func (r *twoFactorRepo) MarkUsed(ctx context.Context, userID string, step int64) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE user_totp
+		SET last_used_step = $2
+		WHERE user_id = $1 AND last_used_step < $2;`, userID, step)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error updating two-factor step: ", err)
+		return repoerr.ErrTOTPSave
+	}
+	if tag.RowsAffected() == 0 {
+		return repoerr.ErrTOTPCodeReused
+	}
+	return nil
+}
+
+func (r *twoFactorRepo) ReplaceRecoveryCodes(ctx context.Context, userID string, hashes []string) error {
+	_, err := r.db.Exec(ctx, `
+		WITH old AS (DELETE FROM totp_recovery_codes WHERE user_id = $1)
+		INSERT INTO totp_recovery_codes(user_id, code_hash)
+		SELECT $1, unnest($2::text[]);`, userID, hashes)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error saving recovery codes: ", err)
+		return repoerr.ErrRecoveryCodesSave
+	}
+	r.logger.InfoContext(ctx, "Recovery codes replaced for user: ", userID)
+	return nil
+}
+
+func (r *twoFactorRepo) UseRecoveryCode(ctx context.Context, userID, hash string) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE totp_recovery_codes
+		SET used_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;`, userID, hash)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error using recovery code: ", err)
+		return repoerr.ErrRecoveryCodeUse
+	}
+	if tag.RowsAffected() == 0 {
+		return repoerr.ErrRecoveryCodeNotFound
+	}
+	r.logger.InfoContext(ctx, "Recovery code used by user: ", userID)
+	return nil
+}
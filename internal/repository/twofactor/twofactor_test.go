@@ -0,0 +1,225 @@
+//nolint:all // testpackage
+package twofactor
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/pkg/db"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTwoFactorRepo_Get(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &twoFactorRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(pgx.ErrNoRows)
+
+		totp, err := repo.Get(context.Background(), "user-1")
+		assert.Nil(t, totp)
+		assert.Equal(t, repoerr.ErrTOTPNotFound, err)
+	})
+
+	t.Run("select error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &twoFactorRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(errors.New("db error"))
+
+		totp, err := repo.Get(context.Background(), "user-1")
+		assert.Nil(t, totp)
+		assert.Equal(t, repoerr.ErrTOTPSelect, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &twoFactorRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil)
+
+		totp, err := repo.Get(context.Background(), "user-1")
+		assert.NoError(t, err)
+		assert.NotNil(t, totp)
+	})
+}
+
+func TestTwoFactorRepo_Save(t *testing.T) {
+	t.Run("exec error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &twoFactorRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.CommandTag{}, errors.New("db error"))
+
+		err := repo.Save(context.Background(), &entities.TOTP{UserID: "user-1"})
+		assert.Equal(t, repoerr.ErrTOTPSave, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &twoFactorRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+
+		err := repo.Save(context.Background(), &entities.TOTP{
+			UserID:      "user-1",
+			Enabled:     true,
+			ConfirmedAt: time.Now(),
+		})
+		assert.NoError(t, err)
+	})
+}
+
+func TestTwoFactorRepo_MarkUsed(t *testing.T) {
+	t.Run("exec error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &twoFactorRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.CommandTag{}, errors.New("db error"))
+
+		err := repo.MarkUsed(context.Background(), "user-1", 100)
+		assert.Equal(t, repoerr.ErrTOTPSave, err)
+	})
+
+	t.Run("code reused", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &twoFactorRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("UPDATE 0"), nil)
+
+		err := repo.MarkUsed(context.Background(), "user-1", 100)
+		assert.Equal(t, repoerr.ErrTOTPCodeReused, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &twoFactorRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+
+		err := repo.MarkUsed(context.Background(), "user-1", 100)
+		assert.NoError(t, err)
+	})
+}
+
+func TestTwoFactorRepo_Delete(t *testing.T) {
+	t.Run("exec error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &twoFactorRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.CommandTag{}, errors.New("db error"))
+
+		err := repo.Delete(context.Background(), "user-1")
+		assert.Equal(t, repoerr.ErrTOTPDelete, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &twoFactorRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("DELETE 1"), nil)
+
+		err := repo.Delete(context.Background(), "user-1")
+		assert.NoError(t, err)
+	})
+}
+
+func TestTwoFactorRepo_ReplaceRecoveryCodes(t *testing.T) {
+	t.Run("exec error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &twoFactorRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.CommandTag{}, errors.New("db error"))
+
+		err := repo.ReplaceRecoveryCodes(context.Background(), "user-1", []string{"a", "b"})
+		assert.Equal(t, repoerr.ErrRecoveryCodesSave, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &twoFactorRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("INSERT 0 2"), nil)
+
+		err := repo.ReplaceRecoveryCodes(context.Background(), "user-1", []string{"a", "b"})
+		assert.NoError(t, err)
+	})
+}
+
+func TestTwoFactorRepo_UseRecoveryCode(t *testing.T) {
+	t.Run("exec error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &twoFactorRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.CommandTag{}, errors.New("db error"))
+
+		err := repo.UseRecoveryCode(context.Background(), "user-1", "hash")
+		assert.Equal(t, repoerr.ErrRecoveryCodeUse, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &twoFactorRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("UPDATE 0"), nil)
+
+		err := repo.UseRecoveryCode(context.Background(), "user-1", "hash")
+		assert.Equal(t, repoerr.ErrRecoveryCodeNotFound, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &twoFactorRepo{db: mockPool}
+		mockPool.On("Exec", mock.Anything, mock.Anything, mock.Anything).
+			Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+
+		err := repo.UseRecoveryCode(context.Background(), "user-1", "hash")
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,27 @@
+package twofactor
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/pkg/db"
+	customLogger "ads-service/pkg/logger"
+	"context"
+)
+
+type TwoFactorRepository interface {
+	Get(ctx context.Context, userID string) (*entities.TOTP, error)
+	Save(ctx context.Context, totp *entities.TOTP) error
+	Delete(ctx context.Context, userID string) error
+	// MarkUsed stores the last accepted time step and fails if it is not newer than the stored one.
+	MarkUsed(ctx context.Context, userID string, step int64) error
+	ReplaceRecoveryCodes(ctx context.Context, userID string, hashes []string) error
+	UseRecoveryCode(ctx context.Context, userID, hash string) error
+}
+
+type twoFactorRepo struct {
+	db     db.Pool
+	logger customLogger.Logger
+}
+
+func NewTwoFactorRepo(pool db.Pool, logTool customLogger.Logger) TwoFactorRepository {
+	return &twoFactorRepo{db: pool, logger: logTool}
+}
@@ -61,6 +61,11 @@ func (m *MockUserRepo) IsExists(ctx context.Context, phone string) (bool, error)
 	return false, args.Error(1)
 }
 
+func (m *MockUserRepo) Ban(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
 func (m *MockUserRepo) Create(ctx context.Context, ad *entities.Ad) error {
 	args := m.Called(ctx, ad)
 	return args.Error(0)
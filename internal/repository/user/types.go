@@ -15,6 +15,8 @@ type UserRepository interface {
 	UpdateUser(ctx context.Context, user *entities.User) error
 	DeleteUser(ctx context.Context, userID string) error
 	IsExists(ctx context.Context, phone string) (bool, error)
+	// Ban blocks the login, ends sessions and API keys and takes the user's ads out of the catalog.
+	Ban(ctx context.Context, userID string) error
 }
 
 type userRepo struct {
@@ -5,6 +5,7 @@ import (
 	"ads-service/internal/errs/repoerr"
 	"context"
 	"errors"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 )
@@ -20,10 +21,10 @@ func (r *userRepo) CreateUser(ctx context.Context, user *entities.User) (string,
 		user.FName, user.LName, user.Phone, user.PasswordHash).
 		Scan(&user.ID)
 	if err != nil {
-		r.logger.ERROR("Failed to create user")
+		r.logger.ErrorContext(ctx, "Failed to create user")
 		return "", repoerr.ErrCreationUser
 	}
-	r.logger.INFO("Create user successfully")
+	r.logger.InfoContext(ctx, "Create user successfully")
 
 	return user.ID, nil
 }
@@ -31,24 +32,30 @@ func (r *userRepo) CreateUser(ctx context.Context, user *entities.User) (string,
 func (r *userRepo) GetByPhone(ctx context.Context, phone string) (*entities.User, error) {
 	selectQuery := `
 		SELECT id, first_name, last_name, phone, 
-		       role, password_hash, created_at, updated_at
+		       role, password_hash, created_at, updated_at, banned_at
 		FROM users
 		WHERE phone = $1`
 
 	row := r.db.QueryRow(ctx, selectQuery, phone)
-	var user entities.User
+	var (
+		user     entities.User
+		bannedAt *time.Time
+	)
 	err := row.Scan(&user.ID, &user.FName, &user.LName, &user.Phone, &user.Role,
-		&user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
+		&user.PasswordHash, &user.CreatedAt, &user.UpdatedAt, &bannedAt)
 	if err != nil {
-		r.logger.ERROR("Error selecting user by phone:", err)
+		r.logger.ErrorContext(ctx, "Error selecting user by phone:", err)
 		if errors.Is(err, pgx.ErrNoRows) {
-			r.logger.ERROR("User not found")
-			return nil, pgx.ErrNoRows
+			r.logger.ErrorContext(ctx, "User not found")
+			return nil, repoerr.ErrUserNotFound
 		}
-		r.logger.ERROR("Error selecting user by phone: ", err)
+		r.logger.ErrorContext(ctx, "Error selecting user by phone: ", err)
 		return nil, repoerr.ErrScan
 	}
-	r.logger.INFO("User successfully retrieved")
+	if bannedAt != nil {
+		user.BannedAt = *bannedAt
+	}
+	r.logger.InfoContext(ctx, "User successfully retrieved")
 	return &user, nil
 }
 
@@ -58,10 +65,10 @@ func (r *userRepo) IsExists(ctx context.Context, phone string) (bool, error) {
 	var count int
 	err := row.Scan(&count)
 	if err != nil {
-		r.logger.ERROR("Error checking user existence:", err)
+		r.logger.ErrorContext(ctx, "Error checking user existence:", err)
 		return false, repoerr.ErrScan
 	}
-	r.logger.INFO("Number of users found:", count)
+	r.logger.InfoContext(ctx, "Number of users found:", count)
 	return count > 0, nil
 }
 
@@ -70,7 +77,7 @@ func (r *userRepo) GetAllUser(ctx context.Context) ([]entities.User, error) {
 		SELECT id, first_name, last_name, phone, role
 		FROM users`)
 	if err != nil {
-		r.logger.ERROR("Error getting users:", err)
+		r.logger.ErrorContext(ctx, "Error getting users:", err)
 		return nil, repoerr.ErrSelection
 	}
 	defer rows.Close()
@@ -80,36 +87,42 @@ func (r *userRepo) GetAllUser(ctx context.Context) ([]entities.User, error) {
 		var user entities.User
 		if err = rows.Scan(&user.ID, &user.FName, &user.LName, &user.Phone,
 			&user.Role); err != nil {
-			r.logger.ERROR("Error scanning users:", err)
+			r.logger.ErrorContext(ctx, "Error scanning users:", err)
 			return nil, repoerr.ErrScan
 		}
 		users = append(users, user)
 	}
 
 	if err = rows.Err(); err != nil {
-		r.logger.ERROR("Error iterating rows:", err)
+		r.logger.ErrorContext(ctx, "Error iterating rows:", err)
 		return nil, repoerr.ErrScan
 	}
-	r.logger.INFO("Users successfully retrieved")
+	r.logger.InfoContext(ctx, "Users successfully retrieved")
 	return users, nil
 }
 
 func (r *userRepo) GetUserByID(ctx context.Context, userID string) (*entities.User, error) {
-	var user entities.User
+	var (
+		user     entities.User
+		bannedAt *time.Time
+	)
 	err := r.db.QueryRow(ctx, `
-		SELECT id, first_name, last_name, phone, role
+		SELECT id, first_name, last_name, phone, role, banned_at
 		FROM users
 		WHERE id = $1`, userID).
-		Scan(&user.ID, &user.FName, &user.LName, &user.Phone, &user.Role)
+		Scan(&user.ID, &user.FName, &user.LName, &user.Phone, &user.Role, &bannedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			r.logger.ERROR("No user found with ID:", userID)
+			r.logger.ErrorContext(ctx, "No user found with ID:", userID)
 			return nil, repoerr.ErrUserNotFound
 		}
-		r.logger.ERROR("Error selecting user:", err)
+		r.logger.ErrorContext(ctx, "Error selecting user:", err)
 		return nil, repoerr.ErrSelection
 	}
-	r.logger.INFO("User successfully retrieved by ID: ", userID)
+	if bannedAt != nil {
+		user.BannedAt = *bannedAt
+	}
+	r.logger.InfoContext(ctx, "User successfully retrieved by ID: ", userID)
 
 	return &user, nil
 }
@@ -121,10 +134,10 @@ func (r *userRepo) UpdateUser(ctx context.Context, user *entities.User) error {
 		WHERE id = $5;`,
 		user.FName, user.LName, user.Phone, user.Role, user.ID)
 	if err != nil {
-		r.logger.ERROR("Error updating user:", err)
+		r.logger.ErrorContext(ctx, "Error updating user:", err)
 		return repoerr.ErrUpdate
 	}
-	r.logger.INFO("User successfully updated")
+	r.logger.InfoContext(ctx, "User successfully updated")
 	return nil
 }
 
@@ -133,13 +146,42 @@ func (r *userRepo) DeleteUser(ctx context.Context, userID string) error {
 		DELETE FROM users
 		WHERE id = $1;`, userID)
 	if err != nil {
-		r.logger.ERROR("Error deleting user:", err)
+		r.logger.ErrorContext(ctx, "Error deleting user:", err)
 		return repoerr.ErrDelete
 	}
 	if result.RowsAffected() == 0 {
-		r.logger.ERROR("No user found with ID:", userID)
+		r.logger.ErrorContext(ctx, "No user found with ID:", userID)
+		return repoerr.ErrUserNotFound
+	}
+	r.logger.InfoContext(ctx, "User successfully deleted")
+	return nil
+}
+
+func (r *userRepo) Ban(ctx context.Context, userID string) error {
+	var banned bool
+	// one statement, so a half-banned user with live sessions or published ads is not possible
+	err := r.db.QueryRow(ctx, `
+		WITH banned AS (
+			UPDATE users SET banned_at = COALESCE(banned_at, CURRENT_TIMESTAMP)
+			WHERE id = $1
+			RETURNING id
+		), sessions AS (
+			DELETE FROM refresh_tokens WHERE user_id = $1
+		), keys AS (
+			UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP
+			WHERE user_id = $1 AND revoked_at IS NULL
+		), hidden AS (
+			UPDATE ads SET is_active = FALSE, updated_at = CURRENT_TIMESTAMP, version = version + 1
+			WHERE author_id = $1 AND is_active
+		)
+		SELECT EXISTS (SELECT 1 FROM banned)`, userID).Scan(&banned)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error banning user:", err)
+		return repoerr.ErrUserBan
+	}
+	if !banned {
 		return repoerr.ErrUserNotFound
 	}
-	r.logger.INFO("User successfully deleted")
+	r.logger.InfoContext(ctx, "User banned: ", userID)
 	return nil
 }
@@ -101,7 +101,7 @@ func TestUserRepo_GetByPhone(t *testing.T) {
 		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).
 			Return(mockRow)
 		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
-			mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(errors.New("db error"))
 
 		_, err := pool.GetByPhone(context.Background(), "1234567890")
@@ -120,12 +120,12 @@ func TestUserRepo_GetByPhone(t *testing.T) {
 		mockPool.On("QueryRow", mock.Anything, mock.Anything,
 			[]interface{}{"9876543210"}).Return(mockRow)
 		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
-			mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(pgx.ErrNoRows)
 
 		user, err := pool.GetByPhone(context.Background(), phone)
 		assert.Nil(t, user)
-		assert.Equal(t, pgx.ErrNoRows, err)
+		assert.Equal(t, repoerr.ErrUserNotFound, err)
 	})
 
 	t.Run("user not found by phone", func(t *testing.T) {
@@ -139,12 +139,12 @@ func TestUserRepo_GetByPhone(t *testing.T) {
 		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).
 			Return(mockRow)
 		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
-			mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(pgx.ErrNoRows)
 
 		user, err := pool.GetByPhone(context.Background(), "1234567890")
 		assert.Nil(t, user)
-		assert.Equal(t, pgx.ErrNoRows, err)
+		assert.Equal(t, repoerr.ErrUserNotFound, err)
 	})
 
 	t.Run("successful getting user by phone", func(t *testing.T) {
@@ -169,7 +169,7 @@ func TestUserRepo_GetByPhone(t *testing.T) {
 			[]interface{}{"1234567890"}).
 			Return(mockRow)
 		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
-			mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Run(func(args mock.Arguments) {
 				*(args[0].(*string)) = expectedUser.ID
 				*(args[1].(*string)) = expectedUser.FName
@@ -453,7 +453,7 @@ func TestUserRepo_GetUserByID(t *testing.T) {
 		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).
 			Return(mockRow)
 		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
-			mock.Anything).Return(errors.New("db error"))
+			mock.Anything, mock.Anything).Return(errors.New("db error"))
 
 		user, err := pool.GetUserByID(context.Background(), "test-id")
 		assert.Nil(t, user)
@@ -472,7 +472,7 @@ func TestUserRepo_GetUserByID(t *testing.T) {
 		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).
 			Return(mockRow)
 		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
-			mock.Anything).Return(pgx.ErrNoRows)
+			mock.Anything, mock.Anything).Return(pgx.ErrNoRows)
 
 		user, err := pool.GetUserByID(context.Background(), "test-id")
 		assert.Nil(t, user)
@@ -492,7 +492,8 @@ func TestUserRepo_GetUserByID(t *testing.T) {
 				return len(args) == 1 && args[0] == "id"
 			}),
 		).Return(mockRow)
-		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything).
 			Return(pgx.ErrNoRows)
 
 		user, err := pool.GetUserByID(context.Background(), id)
@@ -520,7 +521,8 @@ func TestUserRepo_GetUserByID(t *testing.T) {
 		mockPool.On("QueryRow", mock.Anything, mock.Anything,
 			[]interface{}{"test-id"}).
 			Return(mockRow)
-		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything).
 			Run(func(args mock.Arguments) {
 				*(args[0].(*string)) = expectedUser.ID
 				*(args[1].(*string)) = expectedUser.FName
@@ -699,12 +701,12 @@ func TestUserRepo_GetByPhone_Args(t *testing.T) {
 		mockPool.On("QueryRow", mock.Anything, mock.Anything,
 			[]interface{}{"9876543210"}).Return(mockRow)
 		mockRow.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
-			mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(pgx.ErrNoRows)
 
 		user, err := pool.GetByPhone(context.Background(), phone)
 		assert.Nil(t, user)
-		assert.Equal(t, pgx.ErrNoRows, err)
+		assert.Equal(t, repoerr.ErrUserNotFound, err)
 	})
 }
 
@@ -729,3 +731,44 @@ func TestUserRepo_IsExists_Args(t *testing.T) {
 		assert.True(t, exists)
 	})
 }
+
+func TestUserRepo_Ban(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+		defer mockRow.AssertExpectations(t)
+
+		repo := &userRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(0).(*bool) = true
+		}).Return(nil)
+
+		assert.NoError(t, repo.Ban(context.Background(), "user-id"))
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &userRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything).Return(nil)
+
+		assert.Equal(t, repoerr.ErrUserNotFound, repo.Ban(context.Background(), "user-id"))
+	})
+
+	t.Run("db error", func(t *testing.T) {
+		mockPool := new(db.MockPool)
+		mockRow := new(db.MockRow)
+		defer mockPool.AssertExpectations(t)
+
+		repo := &userRepo{db: mockPool}
+		mockPool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(mockRow)
+		mockRow.On("Scan", mock.Anything).Return(errors.New("db error"))
+
+		assert.Equal(t, repoerr.ErrUserBan, repo.Ban(context.Background(), "user-id"))
+	})
+}
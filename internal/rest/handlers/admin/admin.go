@@ -1,6 +1,10 @@
 package admin
 
 import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs"
+	"ads-service/internal/rest/middleware"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -13,14 +17,13 @@ import (
 // @Tags admin
 // @Produce json
 // @Success 200 {object} map[string]interface{}
-// @Failure 500 {object} map[string]string
+// @Failure 500 {object} middleware.Problem
 // @Router /admin/ads [get]
 // @Security BearerAuth
 func (h *AdminHandler) GetAllAds(c *gin.Context) {
 	ads, err := h.adminService.GetAllAds(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "failed to get ads: " + err.Error()})
+		_ = c.Error(fmt.Errorf("failed to get ads: %w", err))
 		return
 	}
 
@@ -29,23 +32,63 @@ func (h *AdminHandler) GetAllAds(c *gin.Context) {
 
 // GetStatistics godoc
 // @Summary Get statistics
-// @Description Get aggregated system statistics (admin only)
+// @Description Counters of all ads and moderation statistics of a period (admin only): a time series of
+// @Description submissions, decisions and signups, breakdowns by category and moderator, the approval rate,
+// @Description the median time from submission to decision and the age of the current backlog.
 // @Tags admin
 // @Produce json
+// @Param from query string false "First day, YYYY-MM-DD, 30 days before to by default"
+// @Param to query string false "Last day included, YYYY-MM-DD, today by default"
+// @Param granularity query string false "day (default), week or month"
 // @Success 200 {object} map[string]interface{}
-// @Failure 500 {object} map[string]string
-// @Router /admin/statistics [get]
+// @Failure 400 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /admin/stats [get]
 // @Security BearerAuth
 func (h *AdminHandler) GetStatistics(c *gin.Context) {
-	stats, err := h.adminService.GetStatistics(c.Request.Context())
+	filter, err := middleware.StatisticsFilter(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "failed to get statistics: " + err.Error()})
+		_ = c.Error(err)
+		return
+	}
+
+	stats, err := h.adminService.GetStatistics(c.Request.Context(), filter)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to get statistics: %w", err))
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"statistics": stats})
 }
 
+// GetAd godoc
+// @Summary Get ad by ID
+// @Description Ad in any status (admin only). The ETag names the version of the ad, decisions send it back in If-Match
+// @Tags admin
+// @Param id path int true "Ad ID"
+// @Produce json
+// @Success 200 {object} entities.Ad
+// @Header 200 {string} ETag "version of the ad"
+// @Failure 400 {object} middleware.Problem
+// @Failure 404 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /admin/ads/{id} [get]
+// @Security BearerAuth
+func (h *AdminHandler) GetAd(c *gin.Context) {
+	adID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || adID <= 0 {
+		_ = c.Error(errs.Invalid("id", "invalid ad id"))
+		return
+	}
+
+	ad, err := h.adminService.GetAd(c.Request.Context(), adID)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to get ad: %w", err))
+		return
+	}
+	c.Header("ETag", middleware.ETag(ad.Version))
+	c.JSON(http.StatusOK, ad)
+}
+
 // DeleteAd удаляет объявление по ID (только для администратора)
 // @Summary Delete ad by ID
 // @Description Permanently deletes an ad by its ID. Admin only.
@@ -53,21 +96,19 @@ func (h *AdminHandler) GetStatistics(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Ad ID"
 // @Success 200 {object} map[string]string "ad deleted"
-// @Failure 400 {object} map[string]string "invalid ad id"
-// @Failure 500 {object} map[string]string "failed to delete ad"
+// @Failure 400 {object} middleware.Problem "invalid ad id"
+// @Failure 500 {object} middleware.Problem "failed to delete ad"
 // @Security BearerAuth
 // @Router /api/v1/admin/ads/{id} [delete]
 func (h *AdminHandler) DeleteAd(c *gin.Context) {
 	adID, err := strconv.Atoi(c.Param("id"))
 	if err != nil || adID <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid ad id"})
+		_ = c.Error(errs.Invalid("id", "invalid ad id"))
 		return
 	}
 
 	if err := h.adminService.DeleteAd(c.Request.Context(), adID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "failed to delete ad: " + err.Error()})
+		_ = c.Error(fmt.Errorf("failed to delete ad: %w", err))
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "ad deleted"})
@@ -75,25 +116,33 @@ func (h *AdminHandler) DeleteAd(c *gin.Context) {
 
 // Approve godoc
 // @Summary Approve ad
-// @Description Approve ad by ID (admin only)
+// @Description Approve ad by ID (admin only). A stale If-Match gets 412 with the current ad and its ETag
 // @Tags admin
 // @Param id path int true "Ad ID"
+// @Param If-Match header string true "ETag of the ad"
 // @Produce json
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} middleware.Problem
+// @Failure 412 {object} entities.Ad
+// @Failure 428 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
 // @Router /admin/ads/{id}/approve [post]
 // @Security BearerAuth
 func (h *AdminHandler) Approve(c *gin.Context) {
 	adID, err := strconv.Atoi(c.Param("id"))
 	if err != nil || adID <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ad id"})
+		_ = c.Error(errs.Invalid("id", "invalid ad id"))
 		return
 	}
 
-	if err := h.adminService.Approve(c.Request.Context(), adID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "failed to approve ad: " + err.Error()})
+	version, err := middleware.IfMatch(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err := h.adminService.Approve(c.Request.Context(), adID, version, c.GetString("user_id")); err != nil {
+		_ = c.Error(fmt.Errorf("failed to approve ad: %w", err))
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "ad approved"})
@@ -101,34 +150,75 @@ func (h *AdminHandler) Approve(c *gin.Context) {
 
 // Reject godoc
 // @Summary Reject ad
-// @Description Reject ad by ID with a reason (admin only)
+// @Description Reject ad by ID with a rejection template code and an optional note (admin only).
+// @Description A stale If-Match gets 412 with the current ad and its ETag
 // @Tags admin
 // @Param id path int true "Ad ID"
+// @Param If-Match header string true "ETag of the ad"
 // @Accept json
 // @Produce json
-// @Param rejection body RejectionRequest true "Rejection reason"
+// @Param rejection body RejectionRequest true "Rejection code and note"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} middleware.Problem
+// @Failure 412 {object} entities.Ad
+// @Failure 428 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
 // @Router /admin/ads/{id}/reject [post]
 // @Security BearerAuth
 func (h *AdminHandler) Reject(c *gin.Context) {
 	var req RejectionRequest
 	adID, err := strconv.Atoi(c.Param("id"))
 	if err != nil || adID <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ad id"})
+		_ = c.Error(errs.Invalid("id", "invalid ad id"))
+		return
+	}
+	version, err := middleware.IfMatch(c)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "rejection reason required"})
+		_ = c.Error(fmt.Errorf("%w: %w", errs.ErrInvalidBody, err))
 		return
 	}
 
-	if err := h.adminService.Reject(c.Request.Context(), adID, req.Reason); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "failed to reject ad: " + err.Error()})
+	rejection := entities.Rejection{Code: req.Code, Note: req.Note}
+	err = h.adminService.Reject(c.Request.Context(), adID, version, c.GetString("user_id"), rejection)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to reject ad: %w", err))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "ad rejected"})
 }
+
+// GetHistory godoc
+// @Summary Moderation history of an ad
+// @Description Submissions, approvals, rejections and appeals of the ad, oldest first.
+// @Description actor_id is empty for pre-moderation
+// @Tags admin
+// @Param id path int true "Ad ID"
+// @Produce json
+// @Success 200 {array} HistoryResponse
+// @Failure 400 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /admin/ads/{id}/history [get]
+// @Security BearerAuth
+func (h *AdminHandler) GetHistory(c *gin.Context) {
+	adID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || adID <= 0 {
+		_ = c.Error(errs.Invalid("id", "invalid ad id"))
+		return
+	}
+
+	events, err := h.adminService.GetHistory(c.Request.Context(), adID)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to get moderation history: %w", err))
+		return
+	}
+	resp := make([]HistoryResponse, 0, len(events))
+	for i := range events {
+		resp = append(resp, newHistoryResponse(&events[i]))
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -3,6 +3,9 @@ package admin
 
 import (
 	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/rest/handlers/handlertest"
 	"ads-service/internal/usecase/admin"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -11,6 +14,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestAdminHandler_Approve(t *testing.T) {
@@ -19,16 +23,18 @@ func TestAdminHandler_Approve(t *testing.T) {
 		handler := NewAdminHandler(mockService)
 		defer mockService.AssertExpectations(t)
 
-		mockService.On("Approve", mock.Anything, 1).Return(nil)
+		mockService.On("Approve", mock.Anything, 1, 1, "admin").Return(nil)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "admin")
 		c.Params = gin.Params{
 			{Key: "id", Value: "1"},
 		}
 
 		c.Request = httptest.NewRequest(http.MethodPost, "/admin/ads/1/approve", nil)
-		handler.Approve(c)
+		c.Request.Header.Set("If-Match", `"1"`)
+		handlertest.Serve(c, handler.Approve)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Equal(t, `{"message":"ad approved"}`, w.Body.String())
@@ -41,11 +47,12 @@ func TestAdminHandler_Approve(t *testing.T) {
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "admin")
 		c.Params = gin.Params{
 			{Key: "id", Value: "abc"},
 		}
 		c.Request = httptest.NewRequest(http.MethodPost, "/admin/ads/abc/approve", nil)
-		handler.Approve(c)
+		handlertest.Serve(c, handler.Approve)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		assert.Contains(t, w.Body.String(), "invalid ad id")
@@ -56,19 +63,82 @@ func TestAdminHandler_Approve(t *testing.T) {
 		handler := NewAdminHandler(mockService)
 		defer mockService.AssertExpectations(t)
 
-		mockService.On("Approve", mock.Anything, 2).Return(assert.AnError)
+		mockService.On("Approve", mock.Anything, 2, 1, "admin").Return(assert.AnError)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "admin")
 		c.Params = gin.Params{
 			{Key: "id", Value: "2"},
 		}
 		c.Request = httptest.NewRequest(http.MethodPost, "/admin/ads/2/approve", nil)
-		handler.Approve(c)
+		c.Request.Header.Set("If-Match", `"1"`)
+		handlertest.Serve(c, handler.Approve)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
-		assert.Contains(t, w.Body.String(), "failed to approve ad")
+		assert.Contains(t, w.Body.String(), `"code":"internal"`)
 	})
+
+	t.Run("missing if-match", func(t *testing.T) {
+		mockService := new(admin.MockAdminService)
+		handler := NewAdminHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "admin")
+		c.Params = gin.Params{
+			{Key: "id", Value: "1"},
+		}
+		c.Request = httptest.NewRequest(http.MethodPost, "/admin/ads/1/approve", nil)
+		handlertest.Serve(c, handler.Approve)
+
+		assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"if_match_required"`)
+	})
+
+	t.Run("stale version", func(t *testing.T) {
+		mockService := new(admin.MockAdminService)
+		handler := NewAdminHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		current := &entities.Ad{ID: 1, Status: entities.StatusRejected, Version: 2}
+		mockService.On("Approve", mock.Anything, 1, 1, "admin").
+			Return(errs.NewStale(usecaseerr.ErrAdModified, current, current.Version))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "admin")
+		c.Params = gin.Params{
+			{Key: "id", Value: "1"},
+		}
+		c.Request = httptest.NewRequest(http.MethodPost, "/admin/ads/1/approve", nil)
+		c.Request.Header.Set("If-Match", `"1"`)
+		handlertest.Serve(c, handler.Approve)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+		assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+		assert.Contains(t, w.Body.String(), `"Version":2`)
+	})
+}
+
+func TestAdminHandler_GetAd(t *testing.T) {
+	mockService := new(admin.MockAdminService)
+	handler := NewAdminHandler(mockService)
+	defer mockService.AssertExpectations(t)
+
+	mockService.On("GetAd", mock.Anything, 1).Return(&entities.Ad{ID: 1, Version: 3}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{
+		{Key: "id", Value: "1"},
+	}
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/ads/1", nil)
+	handlertest.Serve(c, handler.GetAd)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
 }
 
 func TestAdminHandler_Reject(t *testing.T) {
@@ -77,19 +147,21 @@ func TestAdminHandler_Reject(t *testing.T) {
 		handler := NewAdminHandler(mockService)
 		defer mockService.AssertExpectations(t)
 
-		mockService.On("Reject", mock.Anything, 1, "spam").Return(nil)
+		mockService.On("Reject", mock.Anything, 1, 1, "admin", entities.Rejection{Code: "spam", Note: "ad #3"}).Return(nil)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "admin")
 		c.Params = gin.Params{
 			{Key: "id", Value: "1"},
 		}
-		body := `{"rejection_reason":"spam"}`
+		body := `{"code":"spam","note":"ad #3"}`
 		c.Request = httptest.NewRequest(http.MethodPost, "/admin/ads/1/reject",
 			strings.NewReader(body))
 		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request.Header.Set("If-Match", `"1"`)
 
-		handler.Reject(c)
+		handlertest.Serve(c, handler.Reject)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Equal(t, `{"message":"ad rejected"}`, w.Body.String())
@@ -102,15 +174,17 @@ func TestAdminHandler_Reject(t *testing.T) {
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "admin")
 		c.Params = gin.Params{
 			{Key: "id", Value: "abc"},
 		}
-		body := `{"reason":"spam"}`
+		body := `{"code":"spam"}`
 		c.Request = httptest.NewRequest(http.MethodPost, "/admin/ads/abc/reject",
 			strings.NewReader(body))
 		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request.Header.Set("If-Match", `"1"`)
 
-		handler.Reject(c)
+		handlertest.Serve(c, handler.Reject)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		assert.Contains(t, w.Body.String(), "invalid ad id")
@@ -123,6 +197,7 @@ func TestAdminHandler_Reject(t *testing.T) {
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "admin")
 		c.Params = gin.Params{
 			{Key: "id", Value: "1"},
 		}
@@ -130,11 +205,12 @@ func TestAdminHandler_Reject(t *testing.T) {
 		c.Request = httptest.NewRequest(http.MethodPost, "/admin/ads/1/reject",
 			strings.NewReader(body))
 		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request.Header.Set("If-Match", `"1"`)
 
-		handler.Reject(c)
+		handlertest.Serve(c, handler.Reject)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
-		assert.Contains(t, w.Body.String(), "rejection reason required")
+		assert.Contains(t, w.Body.String(), `"errors":[{"field":"code","code":"required","message":"is required"}]`)
 	})
 
 	t.Run("service error", func(t *testing.T) {
@@ -142,22 +218,49 @@ func TestAdminHandler_Reject(t *testing.T) {
 		handler := NewAdminHandler(mockService)
 		defer mockService.AssertExpectations(t)
 
-		mockService.On("Reject", mock.Anything, 1, "spam").Return(assert.AnError)
+		mockService.On("Reject", mock.Anything, 1, 1, "admin", entities.Rejection{Code: "spam"}).Return(assert.AnError)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "admin")
 		c.Params = gin.Params{
 			{Key: "id", Value: "1"},
 		}
-		body := `{"rejection_reason":"spam"}`
+		body := `{"code":"spam"}`
 		c.Request = httptest.NewRequest(http.MethodPost, "/admin/ads/1/reject",
 			strings.NewReader(body))
 		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request.Header.Set("If-Match", `"1"`)
 
-		handler.Reject(c)
+		handlertest.Serve(c, handler.Reject)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
-		assert.Contains(t, w.Body.String(), "failed to reject ad")
+		assert.Contains(t, w.Body.String(), `"code":"internal"`)
+	})
+
+	t.Run("unknown code", func(t *testing.T) {
+		mockService := new(admin.MockAdminService)
+		handler := NewAdminHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("Reject", mock.Anything, 1, 1, "admin", entities.Rejection{Code: "spam"}).
+			Return(usecaseerr.ErrUnknownRejectionCode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "admin")
+		c.Params = gin.Params{
+			{Key: "id", Value: "1"},
+		}
+		c.Request = httptest.NewRequest(http.MethodPost, "/admin/ads/1/reject",
+			strings.NewReader(`{"code":"spam"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request.Header.Set("If-Match", `"1"`)
+
+		handlertest.Serve(c, handler.Reject)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"code","code":"unknown_rejection_code"`)
 	})
 }
 
@@ -177,7 +280,7 @@ func TestAdminHandler_GetAllAds(t *testing.T) {
 		c, _ := gin.CreateTestContext(w)
 		c.Request = httptest.NewRequest(http.MethodGet, "/admin/ads", nil)
 
-		handler.GetAllAds(c)
+		handlertest.Serve(c, handler.GetAllAds)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Contains(t, w.Body.String(), "ad1")
@@ -196,10 +299,10 @@ func TestAdminHandler_GetAllAds(t *testing.T) {
 		c, _ := gin.CreateTestContext(w)
 		c.Request = httptest.NewRequest(http.MethodGet, "/admin/ads", nil)
 
-		handler.GetAllAds(c)
+		handlertest.Serve(c, handler.GetAllAds)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
-		assert.Contains(t, w.Body.String(), "failed to get ads")
+		assert.Contains(t, w.Body.String(), `"code":"internal"`)
 	})
 
 }
@@ -210,16 +313,18 @@ func TestAdminHandler_GetStatistics(t *testing.T) {
 		handler := NewAdminHandler(mockService)
 		defer mockService.AssertExpectations(t)
 
-		mockService.On("GetStatistics", mock.Anything).
-			Return(entities.AdStatistics{Total: 10, Published: 5, Pending: 3, Rejected: 2}, nil)
+		mockService.On("GetStatistics", mock.Anything, entities.StatisticsFilter{}).
+			Return(entities.AdStatistics{Total: 10, Published: 5, Pending: 3, Rejected: 2,
+				RejectionCodes: map[string]int{"poor_photos": 2}}, nil)
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 		c.Request = httptest.NewRequest(http.MethodGet, "/admin/statistics", nil)
 
-		handler.GetStatistics(c)
+		handlertest.Serve(c, handler.GetStatistics)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Contains(t, w.Body.String(), `"Total":10`)
+		assert.Contains(t, w.Body.String(), `"RejectionCodes":{"poor_photos":2}`)
 	})
 
 	t.Run("error", func(t *testing.T) {
@@ -227,17 +332,66 @@ func TestAdminHandler_GetStatistics(t *testing.T) {
 		handler := NewAdminHandler(mockService)
 		defer mockService.AssertExpectations(t)
 
-		mockService.On("GetStatistics", mock.Anything).
+		mockService.On("GetStatistics", mock.Anything, mock.Anything).
 			Return(entities.AdStatistics{}, assert.AnError)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 		c.Request = httptest.NewRequest(http.MethodGet, "/admin/statistics", nil)
 
-		handler.GetStatistics(c)
+		handlertest.Serve(c, handler.GetStatistics)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
-		assert.Contains(t, w.Body.String(), "failed to get statistics")
+		assert.Contains(t, w.Body.String(), `"code":"internal"`)
+	})
+
+	t.Run("period", func(t *testing.T) {
+		mockService := new(admin.MockAdminService)
+		handler := NewAdminHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("GetStatistics", mock.Anything, entities.StatisticsFilter{
+			From:        time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			To:          time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+			Granularity: entities.GranularityWeek,
+		}).Return(entities.AdStatistics{}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet,
+			"/admin/stats?from=2026-03-01&to=2026-03-31&granularity=week", nil)
+
+		handlertest.Serve(c, handler.GetStatistics)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		handler := NewAdminHandler(new(admin.MockAdminService))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/admin/stats?from=01.03.2026", nil)
+
+		handlertest.Serve(c, handler.GetStatistics)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		mockService := new(admin.MockAdminService)
+		handler := NewAdminHandler(mockService)
+
+		mockService.On("GetStatistics", mock.Anything, mock.Anything).
+			Return(entities.AdStatistics{}, usecaseerr.ErrInvalidStatistics)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/admin/stats?granularity=year", nil)
+
+		handlertest.Serve(c, handler.GetStatistics)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 }
 
@@ -256,7 +410,7 @@ func TestAdminHandler_DeleteAd(t *testing.T) {
 		}
 		c.Request = httptest.NewRequest(http.MethodDelete, "/admin/ads/1", nil)
 
-		handler.DeleteAd(c)
+		handlertest.Serve(c, handler.DeleteAd)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
@@ -273,7 +427,7 @@ func TestAdminHandler_DeleteAd(t *testing.T) {
 		}
 		c.Request = httptest.NewRequest(http.MethodDelete, "/admin/ads/abc", nil)
 
-		handler.DeleteAd(c)
+		handlertest.Serve(c, handler.DeleteAd)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		assert.Contains(t, w.Body.String(), "invalid ad id")
@@ -293,9 +447,55 @@ func TestAdminHandler_DeleteAd(t *testing.T) {
 		}
 		c.Request = httptest.NewRequest(http.MethodDelete, "/admin/ads/2", nil)
 
-		handler.DeleteAd(c)
+		handlertest.Serve(c, handler.DeleteAd)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"internal"`)
+	})
+}
+
+func TestAdminHandler_GetHistory(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(admin.MockAdminService)
+		handler := NewAdminHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("GetHistory", mock.Anything, 3).Return([]entities.ModerationEvent{
+			{ID: 1, AdID: 3, Action: entities.ActionRejected, RejectionCode: "rules_violation"},
+			{ID: 2, AdID: 3, Action: entities.ActionAppealed, ActorID: "author", Note: "Please recheck."},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{
+			{Key: "id", Value: "3"},
+		}
+		c.Request = httptest.NewRequest(http.MethodGet, "/admin/ads/3/history", nil)
+
+		handlertest.Serve(c, handler.GetHistory)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"action":"rejected","rejection_code":"rules_violation"`)
+		assert.Contains(t, w.Body.String(), `"action":"appealed","actor_id":"author"`)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		mockService := new(admin.MockAdminService)
+		handler := NewAdminHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("GetHistory", mock.Anything, 3).Return(nil, assert.AnError)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{
+			{Key: "id", Value: "3"},
+		}
+		c.Request = httptest.NewRequest(http.MethodGet, "/admin/ads/3/history", nil)
+
+		handlertest.Serve(c, handler.GetHistory)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
-		assert.Contains(t, w.Body.String(), "failed to delete ad")
+		assert.Contains(t, w.Body.String(), `"code":"internal"`)
 	})
 }
@@ -1,6 +1,10 @@
 package admin
 
-import "ads-service/internal/usecase/admin"
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/usecase/admin"
+	"time"
+)
 
 type AdminHandler struct {
 	adminService admin.AdminAdvertisementService
@@ -12,6 +16,28 @@ func NewAdminHandler(adminService admin.AdminAdvertisementService) *AdminHandler
 	}
 }
 
+// RejectionRequest - code of an active rejection template, the note is shown to the author after its text.
 type RejectionRequest struct {
-	Reason string `json:"rejection_reason" binding:"required"`
+	Code string `json:"code" binding:"required"`
+	Note string `json:"note"`
+}
+
+type HistoryResponse struct {
+	CreatedAt     time.Time `json:"created_at"`
+	Action        string    `json:"action"`
+	ActorID       string    `json:"actor_id,omitempty"`
+	RejectionCode string    `json:"rejection_code,omitempty"`
+	Note          string    `json:"note,omitempty"`
+	ID            int       `json:"id"`
+}
+
+func newHistoryResponse(e *entities.ModerationEvent) HistoryResponse {
+	return HistoryResponse{
+		CreatedAt:     e.CreatedAt,
+		Action:        string(e.Action),
+		ActorID:       e.ActorID,
+		RejectionCode: e.RejectionCode,
+		Note:          e.Note,
+		ID:            e.ID,
+	}
 }
@@ -0,0 +1,101 @@
+package apikey
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateAPIKey godoc
+// @Summary      Create an API key
+// @Description  Creates a scoped key for integrations. The key is returned only once
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CreateAPIKeyRequest  true  "Name, scopes (ads:read, ads:write) and optional expiry"
+// @Success      201  {object}  APIKeyResponse
+// @Failure      400  {object}  middleware.Problem
+// @Failure      409  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(fmt.Errorf("%w: %w", errs.ErrInvalidBody, err))
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		_ = c.Error(errs.ErrUnauthorized)
+		return
+	}
+
+	key := entities.APIKey{Name: req.Name, Scopes: req.Scopes}
+	if req.ExpiresAt != nil {
+		key.ExpiresAt = *req.ExpiresAt
+	}
+	rawKey, err := h.apiKeyService.Create(c.Request.Context(), userID, &key)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to create api key: %w", err))
+		return
+	}
+
+	resp := newAPIKeyResponse(&key)
+	resp.Key = rawKey
+	c.JSON(http.StatusCreated, resp)
+}
+
+// GetAPIKeys godoc
+// @Summary      List API keys
+// @Description  Returns metadata of the user's API keys including revoked ones
+// @Tags         api-keys
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /api-keys [get]
+func (h *APIKeyHandler) GetAPIKeys(c *gin.Context) {
+	keys, err := h.apiKeyService.List(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to get api keys: %w", err))
+		return
+	}
+
+	resp := make([]APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		resp = append(resp, newAPIKeyResponse(&keys[i]))
+	}
+	c.JSON(http.StatusOK, gin.H{"api_keys": resp})
+}
+
+// RevokeAPIKey godoc
+// @Summary      Revoke an API key
+// @Tags         api-keys
+// @Produce      json
+// @Param        id   path      int  true  "API key ID"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  middleware.Problem
+// @Failure      404  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		_ = c.Error(errs.Invalid("id", "invalid api key id"))
+		return
+	}
+
+	err = h.apiKeyService.Revoke(c.Request.Context(), c.GetString("user_id"), id)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to revoke api key: %w", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "api key revoked"})
+}
@@ -0,0 +1,202 @@
+//nolint:all // testpackage
+package apikey
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/rest/handlers/handlertest"
+	"ads-service/internal/usecase/apikey"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAPIKeyHandler_CreateAPIKey(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(apikey.MockAPIKeyService)
+		handler := NewAPIKeyHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("Create", mock.Anything, "123", mock.MatchedBy(func(k *entities.APIKey) bool {
+			return k.Name == "importer" && len(k.Scopes) == 2 && !k.ExpiresAt.IsZero()
+		})).Run(func(args mock.Arguments) {
+			key := args.Get(2).(*entities.APIKey)
+			key.ID = 1
+			key.Prefix = "0123456789ab"
+		}).Return("gads_0123456789ab_secret", nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "123")
+		body := `{"name":"importer","scopes":["ads:read","ads:write"],"expires_at":"2030-01-01T00:00:00Z"}`
+		req := httptest.NewRequest(http.MethodPost, "/api-keys", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+
+		handlertest.Serve(c, handler.CreateAPIKey)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"key":"gads_0123456789ab_secret"`)
+		assert.Contains(t, w.Body.String(), `"prefix":"0123456789ab"`)
+		assert.NotContains(t, w.Body.String(), "hash")
+	})
+
+	t.Run("invalid data", func(t *testing.T) {
+		mockService := new(apikey.MockAPIKeyService)
+		handler := NewAPIKeyHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("Create", mock.Anything, "123", mock.Anything).Return("", usecaseerr.ErrInvalidAPIKeyData)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "123")
+		req := httptest.NewRequest(http.MethodPost, "/api-keys",
+			strings.NewReader(`{"name":"importer","scopes":["admin"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+
+		handlertest.Serve(c, handler.CreateAPIKey)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("limit reached", func(t *testing.T) {
+		mockService := new(apikey.MockAPIKeyService)
+		handler := NewAPIKeyHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("Create", mock.Anything, "123", mock.Anything).Return("", usecaseerr.ErrTooManyAPIKeys)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "123")
+		req := httptest.NewRequest(http.MethodPost, "/api-keys",
+			strings.NewReader(`{"name":"importer","scopes":["ads:read"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+
+		handlertest.Serve(c, handler.CreateAPIKey)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		handler := NewAPIKeyHandler(new(apikey.MockAPIKeyService))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "123")
+		req := httptest.NewRequest(http.MethodPost, "/api-keys", strings.NewReader(`{"name":"importer"}`))
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+
+		handlertest.Serve(c, handler.CreateAPIKey)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "invalid request body")
+	})
+}
+
+func TestAPIKeyHandler_GetAPIKeys(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(apikey.MockAPIKeyService)
+		handler := NewAPIKeyHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("List", mock.Anything, "123").Return([]entities.APIKey{{
+			ID:        1,
+			Name:      "importer",
+			Prefix:    "0123456789ab",
+			Hash:      "secret-hash",
+			Scopes:    []entities.APIKeyScope{entities.ScopeAdsRead},
+			RevokedAt: time.Now(),
+		}}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "123")
+		c.Request = httptest.NewRequest(http.MethodGet, "/api-keys", nil)
+
+		handlertest.Serve(c, handler.GetAPIKeys)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"revoked_at"`)
+		assert.NotContains(t, w.Body.String(), "secret-hash")
+		assert.NotContains(t, w.Body.String(), `"expires_at"`)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		mockService := new(apikey.MockAPIKeyService)
+		handler := NewAPIKeyHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("List", mock.Anything, "123").Return(nil, usecaseerr.ErrGettingAPIKeys)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "123")
+		c.Request = httptest.NewRequest(http.MethodGet, "/api-keys", nil)
+
+		handlertest.Serve(c, handler.GetAPIKeys)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestAPIKeyHandler_RevokeAPIKey(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(apikey.MockAPIKeyService)
+		handler := NewAPIKeyHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("Revoke", mock.Anything, "123", 1).Return(nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "123")
+		c.Params = gin.Params{{Key: "id", Value: "1"}}
+		c.Request = httptest.NewRequest(http.MethodDelete, "/api-keys/1", nil)
+
+		handlertest.Serve(c, handler.RevokeAPIKey)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockService := new(apikey.MockAPIKeyService)
+		handler := NewAPIKeyHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("Revoke", mock.Anything, "123", 2).Return(usecaseerr.ErrAPIKeyNotFound)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "123")
+		c.Params = gin.Params{{Key: "id", Value: "2"}}
+		c.Request = httptest.NewRequest(http.MethodDelete, "/api-keys/2", nil)
+
+		handlertest.Serve(c, handler.RevokeAPIKey)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		handler := NewAPIKeyHandler(new(apikey.MockAPIKeyService))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "id", Value: "abc"}}
+		c.Request = httptest.NewRequest(http.MethodDelete, "/api-keys/abc", nil)
+
+		handlertest.Serve(c, handler.RevokeAPIKey)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
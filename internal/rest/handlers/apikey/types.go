@@ -0,0 +1,56 @@
+package apikey
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/usecase/apikey"
+	"time"
+)
+
+type APIKeyHandler struct {
+	apiKeyService apikey.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService apikey.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+type CreateAPIKeyRequest struct {
+	ExpiresAt *time.Time             `json:"expires_at"`
+	Name      string                 `json:"name" binding:"required"`
+	Scopes    []entities.APIKeyScope `json:"scopes" binding:"required"`
+}
+
+// APIKeyResponse - key metadata, the hash is never returned.
+type APIKeyResponse struct {
+	CreatedAt  time.Time              `json:"created_at"`
+	ExpiresAt  *time.Time             `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time             `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time             `json:"revoked_at,omitempty"`
+	Name       string                 `json:"name"`
+	Prefix     string                 `json:"prefix"`
+	Key        string                 `json:"key,omitempty"` // only in the create response
+	Scopes     []entities.APIKeyScope `json:"scopes"`
+	ID         int                    `json:"id"`
+}
+
+func newAPIKeyResponse(key *entities.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		CreatedAt:  key.CreatedAt,
+		ExpiresAt:  optionalTime(key.ExpiresAt),
+		LastUsedAt: optionalTime(key.LastUsedAt),
+		RevokedAt:  optionalTime(key.RevokedAt),
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		ID:         key.ID,
+	}
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,132 @@
+package appeal
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Appeal godoc
+// @Summary      Appeal a rejection
+// @Description  The author asks to reconsider the rejection of their ad. A moderator other than the one who rejected
+// @Description  the ad reviews the appeal. An ad has one pending appeal at a time and at most 2 appeals in total.
+// @Tags         user-ads
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int            true  "Ad ID"
+// @Param        request  body      AppealRequest  true  "Message to the moderator, up to 2000 characters"
+// @Success      201  {object}  AppealResponse
+// @Failure      400  {object}  middleware.Problem
+// @Failure      403  {object}  middleware.Problem
+// @Failure      404  {object}  middleware.Problem
+// @Failure      409  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /ads/{id}/appeal [post]
+func (h *AppealHandler) Appeal(c *gin.Context) {
+	adID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || adID <= 0 {
+		_ = c.Error(errs.Invalid("id", "invalid ad ID"))
+		return
+	}
+	var req AppealRequest
+	if err = c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(fmt.Errorf("%w: %w", errs.ErrInvalidBody, err))
+		return
+	}
+
+	appeal, err := h.appealService.Appeal(c.Request.Context(), c.GetString("user_id"), adID, req.Message)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to appeal: %w", err))
+		return
+	}
+	c.JSON(http.StatusCreated, newAppealResponse(appeal))
+}
+
+// GetAppeals godoc
+// @Summary      Appeals of an ad
+// @Description  Appeals of the author's ad with their outcome, oldest first.
+// @Tags         user-ads
+// @Produce      json
+// @Param        id   path      int  true  "Ad ID"
+// @Success      200  {array}   AppealResponse
+// @Failure      400  {object}  middleware.Problem
+// @Failure      403  {object}  middleware.Problem
+// @Failure      404  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /ads/{id}/appeals [get]
+func (h *AppealHandler) GetAppeals(c *gin.Context) {
+	adID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || adID <= 0 {
+		_ = c.Error(errs.Invalid("id", "invalid ad ID"))
+		return
+	}
+
+	appeals, err := h.appealService.GetAppeals(c.Request.Context(), c.GetString("user_id"), adID)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to get appeals: %w", err))
+		return
+	}
+	c.JSON(http.StatusOK, newAppealResponses(appeals))
+}
+
+// GetPendingAppeals godoc
+// @Summary      Appeals queue
+// @Description  Appeals waiting for a review, oldest first. rejected_by is empty when pre-moderation rejected the ad.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {array}   AppealResponse
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /admin/appeals [get]
+func (h *AppealHandler) GetPendingAppeals(c *gin.Context) {
+	appeals, err := h.appealService.ListPending(c.Request.Context())
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to get appeals: %w", err))
+		return
+	}
+	c.JSON(http.StatusOK, newAppealResponses(appeals))
+}
+
+// ReviewAppeal godoc
+// @Summary      Review an appeal
+// @Description  upheld keeps the rejection, overturned approves the ad. The moderator who rejected the ad cannot
+// @Description  review its appeal. The author is notified either way.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int            true  "Appeal ID"
+// @Param        request  body      ReviewRequest  true  "Decision and optional note to the author"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  middleware.Problem
+// @Failure      403  {object}  middleware.Problem
+// @Failure      404  {object}  middleware.Problem
+// @Failure      409  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /admin/appeals/{id}/review [post]
+func (h *AppealHandler) ReviewAppeal(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		_ = c.Error(errs.Invalid("id", "invalid appeal ID"))
+		return
+	}
+	var req ReviewRequest
+	if err = c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(fmt.Errorf("%w: %w", errs.ErrInvalidBody, err))
+		return
+	}
+
+	err = h.appealService.Review(c.Request.Context(), c.GetString("user_id"), id,
+		entities.AppealStatus(req.Decision), req.Note)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to review appeal: %w", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "appeal reviewed"})
+}
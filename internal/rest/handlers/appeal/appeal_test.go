@@ -0,0 +1,174 @@
+//nolint:all // testpackage
+package appeal
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/rest/handlers/handlertest"
+	"ads-service/internal/usecase/appeal"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newContext(method, target, body, id string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	if id != "" {
+		c.Params = gin.Params{{Key: "id", Value: id}}
+	}
+	c.Request = httptest.NewRequest(method, target, strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("user_id", "user-1")
+	return c, w
+}
+
+func TestAppealHandler_Appeal(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(appeal.MockAppealService)
+		handler := NewAppealHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("Appeal", mock.Anything, "user-1", 7, "The photos are mine.").
+			Return(&entities.Appeal{ID: 1, AdID: 7, AuthorID: "user-1", Message: "The photos are mine.",
+				Status: entities.AppealPending}, nil)
+
+		c, w := newContext(http.MethodPost, "/ads/7/appeal", `{"message":"The photos are mine."}`, "7")
+		handlertest.Serve(c, handler.Appeal)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"pending"`)
+		assert.NotContains(t, w.Body.String(), "reviewed_at")
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		handler := NewAppealHandler(new(appeal.MockAppealService))
+
+		c, w := newContext(http.MethodPost, "/ads/abc/appeal", `{"message":"why"}`, "abc")
+		handlertest.Serve(c, handler.Appeal)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("missing message", func(t *testing.T) {
+		handler := NewAppealHandler(new(appeal.MockAppealService))
+
+		c, w := newContext(http.MethodPost, "/ads/7/appeal", `{}`, "7")
+		handlertest.Serve(c, handler.Appeal)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		cases := map[error]int{
+			usecaseerr.ErrInvalidAppeal:  http.StatusBadRequest,
+			usecaseerr.ErrAccessDenied:   http.StatusForbidden,
+			usecaseerr.ErrAdNotFound:     http.StatusNotFound,
+			usecaseerr.ErrAdNotRejected:  http.StatusConflict,
+			usecaseerr.ErrAppealLimit:    http.StatusConflict,
+			usecaseerr.ErrCreatingAppeal: http.StatusInternalServerError,
+		}
+		for err, status := range cases {
+			mockService := new(appeal.MockAppealService)
+			handler := NewAppealHandler(mockService)
+			mockService.On("Appeal", mock.Anything, "user-1", 7, "why").Return(nil, err)
+
+			c, w := newContext(http.MethodPost, "/ads/7/appeal", `{"message":"why"}`, "7")
+			handlertest.Serve(c, handler.Appeal)
+
+			assert.Equal(t, status, w.Code, err.Error())
+		}
+	})
+}
+
+func TestAppealHandler_GetAppeals(t *testing.T) {
+	mockService := new(appeal.MockAppealService)
+	handler := NewAppealHandler(mockService)
+	defer mockService.AssertExpectations(t)
+
+	mockService.On("GetAppeals", mock.Anything, "user-1", 7).Return([]entities.Appeal{
+		{ID: 1, AdID: 7, Status: entities.AppealUpheld, DecisionNote: "Still blurry."},
+	}, nil)
+
+	c, w := newContext(http.MethodGet, "/ads/7/appeals", "", "7")
+	handlertest.Serve(c, handler.GetAppeals)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"decision_note":"Still blurry."`)
+}
+
+func TestAppealHandler_GetPendingAppeals(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		mockService := new(appeal.MockAppealService)
+		handler := NewAppealHandler(mockService)
+
+		mockService.On("ListPending", mock.Anything).Return([]entities.Appeal{}, nil)
+
+		c, w := newContext(http.MethodGet, "/admin/appeals", "", "")
+		handlertest.Serve(c, handler.GetPendingAppeals)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "[]", w.Body.String())
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		mockService := new(appeal.MockAppealService)
+		handler := NewAppealHandler(mockService)
+
+		mockService.On("ListPending", mock.Anything).Return(nil, usecaseerr.ErrGettingAppeals)
+
+		c, w := newContext(http.MethodGet, "/admin/appeals", "", "")
+		handlertest.Serve(c, handler.GetPendingAppeals)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestAppealHandler_ReviewAppeal(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(appeal.MockAppealService)
+		handler := NewAppealHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("Review", mock.Anything, "user-1", 3, entities.AppealOverturned, "Looks fine.").Return(nil)
+
+		c, w := newContext(http.MethodPost, "/admin/appeals/3/review",
+			`{"decision":"overturned","note":"Looks fine."}`, "3")
+		handlertest.Serve(c, handler.ReviewAppeal)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("missing decision", func(t *testing.T) {
+		handler := NewAppealHandler(new(appeal.MockAppealService))
+
+		c, w := newContext(http.MethodPost, "/admin/appeals/3/review", `{"note":"ok"}`, "3")
+		handlertest.Serve(c, handler.ReviewAppeal)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		cases := map[error]int{
+			usecaseerr.ErrInvalidDecision: http.StatusBadRequest,
+			usecaseerr.ErrSameModerator:   http.StatusForbidden,
+			usecaseerr.ErrAppealNotFound:  http.StatusNotFound,
+			usecaseerr.ErrAppealReviewed:  http.StatusConflict,
+		}
+		for err, status := range cases {
+			mockService := new(appeal.MockAppealService)
+			handler := NewAppealHandler(mockService)
+			mockService.On("Review", mock.Anything, "user-1", 3, entities.AppealUpheld, "").Return(err)
+
+			c, w := newContext(http.MethodPost, "/admin/appeals/3/review", `{"decision":"upheld"}`, "3")
+			handlertest.Serve(c, handler.ReviewAppeal)
+
+			assert.Equal(t, status, w.Code, err.Error())
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package appeal
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/usecase/appeal"
+	"time"
+)
+
+type AppealHandler struct {
+	appealService appeal.AppealService
+}
+
+func NewAppealHandler(appealService appeal.AppealService) *AppealHandler {
+	return &AppealHandler{
+		appealService: appealService,
+	}
+}
+
+type AppealRequest struct {
+	Message string `json:"message" binding:"required"`
+}
+
+// ReviewRequest - decision is upheld to keep the rejection or overturned to publish the ad.
+type ReviewRequest struct {
+	Decision string `json:"decision" binding:"required"`
+	Note     string `json:"note"`
+}
+
+type AppealResponse struct {
+	CreatedAt    time.Time  `json:"created_at"`
+	ReviewedAt   *time.Time `json:"reviewed_at,omitempty"`
+	Status       string     `json:"status"`
+	AuthorID     string     `json:"author_id"`
+	RejectedBy   string     `json:"rejected_by,omitempty"`
+	ReviewedBy   string     `json:"reviewed_by,omitempty"`
+	Message      string     `json:"message"`
+	DecisionNote string     `json:"decision_note,omitempty"`
+	ID           int        `json:"id"`
+	AdID         int        `json:"ad_id"`
+}
+
+func newAppealResponse(a *entities.Appeal) AppealResponse {
+	resp := AppealResponse{
+		CreatedAt:    a.CreatedAt,
+		Status:       string(a.Status),
+		AuthorID:     a.AuthorID,
+		RejectedBy:   a.RejectedBy,
+		ReviewedBy:   a.ReviewedBy,
+		Message:      a.Message,
+		DecisionNote: a.DecisionNote,
+		ID:           a.ID,
+		AdID:         a.AdID,
+	}
+	if !a.ReviewedAt.IsZero() {
+		resp.ReviewedAt = &a.ReviewedAt
+	}
+	return resp
+}
+
+func newAppealResponses(appeals []entities.Appeal) []AppealResponse {
+	resp := make([]AppealResponse, 0, len(appeals))
+	for i := range appeals {
+		resp = append(resp, newAppealResponse(&appeals[i]))
+	}
+	return resp
+}
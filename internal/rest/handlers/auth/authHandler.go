@@ -2,7 +2,12 @@ package auth
 
 import (
 	"ads-service/internal/domain/entities"
-	"log"
+	"ads-service/internal/errs"
+	"ads-service/internal/errs/usecaseerr"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,20 +20,20 @@ import (
 // @Produce json
 // @Param user body entities.User true "User registration payload"
 // @Success 201 {object} map[string]string "user registered successfully"
-// @Failure 400 {object} map[string]string "invalid request body"
-// @Failure 500 {object} map[string]string "failed to register user"
+// @Failure 400 {object} middleware.Problem "invalid request body"
+// @Failure 500 {object} middleware.Problem "failed to register user"
 // @Router /auth/register [post]
 func (h *AuthHandler) Register(c *gin.Context) {
 	var user entities.User
 
 	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(400, gin.H{"error": "invalid request body: " + err.Error()})
+		_ = c.Error(fmt.Errorf("%w: %w", errs.ErrInvalidBody, err))
 		return
 	}
 
 	if err := h.userAuthService.Register(c.Request.Context(), &user); err != nil {
-		log.Println("problem in handler")
-		c.JSON(500, gin.H{"error": "failed to register user: " + err.Error()})
+		slog.ErrorContext(c.Request.Context(), "registering user", "error", err)
+		_ = c.Error(fmt.Errorf("failed to register user: %w", err))
 		return
 	}
 	c.JSON(201, gin.H{"message": "user registered successfully"})
@@ -36,28 +41,127 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 // Login godoc
 // @Summary User login
-// @Description Authenticate user and return access and refresh tokens
+// @Description Authenticate user and return access and refresh tokens.
+// @Description With two-factor authentication enabled only mfa_token is returned, see /auth/login/2fa
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param credentials body AuthRequest true "User login credentials"
 // @Success 200 {object} LoginResponse
-// @Failure 400 {object} map[string]string "invalid request body"
-// @Failure 401 {object} map[string]string "failed to login"
+// @Failure 400 {object} middleware.Problem "invalid request body"
+// @Failure 401 {object} middleware.Problem "failed to login"
+// @Failure 403 {object} middleware.Problem "user is banned"
+// @Failure 429 {object} middleware.Problem "too many failed attempts"
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var loginReq AuthRequest
 
 	if err := c.ShouldBindJSON(&loginReq); err != nil {
-		c.JSON(400, gin.H{"error": "invalid request body: " + err.Error()})
+		_ = c.Error(fmt.Errorf("%w: %w", errs.ErrInvalidBody, err))
 		return
 	}
 
-	access, refresh, err := h.userAuthService.Login(c.Request.Context(), loginReq.Phone, loginReq.Password)
+	tokens, err := h.userAuthService.Login(c.Request.Context(), loginReq.Phone, loginReq.Password,
+		c.ClientIP())
 	if err != nil {
-		c.JSON(401, gin.H{"error": "failed to login: " + err.Error()})
+		_ = c.Error(fmt.Errorf("failed to login: %w", err))
 		return
 	}
 
-	c.JSON(200, LoginResponse{access, refresh})
+	c.JSON(200, newLoginResponse(tokens))
+}
+
+// LoginMFA godoc
+// @Summary Second login step
+// @Description Exchange mfa_token from /auth/login and a TOTP or recovery code for access and refresh tokens
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body MFARequest true "MFA token and code"
+// @Success 200 {object} LoginResponse
+// @Failure 400 {object} middleware.Problem "invalid request body"
+// @Failure 401 {object} middleware.Problem "failed to login"
+// @Failure 429 {object} middleware.Problem "too many failed attempts"
+// @Router /auth/login/2fa [post]
+func (h *AuthHandler) LoginMFA(c *gin.Context) {
+	var req MFARequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(fmt.Errorf("%w: %w", errs.ErrInvalidBody, err))
+		return
+	}
+
+	tokens, err := h.userAuthService.VerifyMFA(c.Request.Context(), req.MFAToken, req.Code, c.ClientIP())
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to login: %w", err))
+		return
+	}
+
+	c.JSON(200, newLoginResponse(tokens))
+}
+
+func newLoginResponse(tokens *entities.AuthTokens) LoginResponse {
+	return LoginResponse{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		MFARequired:  tokens.MFAToken != "",
+		MFAToken:     tokens.MFAToken,
+	}
+}
+
+// GetLockouts godoc
+// @Summary List active login lockouts
+// @Description Returns phones and IPs that are currently locked out after failed logins (admin only)
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} middleware.Problem
+// @Router /admin/lockouts [get]
+// @Security BearerAuth
+func (h *AuthHandler) GetLockouts(c *gin.Context) {
+	lockouts, err := h.lockoutService.GetLockouts(c.Request.Context())
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to get lockouts: %w", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"lockouts": lockouts})
+}
+
+// ClearLockout godoc
+// @Summary Clear login lockout
+// @Description Resets failed login counter for a phone or an IP (admin only)
+// @Tags admin
+// @Produce json
+// @Param kind path string true "Lockout kind (phone or ip)"
+// @Param key path string true "Phone number or IP address"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} middleware.Problem
+// @Failure 404 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /admin/lockouts/{kind}/{key} [delete]
+// @Security BearerAuth
+func (h *AuthHandler) ClearLockout(c *gin.Context) {
+	kind := entities.LockoutKind(c.Param("kind"))
+	err := h.lockoutService.ClearLockout(c.Request.Context(), kind, c.Param("key"))
+	switch {
+	case errors.Is(err, usecaseerr.ErrInvalidParams):
+		_ = c.Error(errs.Invalid("kind", "invalid lockout kind or key"))
+		return
+	case err != nil:
+		_ = c.Error(fmt.Errorf("failed to clear lockout: %w", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "lockout cleared"})
+}
+
+// JWKS godoc
+// @Summary JSON Web Key Set
+// @Description Public keys that verify access tokens issued by this service
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} jwtkeys.JWKS
+// @Router /.well-known/jwks.json [get]
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.userAuthService.JWKS())
 }
@@ -1,7 +1,12 @@
 package auth
 
 import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/rest/handlers/handlertest"
 	"ads-service/internal/usecase/auth"
+	"ads-service/internal/usecase/lockout"
+	"ads-service/pkg/jwtkeys"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -14,7 +19,7 @@ import (
 func TestAuthHandler_Register(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockService := new(auth.MockAuthService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &lockout.MockLockoutService{})
 		defer mockService.AssertExpectations(t)
 
 		body := `{"username":"testuser","password":"testpass"}`
@@ -27,7 +32,7 @@ func TestAuthHandler_Register(t *testing.T) {
 
 		mockService.On("Register", mock.Anything, mock.Anything).Return(nil)
 
-		handler.Register(c)
+		handlertest.Serve(c, handler.Register)
 
 		assert.Equal(t, http.StatusCreated, w.Code)
 		assert.Equal(t, `{"message":"user registered successfully"}`, w.Body.String())
@@ -35,7 +40,7 @@ func TestAuthHandler_Register(t *testing.T) {
 
 	t.Run("invalid username", func(t *testing.T) {
 		mockService := new(auth.MockAuthService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &lockout.MockLockoutService{})
 		defer mockService.AssertExpectations(t)
 
 		body := `{"username":testuser,"password":123}`
@@ -46,7 +51,7 @@ func TestAuthHandler_Register(t *testing.T) {
 		c, _ := gin.CreateTestContext(w)
 		c.Request = req
 
-		handler.Register(c)
+		handlertest.Serve(c, handler.Register)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		assert.Contains(t, w.Body.String(), "invalid request body")
@@ -54,7 +59,7 @@ func TestAuthHandler_Register(t *testing.T) {
 
 	t.Run("invalid password", func(t *testing.T) {
 		mockService := new(auth.MockAuthService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &lockout.MockLockoutService{})
 		defer mockService.AssertExpectations(t)
 
 		body := `{"username":"testuser","password":123}`
@@ -65,7 +70,7 @@ func TestAuthHandler_Register(t *testing.T) {
 		c, _ := gin.CreateTestContext(w)
 		c.Request = req
 
-		handler.Register(c)
+		handlertest.Serve(c, handler.Register)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		assert.Contains(t, w.Body.String(), "invalid request body")
@@ -73,7 +78,7 @@ func TestAuthHandler_Register(t *testing.T) {
 
 	t.Run("fail service", func(t *testing.T) {
 		mockService := new(auth.MockAuthService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &lockout.MockLockoutService{})
 		defer mockService.AssertExpectations(t)
 
 		body := `{"username":"testuser","password":"testpass"}`
@@ -86,17 +91,17 @@ func TestAuthHandler_Register(t *testing.T) {
 
 		mockService.On("Register", mock.Anything, mock.Anything).Return(assert.AnError)
 
-		handler.Register(c)
+		handlertest.Serve(c, handler.Register)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
-		assert.Contains(t, w.Body.String(), "failed to register user")
+		assert.Contains(t, w.Body.String(), `"code":"internal"`)
 	})
 }
 
 func TestAuthHandler_Login_Success(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockService := new(auth.MockAuthService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &lockout.MockLockoutService{})
 		defer mockService.AssertExpectations(t)
 
 		body := `{"phone":"1234567890","password":"testpass"}`
@@ -107,18 +112,40 @@ func TestAuthHandler_Login_Success(t *testing.T) {
 		c, _ := gin.CreateTestContext(w)
 		c.Request = req
 
-		mockService.On("Login", mock.Anything, "1234567890", "testpass").Return("access-token", "refresh-token", nil)
+		mockService.On("Login", mock.Anything, "1234567890", "testpass", mock.Anything).
+			Return(&entities.AuthTokens{AccessToken: "access-token", RefreshToken: "refresh-token"}, nil)
 
-		handler.Login(c)
+		handlertest.Serve(c, handler.Login)
 
 		assert.Equal(t, http.StatusOK, w.Code)
-		assert.Contains(t, w.Body.String(), "access-token")
-		assert.Contains(t, w.Body.String(), "refresh-token")
+		assert.JSONEq(t, `{"access_token":"access-token","refresh_token":"refresh-token"}`, w.Body.String())
+	})
+
+	t.Run("second factor required", func(t *testing.T) {
+		mockService := new(auth.MockAuthService)
+		handler := NewAuthHandler(mockService, &lockout.MockLockoutService{})
+		defer mockService.AssertExpectations(t)
+
+		body := `{"phone":"1234567890","password":"testpass"}`
+		req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+
+		mockService.On("Login", mock.Anything, "1234567890", "testpass", mock.Anything).
+			Return(&entities.AuthTokens{MFAToken: "mfa-token"}, nil)
+
+		handlertest.Serve(c, handler.Login)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"mfa_required":true,"mfa_token":"mfa-token"}`, w.Body.String())
 	})
 
 	t.Run("invalid phone", func(t *testing.T) {
 		mockService := new(auth.MockAuthService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &lockout.MockLockoutService{})
 		defer mockService.AssertExpectations(t)
 
 		body := `{"phone":1234567890,"password":"testpass"}` // некорректный тип поля
@@ -129,7 +156,7 @@ func TestAuthHandler_Login_Success(t *testing.T) {
 		c, _ := gin.CreateTestContext(w)
 		c.Request = req
 
-		handler.Login(c)
+		handlertest.Serve(c, handler.Login)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		assert.Contains(t, w.Body.String(), "invalid request body")
@@ -137,7 +164,7 @@ func TestAuthHandler_Login_Success(t *testing.T) {
 
 	t.Run("invalid password", func(t *testing.T) {
 		mockService := new(auth.MockAuthService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &lockout.MockLockoutService{})
 		defer mockService.AssertExpectations(t)
 
 		body := `{"phone":"1234567890","password":123}` // некорректный тип поля
@@ -148,7 +175,7 @@ func TestAuthHandler_Login_Success(t *testing.T) {
 		c, _ := gin.CreateTestContext(w)
 		c.Request = req
 
-		handler.Login(c)
+		handlertest.Serve(c, handler.Login)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		assert.Contains(t, w.Body.String(), "invalid request body")
@@ -156,7 +183,7 @@ func TestAuthHandler_Login_Success(t *testing.T) {
 
 	t.Run("invalid body", func(t *testing.T) {
 		mockService := new(auth.MockAuthService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &lockout.MockLockoutService{})
 		defer mockService.AssertExpectations(t)
 
 		body := `{"phone":123}` // некорректный тип поля
@@ -167,7 +194,7 @@ func TestAuthHandler_Login_Success(t *testing.T) {
 		c, _ := gin.CreateTestContext(w)
 		c.Request = req
 
-		handler.Login(c)
+		handlertest.Serve(c, handler.Login)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		assert.Contains(t, w.Body.String(), "invalid request body")
@@ -175,7 +202,7 @@ func TestAuthHandler_Login_Success(t *testing.T) {
 
 	t.Run("fail service", func(t *testing.T) {
 		mockService := new(auth.MockAuthService)
-		handler := NewAuthHandler(mockService)
+		handler := NewAuthHandler(mockService, &lockout.MockLockoutService{})
 		defer mockService.AssertExpectations(t)
 
 		body := `{"phone":"1234567890","password":"testpass"}`
@@ -186,12 +213,226 @@ func TestAuthHandler_Login_Success(t *testing.T) {
 		c, _ := gin.CreateTestContext(w)
 		c.Request = req
 
-		mockService.On("Login", mock.Anything, "1234567890", "testpass").Return("", "", assert.AnError)
+		mockService.On("Login", mock.Anything, "1234567890", "testpass", mock.Anything).
+			Return(nil, usecaseerr.ErrInvalidCredentials)
 
-		handler.Login(c)
+		handlertest.Serve(c, handler.Login)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
 		assert.Contains(t, w.Body.String(), "failed to login")
 	})
 
 }
+
+func TestAuthHandler_Login_Locked(t *testing.T) {
+	mockService := new(auth.MockAuthService)
+	handler := NewAuthHandler(mockService, &lockout.MockLockoutService{})
+	defer mockService.AssertExpectations(t)
+
+	body := `{"phone":"1234567890","password":"testpass"}`
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	mockService.On("Login", mock.Anything, "1234567890", "testpass", "192.0.2.1").
+		Return(nil, usecaseerr.ErrTooManyAttempts)
+
+	handlertest.Serve(c, handler.Login)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Contains(t, w.Body.String(), "too many failed login attempts")
+}
+
+func TestAuthHandler_Login_Banned(t *testing.T) {
+	mockService := new(auth.MockAuthService)
+	handler := NewAuthHandler(mockService, &lockout.MockLockoutService{})
+	defer mockService.AssertExpectations(t)
+
+	body := `{"phone":"1234567890","password":"testpass"}`
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	mockService.On("Login", mock.Anything, "1234567890", "testpass", "192.0.2.1").
+		Return(nil, usecaseerr.ErrUserBanned)
+
+	handlertest.Serve(c, handler.Login)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAuthHandler_LoginMFA(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(auth.MockAuthService)
+		handler := NewAuthHandler(mockService, &lockout.MockLockoutService{})
+		defer mockService.AssertExpectations(t)
+
+		body := `{"mfa_token":"mfa-token","code":"123456"}`
+		req := httptest.NewRequest(http.MethodPost, "/auth/login/2fa", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+
+		mockService.On("VerifyMFA", mock.Anything, "mfa-token", "123456", mock.Anything).
+			Return(&entities.AuthTokens{AccessToken: "access-token", RefreshToken: "refresh-token"}, nil)
+
+		handlertest.Serve(c, handler.LoginMFA)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"access_token":"access-token","refresh_token":"refresh-token"}`, w.Body.String())
+	})
+
+	t.Run("missing code", func(t *testing.T) {
+		mockService := new(auth.MockAuthService)
+		handler := NewAuthHandler(mockService, &lockout.MockLockoutService{})
+
+		body := `{"mfa_token":"mfa-token"}`
+		req := httptest.NewRequest(http.MethodPost, "/auth/login/2fa", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+
+		handlertest.Serve(c, handler.LoginMFA)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		mockService := new(auth.MockAuthService)
+		handler := NewAuthHandler(mockService, &lockout.MockLockoutService{})
+		defer mockService.AssertExpectations(t)
+
+		body := `{"mfa_token":"mfa-token","code":"000000"}`
+		req := httptest.NewRequest(http.MethodPost, "/auth/login/2fa", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+
+		mockService.On("VerifyMFA", mock.Anything, "mfa-token", "000000", mock.Anything).
+			Return(nil, usecaseerr.ErrInvalidTwoFactorCode)
+
+		handlertest.Serve(c, handler.LoginMFA)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), "invalid two-factor code")
+	})
+
+	t.Run("locked", func(t *testing.T) {
+		mockService := new(auth.MockAuthService)
+		handler := NewAuthHandler(mockService, &lockout.MockLockoutService{})
+		defer mockService.AssertExpectations(t)
+
+		body := `{"mfa_token":"mfa-token","code":"000000"}`
+		req := httptest.NewRequest(http.MethodPost, "/auth/login/2fa", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+
+		mockService.On("VerifyMFA", mock.Anything, "mfa-token", "000000", mock.Anything).
+			Return(nil, usecaseerr.ErrTooManyAttempts)
+
+		handlertest.Serve(c, handler.LoginMFA)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	})
+}
+
+func TestAuthHandler_GetLockouts(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(lockout.MockLockoutService)
+		handler := NewAuthHandler(&auth.MockAuthService{}, mockService)
+		defer mockService.AssertExpectations(t)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/admin/lockouts", nil)
+
+		mockService.On("GetLockouts", mock.Anything).
+			Return([]entities.LoginAttempt{{Kind: entities.LockoutByIP, Key: "10.0.0.1"}}, nil)
+
+		handlertest.Serve(c, handler.GetLockouts)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "10.0.0.1")
+	})
+
+	t.Run("fail service", func(t *testing.T) {
+		mockService := new(lockout.MockLockoutService)
+		handler := NewAuthHandler(&auth.MockAuthService{}, mockService)
+		defer mockService.AssertExpectations(t)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/admin/lockouts", nil)
+
+		mockService.On("GetLockouts", mock.Anything).Return(nil, assert.AnError)
+
+		handlertest.Serve(c, handler.GetLockouts)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestAuthHandler_ClearLockout(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{"success", nil, http.StatusOK},
+		{"invalid kind", usecaseerr.ErrInvalidParams, http.StatusBadRequest},
+		{"not found", usecaseerr.ErrLockoutNotFound, http.StatusNotFound},
+		{"fail service", assert.AnError, http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := new(lockout.MockLockoutService)
+			handler := NewAuthHandler(&auth.MockAuthService{}, mockService)
+			defer mockService.AssertExpectations(t)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodDelete, "/admin/lockouts/phone/+998901234567", nil)
+			c.Params = gin.Params{{Key: "kind", Value: "phone"}, {Key: "key", Value: "+998901234567"}}
+
+			mockService.On("ClearLockout", mock.Anything, entities.LockoutByPhone, "+998901234567").
+				Return(tc.err)
+
+			handlertest.Serve(c, handler.ClearLockout)
+
+			assert.Equal(t, tc.wantCode, w.Code)
+		})
+	}
+}
+
+func TestAuthHandler_JWKS(t *testing.T) {
+	mockService := new(auth.MockAuthService)
+	handler := NewAuthHandler(mockService, &lockout.MockLockoutService{})
+	defer mockService.AssertExpectations(t)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+
+	mockService.On("JWKS").Return(jwtkeys.JWKS{Keys: []jwtkeys.JWK{{Kty: "OKP", Kid: "ed-1", Crv: "Ed25519"}}})
+
+	handlertest.Serve(c, handler.JWKS)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"kid":"ed-1"`)
+	assert.NotEmpty(t, w.Header().Get("Cache-Control"))
+}
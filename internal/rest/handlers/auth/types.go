@@ -1,14 +1,19 @@
 package auth
 
-import "ads-service/internal/usecase/auth"
+import (
+	"ads-service/internal/usecase/auth"
+	"ads-service/internal/usecase/lockout"
+)
 
 type AuthHandler struct {
 	userAuthService auth.AuthService
+	lockoutService  lockout.LockoutService
 }
 
-func NewAuthHandler(userAuthService auth.AuthService) *AuthHandler {
+func NewAuthHandler(userAuthService auth.AuthService, lockoutService lockout.LockoutService) *AuthHandler {
 	return &AuthHandler{
 		userAuthService: userAuthService,
+		lockoutService:  lockoutService,
 	}
 }
 
@@ -18,7 +23,13 @@ type AuthRequest struct {
 }
 
 type LoginResponse struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	MFARequired  bool   `json:"mfa_required,omitempty"`
+	MFAToken     string `json:"mfa_token,omitempty"`
 }
 
+type MFARequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
@@ -0,0 +1,43 @@
+package duplicate
+
+import (
+	"ads-service/internal/errs"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetDuplicates godoc
+// @Summary      Possible duplicates
+// @Description  Other ads of the author and active ads of other users with a similar text or image,
+// @Description  exact copies first. Similarities go from 0 to 1.
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      int  true  "Ad ID"
+// @Success      200  {array}   DuplicateResponse
+// @Failure      400  {object}  middleware.Problem
+// @Failure      404  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /admin/ads/{id}/duplicates [get]
+func (h *DuplicateHandler) GetDuplicates(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		_ = c.Error(errs.Invalid("id", "invalid ad ID"))
+		return
+	}
+
+	duplicates, err := h.duplicateService.FindDuplicates(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to get duplicates: %w", err))
+		return
+	}
+
+	resp := make([]DuplicateResponse, 0, len(duplicates))
+	for i := range duplicates {
+		resp = append(resp, newDuplicateResponse(&duplicates[i]))
+	}
+	c.JSON(http.StatusOK, resp)
+}
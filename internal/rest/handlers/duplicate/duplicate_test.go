@@ -0,0 +1,64 @@
+//nolint:all // testpackage
+package duplicate
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/rest/handlers/handlertest"
+	"ads-service/internal/usecase/duplicate"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newContext(id string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: id}}
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/ads/"+id+"/duplicates", nil)
+	return c, w
+}
+
+func TestDuplicateHandler_GetDuplicates(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(duplicate.MockDuplicateService)
+		handler := NewDuplicateHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("FindDuplicates", mock.Anything, 7).Return([]entities.DuplicateCandidate{
+			{AdID: 3, TextSimilarity: 0.921875, SameAuthor: true},
+		}, nil)
+		c, w := newContext("7")
+
+		handlertest.Serve(c, handler.GetDuplicates)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"text_similarity":0.92`)
+		assert.Contains(t, w.Body.String(), `"same_author":true`)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		handler := NewDuplicateHandler(new(duplicate.MockDuplicateService))
+		c, w := newContext("-1")
+
+		handlertest.Serve(c, handler.GetDuplicates)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("not submitted", func(t *testing.T) {
+		mockService := new(duplicate.MockDuplicateService)
+		handler := NewDuplicateHandler(mockService)
+
+		mockService.On("FindDuplicates", mock.Anything, 7).Return(nil, usecaseerr.ErrFingerprintNotFound)
+		c, w := newContext("7")
+
+		handlertest.Serve(c, handler.GetDuplicates)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
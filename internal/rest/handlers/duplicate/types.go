@@ -0,0 +1,45 @@
+package duplicate
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/usecase/duplicate"
+	"math"
+)
+
+type DuplicateHandler struct {
+	duplicateService duplicate.DuplicateService
+}
+
+func NewDuplicateHandler(duplicateService duplicate.DuplicateService) *DuplicateHandler {
+	return &DuplicateHandler{
+		duplicateService: duplicateService,
+	}
+}
+
+type DuplicateResponse struct {
+	Status          string  `json:"status"`
+	Title           string  `json:"title"`
+	AuthorID        string  `json:"author_id"`
+	TextSimilarity  float64 `json:"text_similarity"`
+	ImageSimilarity float64 `json:"image_similarity"`
+	AdID            int     `json:"ad_id"`
+	SameAuthor      bool    `json:"same_author"`
+	Exact           bool    `json:"exact"`
+}
+
+func newDuplicateResponse(d *entities.DuplicateCandidate) DuplicateResponse {
+	return DuplicateResponse{
+		Status:          string(d.Status),
+		Title:           d.Title,
+		AuthorID:        d.AuthorID,
+		TextSimilarity:  round(d.TextSimilarity),
+		ImageSimilarity: round(d.ImageSimilarity),
+		AdID:            d.AdID,
+		SameAuthor:      d.SameAuthor,
+		Exact:           d.Exact,
+	}
+}
+
+func round(similarity float64) float64 {
+	return math.Round(similarity*100) / 100
+}
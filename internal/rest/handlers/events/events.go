@@ -0,0 +1,54 @@
+package events
+
+import (
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Stream godoc
+// @Summary      Real-time event stream
+// @Description  Server-Sent Events stream of the caller's notifications: ad approved/rejected, new messages and
+// @Description  price changes of favorited ads. Events are delivered only while the connection is open
+// @Tags         events
+// @Produce      text/event-stream
+// @Success      200  {string}  string  "event stream"
+// @Failure      401  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /events [get]
+func (h *EventsHandler) Stream(c *gin.Context) {
+	events, unsubscribe := h.bus.Subscribe(c.GetString("user_id"))
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Writer.WriteHeaderNow()
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(h.keepAlive)
+	defer ticker.Stop()
+
+	ctx := c.Request.Context()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+			c.SSEvent("ping", gin.H{"time": time.Now().UTC()})
+			return true
+		case e, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent(string(e.Type), gin.H{
+				"type":       e.Type,
+				"created_at": e.CreatedAt,
+				"data":       e.Data,
+			})
+			return true
+		}
+	})
+}
@@ -0,0 +1,109 @@
+//nolint:all // testpackage
+package events
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/pkg/eventbus"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// streamRecorder adds CloseNotify which gin's Stream requires from the writer.
+type streamRecorder struct {
+	*httptest.ResponseRecorder
+	mu sync.Mutex
+}
+
+func (r *streamRecorder) CloseNotify() <-chan bool {
+	return make(chan bool)
+}
+
+func (r *streamRecorder) Write(b []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ResponseRecorder.Write(b)
+}
+
+func (r *streamRecorder) WriteString(s string) (int, error) {
+	return r.Write([]byte(s))
+}
+
+func (r *streamRecorder) body() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ResponseRecorder.Body.String()
+}
+
+func runStream(t *testing.T, handler *EventsHandler, userID string) (*streamRecorder, context.CancelFunc, chan struct{}) {
+	t.Helper()
+	w := &streamRecorder{ResponseRecorder: httptest.NewRecorder()}
+	c, _ := gin.CreateTestContext(w)
+	c.Set("user_id", userID)
+	ctx, cancel := context.WithCancel(context.Background())
+	c.Request = httptest.NewRequest(http.MethodGet, "/events", nil).WithContext(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		handler.Stream(c)
+		close(done)
+	}()
+	return w, cancel, done
+}
+
+func TestEventsHandler_Stream(t *testing.T) {
+	t.Run("delivers the user's events", func(t *testing.T) {
+		bus := eventbus.New()
+		handler := NewEventsHandler(bus)
+		w, cancel, done := runStream(t, handler, "user")
+
+		assert.Eventually(t, func() bool {
+			bus.Publish(context.Background(), entities.Event{
+				Type:   entities.EventAdApproved,
+				UserID: "user",
+				Data:   entities.EventData{"ad_id": 7},
+			})
+			return strings.Contains(w.body(), "event:ad.approved")
+		}, time.Second, 10*time.Millisecond)
+
+		cancel()
+		<-done
+		assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.body(), `"ad_id":7`)
+	})
+
+	t.Run("ignores other users' events", func(t *testing.T) {
+		bus := eventbus.New()
+		handler := NewEventsHandler(bus)
+		handler.keepAlive = 5 * time.Millisecond
+		w, cancel, done := runStream(t, handler, "user")
+
+		assert.Eventually(t, func() bool {
+			bus.Publish(context.Background(), entities.Event{Type: entities.EventNewMessage, UserID: "other"})
+			return strings.Contains(w.body(), "event:ping")
+		}, time.Second, 10*time.Millisecond)
+
+		cancel()
+		<-done
+		assert.NotContains(t, w.body(), "message.new")
+	})
+
+	t.Run("stops when the client disconnects", func(t *testing.T) {
+		handler := NewEventsHandler(eventbus.New())
+		_, cancel, done := runStream(t, handler, "user")
+
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("stream did not stop after the request was cancelled")
+		}
+	})
+}
@@ -0,0 +1,21 @@
+package events
+
+import (
+	"ads-service/pkg/eventbus"
+	"time"
+)
+
+// keepAliveInterval keeps idle streams from being cut by proxies.
+const keepAliveInterval = 25 * time.Second
+
+type EventsHandler struct {
+	bus       eventbus.Bus
+	keepAlive time.Duration
+}
+
+func NewEventsHandler(bus eventbus.Bus) *EventsHandler {
+	return &EventsHandler{
+		bus:       bus,
+		keepAlive: keepAliveInterval,
+	}
+}
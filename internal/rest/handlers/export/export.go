@@ -0,0 +1,158 @@
+package export
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs"
+	"ads-service/internal/rest/middleware"
+	"ads-service/pkg/export"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportAds godoc
+// @Summary Export ads
+// @Description Streams the ads matching the filter as a CSV or XLSX file (admin only).
+// @Tags admin
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "csv (default) or xlsx"
+// @Param status query string false "Ad status"
+// @Param category query int false "Category ID"
+// @Param user_id query string false "Author ID"
+// @Param price_min query int false "Minimum price"
+// @Param price_max query int false "Maximum price"
+// @Param text query string false "Substring of the title or description"
+// @Param location query string false "Location"
+// @Param from query string false "First day of creation, YYYY-MM-DD"
+// @Param to query string false "Last day of creation included, YYYY-MM-DD"
+// @Success 200 {file} file
+// @Failure 400 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /admin/export/ads [get]
+// @Security BearerAuth
+func (h *ExportHandler) ExportAds(c *gin.Context) {
+	filter, err := adFilter(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	h.write(c, "ads", func(out export.Writer) error {
+		return h.exportService.ExportAds(c.Request.Context(), filter, out)
+	})
+}
+
+// ExportDecisions godoc
+// @Summary Export moderation decisions
+// @Description Streams the approvals, rejections and appeal decisions of a period as a CSV or XLSX file
+// @Description (admin only).
+// @Tags admin
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "csv (default) or xlsx"
+// @Param from query string false "First day, YYYY-MM-DD, 30 days before to by default"
+// @Param to query string false "Last day included, YYYY-MM-DD, today by default"
+// @Success 200 {file} file
+// @Failure 400 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /admin/export/decisions [get]
+// @Security BearerAuth
+func (h *ExportHandler) ExportDecisions(c *gin.Context) {
+	filter, err := middleware.StatisticsFilter(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	h.write(c, "decisions", func(out export.Writer) error {
+		return h.exportService.ExportDecisions(c.Request.Context(), filter, out)
+	})
+}
+
+// ExportStatistics godoc
+// @Summary Export statistics
+// @Description Writes the statistics of GET /admin/stats as a CSV or XLSX file, one table per breakdown
+// @Description (admin only).
+// @Tags admin
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "csv (default) or xlsx"
+// @Param from query string false "First day, YYYY-MM-DD, 30 days before to by default"
+// @Param to query string false "Last day included, YYYY-MM-DD, today by default"
+// @Param granularity query string false "day (default), week or month"
+// @Success 200 {file} file
+// @Failure 400 {object} middleware.Problem
+// @Failure 500 {object} middleware.Problem
+// @Router /admin/export/stats [get]
+// @Security BearerAuth
+func (h *ExportHandler) ExportStatistics(c *gin.Context) {
+	filter, err := middleware.StatisticsFilter(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	h.write(c, "statistics", func(out export.Writer) error {
+		return h.exportService.ExportStatistics(c.Request.Context(), filter, out)
+	})
+}
+
+// write streams the export to the response as an attachment. An error before the first byte is sent
+// is returned as a problem, after it the response can only be cut short.
+func (h *ExportHandler) write(c *gin.Context, name string, fn func(out export.Writer) error) {
+	format := export.Format(c.DefaultQuery("format", string(export.CSV)))
+	out, err := export.New(format, c.Writer)
+	if err != nil {
+		_ = c.Error(errs.Invalid("format", err.Error()))
+		return
+	}
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.%s"`,
+		name, time.Now().UTC().Format(time.DateOnly), format))
+
+	if err = fn(out); err == nil {
+		err = out.Close()
+	}
+	if err == nil {
+		return
+	}
+	if c.Writer.Written() {
+		_ = c.Error(err)
+		c.Abort()
+		return
+	}
+	c.Writer.Header().Del("Content-Type")
+	c.Writer.Header().Del("Content-Disposition")
+	_ = c.Error(fmt.Errorf("failed to export %s: %w", name, err))
+}
+
+// adFilter reads the filters of the ads listing, the to day is included.
+func adFilter(c *gin.Context) (*entities.AdFilter, error) {
+	filter := &entities.AdFilter{
+		Status:   c.Query("status"),
+		UserID:   c.Query("user_id"),
+		Text:     c.Query("text"),
+		Location: c.Query("location"),
+	}
+	numbers := map[string]*int{"category": &filter.CategoryID, "price_min": &filter.PriceMin,
+		"price_max": &filter.PriceMax}
+	for param, value := range numbers {
+		if raw := c.Query(param); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil {
+				return nil, errs.Invalid(param, fmt.Sprintf("invalid %s, expected a number", param))
+			}
+			*value = n
+		}
+	}
+
+	from, to, err := middleware.Period(c)
+	if err != nil {
+		return nil, err
+	}
+	filter.DateFrom = from
+	if !to.IsZero() {
+		filter.DateTo = to.Add(-time.Microsecond)
+	}
+	return filter, nil
+}
@@ -0,0 +1,191 @@
+//nolint:all // testpackage
+package export
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/rest/handlers/handlertest"
+	"ads-service/internal/rest/middleware"
+	"ads-service/internal/usecase/export"
+	table "ads-service/pkg/export"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newContext(target string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+	c.Set("user_id", "admin-1")
+	return c, w
+}
+
+func writeRow(args mock.Arguments) {
+	out := args.Get(2).(table.Writer)
+	_ = out.Sheet("ads", "id")
+	_ = out.Row(3)
+}
+
+func TestExportHandler_ExportAds(t *testing.T) {
+	t.Run("csv with filters", func(t *testing.T) {
+		mockService := new(export.MockExportService)
+		handler := NewExportHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("ExportAds", mock.Anything, mock.MatchedBy(func(f *entities.AdFilter) bool {
+			return f.Status == "approved" && f.CategoryID == 2 && f.PriceMax == 500 &&
+				f.DateFrom.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)) &&
+				f.DateTo.Before(time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)) &&
+				f.DateTo.After(time.Date(2025, 3, 2, 23, 59, 59, 0, time.UTC))
+		}), mock.Anything).Run(writeRow).Return(nil)
+
+		c, w := newContext("/admin/export/ads?status=approved&category=2&price_max=500&from=2025-03-01&to=2025-03-02")
+		handlertest.Serve(c, handler.ExportAds)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), `attachment; filename="ads-`)
+		assert.Equal(t, "id\n3\n", w.Body.String())
+	})
+
+	t.Run("xlsx", func(t *testing.T) {
+		mockService := new(export.MockExportService)
+		handler := NewExportHandler(mockService)
+		mockService.On("ExportAds", mock.Anything, mock.Anything, mock.Anything).Run(writeRow).Return(nil)
+
+		c, w := newContext("/admin/export/ads?format=xlsx")
+		handlertest.Serve(c, handler.ExportAds)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, table.XLSX.ContentType(), w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), ".xlsx")
+		assert.Equal(t, "PK", w.Body.String()[:2])
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		handler := NewExportHandler(new(export.MockExportService))
+
+		c, w := newContext("/admin/export/ads?format=pdf")
+		handlertest.Serve(c, handler.ExportAds)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		handler := NewExportHandler(new(export.MockExportService))
+
+		c, w := newContext("/admin/export/ads?price_min=cheap")
+		handlertest.Serve(c, handler.ExportAds)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "price_min")
+	})
+
+	t.Run("error before writing", func(t *testing.T) {
+		mockService := new(export.MockExportService)
+		handler := NewExportHandler(mockService)
+		mockService.On("ExportAds", mock.Anything, mock.Anything, mock.Anything).Return(usecaseerr.ErrExporting)
+
+		c, w := newContext("/admin/export/ads")
+		handlertest.Serve(c, handler.ExportAds)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, middleware.ProblemContentType, w.Header().Get("Content-Type"))
+	})
+
+	t.Run("error within the first batch", func(t *testing.T) {
+		mockService := new(export.MockExportService)
+		handler := NewExportHandler(mockService)
+		mockService.On("ExportAds", mock.Anything, mock.Anything, mock.Anything).Run(writeRow).
+			Return(errors.New("connection lost"))
+
+		c, w := newContext("/admin/export/ads")
+		handlertest.Serve(c, handler.ExportAds)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Disposition"))
+	})
+
+	t.Run("error while writing", func(t *testing.T) {
+		mockService := new(export.MockExportService)
+		handler := NewExportHandler(mockService)
+		// the csv writer sends rows in batches of 100, the first batch starts the response
+		mockService.On("ExportAds", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			out := args.Get(2).(table.Writer)
+			_ = out.Sheet("ads", "id")
+			for i := 0; i < 100; i++ {
+				_ = out.Row(3)
+			}
+		}).Return(errors.New("connection lost"))
+
+		c, w := newContext("/admin/export/ads")
+		handlertest.Serve(c, handler.ExportAds)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, strings.HasPrefix(w.Body.String(), "id\n3\n3\n"))
+		assert.Len(t, c.Errors, 1)
+	})
+}
+
+func TestExportHandler_ExportDecisions(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(export.MockExportService)
+		handler := NewExportHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("ExportDecisions", mock.Anything, entities.StatisticsFilter{
+			From: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+		}, mock.Anything).Return(nil)
+
+		c, w := newContext("/admin/export/decisions?from=2025-03-01&to=2025-03-31")
+		handlertest.Serve(c, handler.ExportDecisions)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		mockService := new(export.MockExportService)
+		handler := NewExportHandler(mockService)
+		mockService.On("ExportDecisions", mock.Anything, mock.Anything, mock.Anything).
+			Return(usecaseerr.ErrInvalidStatistics)
+
+		c, w := newContext("/admin/export/decisions?from=2025-03-01&to=2020-03-31")
+		handlertest.Serve(c, handler.ExportDecisions)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		handler := NewExportHandler(new(export.MockExportService))
+
+		c, w := newContext("/admin/export/decisions?from=yesterday")
+		handlertest.Serve(c, handler.ExportDecisions)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestExportHandler_ExportStatistics(t *testing.T) {
+	mockService := new(export.MockExportService)
+	handler := NewExportHandler(mockService)
+	defer mockService.AssertExpectations(t)
+
+	mockService.On("ExportStatistics", mock.Anything, entities.StatisticsFilter{Granularity: entities.GranularityWeek},
+		mock.Anything).Return(nil)
+
+	c, w := newContext("/admin/export/stats?granularity=week&format=xlsx")
+	handlertest.Serve(c, handler.ExportStatistics)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Disposition"), `filename="statistics-`)
+}
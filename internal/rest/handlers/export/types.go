@@ -0,0 +1,15 @@
+package export
+
+import (
+	"ads-service/internal/usecase/export"
+)
+
+type ExportHandler struct {
+	exportService export.ExportService
+}
+
+func NewExportHandler(exportService export.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
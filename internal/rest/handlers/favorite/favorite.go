@@ -0,0 +1,82 @@
+package favorite
+
+import (
+	"ads-service/internal/errs"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AddFavorite godoc
+// @Summary      Add ad to favorites
+// @Description  Bookmarks a published ad of another user. Adding the same ad twice is not an error
+// @Tags         favorites
+// @Produce      json
+// @Param        adID  path      int  true  "Ad ID"
+// @Success      201  {object}  map[string]string
+// @Failure      400  {object}  middleware.Problem
+// @Failure      404  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /favorites/{adID} [post]
+func (h *FavoriteHandler) AddFavorite(c *gin.Context) {
+	adID, err := strconv.Atoi(c.Param("adID"))
+	if err != nil || adID <= 0 {
+		_ = c.Error(errs.Invalid("adID", "invalid ad ID"))
+		return
+	}
+
+	err = h.favoriteService.Add(c.Request.Context(), c.GetString("user_id"), adID)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to add favorite: %w", err))
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "ad added to favorites"})
+}
+
+// RemoveFavorite godoc
+// @Summary      Remove ad from favorites
+// @Tags         favorites
+// @Produce      json
+// @Param        adID  path      int  true  "Ad ID"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  middleware.Problem
+// @Failure      404  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /favorites/{adID} [delete]
+func (h *FavoriteHandler) RemoveFavorite(c *gin.Context) {
+	adID, err := strconv.Atoi(c.Param("adID"))
+	if err != nil || adID <= 0 {
+		_ = c.Error(errs.Invalid("adID", "invalid ad ID"))
+		return
+	}
+
+	err = h.favoriteService.Remove(c.Request.Context(), c.GetString("user_id"), adID)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to remove favorite: %w", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "ad removed from favorites"})
+}
+
+// GetFavorites godoc
+// @Summary      List favorites
+// @Description  Returns saved ads with their current state. Deleted, unpublished or inactive ads
+// @Description  stay in the list with Available=false and the title saved at bookmarking time
+// @Tags         favorites
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /favorites [get]
+func (h *FavoriteHandler) GetFavorites(c *gin.Context) {
+	favorites, err := h.favoriteService.List(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to get favorites: %w", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"favorites": favorites})
+}
@@ -0,0 +1,134 @@
+//nolint:all // testpackage
+package favorite
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/rest/handlers/handlertest"
+	"ads-service/internal/usecase/favorite"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newContext(method, adID string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set("user_id", "buyer")
+	c.Params = gin.Params{{Key: "adID", Value: adID}}
+	c.Request = httptest.NewRequest(method, "/favorites/"+adID, nil)
+	return c, w
+}
+
+func TestFavoriteHandler_AddFavorite(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(favorite.MockFavoriteService)
+		handler := NewFavoriteHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("Add", mock.Anything, "buyer", 1).Return(nil)
+		c, w := newContext(http.MethodPost, "1")
+
+		handlertest.Serve(c, handler.AddFavorite)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("ad not available", func(t *testing.T) {
+		mockService := new(favorite.MockFavoriteService)
+		handler := NewFavoriteHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("Add", mock.Anything, "buyer", 2).Return(usecaseerr.ErrAdNotAvailable)
+		c, w := newContext(http.MethodPost, "2")
+
+		handlertest.Serve(c, handler.AddFavorite)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("own ad", func(t *testing.T) {
+		mockService := new(favorite.MockFavoriteService)
+		handler := NewFavoriteHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("Add", mock.Anything, "buyer", 3).Return(usecaseerr.ErrFavoriteOwnAd)
+		c, w := newContext(http.MethodPost, "3")
+
+		handlertest.Serve(c, handler.AddFavorite)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		handler := NewFavoriteHandler(new(favorite.MockFavoriteService))
+		c, w := newContext(http.MethodPost, "abc")
+
+		handlertest.Serve(c, handler.AddFavorite)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestFavoriteHandler_RemoveFavorite(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(favorite.MockFavoriteService)
+		handler := NewFavoriteHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("Remove", mock.Anything, "buyer", 1).Return(nil)
+		c, w := newContext(http.MethodDelete, "1")
+
+		handlertest.Serve(c, handler.RemoveFavorite)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockService := new(favorite.MockFavoriteService)
+		handler := NewFavoriteHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("Remove", mock.Anything, "buyer", 1).Return(usecaseerr.ErrFavoriteNotFound)
+		c, w := newContext(http.MethodDelete, "1")
+
+		handlertest.Serve(c, handler.RemoveFavorite)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestFavoriteHandler_GetFavorites(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(favorite.MockFavoriteService)
+		handler := NewFavoriteHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("List", mock.Anything, "buyer").
+			Return([]entities.Favorite{{AdID: 3, Title: "Deleted car"}}, nil)
+		c, w := newContext(http.MethodGet, "")
+
+		handlertest.Serve(c, handler.GetFavorites)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"Available":false`)
+		assert.Contains(t, w.Body.String(), "Deleted car")
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		mockService := new(favorite.MockFavoriteService)
+		handler := NewFavoriteHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("List", mock.Anything, "buyer").Return(nil, usecaseerr.ErrGettingFavorites)
+		c, w := newContext(http.MethodGet, "")
+
+		handlertest.Serve(c, handler.GetFavorites)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,13 @@
+package favorite
+
+import "ads-service/internal/usecase/favorite"
+
+type FavoriteHandler struct {
+	favoriteService favorite.FavoriteService
+}
+
+func NewFavoriteHandler(favoriteService favorite.FavoriteService) *FavoriteHandler {
+	return &FavoriteHandler{
+		favoriteService: favoriteService,
+	}
+}
@@ -0,0 +1,15 @@
+// Package handlertest holds helpers shared by the handler tests.
+package handlertest
+
+import (
+	"ads-service/internal/rest/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Serve runs the handler and then renders its errors the way the router does,
+// the test context has no handler chain so the middleware finds nothing left to call.
+func Serve(c *gin.Context, handler gin.HandlerFunc) {
+	handler(c)
+	middleware.Errors()(c)
+}
@@ -0,0 +1,81 @@
+package health
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Healthz godoc
+// @Summary Liveness probe
+// @Description Returns 200 while the process serves requests, dependencies are not checked.
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /healthz [get]
+func (h *HealthHandler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz godoc
+// @Summary Readiness probe
+// @Description Returns 200 when the database answers and the image storage is writable, 503 otherwise
+// @Description and while the server is shutting down.
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /readyz [get]
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	if h.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "draining"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), checkTimeout)
+	defer cancel()
+	checks := gin.H{"database": "ok", "storage": "ok"}
+	status := http.StatusOK
+	// the probe is public, the errors may name hosts and paths, so they stay in the log
+	if err := h.pool.Ping(ctx); err != nil {
+		slog.ErrorContext(ctx, "readiness check of the database failed", "error", err)
+		checks["database"] = unavailable
+		status = http.StatusServiceUnavailable
+	}
+	if err := h.checkStorage(); err != nil {
+		slog.ErrorContext(ctx, "readiness check of the storage failed", "error", err)
+		checks["storage"] = unavailable
+		status = http.StatusServiceUnavailable
+	}
+
+	if status == http.StatusOK {
+		c.JSON(status, gin.H{"status": "ready", "checks": checks})
+		return
+	}
+	c.JSON(status, gin.H{"status": "not ready", "checks": checks})
+}
+
+// Drain makes the readiness probe fail, so that the load balancer stops sending requests before shutdown.
+func (h *HealthHandler) Drain() {
+	h.draining.Store(true)
+}
+
+// checkStorage creates and removes a file in the storage directory.
+func (h *HealthHandler) checkStorage() error {
+	if err := os.MkdirAll(h.storageDir, 0o750); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(h.storageDir, ".readyz-*")
+	if err != nil {
+		return err
+	}
+	name := file.Name()
+	if err = file.Close(); err != nil {
+		_ = os.Remove(name)
+		return err
+	}
+	return os.Remove(name)
+}
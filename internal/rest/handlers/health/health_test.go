@@ -0,0 +1,88 @@
+//nolint:all // testpackage
+package health
+
+import (
+	"ads-service/pkg/db"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newContext(target string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+	return c, w
+}
+
+func TestHealthHandler_Healthz(t *testing.T) {
+	handler := NewHealthHandler(new(db.MockPool))
+
+	c, w := newContext("/healthz")
+	handler.Healthz(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestHealthHandler_Readyz(t *testing.T) {
+	t.Run("ready", func(t *testing.T) {
+		pool := new(db.MockPool)
+		pool.On("Ping", mock.Anything).Return(nil)
+		dir := t.TempDir()
+		handler := &HealthHandler{pool: pool, storageDir: dir}
+
+		c, w := newContext("/readyz")
+		handler.Readyz(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"ready"`)
+		entries, _ := os.ReadDir(dir)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("database down", func(t *testing.T) {
+		pool := new(db.MockPool)
+		pool.On("Ping", mock.Anything).Return(errors.New("connection refused"))
+		handler := &HealthHandler{pool: pool, storageDir: t.TempDir()}
+
+		c, w := newContext("/readyz")
+		handler.Readyz(c)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Contains(t, w.Body.String(), `"database":"unavailable"`)
+		assert.NotContains(t, w.Body.String(), "connection refused")
+	})
+
+	t.Run("storage not writable", func(t *testing.T) {
+		pool := new(db.MockPool)
+		pool.On("Ping", mock.Anything).Return(nil)
+		file := filepath.Join(t.TempDir(), "file")
+		assert.NoError(t, os.WriteFile(file, nil, 0o600))
+		handler := &HealthHandler{pool: pool, storageDir: filepath.Join(file, "uploads")}
+
+		c, w := newContext("/readyz")
+		handler.Readyz(c)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Contains(t, w.Body.String(), `"database":"ok"`)
+		assert.Contains(t, w.Body.String(), `"storage":"unavailable"`)
+	})
+
+	t.Run("draining", func(t *testing.T) {
+		handler := NewHealthHandler(new(db.MockPool))
+		handler.Drain()
+
+		c, w := newContext("/readyz")
+		handler.Readyz(c)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Contains(t, w.Body.String(), "draining")
+	})
+}
@@ -0,0 +1,27 @@
+package health
+
+import (
+	"ads-service/internal/usecase/user"
+	"ads-service/pkg/db"
+	"sync/atomic"
+	"time"
+)
+
+// checkTimeout - time given to every readiness check.
+const checkTimeout = 2 * time.Second
+
+// unavailable - the value of a failed check.
+const unavailable = "unavailable"
+
+type HealthHandler struct {
+	pool       db.Pool
+	storageDir string
+	draining   atomic.Bool
+}
+
+func NewHealthHandler(pool db.Pool) *HealthHandler {
+	return &HealthHandler{
+		pool:       pool,
+		storageDir: user.UploadsDir,
+	}
+}
@@ -0,0 +1,224 @@
+package message
+
+import (
+	"ads-service/internal/errs"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StartConversation godoc
+// @Summary      Contact the seller
+// @Description  Opens a conversation about a published ad with its author and sends the first message.
+// @Description  Writing about the same ad again continues the existing conversation
+// @Tags         messages
+// @Accept       json
+// @Produce      json
+// @Param        request  body      StartConversationRequest  true  "Ad ID and message text"
+// @Success      201  {object}  StartConversationResponse
+// @Failure      400  {object}  middleware.Problem
+// @Failure      403  {object}  middleware.Problem
+// @Failure      404  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /conversations [post]
+func (h *MessageHandler) StartConversation(c *gin.Context) {
+	var req StartConversationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(fmt.Errorf("%w: %w", errs.ErrInvalidBody, err))
+		return
+	}
+
+	conv, msg, err := h.messageService.StartConversation(c.Request.Context(), c.GetString("user_id"),
+		req.AdID, req.Body)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to start conversation: %w", err))
+		return
+	}
+	c.JSON(http.StatusCreated, StartConversationResponse{
+		Conversation: newConversationResponse(conv),
+		Message:      newMessageResponse(msg),
+	})
+}
+
+// GetConversations godoc
+// @Summary      List conversations
+// @Description  Conversations of the user as buyer or seller, most recent first, with unread counters
+// @Tags         messages
+// @Produce      json
+// @Param        page   query     int  false  "Page number, starts with 1"
+// @Param        limit  query     int  false  "Page size, 20 by default, at most 100"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /conversations [get]
+func (h *MessageHandler) GetConversations(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	conversations, err := h.messageService.ListConversations(c.Request.Context(), c.GetString("user_id"),
+		page, limit)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to get conversations: %w", err))
+		return
+	}
+
+	resp := make([]ConversationResponse, 0, len(conversations))
+	for i := range conversations {
+		resp = append(resp, newConversationResponse(&conversations[i]))
+	}
+	c.JSON(http.StatusOK, gin.H{"conversations": resp})
+}
+
+// GetUnreadCount godoc
+// @Summary      Unread messages counter
+// @Tags         messages
+// @Produce      json
+// @Success      200  {object}  map[string]int
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /conversations/unread [get]
+func (h *MessageHandler) GetUnreadCount(c *gin.Context) {
+	count, err := h.messageService.UnreadCount(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to count unread messages: %w", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"unread": count})
+}
+
+// GetMessages godoc
+// @Summary      Messages of a conversation
+// @Description  Returns messages newest first and marks the incoming ones as read.
+// @Description  Pass the smallest received ID as before to load older messages
+// @Tags         messages
+// @Produce      json
+// @Param        id      path      int  true   "Conversation ID"
+// @Param        before  query     int  false  "Return messages with ID lower than this"
+// @Param        limit   query     int  false  "Page size, 20 by default, at most 100"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  middleware.Problem
+// @Failure      404  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /conversations/{id}/messages [get]
+func (h *MessageHandler) GetMessages(c *gin.Context) {
+	id, ok := conversationID(c)
+	if !ok {
+		return
+	}
+	var before int64
+	if v := c.Query("before"); v != "" {
+		parsed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			_ = c.Error(errs.Invalid("before", "invalid before parameter"))
+			return
+		}
+		before = parsed
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	messages, err := h.messageService.GetMessages(c.Request.Context(), c.GetString("user_id"), id, before, limit)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to get messages: %w", err))
+		return
+	}
+
+	resp := make([]MessageResponse, 0, len(messages))
+	for i := range messages {
+		resp = append(resp, newMessageResponse(&messages[i]))
+	}
+	c.JSON(http.StatusOK, gin.H{"messages": resp})
+}
+
+// SendMessage godoc
+// @Summary      Send a message
+// @Tags         messages
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                 true  "Conversation ID"
+// @Param        request  body      SendMessageRequest  true  "Message text"
+// @Success      201  {object}  MessageResponse
+// @Failure      400  {object}  middleware.Problem
+// @Failure      403  {object}  middleware.Problem
+// @Failure      404  {object}  middleware.Problem
+// @Failure      409  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /conversations/{id}/messages [post]
+func (h *MessageHandler) SendMessage(c *gin.Context) {
+	id, ok := conversationID(c)
+	if !ok {
+		return
+	}
+	var req SendMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(fmt.Errorf("%w: %w", errs.ErrInvalidBody, err))
+		return
+	}
+
+	msg, err := h.messageService.SendMessage(c.Request.Context(), c.GetString("user_id"), id, req.Body)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to send message: %w", err))
+		return
+	}
+	c.JSON(http.StatusCreated, newMessageResponse(msg))
+}
+
+// BlockBuyer godoc
+// @Summary      Block the buyer
+// @Description  Seller only. The buyer can no longer write in any conversation with the seller
+// @Tags         messages
+// @Produce      json
+// @Param        id   path      int  true  "Conversation ID"
+// @Success      200  {object}  map[string]string
+// @Failure      403  {object}  middleware.Problem
+// @Failure      404  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /conversations/{id}/block [post]
+func (h *MessageHandler) BlockBuyer(c *gin.Context) {
+	id, ok := conversationID(c)
+	if !ok {
+		return
+	}
+	if err := h.messageService.BlockBuyer(c.Request.Context(), c.GetString("user_id"), id); err != nil {
+		_ = c.Error(fmt.Errorf("failed to block buyer: %w", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "buyer blocked"})
+}
+
+// UnblockBuyer godoc
+// @Summary      Unblock the buyer
+// @Tags         messages
+// @Produce      json
+// @Param        id   path      int  true  "Conversation ID"
+// @Success      200  {object}  map[string]string
+// @Failure      403  {object}  middleware.Problem
+// @Failure      404  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /conversations/{id}/block [delete]
+func (h *MessageHandler) UnblockBuyer(c *gin.Context) {
+	id, ok := conversationID(c)
+	if !ok {
+		return
+	}
+	if err := h.messageService.UnblockBuyer(c.Request.Context(), c.GetString("user_id"), id); err != nil {
+		_ = c.Error(fmt.Errorf("failed to unblock buyer: %w", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "buyer unblocked"})
+}
+
+func conversationID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		_ = c.Error(errs.Invalid("id", "invalid conversation ID"))
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,155 @@
+//nolint:all // testpackage
+package message
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/rest/handlers/handlertest"
+	"ads-service/internal/usecase/message"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newContext(method, target, body, id string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set("user_id", "buyer")
+	if id != "" {
+		c.Params = gin.Params{{Key: "id", Value: id}}
+	}
+	c.Request = httptest.NewRequest(method, target, strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	return c, w
+}
+
+func TestMessageHandler_StartConversation(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(message.MockMessageService)
+		handler := NewMessageHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("StartConversation", mock.Anything, "buyer", 1, "Hello").Return(
+			&entities.Conversation{ID: 5, AdID: 1, BuyerID: "buyer", SellerID: "seller"},
+			&entities.Message{ID: 1, ConversationID: 5, SenderID: "buyer", Body: "Hello"}, nil)
+		c, w := newContext(http.MethodPost, "/conversations", `{"ad_id":1,"body":"Hello"}`, "")
+
+		handlertest.Serve(c, handler.StartConversation)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"conversation_id":5`)
+		assert.NotContains(t, w.Body.String(), "read_at")
+	})
+
+	t.Run("blocked", func(t *testing.T) {
+		mockService := new(message.MockMessageService)
+		handler := NewMessageHandler(mockService)
+
+		mockService.On("StartConversation", mock.Anything, "buyer", 1, "Hello").
+			Return(nil, nil, usecaseerr.ErrUserBlocked)
+		c, w := newContext(http.MethodPost, "/conversations", `{"ad_id":1,"body":"Hello"}`, "")
+
+		handlertest.Serve(c, handler.StartConversation)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("missing ad", func(t *testing.T) {
+		handler := NewMessageHandler(new(message.MockMessageService))
+		c, w := newContext(http.MethodPost, "/conversations", `{"body":"Hello"}`, "")
+
+		handlertest.Serve(c, handler.StartConversation)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestMessageHandler_GetMessages(t *testing.T) {
+	t.Run("success with cursor", func(t *testing.T) {
+		mockService := new(message.MockMessageService)
+		handler := NewMessageHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		readAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		mockService.On("GetMessages", mock.Anything, "buyer", 5, int64(30), 10).
+			Return([]entities.Message{{ID: 29, Body: "hi", ReadAt: readAt}}, nil)
+		c, w := newContext(http.MethodGet, "/conversations/5/messages?before=30&limit=10", "", "5")
+
+		handlertest.Serve(c, handler.GetMessages)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"read_at":"2025-01-01T00:00:00Z"`)
+	})
+
+	t.Run("not a participant", func(t *testing.T) {
+		mockService := new(message.MockMessageService)
+		handler := NewMessageHandler(mockService)
+
+		mockService.On("GetMessages", mock.Anything, "buyer", 5, int64(0), 0).
+			Return(nil, usecaseerr.ErrConversationNotFound)
+		c, w := newContext(http.MethodGet, "/conversations/5/messages", "", "5")
+
+		handlertest.Serve(c, handler.GetMessages)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		handler := NewMessageHandler(new(message.MockMessageService))
+		c, w := newContext(http.MethodGet, "/conversations/5/messages?before=x", "", "5")
+
+		handlertest.Serve(c, handler.GetMessages)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestMessageHandler_SendMessage(t *testing.T) {
+	t.Run("locked", func(t *testing.T) {
+		mockService := new(message.MockMessageService)
+		handler := NewMessageHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("SendMessage", mock.Anything, "buyer", 5, "hi").Return(nil, usecaseerr.ErrConversationLocked)
+		c, w := newContext(http.MethodPost, "/conversations/5/messages", `{"body":"hi"}`, "5")
+
+		handlertest.Serve(c, handler.SendMessage)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestMessageHandler_GetConversations(t *testing.T) {
+	mockService := new(message.MockMessageService)
+	handler := NewMessageHandler(mockService)
+	defer mockService.AssertExpectations(t)
+
+	mockService.On("ListConversations", mock.Anything, "buyer", 2, 0).
+		Return([]entities.Conversation{{ID: 5, UnreadCount: 3, Locked: true}}, nil)
+	c, w := newContext(http.MethodGet, "/conversations?page=2", "", "")
+
+	handlertest.Serve(c, handler.GetConversations)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"unread_count":3`)
+	assert.Contains(t, w.Body.String(), `"locked":true`)
+}
+
+func TestMessageHandler_BlockBuyer(t *testing.T) {
+	mockService := new(message.MockMessageService)
+	handler := NewMessageHandler(mockService)
+	defer mockService.AssertExpectations(t)
+
+	mockService.On("BlockBuyer", mock.Anything, "buyer", 5).Return(usecaseerr.ErrAccessDenied)
+	c, w := newContext(http.MethodPost, "/conversations/5/block", "", "5")
+
+	handlertest.Serve(c, handler.BlockBuyer)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -0,0 +1,82 @@
+package message
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/usecase/message"
+	"time"
+)
+
+type MessageHandler struct {
+	messageService message.MessageService
+}
+
+func NewMessageHandler(messageService message.MessageService) *MessageHandler {
+	return &MessageHandler{
+		messageService: messageService,
+	}
+}
+
+type StartConversationRequest struct {
+	Body string `json:"body" binding:"required"`
+	AdID int    `json:"ad_id" binding:"required"`
+}
+
+type SendMessageRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+type ConversationResponse struct {
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	BuyerID     string    `json:"buyer_id"`
+	SellerID    string    `json:"seller_id"`
+	AdTitle     string    `json:"ad_title"`
+	ID          int       `json:"id"`
+	AdID        int       `json:"ad_id,omitempty"`
+	UnreadCount int       `json:"unread_count"`
+	Locked      bool      `json:"locked"`
+	Blocked     bool      `json:"blocked"`
+}
+
+type MessageResponse struct {
+	CreatedAt      time.Time  `json:"created_at"`
+	ReadAt         *time.Time `json:"read_at,omitempty"`
+	SenderID       string     `json:"sender_id"`
+	Body           string     `json:"body"`
+	ConversationID int        `json:"conversation_id"`
+	ID             int64      `json:"id"`
+}
+
+type StartConversationResponse struct {
+	Conversation ConversationResponse `json:"conversation"`
+	Message      MessageResponse      `json:"message"`
+}
+
+func newConversationResponse(c *entities.Conversation) ConversationResponse {
+	return ConversationResponse{
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
+		BuyerID:     c.BuyerID,
+		SellerID:    c.SellerID,
+		AdTitle:     c.AdTitle,
+		ID:          c.ID,
+		AdID:        c.AdID,
+		UnreadCount: c.UnreadCount,
+		Locked:      c.Locked,
+		Blocked:     c.Blocked,
+	}
+}
+
+func newMessageResponse(m *entities.Message) MessageResponse {
+	resp := MessageResponse{
+		CreatedAt:      m.CreatedAt,
+		SenderID:       m.SenderID,
+		Body:           m.Body,
+		ConversationID: m.ConversationID,
+		ID:             m.ID,
+	}
+	if !m.ReadAt.IsZero() {
+		resp.ReadAt = &m.ReadAt
+	}
+	return resp
+}
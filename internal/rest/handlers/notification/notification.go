@@ -0,0 +1,150 @@
+package notification
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetNotifications godoc
+// @Summary      Notification center
+// @Description  Notifications of the user newest first: moderation results, messages and saved search hits
+// @Tags         notifications
+// @Produce      json
+// @Param        unread  query     bool  false  "Only unread notifications"
+// @Param        page    query     int   false  "Page number, starts with 1"
+// @Param        limit   query     int   false  "Page size, 20 by default, at most 100"
+// @Success      200  {object}  NotificationListResponse
+// @Failure      400  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /notifications [get]
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	var unreadOnly bool
+	if v := c.Query("unread"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			_ = c.Error(errs.Invalid("unread", "invalid unread parameter"))
+			return
+		}
+		unreadOnly = parsed
+	}
+	page, _ := strconv.Atoi(c.Query("page"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	ctx, userID := c.Request.Context(), c.GetString("user_id")
+	notifications, err := h.notificationService.List(ctx, userID, unreadOnly, page, limit)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to get notifications: %w", err))
+		return
+	}
+	unread, err := h.notificationService.UnreadCount(ctx, userID)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to get notifications: %w", err))
+		return
+	}
+
+	resp := NotificationListResponse{
+		Notifications: make([]NotificationResponse, 0, len(notifications)),
+		Unread:        unread,
+	}
+	for i := range notifications {
+		resp.Notifications = append(resp.Notifications, newNotificationResponse(&notifications[i]))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// MarkRead godoc
+// @Summary      Mark a notification as read
+// @Tags         notifications
+// @Produce      json
+// @Param        id   path      int  true  "Notification ID"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  middleware.Problem
+// @Failure      404  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		_ = c.Error(errs.Invalid("id", "invalid notification ID"))
+		return
+	}
+	if err = h.notificationService.MarkRead(c.Request.Context(), c.GetString("user_id"), id); err != nil {
+		_ = c.Error(fmt.Errorf("failed to mark notification as read: %w", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "notification marked as read"})
+}
+
+// MarkAllRead godoc
+// @Summary      Mark all notifications as read
+// @Tags         notifications
+// @Produce      json
+// @Success      200  {object}  map[string]int64
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	n, err := h.notificationService.MarkAllRead(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to mark notifications as read: %w", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"updated": n})
+}
+
+// GetPreferences godoc
+// @Summary      Notification channels
+// @Description  In-app notifications are on by default, SMS goes to the account phone, email to email_address
+// @Tags         notifications
+// @Produce      json
+// @Success      200  {object}  PreferencesResponse
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /notifications/preferences [get]
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	prefs, err := h.notificationService.GetPreferences(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to get preferences: %w", err))
+		return
+	}
+	c.JSON(http.StatusOK, newPreferencesResponse(prefs))
+}
+
+// UpdatePreferences godoc
+// @Summary      Change notification channels
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Param        request  body      PreferencesRequest  true  "Enabled channels"
+// @Success      200  {object}  PreferencesResponse
+// @Failure      400  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /notifications/preferences [put]
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	var req PreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(fmt.Errorf("%w: %w", errs.ErrInvalidBody, err))
+		return
+	}
+
+	prefs := &entities.NotificationPreferences{
+		UserID:       c.GetString("user_id"),
+		EmailAddress: req.EmailAddress,
+		InApp:        req.InApp,
+		SMS:          req.SMS,
+		Email:        req.Email,
+	}
+	if err := h.notificationService.UpdatePreferences(c.Request.Context(), prefs); err != nil {
+		_ = c.Error(fmt.Errorf("failed to update preferences: %w", err))
+		return
+	}
+	c.JSON(http.StatusOK, newPreferencesResponse(prefs))
+}
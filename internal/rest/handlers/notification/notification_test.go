@@ -0,0 +1,180 @@
+//nolint:all // testpackage
+package notification
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/rest/handlers/handlertest"
+	"ads-service/internal/usecase/notification"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newContext(method, target, body, id string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set("user_id", "user")
+	if id != "" {
+		c.Params = gin.Params{{Key: "id", Value: id}}
+	}
+	c.Request = httptest.NewRequest(method, target, strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	return c, w
+}
+
+func TestNotificationHandler_GetNotifications(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(notification.MockNotificationService)
+		handler := NewNotificationHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("List", mock.Anything, "user", true, 2, 10).Return([]entities.Notification{
+			{ID: 1, Kind: entities.NotificationModeration, Subject: "rejected"},
+			{ID: 2, Kind: entities.NotificationMessage, ReadAt: time.Now()},
+		}, nil)
+		mockService.On("UnreadCount", mock.Anything, "user").Return(1, nil)
+		c, w := newContext(http.MethodGet, "/notifications?unread=true&page=2&limit=10", "", "")
+
+		handlertest.Serve(c, handler.GetNotifications)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"unread":1`)
+		assert.Contains(t, w.Body.String(), `"kind":"moderation"`)
+		assert.Equal(t, 1, strings.Count(w.Body.String(), "read_at"))
+	})
+
+	t.Run("invalid unread", func(t *testing.T) {
+		mockService := new(notification.MockNotificationService)
+		handler := NewNotificationHandler(mockService)
+		c, w := newContext(http.MethodGet, "/notifications?unread=maybe", "", "")
+
+		handlertest.Serve(c, handler.GetNotifications)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		mockService := new(notification.MockNotificationService)
+		handler := NewNotificationHandler(mockService)
+
+		mockService.On("List", mock.Anything, "user", false, 0, 0).
+			Return(nil, usecaseerr.ErrGettingNotifications)
+		c, w := newContext(http.MethodGet, "/notifications", "", "")
+
+		handlertest.Serve(c, handler.GetNotifications)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestNotificationHandler_MarkRead(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(notification.MockNotificationService)
+		handler := NewNotificationHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("MarkRead", mock.Anything, "user", 3).Return(nil)
+		c, w := newContext(http.MethodPost, "/notifications/3/read", "", "3")
+
+		handlertest.Serve(c, handler.MarkRead)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockService := new(notification.MockNotificationService)
+		handler := NewNotificationHandler(mockService)
+
+		mockService.On("MarkRead", mock.Anything, "user", 3).Return(usecaseerr.ErrNotificationNotFound)
+		c, w := newContext(http.MethodPost, "/notifications/3/read", "", "3")
+
+		handlertest.Serve(c, handler.MarkRead)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		mockService := new(notification.MockNotificationService)
+		handler := NewNotificationHandler(mockService)
+		c, w := newContext(http.MethodPost, "/notifications/abc/read", "", "abc")
+
+		handlertest.Serve(c, handler.MarkRead)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestNotificationHandler_MarkAllRead(t *testing.T) {
+	mockService := new(notification.MockNotificationService)
+	handler := NewNotificationHandler(mockService)
+	defer mockService.AssertExpectations(t)
+
+	mockService.On("MarkAllRead", mock.Anything, "user").Return(int64(4), nil)
+	c, w := newContext(http.MethodPost, "/notifications/read-all", "", "")
+
+	handlertest.Serve(c, handler.MarkAllRead)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"updated":4}`, w.Body.String())
+}
+
+func TestNotificationHandler_UpdatePreferences(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(notification.MockNotificationService)
+		handler := NewNotificationHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("UpdatePreferences", mock.Anything, &entities.NotificationPreferences{
+			UserID: "user", InApp: true, Email: true, EmailAddress: "user@example.com",
+		}).Return(nil)
+		c, w := newContext(http.MethodPut, "/notifications/preferences",
+			`{"in_app":true,"email":true,"email_address":"user@example.com"}`, "")
+
+		handlertest.Serve(c, handler.UpdatePreferences)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"email":true`)
+	})
+
+	t.Run("invalid preferences", func(t *testing.T) {
+		mockService := new(notification.MockNotificationService)
+		handler := NewNotificationHandler(mockService)
+
+		mockService.On("UpdatePreferences", mock.Anything, mock.Anything).Return(usecaseerr.ErrInvalidPreferences)
+		c, w := newContext(http.MethodPut, "/notifications/preferences", `{"email":true}`, "")
+
+		handlertest.Serve(c, handler.UpdatePreferences)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		mockService := new(notification.MockNotificationService)
+		handler := NewNotificationHandler(mockService)
+		c, w := newContext(http.MethodPut, "/notifications/preferences", `{`, "")
+
+		handlertest.Serve(c, handler.UpdatePreferences)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestNotificationHandler_GetPreferences(t *testing.T) {
+	mockService := new(notification.MockNotificationService)
+	handler := NewNotificationHandler(mockService)
+
+	mockService.On("GetPreferences", mock.Anything, "user").Return(nil, errors.New("db down"))
+	c, w := newContext(http.MethodGet, "/notifications/preferences", "", "")
+
+	handlertest.Serve(c, handler.GetPreferences)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
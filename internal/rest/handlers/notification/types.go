@@ -0,0 +1,75 @@
+package notification
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/usecase/notification"
+	"time"
+)
+
+type NotificationHandler struct {
+	notificationService notification.NotificationService
+}
+
+func NewNotificationHandler(notificationService notification.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+type NotificationResponse struct {
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	Kind      string     `json:"kind"`
+	Subject   string     `json:"subject"`
+	Body      string     `json:"body"`
+	ID        int        `json:"id"`
+	Read      bool       `json:"read"`
+}
+
+type NotificationListResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+	Unread        int                    `json:"unread"`
+}
+
+type PreferencesRequest struct {
+	EmailAddress string `json:"email_address"`
+	InApp        bool   `json:"in_app"`
+	SMS          bool   `json:"sms"`
+	Email        bool   `json:"email"`
+}
+
+type PreferencesResponse struct {
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+	EmailAddress string     `json:"email_address"`
+	InApp        bool       `json:"in_app"`
+	SMS          bool       `json:"sms"`
+	Email        bool       `json:"email"`
+}
+
+func newNotificationResponse(n *entities.Notification) NotificationResponse {
+	resp := NotificationResponse{
+		CreatedAt: n.CreatedAt,
+		Kind:      string(n.Kind),
+		Subject:   n.Subject,
+		Body:      n.Body,
+		ID:        n.ID,
+		Read:      !n.ReadAt.IsZero(),
+	}
+	if resp.Read {
+		resp.ReadAt = &n.ReadAt
+	}
+	return resp
+}
+
+func newPreferencesResponse(p *entities.NotificationPreferences) PreferencesResponse {
+	resp := PreferencesResponse{
+		EmailAddress: p.EmailAddress,
+		InApp:        p.InApp,
+		SMS:          p.SMS,
+		Email:        p.Email,
+	}
+	if !p.UpdatedAt.IsZero() {
+		resp.UpdatedAt = &p.UpdatedAt
+	}
+	return resp
+}
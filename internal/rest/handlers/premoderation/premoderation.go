@@ -0,0 +1,42 @@
+package premoderation
+
+import (
+	"ads-service/internal/errs"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetRuleHits godoc
+// @Summary      Pre-moderation rule hits
+// @Description  Rules that matched the ad on every submission, newest first, with the decision taken then:
+// @Description  approve, reject or review (left for a moderator).
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      int  true  "Ad ID"
+// @Success      200  {array}   RuleHitResponse
+// @Failure      400  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /admin/ads/{id}/rule-hits [get]
+func (h *PreModerationHandler) GetRuleHits(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		_ = c.Error(errs.Invalid("id", "invalid ad ID"))
+		return
+	}
+
+	hits, err := h.preModerationService.GetRuleHits(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to get rule hits: %w", err))
+		return
+	}
+
+	resp := make([]RuleHitResponse, 0, len(hits))
+	for i := range hits {
+		resp = append(resp, newRuleHitResponse(&hits[i]))
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,77 @@
+//nolint:all // testpackage
+package premoderation
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/rest/handlers/handlertest"
+	"ads-service/internal/usecase/premoderation"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newContext(id string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: id}}
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/ads/"+id+"/rule-hits", nil)
+	return c, w
+}
+
+func TestPreModerationHandler_GetRuleHits(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(premoderation.MockPreModerationService)
+		handler := NewPreModerationHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("GetRuleHits", mock.Anything, 7).Return([]entities.RuleHit{
+			{Rule: entities.RulePhone, Detail: "phone number in the text", Score: 40, Decision: entities.DecisionReview},
+		}, nil)
+		c, w := newContext("7")
+
+		handlertest.Serve(c, handler.GetRuleHits)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"rule":"phone"`)
+		assert.Contains(t, w.Body.String(), `"decision":"review"`)
+	})
+
+	t.Run("no hits", func(t *testing.T) {
+		mockService := new(premoderation.MockPreModerationService)
+		handler := NewPreModerationHandler(mockService)
+
+		mockService.On("GetRuleHits", mock.Anything, 7).Return(nil, nil)
+		c, w := newContext("7")
+
+		handlertest.Serve(c, handler.GetRuleHits)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "[]", w.Body.String())
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		handler := NewPreModerationHandler(new(premoderation.MockPreModerationService))
+		c, w := newContext("abc")
+
+		handlertest.Serve(c, handler.GetRuleHits)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		mockService := new(premoderation.MockPreModerationService)
+		handler := NewPreModerationHandler(mockService)
+
+		mockService.On("GetRuleHits", mock.Anything, 7).Return(nil, usecaseerr.ErrGettingRuleHits)
+		c, w := newContext("7")
+
+		handlertest.Serve(c, handler.GetRuleHits)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,35 @@
+package premoderation
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/usecase/premoderation"
+	"time"
+)
+
+type PreModerationHandler struct {
+	preModerationService premoderation.PreModerationService
+}
+
+func NewPreModerationHandler(preModerationService premoderation.PreModerationService) *PreModerationHandler {
+	return &PreModerationHandler{
+		preModerationService: preModerationService,
+	}
+}
+
+type RuleHitResponse struct {
+	CreatedAt time.Time `json:"created_at"`
+	Decision  string    `json:"decision"`
+	Rule      string    `json:"rule"`
+	Detail    string    `json:"detail"`
+	Score     int       `json:"score"`
+}
+
+func newRuleHitResponse(hit *entities.RuleHit) RuleHitResponse {
+	return RuleHitResponse{
+		CreatedAt: hit.CreatedAt,
+		Decision:  string(hit.Decision),
+		Rule:      hit.Rule,
+		Detail:    hit.Detail,
+		Score:     hit.Score,
+	}
+}
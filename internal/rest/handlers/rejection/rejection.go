@@ -0,0 +1,97 @@
+package rejection
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTemplates godoc
+// @Summary      Rejection templates
+// @Description  All rejection templates including deactivated ones, ordered by code.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {array}   TemplateResponse
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /admin/rejection-templates [get]
+func (h *RejectionHandler) GetTemplates(c *gin.Context) {
+	templates, err := h.rejectionService.GetTemplates(c.Request.Context())
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to get rejection templates: %w", err))
+		return
+	}
+	resp := make([]TemplateResponse, 0, len(templates))
+	for i := range templates {
+		resp = append(resp, newTemplateResponse(&templates[i]))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// CreateTemplate godoc
+// @Summary      Create a rejection template
+// @Description  Code is lowercase letters, digits and underscores. Texts map a two-letter language code to the text
+// @Description  shown to the author, "en" is required.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body      TemplateRequest  true  "Code, texts and active flag"
+// @Success      201  {object}  TemplateResponse
+// @Failure      400  {object}  middleware.Problem
+// @Failure      409  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /admin/rejection-templates [post]
+func (h *RejectionHandler) CreateTemplate(c *gin.Context) {
+	var req TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(fmt.Errorf("%w: %w", errs.ErrInvalidBody, err))
+		return
+	}
+
+	template := entities.RejectionTemplate{Code: req.Code, Texts: req.Texts, IsActive: true}
+	if req.IsActive != nil {
+		template.IsActive = *req.IsActive
+	}
+	if err := h.rejectionService.CreateTemplate(c.Request.Context(), &template); err != nil {
+		_ = c.Error(fmt.Errorf("failed to create rejection template: %w", err))
+		return
+	}
+	c.JSON(http.StatusCreated, newTemplateResponse(&template))
+}
+
+// UpdateTemplate godoc
+// @Summary      Update a rejection template
+// @Description  Replaces the texts and the active flag. Deactivated templates cannot be used for new rejections,
+// @Description  ads rejected with them keep their reason. Templates used by the service cannot be deactivated.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        code     path      string           true  "Template code"
+// @Param        request  body      TemplateRequest  true  "Texts and active flag, code is ignored"
+// @Success      200  {object}  TemplateResponse
+// @Failure      400  {object}  middleware.Problem
+// @Failure      404  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /admin/rejection-templates/{code} [put]
+func (h *RejectionHandler) UpdateTemplate(c *gin.Context) {
+	var req TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(fmt.Errorf("%w: %w", errs.ErrInvalidBody, err))
+		return
+	}
+
+	template := entities.RejectionTemplate{Code: c.Param("code"), Texts: req.Texts, IsActive: true}
+	if req.IsActive != nil {
+		template.IsActive = *req.IsActive
+	}
+	if err := h.rejectionService.UpdateTemplate(c.Request.Context(), &template); err != nil {
+		_ = c.Error(fmt.Errorf("failed to update rejection template: %w", err))
+		return
+	}
+	c.JSON(http.StatusOK, newTemplateResponse(&template))
+}
@@ -0,0 +1,143 @@
+//nolint:all // testpackage
+package rejection
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/rest/handlers/handlertest"
+	"ads-service/internal/usecase/rejection"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newContext(method, target, body, code string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	if code != "" {
+		c.Params = gin.Params{{Key: "code", Value: code}}
+	}
+	c.Request = httptest.NewRequest(method, target, strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	return c, w
+}
+
+func TestRejectionHandler_GetTemplates(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(rejection.MockRejectionService)
+		handler := NewRejectionHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("GetTemplates", mock.Anything).Return([]entities.RejectionTemplate{
+			{Code: "poor_photos", Texts: map[string]string{"en": "Bad photos."}, IsActive: true},
+		}, nil)
+
+		c, w := newContext(http.MethodGet, "/admin/rejection-templates", "", "")
+		handlertest.Serve(c, handler.GetTemplates)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"poor_photos"`)
+		assert.Contains(t, w.Body.String(), `"texts":{"en":"Bad photos."}`)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		mockService := new(rejection.MockRejectionService)
+		handler := NewRejectionHandler(mockService)
+
+		mockService.On("GetTemplates", mock.Anything).Return(nil, usecaseerr.ErrGettingRejectionTemplates)
+
+		c, w := newContext(http.MethodGet, "/admin/rejection-templates", "", "")
+		handlertest.Serve(c, handler.GetTemplates)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestRejectionHandler_CreateTemplate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(rejection.MockRejectionService)
+		handler := NewRejectionHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("CreateTemplate", mock.Anything, &entities.RejectionTemplate{
+			Code:     "no_price",
+			Texts:    map[string]string{"en": "The price is missing."},
+			IsActive: true,
+		}).Return(nil)
+
+		c, w := newContext(http.MethodPost, "/admin/rejection-templates",
+			`{"code":"no_price","texts":{"en":"The price is missing."}}`, "")
+		handlertest.Serve(c, handler.CreateTemplate)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"is_active":true`)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		handler := NewRejectionHandler(new(rejection.MockRejectionService))
+
+		c, w := newContext(http.MethodPost, "/admin/rejection-templates", `{"code":"no_price"}`, "")
+		handlertest.Serve(c, handler.CreateTemplate)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("already exists", func(t *testing.T) {
+		mockService := new(rejection.MockRejectionService)
+		handler := NewRejectionHandler(mockService)
+
+		mockService.On("CreateTemplate", mock.Anything, mock.Anything).Return(usecaseerr.ErrRejectionTemplateExists)
+
+		c, w := newContext(http.MethodPost, "/admin/rejection-templates",
+			`{"code":"duplicate","texts":{"en":"Duplicate."}}`, "")
+		handlertest.Serve(c, handler.CreateTemplate)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestRejectionHandler_UpdateTemplate(t *testing.T) {
+	t.Run("deactivate", func(t *testing.T) {
+		mockService := new(rejection.MockRejectionService)
+		handler := NewRejectionHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("UpdateTemplate", mock.Anything, &entities.RejectionTemplate{
+			Code:  "poor_photos",
+			Texts: map[string]string{"en": "Bad photos."},
+		}).Return(nil)
+
+		c, w := newContext(http.MethodPut, "/admin/rejection-templates/poor_photos",
+			`{"texts":{"en":"Bad photos."},"is_active":false}`, "poor_photos")
+		handlertest.Serve(c, handler.UpdateTemplate)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"is_active":false`)
+	})
+
+	for name, tc := range map[string]struct {
+		err  error
+		code int
+	}{
+		"not found":       {usecaseerr.ErrRejectionTemplateNotFound, http.StatusNotFound},
+		"system template": {usecaseerr.ErrSystemRejectionTemplate, http.StatusBadRequest},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockService := new(rejection.MockRejectionService)
+			handler := NewRejectionHandler(mockService)
+
+			mockService.On("UpdateTemplate", mock.Anything, mock.Anything).Return(tc.err)
+
+			c, w := newContext(http.MethodPut, "/admin/rejection-templates/x",
+				`{"texts":{"en":"Text."},"is_active":false}`, "x")
+			handlertest.Serve(c, handler.UpdateTemplate)
+
+			assert.Equal(t, tc.code, w.Code)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package rejection
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/usecase/rejection"
+	"time"
+)
+
+type RejectionHandler struct {
+	rejectionService rejection.RejectionService
+}
+
+func NewRejectionHandler(rejectionService rejection.RejectionService) *RejectionHandler {
+	return &RejectionHandler{
+		rejectionService: rejectionService,
+	}
+}
+
+// TemplateRequest - texts by language code, "en" is required. Without is_active the template is active.
+type TemplateRequest struct {
+	Texts    map[string]string `json:"texts" binding:"required"`
+	IsActive *bool             `json:"is_active"`
+	Code     string            `json:"code"`
+}
+
+type TemplateResponse struct {
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Texts     map[string]string `json:"texts"`
+	Code      string            `json:"code"`
+	IsActive  bool              `json:"is_active"`
+}
+
+func newTemplateResponse(t *entities.RejectionTemplate) TemplateResponse {
+	return TemplateResponse{
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+		Texts:     t.Texts,
+		Code:      t.Code,
+		IsActive:  t.IsActive,
+	}
+}
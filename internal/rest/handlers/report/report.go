@@ -0,0 +1,112 @@
+package report
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReportAd godoc
+// @Summary      Report an ad
+// @Description  Flags a published ad of another user. After 3 distinct reports the ad is hidden until a moderator
+// @Description  reviews it. Reason is one of scam, prohibited, offensive, duplicate, wrong_category, other;
+// @Description  "other" needs a comment.
+// @Tags         reports
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int            true  "Ad ID"
+// @Param        request  body      ReportRequest  true  "Reason and optional comment"
+// @Success      201  {object}  ReportResponse
+// @Failure      400  {object}  middleware.Problem
+// @Failure      404  {object}  middleware.Problem
+// @Failure      409  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /ads/{id}/report [post]
+func (h *ReportHandler) ReportAd(c *gin.Context) {
+	adID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || adID <= 0 {
+		_ = c.Error(errs.Invalid("id", "invalid ad ID"))
+		return
+	}
+	var req ReportRequest
+	if err = c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(fmt.Errorf("%w: %w", errs.ErrInvalidBody, err))
+		return
+	}
+
+	report, err := h.reportService.Report(c.Request.Context(), c.GetString("user_id"), adID,
+		entities.ReportReason(req.Reason), req.Comment)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to report ad: %w", err))
+		return
+	}
+	c.JSON(http.StatusCreated, newReportResponse(report))
+}
+
+// GetReports godoc
+// @Summary      Reports queue
+// @Description  Reports with the given status, open by default. Open reports are grouped by ad, most reported first.
+// @Tags         admin
+// @Produce      json
+// @Param        status  query     string  false  "open, dismissed, ad_removed or user_banned"
+// @Success      200  {array}   ReportResponse
+// @Failure      400  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /admin/reports [get]
+func (h *ReportHandler) GetReports(c *gin.Context) {
+	reports, err := h.reportService.List(c.Request.Context(), entities.ReportStatus(c.Query("status")))
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to get reports: %w", err))
+		return
+	}
+
+	resp := make([]ReportResponse, 0, len(reports))
+	for i := range reports {
+		resp = append(resp, newReportResponse(&reports[i]))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ResolveReport godoc
+// @Summary      Resolve a report
+// @Description  Closes all open reports on the ad. ad_removed deletes the ad, user_banned rejects it and bans the
+// @Description  author: the author's sessions and API keys are revoked and all of their ads are hidden.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int             true  "Report ID"
+// @Param        request  body      ResolveRequest  true  "dismissed, ad_removed or user_banned and an optional note"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  middleware.Problem
+// @Failure      403  {object}  middleware.Problem
+// @Failure      404  {object}  middleware.Problem
+// @Failure      409  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /admin/reports/{id}/resolve [post]
+func (h *ReportHandler) ResolveReport(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		_ = c.Error(errs.Invalid("id", "invalid report ID"))
+		return
+	}
+	var req ResolveRequest
+	if err = c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(fmt.Errorf("%w: %w", errs.ErrInvalidBody, err))
+		return
+	}
+
+	err = h.reportService.Resolve(c.Request.Context(), c.GetString("user_id"), id,
+		entities.ReportStatus(req.Resolution), req.Note)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to resolve report: %w", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "report resolved"})
+}
@@ -0,0 +1,164 @@
+//nolint:all // testpackage
+package report
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/rest/handlers/handlertest"
+	"ads-service/internal/usecase/report"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newContext(method, target, body, id string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set("user_id", "user")
+	if id != "" {
+		c.Params = gin.Params{{Key: "id", Value: id}}
+	}
+	c.Request = httptest.NewRequest(method, target, strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	return c, w
+}
+
+func TestReportHandler_ReportAd(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(report.MockReportService)
+		handler := NewReportHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("Report", mock.Anything, "user", 7, entities.ReasonScam, "fake").
+			Return(&entities.Report{ID: 1, AdID: 7, Reason: entities.ReasonScam, Status: entities.ReportOpen}, nil)
+		c, w := newContext(http.MethodPost, "/ads/7/report", `{"reason":"scam","comment":"fake"}`, "7")
+
+		handlertest.Serve(c, handler.ReportAd)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"open"`)
+		assert.NotContains(t, w.Body.String(), "resolved_at")
+	})
+
+	t.Run("invalid ad ID", func(t *testing.T) {
+		handler := NewReportHandler(new(report.MockReportService))
+		c, w := newContext(http.MethodPost, "/ads/x/report", `{"reason":"scam"}`, "x")
+
+		handlertest.Serve(c, handler.ReportAd)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("missing reason", func(t *testing.T) {
+		handler := NewReportHandler(new(report.MockReportService))
+		c, w := newContext(http.MethodPost, "/ads/7/report", `{}`, "7")
+
+		handlertest.Serve(c, handler.ReportAd)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	for name, tc := range map[string]struct {
+		err  error
+		code int
+	}{
+		"own ad":           {err: usecaseerr.ErrReportOwnAd, code: http.StatusBadRequest},
+		"ad not available": {err: usecaseerr.ErrAdNotAvailable, code: http.StatusNotFound},
+		"already reported": {err: usecaseerr.ErrAlreadyReported, code: http.StatusConflict},
+		"service error":    {err: usecaseerr.ErrCreatingReport, code: http.StatusInternalServerError},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockService := new(report.MockReportService)
+			handler := NewReportHandler(mockService)
+
+			mockService.On("Report", mock.Anything, "user", 7, entities.ReasonScam, "").Return(nil, tc.err)
+			c, w := newContext(http.MethodPost, "/ads/7/report", `{"reason":"scam"}`, "7")
+
+			handlertest.Serve(c, handler.ReportAd)
+
+			assert.Equal(t, tc.code, w.Code)
+		})
+	}
+}
+
+func TestReportHandler_GetReports(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(report.MockReportService)
+		handler := NewReportHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("List", mock.Anything, entities.ReportStatus("")).
+			Return([]entities.Report{{ID: 1, AdID: 7, OpenReports: 2}}, nil)
+		c, w := newContext(http.MethodGet, "/admin/reports", "", "")
+
+		handlertest.Serve(c, handler.GetReports)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"open_reports":2`)
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		mockService := new(report.MockReportService)
+		handler := NewReportHandler(mockService)
+
+		mockService.On("List", mock.Anything, entities.ReportStatus("closed")).
+			Return(nil, usecaseerr.ErrInvalidParams)
+		c, w := newContext(http.MethodGet, "/admin/reports?status=closed", "", "")
+
+		handlertest.Serve(c, handler.GetReports)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestReportHandler_ResolveReport(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(report.MockReportService)
+		handler := NewReportHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("Resolve", mock.Anything, "user", 3, entities.ReportUserBanned, "scammer").Return(nil)
+		c, w := newContext(http.MethodPost, "/admin/reports/3/resolve",
+			`{"resolution":"user_banned","note":"scammer"}`, "3")
+
+		handlertest.Serve(c, handler.ResolveReport)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		handler := NewReportHandler(new(report.MockReportService))
+		c, w := newContext(http.MethodPost, "/admin/reports/3/resolve", `{"note":"x"}`, "3")
+
+		handlertest.Serve(c, handler.ResolveReport)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	for name, tc := range map[string]struct {
+		err  error
+		code int
+	}{
+		"invalid resolution": {err: usecaseerr.ErrInvalidResolution, code: http.StatusBadRequest},
+		"admin author":       {err: usecaseerr.ErrCannotBanAdmin, code: http.StatusForbidden},
+		"not found":          {err: usecaseerr.ErrReportNotFound, code: http.StatusNotFound},
+		"already resolved":   {err: usecaseerr.ErrReportResolved, code: http.StatusConflict},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockService := new(report.MockReportService)
+			handler := NewReportHandler(mockService)
+
+			mockService.On("Resolve", mock.Anything, "user", 3, entities.ReportDismissed, "").Return(tc.err)
+			c, w := newContext(http.MethodPost, "/admin/reports/3/resolve", `{"resolution":"dismissed"}`, "3")
+
+			handlertest.Serve(c, handler.ResolveReport)
+
+			assert.Equal(t, tc.code, w.Code)
+		})
+	}
+}
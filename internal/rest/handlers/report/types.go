@@ -0,0 +1,62 @@
+package report
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/usecase/report"
+	"time"
+)
+
+type ReportHandler struct {
+	reportService report.ReportService
+}
+
+func NewReportHandler(reportService report.ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+	}
+}
+
+type ReportRequest struct {
+	Reason  string `json:"reason" binding:"required"`
+	Comment string `json:"comment"`
+}
+
+type ResolveRequest struct {
+	Resolution string `json:"resolution" binding:"required"`
+	Note       string `json:"note"`
+}
+
+type ReportResponse struct {
+	CreatedAt   time.Time  `json:"created_at"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+	Reason      string     `json:"reason"`
+	Status      string     `json:"status"`
+	ReporterID  string     `json:"reporter_id"`
+	ResolvedBy  string     `json:"resolved_by,omitempty"`
+	Comment     string     `json:"comment,omitempty"`
+	AdTitle     string     `json:"ad_title,omitempty"`
+	AdAuthorID  string     `json:"ad_author_id,omitempty"`
+	ID          int        `json:"id"`
+	AdID        int        `json:"ad_id"`
+	OpenReports int        `json:"open_reports"`
+}
+
+func newReportResponse(r *entities.Report) ReportResponse {
+	resp := ReportResponse{
+		CreatedAt:   r.CreatedAt,
+		Reason:      string(r.Reason),
+		Status:      string(r.Status),
+		ReporterID:  r.ReporterID,
+		ResolvedBy:  r.ResolvedBy,
+		Comment:     r.Comment,
+		AdTitle:     r.AdTitle,
+		AdAuthorID:  r.AdAuthorID,
+		ID:          r.ID,
+		AdID:        r.AdID,
+		OpenReports: r.OpenReports,
+	}
+	if !r.ResolvedAt.IsZero() {
+		resp.ResolvedAt = &r.ResolvedAt
+	}
+	return resp
+}
@@ -0,0 +1,97 @@
+package savedsearch
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateSavedSearch godoc
+// @Summary      Save a search
+// @Description  Stores an ad filter. When a newly approved ad matches it the user gets an instant alert
+// @Description  or the ad is included in the daily digest, depending on mode
+// @Tags         saved-searches
+// @Accept       json
+// @Produce      json
+// @Param        request  body      SavedSearchRequest  true  "Name, criteria and notification mode"
+// @Success      201  {object}  SavedSearchResponse
+// @Failure      400  {object}  middleware.Problem
+// @Failure      409  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /saved-searches [post]
+func (h *SavedSearchHandler) CreateSavedSearch(c *gin.Context) {
+	var req SavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(fmt.Errorf("%w: %w", errs.ErrInvalidBody, err))
+		return
+	}
+
+	search := entities.SavedSearch{
+		Name:       req.Name,
+		Text:       req.Text,
+		Location:   req.Location,
+		Mode:       req.Mode,
+		CategoryID: req.CategoryID,
+		PriceMin:   req.PriceMin,
+		PriceMax:   req.PriceMax,
+	}
+	err := h.savedSearchService.Create(c.Request.Context(), c.GetString("user_id"), &search)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to save search: %w", err))
+		return
+	}
+	c.JSON(http.StatusCreated, newSavedSearchResponse(&search))
+}
+
+// GetSavedSearches godoc
+// @Summary      List saved searches
+// @Tags         saved-searches
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /saved-searches [get]
+func (h *SavedSearchHandler) GetSavedSearches(c *gin.Context) {
+	searches, err := h.savedSearchService.List(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to get saved searches: %w", err))
+		return
+	}
+
+	resp := make([]SavedSearchResponse, 0, len(searches))
+	for i := range searches {
+		resp = append(resp, newSavedSearchResponse(&searches[i]))
+	}
+	c.JSON(http.StatusOK, gin.H{"saved_searches": resp})
+}
+
+// DeleteSavedSearch godoc
+// @Summary      Delete a saved search
+// @Tags         saved-searches
+// @Produce      json
+// @Param        id   path      int  true  "Saved search ID"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  middleware.Problem
+// @Failure      404  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /saved-searches/{id} [delete]
+func (h *SavedSearchHandler) DeleteSavedSearch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		_ = c.Error(errs.Invalid("id", "invalid saved search ID"))
+		return
+	}
+
+	err = h.savedSearchService.Delete(c.Request.Context(), c.GetString("user_id"), id)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to delete saved search: %w", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "saved search deleted"})
+}
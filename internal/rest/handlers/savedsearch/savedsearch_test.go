@@ -0,0 +1,120 @@
+//nolint:all // testpackage
+package savedsearch
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/rest/handlers/handlertest"
+	"ads-service/internal/usecase/savedsearch"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newContext(method, body string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set("user_id", "buyer")
+	c.Request = httptest.NewRequest(method, "/saved-searches", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	return c, w
+}
+
+func TestSavedSearchHandler_CreateSavedSearch(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(savedsearch.MockSavedSearchService)
+		handler := NewSavedSearchHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("Create", mock.Anything, "buyer", mock.MatchedBy(func(s *entities.SavedSearch) bool {
+			return s.Name == "Cars" && s.PriceMax == 10000 && s.Mode == entities.NotifyDaily
+		})).Run(func(args mock.Arguments) {
+			args.Get(2).(*entities.SavedSearch).ID = 4
+		}).Return(nil)
+		c, w := newContext(http.MethodPost, `{"name":"Cars","price_max":10000,"mode":"daily"}`)
+
+		handlertest.Serve(c, handler.CreateSavedSearch)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"id":4`)
+	})
+
+	t.Run("missing name", func(t *testing.T) {
+		handler := NewSavedSearchHandler(new(savedsearch.MockSavedSearchService))
+		c, w := newContext(http.MethodPost, `{"text":"bmw"}`)
+
+		handlertest.Serve(c, handler.CreateSavedSearch)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid criteria", func(t *testing.T) {
+		mockService := new(savedsearch.MockSavedSearchService)
+		handler := NewSavedSearchHandler(mockService)
+
+		mockService.On("Create", mock.Anything, "buyer", mock.Anything).Return(usecaseerr.ErrInvalidSavedSearch)
+		c, w := newContext(http.MethodPost, `{"name":"Everything"}`)
+
+		handlertest.Serve(c, handler.CreateSavedSearch)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("too many", func(t *testing.T) {
+		mockService := new(savedsearch.MockSavedSearchService)
+		handler := NewSavedSearchHandler(mockService)
+
+		mockService.On("Create", mock.Anything, "buyer", mock.Anything).Return(usecaseerr.ErrTooManySavedSearches)
+		c, w := newContext(http.MethodPost, `{"name":"Cars","category_id":1}`)
+
+		handlertest.Serve(c, handler.CreateSavedSearch)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestSavedSearchHandler_GetSavedSearches(t *testing.T) {
+	mockService := new(savedsearch.MockSavedSearchService)
+	handler := NewSavedSearchHandler(mockService)
+	defer mockService.AssertExpectations(t)
+
+	mockService.On("List", mock.Anything, "buyer").
+		Return([]entities.SavedSearch{{ID: 1, Name: "Cars", Mode: entities.NotifyInstant}}, nil)
+	c, w := newContext(http.MethodGet, "")
+
+	handlertest.Serve(c, handler.GetSavedSearches)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"mode":"instant"`)
+}
+
+func TestSavedSearchHandler_DeleteSavedSearch(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		mockService := new(savedsearch.MockSavedSearchService)
+		handler := NewSavedSearchHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("Delete", mock.Anything, "buyer", 9).Return(usecaseerr.ErrSavedSearchNotFound)
+		c, w := newContext(http.MethodDelete, "")
+		c.Params = gin.Params{{Key: "id", Value: "9"}}
+
+		handlertest.Serve(c, handler.DeleteSavedSearch)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		handler := NewSavedSearchHandler(new(savedsearch.MockSavedSearchService))
+		c, w := newContext(http.MethodDelete, "")
+		c.Params = gin.Params{{Key: "id", Value: "x"}}
+
+		handlertest.Serve(c, handler.DeleteSavedSearch)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
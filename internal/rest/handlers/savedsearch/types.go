@@ -0,0 +1,54 @@
+package savedsearch
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/usecase/savedsearch"
+	"time"
+)
+
+type SavedSearchHandler struct {
+	savedSearchService savedsearch.SavedSearchService
+}
+
+func NewSavedSearchHandler(savedSearchService savedsearch.SavedSearchService) *SavedSearchHandler {
+	return &SavedSearchHandler{
+		savedSearchService: savedSearchService,
+	}
+}
+
+// SavedSearchRequest - filter criteria, omitted fields mean "any".
+type SavedSearchRequest struct {
+	Name       string              `json:"name" binding:"required"`
+	Text       string              `json:"text"`
+	Location   string              `json:"location"`
+	Mode       entities.NotifyMode `json:"mode"` // instant (default) or daily
+	CategoryID int                 `json:"category_id"`
+	PriceMin   int                 `json:"price_min"`
+	PriceMax   int                 `json:"price_max"`
+}
+
+type SavedSearchResponse struct {
+	CreatedAt  time.Time           `json:"created_at"`
+	Name       string              `json:"name"`
+	Text       string              `json:"text,omitempty"`
+	Location   string              `json:"location,omitempty"`
+	Mode       entities.NotifyMode `json:"mode"`
+	CategoryID int                 `json:"category_id,omitempty"`
+	PriceMin   int                 `json:"price_min,omitempty"`
+	PriceMax   int                 `json:"price_max,omitempty"`
+	ID         int                 `json:"id"`
+}
+
+func newSavedSearchResponse(s *entities.SavedSearch) SavedSearchResponse {
+	return SavedSearchResponse{
+		CreatedAt:  s.CreatedAt,
+		Name:       s.Name,
+		Text:       s.Text,
+		Location:   s.Location,
+		Mode:       s.Mode,
+		CategoryID: s.CategoryID,
+		PriceMin:   s.PriceMin,
+		PriceMax:   s.PriceMax,
+		ID:         s.ID,
+	}
+}
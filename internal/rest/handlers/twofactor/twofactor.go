@@ -0,0 +1,133 @@
+package twofactor
+
+import (
+	"ads-service/internal/errs"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Enroll godoc
+// @Summary      Start two-factor enrollment
+// @Description  Generates a TOTP secret and an otpauth URI for an authenticator app. 2FA is enabled after confirm
+// @Tags         2fa
+// @Produce      json
+// @Success      200  {object}  EnrollResponse
+// @Failure      401  {object}  middleware.Problem
+// @Failure      409  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /2fa/enroll [post]
+func (h *TwoFactorHandler) Enroll(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		_ = c.Error(errs.ErrUnauthorized)
+		return
+	}
+
+	enrollment, err := h.twoFactorService.Enroll(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to enroll: %w", err))
+		return
+	}
+	c.JSON(http.StatusOK, EnrollResponse{Secret: enrollment.Secret, URI: enrollment.URI})
+}
+
+// Confirm godoc
+// @Summary      Confirm two-factor enrollment
+// @Description  Enables 2FA with the first code from the app. Recovery codes are returned only once
+// @Tags         2fa
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CodeRequest  true  "TOTP code"
+// @Success      200  {object}  RecoveryCodesResponse
+// @Failure      400  {object}  middleware.Problem
+// @Failure      401  {object}  middleware.Problem
+// @Failure      409  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /2fa/confirm [post]
+func (h *TwoFactorHandler) Confirm(c *gin.Context) {
+	userID, code, ok := bindCode(c)
+	if !ok {
+		return
+	}
+
+	codes, err := h.twoFactorService.Confirm(c.Request.Context(), userID, code)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to confirm: %w", err))
+		return
+	}
+	c.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// Disable godoc
+// @Summary      Disable two-factor authentication
+// @Description  Requires a current TOTP code or a recovery code
+// @Tags         2fa
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CodeRequest  true  "TOTP or recovery code"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  middleware.Problem
+// @Failure      401  {object}  middleware.Problem
+// @Failure      429  {object}  middleware.Problem  "too many failed attempts"
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /2fa/disable [post]
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	userID, code, ok := bindCode(c)
+	if !ok {
+		return
+	}
+
+	if err := h.twoFactorService.Disable(c.Request.Context(), userID, code, c.ClientIP()); err != nil {
+		_ = c.Error(fmt.Errorf("failed to disable: %w", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary      Regenerate recovery codes
+// @Description  Replaces all recovery codes, the old ones stop working
+// @Tags         2fa
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CodeRequest  true  "TOTP or recovery code"
+// @Success      200  {object}  RecoveryCodesResponse
+// @Failure      400  {object}  middleware.Problem
+// @Failure      401  {object}  middleware.Problem
+// @Failure      429  {object}  middleware.Problem  "too many failed attempts"
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /2fa/recovery-codes [post]
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, code, ok := bindCode(c)
+	if !ok {
+		return
+	}
+
+	codes, err := h.twoFactorService.RegenerateRecoveryCodes(c.Request.Context(), userID, code, c.ClientIP())
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to regenerate recovery codes: %w", err))
+		return
+	}
+	c.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func bindCode(c *gin.Context) (userID, code string, ok bool) {
+	userID = c.GetString("user_id")
+	if userID == "" {
+		_ = c.Error(errs.ErrUnauthorized)
+		return "", "", false
+	}
+
+	var req CodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(fmt.Errorf("%w: %w", errs.ErrInvalidBody, err))
+		return "", "", false
+	}
+	return userID, req.Code, true
+}
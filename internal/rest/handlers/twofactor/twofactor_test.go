@@ -0,0 +1,204 @@
+//nolint:all // testpackage
+package twofactor
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/rest/handlers/handlertest"
+	"ads-service/internal/usecase/twofactor"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTwoFactorHandler_Enroll(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(twofactor.MockTwoFactorService)
+		handler := NewTwoFactorHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("Enroll", mock.Anything, "123").
+			Return(&entities.TOTPEnrollment{Secret: "SECRET", URI: "otpauth://totp/GoAds:123?secret=SECRET"}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "123")
+		c.Request = httptest.NewRequest(http.MethodPost, "/2fa/enroll", nil)
+
+		handlertest.Serve(c, handler.Enroll)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"secret":"SECRET","otpauth_uri":"otpauth://totp/GoAds:123?secret=SECRET"}`, w.Body.String())
+	})
+
+	t.Run("already enabled", func(t *testing.T) {
+		mockService := new(twofactor.MockTwoFactorService)
+		handler := NewTwoFactorHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("Enroll", mock.Anything, "123").Return(nil, usecaseerr.ErrTwoFactorAlreadyEnabled)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "123")
+		c.Request = httptest.NewRequest(http.MethodPost, "/2fa/enroll", nil)
+
+		handlertest.Serve(c, handler.Enroll)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		handler := NewTwoFactorHandler(new(twofactor.MockTwoFactorService))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/2fa/enroll", nil)
+
+		handlertest.Serve(c, handler.Enroll)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestTwoFactorHandler_Confirm(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(twofactor.MockTwoFactorService)
+		handler := NewTwoFactorHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("Confirm", mock.Anything, "123", "123456").Return([]string{"abcde-fghjk"}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "123")
+		req := httptest.NewRequest(http.MethodPost, "/2fa/confirm", strings.NewReader(`{"code":"123456"}`))
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+
+		handlertest.Serve(c, handler.Confirm)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"recovery_codes":["abcde-fghjk"]}`, w.Body.String())
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		mockService := new(twofactor.MockTwoFactorService)
+		handler := NewTwoFactorHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("Confirm", mock.Anything, "123", "000000").Return(nil, usecaseerr.ErrInvalidTwoFactorCode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "123")
+		req := httptest.NewRequest(http.MethodPost, "/2fa/confirm", strings.NewReader(`{"code":"000000"}`))
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+
+		handlertest.Serve(c, handler.Confirm)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), "failed to confirm")
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		handler := NewTwoFactorHandler(new(twofactor.MockTwoFactorService))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "123")
+		req := httptest.NewRequest(http.MethodPost, "/2fa/confirm", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+
+		handlertest.Serve(c, handler.Confirm)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestTwoFactorHandler_Disable(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockService := new(twofactor.MockTwoFactorService)
+		handler := NewTwoFactorHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("Disable", mock.Anything, "123", "123456", "192.0.2.1").Return(nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "123")
+		req := httptest.NewRequest(http.MethodPost, "/2fa/disable", strings.NewReader(`{"code":"123456"}`))
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+
+		handlertest.Serve(c, handler.Disable)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("not enabled", func(t *testing.T) {
+		mockService := new(twofactor.MockTwoFactorService)
+		handler := NewTwoFactorHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("Disable", mock.Anything, "123", "123456", "192.0.2.1").Return(usecaseerr.ErrTwoFactorNotEnabled)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "123")
+		req := httptest.NewRequest(http.MethodPost, "/2fa/disable", strings.NewReader(`{"code":"123456"}`))
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+
+		handlertest.Serve(c, handler.Disable)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("locked out", func(t *testing.T) {
+		mockService := new(twofactor.MockTwoFactorService)
+		handler := NewTwoFactorHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("Disable", mock.Anything, "123", "123456", "192.0.2.1").Return(usecaseerr.ErrTooManyAttempts)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "123")
+		req := httptest.NewRequest(http.MethodPost, "/2fa/disable", strings.NewReader(`{"code":"123456"}`))
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+
+		handlertest.Serve(c, handler.Disable)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	})
+}
+
+func TestTwoFactorHandler_RegenerateRecoveryCodes(t *testing.T) {
+	mockService := new(twofactor.MockTwoFactorService)
+	handler := NewTwoFactorHandler(mockService)
+	defer mockService.AssertExpectations(t)
+
+	mockService.On("RegenerateRecoveryCodes", mock.Anything, "123", "123456", "192.0.2.1").
+		Return(nil, assert.AnError)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set("user_id", "123")
+	req := httptest.NewRequest(http.MethodPost, "/2fa/recovery-codes", strings.NewReader(`{"code":"123456"}`))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+
+	handlertest.Serve(c, handler.RegenerateRecoveryCodes)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"internal"`)
+}
@@ -0,0 +1,26 @@
+package twofactor
+
+import "ads-service/internal/usecase/twofactor"
+
+type TwoFactorHandler struct {
+	twoFactorService twofactor.TwoFactorService
+}
+
+func NewTwoFactorHandler(twoFactorService twofactor.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorService: twoFactorService,
+	}
+}
+
+type CodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type EnrollResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -15,5 +15,7 @@ func NewUserHandler(userService user.UserAdvertisementService) *UserHandler {
 type UpdateAdRequest struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
+	Location    string `json:"location"`
 	CategoryID  int    `json:"category_id"`
+	Price       int    `json:"price"`
 }
@@ -2,7 +2,10 @@ package user
 
 import (
 	"ads-service/internal/domain/entities"
-	"log"
+	"ads-service/internal/errs"
+	"ads-service/internal/rest/middleware"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
 
@@ -11,31 +14,33 @@ import (
 
 // CreateDraft godoc
 // @Summary      Create a new ad draft
-// @Description  Allows a user to create an ad draft
+// @Description  Allows a user to create an ad draft. A retry with the same Idempotency-Key gets the first response
 // @Tags         user-ads
 // @Accept       json
 // @Produce      json
-// @Param        ad  body      entities.Ad  true  "Ad draft"
+// @Param        Idempotency-Key  header    string       false  "Key of the request, unique per user"
+// @Param        ad               body      entities.Ad  true   "Ad draft"
 // @Success      201  {object}  map[string]string
-// @Failure      400  {object}  map[string]string
-// @Failure      500  {object}  map[string]string
+// @Failure      400  {object}  middleware.Problem
+// @Failure      409  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
 // @Security BearerAuth
 // @Router       /ads [post]
 func (h *UserHandler) CreateDraft(c *gin.Context) {
 	var ad entities.Ad
 	if err := c.ShouldBindJSON(&ad); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body: " + err.Error()})
+		_ = c.Error(fmt.Errorf("%w: %w", errs.ErrInvalidBody, err))
 		return
 	}
 
 	userID := c.GetString("user_id")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		_ = c.Error(errs.ErrUnauthorized)
 		return
 	}
 
 	if err := h.userService.CreateDraft(c.Request.Context(), userID, &ad); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create ad: " + err.Error()})
+		_ = c.Error(fmt.Errorf("failed to create ad: %w", err))
 		return
 	}
 
@@ -47,30 +52,64 @@ func (h *UserHandler) CreateDraft(c *gin.Context) {
 // @Tags         user-ads
 // @Produce      json
 // @Success      200  {object}  map[string]interface{}
-// @Failure      500  {object}  map[string]string
+// @Failure      500  {object}  middleware.Problem
 // @Security BearerAuth
 // @Router       /ads [get]
 func (h *UserHandler) GetMyAds(c *gin.Context) {
 	userID := c.GetString("user_id")
 	ads, err := h.userService.GetMyAds(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user ads: " + err.Error()})
+		_ = c.Error(fmt.Errorf("failed to get user ads: %w", err))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"ads": ads})
 }
 
+// GetMyAd godoc
+// @Summary      Get a user's own ad
+// @Description  The ETag names the version of the ad, writes send it back in If-Match
+// @Tags         user-ads
+// @Produce      json
+// @Param        id   path      int  true  "Ad ID"
+// @Success      200  {object}  entities.Ad
+// @Header       200  {string}  ETag  "version of the ad"
+// @Failure      400  {object}  middleware.Problem
+// @Failure      403  {object}  middleware.Problem
+// @Failure      404  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
+// @Security BearerAuth
+// @Router       /ads/{id} [get]
+func (h *UserHandler) GetMyAd(c *gin.Context) {
+	adID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || adID <= 0 {
+		_ = c.Error(errs.Invalid("id", "invalid ad ID"))
+		return
+	}
+
+	ad, err := h.userService.GetMyAd(c.Request.Context(), c.GetString("user_id"), adID)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("failed to get ad: %w", err))
+		return
+	}
+	c.Header("ETag", middleware.ETag(ad.Version))
+	c.JSON(http.StatusOK, ad)
+}
+
 // UpdateMyAd godoc
 // @Summary      Update a user's own ad
+// @Description  If-Match must carry the ETag of the ad, a stale one gets 412 with the current ad and its ETag
 // @Tags         user-ads
 // @Accept       json
 // @Produce      json
-// @Param        id   path      int          true  "Ad ID"
-// @Param        ad   body      entities.Ad  true  "Updated ad data"
+// @Param        id        path      int          true  "Ad ID"
+// @Param        If-Match  header    string       true  "ETag of the ad"
+// @Param        ad        body      entities.Ad  true  "Updated ad data"
 // @Success      200  {object}  map[string]string
-// @Failure      400  {object}  map[string]string
-// @Failure      500  {object}  map[string]string
+// @Failure      400  {object}  middleware.Problem
+// @Failure      412  {object}  entities.Ad
+// @Failure      428  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
 // @Security BearerAuth
 // @Router       /ads/{id} [put]
 func (h *UserHandler) UpdateMyAd(c *gin.Context) {
@@ -78,20 +117,26 @@ func (h *UserHandler) UpdateMyAd(c *gin.Context) {
 	adIDStr := c.Param("id")
 	adID, err := strconv.Atoi(adIDStr)
 	if err != nil || adID <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ad ID: " + err.Error()})
+		_ = c.Error(errs.Invalid("id", "invalid ad ID"))
+		return
+	}
+	version, err := middleware.IfMatch(c)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
 	ad.ID = adID
 
 	if err := c.ShouldBindJSON(&ad); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body: " + err.Error()})
+		_ = c.Error(fmt.Errorf("%w: %w", errs.ErrInvalidBody, err))
 		return
 	}
+	ad.Version = version
 
 	userID := c.GetString("user_id")
 	if err := h.userService.UpdateMyAd(c.Request.Context(), userID, &ad); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update ad: " + err.Error()})
+		_ = c.Error(fmt.Errorf("failed to update ad: %w", err))
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Ad updated successfully"})
@@ -103,21 +148,21 @@ func (h *UserHandler) UpdateMyAd(c *gin.Context) {
 // @Produce      json
 // @Param        id   path      int  true  "Ad ID"
 // @Success      200  {object}  map[string]string
-// @Failure      400  {object}  map[string]string
-// @Failure      500  {object}  map[string]string
+// @Failure      400  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
 // @Security BearerAuth
 // @Router       /ads/{id} [delete]
 func (h *UserHandler) DeleteMyAd(c *gin.Context) {
 	adIDStr := c.Param("id")
 	adID, err := strconv.Atoi(adIDStr)
 	if err != nil || adID <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ad ID: " + err.Error()})
+		_ = c.Error(errs.Invalid("id", "invalid ad ID"))
 		return
 	}
 
 	userID := c.GetString("user_id")
 	if err := h.userService.DeleteMyAd(c.Request.Context(), userID, adID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete ad: " + err.Error()})
+		_ = c.Error(fmt.Errorf("failed to delete ad: %w", err))
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Ad deleted successfully"})
@@ -125,25 +170,27 @@ func (h *UserHandler) DeleteMyAd(c *gin.Context) {
 
 // SubmitForModeration godoc
 // @Summary      Submit an ad for moderation
+// @Description  An exact copy of another ad of the user submitted within REPOST_BLOCK_WINDOW is refused with 409
 // @Tags         user-ads
 // @Produce      json
 // @Param        id   path      int  true  "Ad ID"
 // @Success      200  {object}  map[string]string
-// @Failure      400  {object}  map[string]string
-// @Failure      500  {object}  map[string]string
+// @Failure      400  {object}  middleware.Problem
+// @Failure      409  {object}  middleware.Problem
+// @Failure      500  {object}  middleware.Problem
 // @Security BearerAuth
 // @Router       /ads/{id}/submit [post]
 func (h *UserHandler) SubmitForModeration(c *gin.Context) {
 	adIDStr := c.Param("id")
 	adID, err := strconv.Atoi(adIDStr)
 	if err != nil || adID <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ad ID: " + err.Error()})
+		_ = c.Error(errs.Invalid("id", "invalid ad ID"))
 		return
 	}
 
 	userID := c.GetString("user_id")
 	if err := h.userService.SubmitForModeration(c.Request.Context(), userID, adID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to submit ad for moderation: " + err.Error()})
+		_ = c.Error(fmt.Errorf("failed to submit ad for moderation: %w", err))
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Ad submitted for moderation"})
@@ -156,42 +203,46 @@ func (h *UserHandler) SubmitForModeration(c *gin.Context) {
 // @Accept multipart/form-data
 // @Produce json
 // @Param id path int true "Ad ID"
+// @Param If-Match header string true "ETag of the ad"
+// @Param Idempotency-Key header string false "Key of the request, a retry with it gets the first response"
 // @Param file formData file true "Image file"
 // @Success 200 {object} map[string]interface{} "image added successfully"
-// @Failure 400 {object} map[string]string "invalid input"
-// @Failure 500 {object} map[string]string "internal error"
+// @Failure 400 {object} middleware.Problem "invalid input"
+// @Failure 409 {object} middleware.Problem "Idempotency-Key used for another request"
+// @Failure 412 {object} entities.Ad "the ad changed, current ad"
+// @Failure 428 {object} middleware.Problem "If-Match missing"
+// @Failure 500 {object} middleware.Problem "internal error"
 // @Security BearerAuth
 // @Router /ads/{id}/image [post]
 func (h *UserHandler) AddImageToMyAd(c *gin.Context) {
-	log.Println("YOU ARE HERE~!!!!")
-
 	file, err := c.FormFile("file")
 	if err != nil {
-		log.Println("Error no file provided: ", err)
-		c.JSON(400, gin.H{"error": "failed to get file from form: " + err.Error()})
+		slog.WarnContext(c.Request.Context(), "no file provided", "error", err)
+		_ = c.Error(errs.Invalid("file", "file is required"))
 		return
 	}
 
 	adID := c.Param("id")
 	intID, err := strconv.Atoi(adID)
 	if err != nil || intID <= 0 {
-		log.Println("Error converting to int: ", err)
-		c.JSON(400, gin.H{"error": "invalid ad ID: " + err.Error()})
+		slog.WarnContext(c.Request.Context(), "invalid ad id", "id", c.Param("id"))
+		_ = c.Error(errs.Invalid("id", "invalid ad ID"))
+		return
+	}
+	version, err := middleware.IfMatch(c)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
-	log.Println("ADID IS : ", adID)
 	adFile := &entities.AdFile{
 		FileName: file.Filename,
 		AdID:     intID,
 	}
-	err = h.userService.AddImageToMyAd(c.Request.Context(), c.GetString("user_id"), adFile)
+	err = h.userService.AddImageToMyAd(c.Request.Context(), c.GetString("user_id"), adFile, version,
+		func(path string) error { return c.SaveUploadedFile(file, path) })
 	if err != nil {
-		log.Println("Error adding image to ad: ", err)
-		c.JSON(500, gin.H{"error": "failed to add image to ad: " + err.Error()})
-		return
-	}
-	if err := c.SaveUploadedFile(file, adFile.URL); err != nil {
-		c.JSON(500, gin.H{"error": "failed to save uploaded file: " + err.Error()})
+		slog.ErrorContext(c.Request.Context(), "adding image to ad", "ad_id", intID, "error", err)
+		_ = c.Error(fmt.Errorf("failed to add image to ad: %w", err))
 		return
 	}
 	c.JSON(200, gin.H{"message": "image added to Ad successfully", "file": adFile})
@@ -204,9 +255,12 @@ func (h *UserHandler) AddImageToMyAd(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Ad ID"
 // @Param fid path int true "File ID"
+// @Param If-Match header string true "ETag of the ad"
 // @Success 200 {object} map[string]string "image deleted successfully"
-// @Failure 400 {object} map[string]string "invalid input"
-// @Failure 500 {object} map[string]string "internal error"
+// @Failure 400 {object} middleware.Problem "invalid input"
+// @Failure 412 {object} entities.Ad "the ad changed, current ad"
+// @Failure 428 {object} middleware.Problem "If-Match missing"
+// @Failure 500 {object} middleware.Problem "internal error"
 // @Security BearerAuth
 // @Router /ads/{id}/image/{fid} [delete]
 func (h *UserHandler) DeleteMyAdImage(c *gin.Context) {
@@ -214,12 +268,17 @@ func (h *UserHandler) DeleteMyAdImage(c *gin.Context) {
 	fID := c.Param("fid")
 	intID, err := strconv.Atoi(adID)
 	if err != nil || intID <= 0 {
-		c.JSON(400, gin.H{"error": "invalid ad ID: " + err.Error()})
+		_ = c.Error(errs.Invalid("id", "invalid ad ID"))
 		return
 	}
 	intfID, err := strconv.Atoi(fID)
 	if err != nil || intfID <= 0 {
-		c.JSON(400, gin.H{"error": "invalid ad file ID: " + err.Error()})
+		_ = c.Error(errs.Invalid("fid", "invalid ad file ID"))
+		return
+	}
+	version, err := middleware.IfMatch(c)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
@@ -228,8 +287,8 @@ func (h *UserHandler) DeleteMyAdImage(c *gin.Context) {
 		AdID: intID,
 	}
 
-	if err := h.userService.DeleteMyAdImage(c.Request.Context(), c.GetString("user_id"), file); err != nil {
-		c.JSON(500, gin.H{"error": "failed to delete ad image: " + err.Error()})
+	if err := h.userService.DeleteMyAdImage(c.Request.Context(), c.GetString("user_id"), file, version); err != nil {
+		_ = c.Error(fmt.Errorf("failed to delete ad image: %w", err))
 		return
 	}
 	c.JSON(200, gin.H{"message": "Ad image deleted successfully"})
@@ -242,21 +301,21 @@ func (h *UserHandler) DeleteMyAdImage(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Ad ID"
 // @Success 200 {object} map[string]interface{} "list of images"
-// @Failure 400 {object} map[string]string "invalid input"
-// @Failure 500 {object} map[string]string "internal error"
+// @Failure 400 {object} middleware.Problem "invalid input"
+// @Failure 500 {object} middleware.Problem "internal error"
 // @Security BearerAuth
 // @Router /ads/{id}/image [get]
 func (h *UserHandler) GetImagesToMyAd(c *gin.Context) {
 	adID := c.Param("id")
 	intID, err := strconv.Atoi(adID)
 	if err != nil || intID <= 0 {
-		c.JSON(400, gin.H{"error": "invalid ad ID: " + err.Error()})
+		_ = c.Error(errs.Invalid("id", "invalid ad ID"))
 		return
 	}
 
 	files, err := h.userService.GetImagesToMyAd(c.Request.Context(), c.GetString("user_id"), intID)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "failed to get ad images: " + err.Error()})
+		_ = c.Error(fmt.Errorf("failed to get ad images: %w", err))
 		return
 	}
 	c.JSON(200, gin.H{"files": files})
@@ -275,6 +334,18 @@ func (h *UserHandler) GetMyAdsByFilter(c *gin.Context) {
 			filter.CategoryID = cat
 		}
 	}
+	if priceMin := c.Query("price_min"); priceMin != "" {
+		if p, err := strconv.Atoi(priceMin); err == nil {
+			filter.PriceMin = p
+		}
+	}
+	if priceMax := c.Query("price_max"); priceMax != "" {
+		if p, err := strconv.Atoi(priceMax); err == nil {
+			filter.PriceMax = p
+		}
+	}
+	filter.Text = c.Query("text")
+	filter.Location = c.Query("location")
 	if limit := c.Query("limit"); limit != "" {
 		if l, err := strconv.Atoi(limit); err == nil {
 			filter.Limit = l
@@ -288,7 +359,7 @@ func (h *UserHandler) GetMyAdsByFilter(c *gin.Context) {
 
 	ads, err := h.userService.GetMyAdsByFilter(c.Request.Context(), userID, &filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user ads: " + err.Error()})
+		_ = c.Error(fmt.Errorf("failed to get user ads: %w", err))
 		return
 	}
 
@@ -3,9 +3,15 @@ package user
 
 import (
 	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs"
+	"ads-service/internal/errs/pkgerr/utilserr"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/rest/handlers/handlertest"
+	"ads-service/internal/rest/middleware"
 	"ads-service/internal/usecase/user"
 	"bytes"
 	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -35,7 +41,7 @@ func TestUserHandler_CreateDraft(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		c.Request = req
-		handler.CreateDraft(c)
+		handlertest.Serve(c, handler.CreateDraft)
 
 		assert.Equal(t, 201, w.Code)
 		assert.Equal(t, `{"message":"Ad draft created successfully"}`, w.Body.String())
@@ -57,12 +63,14 @@ func TestUserHandler_CreateDraft(t *testing.T) {
 		c.Request = req
 
 		mockService.On("CreateDraft", mock.Anything, "123", mock.Anything).
-			Return(errors.New("validation error"))
+			Return(fmt.Errorf("%w: %w", usecaseerr.ErrInvalidParams, utilserr.ErrTitleRequired))
 
-		handler.CreateDraft(c)
+		handlertest.Serve(c, handler.CreateDraft)
 
-		assert.Equal(t, http.StatusInternalServerError, w.Code)
-		assert.Contains(t, w.Body.String(), "failed to create ad")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, middleware.ProblemContentType, w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), `"code":"invalid_params"`)
+		assert.Contains(t, w.Body.String(), `"field":"title","code":"title_required"`)
 	})
 
 	t.Run("service error", func(t *testing.T) {
@@ -82,10 +90,10 @@ func TestUserHandler_CreateDraft(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 		c.Request = req
 
-		handler.CreateDraft(c)
+		handlertest.Serve(c, handler.CreateDraft)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
-		assert.Contains(t, w.Body.String(), "failed to create ad")
+		assert.Contains(t, w.Body.String(), `"code":"internal"`)
 	})
 
 	t.Run("missing user_id", func(t *testing.T) {
@@ -101,7 +109,7 @@ func TestUserHandler_CreateDraft(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 		c.Request = req
 
-		handler.CreateDraft(c)
+		handlertest.Serve(c, handler.CreateDraft)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
 		assert.Contains(t, w.Body.String(), "unauthorized")
@@ -128,7 +136,7 @@ func TestUserHandler_GetMyAds(t *testing.T) {
 		mockService.On("GetMyAds", mock.Anything, "123").
 			Return(expectedAds, nil)
 
-		handler.GetMyAds(c)
+		handlertest.Serve(c, handler.GetMyAds)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Contains(t, w.Body.String(), "Ad 1")
@@ -149,10 +157,10 @@ func TestUserHandler_GetMyAds(t *testing.T) {
 		mockService.On("GetMyAds", mock.Anything, "123").
 			Return(nil, assert.AnError)
 
-		handler.GetMyAds(c)
+		handlertest.Serve(c, handler.GetMyAds)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
-		assert.Contains(t, w.Body.String(), "failed to get user ads")
+		assert.Contains(t, w.Body.String(), `"code":"internal"`)
 	})
 }
 
@@ -171,10 +179,11 @@ func TestUserHandler_UpdateMyAd(t *testing.T) {
 		body := `{"title":"Updated Ad","description":"desc","category_id":1}`
 		req := httptest.NewRequest(http.MethodPut, "/ads/1", strings.NewReader(body))
 		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
 		c.Request = req
 		c.Params = gin.Params{{Key: "id", Value: "1"}}
 
-		handler.UpdateMyAd(c)
+		handlertest.Serve(c, handler.UpdateMyAd)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Contains(t, w.Body.String(), "Ad updated successfully")
@@ -194,7 +203,7 @@ func TestUserHandler_UpdateMyAd(t *testing.T) {
 		c.Request = req
 		c.Params = gin.Params{{Key: "id", Value: "abc"}}
 
-		handler.UpdateMyAd(c)
+		handlertest.Serve(c, handler.UpdateMyAd)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		assert.Contains(t, w.Body.String(), "invalid ad ID")
@@ -211,10 +220,11 @@ func TestUserHandler_UpdateMyAd(t *testing.T) {
 		body := `invalid-json`
 		req := httptest.NewRequest(http.MethodPut, "/ads/1", strings.NewReader(body))
 		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
 		c.Request = req
 		c.Params = gin.Params{{Key: "id", Value: "1"}}
 
-		handler.UpdateMyAd(c)
+		handlertest.Serve(c, handler.UpdateMyAd)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		assert.Contains(t, w.Body.String(), "invalid request body")
@@ -234,16 +244,106 @@ func TestUserHandler_UpdateMyAd(t *testing.T) {
 		body := `{"title":"Updated Ad","description":"desc","category_id":1}`
 		req := httptest.NewRequest(http.MethodPut, "/ads/1", strings.NewReader(body))
 		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
 		c.Request = req
 		c.Params = gin.Params{{Key: "id", Value: "1"}}
 
-		handler.UpdateMyAd(c)
+		handlertest.Serve(c, handler.UpdateMyAd)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
-		assert.Contains(t, w.Body.String(), "failed to update ad")
+		assert.Contains(t, w.Body.String(), `"code":"internal"`)
+	})
+
+	t.Run("version from if-match", func(t *testing.T) {
+		mockService := new(user.MockUserService)
+		handler := NewUserHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		// a version in the body is ignored, only If-Match names it
+		mockService.On("UpdateMyAd", mock.Anything, "123", mock.MatchedBy(func(ad *entities.Ad) bool {
+			return ad.Version == 4
+		})).Return(nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "123")
+		body := `{"title":"Updated Ad","category_id":1,"Version":9}`
+		req := httptest.NewRequest(http.MethodPut, "/ads/1", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"4"`)
+		c.Request = req
+		c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		handlertest.Serve(c, handler.UpdateMyAd)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("missing if-match", func(t *testing.T) {
+		mockService := new(user.MockUserService)
+		handler := NewUserHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "123")
+		req := httptest.NewRequest(http.MethodPut, "/ads/1", strings.NewReader(`{"title":"Updated Ad"}`))
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+		c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		handlertest.Serve(c, handler.UpdateMyAd)
+
+		assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"if_match_required"`)
+	})
+
+	t.Run("stale version", func(t *testing.T) {
+		mockService := new(user.MockUserService)
+		handler := NewUserHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		current := &entities.Ad{ID: 1, Title: "Newer", Version: 5}
+		mockService.On("UpdateMyAd", mock.Anything, "123", mock.Anything).
+			Return(errs.NewStale(usecaseerr.ErrAdModified, current, current.Version))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user_id", "123")
+		body := `{"title":"Updated Ad","description":"desc","category_id":1}`
+		req := httptest.NewRequest(http.MethodPut, "/ads/1", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"4"`)
+		c.Request = req
+		c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		handlertest.Serve(c, handler.UpdateMyAd)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+		assert.Equal(t, `"5"`, w.Header().Get("ETag"))
+		assert.Contains(t, w.Body.String(), `"Title":"Newer"`)
 	})
 }
 
+func TestUserHandler_GetMyAd(t *testing.T) {
+	mockService := new(user.MockUserService)
+	handler := NewUserHandler(mockService)
+	defer mockService.AssertExpectations(t)
+
+	mockService.On("GetMyAd", mock.Anything, "123", 1).Return(&entities.Ad{ID: 1, Version: 2}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set("user_id", "123")
+	c.Request = httptest.NewRequest(http.MethodGet, "/ads/1", nil)
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	handlertest.Serve(c, handler.GetMyAd)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+}
+
 func TestUserHandler_DeleteMyAd(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockService := new(user.MockUserService)
@@ -260,7 +360,7 @@ func TestUserHandler_DeleteMyAd(t *testing.T) {
 		mockService.On("DeleteMyAd", mock.Anything, "123", 1).
 			Return(nil)
 
-		handler.DeleteMyAd(c)
+		handlertest.Serve(c, handler.DeleteMyAd)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Contains(t, w.Body.String(), "Ad deleted successfully")
@@ -275,8 +375,9 @@ func TestUserHandler_DeleteMyAd(t *testing.T) {
 		c, _ := gin.CreateTestContext(w)
 		c.Set("user_id", "123")
 		c.Params = gin.Params{{Key: "id", Value: "abc"}}
+		c.Request = httptest.NewRequest(http.MethodDelete, "/ads/abc", nil)
 
-		handler.DeleteMyAd(c)
+		handlertest.Serve(c, handler.DeleteMyAd)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		assert.Contains(t, w.Body.String(), "invalid ad ID")
@@ -297,10 +398,10 @@ func TestUserHandler_DeleteMyAd(t *testing.T) {
 		c.Set("user_id", "123")
 		c.Params = gin.Params{{Key: "id", Value: "1"}}
 
-		handler.DeleteMyAd(c)
+		handlertest.Serve(c, handler.DeleteMyAd)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
-		assert.Contains(t, w.Body.String(), "failed to delete ad")
+		assert.Contains(t, w.Body.String(), `"code":"internal"`)
 	})
 }
 
@@ -319,7 +420,7 @@ func TestUserHandler_SubmitForModeration(t *testing.T) {
 		c.Set("user_id", "123")
 		c.Params = gin.Params{{Key: "id", Value: "1"}}
 
-		handler.SubmitForModeration(c)
+		handlertest.Serve(c, handler.SubmitForModeration)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Contains(t, w.Body.String(), "Ad submitted for moderation")
@@ -336,7 +437,7 @@ func TestUserHandler_SubmitForModeration(t *testing.T) {
 		c.Set("user_id", "123")
 		c.Params = gin.Params{{Key: "id", Value: "abc"}}
 
-		handler.SubmitForModeration(c)
+		handlertest.Serve(c, handler.SubmitForModeration)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		assert.Contains(t, w.Body.String(), "invalid ad ID")
@@ -356,10 +457,29 @@ func TestUserHandler_SubmitForModeration(t *testing.T) {
 		c.Set("user_id", "123")
 		c.Params = gin.Params{{Key: "id", Value: "1"}}
 
-		handler.SubmitForModeration(c)
+		handlertest.Serve(c, handler.SubmitForModeration)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
-		assert.Contains(t, w.Body.String(), "failed to submit ad for moderation")
+		assert.Contains(t, w.Body.String(), `"code":"internal"`)
+	})
+
+	t.Run("repost", func(t *testing.T) {
+		mockService := new(user.MockUserService)
+		handler := NewUserHandler(mockService)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("SubmitForModeration", mock.Anything, "123", 1).
+			Return(usecaseerr.ErrRepost)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/ads/1/submit", nil)
+		c.Set("user_id", "123")
+		c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		handlertest.Serve(c, handler.SubmitForModeration)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
 	})
 }
 
@@ -377,6 +497,7 @@ func TestUserHandler_AddImageToMyAd(t *testing.T) {
 
 		req := httptest.NewRequest(http.MethodPost, "/ads/1/image", body)
 		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("If-Match", `"1"`)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -384,9 +505,9 @@ func TestUserHandler_AddImageToMyAd(t *testing.T) {
 		c.Params = gin.Params{{Key: "id", Value: "1"}}
 		c.Set("user_id", "user-1")
 
-		handler.AddImageToMyAd(c)
+		handlertest.Serve(c, handler.AddImageToMyAd)
 		assert.Equal(t, 400, w.Code)
-		assert.Contains(t, w.Body.String(), "failed to get file from form")
+		assert.Contains(t, w.Body.String(), `"field":"file"`)
 	})
 
 	t.Run("invalid ad id", func(t *testing.T) {
@@ -411,7 +532,7 @@ func TestUserHandler_AddImageToMyAd(t *testing.T) {
 		c.Params = gin.Params{{Key: "id", Value: "abc"}}
 		c.Set("user_id", "user-1")
 
-		handler.AddImageToMyAd(c)
+		handlertest.Serve(c, handler.AddImageToMyAd)
 		assert.Equal(t, 400, w.Code)
 		assert.Contains(t, w.Body.String(), "invalid ad ID")
 	})
@@ -431,6 +552,7 @@ func TestUserHandler_AddImageToMyAd(t *testing.T) {
 
 		req := httptest.NewRequest(http.MethodPost, "/ads/1/image", body)
 		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("If-Match", `"1"`)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -439,11 +561,11 @@ func TestUserHandler_AddImageToMyAd(t *testing.T) {
 		c.Set("user_id", "user-1")
 
 		mockService.On("AddImageToMyAd", mock.Anything, "user-1",
-			mock.AnythingOfType("*entities.AdFile")).Return(errors.New("service error"))
+			mock.AnythingOfType("*entities.AdFile"), 1, mock.Anything).Return(errors.New("service error"))
 
-		handler.AddImageToMyAd(c)
+		handlertest.Serve(c, handler.AddImageToMyAd)
 		assert.Equal(t, 500, w.Code)
-		assert.Contains(t, w.Body.String(), "failed to add image to ad")
+		assert.Contains(t, w.Body.String(), `"code":"internal"`)
 	})
 }
 
@@ -459,13 +581,14 @@ func TestUserHandler_DeleteMyAdImage(t *testing.T) {
 		c.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "fid", Value: "1"}}
 
 		mockService.On("DeleteMyAdImage", mock.Anything, "user-1",
-			mock.AnythingOfType("*entities.AdFile")).
+			mock.AnythingOfType("*entities.AdFile"), 1).
 			Return(nil)
 
 		req := httptest.NewRequest(http.MethodDelete, "/ads/1/image/1", nil)
+		req.Header.Set("If-Match", `"1"`)
 		c.Request = req
 
-		handler.DeleteMyAdImage(c)
+		handlertest.Serve(c, handler.DeleteMyAdImage)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Contains(t, w.Body.String(), "Ad image deleted successfully")
@@ -484,7 +607,7 @@ func TestUserHandler_DeleteMyAdImage(t *testing.T) {
 		req := httptest.NewRequest(http.MethodDelete, "/ads/abc/image/img-123", nil)
 		c.Request = req
 
-		handler.DeleteMyAdImage(c)
+		handlertest.Serve(c, handler.DeleteMyAdImage)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		assert.Contains(t, w.Body.String(), "invalid ad ID")
@@ -501,15 +624,16 @@ func TestUserHandler_DeleteMyAdImage(t *testing.T) {
 		c.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "fid", Value: "1"}}
 
 		mockService.On("DeleteMyAdImage", mock.Anything, mock.Anything,
-			mock.AnythingOfType("*entities.AdFile")).
+			mock.AnythingOfType("*entities.AdFile"), 1).
 			Return(errors.New("some error"))
 		req := httptest.NewRequest(http.MethodDelete, "/ads/1", nil)
+		req.Header.Set("If-Match", `"1"`)
 		c.Request = req
 
-		handler.DeleteMyAdImage(c)
+		handlertest.Serve(c, handler.DeleteMyAdImage)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
-		assert.Contains(t, w.Body.String(), "failed to delete ad image")
+		assert.Contains(t, w.Body.String(), `"code":"internal"`)
 	})
 }
 
@@ -535,7 +659,7 @@ func TestUserHandler_GetMyAdsByFilter(t *testing.T) {
 		c.Request = req
 		c.Set("user_id", "user-1")
 
-		handler.GetMyAdsByFilter(c)
+		handlertest.Serve(c, handler.GetMyAdsByFilter)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Contains(t, w.Body.String(), "Ad 1")
@@ -557,9 +681,9 @@ func TestUserHandler_GetMyAdsByFilter(t *testing.T) {
 		c.Request = req
 		c.Set("user_id", "user-1")
 
-		handler.GetMyAdsByFilter(c)
+		handlertest.Serve(c, handler.GetMyAdsByFilter)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
-		assert.Contains(t, w.Body.String(), "failed to get user ads")
+		assert.Contains(t, w.Body.String(), `"code":"internal"`)
 	})
 }
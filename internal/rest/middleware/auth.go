@@ -1,74 +1,79 @@
 package middleware
 
 import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs"
+	"ads-service/internal/errs/usecaseerr"
+	customLogger "ads-service/pkg/logger"
 	"ads-service/pkg/utils"
-	"log"
-	"os"
+	"errors"
+	"log/slog"
+	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 )
 
+var errAdminMFARequired = errs.New(errs.Forbidden, "two_factor_required", "two-factor authentication required")
+
 func (m *Middleware) UserAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		data := strings.Split(authHeader, " ")
-		if len(data) != 2 || data[0] != "Bearer" {
-			log.Println("Invalid Authorization header format")
-			c.JSON(401, gin.H{"error": "unauthorized"})
+		claims, ok := m.parseBearer(c)
+		if !ok {
+			_ = c.Error(errs.ErrUnauthorized)
 			c.Abort()
 			return
 		}
-		rawtoken := data[1]
-
-		token, err := jwt.ParseWithClaims(rawtoken, &utils.CustomClaims{},
-			func(token *jwt.Token) (interface{}, error) {
-				return []byte(os.Getenv("JWT_SECRET_KEY")), nil
-			})
-		if err != nil {
-			log.Println("Err parsing token:", err)
-			c.JSON(401, gin.H{"error": "unauthorized"})
-			c.Abort()
-			return
-		}
-		claims, ok := token.Claims.(*utils.CustomClaims)
-		if !ok || !token.Valid {
-			log.Println("Err validating token:", err)
-			c.JSON(401, gin.H{"error": "unauthorized"})
+		// a ban revokes refresh tokens and API keys, the access token still has to be refused here
+		if err := m.authService.CheckActive(c.Request.Context(), claims.UserID); err != nil {
+			slog.WarnContext(c.Request.Context(), "user access denied", "user_id", claims.UserID, "error", err)
+			if errors.Is(err, usecaseerr.ErrUserNotFound) {
+				err = errs.ErrUnauthorized
+			}
+			_ = c.Error(err)
 			c.Abort()
 			return
 		}
 
-		c.Set("user_id", claims.UserID)
+		setUser(c, claims.UserID)
 		c.Next()
 	}
 }
 
-func (m *Middleware) AdminAuth() gin.HandlerFunc {
+// UserOrAPIKeyAuth accepts an X-API-Key header as an alternative to the bearer token.
+// Safe methods need the ads:read scope, everything else ads:write.
+func (m *Middleware) UserOrAPIKeyAuth() gin.HandlerFunc {
+	userAuth := m.UserAuth()
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		data := strings.Split(authHeader, " ")
-		if len(data) != 2 || data[0] != "Bearer" {
-			c.JSON(401, gin.H{"error": "unauthorized"})
-			c.Abort()
+		rawKey := c.GetHeader("X-API-Key")
+		if rawKey == "" {
+			userAuth(c)
 			return
 		}
-		rawtoken := data[1]
 
-		token, err := jwt.ParseWithClaims(rawtoken, &utils.CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-			return []byte(os.Getenv("JWT_SECRET_KEY")), nil // TODO: remember
-		})
+		scope := entities.ScopeAdsWrite
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			scope = entities.ScopeAdsRead
+		}
+		key, err := m.apiKeyService.Authenticate(c.Request.Context(), rawKey, scope)
 		if err != nil {
-			log.Println("Error parsing token:", err)
-			c.JSON(401, gin.H{"error": "unauthorized"})
+			slog.WarnContext(c.Request.Context(), "authenticating api key", "error", err)
+			_ = c.Error(err)
 			c.Abort()
 			return
 		}
-		claims, ok := token.Claims.(*utils.CustomClaims)
-		if !ok || !token.Valid {
-			log.Println("Error validating token:")
-			c.JSON(401, gin.H{"error": "unauthorized"})
+
+		setUser(c, key.UserID)
+		c.Set("api_key_id", key.ID)
+		c.Next()
+	}
+}
+
+func (m *Middleware) AdminAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := m.parseBearer(c)
+		if !ok {
+			_ = c.Error(errs.ErrUnauthorized)
 			c.Abort()
 			return
 		}
@@ -76,13 +81,52 @@ func (m *Middleware) AdminAuth() gin.HandlerFunc {
 
 		isAdmin, err := m.authService.IsAdmin(c.Request.Context(), userID)
 		if err != nil || !isAdmin {
-			log.Println("User is not admin or error checking admin status:", err)
-			c.JSON(403, gin.H{"error": "forbidden"})
+			slog.WarnContext(c.Request.Context(), "admin access denied", "user_id", userID, "error", err)
+			_ = c.Error(errs.ErrForbidden)
+			c.Abort()
+			return
+		}
+		if m.config.AdminMFARequired && !claims.MFA {
+			_ = c.Error(errAdminMFARequired)
 			c.Abort()
 			return
 		}
 
+		setUser(c, userID)
 		c.Next()
 	}
+}
 
+// setUser stores the authenticated user for handlers and for the log records of the request.
+func setUser(c *gin.Context, userID string) {
+	c.Set("user_id", userID)
+	c.Request = c.Request.WithContext(customLogger.WithUserID(c.Request.Context(), userID))
+}
+
+// parseBearer validates the bearer token against the JWT key set.
+func (m *Middleware) parseBearer(c *gin.Context) (*utils.CustomClaims, bool) {
+	authHeader := c.GetHeader("Authorization")
+	data := strings.Split(authHeader, " ")
+	if len(data) != 2 || data[0] != "Bearer" {
+		slog.DebugContext(c.Request.Context(), "invalid Authorization header format")
+		return nil, false
+	}
+
+	claims := &utils.CustomClaims{}
+	token, err := m.keys.Parse(data[1], claims)
+	if err != nil {
+		slog.DebugContext(c.Request.Context(), "parsing token", "error", err)
+		return nil, false
+	}
+	if !token.Valid {
+		slog.DebugContext(c.Request.Context(), "token is not valid")
+		return nil, false
+	}
+	// MFA challenge tokens only work on the second login step.
+	if claims.Purpose != "" {
+		slog.WarnContext(c.Request.Context(), "token with purpose used for API access",
+			"purpose", claims.Purpose)
+		return nil, false
+	}
+	return claims, true
 }
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/usecase/auth"
+	"ads-service/pkg/jwtkeys"
+	"ads-service/pkg/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserAuth(t *testing.T) {
+	keys, err := jwtkeys.NewHMAC([]byte("testsecret"))
+	require.NoError(t, err)
+	token, err := utils.GenerateToken(keys, "u1", 15)
+	require.NoError(t, err)
+
+	serve := func(service *auth.MockAuthService, authorization string) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		m := &Middleware{authService: service, keys: keys}
+		router := gin.New()
+		router.Use(Errors(), m.UserAuth())
+		router.GET("/ads/my", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"user_id": c.GetString("user_id")})
+		})
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/ads/my", nil)
+		req.Header.Set("Authorization", authorization)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("active user", func(t *testing.T) {
+		service := new(auth.MockAuthService)
+		defer service.AssertExpectations(t)
+		service.On("CheckActive", mock.Anything, "u1").Return(nil)
+
+		w := serve(service, "Bearer "+token)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"user_id":"u1"`)
+	})
+
+	t.Run("banned user", func(t *testing.T) {
+		service := new(auth.MockAuthService)
+		defer service.AssertExpectations(t)
+		service.On("CheckActive", mock.Anything, "u1").Return(usecaseerr.ErrUserBanned)
+
+		w := serve(service, "Bearer "+token)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"user_banned"`)
+	})
+
+	t.Run("deleted user", func(t *testing.T) {
+		service := new(auth.MockAuthService)
+		defer service.AssertExpectations(t)
+		service.On("CheckActive", mock.Anything, "u1").Return(usecaseerr.ErrUserNotFound)
+
+		w := serve(service, "Bearer "+token)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("no token", func(t *testing.T) {
+		service := new(auth.MockAuthService)
+		defer service.AssertExpectations(t)
+
+		w := serve(service, "")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -0,0 +1,181 @@
+package middleware
+
+import (
+	"ads-service/internal/errs"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// ProblemContentType - media type of RFC 7807 error responses.
+const ProblemContentType = "application/problem+json"
+
+// Problem - RFC 7807 error body. Code is stable and meant for clients, detail only for people.
+type Problem struct {
+	Type      string         `json:"type"`
+	Title     string         `json:"title"`
+	Status    int            `json:"status"`
+	Detail    string         `json:"detail,omitempty"`
+	Instance  string         `json:"instance,omitempty"`
+	Code      string         `json:"code"`
+	RequestID string         `json:"request_id,omitempty"`
+	Errors    []ProblemField `json:"errors,omitempty"`
+}
+
+// ProblemField - validation error of a single request field.
+type ProblemField struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+var statuses = map[errs.Kind]int{
+	errs.Validation:           http.StatusBadRequest,
+	errs.Unauthorized:         http.StatusUnauthorized,
+	errs.Forbidden:            http.StatusForbidden,
+	errs.NotFound:             http.StatusNotFound,
+	errs.Conflict:             http.StatusConflict,
+	errs.TooManyRequests:      http.StatusTooManyRequests,
+	errs.TooLarge:             http.StatusRequestEntityTooLarge,
+	errs.Internal:             http.StatusInternalServerError,
+	errs.PreconditionFailed:   http.StatusPreconditionFailed,
+	errs.PreconditionRequired: http.StatusPreconditionRequired,
+}
+
+var registerTagNames sync.Once
+
+// Errors renders the last error a handler attached with c.Error as application/problem+json.
+// The status comes from the error kind, responses already written by the handler are left alone.
+// A stale write is answered with the current representation and its ETag instead of a problem.
+func Errors() gin.HandlerFunc {
+	registerTagNames.Do(func() {
+		// field names in validation errors should match the JSON the client sent
+		if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+			v.RegisterTagNameFunc(jsonName)
+		}
+	})
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err := c.Errors.Last().Err
+		var stale *errs.StaleError
+		if errors.As(err, &stale) {
+			c.Header("ETag", ETag(stale.Version))
+			c.JSON(http.StatusPreconditionFailed, stale.Current)
+			return
+		}
+		problem := NewProblem(err)
+		problem.Instance = c.Request.URL.Path
+		problem.RequestID = c.GetString("request_id")
+		if problem.Status >= http.StatusInternalServerError {
+			slog.ErrorContext(c.Request.Context(), "request failed", "error", err)
+		}
+
+		c.Header("Content-Type", ProblemContentType)
+		c.JSON(problem.Status, problem)
+	}
+}
+
+// NewProblem maps an error to its problem body.
+func NewProblem(err error) Problem {
+	kind := errs.KindOf(err)
+	status, ok := statuses[kind]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+	problem := Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail(err),
+		Code:   string(errs.Internal),
+	}
+
+	var domainErr *errs.Error
+	if errors.As(err, &domainErr) {
+		problem.Code = domainErr.Code()
+	}
+	for _, field := range errs.FieldErrors(err) {
+		problem.Errors = append(problem.Errors, ProblemField{Field: field.Field(), Code: field.Code(),
+			Message: field.Error()})
+	}
+	problem.Errors = append(problem.Errors, bindingFields(err)...)
+	return problem
+}
+
+// detail cuts the message after the outermost domain error, causes wrapped below it
+// may carry database or driver details that must not reach the client.
+func detail(err error) string {
+	var domainErr *errs.Error
+	if !errors.As(err, &domainErr) {
+		return http.StatusText(http.StatusInternalServerError)
+	}
+	msg := err.Error()
+	if i := strings.Index(msg, domainErr.Error()); i >= 0 {
+		return msg[:i+len(domainErr.Error())]
+	}
+	return msg
+}
+
+// bindingFields converts request binding errors into field errors.
+func bindingFields(err error) []ProblemField {
+	var fields []ProblemField
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		for _, fe := range validationErrs {
+			fields = append(fields, ProblemField{Field: fieldPath(fe.Namespace()), Code: fe.Tag(),
+				Message: validationMessage(fe)})
+		}
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		fields = append(fields, ProblemField{Field: typeErr.Field, Code: "type",
+			Message: "must be " + typeErr.Type.String()})
+	}
+	return fields
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "oneof":
+		return "must be one of: " + fe.Param()
+	case "min", "gte":
+		return "must be at least " + fe.Param()
+	case "max", "lte":
+		return "must be at most " + fe.Param()
+	default:
+		return "failed the " + fe.Tag() + " check"
+	}
+}
+
+// fieldPath drops the struct name validator puts in front of the field path.
+func fieldPath(namespace string) string {
+	if _, path, ok := strings.Cut(namespace, "."); ok {
+		return path
+	}
+	return namespace
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
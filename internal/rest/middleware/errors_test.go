@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"ads-service/internal/errs"
+	"ads-service/internal/errs/pkgerr/utilserr"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveError(t *testing.T, handler gin.HandlerFunc, body string) (*httptest.ResponseRecorder, Problem) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID(), Errors())
+	router.POST("/ads", handler)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/ads", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	var problem Problem
+	if w.Header().Get("Content-Type") == ProblemContentType {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	}
+	return w, problem
+}
+
+func TestErrors_Status(t *testing.T) {
+	for name, tc := range map[string]struct {
+		err    error
+		status int
+		code   string
+	}{
+		"not found":    {usecaseerr.ErrAdNotFound, http.StatusNotFound, "ad_not_found"},
+		"forbidden":    {usecaseerr.ErrAccessDenied, http.StatusForbidden, "access_denied"},
+		"validation":   {usecaseerr.ErrInvalidParams, http.StatusBadRequest, "invalid_params"},
+		"conflict":     {usecaseerr.ErrRepost, http.StatusConflict, "repost"},
+		"unauthorized": {usecaseerr.ErrInvalidCredentials, http.StatusUnauthorized, "invalid_credentials"},
+		"rate limited": {usecaseerr.ErrTooManyAttempts, http.StatusTooManyRequests, "too_many_attempts"},
+		"stale":        {usecaseerr.ErrAdModified, http.StatusPreconditionFailed, "ad_modified"},
+		"no if-match":  {errIfMatchRequired, http.StatusPreconditionRequired, "if_match_required"},
+		"internal":     {usecaseerr.ErrCreatingAd, http.StatusInternalServerError, "creating_ad"},
+		"unclassified": {assert.AnError, http.StatusInternalServerError, "internal"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			w, problem := serveError(t, func(c *gin.Context) {
+				_ = c.Error(fmt.Errorf("failed to create ad: %w", tc.err))
+			}, "")
+
+			assert.Equal(t, tc.status, w.Code)
+			assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tc.status, problem.Status)
+			assert.Equal(t, tc.code, problem.Code)
+			assert.Equal(t, http.StatusText(tc.status), problem.Title)
+			assert.Equal(t, "/ads", problem.Instance)
+			assert.Equal(t, w.Header().Get(RequestIDHeader), problem.RequestID)
+		})
+	}
+}
+
+func TestErrors_Detail(t *testing.T) {
+	t.Run("cause is not exposed", func(t *testing.T) {
+		cause := errors.New(`duplicate key value violates unique constraint "ads_pkey"`)
+		_, problem := serveError(t, func(c *gin.Context) {
+			_ = c.Error(fmt.Errorf("failed to create ad: %w",
+				fmt.Errorf("%w: %w", usecaseerr.ErrCreatingAd, fmt.Errorf("%w: %w", repoerr.ErrInsert, cause))))
+		}, "")
+
+		assert.Equal(t, "failed to create ad: error creating ad", problem.Detail)
+	})
+
+	t.Run("unclassified", func(t *testing.T) {
+		_, problem := serveError(t, func(c *gin.Context) {
+			_ = c.Error(errors.New("dial tcp 10.0.0.5:5432: connection refused"))
+		}, "")
+
+		assert.Equal(t, http.StatusText(http.StatusInternalServerError), problem.Detail)
+	})
+}
+
+func TestErrors_Fields(t *testing.T) {
+	t.Run("domain", func(t *testing.T) {
+		_, problem := serveError(t, func(c *gin.Context) {
+			_ = c.Error(fmt.Errorf("%w: %w", usecaseerr.ErrInvalidParams,
+				errors.Join(utilserr.ErrTitleRequired, utilserr.ErrNegativePrice)))
+		}, "")
+
+		assert.Equal(t, []ProblemField{
+			{Field: "title", Code: "title_required", Message: "title is required"},
+			{Field: "price", Code: "negative_price", Message: "price cannot be negative"},
+		}, problem.Errors)
+	})
+
+	t.Run("binding", func(t *testing.T) {
+		var req struct {
+			Reason string `json:"reason" binding:"required"`
+			Count  int    `json:"count"`
+		}
+		bind := func(c *gin.Context) {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				_ = c.Error(fmt.Errorf("%w: %w", errs.ErrInvalidBody, err))
+			}
+		}
+
+		w, problem := serveError(t, bind, `{}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "invalid_body", problem.Code)
+		assert.Equal(t, []ProblemField{{Field: "reason", Code: "required", Message: "is required"}}, problem.Errors)
+
+		_, problem = serveError(t, bind, `{"reason":"spam","count":"many"}`)
+		assert.Equal(t, []ProblemField{{Field: "count", Code: "type", Message: "must be int"}}, problem.Errors)
+	})
+
+	t.Run("parameter", func(t *testing.T) {
+		_, problem := serveError(t, func(c *gin.Context) {
+			_ = c.Error(errs.Invalid("id", "invalid ad ID"))
+		}, "")
+
+		assert.Equal(t, "invalid_id", problem.Code)
+		assert.Equal(t, []ProblemField{{Field: "id", Code: "invalid_id", Message: "invalid ad ID"}}, problem.Errors)
+	})
+}
+
+func TestErrors_Written(t *testing.T) {
+	w, _ := serveError(t, func(c *gin.Context) {
+		c.String(http.StatusOK, "partial export")
+		_ = c.Error(assert.AnError)
+	}, "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "partial export", w.Body.String())
+}
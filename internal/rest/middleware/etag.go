@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"ads-service/internal/errs"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errIfMatchRequired = errs.New(errs.PreconditionRequired, "if_match_required",
+		"If-Match header with the ETag of the ad is required")
+	errInvalidIfMatch = errs.NewField("invalid_if_match", "If-Match", "If-Match must be a single ETag or *")
+)
+
+// ETag - strong entity tag of the version of a resource.
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// IfMatch returns the version named by the If-Match header of a write, 0 for "*", which matches any version.
+// Weak tags never match in If-Match, so they are refused along with lists.
+func IfMatch(c *gin.Context) (int, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		return 0, errIfMatchRequired
+	}
+	if header == "*" {
+		return 0, nil
+	}
+	tag, ok := strings.CutPrefix(header, `"`)
+	if !ok {
+		return 0, errInvalidIfMatch
+	}
+	tag, ok = strings.CutSuffix(tag, `"`)
+	if !ok {
+		return 0, errInvalidIfMatch
+	}
+	version, err := strconv.Atoi(tag)
+	if err != nil || version <= 0 {
+		return 0, errInvalidIfMatch
+	}
+	return version, nil
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"ads-service/internal/errs"
+	"ads-service/internal/errs/usecaseerr"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIfMatch(t *testing.T) {
+	for name, tc := range map[string]struct {
+		header  string
+		version int
+		err     error
+	}{
+		"version":  {`"3"`, 3, nil},
+		"any":      {`*`, 0, nil},
+		"missing":  {``, 0, errIfMatchRequired},
+		"weak":     {`W/"3"`, 0, errInvalidIfMatch},
+		"list":     {`"3", "4"`, 0, errInvalidIfMatch},
+		"unquoted": {`3`, 0, errInvalidIfMatch},
+		"zero":     {`"0"`, 0, errInvalidIfMatch},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPut, "/ads/1", nil)
+			if tc.header != "" {
+				c.Request.Header.Set("If-Match", tc.header)
+			}
+
+			version, err := IfMatch(c)
+			assert.Equal(t, tc.version, version)
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestErrors_Stale(t *testing.T) {
+	current := map[string]any{"id": 1, "title": "newer"}
+	w, _ := serveError(t, func(c *gin.Context) {
+		_ = c.Error(fmt.Errorf("failed to update ad: %w", errs.NewStale(usecaseerr.ErrAdModified, current, 7)))
+	}, "")
+
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	assert.Equal(t, `"7"`, w.Header().Get("ETag"))
+	assert.JSONEq(t, `{"id":1,"title":"newer"}`, w.Body.String())
+}
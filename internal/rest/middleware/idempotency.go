@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from an earlier request.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// replayedHeaders - response headers stored with the body and sent again on replay, besides Content-Type.
+var replayedHeaders = []string{"Location", "ETag"}
+
+var (
+	errInvalidIdempotencyKey = errs.NewField("invalid_idempotency_key", IdempotencyKeyHeader,
+		"Idempotency-Key must be 1 to 255 printable ASCII characters")
+	errIdempotentBodyTooLarge = errs.New(errs.TooLarge, "body_too_large",
+		"the body of a request with an Idempotency-Key is too large")
+)
+
+// Idempotency makes POST requests sent with an Idempotency-Key safe to retry. The first request with a key
+// runs and its response is stored, a retry with the same method, path and body gets the stored response
+// without running again, a different request with the same key gets 409. Failed requests are not stored,
+// their retries run again. It must come after the authentication, keys are per user. The body is buffered
+// to be fingerprinted, one over Config.IdempotencyMaxBody gets 413.
+func (m *Middleware) Idempotency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		if !validIdempotencyKey(key) {
+			_ = c.Error(errInvalidIdempotencyKey)
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, m.config.IdempotencyMaxBody))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			_ = c.Error(errIdempotentBodyTooLarge)
+			c.Abort()
+			return
+		}
+		if err != nil {
+			_ = c.Error(fmt.Errorf("%w: %w", errs.ErrInvalidBody, err))
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		userID := c.GetString("user_id")
+		fingerprint := requestFingerprint(c.Request, body)
+		stored, err := m.idempotencyService.Begin(ctx, userID, key, fingerprint)
+		if err != nil {
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+		if stored != nil {
+			for name, value := range stored.Headers {
+				c.Header(name, value)
+			}
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(stored.Status, stored.ContentType, stored.Body)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+		c.Writer = recorder.ResponseWriter
+
+		// the client may be gone already, the key must not stay reserved because of it
+		ctx = context.WithoutCancel(ctx)
+		// errors are rendered by Errors only after this returns, so nothing is written for them here
+		if len(c.Errors) > 0 || !recorder.Written() || recorder.Status() >= http.StatusInternalServerError {
+			if err = m.idempotencyService.Release(ctx, userID, key); err != nil {
+				slog.ErrorContext(ctx, "releasing idempotency key", "error", err)
+			}
+			return
+		}
+		err = m.idempotencyService.Complete(ctx, &entities.IdempotentRequest{
+			UserID:      userID,
+			Key:         key,
+			Fingerprint: fingerprint,
+			Status:      recorder.Status(),
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+			Headers:     storedHeaders(recorder.Header()),
+		})
+		if err != nil {
+			// the request is done, its retry gets 409 until the key expires
+			slog.ErrorContext(ctx, "storing idempotent response", "error", err)
+		}
+	}
+}
+
+func storedHeaders(header http.Header) map[string]string {
+	stored := make(map[string]string)
+	for _, name := range replayedHeaders {
+		if value := header.Get(name); value != "" {
+			stored[name] = value
+		}
+	}
+	return stored
+}
+
+func validIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+	for i := range len(key) {
+		if key[i] < ' ' || key[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// requestFingerprint hashes the method, path and body of the request. The boundary of a multipart body
+// is left out, clients pick a new one for every attempt.
+func requestFingerprint(r *http.Request, body []byte) string {
+	if _, params, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err == nil && params["boundary"] != "" {
+		body = bytes.ReplaceAll(body, []byte(params["boundary"]), nil)
+	}
+	hash := sha256.New()
+	_, _ = fmt.Fprintf(hash, "%s %s\n", r.Method, r.URL.Path)
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body written through it.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/usecase/idempotency"
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// serveIdempotent sends a POST with the key through the middleware to a handler that answers with status.
+func serveIdempotent(service *idempotency.MockIdempotencyService, key, body string,
+	status int) (*httptest.ResponseRecorder, int) {
+	gin.SetMode(gin.TestMode)
+	m := &Middleware{idempotencyService: service, config: Config{IdempotencyMaxBody: 64}}
+	calls := 0
+
+	router := gin.New()
+	router.Use(Errors(), func(c *gin.Context) {
+		c.Set("user_id", "u1")
+	}, m.Idempotency())
+	router.POST("/ads/create", func(c *gin.Context) {
+		calls++
+		if status >= http.StatusBadRequest {
+			_ = c.Error(usecaseerr.ErrInvalidParams)
+			return
+		}
+		c.Header("ETag", ETag(1))
+		c.JSON(status, gin.H{"id": 7})
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/ads/create", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	router.ServeHTTP(w, req)
+	return w, calls
+}
+
+func TestIdempotency(t *testing.T) {
+	t.Run("without key", func(t *testing.T) {
+		service := new(idempotency.MockIdempotencyService)
+		defer service.AssertExpectations(t)
+
+		w, calls := serveIdempotent(service, "", `{"title":"BMW"}`, http.StatusCreated)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("first request is stored", func(t *testing.T) {
+		service := new(idempotency.MockIdempotencyService)
+		defer service.AssertExpectations(t)
+
+		service.On("Begin", mock.Anything, "u1", "k1", mock.AnythingOfType("string")).Return(nil, nil)
+		service.On("Complete", mock.Anything, mock.MatchedBy(func(req *entities.IdempotentRequest) bool {
+			return req.UserID == "u1" && req.Key == "k1" && req.Status == http.StatusCreated &&
+				string(req.Body) == `{"id":7}` && strings.HasPrefix(req.ContentType, "application/json") &&
+				req.Headers["ETag"] == `"1"` && len(req.Headers) == 1
+		})).Return(nil)
+
+		w, calls := serveIdempotent(service, "k1", `{"title":"BMW"}`, http.StatusCreated)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, 1, calls)
+		assert.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+	})
+
+	t.Run("retry is replayed", func(t *testing.T) {
+		service := new(idempotency.MockIdempotencyService)
+		defer service.AssertExpectations(t)
+
+		service.On("Begin", mock.Anything, "u1", "k1", mock.AnythingOfType("string")).
+			Return(&entities.IdempotentRequest{Status: http.StatusCreated, ContentType: "application/json",
+				Body: []byte(`{"id":7}`), Headers: map[string]string{"ETag": `"1"`}}, nil)
+
+		w, calls := serveIdempotent(service, "k1", `{"title":"BMW"}`, http.StatusCreated)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, 0, calls)
+		assert.Equal(t, `{"id":7}`, w.Body.String())
+		assert.Equal(t, "true", w.Header().Get(IdempotentReplayedHeader))
+		assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("key reused with another body", func(t *testing.T) {
+		service := new(idempotency.MockIdempotencyService)
+		service.On("Begin", mock.Anything, "u1", "k1", mock.AnythingOfType("string")).
+			Return(nil, usecaseerr.ErrIdempotencyKeyReused)
+
+		w, calls := serveIdempotent(service, "k1", `{"title":"Audi"}`, http.StatusCreated)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, 0, calls)
+		assert.Contains(t, w.Body.String(), `"code":"idempotency_key_reused"`)
+	})
+
+	t.Run("failed request releases the key", func(t *testing.T) {
+		service := new(idempotency.MockIdempotencyService)
+		defer service.AssertExpectations(t)
+
+		service.On("Begin", mock.Anything, "u1", "k1", mock.AnythingOfType("string")).Return(nil, nil)
+		service.On("Release", mock.Anything, "u1", "k1").Return(nil)
+
+		w, calls := serveIdempotent(service, "k1", `{}`, http.StatusBadRequest)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		service := new(idempotency.MockIdempotencyService)
+		defer service.AssertExpectations(t)
+
+		w, calls := serveIdempotent(service, "k1", strings.Repeat("a", 65), http.StatusCreated)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Equal(t, 0, calls)
+		assert.Contains(t, w.Body.String(), `"code":"body_too_large"`)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		service := new(idempotency.MockIdempotencyService)
+		defer service.AssertExpectations(t)
+
+		w, calls := serveIdempotent(service, strings.Repeat("k", 256), `{}`, http.StatusCreated)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, 0, calls)
+		assert.Contains(t, w.Body.String(), `"field":"Idempotency-Key"`)
+	})
+}
+
+func TestRequestFingerprint(t *testing.T) {
+	multipartRequest := func(fileContent string) *http.Request {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, _ := writer.CreateFormFile("file", "car.jpg")
+		_, _ = part.Write([]byte(fileContent))
+		_ = writer.Close()
+		req := httptest.NewRequest(http.MethodPost, "/ads/1/image", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		return req
+	}
+	fingerprint := func(req *http.Request) string {
+		body := &bytes.Buffer{}
+		_, _ = body.ReadFrom(req.Body)
+		return requestFingerprint(req, body.Bytes())
+	}
+
+	// every attempt gets a new random boundary
+	assert.Equal(t, fingerprint(multipartRequest("jpeg")), fingerprint(multipartRequest("jpeg")))
+	assert.NotEqual(t, fingerprint(multipartRequest("jpeg")), fingerprint(multipartRequest("png")))
+
+	other := httptest.NewRequest(http.MethodPost, "/ads/2/image", strings.NewReader(""))
+	same := httptest.NewRequest(http.MethodPost, "/ads/1/image", strings.NewReader(""))
+	assert.NotEqual(t, fingerprint(other), fingerprint(same))
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatisticsFilter reads the from, to and granularity query parameters of the statistics.
+func StatisticsFilter(c *gin.Context) (entities.StatisticsFilter, error) {
+	from, to, err := Period(c)
+	if err != nil {
+		return entities.StatisticsFilter{}, err
+	}
+	return entities.StatisticsFilter{From: from, To: to, Granularity: entities.Granularity(c.Query("granularity"))},
+		nil
+}
+
+// Period reads the from and to days, both in YYYY-MM-DD. To is included, so it is returned as the start of
+// the next day.
+func Period(c *gin.Context) (from, to time.Time, err error) {
+	if raw := c.Query("from"); raw != "" {
+		if from, err = time.Parse(time.DateOnly, raw); err != nil {
+			return time.Time{}, time.Time{}, errs.Invalid("from", "invalid from date, expected YYYY-MM-DD")
+		}
+	}
+	if raw := c.Query("to"); raw != "" {
+		if to, err = time.Parse(time.DateOnly, raw); err != nil {
+			return time.Time{}, time.Time{}, errs.Invalid("to", "invalid to date, expected YYYY-MM-DD")
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+	return from, to, nil
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatisticsFilter(t *testing.T) {
+	for name, tc := range map[string]struct {
+		query  string
+		filter entities.StatisticsFilter
+		field  string
+	}{
+		"defaults": {"", entities.StatisticsFilter{}, ""},
+		"period": {"from=2026-03-01&to=2026-03-31&granularity=week", entities.StatisticsFilter{
+			From:        time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			To:          time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+			Granularity: entities.Granularity("week"),
+		}, ""},
+		"invalid from": {"from=01.03.2026", entities.StatisticsFilter{}, "from"},
+		"invalid to":   {"to=yesterday", entities.StatisticsFilter{}, "to"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/admin/stats?"+tc.query, nil)
+
+			filter, err := StatisticsFilter(c)
+			assert.Equal(t, tc.filter, filter)
+			if tc.field == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var e *errs.Error
+			if assert.ErrorAs(t, err, &e) {
+				assert.Equal(t, tc.field, e.Field())
+			}
+		})
+	}
+}
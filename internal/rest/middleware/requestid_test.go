@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	customLogger "ads-service/pkg/logger"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newRouter(seen *string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID(), Tracing())
+	router.GET("/ads/:id", func(c *gin.Context) {
+		*seen = customLogger.RequestID(c.Request.Context())
+		c.Status(http.StatusInternalServerError)
+	})
+	return router
+}
+
+func TestRequestID(t *testing.T) {
+	var seen string
+	router := newRouter(&seen)
+
+	t.Run("accepted", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/ads/7", nil)
+		req.Header.Set(RequestIDHeader, "client-req.1")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, "client-req.1", w.Header().Get(RequestIDHeader))
+		assert.Equal(t, "client-req.1", seen)
+	})
+
+	for name, header := range map[string]string{"missing": "", "invalid": "bad id\n", "long": strings.Repeat("a", 129)} {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/ads/7", nil)
+			req.Header.Set(RequestIDHeader, header)
+			router.ServeHTTP(w, req)
+
+			id := w.Header().Get(RequestIDHeader)
+			assert.Len(t, id, 32)
+			assert.Equal(t, id, seen)
+		})
+	}
+}
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	var seen string
+	router := newRouter(&seen)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ads/7", nil))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /ads/:id", spans[0].Name())
+	assert.Equal(t, "Error", spans[0].Status().Code.String())
+	attrs := map[string]string{}
+	for _, attr := range spans[0].Attributes() {
+		attrs[string(attr.Key)] = attr.Value.Emit()
+	}
+	assert.Equal(t, seen, attrs["request_id"])
+	assert.Equal(t, "500", attrs["http.response.status_code"])
+}
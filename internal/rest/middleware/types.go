@@ -1,18 +1,38 @@
 package middleware
 
 import (
+	"ads-service/internal/usecase/apikey"
 	"ads-service/internal/usecase/auth"
+	"ads-service/internal/usecase/idempotency"
 	"ads-service/internal/usecase/user"
+	"ads-service/pkg/jwtkeys"
 )
 
+// Config - AdminMFARequired rejects admin endpoints for sessions opened without 2FA.
+// IdempotencyMaxBody is the largest body in bytes of a request sent with an Idempotency-Key.
+type Config struct {
+	AdminMFARequired   bool
+	IdempotencyMaxBody int64
+}
+
 type Middleware struct {
-	authService auth.AuthService
-	userService user.UserAdvertisementService
+	authService        auth.AuthService
+	userService        user.UserAdvertisementService
+	apiKeyService      apikey.APIKeyService
+	idempotencyService idempotency.IdempotencyService
+	keys               *jwtkeys.KeySet
+	config             Config
 }
 
-func NewMiddleware(authService auth.AuthService, userService user.UserAdvertisementService) *Middleware {
+func NewMiddleware(authService auth.AuthService, userService user.UserAdvertisementService,
+	apiKeyService apikey.APIKeyService, idempotencyService idempotency.IdempotencyService,
+	keys *jwtkeys.KeySet, config Config) *Middleware {
 	return &Middleware{
-		authService: authService,
-		userService: userService,
+		authService:        authService,
+		userService:        userService,
+		apiKeyService:      apiKeyService,
+		idempotencyService: idempotencyService,
+		keys:               keys,
+		config:             config,
 	}
 }
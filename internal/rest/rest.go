@@ -14,7 +14,22 @@ package rest
 
 import (
 	"ads-service/internal/rest/handlers/admin"
+	"ads-service/internal/rest/handlers/apikey"
+	"ads-service/internal/rest/handlers/appeal"
+	"ads-service/internal/rest/handlers/duplicate"
+	"ads-service/internal/rest/handlers/events"
+	"ads-service/internal/rest/handlers/export"
+	"ads-service/internal/rest/handlers/favorite"
+	"ads-service/internal/rest/handlers/health"
+	"ads-service/internal/rest/handlers/message"
+	"ads-service/internal/rest/handlers/notification"
+	"ads-service/internal/rest/handlers/premoderation"
+	"ads-service/internal/rest/handlers/rejection"
+	"ads-service/internal/rest/handlers/report"
+	"ads-service/internal/rest/handlers/savedsearch"
+	"ads-service/internal/rest/handlers/twofactor"
 	"ads-service/internal/rest/handlers/user"
+	"fmt"
 	"net/http"
 
 	authHandle "ads-service/internal/rest/handlers/auth"
@@ -26,27 +41,77 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// Config - TrustedProxies are the addresses or CIDRs of the reverse proxies in front of the service.
+// The client IP, which login limits are keyed on, is taken from X-Forwarded-For only behind them,
+// with none the address of the connection is used.
+type Config struct {
+	TrustedProxies []string
+}
+
 type Server struct {
-	mux          *gin.Engine
-	authHandler  *authHandle.AuthHandler
-	adminHandler *admin.AdminHandler
-	userHandler  *user.UserHandler
-	mv           *middleware.Middleware
+	mux              *gin.Engine
+	authHandler      *authHandle.AuthHandler
+	adminHandler     *admin.AdminHandler
+	userHandler      *user.UserHandler
+	twoFactorHandler *twofactor.TwoFactorHandler
+	apiKeyHandler    *apikey.APIKeyHandler
+	favoriteHandler  *favorite.FavoriteHandler
+	searchHandler    *savedsearch.SavedSearchHandler
+	messageHandler   *message.MessageHandler
+	eventsHandler    *events.EventsHandler
+	notifyHandler    *notification.NotificationHandler
+	reportHandler    *report.ReportHandler
+	rulesHandler     *premoderation.PreModerationHandler
+	duplicateHandler *duplicate.DuplicateHandler
+	reasonsHandler   *rejection.RejectionHandler
+	appealHandler    *appeal.AppealHandler
+	exportHandler    *export.ExportHandler
+	healthHandler    *health.HealthHandler
+	mv               *middleware.Middleware
 }
 
 func NewServer(mux *gin.Engine, authHandler *authHandle.AuthHandler, mv *middleware.Middleware,
-	adminHandler *admin.AdminHandler, userHandler *user.UserHandler) *Server {
+	adminHandler *admin.AdminHandler, userHandler *user.UserHandler,
+	twoFactorHandler *twofactor.TwoFactorHandler, apiKeyHandler *apikey.APIKeyHandler,
+	favoriteHandler *favorite.FavoriteHandler, searchHandler *savedsearch.SavedSearchHandler,
+	messageHandler *message.MessageHandler, eventsHandler *events.EventsHandler,
+	notifyHandler *notification.NotificationHandler, reportHandler *report.ReportHandler,
+	rulesHandler *premoderation.PreModerationHandler, duplicateHandler *duplicate.DuplicateHandler,
+	reasonsHandler *rejection.RejectionHandler, appealHandler *appeal.AppealHandler,
+	exportHandler *export.ExportHandler, healthHandler *health.HealthHandler, config Config) (*Server, error) {
+	if err := mux.SetTrustedProxies(config.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("failed to set trusted proxies: %w", err)
+	}
+	mux.Use(middleware.RequestID())
+	mux.Use(middleware.Tracing())
+	// outside of Recovery, so requests recovered from a panic are counted as 500
+	mux.Use(middleware.Metrics())
 	mux.Use(gin.Recovery())
 	mux.Use(gin.Logger())
+	// innermost, so the metrics and the access log see the status of the rendered problem
+	mux.Use(middleware.Errors())
 
 	return &Server{
-		mux:          mux,
-		authHandler:  authHandler,
-		adminHandler: adminHandler,
-		userHandler:  userHandler,
-		mv:           mv,
-	}
-
+		mux:              mux,
+		authHandler:      authHandler,
+		adminHandler:     adminHandler,
+		userHandler:      userHandler,
+		twoFactorHandler: twoFactorHandler,
+		apiKeyHandler:    apiKeyHandler,
+		favoriteHandler:  favoriteHandler,
+		searchHandler:    searchHandler,
+		messageHandler:   messageHandler,
+		eventsHandler:    eventsHandler,
+		notifyHandler:    notifyHandler,
+		reportHandler:    reportHandler,
+		rulesHandler:     rulesHandler,
+		duplicateHandler: duplicateHandler,
+		reasonsHandler:   reasonsHandler,
+		appealHandler:    appealHandler,
+		exportHandler:    exportHandler,
+		healthHandler:    healthHandler,
+		mv:               mv,
+	}, nil
 }
 
 func (s *Server) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
@@ -55,6 +120,9 @@ func (s *Server) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 
 func (s *Server) Init() {
 	s.mux.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	s.mux.GET("/.well-known/jwks.json", s.authHandler.JWKS)
+	s.mux.GET("/healthz", s.healthHandler.Healthz)
+	s.mux.GET("/readyz", s.healthHandler.Readyz)
 
 	const basePath = "/api/v1"
 	baseGroup := s.mux.Group(basePath)
@@ -63,12 +131,30 @@ func (s *Server) Init() {
 	authGroup := baseGroup.Group("/auth")
 	authGroup.POST("/register", s.authHandler.Register)
 	authGroup.POST("/login", s.authHandler.Login)
+	authGroup.POST("/login/2fa", s.authHandler.LoginMFA)
+
+	// Двухфакторная аутентификация. Ответы с секретами, ключами и кодами восстановления
+	// не должны сохраняться, поэтому Idempotency здесь и в /api-keys не подключается
+	twoFactorGroup := baseGroup.Group("/2fa")
+	twoFactorGroup.Use(s.mv.UserAuth())
+	twoFactorGroup.POST("/enroll", s.twoFactorHandler.Enroll)
+	twoFactorGroup.POST("/confirm", s.twoFactorHandler.Confirm)
+	twoFactorGroup.POST("/disable", s.twoFactorHandler.Disable)
+	twoFactorGroup.POST("/recovery-codes", s.twoFactorHandler.RegenerateRecoveryCodes)
+
+	// API-ключи для интеграций, управляются только с JWT
+	apiKeyGroup := baseGroup.Group("/api-keys")
+	apiKeyGroup.Use(s.mv.UserAuth())
+	apiKeyGroup.POST("", s.apiKeyHandler.CreateAPIKey)
+	apiKeyGroup.GET("", s.apiKeyHandler.GetAPIKeys)
+	apiKeyGroup.DELETE("/:id", s.apiKeyHandler.RevokeAPIKey)
 
 	// Пользовательские маршруты
 	userGroup := baseGroup.Group("/ads")
-	userGroup.Use(s.mv.UserAuth())
+	userGroup.Use(s.mv.UserOrAPIKeyAuth(), s.mv.Idempotency())
 	userGroup.POST("/create", s.userHandler.CreateDraft)
 	userGroup.GET("/my", s.userHandler.GetMyAds)
+	userGroup.GET("/:id", s.userHandler.GetMyAd)
 	userGroup.PUT("/:id", s.userHandler.UpdateMyAd)
 	userGroup.DELETE("/:id", s.userHandler.DeleteMyAd)
 	userGroup.POST("/:id/submit", s.userHandler.SubmitForModeration)
@@ -76,13 +162,71 @@ func (s *Server) Init() {
 	userGroup.GET("/:id/image", s.userHandler.GetImagesToMyAd)
 	userGroup.DELETE("/:id/image/:fid", s.userHandler.DeleteMyAdImage)
 	userGroup.GET("/filter", s.userHandler.GetMyAdsByFilter)
+	userGroup.POST("/:id/report", s.reportHandler.ReportAd)
+	userGroup.POST("/:id/appeal", s.appealHandler.Appeal)
+	userGroup.GET("/:id/appeals", s.appealHandler.GetAppeals)
+
+	// Избранное покупателя
+	favoriteGroup := baseGroup.Group("/favorites")
+	favoriteGroup.Use(s.mv.UserAuth(), s.mv.Idempotency())
+	favoriteGroup.GET("", s.favoriteHandler.GetFavorites)
+	favoriteGroup.POST("/:adID", s.favoriteHandler.AddFavorite)
+	favoriteGroup.DELETE("/:adID", s.favoriteHandler.RemoveFavorite)
+
+	// Сохранённые поиски
+	searchGroup := baseGroup.Group("/saved-searches")
+	searchGroup.Use(s.mv.UserAuth(), s.mv.Idempotency())
+	searchGroup.POST("", s.searchHandler.CreateSavedSearch)
+	searchGroup.GET("", s.searchHandler.GetSavedSearches)
+	searchGroup.DELETE("/:id", s.searchHandler.DeleteSavedSearch)
+
+	// Переписка покупателя и продавца
+	conversationGroup := baseGroup.Group("/conversations")
+	conversationGroup.Use(s.mv.UserAuth(), s.mv.Idempotency())
+	conversationGroup.POST("", s.messageHandler.StartConversation)
+	conversationGroup.GET("", s.messageHandler.GetConversations)
+	conversationGroup.GET("/unread", s.messageHandler.GetUnreadCount)
+	conversationGroup.GET("/:id/messages", s.messageHandler.GetMessages)
+	conversationGroup.POST("/:id/messages", s.messageHandler.SendMessage)
+	conversationGroup.POST("/:id/block", s.messageHandler.BlockBuyer)
+	conversationGroup.DELETE("/:id/block", s.messageHandler.UnblockBuyer)
+
+	// Поток событий в реальном времени (SSE)
+	eventsGroup := baseGroup.Group("/events")
+	eventsGroup.Use(s.mv.UserAuth())
+	eventsGroup.GET("", s.eventsHandler.Stream)
+
+	// Центр уведомлений
+	notificationGroup := baseGroup.Group("/notifications")
+	notificationGroup.Use(s.mv.UserAuth(), s.mv.Idempotency())
+	notificationGroup.GET("", s.notifyHandler.GetNotifications)
+	notificationGroup.POST("/:id/read", s.notifyHandler.MarkRead)
+	notificationGroup.POST("/read-all", s.notifyHandler.MarkAllRead)
+	notificationGroup.GET("/preferences", s.notifyHandler.GetPreferences)
+	notificationGroup.PUT("/preferences", s.notifyHandler.UpdatePreferences)
 
 	// Админские маршруты
 	adminGroup := baseGroup.Group("/admin")
-	adminGroup.Use(s.mv.AdminAuth())
+	adminGroup.Use(s.mv.AdminAuth(), s.mv.Idempotency())
 	adminGroup.GET("/ads", s.adminHandler.GetAllAds)
+	adminGroup.GET("/ads/:id", s.adminHandler.GetAd)
 	adminGroup.GET("/stats", s.adminHandler.GetStatistics)
 	adminGroup.DELETE("/ads/:id", s.adminHandler.DeleteAd)
 	adminGroup.POST("/ads/:id/approve", s.adminHandler.Approve)
 	adminGroup.POST("/ads/:id/reject", s.adminHandler.Reject)
+	adminGroup.GET("/ads/:id/rule-hits", s.rulesHandler.GetRuleHits)
+	adminGroup.GET("/ads/:id/duplicates", s.duplicateHandler.GetDuplicates)
+	adminGroup.GET("/ads/:id/history", s.adminHandler.GetHistory)
+	adminGroup.GET("/rejection-templates", s.reasonsHandler.GetTemplates)
+	adminGroup.POST("/rejection-templates", s.reasonsHandler.CreateTemplate)
+	adminGroup.PUT("/rejection-templates/:code", s.reasonsHandler.UpdateTemplate)
+	adminGroup.GET("/reports", s.reportHandler.GetReports)
+	adminGroup.POST("/reports/:id/resolve", s.reportHandler.ResolveReport)
+	adminGroup.GET("/appeals", s.appealHandler.GetPendingAppeals)
+	adminGroup.POST("/appeals/:id/review", s.appealHandler.ReviewAppeal)
+	adminGroup.GET("/export/ads", s.exportHandler.ExportAds)
+	adminGroup.GET("/export/decisions", s.exportHandler.ExportDecisions)
+	adminGroup.GET("/export/stats", s.exportHandler.ExportStatistics)
+	adminGroup.GET("/lockouts", s.authHandler.GetLockouts)
+	adminGroup.DELETE("/lockouts/:kind/:key", s.authHandler.ClearLockout)
 }
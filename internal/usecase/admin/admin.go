@@ -2,12 +2,25 @@ package admin
 
 import (
 	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs"
+	"ads-service/internal/errs/repoerr"
 	"ads-service/internal/errs/usecaseerr"
+	"ads-service/pkg/db"
+	"ads-service/pkg/tracing"
 	"context"
+	"errors"
+	"fmt"
 	"time"
+	"unicode/utf8"
 )
 
-func (s *service) GetAllAds(ctx context.Context) ([]entities.Ad, error) {
+// MaxRejectionNoteLength - longest note of a rejection, in runes.
+const MaxRejectionNoteLength = 1000
+
+func (s *service) GetAllAds(ctx context.Context) (_ []entities.Ad, err error) {
+	ctx, span := tracing.Start(ctx, "admin.GetAllAds")
+	defer tracing.End(span, &err)
+
 	ads, err := s.adRepo.GetAll(ctx)
 	if err != nil {
 		s.logger.ERROR("error getting all ads:", err)
@@ -21,13 +34,28 @@ func (s *service) GetAllAds(ctx context.Context) ([]entities.Ad, error) {
 	return ads, nil
 }
 
-func (s *service) DeleteAd(ctx context.Context, adID int) error {
+func (s *service) GetAd(ctx context.Context, adID int) (_ *entities.Ad, err error) {
+	ctx, span := tracing.Start(ctx, "admin.GetAd")
+	defer tracing.End(span, &err)
+
+	ad, err := s.adRepo.GetByID(ctx, adID)
+	if err != nil {
+		s.logger.ERROR("error getting ad:", err)
+		return nil, usecaseerr.AdLookup(err)
+	}
+	return ad, nil
+}
+
+func (s *service) DeleteAd(ctx context.Context, adID int) (err error) {
+	ctx, span := tracing.Start(ctx, "admin.DeleteAd")
+	defer tracing.End(span, &err)
+
 	if adID <= 0 {
 		s.logger.ERROR("invalid ad ID")
 		return usecaseerr.ErrInvalidParams
 	}
 
-	err := s.adRepo.Delete(ctx, adID)
+	err = s.adRepo.Delete(ctx, adID)
 	if err != nil {
 		s.logger.ERROR("error deleting ad:", err)
 		return usecaseerr.ErrDeletingAd
@@ -51,58 +79,159 @@ func (s *service) DeleteAd(ctx context.Context, adID int) error {
 		return nil
 	}
 */
-func (s *service) Approve(ctx context.Context, adID int) error {
+func (s *service) Approve(ctx context.Context, adID, version int, moderatorID string) (err error) {
+	ctx, span := tracing.Start(ctx, "admin.Approve")
+	defer tracing.End(span, &err)
+
 	repoAd, err := s.adRepo.GetByID(ctx, adID)
 	if err != nil {
 		s.logger.ERROR("error getting ad:", err)
-		return usecaseerr.ErrGettingAdByID
+		return usecaseerr.AdLookup(err)
 	}
 	if repoAd == nil {
-		s.logger.ERROR("no ad found ", usecaseerr.ErrNoAds)
-		return usecaseerr.ErrGettingAdByID
+		return usecaseerr.ErrAdNotFound
+	}
+	if version != 0 && version != repoAd.Version {
+		return errs.NewStale(usecaseerr.ErrAdModified, repoAd, repoAd.Version)
 	}
 
 	repoAd.Status = entities.StatusApproved
 	repoAd.IsActive = true
 	repoAd.UpdatedAt = time.Now().UTC()
 
-	if err = s.adRepo.Approve(ctx, adID, repoAd); err != nil {
-		s.logger.ERROR("error approving ad:", err)
-		return usecaseerr.ErrApprovingAd
+	// the event is saved with the decision, statistics count it and appeals take the moderator from it
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.adRepo.Approve(ctx, adID, repoAd); err != nil {
+			s.logger.ERROR("error approving ad:", err)
+			return usecaseerr.AdWrite(ctx, adID, err, usecaseerr.ErrApprovingAd, s.adRepo.GetByID)
+		}
+		return s.record(ctx, &entities.ModerationEvent{AdID: adID, Action: entities.ActionApproved,
+			ActorID: moderatorID}, usecaseerr.ErrApprovingAd)
+	})
+	if err != nil {
+		return err
 	}
+	// an approval made as part of a larger unit of work, such as an appeal, is announced once it commits
+	db.AfterCommit(ctx, func(ctx context.Context) {
+		// the ad is already published, a failed alert must not roll the approval back
+		if err := s.savedSearches.NotifyMatches(ctx, repoAd); err != nil {
+			s.logger.ERROR("error notifying saved searches:", err)
+		}
+		s.announceDecision(ctx, repoAd, entities.EventAdApproved)
+	})
 	s.logger.INFO("ad approved successfully")
 	return nil
 }
 
-func (s *service) Reject(ctx context.Context, adID int, reason string) error {
+func (s *service) Reject(ctx context.Context, adID, version int, moderatorID string,
+	rejection entities.Rejection) (err error) {
+	ctx, span := tracing.Start(ctx, "admin.Reject")
+	defer tracing.End(span, &err)
+
+	if utf8.RuneCountInString(rejection.Note) > MaxRejectionNoteLength {
+		return usecaseerr.ErrInvalidRejectionNote
+	}
+	template, err := s.rejections.GetTemplate(ctx, rejection.Code)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrRejectionTemplateNotFound) {
+			return usecaseerr.ErrUnknownRejectionCode
+		}
+		s.logger.ERROR("error getting rejection template:", err)
+		return usecaseerr.ErrRejectingAd
+	}
+	if !template.IsActive {
+		return usecaseerr.ErrRejectionTemplateInactive
+	}
+	rejection.Texts = template.Texts
+
 	repoAd, err := s.adRepo.GetByID(ctx, adID)
 	if err != nil {
 		s.logger.ERROR("error getting ad:", err)
-		return usecaseerr.ErrGettingAdByID
+		return usecaseerr.AdLookup(err)
 	}
 	if repoAd == nil {
-		s.logger.ERROR("no ad found ", usecaseerr.ErrGettingAdByID)
-		return usecaseerr.ErrGettingAdByID
+		return usecaseerr.ErrAdNotFound
+	}
+	if version != 0 && version != repoAd.Version {
+		return errs.NewStale(usecaseerr.ErrAdModified, repoAd, repoAd.Version)
 	}
 
 	repoAd.Status = entities.StatusRejected
 	repoAd.IsActive = false
 	repoAd.UpdatedAt = time.Now().UTC()
-	repoAd.RejectionReason = reason
+	repoAd.Rejection = &rejection
+	repoAd.RejectionReason = rejection.Reason()
 
-	if err = s.adRepo.Reject(ctx, adID, repoAd); err != nil {
-		s.logger.ERROR("error rejecting ad:", err)
-		return usecaseerr.ErrRejectingAd
+	// an appeal of the rejection goes to another moderator than the one recorded here
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.adRepo.Reject(ctx, adID, repoAd); err != nil {
+			s.logger.ERROR("error rejecting ad:", err)
+			return usecaseerr.AdWrite(ctx, adID, err, usecaseerr.ErrRejectingAd, s.adRepo.GetByID)
+		}
+		return s.record(ctx, &entities.ModerationEvent{
+			AdID:          adID,
+			Action:        entities.ActionRejected,
+			ActorID:       moderatorID,
+			RejectionCode: rejection.Code,
+			Note:          rejection.Note,
+		}, usecaseerr.ErrRejectingAd)
+	})
+	if err != nil {
+		return err
 	}
+	db.AfterCommit(ctx, func(ctx context.Context) {
+		s.announceDecision(ctx, repoAd, entities.EventAdRejected)
+	})
 	s.logger.INFO("ad rejected successfully")
 	return nil
 }
 
-func (s *service) GetStatistics(ctx context.Context) (entities.AdStatistics, error) {
-	statistics, err := s.adRepo.GetStatistics(ctx)
+// record adds the decision to the moderation history, a failure is returned as failed and undoes the decision.
+func (s *service) record(ctx context.Context, event *entities.ModerationEvent, failed error) error {
+	if err := s.history.Record(ctx, event); err != nil {
+		s.logger.ERROR("error recording moderation event:", err)
+		return fmt.Errorf("%w: %w", failed, err)
+	}
+	return nil
+}
+
+func (s *service) GetHistory(ctx context.Context, adID int) (_ []entities.ModerationEvent, err error) {
+	ctx, span := tracing.Start(ctx, "admin.GetHistory")
+	defer tracing.End(span, &err)
+
+	if adID <= 0 {
+		return nil, usecaseerr.ErrInvalidParams
+	}
+	events, err := s.history.GetByAd(ctx, adID)
 	if err != nil {
-		s.logger.ERROR("error getting statistics:", err)
-		return entities.AdStatistics{}, usecaseerr.ErrGettingStatistics
+		s.logger.ERROR("error getting moderation history:", err)
+		return nil, usecaseerr.ErrGettingAdHistory
+	}
+	return events, nil
+}
+
+// announceDecision lets the author know the moderation result without polling their ads.
+// The decision is already saved, so a failed notification is only logged.
+func (s *service) announceDecision(ctx context.Context, ad *entities.Ad, eventType entities.EventType) {
+	data := entities.EventData{
+		"ad_id":  ad.ID,
+		"title":  ad.Title,
+		"status": ad.Status,
+	}
+	n := entities.Notification{
+		Kind:    entities.NotificationModeration,
+		UserID:  ad.AuthorID,
+		Subject: fmt.Sprintf("Your ad %q is published", ad.Title),
+	}
+	if ad.Status == entities.StatusRejected {
+		data["reason"] = ad.RejectionReason
+		data["code"] = ad.Rejection.Code
+		n.Subject = fmt.Sprintf("Your ad %q was rejected", ad.Title)
+		n.Body = "Reason: " + ad.RejectionReason
+	}
+	s.events.Publish(ctx, entities.Event{Type: eventType, UserID: ad.AuthorID, Data: data})
+
+	if err := s.notifier.Notify(ctx, n); err != nil {
+		s.logger.ERROR("error notifying author about moderation:", err)
 	}
-	return statistics, nil
 }
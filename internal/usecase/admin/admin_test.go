@@ -2,16 +2,80 @@ package admin
 
 import (
 	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs"
 	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
 	"ads-service/internal/repository/ad"
+	"ads-service/internal/repository/history"
+	"ads-service/internal/repository/rejection"
+	"ads-service/internal/repository/statistics"
 	"ads-service/internal/repository/user"
+	"ads-service/internal/usecase/savedsearch"
+	"ads-service/pkg/db"
+	"ads-service/pkg/eventbus"
 	customLogger "ads-service/pkg/logger"
+	"ads-service/pkg/notifier"
 	"context"
+	"errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"strings"
 	"testing"
+	"time"
 )
 
+func quietSearches() *savedsearch.MockSavedSearchService {
+	searches := &savedsearch.MockSavedSearchService{}
+	searches.On("NotifyMatches", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return searches
+}
+
+func quietNotifier() *notifier.MockNotifier {
+	notify := &notifier.MockNotifier{}
+	notify.On("Notify", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return notify
+}
+
+// knownRejections has an active template for every code.
+func knownRejections() *rejection.MockRejectionRepo {
+	rejections := &rejection.MockRejectionRepo{}
+	rejections.On("GetTemplate", mock.Anything, mock.Anything).
+		Return(&entities.RejectionTemplate{Texts: map[string]string{"en": "Bad photos."}, IsActive: true}, nil).Maybe()
+	rejections.On("CountByCode", mock.Anything).Return(map[string]int{}, nil).Maybe()
+	return rejections
+}
+
+func quietHistory() *history.MockHistoryRepo {
+	m := &history.MockHistoryRepo{}
+	m.On("Record", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return m
+}
+
+func quietStatistics() *statistics.MockStatisticsRepo {
+	m := &statistics.MockStatisticsRepo{}
+	m.On("GetSummary", mock.Anything, mock.Anything).Return(entities.ModerationStatistics{}, nil).Maybe()
+	m.On("GetSeries", mock.Anything, mock.Anything).Return([]entities.StatisticsPoint{}, nil).Maybe()
+	m.On("GetCategories", mock.Anything, mock.Anything).Return([]entities.CategoryStatistics{}, nil).Maybe()
+	m.On("GetModerators", mock.Anything, mock.Anything).Return([]entities.ModeratorStatistics{}, nil).Maybe()
+	m.On("GetBacklogAge", mock.Anything).Return([]entities.BacklogBucket{}, nil).Maybe()
+	return m
+}
+
+// inTx runs the unit of work right away, the transaction itself is covered in the db package.
+func inTx() *db.MockTxManager {
+	m := &db.MockTxManager{}
+	m.On("WithinTx", mock.Anything).Return(nil).Maybe()
+	return m
+}
+
+func newTestService(adRepo *ad.MockAdRepo, userRepo *user.MockUserRepo) AdminAdvertisementService {
+	return NewAdminService(adRepo, userRepo, knownRejections(), quietHistory(), quietStatistics(), quietSearches(),
+		eventbus.New(), quietNotifier(), inTx(), customLogger.Logger{})
+}
+
 func TestMockAdminService_GetAllAds(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockRepo := ad.MockAdRepo{}
@@ -19,7 +83,7 @@ func TestMockAdminService_GetAllAds(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockUserRepo.AssertExpectations(t)
 
-		service := NewAdminService(&mockRepo, &mockUserRepo, customLogger.Logger{})
+		service := newTestService(&mockRepo, &mockUserRepo)
 		expectedAds := []entities.Ad{
 			{ID: 1, AuthorID: "1", Title: "ad1"},
 			{ID: 2, AuthorID: "1", Title: "ad2"},
@@ -38,7 +102,7 @@ func TestMockAdminService_GetAllAds(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockUserRepo.AssertExpectations(t)
 
-		service := NewAdminService(&mockRepo, &mockUserRepo, customLogger.Logger{})
+		service := newTestService(&mockRepo, &mockUserRepo)
 
 		mockRepo.On("GetAll", mock.Anything).
 			Return([]entities.Ad{}, repoerr.ErrSelection)
@@ -54,7 +118,7 @@ func TestMockAdminService_GetAllAds(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockUserRepo.AssertExpectations(t)
 
-		service := NewAdminService(&mockRepo, &mockUserRepo, customLogger.Logger{})
+		service := newTestService(&mockRepo, &mockUserRepo)
 
 		mockRepo.On("GetAll", mock.Anything).Return([]entities.Ad{}, nil)
 
@@ -71,7 +135,7 @@ func TestMockAdminService_DeleteAd(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockUserRepo.AssertExpectations(t)
 
-		service := NewAdminService(&mockRepo, &mockUserRepo, customLogger.Logger{})
+		service := newTestService(&mockRepo, &mockUserRepo)
 		mockRepo.On("Delete", mock.Anything, 1).Return(nil)
 
 		err := service.DeleteAd(context.Background(), 1)
@@ -81,7 +145,7 @@ func TestMockAdminService_DeleteAd(t *testing.T) {
 	t.Run("invalid ad id", func(t *testing.T) {
 		mockRepo := ad.MockAdRepo{}
 		mockUserRepo := user.MockUserRepo{}
-		service := NewAdminService(&mockRepo, &mockUserRepo, customLogger.Logger{})
+		service := newTestService(&mockRepo, &mockUserRepo)
 
 		err := service.DeleteAd(context.Background(), 0)
 		assert.Error(t, err)
@@ -91,7 +155,7 @@ func TestMockAdminService_DeleteAd(t *testing.T) {
 		mockRepo := ad.MockAdRepo{}
 		mockUserRepo := user.MockUserRepo{}
 		defer mockRepo.AssertExpectations(t)
-		service := NewAdminService(&mockRepo, &mockUserRepo, customLogger.Logger{})
+		service := newTestService(&mockRepo, &mockUserRepo)
 
 		mockRepo.On("Delete", mock.Anything, 2).Return(assert.AnError)
 
@@ -105,13 +169,34 @@ func TestMockAdminService_Approve(t *testing.T) {
 		mockRepo := ad.MockAdRepo{}
 		mockUserRepo := user.MockUserRepo{}
 		defer mockRepo.AssertExpectations(t)
-		service := NewAdminService(&mockRepo, &mockUserRepo, customLogger.Logger{})
+		service := newTestService(&mockRepo, &mockUserRepo)
 
 		adEntity := &entities.Ad{ID: 1}
 		mockRepo.On("GetByID", mock.Anything, 1).Return(adEntity, nil)
 		mockRepo.On("Approve", mock.Anything, 1, mock.AnythingOfType("*entities.Ad")).Return(nil)
 
-		err := service.Approve(context.Background(), 1)
+		err := service.Approve(context.Background(), 1, 0, "admin")
+		assert.NoError(t, err)
+	})
+
+	t.Run("published ad is matched against saved searches", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		mockUserRepo := user.MockUserRepo{}
+		searches := &savedsearch.MockSavedSearchService{}
+		defer mockRepo.AssertExpectations(t)
+		defer searches.AssertExpectations(t)
+		service := NewAdminService(&mockRepo, &mockUserRepo, knownRejections(), quietHistory(), quietStatistics(), searches,
+			eventbus.New(), quietNotifier(), inTx(), customLogger.Logger{})
+
+		adEntity := &entities.Ad{ID: 5, Title: "BMW"}
+		mockRepo.On("GetByID", mock.Anything, 5).Return(adEntity, nil)
+		mockRepo.On("Approve", mock.Anything, 5, mock.AnythingOfType("*entities.Ad")).Return(nil)
+		searches.On("NotifyMatches", mock.Anything, mock.MatchedBy(func(a *entities.Ad) bool {
+			return a.ID == 5 && a.Status == entities.StatusApproved
+		})).Return(assert.AnError)
+
+		// a failed alert does not fail the approval
+		err := service.Approve(context.Background(), 5, 0, "admin")
 		assert.NoError(t, err)
 	})
 
@@ -119,53 +204,136 @@ func TestMockAdminService_Approve(t *testing.T) {
 		mockRepo := ad.MockAdRepo{}
 		mockUserRepo := user.MockUserRepo{}
 		defer mockRepo.AssertExpectations(t)
-		service := NewAdminService(&mockRepo, &mockUserRepo, customLogger.Logger{})
+		service := newTestService(&mockRepo, &mockUserRepo)
 
-		mockRepo.On("GetByID", mock.Anything, 2).Return(nil, nil)
+		mockRepo.On("GetByID", mock.Anything, 2).Return(nil, repoerr.ErrAdNotFound)
 
-		err := service.Approve(context.Background(), 2)
-		assert.Error(t, err)
+		err := service.Approve(context.Background(), 2, 0, "admin")
+		assert.ErrorIs(t, err, usecaseerr.ErrAdNotFound)
 	})
 
 	t.Run("get by id error", func(t *testing.T) {
 		mockRepo := ad.MockAdRepo{}
 		mockUserRepo := user.MockUserRepo{}
 		defer mockRepo.AssertExpectations(t)
-		service := NewAdminService(&mockRepo, &mockUserRepo, customLogger.Logger{})
+		service := newTestService(&mockRepo, &mockUserRepo)
 
 		mockRepo.On("GetByID", mock.Anything, 3).Return(nil, assert.AnError)
 
-		err := service.Approve(context.Background(), 3)
-		assert.Error(t, err)
+		err := service.Approve(context.Background(), 3, 0, "admin")
+		assert.ErrorIs(t, err, usecaseerr.ErrGettingAdByID)
+		assert.ErrorIs(t, err, assert.AnError)
 	})
 
 	t.Run("approve error", func(t *testing.T) {
 		mockRepo := ad.MockAdRepo{}
 		mockUserRepo := user.MockUserRepo{}
 		defer mockRepo.AssertExpectations(t)
-		service := NewAdminService(&mockRepo, &mockUserRepo, customLogger.Logger{})
+		service := newTestService(&mockRepo, &mockUserRepo)
 
 		adEntity := &entities.Ad{ID: 4}
 		mockRepo.On("GetByID", mock.Anything, 4).Return(adEntity, nil)
 		mockRepo.On("Approve", mock.Anything, 4, mock.AnythingOfType("*entities.Ad")).Return(assert.AnError)
 
-		err := service.Approve(context.Background(), 4)
+		err := service.Approve(context.Background(), 4, 0, "admin")
 		assert.Error(t, err)
 	})
+
+	t.Run("stale version", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		mockUserRepo := user.MockUserRepo{}
+		defer mockRepo.AssertExpectations(t)
+		service := newTestService(&mockRepo, &mockUserRepo)
+
+		mockRepo.On("GetByID", mock.Anything, 6).Return(&entities.Ad{ID: 6, Version: 3}, nil)
+
+		err := service.Approve(context.Background(), 6, 2, "admin")
+		var stale *errs.StaleError
+		assert.ErrorAs(t, err, &stale)
+		assert.ErrorIs(t, err, usecaseerr.ErrAdModified)
+		assert.Equal(t, 3, stale.Version)
+		mockRepo.AssertNotCalled(t, "Approve", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("modified between read and write", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		mockUserRepo := user.MockUserRepo{}
+		defer mockRepo.AssertExpectations(t)
+		service := newTestService(&mockRepo, &mockUserRepo)
+
+		mockRepo.On("GetByID", mock.Anything, 7).Return(&entities.Ad{ID: 7, Version: 2}, nil).Once()
+		mockRepo.On("Approve", mock.Anything, 7, mock.AnythingOfType("*entities.Ad")).Return(repoerr.ErrAdModified)
+		mockRepo.On("GetByID", mock.Anything, 7).Return(&entities.Ad{ID: 7, Version: 3}, nil).Once()
+
+		err := service.Approve(context.Background(), 7, 2, "admin")
+		var stale *errs.StaleError
+		assert.ErrorAs(t, err, &stale)
+		assert.Equal(t, 3, stale.Version)
+	})
+}
+
+func TestMockAdminService_GetAd(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		service := newTestService(&mockRepo, &user.MockUserRepo{})
+		mockRepo.On("GetByID", mock.Anything, 1).Return(&entities.Ad{ID: 1, Version: 4}, nil)
+
+		got, err := service.GetAd(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, got.Version)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		service := newTestService(&mockRepo, &user.MockUserRepo{})
+		mockRepo.On("GetByID", mock.Anything, 2).Return(nil, repoerr.ErrAdNotFound)
+
+		_, err := service.GetAd(context.Background(), 2)
+		assert.ErrorIs(t, err, usecaseerr.ErrAdNotFound)
+	})
 }
 
 func TestMockAdminService_Reject(t *testing.T) {
+	t.Run("author is notified", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		mockUserRepo := user.MockUserRepo{}
+		bus := eventbus.New()
+		events, unsubscribe := bus.Subscribe("author")
+		defer unsubscribe()
+		notify := &notifier.MockNotifier{}
+		defer notify.AssertExpectations(t)
+		service := NewAdminService(&mockRepo, &mockUserRepo, knownRejections(), quietHistory(), quietStatistics(),
+			quietSearches(), bus, notify, inTx(), customLogger.Logger{})
+
+		mockRepo.On("GetByID", mock.Anything, 1).Return(&entities.Ad{ID: 1, Title: "Bike", AuthorID: "author"}, nil)
+		mockRepo.On("Reject", mock.Anything, 1, mock.AnythingOfType("*entities.Ad")).Return(nil)
+		notify.On("Notify", mock.Anything, entities.Notification{
+			Kind:    entities.NotificationModeration,
+			UserID:  "author",
+			Subject: `Your ad "Bike" was rejected`,
+			Body:    "Reason: Bad photos. Three of them are blurry.",
+		}).Return(nil)
+
+		rejection := entities.Rejection{Code: "poor_photos", Note: "Three of them are blurry."}
+		assert.NoError(t, service.Reject(context.Background(), 1, 0, "admin", rejection))
+
+		event := <-events
+		assert.Equal(t, entities.EventAdRejected, event.Type)
+		assert.Equal(t, "Bad photos. Three of them are blurry.", event.Data["reason"])
+		assert.Equal(t, "poor_photos", event.Data["code"])
+	})
+
 	t.Run("success", func(t *testing.T) {
 		mockRepo := ad.MockAdRepo{}
 		mockUserRepo := user.MockUserRepo{}
 		defer mockRepo.AssertExpectations(t)
-		service := NewAdminService(&mockRepo, &mockUserRepo, customLogger.Logger{})
+		service := newTestService(&mockRepo, &mockUserRepo)
 
 		adEntity := &entities.Ad{ID: 1}
 		mockRepo.On("GetByID", mock.Anything, 1).Return(adEntity, nil)
 		mockRepo.On("Reject", mock.Anything, 1, mock.AnythingOfType("*entities.Ad")).Return(nil)
 
-		err := service.Reject(context.Background(), 1, "bad")
+		err := service.Reject(context.Background(), 1, 0, "admin", entities.Rejection{Code: "poor_photos"})
 		assert.NoError(t, err)
 	})
 
@@ -173,23 +341,23 @@ func TestMockAdminService_Reject(t *testing.T) {
 		mockRepo := ad.MockAdRepo{}
 		mockUserRepo := user.MockUserRepo{}
 		defer mockRepo.AssertExpectations(t)
-		service := NewAdminService(&mockRepo, &mockUserRepo, customLogger.Logger{})
+		service := newTestService(&mockRepo, &mockUserRepo)
 
-		mockRepo.On("GetByID", mock.Anything, 2).Return(nil, nil)
+		mockRepo.On("GetByID", mock.Anything, 2).Return(nil, repoerr.ErrAdNotFound)
 
-		err := service.Reject(context.Background(), 2, "bad")
-		assert.Error(t, err)
+		err := service.Reject(context.Background(), 2, 0, "admin", entities.Rejection{Code: "poor_photos"})
+		assert.ErrorIs(t, err, usecaseerr.ErrAdNotFound)
 	})
 
 	t.Run("get by id error", func(t *testing.T) {
 		mockRepo := ad.MockAdRepo{}
 		mockUserRepo := user.MockUserRepo{}
 		defer mockRepo.AssertExpectations(t)
-		service := NewAdminService(&mockRepo, &mockUserRepo, customLogger.Logger{})
+		service := newTestService(&mockRepo, &mockUserRepo)
 
 		mockRepo.On("GetByID", mock.Anything, 3).Return(nil, assert.AnError)
 
-		err := service.Reject(context.Background(), 3, "bad")
+		err := service.Reject(context.Background(), 3, 0, "admin", entities.Rejection{Code: "poor_photos"})
 		assert.Error(t, err)
 	})
 
@@ -197,42 +365,292 @@ func TestMockAdminService_Reject(t *testing.T) {
 		mockRepo := ad.MockAdRepo{}
 		mockUserRepo := user.MockUserRepo{}
 		defer mockRepo.AssertExpectations(t)
-		service := NewAdminService(&mockRepo, &mockUserRepo, customLogger.Logger{})
+		service := newTestService(&mockRepo, &mockUserRepo)
 
 		adEntity := &entities.Ad{ID: 4}
 		mockRepo.On("GetByID", mock.Anything, 4).Return(adEntity, nil)
 		mockRepo.On("Reject", mock.Anything, 4, mock.AnythingOfType("*entities.Ad")).Return(assert.AnError)
 
-		err := service.Reject(context.Background(), 4, "bad")
+		err := service.Reject(context.Background(), 4, 0, "admin", entities.Rejection{Code: "poor_photos"})
 		assert.Error(t, err)
 	})
 }
 
+func TestMockAdminService_RejectTemplate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		template  *entities.RejectionTemplate
+		repoErr   error
+		rejection entities.Rejection
+		want      error
+	}{
+		"unknown code": {
+			repoErr:   repoerr.ErrRejectionTemplateNotFound,
+			rejection: entities.Rejection{Code: "spam"},
+			want:      usecaseerr.ErrUnknownRejectionCode,
+		},
+		"deactivated template": {
+			template:  &entities.RejectionTemplate{Code: "spam"},
+			rejection: entities.Rejection{Code: "spam"},
+			want:      usecaseerr.ErrRejectionTemplateInactive,
+		},
+		"template error": {
+			repoErr:   assert.AnError,
+			rejection: entities.Rejection{Code: "spam"},
+			want:      usecaseerr.ErrRejectingAd,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rejections := &rejection.MockRejectionRepo{}
+			defer rejections.AssertExpectations(t)
+			service := NewAdminService(&ad.MockAdRepo{}, &user.MockUserRepo{}, rejections, quietHistory(), quietStatistics(),
+				quietSearches(), eventbus.New(), quietNotifier(), inTx(), customLogger.Logger{})
+
+			rejections.On("GetTemplate", mock.Anything, tc.rejection.Code).Return(tc.template, tc.repoErr)
+
+			assert.Equal(t, tc.want, service.Reject(context.Background(), 1, 0, "admin", tc.rejection))
+		})
+	}
+
+	t.Run("note too long", func(t *testing.T) {
+		service := newTestService(&ad.MockAdRepo{}, &user.MockUserRepo{})
+
+		err := service.Reject(context.Background(), 1, 0, "admin",
+			entities.Rejection{Code: "spam", Note: strings.Repeat("a", MaxRejectionNoteLength+1)})
+		assert.Equal(t, usecaseerr.ErrInvalidRejectionNote, err)
+	})
+}
+
 func TestMockAdminService_GetStatistics(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockRepo := ad.MockAdRepo{}
 		mockUserRepo := user.MockUserRepo{}
 		defer mockRepo.AssertExpectations(t)
-		service := NewAdminService(&mockRepo, &mockUserRepo, customLogger.Logger{})
+		service := newTestService(&mockRepo, &mockUserRepo)
+
+		mockRepo.On("GetStatistics", mock.Anything).Return(entities.AdStatistics{Total: 10, Rejected: 3}, nil)
+
+		stats, err := service.GetStatistics(context.Background(), entities.StatisticsFilter{})
+		assert.NoError(t, err)
+		assert.Equal(t, 10, stats.Total)
+		assert.Equal(t, 3, stats.Rejected)
+		assert.Equal(t, map[string]int{}, stats.RejectionCodes)
+	})
+
+	t.Run("moderation statistics", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		statsRepo := &statistics.MockStatisticsRepo{}
+		defer statsRepo.AssertExpectations(t)
+		service := NewAdminService(&mockRepo, &user.MockUserRepo{}, knownRejections(), quietHistory(), statsRepo,
+			quietSearches(), eventbus.New(), quietNotifier(), inTx(), customLogger.Logger{})
+
+		filter := entities.StatisticsFilter{
+			From:        time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			To:          time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+			Granularity: entities.GranularityMonth,
+		}
+		points := []entities.StatisticsPoint{{Period: filter.From, Submitted: 4, Approved: 3, Rejected: 1}}
+		categories := []entities.CategoryStatistics{{ID: 1, Title: "cars", Approved: 3, Rejected: 1}}
+		moderators := []entities.ModeratorStatistics{{ModeratorID: "admin", Approved: 3}, {Rejected: 1}}
+		backlog := []entities.BacklogBucket{{Label: "<1h", Count: 2}}
+		mockRepo.On("GetStatistics", mock.Anything).Return(entities.AdStatistics{Total: 10}, nil)
+		statsRepo.On("GetSummary", mock.Anything, filter).
+			Return(entities.ModerationStatistics{Decisions: 4, ApprovalRate: 0.75, MedianDecisionSeconds: 90}, nil)
+		statsRepo.On("GetSeries", mock.Anything, filter).Return(points, nil)
+		statsRepo.On("GetCategories", mock.Anything, filter).Return(categories, nil)
+		statsRepo.On("GetModerators", mock.Anything, filter).Return(moderators, nil)
+		statsRepo.On("GetBacklogAge", mock.Anything).Return(backlog, nil)
+
+		stats, err := service.GetStatistics(context.Background(), filter)
+		assert.NoError(t, err)
+		assert.Equal(t, entities.ModerationStatistics{
+			From: filter.From, To: filter.To, Granularity: entities.GranularityMonth,
+			Series: points, Categories: categories, Moderators: moderators, BacklogAge: backlog,
+			Decisions: 4, ApprovalRate: 0.75, MedianDecisionSeconds: 90,
+		}, stats.Moderation)
+	})
 
-		expectedStats := entities.AdStatistics{Total: 10}
-		mockRepo.On("GetStatistics", mock.Anything).Return(expectedStats, nil)
+	t.Run("default period", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		statsRepo := quietStatistics()
+		service := NewAdminService(&mockRepo, &user.MockUserRepo{}, knownRejections(), quietHistory(), statsRepo,
+			quietSearches(), eventbus.New(), quietNotifier(), inTx(), customLogger.Logger{})
+		mockRepo.On("GetStatistics", mock.Anything).Return(entities.AdStatistics{}, nil)
 
-		stats, err := service.GetStatistics(context.Background())
+		stats, err := service.GetStatistics(context.Background(), entities.StatisticsFilter{})
 		assert.NoError(t, err)
-		assert.Equal(t, expectedStats, stats)
+		tomorrow := time.Now().UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+		assert.Equal(t, tomorrow, stats.Moderation.To)
+		assert.Equal(t, tomorrow.AddDate(0, 0, -30), stats.Moderation.From)
+		assert.Equal(t, entities.GranularityDay, stats.Moderation.Granularity)
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		service := newTestService(&ad.MockAdRepo{}, &user.MockUserRepo{})
+		from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		filters := []entities.StatisticsFilter{
+			{Granularity: "year"},
+			{From: from, To: from},
+			{From: from, To: from.AddDate(3, 0, 0)},
+		}
+		for _, filter := range filters {
+			_, err := service.GetStatistics(context.Background(), filter)
+			assert.ErrorIs(t, err, usecaseerr.ErrInvalidStatistics)
+		}
+	})
+
+	t.Run("moderation statistics error", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		statsRepo := &statistics.MockStatisticsRepo{}
+		service := NewAdminService(&mockRepo, &user.MockUserRepo{}, knownRejections(), quietHistory(), statsRepo,
+			quietSearches(), eventbus.New(), quietNotifier(), inTx(), customLogger.Logger{})
+		mockRepo.On("GetStatistics", mock.Anything).Return(entities.AdStatistics{}, nil)
+		statsRepo.On("GetSummary", mock.Anything, mock.Anything).
+			Return(entities.ModerationStatistics{}, repoerr.ErrGettingStatistics)
+
+		_, err := service.GetStatistics(context.Background(), entities.StatisticsFilter{})
+		assert.Equal(t, usecaseerr.ErrGettingStatistics, err)
+	})
+
+	t.Run("rejection codes", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		rejections := &rejection.MockRejectionRepo{}
+		defer rejections.AssertExpectations(t)
+		service := NewAdminService(&mockRepo, &user.MockUserRepo{}, rejections, quietHistory(), quietStatistics(),
+			quietSearches(), eventbus.New(), quietNotifier(), inTx(), customLogger.Logger{})
+
+		mockRepo.On("GetStatistics", mock.Anything).Return(entities.AdStatistics{Rejected: 3}, nil)
+		rejections.On("CountByCode", mock.Anything).Return(map[string]int{"none": 1, "poor_photos": 2}, nil)
+
+		stats, err := service.GetStatistics(context.Background(), entities.StatisticsFilter{})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"none": 1, "poor_photos": 2}, stats.RejectionCodes)
+	})
+
+	t.Run("rejection codes error", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		rejections := &rejection.MockRejectionRepo{}
+		service := NewAdminService(&mockRepo, &user.MockUserRepo{}, rejections, quietHistory(), quietStatistics(),
+			quietSearches(), eventbus.New(), quietNotifier(), inTx(), customLogger.Logger{})
+
+		mockRepo.On("GetStatistics", mock.Anything).Return(entities.AdStatistics{Rejected: 3}, nil)
+		rejections.On("CountByCode", mock.Anything).Return(nil, assert.AnError)
+
+		_, err := service.GetStatistics(context.Background(), entities.StatisticsFilter{})
+		assert.Equal(t, usecaseerr.ErrGettingStatistics, err)
 	})
 
 	t.Run("repo error", func(t *testing.T) {
 		mockRepo := ad.MockAdRepo{}
 		mockUserRepo := user.MockUserRepo{}
 		defer mockRepo.AssertExpectations(t)
-		service := NewAdminService(&mockRepo, &mockUserRepo, customLogger.Logger{})
+		service := newTestService(&mockRepo, &mockUserRepo)
 
 		mockRepo.On("GetStatistics", mock.Anything).Return(entities.AdStatistics{}, assert.AnError)
 
-		stats, err := service.GetStatistics(context.Background())
+		stats, err := service.GetStatistics(context.Background(), entities.StatisticsFilter{})
 		assert.Error(t, err)
 		assert.Equal(t, entities.AdStatistics{}, stats)
 	})
 }
+
+func TestMockAdminService_GetHistory(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		historyRepo := &history.MockHistoryRepo{}
+		service := NewAdminService(&ad.MockAdRepo{}, &user.MockUserRepo{}, knownRejections(), historyRepo, quietStatistics(),
+			quietSearches(), eventbus.New(), quietNotifier(), inTx(), customLogger.Logger{})
+		expected := []entities.ModerationEvent{
+			{ID: 1, AdID: 1, Action: entities.ActionRejected, ActorID: "admin", RejectionCode: "poor_photos"},
+			{ID: 2, AdID: 1, Action: entities.ActionAppealed, ActorID: "author", Note: "The photos are fine."},
+		}
+		historyRepo.On("GetByAd", mock.Anything, 1).Return(expected, nil)
+
+		events, err := service.GetHistory(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, events)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		service := newTestService(&ad.MockAdRepo{}, &user.MockUserRepo{})
+
+		_, err := service.GetHistory(context.Background(), 0)
+		assert.ErrorIs(t, err, usecaseerr.ErrInvalidParams)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		historyRepo := &history.MockHistoryRepo{}
+		service := NewAdminService(&ad.MockAdRepo{}, &user.MockUserRepo{}, knownRejections(), historyRepo, quietStatistics(),
+			quietSearches(), eventbus.New(), quietNotifier(), inTx(), customLogger.Logger{})
+		historyRepo.On("GetByAd", mock.Anything, 1).Return(nil, repoerr.ErrHistorySelect)
+
+		_, err := service.GetHistory(context.Background(), 1)
+		assert.ErrorIs(t, err, usecaseerr.ErrGettingAdHistory)
+	})
+}
+
+func TestMockAdminService_RejectRecordsHistory(t *testing.T) {
+	mockRepo := ad.MockAdRepo{}
+	historyRepo := &history.MockHistoryRepo{}
+	defer historyRepo.AssertExpectations(t)
+	service := NewAdminService(&mockRepo, &user.MockUserRepo{}, knownRejections(), historyRepo, quietStatistics(),
+		quietSearches(), eventbus.New(), quietNotifier(), inTx(), customLogger.Logger{})
+
+	mockRepo.On("GetByID", mock.Anything, 1).Return(&entities.Ad{ID: 1}, nil)
+	mockRepo.On("Reject", mock.Anything, 1, mock.AnythingOfType("*entities.Ad")).Return(nil)
+	historyRepo.On("Record", mock.Anything, &entities.ModerationEvent{
+		AdID: 1, Action: entities.ActionRejected, ActorID: "admin", RejectionCode: "poor_photos", Note: "Blurry.",
+	}).Return(nil)
+
+	err := service.Reject(context.Background(), 1, 0, "admin", entities.Rejection{Code: "poor_photos", Note: "Blurry."})
+	assert.NoError(t, err)
+}
+
+func TestMockAdminService_RejectHistoryError(t *testing.T) {
+	mockRepo := ad.MockAdRepo{}
+	historyRepo := &history.MockHistoryRepo{}
+	service := NewAdminService(&mockRepo, &user.MockUserRepo{}, knownRejections(), historyRepo, quietStatistics(),
+		quietSearches(), eventbus.New(), quietNotifier(), inTx(), customLogger.Logger{})
+
+	mockRepo.On("GetByID", mock.Anything, 1).Return(&entities.Ad{ID: 1}, nil)
+	mockRepo.On("Reject", mock.Anything, 1, mock.AnythingOfType("*entities.Ad")).Return(nil)
+	historyRepo.On("Record", mock.Anything, mock.Anything).Return(errors.New("connection lost"))
+
+	// without the event an appeal could not tell who rejected the ad, so the rejection is rolled back
+	err := service.Reject(context.Background(), 1, 0, "admin", entities.Rejection{Code: "poor_photos"})
+	assert.ErrorIs(t, err, usecaseerr.ErrRejectingAd)
+}
+
+func TestRegisterMetrics(t *testing.T) {
+	statsRepo := &statistics.MockStatisticsRepo{}
+	statsRepo.On("CountAdsByStatus", mock.Anything).
+		Return(map[entities.Status]int{entities.StatusPending: 5, entities.StatusApproved: 7}, nil).Once()
+	statsRepo.On("GetBacklogAge", mock.Anything).
+		Return([]entities.BacklogBucket{{Label: "<1h", Count: 2}, {Label: ">7d", Count: 1}}, nil).Once()
+	defer statsRepo.AssertExpectations(t)
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, RegisterMetrics(registry, statsRepo, time.Minute))
+
+	expected := `
+# HELP ads_by_status Ads by status, drafts are pending.
+# TYPE ads_by_status gauge
+ads_by_status{status="approved"} 7
+ads_by_status{status="pending"} 5
+ads_by_status{status="rejected"} 0
+# HELP ads_moderation_queue_length Submitted ads waiting for a moderator decision.
+# TYPE ads_moderation_queue_length gauge
+ads_moderation_queue_length 3
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected)))
+	// the second scrape within the ttl is served from the cache, the mocks answer only once
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected)))
+}
+
+func TestRegisterMetrics_CountError(t *testing.T) {
+	statsRepo := &statistics.MockStatisticsRepo{}
+	statsRepo.On("CountAdsByStatus", mock.Anything).Return(nil, errors.New("db error"))
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, RegisterMetrics(registry, statsRepo, time.Minute))
+
+	count, err := testutil.GatherAndCount(registry)
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+}
@@ -0,0 +1,79 @@
+package admin
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/repository/statistics"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// countTimeout bounds the queries of a scrape, Prometheus gives up on the scrape itself after 10s by default.
+const countTimeout = 5 * time.Second
+
+var (
+	adsByStatusDesc = prometheus.NewDesc("ads_by_status", "Ads by status, drafts are pending.",
+		[]string{"status"}, nil)
+	queueLengthDesc = prometheus.NewDesc("ads_moderation_queue_length",
+		"Submitted ads waiting for a moderator decision.", nil, nil)
+)
+
+// RegisterMetrics exposes the ads by status and the length of the moderation queue. They are counted in
+// the database at most once per ttl, scrapes in between get the last counts. Until the first count succeeds
+// both are left out of the scrape.
+func RegisterMetrics(registerer prometheus.Registerer, statsRepo statistics.StatisticsRepository,
+	ttl time.Duration) error {
+	return registerer.Register(&adsCollector{statsRepo: statsRepo, ttl: ttl})
+}
+
+type adsCollector struct {
+	countedAt time.Time
+	statsRepo statistics.StatisticsRepository
+	byStatus  map[entities.Status]int
+	waiting   int
+	ttl       time.Duration
+	mu        sync.Mutex
+}
+
+func (c *adsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- adsByStatusDesc
+	ch <- queueLengthDesc
+}
+
+func (c *adsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if time.Since(c.countedAt) >= c.ttl {
+		c.count()
+	}
+	if c.countedAt.IsZero() {
+		return
+	}
+	for _, status := range []entities.Status{entities.StatusPending, entities.StatusApproved, entities.StatusRejected} {
+		ch <- prometheus.MustNewConstMetric(adsByStatusDesc, prometheus.GaugeValue, float64(c.byStatus[status]),
+			string(status))
+	}
+	ch <- prometheus.MustNewConstMetric(queueLengthDesc, prometheus.GaugeValue, float64(c.waiting))
+}
+
+// count reads both counts, a failure keeps the last ones and the next scrape tries again.
+func (c *adsCollector) count() {
+	ctx, cancel := context.WithTimeout(context.Background(), countTimeout)
+	defer cancel()
+
+	byStatus, err := c.statsRepo.CountAdsByStatus(ctx)
+	if err != nil {
+		return
+	}
+	buckets, err := c.statsRepo.GetBacklogAge(ctx)
+	if err != nil {
+		return
+	}
+	var waiting int
+	for _, bucket := range buckets {
+		waiting += bucket.Count
+	}
+	c.byStatus, c.waiting, c.countedAt = byStatus, waiting, time.Now()
+}
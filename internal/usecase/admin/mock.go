@@ -17,8 +17,9 @@ func (m *MockAdminService) GetAllAds(ctx context.Context) ([]entities.Ad, error)
 	return args.Get(0).([]entities.Ad), args.Error(1)
 }
 
-func (m *MockAdminService) GetStatistics(ctx context.Context) (entities.AdStatistics, error) {
-	args := m.Called(ctx)
+func (m *MockAdminService) GetStatistics(ctx context.Context,
+	filter entities.StatisticsFilter) (entities.AdStatistics, error) {
+	args := m.Called(ctx, filter)
 	return args.Get(0).(entities.AdStatistics), args.Error(1)
 }
 
@@ -34,14 +35,31 @@ func (m *MockAdminService) DeleteFile(ctx context.Context, adID int, imageID int
 }
 */
 
-func (m *MockAdminService) Approve(ctx context.Context, adID int) error {
+func (m *MockAdminService) GetAd(ctx context.Context, adID int) (*entities.Ad, error) {
 	args := m.Called(ctx, adID)
+	if ad, ok := args.Get(0).(*entities.Ad); ok {
+		return ad, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAdminService) Approve(ctx context.Context, adID, version int, moderatorID string) error {
+	args := m.Called(ctx, adID, version, moderatorID)
 	return args.Error(0)
 }
 
-func (m *MockAdminService) Reject(ctx context.Context, adID int, reason string) error {
-	args := m.Called(ctx, adID, reason)
+func (m *MockAdminService) Reject(ctx context.Context, adID, version int, moderatorID string,
+	rejection entities.Rejection) error {
+	args := m.Called(ctx, adID, version, moderatorID, rejection)
 	return args.Error(0)
 }
 
+func (m *MockAdminService) GetHistory(ctx context.Context, adID int) ([]entities.ModerationEvent, error) {
+	args := m.Called(ctx, adID)
+	if events, ok := args.Get(0).([]entities.ModerationEvent); ok {
+		return events, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 var _ AdminAdvertisementService = (*MockAdminService)(nil)
@@ -0,0 +1,65 @@
+package admin
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/pkg/tracing"
+	"context"
+	"time"
+)
+
+func (s *service) GetStatistics(ctx context.Context,
+	filter entities.StatisticsFilter) (_ entities.AdStatistics, err error) {
+	ctx, span := tracing.Start(ctx, "admin.GetStatistics")
+	defer tracing.End(span, &err)
+
+	filter, err = normalizeFilter(filter)
+	if err != nil {
+		return entities.AdStatistics{}, err
+	}
+	statistics, err := s.adRepo.GetStatistics(ctx)
+	if err != nil {
+		s.logger.ERROR("error getting statistics:", err)
+		return entities.AdStatistics{}, usecaseerr.ErrGettingStatistics
+	}
+	if statistics.RejectionCodes, err = s.rejections.CountByCode(ctx); err != nil {
+		s.logger.ERROR("error counting rejection codes:", err)
+		return entities.AdStatistics{}, usecaseerr.ErrGettingStatistics
+	}
+	if statistics.Moderation, err = s.moderationStatistics(ctx, filter); err != nil {
+		s.logger.ERROR("error getting moderation statistics:", err)
+		return entities.AdStatistics{}, usecaseerr.ErrGettingStatistics
+	}
+	return statistics, nil
+}
+
+func (s *service) moderationStatistics(ctx context.Context,
+	filter entities.StatisticsFilter) (entities.ModerationStatistics, error) {
+	moderation, err := s.statistics.GetSummary(ctx, filter)
+	if err != nil {
+		return entities.ModerationStatistics{}, err
+	}
+	moderation.From, moderation.To, moderation.Granularity = filter.From, filter.To, filter.Granularity
+	if moderation.Series, err = s.statistics.GetSeries(ctx, filter); err != nil {
+		return entities.ModerationStatistics{}, err
+	}
+	if moderation.Categories, err = s.statistics.GetCategories(ctx, filter); err != nil {
+		return entities.ModerationStatistics{}, err
+	}
+	if moderation.Moderators, err = s.statistics.GetModerators(ctx, filter); err != nil {
+		return entities.ModerationStatistics{}, err
+	}
+	if moderation.BacklogAge, err = s.statistics.GetBacklogAge(ctx); err != nil {
+		return entities.ModerationStatistics{}, err
+	}
+	return moderation, nil
+}
+
+// normalizeFilter fills the defaults of the filter, see entities.StatisticsFilter.WithDefaults.
+func normalizeFilter(filter entities.StatisticsFilter) (entities.StatisticsFilter, error) {
+	filter = filter.WithDefaults(time.Now())
+	if !filter.IsValid() {
+		return entities.StatisticsFilter{}, usecaseerr.ErrInvalidStatistics
+	}
+	return filter, nil
+}
@@ -3,18 +3,35 @@ package admin
 import (
 	"ads-service/internal/domain/entities"
 	"ads-service/internal/repository/ad"
+	"ads-service/internal/repository/history"
+	"ads-service/internal/repository/rejection"
+	"ads-service/internal/repository/statistics"
 	"ads-service/internal/repository/user"
+	"ads-service/internal/usecase/savedsearch"
+	"ads-service/pkg/db"
+	"ads-service/pkg/eventbus"
 	customLogger "ads-service/pkg/logger"
+	"ads-service/pkg/notifier"
 	"context"
 )
 
 type AdminAdvertisementService interface {
 	GetAllAds(ctx context.Context) ([]entities.Ad, error)
-	GetStatistics(ctx context.Context) (entities.AdStatistics, error)
+	GetAd(ctx context.Context, adID int) (*entities.Ad, error)
+	// GetStatistics returns the counters of all ads and the moderation statistics of the filter period.
+	// Without a period it covers the last 30 days by day.
+	GetStatistics(ctx context.Context, filter entities.StatisticsFilter) (entities.AdStatistics, error)
 	DeleteAd(ctx context.Context, adID int) error
 	// DeleteFile(ctx context.Context, adID int, imageID int, adminID string) error
-	Approve(ctx context.Context, adID int) error
-	Reject(ctx context.Context, adID int, reason string) error
+	// Approve publishes the ad, moderatorID is empty for decisions of pre-moderation.
+	// version is the one the moderator saw, a stale one gives an *errs.StaleError with the current ad.
+	// Decisions not based on a read of the ad pass 0 to skip the check.
+	Approve(ctx context.Context, adID, version int, moderatorID string) error
+	// Reject rejects the ad with an active template, the rejection gets the template texts.
+	// version is checked like in Approve.
+	Reject(ctx context.Context, adID, version int, moderatorID string, rejection entities.Rejection) error
+	// GetHistory returns the moderation history of the ad including appeals, oldest first.
+	GetHistory(ctx context.Context, adID int) ([]entities.ModerationEvent, error)
 }
 
 /*
@@ -24,17 +41,33 @@ type AdminAdvertisementService interface {
 */
 type service struct {
 	// fileDel  FileDeleter
-	adRepo   ad.AdRepository
-	userRepo user.UserRepository
-	logger   customLogger.Logger
+	adRepo        ad.AdRepository
+	userRepo      user.UserRepository
+	rejections    rejection.RejectionRepository
+	history       history.HistoryRepository
+	statistics    statistics.StatisticsRepository
+	savedSearches savedsearch.SavedSearchService
+	events        eventbus.Bus
+	notifier      notifier.Notifier
+	tx            db.TxManager
+	logger        customLogger.Logger
 }
 
-func NewAdminService(adRepo ad.AdRepository, userRepo user.UserRepository,
+func NewAdminService(adRepo ad.AdRepository, userRepo user.UserRepository, rejections rejection.RejectionRepository,
+	historyRepo history.HistoryRepository, statsRepo statistics.StatisticsRepository,
+	savedSearches savedsearch.SavedSearchService, events eventbus.Bus, notify notifier.Notifier, tx db.TxManager,
 	logTool customLogger.Logger) AdminAdvertisementService {
 	return &service{
 		// fileDel: fileDel,
-		adRepo:   adRepo,
-		userRepo: userRepo,
-		logger:   logTool,
+		adRepo:        adRepo,
+		userRepo:      userRepo,
+		rejections:    rejections,
+		history:       historyRepo,
+		statistics:    statsRepo,
+		savedSearches: savedSearches,
+		events:        events,
+		notifier:      notify,
+		tx:            tx,
+		logger:        logTool,
 	}
 }
@@ -0,0 +1,174 @@
+package apikey
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/pkg/tracing"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	keyPrefix        = "gads" // makes leaked keys easy to find with secret scanners
+	prefixBytes      = 6
+	secretBytes      = 32
+	maxKeysPerUser   = 10
+	maxKeyNameLength = 100
+	// last_used_at is not rewritten more often than this, to keep writes off the hot path
+	lastUsedPrecision = time.Minute
+)
+
+func (s *service) Create(ctx context.Context, userID string, key *entities.APIKey) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "apikey.Create")
+	defer tracing.End(span, &err)
+
+	key.Name = strings.TrimSpace(key.Name)
+	if key.Name == "" || len(key.Name) > maxKeyNameLength || !validScopes(key.Scopes) {
+		return "", usecaseerr.ErrInvalidAPIKeyData
+	}
+	now := time.Now().UTC()
+	if !key.ExpiresAt.IsZero() && !key.ExpiresAt.After(now) {
+		return "", usecaseerr.ErrInvalidAPIKeyData
+	}
+
+	count, err := s.repo.CountActive(ctx, userID, now)
+	if err != nil {
+		s.logger.ERROR("Error counting api keys:", err)
+		return "", usecaseerr.ErrCreatingAPIKey
+	}
+	if count >= maxKeysPerUser {
+		return "", usecaseerr.ErrTooManyAPIKeys
+	}
+
+	prefix, err := randomString(prefixBytes, hex.EncodeToString)
+	if err != nil {
+		s.logger.ERROR("Error generating api key:", err)
+		return "", usecaseerr.ErrCreatingAPIKey
+	}
+	secret, err := randomString(secretBytes, hex.EncodeToString)
+	if err != nil {
+		s.logger.ERROR("Error generating api key:", err)
+		return "", usecaseerr.ErrCreatingAPIKey
+	}
+	rawKey := keyPrefix + "_" + prefix + "_" + secret
+
+	key.UserID = userID
+	key.Prefix = prefix
+	key.Hash = hashKey(rawKey)
+	key.ID, err = s.repo.Create(ctx, key)
+	if err != nil {
+		s.logger.ERROR("Error creating api key:", err)
+		return "", usecaseerr.ErrCreatingAPIKey
+	}
+	s.logger.INFO("API key ", key.Prefix, " created for user: ", userID)
+	return rawKey, nil
+}
+
+func (s *service) List(ctx context.Context, userID string) (_ []entities.APIKey, err error) {
+	ctx, span := tracing.Start(ctx, "apikey.List")
+	defer tracing.End(span, &err)
+
+	keys, err := s.repo.GetByUser(ctx, userID)
+	if err != nil {
+		s.logger.ERROR("Error getting api keys:", err)
+		return nil, usecaseerr.ErrGettingAPIKeys
+	}
+	return keys, nil
+}
+
+func (s *service) Revoke(ctx context.Context, userID string, id int) (err error) {
+	ctx, span := tracing.Start(ctx, "apikey.Revoke")
+	defer tracing.End(span, &err)
+
+	if id <= 0 {
+		return usecaseerr.ErrInvalidParams
+	}
+	if err := s.repo.Revoke(ctx, userID, id); err != nil {
+		if errors.Is(err, repoerr.ErrAPIKeyNotFound) {
+			return usecaseerr.ErrAPIKeyNotFound
+		}
+		s.logger.ERROR("Error revoking api key:", err)
+		return usecaseerr.ErrRevokingAPIKey
+	}
+	return nil
+}
+
+func (s *service) Authenticate(ctx context.Context, rawKey string,
+	scope entities.APIKeyScope) (_ *entities.APIKey, err error) {
+	ctx, span := tracing.Start(ctx, "apikey.Authenticate")
+	defer tracing.End(span, &err)
+
+	// gads_<prefix>_<secret>, the prefix is stored in plain text for the lookup
+	parts := strings.Split(rawKey, "_")
+	if len(parts) != 3 || parts[0] != keyPrefix || parts[1] == "" || parts[2] == "" {
+		return nil, usecaseerr.ErrInvalidAPIKey
+	}
+
+	key, err := s.repo.GetByPrefix(ctx, parts[1])
+	if err != nil {
+		if errors.Is(err, repoerr.ErrAPIKeyNotFound) {
+			return nil, usecaseerr.ErrInvalidAPIKey
+		}
+		s.logger.ERROR("Error getting api key:", err)
+		return nil, usecaseerr.ErrCheckingAPIKey
+	}
+	if subtle.ConstantTimeCompare([]byte(key.Hash), []byte(hashKey(rawKey))) != 1 {
+		s.logger.WARN("API key hash mismatch for prefix: ", key.Prefix)
+		return nil, usecaseerr.ErrInvalidAPIKey
+	}
+	now := time.Now().UTC()
+	if !key.IsActive(now) {
+		return nil, usecaseerr.ErrInvalidAPIKey
+	}
+	if !key.HasScope(scope) {
+		return nil, usecaseerr.ErrAPIKeyScope
+	}
+
+	if now.Sub(key.LastUsedAt) >= lastUsedPrecision {
+		if err = s.repo.TouchLastUsed(ctx, key.ID, now); err != nil {
+			s.logger.ERROR("Error updating api key last use:", err)
+		}
+		key.LastUsedAt = now
+	}
+	return key, nil
+}
+
+func validScopes(scopes []entities.APIKeyScope) bool {
+	if len(scopes) == 0 {
+		return false
+	}
+	for _, scope := range scopes {
+		if scope != entities.ScopeAdsRead && scope != entities.ScopeAdsWrite {
+			return false
+		}
+	}
+	return !hasDuplicates(scopes)
+}
+
+func hasDuplicates(scopes []entities.APIKeyScope) bool {
+	sorted := slices.Clone(scopes)
+	slices.Sort(sorted)
+	return len(slices.Compact(sorted)) != len(scopes)
+}
+
+func randomString(size int, encode func([]byte) string) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return encode(buf), nil
+}
+
+// hashKey - keys carry 256 bits of entropy, a fast hash is enough.
+func hashKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
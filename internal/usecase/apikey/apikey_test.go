@@ -0,0 +1,198 @@
+package apikey
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/repository/apikey"
+	customLogger "ads-service/pkg/logger"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestService_Create(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		repo := &apikey.MockAPIKeyRepo{}
+		defer repo.AssertExpectations(t)
+		service := NewAPIKeyService(repo, customLogger.Logger{})
+
+		repo.On("CountActive", mock.Anything, "user-1", mock.Anything).Return(0, nil)
+		repo.On("Create", mock.Anything, mock.MatchedBy(func(k *entities.APIKey) bool {
+			return k.UserID == "user-1" && len(k.Prefix) == 2*prefixBytes && len(k.Hash) == 64
+		})).Return(5, nil)
+
+		key := &entities.APIKey{Name: " importer ", Scopes: []entities.APIKeyScope{entities.ScopeAdsWrite}}
+		raw, err := service.Create(context.Background(), "user-1", key)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(raw, "gads_"+key.Prefix+"_"))
+		assert.Equal(t, hashKey(raw), key.Hash)
+		assert.Equal(t, 5, key.ID)
+		assert.Equal(t, "importer", key.Name)
+	})
+
+	t.Run("invalid scope", func(t *testing.T) {
+		service := NewAPIKeyService(&apikey.MockAPIKeyRepo{}, customLogger.Logger{})
+
+		_, err := service.Create(context.Background(), "user-1",
+			&entities.APIKey{Name: "x", Scopes: []entities.APIKeyScope{"admin"}})
+		assert.Equal(t, usecaseerr.ErrInvalidAPIKeyData, err)
+	})
+
+	t.Run("duplicate scopes", func(t *testing.T) {
+		service := NewAPIKeyService(&apikey.MockAPIKeyRepo{}, customLogger.Logger{})
+
+		_, err := service.Create(context.Background(), "user-1", &entities.APIKey{
+			Name:   "x",
+			Scopes: []entities.APIKeyScope{entities.ScopeAdsRead, entities.ScopeAdsRead},
+		})
+		assert.Equal(t, usecaseerr.ErrInvalidAPIKeyData, err)
+	})
+
+	t.Run("expiry in the past", func(t *testing.T) {
+		service := NewAPIKeyService(&apikey.MockAPIKeyRepo{}, customLogger.Logger{})
+
+		_, err := service.Create(context.Background(), "user-1", &entities.APIKey{
+			Name:      "x",
+			Scopes:    []entities.APIKeyScope{entities.ScopeAdsRead},
+			ExpiresAt: time.Now().Add(-time.Hour),
+		})
+		assert.Equal(t, usecaseerr.ErrInvalidAPIKeyData, err)
+	})
+
+	t.Run("limit reached", func(t *testing.T) {
+		repo := &apikey.MockAPIKeyRepo{}
+		defer repo.AssertExpectations(t)
+		service := NewAPIKeyService(repo, customLogger.Logger{})
+
+		repo.On("CountActive", mock.Anything, "user-1", mock.Anything).Return(maxKeysPerUser, nil)
+
+		_, err := service.Create(context.Background(), "user-1",
+			&entities.APIKey{Name: "x", Scopes: []entities.APIKeyScope{entities.ScopeAdsRead}})
+		assert.Equal(t, usecaseerr.ErrTooManyAPIKeys, err)
+	})
+}
+
+func TestService_Authenticate(t *testing.T) {
+	const raw = "gads_0123456789ab_secret"
+	stored := func() *entities.APIKey {
+		return &entities.APIKey{
+			ID:     1,
+			UserID: "user-1",
+			Prefix: "0123456789ab",
+			Hash:   hashKey(raw),
+			Scopes: []entities.APIKeyScope{entities.ScopeAdsRead},
+		}
+	}
+
+	t.Run("success updates last use", func(t *testing.T) {
+		repo := &apikey.MockAPIKeyRepo{}
+		defer repo.AssertExpectations(t)
+		service := NewAPIKeyService(repo, customLogger.Logger{})
+
+		repo.On("GetByPrefix", mock.Anything, "0123456789ab").Return(stored(), nil)
+		repo.On("TouchLastUsed", mock.Anything, 1, mock.Anything).Return(nil)
+
+		key, err := service.Authenticate(context.Background(), raw, entities.ScopeAdsRead)
+		assert.NoError(t, err)
+		assert.Equal(t, "user-1", key.UserID)
+	})
+
+	t.Run("recently used key is not touched", func(t *testing.T) {
+		repo := &apikey.MockAPIKeyRepo{}
+		defer repo.AssertExpectations(t)
+		service := NewAPIKeyService(repo, customLogger.Logger{})
+
+		key := stored()
+		key.LastUsedAt = time.Now().UTC()
+		repo.On("GetByPrefix", mock.Anything, "0123456789ab").Return(key, nil)
+
+		_, err := service.Authenticate(context.Background(), raw, entities.ScopeAdsRead)
+		assert.NoError(t, err)
+	})
+
+	t.Run("missing scope", func(t *testing.T) {
+		repo := &apikey.MockAPIKeyRepo{}
+		service := NewAPIKeyService(repo, customLogger.Logger{})
+
+		repo.On("GetByPrefix", mock.Anything, "0123456789ab").Return(stored(), nil)
+
+		_, err := service.Authenticate(context.Background(), raw, entities.ScopeAdsWrite)
+		assert.Equal(t, usecaseerr.ErrAPIKeyScope, err)
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		repo := &apikey.MockAPIKeyRepo{}
+		service := NewAPIKeyService(repo, customLogger.Logger{})
+
+		repo.On("GetByPrefix", mock.Anything, "0123456789ab").Return(stored(), nil)
+
+		_, err := service.Authenticate(context.Background(), "gads_0123456789ab_other", entities.ScopeAdsRead)
+		assert.Equal(t, usecaseerr.ErrInvalidAPIKey, err)
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		repo := &apikey.MockAPIKeyRepo{}
+		service := NewAPIKeyService(repo, customLogger.Logger{})
+
+		key := stored()
+		key.RevokedAt = time.Now()
+		repo.On("GetByPrefix", mock.Anything, "0123456789ab").Return(key, nil)
+
+		_, err := service.Authenticate(context.Background(), raw, entities.ScopeAdsRead)
+		assert.Equal(t, usecaseerr.ErrInvalidAPIKey, err)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		repo := &apikey.MockAPIKeyRepo{}
+		service := NewAPIKeyService(repo, customLogger.Logger{})
+
+		key := stored()
+		key.ExpiresAt = time.Now().Add(-time.Minute)
+		repo.On("GetByPrefix", mock.Anything, "0123456789ab").Return(key, nil)
+
+		_, err := service.Authenticate(context.Background(), raw, entities.ScopeAdsRead)
+		assert.Equal(t, usecaseerr.ErrInvalidAPIKey, err)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		service := NewAPIKeyService(&apikey.MockAPIKeyRepo{}, customLogger.Logger{})
+
+		_, err := service.Authenticate(context.Background(), "Bearer abc", entities.ScopeAdsRead)
+		assert.Equal(t, usecaseerr.ErrInvalidAPIKey, err)
+	})
+
+	t.Run("unknown prefix", func(t *testing.T) {
+		repo := &apikey.MockAPIKeyRepo{}
+		service := NewAPIKeyService(repo, customLogger.Logger{})
+
+		repo.On("GetByPrefix", mock.Anything, "0123456789ab").Return(nil, repoerr.ErrAPIKeyNotFound)
+
+		_, err := service.Authenticate(context.Background(), raw, entities.ScopeAdsRead)
+		assert.Equal(t, usecaseerr.ErrInvalidAPIKey, err)
+	})
+}
+
+func TestService_Revoke(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		repo := &apikey.MockAPIKeyRepo{}
+		defer repo.AssertExpectations(t)
+		service := NewAPIKeyService(repo, customLogger.Logger{})
+
+		repo.On("Revoke", mock.Anything, "user-1", 3).Return(repoerr.ErrAPIKeyNotFound)
+
+		err := service.Revoke(context.Background(), "user-1", 3)
+		assert.Equal(t, usecaseerr.ErrAPIKeyNotFound, err)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		service := NewAPIKeyService(&apikey.MockAPIKeyRepo{}, customLogger.Logger{})
+
+		err := service.Revoke(context.Background(), "user-1", 0)
+		assert.Equal(t, usecaseerr.ErrInvalidParams, err)
+	})
+}
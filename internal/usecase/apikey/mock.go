@@ -0,0 +1,42 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package apikey
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyService) Create(ctx context.Context, userID string, key *entities.APIKey) (string, error) {
+	args := m.Called(ctx, userID, key)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAPIKeyService) List(ctx context.Context, userID string) ([]entities.APIKey, error) {
+	args := m.Called(ctx, userID)
+	if keys, ok := args.Get(0).([]entities.APIKey); ok {
+		return keys, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAPIKeyService) Revoke(ctx context.Context, userID string, id int) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyService) Authenticate(ctx context.Context, rawKey string,
+	scope entities.APIKeyScope) (*entities.APIKey, error) {
+	args := m.Called(ctx, rawKey, scope)
+	if key, ok := args.Get(0).(*entities.APIKey); ok {
+		return key, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+var _ APIKeyService = (*MockAPIKeyService)(nil)
@@ -0,0 +1,29 @@
+package apikey
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/repository/apikey"
+	customLogger "ads-service/pkg/logger"
+	"context"
+)
+
+type APIKeyService interface {
+	// Create stores the key and returns its plain value, it cannot be shown again.
+	Create(ctx context.Context, userID string, key *entities.APIKey) (string, error)
+	List(ctx context.Context, userID string) ([]entities.APIKey, error)
+	Revoke(ctx context.Context, userID string, id int) error
+	// Authenticate checks the plain key and the scope, returns the key with its owner.
+	Authenticate(ctx context.Context, rawKey string, scope entities.APIKeyScope) (*entities.APIKey, error)
+}
+
+type service struct {
+	repo   apikey.APIKeyRepository
+	logger customLogger.Logger
+}
+
+func NewAPIKeyService(repo apikey.APIKeyRepository, logTool customLogger.Logger) APIKeyService {
+	return &service{
+		repo:   repo,
+		logger: logTool,
+	}
+}
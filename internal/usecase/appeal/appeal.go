@@ -0,0 +1,203 @@
+package appeal
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/pkg/tracing"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxAppealsPerAd - appeals an ad can get over its lifetime, reviewed ones included.
+	maxAppealsPerAd  = 2
+	maxMessageLength = 2000
+	maxNoteLength    = 1000
+)
+
+func (s *service) Appeal(ctx context.Context, authorID string, adID int,
+	message string) (_ *entities.Appeal, err error) {
+	ctx, span := tracing.Start(ctx, "appeal.Appeal")
+	defer tracing.End(span, &err)
+
+	message = strings.TrimSpace(message)
+	if message == "" || utf8.RuneCountInString(message) > maxMessageLength {
+		return nil, usecaseerr.ErrInvalidAppeal
+	}
+	ad, err := s.ownAd(ctx, authorID, adID)
+	if err != nil {
+		return nil, err
+	}
+	if ad.Status != entities.StatusRejected {
+		return nil, usecaseerr.ErrAdNotRejected
+	}
+
+	appeals, err := s.repo.GetByAd(ctx, adID)
+	if err != nil {
+		s.logger.ERROR("error getting appeals: ", err)
+		return nil, usecaseerr.ErrCreatingAppeal
+	}
+	for i := range appeals {
+		if appeals[i].Status == entities.AppealPending {
+			return nil, usecaseerr.ErrAppealPending
+		}
+	}
+	if len(appeals) >= maxAppealsPerAd {
+		return nil, usecaseerr.ErrAppealLimit
+	}
+
+	appeal := &entities.Appeal{AdID: adID, AuthorID: authorID, Message: message}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, appeal); err != nil {
+			if errors.Is(err, repoerr.ErrAppealPending) {
+				return usecaseerr.ErrAppealPending
+			}
+			s.logger.ERROR("error creating appeal: ", err)
+			return usecaseerr.ErrCreatingAppeal
+		}
+		return s.record(ctx, &entities.ModerationEvent{AdID: adID, Action: entities.ActionAppealed,
+			ActorID: authorID, Note: message}, usecaseerr.ErrCreatingAppeal)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.logger.INFO("appeal ", appeal.ID, " created for ad ", adID)
+	return appeal, nil
+}
+
+func (s *service) GetAppeals(ctx context.Context, authorID string, adID int) (_ []entities.Appeal, err error) {
+	ctx, span := tracing.Start(ctx, "appeal.GetAppeals")
+	defer tracing.End(span, &err)
+
+	if _, err := s.ownAd(ctx, authorID, adID); err != nil {
+		return nil, err
+	}
+	appeals, err := s.repo.GetByAd(ctx, adID)
+	if err != nil {
+		s.logger.ERROR("error getting appeals: ", err)
+		return nil, usecaseerr.ErrGettingAppeals
+	}
+	return appeals, nil
+}
+
+func (s *service) ListPending(ctx context.Context) (_ []entities.Appeal, err error) {
+	ctx, span := tracing.Start(ctx, "appeal.ListPending")
+	defer tracing.End(span, &err)
+
+	appeals, err := s.repo.ListPending(ctx)
+	if err != nil {
+		s.logger.ERROR("error getting pending appeals: ", err)
+		return nil, usecaseerr.ErrGettingAppeals
+	}
+	return appeals, nil
+}
+
+func (s *service) Review(ctx context.Context, moderatorID string, appealID int, decision entities.AppealStatus,
+	note string) (err error) {
+	ctx, span := tracing.Start(ctx, "appeal.Review")
+	defer tracing.End(span, &err)
+
+	note = strings.TrimSpace(note)
+	if !decision.IsDecision() || utf8.RuneCountInString(note) > maxNoteLength {
+		return usecaseerr.ErrInvalidDecision
+	}
+	if appealID <= 0 {
+		return usecaseerr.ErrInvalidParams
+	}
+	var ad *entities.Ad
+	// the appeal is claimed before the ad is approved, of two moderators reviewing it at once only one gets it
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		appeal, err := s.repo.GetByID(ctx, appealID)
+		if err != nil {
+			if errors.Is(err, repoerr.ErrAppealNotFound) {
+				return usecaseerr.ErrAppealNotFound
+			}
+			s.logger.ERROR("error getting appeal: ", err)
+			return fmt.Errorf("%w: %w", usecaseerr.ErrReviewingAppeal, err)
+		}
+		if appeal.Status != entities.AppealPending {
+			return usecaseerr.ErrAppealReviewed
+		}
+		if appeal.RejectedBy == moderatorID {
+			return usecaseerr.ErrSameModerator
+		}
+
+		ad, err = s.adRepo.GetByID(ctx, appeal.AdID)
+		if err != nil {
+			s.logger.ERROR("error getting appealed ad: ", err)
+			return usecaseerr.AdLookup(err)
+		}
+		// the author may have edited and resubmitted the ad meanwhile
+		if decision == entities.AppealOverturned && ad.Status != entities.StatusRejected {
+			return usecaseerr.ErrAdNotRejected
+		}
+
+		appeal.Status, appeal.ReviewedBy, appeal.DecisionNote = decision, moderatorID, note
+		if err = s.repo.Review(ctx, appeal); err != nil {
+			if errors.Is(err, repoerr.ErrAppealReviewed) {
+				return usecaseerr.ErrAppealReviewed
+			}
+			s.logger.ERROR("error reviewing appeal: ", err)
+			return usecaseerr.ErrReviewingAppeal
+		}
+		action := entities.ActionAppealUpheld
+		if decision == entities.AppealOverturned {
+			// the version keeps the approval from publishing an ad changed since it was read
+			if err = s.moderation.Approve(ctx, ad.ID, ad.Version, moderatorID); err != nil {
+				return err
+			}
+			action = entities.ActionAppealOverturned
+		}
+		return s.record(ctx, &entities.ModerationEvent{AdID: ad.ID, Action: action, ActorID: moderatorID,
+			Note: note}, usecaseerr.ErrReviewingAppeal)
+	})
+	if err != nil {
+		return err
+	}
+
+	// an overturned rejection is announced by the approval itself
+	if decision == entities.AppealUpheld {
+		body := "A moderator reviewed your appeal, the rejection stays."
+		if note != "" {
+			body += " " + note
+		}
+		if err = s.notifier.Notify(ctx, entities.Notification{
+			Kind:    entities.NotificationModeration,
+			UserID:  ad.AuthorID,
+			Subject: fmt.Sprintf("Your appeal on %q was declined", ad.Title),
+			Body:    body,
+		}); err != nil {
+			s.logger.ERROR("error notifying author about appeal: ", err)
+		}
+	}
+	s.logger.INFO("appeal ", appealID, " reviewed: ", decision)
+	return nil
+}
+
+func (s *service) ownAd(ctx context.Context, authorID string, adID int) (*entities.Ad, error) {
+	if adID <= 0 {
+		return nil, usecaseerr.ErrInvalidParams
+	}
+	ad, err := s.adRepo.GetByID(ctx, adID)
+	if err != nil {
+		s.logger.ERROR("error getting ad: ", err)
+		return nil, usecaseerr.AdLookup(err)
+	}
+	if ad.AuthorID != authorID {
+		return nil, usecaseerr.ErrAccessDenied
+	}
+	return ad, nil
+}
+
+// record adds the step to the moderation history, a failure is returned as failed and undoes the step.
+func (s *service) record(ctx context.Context, event *entities.ModerationEvent, failed error) error {
+	if err := s.history.Record(ctx, event); err != nil {
+		s.logger.ERROR("error recording moderation event: ", err)
+		return fmt.Errorf("%w: %w", failed, err)
+	}
+	return nil
+}
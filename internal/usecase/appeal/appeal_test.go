@@ -0,0 +1,279 @@
+package appeal
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/repository/ad"
+	"ads-service/internal/repository/appeal"
+	"ads-service/internal/repository/history"
+	"ads-service/internal/usecase/admin"
+	"ads-service/pkg/db"
+	customLogger "ads-service/pkg/logger"
+	"ads-service/pkg/notifier"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+)
+
+type fixture struct {
+	repo       *appeal.MockAppealRepo
+	history    *history.MockHistoryRepo
+	adRepo     *ad.MockAdRepo
+	moderation *admin.MockAdminService
+	notifier   *notifier.MockNotifier
+	service    AppealService
+}
+
+func newFixture() *fixture {
+	f := &fixture{
+		repo:       &appeal.MockAppealRepo{},
+		history:    &history.MockHistoryRepo{},
+		adRepo:     &ad.MockAdRepo{},
+		moderation: &admin.MockAdminService{},
+		notifier:   &notifier.MockNotifier{},
+	}
+	f.history.On("Record", mock.Anything, mock.Anything).Return(nil).Maybe()
+	tx := &db.MockTxManager{}
+	tx.On("WithinTx", mock.Anything).Return(nil).Maybe()
+	f.service = NewAppealService(f.repo, f.history, f.adRepo, f.moderation, f.notifier, tx, customLogger.Logger{})
+	return f
+}
+
+func rejectedAd() *entities.Ad {
+	return &entities.Ad{ID: 7, AuthorID: "author", Title: "Bike", Status: entities.StatusRejected, Version: 2}
+}
+
+func TestService_Appeal(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		f := newFixture()
+		f.adRepo.On("GetByID", mock.Anything, 7).Return(rejectedAd(), nil)
+		f.repo.On("GetByAd", mock.Anything, 7).
+			Return([]entities.Appeal{{ID: 1, Status: entities.AppealUpheld}}, nil)
+		f.repo.On("Create", mock.Anything, mock.MatchedBy(func(a *entities.Appeal) bool {
+			return a.AdID == 7 && a.AuthorID == "author" && a.Message == "The photos are mine."
+		})).Return(nil)
+
+		appealed, err := f.service.Appeal(context.Background(), "author", 7, "  The photos are mine. ")
+		assert.NoError(t, err)
+		assert.Equal(t, "The photos are mine.", appealed.Message)
+		f.history.AssertCalled(t, "Record", mock.Anything, mock.MatchedBy(func(e *entities.ModerationEvent) bool {
+			return e.Action == entities.ActionAppealed && e.ActorID == "author"
+		}))
+	})
+
+	t.Run("invalid message", func(t *testing.T) {
+		f := newFixture()
+		for _, message := range []string{" ", strings.Repeat("a", maxMessageLength+1)} {
+			_, err := f.service.Appeal(context.Background(), "author", 7, message)
+			assert.ErrorIs(t, err, usecaseerr.ErrInvalidAppeal)
+		}
+	})
+
+	t.Run("not owner", func(t *testing.T) {
+		f := newFixture()
+		f.adRepo.On("GetByID", mock.Anything, 7).Return(rejectedAd(), nil)
+
+		_, err := f.service.Appeal(context.Background(), "stranger", 7, "why")
+		assert.ErrorIs(t, err, usecaseerr.ErrAccessDenied)
+	})
+
+	t.Run("ad not found", func(t *testing.T) {
+		f := newFixture()
+		f.adRepo.On("GetByID", mock.Anything, 7).Return(nil, repoerr.ErrAdNotFound)
+
+		_, err := f.service.Appeal(context.Background(), "author", 7, "why")
+		assert.ErrorIs(t, err, usecaseerr.ErrAdNotFound)
+	})
+
+	t.Run("ad not rejected", func(t *testing.T) {
+		f := newFixture()
+		approved := rejectedAd()
+		approved.Status = entities.StatusApproved
+		f.adRepo.On("GetByID", mock.Anything, 7).Return(approved, nil)
+
+		_, err := f.service.Appeal(context.Background(), "author", 7, "why")
+		assert.ErrorIs(t, err, usecaseerr.ErrAdNotRejected)
+	})
+
+	t.Run("pending appeal", func(t *testing.T) {
+		f := newFixture()
+		f.adRepo.On("GetByID", mock.Anything, 7).Return(rejectedAd(), nil)
+		f.repo.On("GetByAd", mock.Anything, 7).
+			Return([]entities.Appeal{{ID: 1, Status: entities.AppealPending}}, nil)
+
+		_, err := f.service.Appeal(context.Background(), "author", 7, "why")
+		assert.ErrorIs(t, err, usecaseerr.ErrAppealPending)
+	})
+
+	t.Run("limit reached", func(t *testing.T) {
+		f := newFixture()
+		f.adRepo.On("GetByID", mock.Anything, 7).Return(rejectedAd(), nil)
+		f.repo.On("GetByAd", mock.Anything, 7).Return([]entities.Appeal{
+			{ID: 1, Status: entities.AppealUpheld}, {ID: 2, Status: entities.AppealUpheld},
+		}, nil)
+
+		_, err := f.service.Appeal(context.Background(), "author", 7, "why")
+		assert.ErrorIs(t, err, usecaseerr.ErrAppealLimit)
+	})
+
+	t.Run("concurrent appeal", func(t *testing.T) {
+		f := newFixture()
+		f.adRepo.On("GetByID", mock.Anything, 7).Return(rejectedAd(), nil)
+		f.repo.On("GetByAd", mock.Anything, 7).Return([]entities.Appeal{}, nil)
+		f.repo.On("Create", mock.Anything, mock.Anything).Return(repoerr.ErrAppealPending)
+
+		_, err := f.service.Appeal(context.Background(), "author", 7, "why")
+		assert.ErrorIs(t, err, usecaseerr.ErrAppealPending)
+	})
+
+	t.Run("record error fails the appeal", func(t *testing.T) {
+		f := newFixture()
+		f.history.ExpectedCalls = nil
+		f.adRepo.On("GetByID", mock.Anything, 7).Return(rejectedAd(), nil)
+		f.repo.On("GetByAd", mock.Anything, 7).Return([]entities.Appeal{}, nil)
+		f.repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		f.history.On("Record", mock.Anything, mock.Anything).Return(assert.AnError)
+
+		appealed, err := f.service.Appeal(context.Background(), "author", 7, "why")
+		assert.Nil(t, appealed)
+		assert.ErrorIs(t, err, usecaseerr.ErrCreatingAppeal)
+	})
+}
+
+func TestService_GetAppeals(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		f := newFixture()
+		expected := []entities.Appeal{{ID: 1, AdID: 7}}
+		f.adRepo.On("GetByID", mock.Anything, 7).Return(rejectedAd(), nil)
+		f.repo.On("GetByAd", mock.Anything, 7).Return(expected, nil)
+
+		appeals, err := f.service.GetAppeals(context.Background(), "author", 7)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, appeals)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		f := newFixture()
+		f.adRepo.On("GetByID", mock.Anything, 7).Return(rejectedAd(), nil)
+		f.repo.On("GetByAd", mock.Anything, 7).Return(nil, repoerr.ErrAppealSelect)
+
+		_, err := f.service.GetAppeals(context.Background(), "author", 7)
+		assert.ErrorIs(t, err, usecaseerr.ErrGettingAppeals)
+	})
+}
+
+func TestService_ListPending(t *testing.T) {
+	f := newFixture()
+	f.repo.On("ListPending", mock.Anything).Return(nil, repoerr.ErrAppealSelect)
+
+	_, err := f.service.ListPending(context.Background())
+	assert.ErrorIs(t, err, usecaseerr.ErrGettingAppeals)
+}
+
+func pendingAppeal() *entities.Appeal {
+	return &entities.Appeal{ID: 3, AdID: 7, AuthorID: "author", RejectedBy: "first", Status: entities.AppealPending}
+}
+
+func TestService_Review(t *testing.T) {
+	t.Run("upheld", func(t *testing.T) {
+		f := newFixture()
+		f.repo.On("GetByID", mock.Anything, 3).Return(pendingAppeal(), nil)
+		f.adRepo.On("GetByID", mock.Anything, 7).Return(rejectedAd(), nil)
+		f.repo.On("Review", mock.Anything, mock.MatchedBy(func(a *entities.Appeal) bool {
+			return a.Status == entities.AppealUpheld && a.ReviewedBy == "second" && a.DecisionNote == "Still blurry."
+		})).Return(nil)
+		f.notifier.On("Notify", mock.Anything, mock.MatchedBy(func(n entities.Notification) bool {
+			return n.UserID == "author" && strings.Contains(n.Body, "Still blurry.")
+		})).Return(nil)
+
+		err := f.service.Review(context.Background(), "second", 3, entities.AppealUpheld, "Still blurry.")
+		assert.NoError(t, err)
+		f.notifier.AssertExpectations(t)
+		f.moderation.AssertNotCalled(t, "Approve", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("overturned", func(t *testing.T) {
+		f := newFixture()
+		f.repo.On("GetByID", mock.Anything, 3).Return(pendingAppeal(), nil)
+		f.adRepo.On("GetByID", mock.Anything, 7).Return(rejectedAd(), nil)
+		f.repo.On("Review", mock.Anything, mock.Anything).Return(nil)
+		f.moderation.On("Approve", mock.Anything, 7, 2, "second").Return(nil)
+
+		err := f.service.Review(context.Background(), "second", 3, entities.AppealOverturned, "")
+		assert.NoError(t, err)
+		f.moderation.AssertExpectations(t)
+		f.history.AssertCalled(t, "Record", mock.Anything, mock.MatchedBy(func(e *entities.ModerationEvent) bool {
+			return e.Action == entities.ActionAppealOverturned && e.ActorID == "second"
+		}))
+	})
+
+	t.Run("same moderator", func(t *testing.T) {
+		f := newFixture()
+		f.repo.On("GetByID", mock.Anything, 3).Return(pendingAppeal(), nil)
+
+		err := f.service.Review(context.Background(), "first", 3, entities.AppealOverturned, "")
+		assert.ErrorIs(t, err, usecaseerr.ErrSameModerator)
+	})
+
+	t.Run("invalid decision", func(t *testing.T) {
+		f := newFixture()
+		err := f.service.Review(context.Background(), "second", 3, entities.AppealPending, "")
+		assert.ErrorIs(t, err, usecaseerr.ErrInvalidDecision)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		f := newFixture()
+		f.repo.On("GetByID", mock.Anything, 3).Return(nil, repoerr.ErrAppealNotFound)
+
+		err := f.service.Review(context.Background(), "second", 3, entities.AppealUpheld, "")
+		assert.ErrorIs(t, err, usecaseerr.ErrAppealNotFound)
+	})
+
+	t.Run("already reviewed", func(t *testing.T) {
+		f := newFixture()
+		reviewed := pendingAppeal()
+		reviewed.Status = entities.AppealUpheld
+		f.repo.On("GetByID", mock.Anything, 3).Return(reviewed, nil)
+
+		err := f.service.Review(context.Background(), "second", 3, entities.AppealUpheld, "")
+		assert.ErrorIs(t, err, usecaseerr.ErrAppealReviewed)
+	})
+
+	t.Run("claimed by another moderator", func(t *testing.T) {
+		f := newFixture()
+		f.repo.On("GetByID", mock.Anything, 3).Return(pendingAppeal(), nil)
+		f.adRepo.On("GetByID", mock.Anything, 7).Return(rejectedAd(), nil)
+		f.repo.On("Review", mock.Anything, mock.Anything).Return(repoerr.ErrAppealReviewed)
+
+		err := f.service.Review(context.Background(), "second", 3, entities.AppealOverturned, "")
+		assert.ErrorIs(t, err, usecaseerr.ErrAppealReviewed)
+		f.moderation.AssertNotCalled(t, "Approve", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("ad resubmitted", func(t *testing.T) {
+		f := newFixture()
+		resubmitted := rejectedAd()
+		resubmitted.Status = entities.StatusPending
+		f.repo.On("GetByID", mock.Anything, 3).Return(pendingAppeal(), nil)
+		f.adRepo.On("GetByID", mock.Anything, 7).Return(resubmitted, nil)
+
+		err := f.service.Review(context.Background(), "second", 3, entities.AppealOverturned, "")
+		assert.ErrorIs(t, err, usecaseerr.ErrAdNotRejected)
+	})
+
+	t.Run("record error fails the review", func(t *testing.T) {
+		f := newFixture()
+		f.history.ExpectedCalls = nil
+		f.repo.On("GetByID", mock.Anything, 3).Return(pendingAppeal(), nil)
+		f.adRepo.On("GetByID", mock.Anything, 7).Return(rejectedAd(), nil)
+		f.repo.On("Review", mock.Anything, mock.Anything).Return(nil)
+		f.history.On("Record", mock.Anything, mock.Anything).Return(assert.AnError)
+
+		err := f.service.Review(context.Background(), "second", 3, entities.AppealUpheld, "")
+		assert.ErrorIs(t, err, usecaseerr.ErrReviewingAppeal)
+		f.notifier.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+	})
+}
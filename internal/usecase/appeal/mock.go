@@ -0,0 +1,46 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package appeal
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAppealService struct {
+	mock.Mock
+}
+
+func (m *MockAppealService) Appeal(ctx context.Context, authorID string, adID int,
+	message string) (*entities.Appeal, error) {
+	args := m.Called(ctx, authorID, adID, message)
+	if appeal, ok := args.Get(0).(*entities.Appeal); ok {
+		return appeal, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAppealService) GetAppeals(ctx context.Context, authorID string, adID int) ([]entities.Appeal, error) {
+	args := m.Called(ctx, authorID, adID)
+	if appeals, ok := args.Get(0).([]entities.Appeal); ok {
+		return appeals, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAppealService) ListPending(ctx context.Context) ([]entities.Appeal, error) {
+	args := m.Called(ctx)
+	if appeals, ok := args.Get(0).([]entities.Appeal); ok {
+		return appeals, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAppealService) Review(ctx context.Context, moderatorID string, appealID int,
+	decision entities.AppealStatus, note string) error {
+	args := m.Called(ctx, moderatorID, appealID, decision, note)
+	return args.Error(0)
+}
+
+var _ AppealService = (*MockAppealService)(nil)
@@ -0,0 +1,50 @@
+package appeal
+
+import (
+	"ads-service/internal/domain/entities"
+	adRepo "ads-service/internal/repository/ad"
+	"ads-service/internal/repository/appeal"
+	"ads-service/internal/repository/history"
+	"ads-service/internal/usecase/admin"
+	"ads-service/pkg/db"
+	customLogger "ads-service/pkg/logger"
+	"ads-service/pkg/notifier"
+	"context"
+)
+
+type AppealService interface {
+	// Appeal objects to the rejection of the author's ad. An ad has one pending appeal at a time
+	// and a limited number of appeals overall.
+	Appeal(ctx context.Context, authorID string, adID int, message string) (*entities.Appeal, error)
+	// GetAppeals returns the appeals of the author's ad, oldest first.
+	GetAppeals(ctx context.Context, authorID string, adID int) ([]entities.Appeal, error)
+	// ListPending returns the appeals waiting for a moderator, oldest first.
+	ListPending(ctx context.Context) ([]entities.Appeal, error)
+	// Review upholds the rejection or overturns it and publishes the ad, both in one transaction.
+	// The moderator who rejected the ad can't review its appeal.
+	Review(ctx context.Context, moderatorID string, appealID int, decision entities.AppealStatus, note string) error
+}
+
+type service struct {
+	repo       appeal.AppealRepository
+	history    history.HistoryRepository
+	adRepo     adRepo.AdRepository
+	moderation admin.AdminAdvertisementService
+	notifier   notifier.Notifier
+	tx         db.TxManager
+	logger     customLogger.Logger
+}
+
+func NewAppealService(repo appeal.AppealRepository, historyRepo history.HistoryRepository,
+	adRepository adRepo.AdRepository, moderation admin.AdminAdvertisementService, notify notifier.Notifier,
+	tx db.TxManager, logTool customLogger.Logger) AppealService {
+	return &service{
+		repo:       repo,
+		history:    historyRepo,
+		adRepo:     adRepository,
+		moderation: moderation,
+		notifier:   notify,
+		tx:         tx,
+		logger:     logTool,
+	}
+}
@@ -4,20 +4,36 @@ import (
 	"ads-service/internal/domain/entities"
 	"ads-service/internal/errs/repoerr"
 	"ads-service/internal/errs/usecaseerr"
+	"ads-service/pkg/jwtkeys"
+	"ads-service/pkg/tracing"
 	"ads-service/pkg/utils"
 	"context"
-	"log"
-	"os"
-	"strconv"
+	"errors"
+	"fmt"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // TODO: Move to config or env variable
 
-func (s *userAuthService) Register(ctx context.Context, user *entities.User) error {
+const mfaTokenLifetime = 5 // minutes to enter the second factor after the password
+
+// dummyPasswordHash is compared against when the phone is unknown.
+//
+//nolint:gosec // not a credential, only equalizes response time
+const dummyPasswordHash = "$2a$10$1pQx3K4XqcngfrWFiMgDBuBRlg5D/smtSe7k73UovqkXeocRs.9dC"
+
+func (s *userAuthService) Register(ctx context.Context, user *entities.User) (err error) {
+	ctx, span := tracing.Start(ctx, "auth.Register")
+	defer tracing.End(span, &err)
+
+	err = s.register(ctx, user)
+	registrations.WithLabelValues(result(err)).Inc()
+	return err
+}
+
+func (s *userAuthService) register(ctx context.Context, user *entities.User) error {
 	if user.Password == "" || user.Phone == "" {
 		s.logger.ERROR("phone or password is empty")
 		return usecaseerr.ErrInvalidUserData
@@ -26,16 +42,7 @@ func (s *userAuthService) Register(ctx context.Context, user *entities.User) err
 		s.logger.ERROR("invalid phone or password")
 		return usecaseerr.ErrInvalidUserData
 	}
-	isExists, err := s.userRepo.IsExists(ctx, user.Phone)
-	if err != nil {
-		s.logger.ERROR("Error checking if user exists:", err)
-		return usecaseerr.ErrCheckUserExists
-	}
-	if isExists {
-		s.logger.ERROR("User already exists with phone:", user.Phone)
-		return usecaseerr.ErrUserAlreadyExists
-	}
-
+	// hashed first, the transaction should not wait for bcrypt
 	hash, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 
 	if err != nil {
@@ -45,135 +52,285 @@ func (s *userAuthService) Register(ctx context.Context, user *entities.User) err
 	user.PasswordHash = string(hash)
 	user.Password = "" // Clear the password field after hashing
 
-	_, err = s.userRepo.CreateUser(ctx, user)
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		isExists, err := s.userRepo.IsExists(ctx, user.Phone)
+		if err != nil {
+			s.logger.ERROR("Error checking if user exists:", err)
+			return usecaseerr.ErrCheckUserExists
+		}
+		if isExists {
+			s.logger.ERROR("User already exists with phone:", user.Phone)
+			return usecaseerr.ErrUserAlreadyExists
+		}
+
+		if _, err = s.userRepo.CreateUser(ctx, user); err != nil {
+			s.logger.ERROR("Error creating user:", err)
+			if errors.Is(err, repoerr.ErrUserExists) {
+				return usecaseerr.ErrUserAlreadyExists
+			}
+			return fmt.Errorf("%w: %w", usecaseerr.ErrRegisteringUser, err)
+		}
+		return nil
+	})
 	if err != nil {
-		s.logger.ERROR("Error creating user:", err)
-		return repoerr.ErrUserInsertFailed
+		return err
 	}
 	s.logger.INFO("User registered successfully:", user.Phone)
 
 	return nil
 }
 
-func (s *userAuthService) Login(ctx context.Context, phone, password string) (rToken, accessToken string, err error) {
-	intRefresh, err := strconv.Atoi(os.Getenv("REFRESH_TOKEN_LIFETIME"))
-	if err != nil {
-		s.logger.ERROR("Error converting refresh token duration to int:", err)
-		return "", "", usecaseerr.ErrInvalidTokenDuration
-	}
+func (s *userAuthService) Login(ctx context.Context, phone, password, ip string) (_ *entities.AuthTokens, err error) {
+	ctx, span := tracing.Start(ctx, "auth.Login")
+	defer tracing.End(span, &err)
 
-	intAccess, err := strconv.Atoi(os.Getenv("ACCESS_TOKEN_LIFETIME"))
-	if err != nil {
-		s.logger.ERROR("Error converting access token duration to int:", err)
-		return "", "", usecaseerr.ErrInvalidTokenDuration
+	tokens, err := s.login(ctx, phone, password, ip)
+	if err == nil && tokens.MFAToken != "" {
+		logins.WithLabelValues("password", "mfa_required").Inc()
+	} else {
+		logins.WithLabelValues("password", result(err)).Inc()
 	}
+	return tokens, err
+}
 
+func (s *userAuthService) login(ctx context.Context, phone, password, ip string) (*entities.AuthTokens, error) {
 	if phone == "" || password == "" {
-		return "", "", usecaseerr.ErrInvalidUserData
+		return nil, usecaseerr.ErrInvalidUserData
 	}
 
-	user, err := s.userRepo.GetByPhone(ctx, phone)
+	attempt, err := s.lockouts.Reserve(ctx, phone, ip)
+	if errors.Is(err, usecaseerr.ErrTooManyAttempts) {
+		s.logger.WARN("Login attempt while locked out, phone: ", phone, " ip: ", ip)
+	}
 	if err != nil {
+		return nil, err
+	}
+	// the attempt stays counted only when the credentials are wrong
+	failed := false
+	defer func() {
+		if !failed {
+			s.lockouts.Release(ctx, attempt)
+		}
+	}()
+
+	user, err := s.userRepo.GetByPhone(ctx, phone)
+	if err != nil && !errors.Is(err, repoerr.ErrUserNotFound) {
 		s.logger.ERROR("Error getting user by phone:", err)
-		return "", "", usecaseerr.ErrCheckUserExists
+		return nil, usecaseerr.ErrCheckUserExists
 	}
 
-	if user == nil {
-		s.logger.ERROR("User not found with phone:", phone)
-		return "", "", usecaseerr.ErrUserNotFound
+	// Compare against a dummy hash for unknown phones so that both failure
+	// paths take the same time and return the same error.
+	passwordHash := dummyPasswordHash
+	if user != nil {
+		passwordHash = user.PasswordHash
+	}
+	err = bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(password))
+	if user == nil || err != nil {
+		s.logger.ERROR("Invalid credentials for phone:", phone)
+		failed = true
+		return nil, usecaseerr.ErrInvalidCredentials
+	}
+	// checked after the password so that the ban is not disclosed to strangers
+	if !user.BannedAt.IsZero() {
+		s.logger.WARN("Login attempt of banned user:", user.Phone)
+		return nil, usecaseerr.ErrUserBanned
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
+	mfaEnabled, err := s.twoFactor.IsEnabled(ctx, user.ID)
 	if err != nil {
-		s.logger.ERROR("Password mismatch for user:", phone)
-		return "", "", usecaseerr.ErrInvalidUserData
+		s.logger.ERROR("Error checking two-factor status:", err)
+		return nil, usecaseerr.ErrTwoFactorCheck
 	}
+	if mfaEnabled {
+		// Counters are kept until the second step succeeds, otherwise a known
+		// password would reset the lockout for guessing codes.
+		mfaToken, err := utils.SignClaims(s.keys, utils.CustomClaims{
+			UserID:  user.ID,
+			Purpose: utils.PurposeMFA,
+		}, mfaTokenLifetime)
+		if err != nil {
+			s.logger.ERROR("Error generating MFA token:", err)
+			return nil, usecaseerr.ErrTokenGeneration
+		}
+		s.logger.INFO("Password accepted, second factor required for user:", user.Phone)
+		return &entities.AuthTokens{MFAToken: mfaToken}, nil
+	}
+	s.lockouts.Reset(ctx, phone)
 
-	rToken, err = utils.GenerateToken(user.ID, intRefresh)
+	tokens, err := s.issueTokens(ctx, user.ID, false)
 	if err != nil {
-		s.logger.ERROR("Error generating refresh token:", err)
-		return "", "", usecaseerr.ErrTokenGeneration
+		return nil, err
+	}
+	s.logger.INFO("Successfully logged in user:", user.Phone)
+	return tokens, nil
+}
+
+func (s *userAuthService) VerifyMFA(ctx context.Context, mfaToken, code,
+	ip string) (_ *entities.AuthTokens, err error) {
+	ctx, span := tracing.Start(ctx, "auth.VerifyMFA")
+	defer tracing.End(span, &err)
+
+	tokens, err := s.verifyMFA(ctx, mfaToken, code, ip)
+	logins.WithLabelValues("mfa", result(err)).Inc()
+	return tokens, err
+}
+
+func (s *userAuthService) verifyMFA(ctx context.Context, mfaToken, code, ip string) (*entities.AuthTokens, error) {
+	claims := &utils.CustomClaims{}
+	token, err := s.keys.Parse(mfaToken, claims)
+	if err != nil || !token.Valid || claims.Purpose != utils.PurposeMFA {
+		s.logger.ERROR("Invalid MFA token:", err)
+		return nil, usecaseerr.ErrInvalidToken
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, claims.UserID)
+	if errors.Is(err, repoerr.ErrUserNotFound) {
+		return nil, usecaseerr.ErrInvalidToken
 	}
-	accessToken, err = utils.GenerateToken(user.ID, intAccess)
 	if err != nil {
-		s.logger.ERROR("Error generating access token:", err)
-		return "", "", usecaseerr.ErrTokenGeneration
+		s.logger.ERROR("Error getting user for MFA:", err)
+		return nil, fmt.Errorf("%w: %w", usecaseerr.ErrGettingUser, err)
 	}
 
-	if err := s.authRepo.Create(ctx, entities.Token{
-		UserID:    user.ID,
-		Token:     rToken,
-		ExpiresAt: time.Now().Local().Add(time.Duration(intRefresh) * time.Minute),
-	}); err != nil {
-		s.logger.ERROR("Error creating refresh token in repository:", err)
-		return "", "", usecaseerr.ErrTokenGeneration
+	attempt, err := s.lockouts.Reserve(ctx, user.Phone, ip)
+	if errors.Is(err, usecaseerr.ErrTooManyAttempts) {
+		s.logger.WARN("MFA attempt while locked out, phone: ", user.Phone, " ip: ", ip)
 	}
-	s.logger.INFO("Successfully logged in user:", user.Phone)
-	return rToken, accessToken, nil
+	if err != nil {
+		return nil, err
+	}
+	failed := false
+	defer func() {
+		if !failed {
+			s.lockouts.Release(ctx, attempt)
+		}
+	}()
+
+	if err = s.twoFactor.Verify(ctx, user.ID, code); err != nil {
+		if errors.Is(err, usecaseerr.ErrInvalidTwoFactorCode) {
+			s.logger.ERROR("Invalid two-factor code for phone:", user.Phone)
+			failed = true
+		}
+		return nil, err
+	}
+	s.lockouts.Reset(ctx, user.Phone)
+
+	tokens, err := s.issueTokens(ctx, user.ID, true)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.INFO("Successfully logged in user with second factor:", user.Phone)
+	return tokens, nil
 }
 
 func (s *userAuthService) Refresh(
 	ctx context.Context,
 	refreshToken string,
 ) (newAccessToken, newRefreshToken string, err error) {
-	intRefresh, err := strconv.Atoi(os.Getenv("REFRESH_TOKEN_LIFETIME"))
-	if err != nil {
-		log.Println("Error converting refresh token duration to int:", err)
-		return "", "", usecaseerr.ErrInvalidTokenDuration
-	}
-
-	intAccess, err := strconv.Atoi(os.Getenv("ACCESS_TOKEN_LIFETIME"))
-	if err != nil {
-		log.Println("Error converting access token duration to int:", err)
-		return "", "", usecaseerr.ErrInvalidTokenDuration
-	}
+	ctx, span := tracing.Start(ctx, "auth.Refresh")
+	defer tracing.End(span, &err)
 
 	claims := &utils.CustomClaims{}
-	token, err := jwt.ParseWithClaims(refreshToken, claims, func(token *jwt.Token) (interface{}, error) {
-		return os.Getenv("JWT_SECRET_KEY"), nil
-	})
+	token, err := s.keys.Parse(refreshToken, claims)
 	if err != nil {
-		log.Println("err while parsing claims: ", err)
+		s.logger.WARN("Error parsing refresh token: ", err)
 		return "", "", usecaseerr.ErrInvalidToken
 	}
-	if !token.Valid {
-		log.Println("token is not valid")
+	if !token.Valid || claims.Purpose != "" {
+		s.logger.WARN("Refresh token is not valid")
 		return "", "", usecaseerr.ErrInvalidToken
 	}
-	if err := s.authRepo.Delete(ctx, refreshToken); err != nil {
-		log.Println("err while deleting refresh token: ", err)
+	user, err := s.userRepo.GetUserByID(ctx, claims.UserID)
+	if errors.Is(err, repoerr.ErrUserNotFound) {
 		return "", "", usecaseerr.ErrInvalidToken
 	}
-	newAccessToken, err = utils.GenerateToken(claims.UserID, intAccess)
 	if err != nil {
-		log.Println(err)
-		return "", "", usecaseerr.ErrTokenGeneration
+		s.logger.ERROR("Error getting user for refresh: ", err)
+		return "", "", fmt.Errorf("%w: %w", usecaseerr.ErrGettingUser, err)
+	}
+	if !user.BannedAt.IsZero() {
+		return "", "", usecaseerr.ErrUserBanned
 	}
-	newRefreshToken, err = utils.GenerateToken(claims.UserID, intRefresh)
+	// the old token is gone only if the new one is stored
+	var tokens *entities.AuthTokens
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.authRepo.Delete(ctx, claims.UserID); err != nil {
+			s.logger.ERROR("Error deleting refresh token: ", err)
+			return usecaseerr.ErrInvalidToken
+		}
+		tokens, err = s.issueTokens(ctx, claims.UserID, claims.MFA)
+		return err
+	})
 	if err != nil {
-		log.Println(err)
-		return "", "", usecaseerr.ErrTokenGeneration
+		return "", "", err
+	}
+	return tokens.AccessToken, tokens.RefreshToken, nil
+}
+
+// issueTokens signs a new access/refresh pair and stores the refresh token.
+func (s *userAuthService) issueTokens(ctx context.Context, userID string, mfa bool) (*entities.AuthTokens, error) {
+	refresh, access := s.config.RefreshTokenLifetime, s.config.AccessTokenLifetime
+	refreshToken, err := utils.SignClaims(s.keys, utils.CustomClaims{UserID: userID, MFA: mfa},
+		int(refresh/time.Minute))
+	if err != nil {
+		s.logger.ERROR("Error generating refresh token:", err)
+		return nil, usecaseerr.ErrTokenGeneration
 	}
-	err = s.authRepo.Create(ctx, entities.Token{
-		UserID:    claims.UserID,
-		Token:     newRefreshToken,
-		ExpiresAt: time.Now().Local().Add(time.Duration(intAccess) * time.Minute),
+	accessToken, err := utils.SignClaims(s.keys, utils.CustomClaims{UserID: userID, MFA: mfa},
+		int(access/time.Minute))
+	if err != nil {
+		s.logger.ERROR("Error generating access token:", err)
+		return nil, usecaseerr.ErrTokenGeneration
+	}
+
+	// Create replaces the previous token of the user, both statements have to land together
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		return s.authRepo.Create(ctx, entities.Token{
+			UserID:    userID,
+			Token:     refreshToken,
+			ExpiresAt: time.Now().Local().Add(refresh),
+		})
 	})
 	if err != nil {
-		log.Println(err)
-		return "", "", usecaseerr.ErrTokenGeneration
+		s.logger.ERROR("Error creating refresh token in repository:", err)
+		return nil, usecaseerr.ErrTokenGeneration
 	}
-	return newAccessToken, newRefreshToken, nil
+	return &entities.AuthTokens{AccessToken: accessToken, RefreshToken: refreshToken}, nil
 }
 
-func (s *userAuthService) IsAdmin(ctx context.Context, userID string) (bool, error) {
+func (s *userAuthService) IsAdmin(ctx context.Context, userID string) (_ bool, err error) {
+	ctx, span := tracing.Start(ctx, "auth.IsAdmin")
+	defer tracing.End(span, &err)
+
 	userByID, err := s.userRepo.GetUserByID(ctx, userID)
 	if err != nil {
-		return false, usecaseerr.ErrGettingUser
+		return false, usecaseerr.UserLookup(err)
 	}
 	if userByID == nil {
 		return false, usecaseerr.ErrUserNotFound
 	}
 
-	return userByID.Role == entities.RoleAdmin, nil
+	return userByID.Role == entities.RoleAdmin && userByID.BannedAt.IsZero(), nil
+}
+
+func (s *userAuthService) CheckActive(ctx context.Context, userID string) (err error) {
+	ctx, span := tracing.Start(ctx, "auth.CheckActive")
+	defer tracing.End(span, &err)
+
+	userByID, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return usecaseerr.UserLookup(err)
+	}
+	if userByID == nil {
+		return usecaseerr.ErrUserNotFound
+	}
+	if !userByID.BannedAt.IsZero() {
+		return usecaseerr.ErrUserBanned
+	}
+	return nil
+}
+
+func (s *userAuthService) JWKS() jwtkeys.JWKS {
+	return s.keys.JWKS()
 }
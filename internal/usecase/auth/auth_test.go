@@ -2,16 +2,47 @@ package auth
 
 import (
 	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
 	"ads-service/internal/repository/auth"
+	"ads-service/internal/repository/lockout"
 	"ads-service/internal/repository/user"
+	lockoutService "ads-service/internal/usecase/lockout"
+	"ads-service/internal/usecase/twofactor"
+	"ads-service/pkg/db"
+	"ads-service/pkg/jwtkeys"
 	customLogger "ads-service/pkg/logger"
+	"ads-service/pkg/utils"
 	"context"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"golang.org/x/crypto/bcrypt"
 	"testing"
+	"time"
 )
 
+var testKeys, _ = jwtkeys.NewHMAC([]byte("testsecret"))
+
+var testConfig = Config{AccessTokenLifetime: 5 * time.Minute, RefreshTokenLifetime: 10 * time.Minute}
+
+func inTx() *db.MockTxManager {
+	m := &db.MockTxManager{}
+	m.On("WithinTx", mock.Anything).Return(nil).Maybe()
+	return m
+}
+
+// lockouts counts the attempts in the mocked repository, the service itself is covered in its package.
+func lockouts(repo *lockout.MockLockoutRepo) lockoutService.LockoutService {
+	return lockoutService.NewLockoutService(repo, inTx(), customLogger.Logger{})
+}
+
+func disabledTwoFactor() *twofactor.MockTwoFactorService {
+	m := &twofactor.MockTwoFactorService{}
+	m.On("IsEnabled", mock.Anything, mock.Anything).Return(false, nil)
+	return m
+}
+
 func TestMockAuthService_IsAdmin(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockUserRepo := &user.MockUserRepo{}
@@ -19,7 +50,8 @@ func TestMockAuthService_IsAdmin(t *testing.T) {
 		defer mockUserRepo.AssertExpectations(t)
 		defer mockAuthRepo.AssertExpectations(t)
 
-		service := NewAuthService(mockUserRepo, mockAuthRepo, customLogger.Logger{})
+		service := NewAuthService(mockUserRepo, mockAuthRepo, lockouts(&lockout.MockLockoutRepo{}),
+			&twofactor.MockTwoFactorService{}, testKeys, testConfig, inTx(), customLogger.Logger{})
 
 		mockUserRepo.On("GetUserByID", mock.Anything, "1").
 			Return(&entities.User{Role: entities.RoleAdmin}, nil)
@@ -35,7 +67,8 @@ func TestMockAuthService_IsAdmin(t *testing.T) {
 		defer mockUserRepo.AssertExpectations(t)
 		defer mockAuthRepo.AssertExpectations(t)
 
-		service := NewAuthService(mockUserRepo, mockAuthRepo, customLogger.Logger{})
+		service := NewAuthService(mockUserRepo, mockAuthRepo, lockouts(&lockout.MockLockoutRepo{}),
+			&twofactor.MockTwoFactorService{}, testKeys, testConfig, inTx(), customLogger.Logger{})
 
 		mockUserRepo.On("GetUserByID", mock.Anything, "2").
 			Return(&entities.User{Role: entities.RoleUser}, nil)
@@ -45,13 +78,29 @@ func TestMockAuthService_IsAdmin(t *testing.T) {
 		assert.False(t, isAdmin)
 	})
 
+	t.Run("banned admin", func(t *testing.T) {
+		mockUserRepo := &user.MockUserRepo{}
+		defer mockUserRepo.AssertExpectations(t)
+
+		service := NewAuthService(mockUserRepo, &auth.MockAuthRepository{}, lockouts(&lockout.MockLockoutRepo{}),
+			&twofactor.MockTwoFactorService{}, testKeys, testConfig, inTx(), customLogger.Logger{})
+
+		mockUserRepo.On("GetUserByID", mock.Anything, "1").
+			Return(&entities.User{Role: entities.RoleAdmin, BannedAt: time.Now()}, nil)
+
+		isAdmin, err := service.IsAdmin(context.Background(), "1")
+		assert.NoError(t, err)
+		assert.False(t, isAdmin)
+	})
+
 	t.Run("user not found", func(t *testing.T) {
 		mockUserRepo := &user.MockUserRepo{}
 		mockAuthRepo := &auth.MockAuthRepository{}
 		defer mockUserRepo.AssertExpectations(t)
 		defer mockAuthRepo.AssertExpectations(t)
 
-		service := NewAuthService(mockUserRepo, mockAuthRepo, customLogger.Logger{})
+		service := NewAuthService(mockUserRepo, mockAuthRepo, lockouts(&lockout.MockLockoutRepo{}),
+			&twofactor.MockTwoFactorService{}, testKeys, testConfig, inTx(), customLogger.Logger{})
 
 		mockUserRepo.On("GetUserByID", mock.Anything, "3").Return(nil, nil)
 
@@ -66,7 +115,8 @@ func TestMockAuthService_IsAdmin(t *testing.T) {
 		defer mockUserRepo.AssertExpectations(t)
 		defer mockAuthRepo.AssertExpectations(t)
 
-		service := NewAuthService(mockUserRepo, mockAuthRepo, customLogger.Logger{})
+		service := NewAuthService(mockUserRepo, mockAuthRepo, lockouts(&lockout.MockLockoutRepo{}),
+			&twofactor.MockTwoFactorService{}, testKeys, testConfig, inTx(), customLogger.Logger{})
 
 		mockUserRepo.On("GetUserByID", mock.Anything, "4").Return(nil, assert.AnError)
 
@@ -76,6 +126,37 @@ func TestMockAuthService_IsAdmin(t *testing.T) {
 	})
 }
 
+func TestMockAuthService_CheckActive(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    *entities.User
+		repoErr error
+		want    error
+	}{
+		{name: "active", user: &entities.User{ID: "1"}},
+		{name: "banned", user: &entities.User{ID: "1", BannedAt: time.Now()}, want: usecaseerr.ErrUserBanned},
+		{name: "deleted", repoErr: repoerr.ErrUserNotFound, want: usecaseerr.ErrUserNotFound},
+		{name: "repo error", repoErr: assert.AnError, want: usecaseerr.ErrGettingUser},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := &user.MockUserRepo{}
+			defer mockUserRepo.AssertExpectations(t)
+
+			service := NewAuthService(mockUserRepo, &auth.MockAuthRepository{}, lockouts(&lockout.MockLockoutRepo{}),
+				&twofactor.MockTwoFactorService{}, testKeys, testConfig, inTx(), customLogger.Logger{})
+			mockUserRepo.On("GetUserByID", mock.Anything, "1").Return(tt.user, tt.repoErr)
+
+			err := service.CheckActive(context.Background(), "1")
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
 func TestMockAuthService_Register(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockUserRepo := &user.MockUserRepo{}
@@ -83,7 +164,8 @@ func TestMockAuthService_Register(t *testing.T) {
 		defer mockUserRepo.AssertExpectations(t)
 		defer mockAuthRepo.AssertExpectations(t)
 
-		service := NewAuthService(mockUserRepo, mockAuthRepo, customLogger.Logger{})
+		service := NewAuthService(mockUserRepo, mockAuthRepo, lockouts(&lockout.MockLockoutRepo{}),
+			&twofactor.MockTwoFactorService{}, testKeys, testConfig, inTx(), customLogger.Logger{})
 		userEntity := &entities.User{Phone: "+998917773355", Password: "ValidPass123!"}
 
 		mockUserRepo.On("IsExists", mock.Anything, userEntity.Phone).
@@ -98,7 +180,8 @@ func TestMockAuthService_Register(t *testing.T) {
 	t.Run("empty pass and phone", func(t *testing.T) {
 		mockUserRepo := &user.MockUserRepo{}
 		mockAuthRepo := &auth.MockAuthRepository{}
-		service := NewAuthService(mockUserRepo, mockAuthRepo, customLogger.Logger{})
+		service := NewAuthService(mockUserRepo, mockAuthRepo, lockouts(&lockout.MockLockoutRepo{}),
+			&twofactor.MockTwoFactorService{}, testKeys, testConfig, inTx(), customLogger.Logger{})
 		userEntity := &entities.User{Phone: "", Password: ""}
 
 		err := service.Register(context.Background(), userEntity)
@@ -108,7 +191,8 @@ func TestMockAuthService_Register(t *testing.T) {
 	t.Run("incorrect pass or number", func(t *testing.T) {
 		mockUserRepo := &user.MockUserRepo{}
 		mockAuthRepo := &auth.MockAuthRepository{}
-		service := NewAuthService(mockUserRepo, mockAuthRepo, customLogger.Logger{})
+		service := NewAuthService(mockUserRepo, mockAuthRepo, lockouts(&lockout.MockLockoutRepo{}),
+			&twofactor.MockTwoFactorService{}, testKeys, testConfig, inTx(), customLogger.Logger{})
 		userEntity := &entities.User{Phone: "123", Password: "123"}
 
 		err := service.Register(context.Background(), userEntity)
@@ -118,7 +202,8 @@ func TestMockAuthService_Register(t *testing.T) {
 	t.Run("user already exist", func(t *testing.T) {
 		mockUserRepo := &user.MockUserRepo{}
 		mockAuthRepo := &auth.MockAuthRepository{}
-		service := NewAuthService(mockUserRepo, mockAuthRepo, customLogger.Logger{})
+		service := NewAuthService(mockUserRepo, mockAuthRepo, lockouts(&lockout.MockLockoutRepo{}),
+			&twofactor.MockTwoFactorService{}, testKeys, testConfig, inTx(), customLogger.Logger{})
 		userEntity := &entities.User{Phone: "+998917773355", Password: "ValidPass123!"}
 
 		mockUserRepo.On("IsExists", mock.Anything, userEntity.Phone).Return(true, nil)
@@ -130,7 +215,8 @@ func TestMockAuthService_Register(t *testing.T) {
 	t.Run("err checking user", func(t *testing.T) {
 		mockUserRepo := &user.MockUserRepo{}
 		mockAuthRepo := &auth.MockAuthRepository{}
-		service := NewAuthService(mockUserRepo, mockAuthRepo, customLogger.Logger{})
+		service := NewAuthService(mockUserRepo, mockAuthRepo, lockouts(&lockout.MockLockoutRepo{}),
+			&twofactor.MockTwoFactorService{}, testKeys, testConfig, inTx(), customLogger.Logger{})
 		userEntity := &entities.User{Phone: "+998917773355", Password: "ValidPass123!"}
 
 		mockUserRepo.On("IsExists", mock.Anything, userEntity.Phone).
@@ -143,7 +229,8 @@ func TestMockAuthService_Register(t *testing.T) {
 	t.Run("err creation user", func(t *testing.T) {
 		mockUserRepo := &user.MockUserRepo{}
 		mockAuthRepo := &auth.MockAuthRepository{}
-		service := NewAuthService(mockUserRepo, mockAuthRepo, customLogger.Logger{})
+		service := NewAuthService(mockUserRepo, mockAuthRepo, lockouts(&lockout.MockLockoutRepo{}),
+			&twofactor.MockTwoFactorService{}, testKeys, testConfig, inTx(), customLogger.Logger{})
 		userEntity := &entities.User{Phone: "+998917773355", Password: "ValidPass123!"}
 
 		mockUserRepo.On("IsExists", mock.Anything, userEntity.Phone).Return(false, nil)
@@ -155,114 +242,459 @@ func TestMockAuthService_Register(t *testing.T) {
 }
 
 func TestMockAuthService_Login(t *testing.T) {
-	t.Setenv("REFRESH_TOKEN_LIFETIME", "10")
-	t.Setenv("ACCESS_TOKEN_LIFETIME", "5")
+	const ip = "10.0.0.1"
+
+	notLocked := func(m *lockout.MockLockoutRepo) {
+		m.On("Reserve", mock.Anything, entities.LockoutByPhone, mock.Anything, mock.Anything, mock.Anything).
+			Return(&entities.LoginAttempt{Kind: entities.LockoutByPhone, Failures: 1}, nil)
+		m.On("Reserve", mock.Anything, entities.LockoutByIP, ip, mock.Anything, mock.Anything).
+			Return(&entities.LoginAttempt{Kind: entities.LockoutByIP, Failures: 1}, nil)
+	}
+	released := func(m *lockout.MockLockoutRepo) {
+		m.On("Release", mock.Anything, entities.LockoutByPhone, mock.Anything, time.Time{}).Return(nil).Once()
+		m.On("Release", mock.Anything, entities.LockoutByIP, ip, time.Time{}).Return(nil).Once()
+	}
 
 	t.Run("success login", func(t *testing.T) {
 		mockUserRepo := &user.MockUserRepo{}
 		mockAuthRepo := &auth.MockAuthRepository{}
+		mockLockoutRepo := &lockout.MockLockoutRepo{}
 		defer mockUserRepo.AssertExpectations(t)
 		defer mockAuthRepo.AssertExpectations(t)
+		defer mockLockoutRepo.AssertExpectations(t)
 
-		service := NewAuthService(mockUserRepo, mockAuthRepo, customLogger.Logger{})
+		service := NewAuthService(mockUserRepo, mockAuthRepo, lockouts(mockLockoutRepo),
+			disabledTwoFactor(), testKeys, testConfig, inTx(), customLogger.Logger{})
 		password := "ValidPass123!"
 		hashed, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 		userEntity := &entities.User{ID: "1", Phone: "+79999999999", PasswordHash: string(hashed)}
 
+		notLocked(mockLockoutRepo)
+		released(mockLockoutRepo)
+		mockLockoutRepo.On("Delete", mock.Anything, entities.LockoutByPhone, userEntity.Phone).Return(nil)
 		mockUserRepo.On("GetByPhone", mock.Anything, userEntity.Phone).Return(userEntity, nil)
 		mockAuthRepo.On("Create", mock.Anything, mock.AnythingOfType("entities.Token")).
 			Return(nil)
 
-		rToken, accessToken, err := service.Login(context.Background(), userEntity.Phone, password)
+		tokens, err := service.Login(context.Background(), userEntity.Phone, password, ip)
 		assert.NoError(t, err)
-		assert.NotEmpty(t, rToken)
-		assert.NotEmpty(t, accessToken)
+		assert.NotEmpty(t, tokens.RefreshToken)
+		assert.NotEmpty(t, tokens.AccessToken)
+		assert.Empty(t, tokens.MFAToken)
+	})
+
+	t.Run("banned user", func(t *testing.T) {
+		mockUserRepo := &user.MockUserRepo{}
+		mockAuthRepo := &auth.MockAuthRepository{}
+		mockLockoutRepo := &lockout.MockLockoutRepo{}
+		defer mockUserRepo.AssertExpectations(t)
+		defer mockAuthRepo.AssertExpectations(t)
+
+		service := NewAuthService(mockUserRepo, mockAuthRepo, lockouts(mockLockoutRepo),
+			disabledTwoFactor(), testKeys, testConfig, inTx(), customLogger.Logger{})
+		password := "ValidPass123!"
+		hashed, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		userEntity := &entities.User{ID: "1", Phone: "+79999999999", PasswordHash: string(hashed),
+			BannedAt: time.Now()}
+
+		notLocked(mockLockoutRepo)
+		released(mockLockoutRepo)
+		mockUserRepo.On("GetByPhone", mock.Anything, userEntity.Phone).Return(userEntity, nil)
+
+		tokens, err := service.Login(context.Background(), userEntity.Phone, password, ip)
+		assert.Nil(t, tokens)
+		assert.Equal(t, usecaseerr.ErrUserBanned, err)
 	})
 
 	t.Run("empty phone and password", func(t *testing.T) {
 		mockUserRepo := &user.MockUserRepo{}
 		mockAuthRepo := &auth.MockAuthRepository{}
-		service := NewAuthService(mockUserRepo, mockAuthRepo, customLogger.Logger{})
+		service := NewAuthService(mockUserRepo, mockAuthRepo, lockouts(&lockout.MockLockoutRepo{}),
+			disabledTwoFactor(), testKeys, testConfig, inTx(), customLogger.Logger{})
 
-		rToken, accessToken, err := service.Login(context.Background(), "", "")
+		tokens, err := service.Login(context.Background(), "", "", ip)
 		assert.Error(t, err)
-		assert.Empty(t, rToken)
-		assert.Empty(t, accessToken)
+		assert.Nil(t, tokens)
 	})
 
 	t.Run("user not found", func(t *testing.T) {
 		mockUserRepo := &user.MockUserRepo{}
 		mockAuthRepo := &auth.MockAuthRepository{}
-		service := NewAuthService(mockUserRepo, mockAuthRepo, customLogger.Logger{})
+		mockLockoutRepo := &lockout.MockLockoutRepo{}
+		defer mockLockoutRepo.AssertExpectations(t)
+		service := NewAuthService(mockUserRepo, mockAuthRepo, lockouts(mockLockoutRepo),
+			disabledTwoFactor(), testKeys, testConfig, inTx(), customLogger.Logger{})
 
-		mockUserRepo.On("GetByPhone", mock.Anything, "notfound").Return(nil, nil)
+		notLocked(mockLockoutRepo)
+		mockUserRepo.On("GetByPhone", mock.Anything, "notfound").Return(nil, repoerr.ErrUserNotFound)
 
-		rToken, accessToken, err := service.Login(context.Background(), "notfound", "pass")
-		assert.Error(t, err)
-		assert.Empty(t, rToken)
-		assert.Empty(t, accessToken)
+		tokens, err := service.Login(context.Background(), "notfound", "pass", ip)
+		assert.Equal(t, usecaseerr.ErrInvalidCredentials, err)
+		assert.Nil(t, tokens)
 	})
 
 	t.Run("error getting user", func(t *testing.T) {
 		mockUserRepo := &user.MockUserRepo{}
 		mockAuthRepo := &auth.MockAuthRepository{}
-		service := NewAuthService(mockUserRepo, mockAuthRepo, customLogger.Logger{})
+		mockLockoutRepo := &lockout.MockLockoutRepo{}
+		service := NewAuthService(mockUserRepo, mockAuthRepo, lockouts(mockLockoutRepo),
+			disabledTwoFactor(), testKeys, testConfig, inTx(), customLogger.Logger{})
 
+		notLocked(mockLockoutRepo)
+		released(mockLockoutRepo)
 		mockUserRepo.On("GetByPhone", mock.Anything, "err").Return(nil, assert.AnError)
 
-		rToken, accessToken, err := service.Login(context.Background(), "err", "pass")
+		tokens, err := service.Login(context.Background(), "err", "pass", ip)
 		assert.Error(t, err)
-		assert.Empty(t, rToken)
-		assert.Empty(t, accessToken)
+		assert.Nil(t, tokens)
 	})
 
 	t.Run("wrong password", func(t *testing.T) {
 		mockUserRepo := &user.MockUserRepo{}
 		mockAuthRepo := &auth.MockAuthRepository{}
-		service := NewAuthService(mockUserRepo, mockAuthRepo, customLogger.Logger{})
+		mockLockoutRepo := &lockout.MockLockoutRepo{}
+		defer mockLockoutRepo.AssertExpectations(t)
+		service := NewAuthService(mockUserRepo, mockAuthRepo, lockouts(mockLockoutRepo),
+			disabledTwoFactor(), testKeys, testConfig, inTx(), customLogger.Logger{})
 		hashed, _ := bcrypt.GenerateFromPassword([]byte("rightpass"), bcrypt.DefaultCost)
 		userEntity := &entities.User{ID: "1", Phone: "+79999999999", PasswordHash: string(hashed)}
 
+		notLocked(mockLockoutRepo)
 		mockUserRepo.On("GetByPhone", mock.Anything, userEntity.Phone).Return(userEntity, nil)
 
-		rToken, accessToken, err := service.Login(context.Background(), userEntity.Phone, "wrongpass")
-		assert.Error(t, err)
-		assert.Empty(t, rToken)
-		assert.Empty(t, accessToken)
+		tokens, err := service.Login(context.Background(), userEntity.Phone, "wrongpass", ip)
+		assert.Equal(t, usecaseerr.ErrInvalidCredentials, err)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("wrong password locks phone after limit", func(t *testing.T) {
+		mockUserRepo := &user.MockUserRepo{}
+		mockAuthRepo := &auth.MockAuthRepository{}
+		mockLockoutRepo := &lockout.MockLockoutRepo{}
+		defer mockLockoutRepo.AssertExpectations(t)
+		service := NewAuthService(mockUserRepo, mockAuthRepo, lockouts(mockLockoutRepo),
+			disabledTwoFactor(), testKeys, testConfig, inTx(), customLogger.Logger{})
+		hashed, _ := bcrypt.GenerateFromPassword([]byte("rightpass"), bcrypt.DefaultCost)
+		userEntity := &entities.User{ID: "1", Phone: "+79999999999", PasswordHash: string(hashed)}
+
+		mockLockoutRepo.On("Reserve", mock.Anything, entities.LockoutByPhone, userEntity.Phone,
+			mock.Anything, mock.Anything).Return(&entities.LoginAttempt{Failures: 5}, nil)
+		mockLockoutRepo.On("Reserve", mock.Anything, entities.LockoutByIP, ip,
+			mock.Anything, mock.Anything).Return(&entities.LoginAttempt{Failures: 1}, nil)
+		mockLockoutRepo.On("Lock", mock.Anything, entities.LockoutByPhone, userEntity.Phone,
+			mock.MatchedBy(func(until time.Time) bool {
+				return until.After(time.Now())
+			})).Return(nil).Once()
+		mockUserRepo.On("GetByPhone", mock.Anything, userEntity.Phone).Return(userEntity, nil)
+
+		_, err := service.Login(context.Background(), userEntity.Phone, "wrongpass", ip)
+		assert.Equal(t, usecaseerr.ErrInvalidCredentials, err)
+	})
+
+	t.Run("right password releases the lock of its attempt", func(t *testing.T) {
+		mockUserRepo := &user.MockUserRepo{}
+		mockAuthRepo := &auth.MockAuthRepository{}
+		mockLockoutRepo := &lockout.MockLockoutRepo{}
+		defer mockLockoutRepo.AssertExpectations(t)
+		service := NewAuthService(mockUserRepo, mockAuthRepo, lockouts(mockLockoutRepo),
+			disabledTwoFactor(), testKeys, testConfig, inTx(), customLogger.Logger{})
+		hashed, _ := bcrypt.GenerateFromPassword([]byte("rightpass"), bcrypt.DefaultCost)
+		userEntity := &entities.User{ID: "1", Phone: "+79999999999", PasswordHash: string(hashed)}
+
+		var lockedUntil time.Time
+		mockLockoutRepo.On("Reserve", mock.Anything, entities.LockoutByPhone, userEntity.Phone,
+			mock.Anything, mock.Anything).Return(&entities.LoginAttempt{Failures: 1}, nil)
+		mockLockoutRepo.On("Reserve", mock.Anything, entities.LockoutByIP, ip,
+			mock.Anything, mock.Anything).Return(&entities.LoginAttempt{Failures: 20}, nil)
+		mockLockoutRepo.On("Lock", mock.Anything, entities.LockoutByIP, ip, mock.Anything).
+			Run(func(args mock.Arguments) {
+				lockedUntil = args.Get(3).(time.Time)
+			}).Return(nil).Once()
+		mockLockoutRepo.On("Delete", mock.Anything, entities.LockoutByPhone, userEntity.Phone).Return(nil)
+		mockLockoutRepo.On("Release", mock.Anything, entities.LockoutByPhone, userEntity.Phone, time.Time{}).
+			Return(nil).Once()
+		mockLockoutRepo.On("Release", mock.Anything, entities.LockoutByIP, ip,
+			mock.MatchedBy(func(until time.Time) bool {
+				return !until.IsZero() && until.Equal(lockedUntil)
+			})).Return(nil).Once()
+		mockUserRepo.On("GetByPhone", mock.Anything, userEntity.Phone).Return(userEntity, nil)
+		mockAuthRepo.On("Create", mock.Anything, mock.AnythingOfType("entities.Token")).Return(nil)
+
+		_, err := service.Login(context.Background(), userEntity.Phone, "rightpass", ip)
+		assert.NoError(t, err)
+	})
+
+	t.Run("locked out", func(t *testing.T) {
+		mockUserRepo := &user.MockUserRepo{}
+		mockAuthRepo := &auth.MockAuthRepository{}
+		mockLockoutRepo := &lockout.MockLockoutRepo{}
+		defer mockUserRepo.AssertExpectations(t)
+		service := NewAuthService(mockUserRepo, mockAuthRepo, lockouts(mockLockoutRepo),
+			disabledTwoFactor(), testKeys, testConfig, inTx(), customLogger.Logger{})
+
+		mockLockoutRepo.On("Reserve", mock.Anything, entities.LockoutByPhone, mock.Anything, mock.Anything,
+			mock.Anything).Return(nil, repoerr.ErrLockedOut)
+
+		tokens, err := service.Login(context.Background(), "+79999999999", "pass", ip)
+		assert.Equal(t, usecaseerr.ErrTooManyAttempts, err)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("lockout check error", func(t *testing.T) {
+		mockUserRepo := &user.MockUserRepo{}
+		mockAuthRepo := &auth.MockAuthRepository{}
+		mockLockoutRepo := &lockout.MockLockoutRepo{}
+		service := NewAuthService(mockUserRepo, mockAuthRepo, lockouts(mockLockoutRepo),
+			disabledTwoFactor(), testKeys, testConfig, inTx(), customLogger.Logger{})
+
+		mockLockoutRepo.On("Reserve", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, repoerr.ErrLoginAttemptSave)
+
+		_, err := service.Login(context.Background(), "+79999999999", "pass", ip)
+		assert.Equal(t, usecaseerr.ErrCheckLockout, err)
 	})
 
 	t.Run("error token creation", func(t *testing.T) {
 		mockUserRepo := &user.MockUserRepo{}
 		mockAuthRepo := &auth.MockAuthRepository{}
-		service := NewAuthService(mockUserRepo, mockAuthRepo, customLogger.Logger{})
+		mockLockoutRepo := &lockout.MockLockoutRepo{}
+		service := NewAuthService(mockUserRepo, mockAuthRepo, lockouts(mockLockoutRepo),
+			disabledTwoFactor(), testKeys, testConfig, inTx(), customLogger.Logger{})
 		password := "ValidPass123!"
 		hashed, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 		userEntity := &entities.User{ID: "1", Phone: "+79999999999", PasswordHash: string(hashed)}
 
+		notLocked(mockLockoutRepo)
+		released(mockLockoutRepo)
+		mockLockoutRepo.On("Delete", mock.Anything, mock.Anything, mock.Anything).
+			Return(repoerr.ErrLockoutNotFound)
 		mockUserRepo.On("GetByPhone", mock.Anything, userEntity.Phone).Return(userEntity, nil)
 		mockAuthRepo.On("Create", mock.Anything, mock.AnythingOfType("entities.Token")).Return(assert.AnError)
 
-		rToken, accessToken, err := service.Login(context.Background(), userEntity.Phone, password)
+		tokens, err := service.Login(context.Background(), userEntity.Phone, password, ip)
 		assert.Error(t, err)
-		assert.Empty(t, rToken)
-		assert.Empty(t, accessToken)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("second factor required", func(t *testing.T) {
+		mockUserRepo := &user.MockUserRepo{}
+		mockAuthRepo := &auth.MockAuthRepository{}
+		mockLockoutRepo := &lockout.MockLockoutRepo{}
+		mockTwoFactor := &twofactor.MockTwoFactorService{}
+		defer mockAuthRepo.AssertExpectations(t)
+		defer mockLockoutRepo.AssertExpectations(t)
+		defer mockTwoFactor.AssertExpectations(t)
+		service := NewAuthService(mockUserRepo, mockAuthRepo, lockouts(mockLockoutRepo),
+			mockTwoFactor, testKeys, testConfig, inTx(), customLogger.Logger{})
+		password := "ValidPass123!"
+		hashed, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		userEntity := &entities.User{ID: "1", Phone: "+79999999999", PasswordHash: string(hashed)}
+
+		notLocked(mockLockoutRepo)
+		released(mockLockoutRepo)
+		mockUserRepo.On("GetByPhone", mock.Anything, userEntity.Phone).Return(userEntity, nil)
+		mockTwoFactor.On("IsEnabled", mock.Anything, "1").Return(true, nil)
+
+		tokens, err := service.Login(context.Background(), userEntity.Phone, password, ip)
+		assert.NoError(t, err)
+		assert.Empty(t, tokens.AccessToken)
+		assert.Empty(t, tokens.RefreshToken)
+
+		claims := &utils.CustomClaims{}
+		_, err = testKeys.Parse(tokens.MFAToken, claims)
+		assert.NoError(t, err)
+		assert.Equal(t, utils.PurposeMFA, claims.Purpose)
+		assert.Equal(t, "1", claims.UserID)
+	})
+}
+
+func TestMockAuthService_VerifyMFA(t *testing.T) {
+	const ip = "10.0.0.1"
+	userEntity := &entities.User{ID: "1", Phone: "+79999999999"}
+	mfaToken, _ := utils.SignClaims(testKeys, utils.CustomClaims{UserID: "1", Purpose: utils.PurposeMFA}, 5)
+
+	notLocked := func(m *lockout.MockLockoutRepo) {
+		m.On("Reserve", mock.Anything, entities.LockoutByPhone, userEntity.Phone, mock.Anything, mock.Anything).
+			Return(&entities.LoginAttempt{Kind: entities.LockoutByPhone, Failures: 1}, nil)
+		m.On("Reserve", mock.Anything, entities.LockoutByIP, ip, mock.Anything, mock.Anything).
+			Return(&entities.LoginAttempt{Kind: entities.LockoutByIP, Failures: 1}, nil)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockUserRepo := &user.MockUserRepo{}
+		mockAuthRepo := &auth.MockAuthRepository{}
+		mockLockoutRepo := &lockout.MockLockoutRepo{}
+		mockTwoFactor := &twofactor.MockTwoFactorService{}
+		defer mockAuthRepo.AssertExpectations(t)
+		defer mockLockoutRepo.AssertExpectations(t)
+		defer mockTwoFactor.AssertExpectations(t)
+		service := NewAuthService(mockUserRepo, mockAuthRepo, lockouts(mockLockoutRepo),
+			mockTwoFactor, testKeys, testConfig, inTx(), customLogger.Logger{})
+
+		notLocked(mockLockoutRepo)
+		mockLockoutRepo.On("Release", mock.Anything, mock.Anything, mock.Anything, time.Time{}).Return(nil).Twice()
+		mockLockoutRepo.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		mockUserRepo.On("GetUserByID", mock.Anything, "1").Return(userEntity, nil)
+		mockTwoFactor.On("Verify", mock.Anything, "1", "123456").Return(nil)
+		mockAuthRepo.On("Create", mock.Anything, mock.AnythingOfType("entities.Token")).Return(nil)
+
+		tokens, err := service.VerifyMFA(context.Background(), mfaToken, "123456", ip)
+		assert.NoError(t, err)
+
+		claims := &utils.CustomClaims{}
+		_, err = testKeys.Parse(tokens.AccessToken, claims)
+		assert.NoError(t, err)
+		assert.True(t, claims.MFA)
+		assert.Empty(t, claims.Purpose)
+	})
+
+	t.Run("invalid code counts as failure", func(t *testing.T) {
+		mockUserRepo := &user.MockUserRepo{}
+		mockLockoutRepo := &lockout.MockLockoutRepo{}
+		mockTwoFactor := &twofactor.MockTwoFactorService{}
+		defer mockLockoutRepo.AssertExpectations(t)
+		service := NewAuthService(mockUserRepo, &auth.MockAuthRepository{}, lockouts(mockLockoutRepo),
+			mockTwoFactor, testKeys, testConfig, inTx(), customLogger.Logger{})
+
+		notLocked(mockLockoutRepo)
+		mockUserRepo.On("GetUserByID", mock.Anything, "1").Return(userEntity, nil)
+		mockTwoFactor.On("Verify", mock.Anything, "1", "000000").Return(usecaseerr.ErrInvalidTwoFactorCode)
+
+		tokens, err := service.VerifyMFA(context.Background(), mfaToken, "000000", ip)
+		assert.Nil(t, tokens)
+		assert.Equal(t, usecaseerr.ErrInvalidTwoFactorCode, err)
+	})
+
+	t.Run("locked out", func(t *testing.T) {
+		mockUserRepo := &user.MockUserRepo{}
+		mockLockoutRepo := &lockout.MockLockoutRepo{}
+		service := NewAuthService(mockUserRepo, &auth.MockAuthRepository{}, lockouts(mockLockoutRepo),
+			&twofactor.MockTwoFactorService{}, testKeys, testConfig, inTx(), customLogger.Logger{})
+
+		mockUserRepo.On("GetUserByID", mock.Anything, "1").Return(userEntity, nil)
+		mockLockoutRepo.On("Reserve", mock.Anything, entities.LockoutByPhone, mock.Anything, mock.Anything,
+			mock.Anything).Return(nil, repoerr.ErrLockedOut)
+
+		tokens, err := service.VerifyMFA(context.Background(), mfaToken, "123456", ip)
+		assert.Nil(t, tokens)
+		assert.Equal(t, usecaseerr.ErrTooManyAttempts, err)
+	})
+
+	t.Run("access token instead of mfa token", func(t *testing.T) {
+		service := NewAuthService(&user.MockUserRepo{}, &auth.MockAuthRepository{},
+			lockouts(&lockout.MockLockoutRepo{}),
+			&twofactor.MockTwoFactorService{}, testKeys, testConfig, inTx(), customLogger.Logger{})
+		accessToken, _ := utils.GenerateToken(testKeys, "1", 5)
+
+		tokens, err := service.VerifyMFA(context.Background(), accessToken, "123456", ip)
+		assert.Nil(t, tokens)
+		assert.Equal(t, usecaseerr.ErrInvalidToken, err)
 	})
 }
 
 func TestMockAuthService_Refresh(t *testing.T) {
-	t.Setenv("REFRESH_TOKEN_LIFETIME", "10")
-	t.Setenv("ACCESS_TOKEN_LIFETIME", "5")
-	t.Setenv("JWT_SECRET_KEY", "testsecret")
+	t.Run("invalid token", func(t *testing.T) {
+		service := NewAuthService(&user.MockUserRepo{}, &auth.MockAuthRepository{},
+			lockouts(&lockout.MockLockoutRepo{}), &twofactor.MockTwoFactorService{}, testKeys, testConfig, inTx(),
+			customLogger.Logger{})
+
+		access, refresh, err := service.Refresh(context.Background(), "not-a-token")
+		assert.Equal(t, usecaseerr.ErrInvalidToken, err)
+		assert.Empty(t, access)
+		assert.Empty(t, refresh)
+	})
 
-	t.Run("ошибка конвертации времени", func(t *testing.T) {
-		t.Setenv("REFRESH_TOKEN_LIFETIME", "notint")
+	t.Run("mfa challenge token", func(t *testing.T) {
+		service := NewAuthService(&user.MockUserRepo{}, &auth.MockAuthRepository{},
+			lockouts(&lockout.MockLockoutRepo{}), &twofactor.MockTwoFactorService{}, testKeys, testConfig, inTx(),
+			customLogger.Logger{})
+		mfaToken, _ := utils.SignClaims(testKeys, utils.CustomClaims{UserID: "user-1", Purpose: utils.PurposeMFA}, 5)
+
+		access, refresh, err := service.Refresh(context.Background(), mfaToken)
+		assert.Equal(t, usecaseerr.ErrInvalidToken, err)
+		assert.Empty(t, access)
+		assert.Empty(t, refresh)
+	})
+
+	t.Run("banned user", func(t *testing.T) {
 		mockUserRepo := &user.MockUserRepo{}
 		mockAuthRepo := &auth.MockAuthRepository{}
-		service := NewAuthService(mockUserRepo, mockAuthRepo, customLogger.Logger{})
+		defer mockAuthRepo.AssertExpectations(t)
+		service := NewAuthService(mockUserRepo, mockAuthRepo,
+			lockouts(&lockout.MockLockoutRepo{}), &twofactor.MockTwoFactorService{}, testKeys, testConfig, inTx(),
+			customLogger.Logger{})
 
-		access, refresh, err := service.Refresh(context.Background(), "sometoken")
-		assert.Error(t, err)
+		refreshToken, err := utils.GenerateToken(testKeys, "user-1", 10)
+		assert.NoError(t, err)
+		mockUserRepo.On("GetUserByID", mock.Anything, "user-1").
+			Return(&entities.User{ID: "user-1", BannedAt: time.Now()}, nil)
+
+		access, refresh, err := service.Refresh(context.Background(), refreshToken)
+		assert.Equal(t, usecaseerr.ErrUserBanned, err)
 		assert.Empty(t, access)
 		assert.Empty(t, refresh)
 	})
+
+	t.Run("success", func(t *testing.T) {
+		mockUserRepo := &user.MockUserRepo{}
+		mockAuthRepo := &auth.MockAuthRepository{}
+		defer mockAuthRepo.AssertExpectations(t)
+		service := NewAuthService(mockUserRepo, mockAuthRepo,
+			lockouts(&lockout.MockLockoutRepo{}), &twofactor.MockTwoFactorService{}, testKeys, testConfig, inTx(),
+			customLogger.Logger{})
+
+		refreshToken, err := utils.GenerateToken(testKeys, "user-1", 10)
+		assert.NoError(t, err)
+
+		mockUserRepo.On("GetUserByID", mock.Anything, "user-1").Return(&entities.User{ID: "user-1"}, nil)
+		mockAuthRepo.On("Delete", mock.Anything, "user-1").Return(nil)
+		mockAuthRepo.On("Create", mock.Anything, mock.MatchedBy(func(tok entities.Token) bool {
+			return tok.UserID == "user-1"
+		})).Return(nil)
+
+		access, refresh, err := service.Refresh(context.Background(), refreshToken)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, access)
+		assert.NotEmpty(t, refresh)
+	})
+	t.Run("new token not stored", func(t *testing.T) {
+		mockUserRepo := &user.MockUserRepo{}
+		mockAuthRepo := &auth.MockAuthRepository{}
+		tx := inTx()
+		defer mockAuthRepo.AssertExpectations(t)
+		service := NewAuthService(mockUserRepo, mockAuthRepo,
+			lockouts(&lockout.MockLockoutRepo{}), &twofactor.MockTwoFactorService{}, testKeys, testConfig, tx,
+			customLogger.Logger{})
+
+		refreshToken, err := utils.GenerateToken(testKeys, "user-1", 10)
+		assert.NoError(t, err)
+
+		mockUserRepo.On("GetUserByID", mock.Anything, "user-1").Return(&entities.User{ID: "user-1"}, nil)
+		mockAuthRepo.On("Delete", mock.Anything, "user-1").Return(nil)
+		mockAuthRepo.On("Create", mock.Anything, mock.Anything).Return(repoerr.ErrCreatingToken)
+
+		// the delete of the old token is rolled back with the failed insert
+		_, _, err = service.Refresh(context.Background(), refreshToken)
+		assert.ErrorIs(t, err, usecaseerr.ErrTokenGeneration)
+		tx.AssertCalled(t, "WithinTx", mock.Anything)
+	})
+}
+
+func TestMockAuthService_JWKS(t *testing.T) {
+	service := NewAuthService(&user.MockUserRepo{}, &auth.MockAuthRepository{},
+		lockouts(&lockout.MockLockoutRepo{}), &twofactor.MockTwoFactorService{}, testKeys, testConfig, inTx(),
+		customLogger.Logger{})
+
+	assert.Empty(t, service.JWKS().Keys)
+}
+
+func TestMetrics(t *testing.T) {
+	service := NewAuthService(&user.MockUserRepo{}, &auth.MockAuthRepository{}, lockouts(&lockout.MockLockoutRepo{}),
+		&twofactor.MockTwoFactorService{}, testKeys, testConfig, inTx(), customLogger.Logger{})
+
+	_ = service.Register(context.Background(), &entities.User{})
+	_, _ = service.Login(context.Background(), "", "", "127.0.0.1")
+
+	assert.GreaterOrEqual(t, testutil.ToFloat64(registrations.WithLabelValues("failure")), 1.0)
+	assert.GreaterOrEqual(t, testutil.ToFloat64(logins.WithLabelValues("password", "failure")), 1.0)
 }
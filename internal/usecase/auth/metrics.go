@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// logins - attempts by step, password or mfa, and result. mfa_required is a password accepted
+	// with the second factor still to come.
+	logins = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ads_logins_total",
+		Help: "Login attempts by step and result: success, mfa_required or failure.",
+	}, []string{"step", "result"})
+	registrations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ads_registrations_total",
+		Help: "Registrations by result: success or failure.",
+	}, []string{"result"})
+)
+
+func result(err error) string {
+	if err != nil {
+		return "failure"
+	}
+	return "success"
+}
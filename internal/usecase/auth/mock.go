@@ -3,6 +3,7 @@ package auth
 
 import (
 	"ads-service/internal/domain/entities"
+	"ads-service/pkg/jwtkeys"
 	"context"
 	"github.com/stretchr/testify/mock"
 )
@@ -19,14 +20,20 @@ func (m *MockAuthService) Register(ctx context.Context, user *entities.User) err
 	return nil
 }
 
-func (m *MockAuthService) Login(ctx context.Context, phone, password string) (string, string, error) {
-	args := m.Called(ctx, phone, password)
-	if accessToken, ok := args.Get(0).(string); ok {
-		if refreshToken, ok := args.Get(1).(string); ok {
-			return accessToken, refreshToken, args.Error(2)
-		}
+func (m *MockAuthService) Login(ctx context.Context, phone, password, ip string) (*entities.AuthTokens, error) {
+	args := m.Called(ctx, phone, password, ip)
+	if tokens, ok := args.Get(0).(*entities.AuthTokens); ok {
+		return tokens, args.Error(1)
 	}
-	return "", "", args.Error(2)
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthService) VerifyMFA(ctx context.Context, mfaToken, code, ip string) (*entities.AuthTokens, error) {
+	args := m.Called(ctx, mfaToken, code, ip)
+	if tokens, ok := args.Get(0).(*entities.AuthTokens); ok {
+		return tokens, args.Error(1)
+	}
+	return nil, args.Error(1)
 }
 
 func (m *MockAuthService) Refresh(ctx context.Context, refreshToken string) (string, string, error) {
@@ -39,6 +46,11 @@ func (m *MockAuthService) Refresh(ctx context.Context, refreshToken string) (str
 	return "", "", args.Error(2)
 }
 
+func (m *MockAuthService) CheckActive(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
 func (m *MockAuthService) IsAdmin(ctx context.Context, userID string) (bool, error) {
 	args := m.Called(ctx, userID)
 	if isAdmin, ok := args.Get(0).(bool); ok {
@@ -46,3 +58,11 @@ func (m *MockAuthService) IsAdmin(ctx context.Context, userID string) (bool, err
 	}
 	return false, args.Error(1)
 }
+
+func (m *MockAuthService) JWKS() jwtkeys.JWKS {
+	args := m.Called()
+	if jwks, ok := args.Get(0).(jwtkeys.JWKS); ok {
+		return jwks
+	}
+	return jwtkeys.JWKS{}
+}
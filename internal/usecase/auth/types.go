@@ -4,28 +4,58 @@ import (
 	"ads-service/internal/domain/entities"
 	"ads-service/internal/repository/auth"
 	"ads-service/internal/repository/user"
+	"ads-service/internal/usecase/lockout"
+	"ads-service/internal/usecase/twofactor"
+	"ads-service/pkg/db"
+	"ads-service/pkg/jwtkeys"
 	customLogger "ads-service/pkg/logger"
 	"context"
+	"time"
 )
 
 type AuthService interface {
 	Register(ctx context.Context, user *entities.User) error
-	Login(ctx context.Context, phone, password string) (string, string, error)
+	// Login returns only MFAToken when the user has two-factor authentication enabled.
+	Login(ctx context.Context, phone, password, ip string) (*entities.AuthTokens, error)
+	// VerifyMFA exchanges the MFA token from Login and a TOTP or recovery code for tokens.
+	VerifyMFA(ctx context.Context, mfaToken, code, ip string) (*entities.AuthTokens, error)
 	Refresh(ctx context.Context, refreshToken string) (string, string, error)
+	// IsAdmin is false for a banned admin.
 	IsAdmin(ctx context.Context, userID string) (bool, error)
+	// CheckActive gives ErrUserBanned for a banned user and ErrUserNotFound for a deleted one, whose access
+	// tokens are still valid until they expire.
+	CheckActive(ctx context.Context, userID string) error
+	JWKS() jwtkeys.JWKS
+}
+
+// Config - lifetimes of issued tokens.
+type Config struct {
+	AccessTokenLifetime  time.Duration
+	RefreshTokenLifetime time.Duration
 }
 
 type userAuthService struct {
-	userRepo user.UserRepository
-	authRepo auth.AuthRepository
-	logger   customLogger.Logger
+	userRepo  user.UserRepository
+	authRepo  auth.AuthRepository
+	lockouts  lockout.LockoutService
+	twoFactor twofactor.TwoFactorService
+	keys      *jwtkeys.KeySet
+	config    Config
+	tx        db.TxManager
+	logger    customLogger.Logger
 }
 
 func NewAuthService(userRepo user.UserRepository, authRepo auth.AuthRepository,
-	logger customLogger.Logger) AuthService {
+	lockouts lockout.LockoutService, twoFactor twofactor.TwoFactorService, keys *jwtkeys.KeySet,
+	config Config, tx db.TxManager, logger customLogger.Logger) AuthService {
 	return &userAuthService{
-		logger:   logger,
-		userRepo: userRepo,
-		authRepo: authRepo,
+		logger:    logger,
+		userRepo:  userRepo,
+		authRepo:  authRepo,
+		lockouts:  lockouts,
+		twoFactor: twoFactor,
+		keys:      keys,
+		config:    config,
+		tx:        tx,
 	}
 }
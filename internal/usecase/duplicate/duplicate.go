@@ -0,0 +1,167 @@
+package duplicate
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/pkg/simhash"
+	"ads-service/pkg/tracing"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg" // decoders for uploaded images
+	_ "image/png"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+const (
+	textThreshold  = 0.85 // SimHash similarity from which texts count as near-duplicates
+	imageThreshold = 0.9  // dHash similarity from which images count as the same picture
+	maxCandidates  = 20
+	// maxImagePixels keeps a small file that declares a huge picture from being decoded into memory
+	maxImagePixels = 40_000_000
+)
+
+func (s *service) Fingerprint(ctx context.Context, ad *entities.Ad) (err error) {
+	ctx, span := tracing.Start(ctx, "duplicate.Fingerprint")
+	defer tracing.End(span, &err)
+
+	text := ad.Title + "\n" + ad.Description
+	fp := &entities.Fingerprint{
+		AuthorID:    ad.AuthorID,
+		ContentHash: simhash.Exact(text),
+		TextHash:    simhash.Text(text),
+		AdID:        ad.ID,
+	}
+
+	files, err := s.fileRepo.GetAll(ctx, ad.ID)
+	if err != nil {
+		s.logger.ERROR("error getting ad images: ", err)
+		return usecaseerr.ErrFingerprinting
+	}
+	for _, file := range files {
+		hash, err := hashImage(file.URL)
+		if err != nil {
+			// svg and broken uploads are not compared, the text still is
+			s.logger.ERROR("error hashing image ", file.URL, ": ", err)
+			continue
+		}
+		fp.ImageHashes = append(fp.ImageHashes, hash)
+	}
+
+	if s.config.RepostWindow > 0 {
+		if err = s.checkRepost(ctx, fp); err != nil {
+			return err
+		}
+	}
+
+	if err = s.repo.Save(ctx, fp); err != nil {
+		s.logger.ERROR("error saving fingerprint: ", err)
+		return usecaseerr.ErrFingerprinting
+	}
+	return nil
+}
+
+func (s *service) checkRepost(ctx context.Context, fp *entities.Fingerprint) error {
+	adID, err := s.repo.FindRepost(ctx, fp, time.Now().Add(-s.config.RepostWindow))
+	if err != nil {
+		s.logger.ERROR("error getting fingerprints: ", err)
+		return usecaseerr.ErrFingerprinting
+	}
+	if adID != 0 {
+		s.logger.INFO("repost of ad ", adID, " refused for ad ", fp.AdID)
+		return usecaseerr.ErrRepost
+	}
+	return nil
+}
+
+func (s *service) FindDuplicates(ctx context.Context, adID int) (_ []entities.DuplicateCandidate, err error) {
+	ctx, span := tracing.Start(ctx, "duplicate.FindDuplicates")
+	defer tracing.End(span, &err)
+
+	if adID <= 0 {
+		return nil, usecaseerr.ErrInvalidParams
+	}
+	fp, err := s.repo.GetByAd(ctx, adID)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrFingerprintNotFound) {
+			return nil, usecaseerr.ErrFingerprintNotFound
+		}
+		s.logger.ERROR("error getting fingerprint: ", err)
+		return nil, usecaseerr.ErrGettingDuplicates
+	}
+	candidates, err := s.repo.ListCandidates(ctx, adID, fp.AuthorID, fp.ContentHash, s.config.MaxCompared)
+	if err != nil {
+		s.logger.ERROR("error getting fingerprints: ", err)
+		return nil, usecaseerr.ErrGettingDuplicates
+	}
+
+	duplicates := make([]entities.DuplicateCandidate, 0)
+	for i := range candidates {
+		c := &candidates[i]
+		d := entities.DuplicateCandidate{
+			Status:          c.AdStatus,
+			Title:           c.AdTitle,
+			AuthorID:        c.AuthorID,
+			TextSimilarity:  simhash.Similarity(fp.TextHash, c.TextHash),
+			ImageSimilarity: imageSimilarity(fp.ImageHashes, c.ImageHashes),
+			AdID:            c.AdID,
+			SameAuthor:      c.AuthorID == fp.AuthorID,
+			Exact:           c.ContentHash == fp.ContentHash,
+		}
+		if d.Exact || d.TextSimilarity >= textThreshold || d.ImageSimilarity >= imageThreshold {
+			duplicates = append(duplicates, d)
+		}
+	}
+
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		a, b := &duplicates[i], &duplicates[j]
+		if a.Exact != b.Exact {
+			return a.Exact
+		}
+		return max(a.TextSimilarity, a.ImageSimilarity) > max(b.TextSimilarity, b.ImageSimilarity)
+	})
+	if len(duplicates) > maxCandidates {
+		duplicates = duplicates[:maxCandidates]
+	}
+	return duplicates, nil
+}
+
+// imageSimilarity returns the similarity of the closest pair of images.
+func imageSimilarity(a, b []uint64) float64 {
+	var best float64
+	for _, x := range a {
+		for _, y := range b {
+			best = max(best, simhash.Similarity(x, y))
+		}
+	}
+	return best
+}
+
+func hashImage(path string) (uint64, error) {
+	f, err := os.Open(path) //nolint:gosec // the path is built by the service on upload
+	if err != nil {
+		return 0, fmt.Errorf("opening image: %w", err)
+	}
+	defer f.Close()
+
+	config, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return 0, fmt.Errorf("decoding image: %w", err)
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return 0, fmt.Errorf("image of %dx%d pixels is too large", config.Width, config.Height)
+	}
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("reading image: %w", err)
+	}
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return 0, fmt.Errorf("decoding image: %w", err)
+	}
+	return simhash.Image(img), nil
+}
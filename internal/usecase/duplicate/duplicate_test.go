@@ -0,0 +1,180 @@
+//nolint:all // testpackage
+package duplicate
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	adfile "ads-service/internal/repository/adFile"
+	"ads-service/internal/repository/fingerprint"
+	customLogger "ads-service/pkg/logger"
+	"ads-service/pkg/simhash"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func writeImage(t *testing.T, name string) string {
+	t.Helper()
+	img := image.NewGray(image.Rect(0, 0, 90, 80))
+	for y := range 80 {
+		for x := range 90 {
+			img.SetGray(x, y, color.Gray{Y: uint8((x * y) % 256)})
+		}
+	}
+	path := filepath.Join(t.TempDir(), name)
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+	require.NoError(t, png.Encode(f, img))
+	return path
+}
+
+// testLimit - MaxCompared of the tests.
+const testLimit = 50
+
+func newTestService(t *testing.T, window time.Duration) (DuplicateService, *fingerprint.MockFingerprintRepo,
+	*adfile.MockAdFileRepository) {
+	repo, files := &fingerprint.MockFingerprintRepo{}, &adfile.MockAdFileRepository{}
+	t.Cleanup(func() {
+		repo.AssertExpectations(t)
+		files.AssertExpectations(t)
+	})
+	service := NewDuplicateService(repo, files, Config{RepostWindow: window, MaxCompared: testLimit},
+		customLogger.Logger{})
+	return service, repo, files
+}
+
+var submitted = &entities.Ad{ID: 9, AuthorID: "seller", Title: "Red bicycle", Description: "Like new, 21 gears"}
+
+func TestService_Fingerprint(t *testing.T) {
+	t.Run("text and images", func(t *testing.T) {
+		service, repo, files := newTestService(t, 0)
+
+		files.On("GetAll", mock.Anything, 9).Return([]entities.AdFile{
+			{URL: writeImage(t, "photo.png")},
+			{URL: filepath.Join(t.TempDir(), "missing.jpg")},
+		}, nil)
+		repo.On("Save", mock.Anything, mock.MatchedBy(func(fp *entities.Fingerprint) bool {
+			return fp.AdID == 9 && fp.ContentHash == simhash.Exact("red bicycle like new 21 gears") &&
+				fp.TextHash != 0 && len(fp.ImageHashes) == 1
+		})).Return(nil)
+
+		assert.NoError(t, service.Fingerprint(context.Background(), submitted))
+	})
+
+	t.Run("repost within the window", func(t *testing.T) {
+		service, repo, files := newTestService(t, 72*time.Hour)
+
+		files.On("GetAll", mock.Anything, 9).Return([]entities.AdFile{}, nil)
+		repo.On("FindRepost", mock.Anything, mock.MatchedBy(func(fp *entities.Fingerprint) bool {
+			return fp.AuthorID == "seller" && fp.ContentHash == simhash.Exact(submitted.Title+" "+submitted.Description)
+		}), mock.MatchedBy(func(since time.Time) bool {
+			return time.Since(since) > 71*time.Hour
+		})).Return(3, nil)
+
+		err := service.Fingerprint(context.Background(), submitted)
+		assert.Equal(t, usecaseerr.ErrRepost, err)
+	})
+
+	t.Run("no repost", func(t *testing.T) {
+		service, repo, files := newTestService(t, 72*time.Hour)
+
+		files.On("GetAll", mock.Anything, 9).Return([]entities.AdFile{}, nil)
+		repo.On("FindRepost", mock.Anything, mock.Anything, mock.Anything).Return(0, nil)
+		repo.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+		assert.NoError(t, service.Fingerprint(context.Background(), submitted))
+	})
+
+	t.Run("save error", func(t *testing.T) {
+		service, repo, files := newTestService(t, 0)
+
+		files.On("GetAll", mock.Anything, 9).Return([]entities.AdFile{}, nil)
+		repo.On("Save", mock.Anything, mock.Anything).Return(repoerr.ErrFingerprintSave)
+
+		err := service.Fingerprint(context.Background(), submitted)
+		assert.Equal(t, usecaseerr.ErrFingerprinting, err)
+	})
+}
+
+func TestService_FindDuplicates(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		service, repo, _ := newTestService(t, 0)
+		text := submitted.Title + "\n" + submitted.Description
+
+		repo.On("GetByAd", mock.Anything, 9).Return(&entities.Fingerprint{
+			AuthorID: "seller", ContentHash: simhash.Exact(text), TextHash: simhash.Text(text),
+			ImageHashes: []uint64{0xF0F0}, AdID: 9,
+		}, nil)
+		repo.On("ListCandidates", mock.Anything, 9, "seller", mock.Anything, testLimit).Return([]entities.Fingerprint{
+			{AuthorID: "other", TextHash: simhash.Text("Red bicycle. Like new, 21 gears!!"), AdID: 1,
+				ContentHash: "x"},
+			{AuthorID: "seller", ContentHash: simhash.Exact(text), TextHash: simhash.Text(text), AdID: 2},
+			{AuthorID: "other", TextHash: simhash.Text("Leather sofa, three seats"), ImageHashes: []uint64{0xF0F1},
+				AdID: 3},
+			{AuthorID: "other", TextHash: simhash.Text("Leather sofa, three seats"), AdID: 4},
+		}, nil)
+
+		duplicates, err := service.FindDuplicates(context.Background(), 9)
+		require.NoError(t, err)
+		require.Len(t, duplicates, 3)
+		assert.Equal(t, 2, duplicates[0].AdID)
+		assert.True(t, duplicates[0].Exact)
+		assert.True(t, duplicates[0].SameAuthor)
+		assert.Equal(t, 1, duplicates[1].AdID)
+		assert.Equal(t, 3, duplicates[2].AdID)
+		assert.InDelta(t, 63.0/64, duplicates[2].ImageSimilarity, 1e-9)
+	})
+
+	t.Run("not submitted", func(t *testing.T) {
+		service, repo, _ := newTestService(t, 0)
+
+		repo.On("GetByAd", mock.Anything, 9).Return(nil, repoerr.ErrFingerprintNotFound)
+
+		_, err := service.FindDuplicates(context.Background(), 9)
+		assert.Equal(t, usecaseerr.ErrFingerprintNotFound, err)
+	})
+
+	t.Run("candidates error", func(t *testing.T) {
+		service, repo, _ := newTestService(t, 0)
+
+		repo.On("GetByAd", mock.Anything, 9).Return(&entities.Fingerprint{AuthorID: "seller", AdID: 9}, nil)
+		repo.On("ListCandidates", mock.Anything, 9, "seller", mock.Anything, testLimit).
+			Return(nil, repoerr.ErrFingerprintSelect)
+
+		_, err := service.FindDuplicates(context.Background(), 9)
+		assert.Equal(t, usecaseerr.ErrGettingDuplicates, err)
+	})
+}
+
+func TestHashImage(t *testing.T) {
+	t.Run("picture", func(t *testing.T) {
+		hash, err := hashImage(writeImage(t, "photo.png"))
+		require.NoError(t, err)
+		assert.NotZero(t, hash)
+	})
+
+	t.Run("too many pixels", func(t *testing.T) {
+		// a valid PNG header declaring 100000x100000 pixels, decoding it would allocate 10 GB
+		ihdr := []byte("IHDR\x00\x01\x86\xa0\x00\x01\x86\xa0\x08\x00\x00\x00\x00")
+		header := binary.BigEndian.AppendUint32([]byte("\x89PNG\r\n\x1a\n"), uint32(len(ihdr)-4))
+		header = binary.BigEndian.AppendUint32(append(header, ihdr...), crc32.ChecksumIEEE(ihdr))
+		path := filepath.Join(t.TempDir(), "bomb.png")
+		require.NoError(t, os.WriteFile(path, header, 0o600))
+
+		_, err := hashImage(path)
+		assert.ErrorContains(t, err, "too large")
+	})
+}
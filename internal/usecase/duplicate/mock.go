@@ -0,0 +1,29 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package duplicate
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDuplicateService struct {
+	mock.Mock
+}
+
+func (m *MockDuplicateService) Fingerprint(ctx context.Context, ad *entities.Ad) error {
+	args := m.Called(ctx, ad)
+	return args.Error(0)
+}
+
+func (m *MockDuplicateService) FindDuplicates(ctx context.Context,
+	adID int) ([]entities.DuplicateCandidate, error) {
+	args := m.Called(ctx, adID)
+	if duplicates, ok := args.Get(0).([]entities.DuplicateCandidate); ok {
+		return duplicates, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+var _ DuplicateService = (*MockDuplicateService)(nil)
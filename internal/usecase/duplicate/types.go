@@ -0,0 +1,44 @@
+package duplicate
+
+import (
+	"ads-service/internal/domain/entities"
+	adfile "ads-service/internal/repository/adFile"
+	"ads-service/internal/repository/fingerprint"
+	customLogger "ads-service/pkg/logger"
+	"context"
+	"time"
+)
+
+type DuplicateService interface {
+	// Fingerprint hashes the text and images of a submitted ad. An exact repost of another ad of the same
+	// author submitted within the repost window is refused.
+	Fingerprint(ctx context.Context, ad *entities.Ad) error
+	// FindDuplicates compares the ad with the other ads of its author and active ads of other users,
+	// most similar first.
+	FindDuplicates(ctx context.Context, adID int) ([]entities.DuplicateCandidate, error)
+}
+
+// Config - RepostWindow is how long an exact repost is refused, 0 turns the check off. MaxCompared bounds
+// the fingerprints an ad is compared with, exact copies are compared first and then the latest, so a near
+// duplicate older than the latest MaxCompared ads is not found.
+type Config struct {
+	RepostWindow time.Duration
+	MaxCompared  int
+}
+
+type service struct {
+	repo     fingerprint.FingerprintRepository
+	fileRepo adfile.AdFileRepository
+	config   Config
+	logger   customLogger.Logger
+}
+
+func NewDuplicateService(repo fingerprint.FingerprintRepository, fileRepo adfile.AdFileRepository, config Config,
+	logTool customLogger.Logger) DuplicateService {
+	return &service{
+		repo:     repo,
+		fileRepo: fileRepo,
+		config:   config,
+		logger:   logTool,
+	}
+}
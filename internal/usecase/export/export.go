@@ -0,0 +1,144 @@
+package export
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/pkg/export"
+	"ads-service/pkg/tracing"
+	"context"
+	"time"
+)
+
+// exportTimeout bounds a streamed export, its cursor holds a database connection until the download ends.
+const exportTimeout = 10 * time.Minute
+
+func (s *service) ExportAds(ctx context.Context, filter *entities.AdFilter, out export.Writer) (err error) {
+	ctx, span := tracing.Start(ctx, "export.ExportAds")
+	defer tracing.End(span, &err)
+	ctx, cancel := context.WithTimeout(ctx, exportTimeout)
+	defer cancel()
+
+	err = out.Sheet("ads", "id", "author_id", "title", "description", "category_id", "status", "is_active",
+		"price", "location", "created_at", "updated_at")
+	if err == nil {
+		err = s.adRepo.Stream(ctx, filter, func(ad *entities.Ad) error {
+			return out.Row(ad.ID, ad.AuthorID, ad.Title, ad.Description, ad.CategoryID, string(ad.Status),
+				ad.IsActive, ad.Price, ad.Location, ad.CreatedAt, ad.UpdatedAt)
+		})
+	}
+	if err != nil {
+		s.logger.ERROR("error exporting ads: ", err)
+		return usecaseerr.ErrExporting
+	}
+	return nil
+}
+
+func (s *service) ExportDecisions(ctx context.Context, filter entities.StatisticsFilter,
+	out export.Writer) (err error) {
+	ctx, span := tracing.Start(ctx, "export.ExportDecisions")
+	defer tracing.End(span, &err)
+
+	filter = filter.WithDefaults(time.Now())
+	if !filter.IsValid() {
+		return usecaseerr.ErrInvalidStatistics
+	}
+	ctx, cancel := context.WithTimeout(ctx, exportTimeout)
+	defer cancel()
+
+	err = out.Sheet("decisions", "id", "ad_id", "action", "moderator_id", "rejection_code", "note", "created_at")
+	if err == nil {
+		err = s.history.StreamDecisions(ctx, filter, func(event *entities.ModerationEvent) error {
+			return out.Row(event.ID, event.AdID, string(event.Action), event.ActorID, event.RejectionCode,
+				event.Note, event.CreatedAt)
+		})
+	}
+	if err != nil {
+		s.logger.ERROR("error exporting moderation decisions: ", err)
+		return usecaseerr.ErrExporting
+	}
+	return nil
+}
+
+func (s *service) ExportStatistics(ctx context.Context, filter entities.StatisticsFilter,
+	out export.Writer) (err error) {
+	ctx, span := tracing.Start(ctx, "export.ExportStatistics")
+	defer tracing.End(span, &err)
+
+	statistics, err := s.statistics.GetStatistics(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if err = writeStatistics(out, &statistics); err != nil {
+		s.logger.ERROR("error exporting statistics: ", err)
+		return usecaseerr.ErrExporting
+	}
+	return nil
+}
+
+// writeStatistics writes the summary and then the breakdowns of the moderation statistics.
+func writeStatistics(out export.Writer, statistics *entities.AdStatistics) error {
+	moderation := &statistics.Moderation
+	summary := [][]interface{}{
+		{"from", moderation.From},
+		{"to", moderation.To},
+		{"granularity", string(moderation.Granularity)},
+		{"total_ads", statistics.Total},
+		{"published_ads", statistics.Published},
+		{"pending_ads", statistics.Pending},
+		{"rejected_ads", statistics.Rejected},
+		{"decisions", moderation.Decisions},
+		{"approval_rate", moderation.ApprovalRate},
+		{"median_decision_seconds", moderation.MedianDecisionSeconds},
+		{"new_users", moderation.NewUsers},
+	}
+	if err := writeTable(out, "summary", []string{"metric", "value"}, summary); err != nil {
+		return err
+	}
+
+	series := make([][]interface{}, 0, len(moderation.Series))
+	for _, p := range moderation.Series {
+		series = append(series, []interface{}{p.Period, p.Submitted, p.Approved, p.Rejected, p.NewUsers})
+	}
+	err := writeTable(out, "series", []string{"period", "submitted", "approved", "rejected", "new_users"}, series)
+	if err != nil {
+		return err
+	}
+
+	categories := make([][]interface{}, 0, len(moderation.Categories))
+	for _, c := range moderation.Categories {
+		categories = append(categories, []interface{}{c.ID, c.Title, c.Submitted, c.Approved, c.Rejected, c.Pending})
+	}
+	err = writeTable(out, "categories",
+		[]string{"category_id", "title", "submitted", "approved", "rejected", "pending"}, categories)
+	if err != nil {
+		return err
+	}
+
+	moderators := make([][]interface{}, 0, len(moderation.Moderators))
+	for _, m := range moderation.Moderators {
+		moderators = append(moderators, []interface{}{m.ModeratorID, m.Approved, m.Rejected, m.MedianDecisionSeconds})
+	}
+	err = writeTable(out, "moderators",
+		[]string{"moderator_id", "approved", "rejected", "median_decision_seconds"}, moderators)
+	if err != nil {
+		return err
+	}
+
+	backlog := make([][]interface{}, 0, len(moderation.BacklogAge))
+	for _, b := range moderation.BacklogAge {
+		backlog = append(backlog, []interface{}{b.Label, b.Count})
+	}
+	return writeTable(out, "backlog", []string{"age", "ads"}, backlog)
+}
+
+func writeTable(out export.Writer, name string, header []string, rows [][]interface{}) error {
+	if err := out.Sheet(name, header...); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := out.Row(row...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
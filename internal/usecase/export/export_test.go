@@ -0,0 +1,126 @@
+package export
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/repository/ad"
+	"ads-service/internal/repository/history"
+	"ads-service/internal/usecase/admin"
+	"ads-service/pkg/export"
+	customLogger "ads-service/pkg/logger"
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+type fixture struct {
+	adRepo     *ad.MockAdRepo
+	history    *history.MockHistoryRepo
+	statistics *admin.MockAdminService
+	service    ExportService
+	buf        *bytes.Buffer
+	out        export.Writer
+}
+
+func newFixture() *fixture {
+	f := &fixture{
+		adRepo:     &ad.MockAdRepo{},
+		history:    &history.MockHistoryRepo{},
+		statistics: &admin.MockAdminService{},
+		buf:        &bytes.Buffer{},
+	}
+	f.out = export.NewCSVWriter(f.buf)
+	f.service = NewExportService(f.adRepo, f.history, f.statistics, customLogger.Logger{})
+	return f
+}
+
+func TestService_ExportAds(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		f := newFixture()
+		created := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+		filter := &entities.AdFilter{Status: "approved"}
+		f.adRepo.On("Stream", mock.Anything, filter, mock.Anything).Run(func(args mock.Arguments) {
+			fn := args.Get(2).(func(*entities.Ad) error)
+			_ = fn(&entities.Ad{ID: 3, AuthorID: "author", Title: "Bike", Status: entities.StatusApproved,
+				IsActive: true, Price: 100, CreatedAt: created, UpdatedAt: created})
+		}).Return(nil)
+
+		assert.NoError(t, f.service.ExportAds(context.Background(), filter, f.out))
+		assert.NoError(t, f.out.Close())
+		assert.Equal(t, "id,author_id,title,description,category_id,status,is_active,price,location,"+
+			"created_at,updated_at\n"+
+			"3,author,Bike,,0,approved,true,100,,2025-03-01 10:00:00,2025-03-01 10:00:00\n", f.buf.String())
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		f := newFixture()
+		f.adRepo.On("Stream", mock.Anything, mock.Anything, mock.Anything).Return(repoerr.ErrGettingAllAds)
+
+		err := f.service.ExportAds(context.Background(), &entities.AdFilter{}, f.out)
+		assert.Equal(t, usecaseerr.ErrExporting, err)
+	})
+}
+
+func TestService_ExportDecisions(t *testing.T) {
+	t.Run("success with default period", func(t *testing.T) {
+		f := newFixture()
+		f.history.On("StreamDecisions", mock.Anything, mock.MatchedBy(func(filter entities.StatisticsFilter) bool {
+			return filter.To.Sub(filter.From) == entities.DefaultStatisticsPeriod
+		}), mock.Anything).Run(func(args mock.Arguments) {
+			fn := args.Get(2).(func(*entities.ModerationEvent) error)
+			_ = fn(&entities.ModerationEvent{ID: 1, AdID: 3, Action: entities.ActionRejected, ActorID: "mod",
+				RejectionCode: "duplicate", CreatedAt: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)})
+		}).Return(nil)
+
+		err := f.service.ExportDecisions(context.Background(), entities.StatisticsFilter{}, f.out)
+		assert.NoError(t, err)
+		assert.NoError(t, f.out.Close())
+		assert.Equal(t, "id,ad_id,action,moderator_id,rejection_code,note,created_at\n"+
+			"1,3,rejected,mod,duplicate,,2025-03-01 10:00:00\n", f.buf.String())
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		f := newFixture()
+		now := time.Now()
+
+		err := f.service.ExportDecisions(context.Background(),
+			entities.StatisticsFilter{From: now, To: now.Add(-time.Hour)}, f.out)
+		assert.Equal(t, usecaseerr.ErrInvalidStatistics, err)
+		assert.Empty(t, f.buf.String())
+	})
+}
+
+func TestService_ExportStatistics(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		f := newFixture()
+		f.statistics.On("GetStatistics", mock.Anything, entities.StatisticsFilter{}).Return(entities.AdStatistics{
+			Total: 4,
+			Moderation: entities.ModerationStatistics{
+				Granularity: entities.GranularityDay,
+				Categories:  []entities.CategoryStatistics{{ID: 2, Title: "Bikes", Approved: 1}},
+				BacklogAge:  []entities.BacklogBucket{{Label: "<1h", Count: 2}},
+			},
+		}, nil)
+
+		assert.NoError(t, f.service.ExportStatistics(context.Background(), entities.StatisticsFilter{}, f.out))
+		assert.NoError(t, f.out.Close())
+		csv := f.buf.String()
+		assert.Contains(t, csv, "metric,value\n")
+		assert.Contains(t, csv, "total_ads,4\n")
+		assert.Contains(t, csv, "categories\ncategory_id,title,submitted,approved,rejected,pending\n2,Bikes,0,1,0,0\n")
+		assert.Contains(t, csv, "backlog\nage,ads\n<1h,2\n")
+	})
+
+	t.Run("statistics error", func(t *testing.T) {
+		f := newFixture()
+		f.statistics.On("GetStatistics", mock.Anything, mock.Anything).
+			Return(entities.AdStatistics{}, usecaseerr.ErrInvalidStatistics)
+
+		err := f.service.ExportStatistics(context.Background(), entities.StatisticsFilter{}, f.out)
+		assert.Equal(t, usecaseerr.ErrInvalidStatistics, err)
+	})
+}
@@ -0,0 +1,33 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package export
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/pkg/export"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockExportService struct {
+	mock.Mock
+}
+
+func (m *MockExportService) ExportAds(ctx context.Context, filter *entities.AdFilter, out export.Writer) error {
+	args := m.Called(ctx, filter, out)
+	return args.Error(0)
+}
+
+func (m *MockExportService) ExportDecisions(ctx context.Context, filter entities.StatisticsFilter,
+	out export.Writer) error {
+	args := m.Called(ctx, filter, out)
+	return args.Error(0)
+}
+
+func (m *MockExportService) ExportStatistics(ctx context.Context, filter entities.StatisticsFilter,
+	out export.Writer) error {
+	args := m.Called(ctx, filter, out)
+	return args.Error(0)
+}
+
+var _ ExportService = (*MockExportService)(nil)
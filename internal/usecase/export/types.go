@@ -0,0 +1,38 @@
+package export
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/repository/ad"
+	"ads-service/internal/repository/history"
+	"ads-service/internal/usecase/admin"
+	"ads-service/pkg/export"
+	customLogger "ads-service/pkg/logger"
+	"context"
+)
+
+// ExportService writes reports for moderators as tables, the caller picks the format and closes the writer.
+type ExportService interface {
+	// ExportAds writes the ads matching the filter in ID order, Limit and Page are ignored.
+	ExportAds(ctx context.Context, filter *entities.AdFilter, out export.Writer) error
+	// ExportDecisions writes the approvals, rejections and appeal decisions of the period, oldest first.
+	ExportDecisions(ctx context.Context, filter entities.StatisticsFilter, out export.Writer) error
+	// ExportStatistics writes the statistics of the period, one table per breakdown.
+	ExportStatistics(ctx context.Context, filter entities.StatisticsFilter, out export.Writer) error
+}
+
+type service struct {
+	adRepo     ad.AdRepository
+	history    history.HistoryRepository
+	statistics admin.AdminAdvertisementService
+	logger     customLogger.Logger
+}
+
+func NewExportService(adRepo ad.AdRepository, historyRepo history.HistoryRepository,
+	statistics admin.AdminAdvertisementService, logTool customLogger.Logger) ExportService {
+	return &service{
+		adRepo:     adRepo,
+		history:    historyRepo,
+		statistics: statistics,
+		logger:     logTool,
+	}
+}
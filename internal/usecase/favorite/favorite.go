@@ -0,0 +1,76 @@
+package favorite
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/pkg/tracing"
+	"context"
+	"errors"
+	"fmt"
+)
+
+func (s *service) Add(ctx context.Context, userID string, adID int) (err error) {
+	ctx, span := tracing.Start(ctx, "favorite.Add")
+	defer tracing.End(span, &err)
+
+	if adID <= 0 {
+		return usecaseerr.ErrInvalidParams
+	}
+	ad, err := s.adRepo.GetByID(ctx, adID)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrAdNotFound) {
+			return usecaseerr.ErrAdNotAvailable
+		}
+		s.logger.ERROR("error getting ad for favorites: ", err)
+		return fmt.Errorf("%w: %w", usecaseerr.ErrAddingFavorite, err)
+	}
+	// drafts and rejected ads are not visible to other users
+	if ad.Status != entities.StatusApproved || !ad.IsActive {
+		return usecaseerr.ErrAdNotAvailable
+	}
+	if ad.AuthorID == userID {
+		return usecaseerr.ErrFavoriteOwnAd
+	}
+
+	if err = s.repo.Add(ctx, userID, adID, ad.Title); err != nil {
+		s.logger.ERROR("error adding favorite: ", err)
+		return usecaseerr.ErrAddingFavorite
+	}
+	return nil
+}
+
+func (s *service) Remove(ctx context.Context, userID string, adID int) (err error) {
+	ctx, span := tracing.Start(ctx, "favorite.Remove")
+	defer tracing.End(span, &err)
+
+	if adID <= 0 {
+		return usecaseerr.ErrInvalidParams
+	}
+	if err := s.repo.Delete(ctx, userID, adID); err != nil {
+		if errors.Is(err, repoerr.ErrFavoriteNotFound) {
+			return usecaseerr.ErrFavoriteNotFound
+		}
+		s.logger.ERROR("error removing favorite: ", err)
+		return usecaseerr.ErrRemovingFavorite
+	}
+	return nil
+}
+
+func (s *service) List(ctx context.Context, userID string) (_ []entities.Favorite, err error) {
+	ctx, span := tracing.Start(ctx, "favorite.List")
+	defer tracing.End(span, &err)
+
+	favorites, err := s.repo.GetByUser(ctx, userID)
+	if err != nil {
+		s.logger.ERROR("error getting favorites: ", err)
+		return nil, usecaseerr.ErrGettingFavorites
+	}
+	for i := range favorites {
+		// details of ads that are no longer published are not shown
+		if !favorites[i].Available {
+			favorites[i].Ad = nil
+		}
+	}
+	return favorites, nil
+}
@@ -0,0 +1,103 @@
+package favorite
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	adRepo "ads-service/internal/repository/ad"
+	"ads-service/internal/repository/favorite"
+	customLogger "ads-service/pkg/logger"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestService_Add(t *testing.T) {
+	published := &entities.Ad{ID: 1, AuthorID: "seller", Title: "Car", Status: entities.StatusApproved, IsActive: true}
+
+	t.Run("success", func(t *testing.T) {
+		repo := &favorite.MockFavoriteRepo{}
+		ads := &adRepo.MockAdRepo{}
+		defer repo.AssertExpectations(t)
+		service := NewFavoriteService(repo, ads, customLogger.Logger{})
+
+		ads.On("GetByID", mock.Anything, 1).Return(published, nil)
+		repo.On("Add", mock.Anything, "buyer", 1, "Car").Return(nil)
+
+		assert.NoError(t, service.Add(context.Background(), "buyer", 1))
+	})
+
+	t.Run("own ad", func(t *testing.T) {
+		ads := &adRepo.MockAdRepo{}
+		service := NewFavoriteService(&favorite.MockFavoriteRepo{}, ads, customLogger.Logger{})
+
+		ads.On("GetByID", mock.Anything, 1).Return(published, nil)
+
+		err := service.Add(context.Background(), "seller", 1)
+		assert.Equal(t, usecaseerr.ErrFavoriteOwnAd, err)
+	})
+
+	t.Run("pending ad", func(t *testing.T) {
+		ads := &adRepo.MockAdRepo{}
+		service := NewFavoriteService(&favorite.MockFavoriteRepo{}, ads, customLogger.Logger{})
+
+		ads.On("GetByID", mock.Anything, 2).Return(&entities.Ad{ID: 2, Status: entities.StatusPending}, nil)
+
+		err := service.Add(context.Background(), "buyer", 2)
+		assert.Equal(t, usecaseerr.ErrAdNotAvailable, err)
+	})
+
+	t.Run("ad not found", func(t *testing.T) {
+		ads := &adRepo.MockAdRepo{}
+		service := NewFavoriteService(&favorite.MockFavoriteRepo{}, ads, customLogger.Logger{})
+
+		ads.On("GetByID", mock.Anything, 3).Return(nil, repoerr.ErrAdNotFound)
+
+		err := service.Add(context.Background(), "buyer", 3)
+		assert.Equal(t, usecaseerr.ErrAdNotAvailable, err)
+	})
+}
+
+func TestService_Remove(t *testing.T) {
+	repo := &favorite.MockFavoriteRepo{}
+	defer repo.AssertExpectations(t)
+	service := NewFavoriteService(repo, &adRepo.MockAdRepo{}, customLogger.Logger{})
+
+	repo.On("Delete", mock.Anything, "buyer", 1).Return(repoerr.ErrFavoriteNotFound)
+
+	err := service.Remove(context.Background(), "buyer", 1)
+	assert.Equal(t, usecaseerr.ErrFavoriteNotFound, err)
+}
+
+func TestService_List(t *testing.T) {
+	t.Run("hides details of unavailable ads", func(t *testing.T) {
+		repo := &favorite.MockFavoriteRepo{}
+		defer repo.AssertExpectations(t)
+		service := NewFavoriteService(repo, &adRepo.MockAdRepo{}, customLogger.Logger{})
+
+		repo.On("GetByUser", mock.Anything, "buyer").Return([]entities.Favorite{
+			{AdID: 1, Title: "Car", Available: true, Ad: &entities.Ad{ID: 1}},
+			{AdID: 2, Title: "Bike", Ad: &entities.Ad{ID: 2, Status: entities.StatusRejected}},
+			{AdID: 3, Title: "Deleted"},
+		}, nil)
+
+		favorites, err := service.List(context.Background(), "buyer")
+		assert.NoError(t, err)
+		assert.NotNil(t, favorites[0].Ad)
+		assert.Nil(t, favorites[1].Ad)
+		assert.False(t, favorites[2].Available)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		repo := &favorite.MockFavoriteRepo{}
+		service := NewFavoriteService(repo, &adRepo.MockAdRepo{}, customLogger.Logger{})
+
+		repo.On("GetByUser", mock.Anything, "buyer").Return(nil, repoerr.ErrFavoriteSelect)
+
+		favorites, err := service.List(context.Background(), "buyer")
+		assert.Nil(t, favorites)
+		assert.Equal(t, usecaseerr.ErrGettingFavorites, err)
+	})
+}
@@ -0,0 +1,33 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package favorite
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockFavoriteService struct {
+	mock.Mock
+}
+
+func (m *MockFavoriteService) Add(ctx context.Context, userID string, adID int) error {
+	args := m.Called(ctx, userID, adID)
+	return args.Error(0)
+}
+
+func (m *MockFavoriteService) Remove(ctx context.Context, userID string, adID int) error {
+	args := m.Called(ctx, userID, adID)
+	return args.Error(0)
+}
+
+func (m *MockFavoriteService) List(ctx context.Context, userID string) ([]entities.Favorite, error) {
+	args := m.Called(ctx, userID)
+	if favorites, ok := args.Get(0).([]entities.Favorite); ok {
+		return favorites, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+var _ FavoriteService = (*MockFavoriteService)(nil)
@@ -0,0 +1,31 @@
+package favorite
+
+import (
+	"ads-service/internal/domain/entities"
+	adRepo "ads-service/internal/repository/ad"
+	"ads-service/internal/repository/favorite"
+	customLogger "ads-service/pkg/logger"
+	"context"
+)
+
+type FavoriteService interface {
+	// Add bookmarks a published ad of another user.
+	Add(ctx context.Context, userID string, adID int) error
+	Remove(ctx context.Context, userID string, adID int) error
+	List(ctx context.Context, userID string) ([]entities.Favorite, error)
+}
+
+type service struct {
+	repo   favorite.FavoriteRepository
+	adRepo adRepo.AdRepository
+	logger customLogger.Logger
+}
+
+func NewFavoriteService(repo favorite.FavoriteRepository, adRepository adRepo.AdRepository,
+	logTool customLogger.Logger) FavoriteService {
+	return &service{
+		repo:   repo,
+		adRepo: adRepository,
+		logger: logTool,
+	}
+}
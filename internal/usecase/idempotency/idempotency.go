@@ -0,0 +1,94 @@
+package idempotency
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/pkg/tracing"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// pendingTimeout - a request running longer than this is taken to have died with the server,
+// its key is handed to the next retry.
+const pendingTimeout = 5 * time.Minute
+
+func (s *service) Begin(ctx context.Context, userID, key,
+	fingerprint string) (_ *entities.IdempotentRequest, err error) {
+	ctx, span := tracing.Start(ctx, "idempotency.Begin")
+	defer tracing.End(span, &err)
+
+	now := time.Now().UTC()
+	reserved, err := s.repo.Reserve(ctx, &entities.IdempotentRequest{
+		UserID:      userID,
+		Key:         key,
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.config.TTL),
+	}, now.Add(-pendingTimeout))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", usecaseerr.ErrIdempotency, err)
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	stored, err := s.repo.Get(ctx, userID, key)
+	if errors.Is(err, repoerr.ErrIdempotencyKeyNotFound) {
+		// the first request failed and released the key just now
+		return nil, usecaseerr.ErrIdempotencyKeyInProgress
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", usecaseerr.ErrIdempotency, err)
+	}
+	if stored.Fingerprint != fingerprint {
+		s.logger.INFO("idempotency key reused with a different request, user ", userID)
+		return nil, usecaseerr.ErrIdempotencyKeyReused
+	}
+	if !stored.Done() {
+		return nil, usecaseerr.ErrIdempotencyKeyInProgress
+	}
+	return stored, nil
+}
+
+func (s *service) Complete(ctx context.Context, req *entities.IdempotentRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "idempotency.Complete")
+	defer tracing.End(span, &err)
+
+	if err := s.repo.Complete(ctx, req); err != nil {
+		s.logger.ERROR("error storing idempotent response: ", err)
+		return fmt.Errorf("%w: %w", usecaseerr.ErrIdempotency, err)
+	}
+	return nil
+}
+
+func (s *service) Release(ctx context.Context, userID, key string) (err error) {
+	ctx, span := tracing.Start(ctx, "idempotency.Release")
+	defer tracing.End(span, &err)
+
+	if err := s.repo.Delete(ctx, userID, key); err != nil {
+		s.logger.ERROR("error releasing idempotency key: ", err)
+		return fmt.Errorf("%w: %w", usecaseerr.ErrIdempotency, err)
+	}
+	return nil
+}
+
+func (s *service) RunCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.repo.DeleteExpired(ctx, time.Now().UTC())
+			if err != nil {
+				s.logger.ERROR("idempotency key cleanup: ", err)
+				continue
+			}
+			s.logger.INFO("expired idempotency keys deleted: ", deleted)
+		}
+	}
+}
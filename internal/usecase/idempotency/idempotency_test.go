@@ -0,0 +1,93 @@
+package idempotency
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/repository/idempotency"
+	customLogger "ads-service/pkg/logger"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestService(repo *idempotency.MockIdempotencyRepo) IdempotencyService {
+	return NewIdempotencyService(repo, Config{TTL: time.Hour}, customLogger.Logger{})
+}
+
+func TestService_Begin(t *testing.T) {
+	t.Run("new key", func(t *testing.T) {
+		repo := &idempotency.MockIdempotencyRepo{}
+		defer repo.AssertExpectations(t)
+
+		repo.On("Reserve", mock.Anything, mock.MatchedBy(func(req *entities.IdempotentRequest) bool {
+			return req.UserID == "u1" && req.Key == "k1" && req.Fingerprint == "f1" &&
+				req.ExpiresAt.Sub(req.CreatedAt) == time.Hour
+		}), mock.AnythingOfType("time.Time")).Return(true, nil)
+
+		stored, err := newTestService(repo).Begin(context.Background(), "u1", "k1", "f1")
+		assert.NoError(t, err)
+		assert.Nil(t, stored)
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		repo := &idempotency.MockIdempotencyRepo{}
+		defer repo.AssertExpectations(t)
+
+		done := &entities.IdempotentRequest{Fingerprint: "f1", Status: 201, Body: []byte(`{"id":7}`)}
+		repo.On("Reserve", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+		repo.On("Get", mock.Anything, "u1", "k1").Return(done, nil)
+
+		stored, err := newTestService(repo).Begin(context.Background(), "u1", "k1", "f1")
+		assert.NoError(t, err)
+		assert.Same(t, done, stored)
+	})
+
+	t.Run("different request", func(t *testing.T) {
+		repo := &idempotency.MockIdempotencyRepo{}
+		repo.On("Reserve", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+		repo.On("Get", mock.Anything, "u1", "k1").
+			Return(&entities.IdempotentRequest{Fingerprint: "other", Status: 201}, nil)
+
+		_, err := newTestService(repo).Begin(context.Background(), "u1", "k1", "f1")
+		assert.ErrorIs(t, err, usecaseerr.ErrIdempotencyKeyReused)
+	})
+
+	t.Run("still running", func(t *testing.T) {
+		repo := &idempotency.MockIdempotencyRepo{}
+		repo.On("Reserve", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+		repo.On("Get", mock.Anything, "u1", "k1").Return(&entities.IdempotentRequest{Fingerprint: "f1"}, nil)
+
+		_, err := newTestService(repo).Begin(context.Background(), "u1", "k1", "f1")
+		assert.ErrorIs(t, err, usecaseerr.ErrIdempotencyKeyInProgress)
+	})
+
+	t.Run("released meanwhile", func(t *testing.T) {
+		repo := &idempotency.MockIdempotencyRepo{}
+		repo.On("Reserve", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+		repo.On("Get", mock.Anything, "u1", "k1").Return(nil, repoerr.ErrIdempotencyKeyNotFound)
+
+		_, err := newTestService(repo).Begin(context.Background(), "u1", "k1", "f1")
+		assert.ErrorIs(t, err, usecaseerr.ErrIdempotencyKeyInProgress)
+	})
+
+	t.Run("reserve error", func(t *testing.T) {
+		repo := &idempotency.MockIdempotencyRepo{}
+		repo.On("Reserve", mock.Anything, mock.Anything, mock.Anything).
+			Return(false, repoerr.ErrIdempotencyKeySave)
+
+		_, err := newTestService(repo).Begin(context.Background(), "u1", "k1", "f1")
+		assert.ErrorIs(t, err, usecaseerr.ErrIdempotency)
+	})
+}
+
+func TestService_Release(t *testing.T) {
+	repo := &idempotency.MockIdempotencyRepo{}
+	defer repo.AssertExpectations(t)
+	repo.On("Delete", mock.Anything, "u1", "k1").Return(nil)
+
+	assert.NoError(t, newTestService(repo).Release(context.Background(), "u1", "k1"))
+}
@@ -0,0 +1,39 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package idempotency
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIdempotencyService struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyService) Begin(ctx context.Context, userID, key,
+	fingerprint string) (*entities.IdempotentRequest, error) {
+	args := m.Called(ctx, userID, key, fingerprint)
+	if req, ok := args.Get(0).(*entities.IdempotentRequest); ok {
+		return req, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockIdempotencyService) Complete(ctx context.Context, req *entities.IdempotentRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyService) Release(ctx context.Context, userID, key string) error {
+	args := m.Called(ctx, userID, key)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyService) RunCleanup(ctx context.Context, interval time.Duration) {
+	m.Called(ctx, interval)
+}
+
+var _ IdempotencyService = (*MockIdempotencyService)(nil)
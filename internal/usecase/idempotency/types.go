@@ -0,0 +1,43 @@
+package idempotency
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/repository/idempotency"
+	customLogger "ads-service/pkg/logger"
+	"context"
+	"time"
+)
+
+type IdempotencyService interface {
+	// Begin reserves the key of the user for the request with the fingerprint and returns nil, the request
+	// may run then. For a key that was used before it returns the finished request to replay its response,
+	// usecaseerr.ErrIdempotencyKeyReused when the fingerprint differs and
+	// usecaseerr.ErrIdempotencyKeyInProgress while the first request is still running.
+	Begin(ctx context.Context, userID, key, fingerprint string) (*entities.IdempotentRequest, error)
+	// Complete stores the response of a request begun with Begin for the TTL.
+	Complete(ctx context.Context, req *entities.IdempotentRequest) error
+	// Release frees the key of a request that failed, so that a retry runs it again.
+	Release(ctx context.Context, userID, key string) error
+	// RunCleanup deletes expired keys every interval until ctx is cancelled.
+	RunCleanup(ctx context.Context, interval time.Duration)
+}
+
+// Config - TTL is how long a response is replayed, the key may be used for another request after it.
+type Config struct {
+	TTL time.Duration
+}
+
+type service struct {
+	repo   idempotency.IdempotencyRepository
+	config Config
+	logger customLogger.Logger
+}
+
+func NewIdempotencyService(repo idempotency.IdempotencyRepository, config Config,
+	logTool customLogger.Logger) IdempotencyService {
+	return &service{
+		repo:   repo,
+		config: config,
+		logger: logTool,
+	}
+}
@@ -0,0 +1,139 @@
+package lockout
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/pkg/tracing"
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	maxPhoneFailures = 5                // failures per phone before it gets locked
+	maxIPFailures    = 20               // failures per IP before it gets locked, higher because of NAT
+	baseLockout      = time.Minute      // first lockout, doubled on every further failure
+	maxLockout       = time.Hour        // upper bound for the exponential backoff
+	failureWindow    = 15 * time.Minute // failures older than this are forgotten
+)
+
+// reservation - an attempt counted for a key before the credentials are checked, with the lock it set.
+type reservation struct {
+	kind        entities.LockoutKind
+	key         string
+	lockedUntil time.Time
+}
+
+// Reserve - an attempt reaching the limit locks the key right away. The keys are counted in one transaction
+// in a fixed order, a concurrent attempt waits for it and then sees the lock. A locked key counts nothing.
+func (s *service) Reserve(ctx context.Context, phone, ip string) (*Attempt, error) {
+	now := time.Now().UTC()
+	attempt := &Attempt{}
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		attempt.reserved = attempt.reserved[:0]
+		for _, r := range lockoutKeys(phone, ip) {
+			counter, err := s.repo.Reserve(ctx, r.kind, r.key, now, now.Add(-failureWindow))
+			if errors.Is(err, repoerr.ErrLockedOut) {
+				return usecaseerr.ErrTooManyAttempts
+			}
+			if err != nil {
+				return err
+			}
+			if lock := lockoutDuration(r.kind, counter.Failures); lock > 0 {
+				r.lockedUntil = now.Add(lock)
+				if err = s.repo.Lock(ctx, r.kind, r.key, r.lockedUntil); err != nil {
+					return err
+				}
+				s.logger.WARN("Locked out ", r.kind, ": ", r.key, " until ", r.lockedUntil)
+			}
+			attempt.reserved = append(attempt.reserved, r)
+		}
+		return nil
+	})
+	if errors.Is(err, usecaseerr.ErrTooManyAttempts) {
+		return nil, err
+	}
+	if err != nil {
+		s.logger.ERROR("Error checking lockout:", err)
+		return nil, usecaseerr.ErrCheckLockout
+	}
+	return attempt, nil
+}
+
+// Release - errors are only logged: the attempt is already decided.
+func (s *service) Release(ctx context.Context, attempt *Attempt) {
+	for _, r := range attempt.reserved {
+		if err := s.repo.Release(ctx, r.kind, r.key, r.lockedUntil); err != nil {
+			s.logger.ERROR("Error releasing login attempt:", err)
+		}
+	}
+}
+
+// Reset keeps the IP counter: a login to one's own account must not clear the failures an attacker collected
+// from the same IP.
+func (s *service) Reset(ctx context.Context, phone string) {
+	err := s.repo.Delete(ctx, entities.LockoutByPhone, phone)
+	if err != nil && !errors.Is(err, repoerr.ErrLockoutNotFound) {
+		s.logger.ERROR("Error resetting login attempts:", err)
+	}
+}
+
+func (s *service) GetLockouts(ctx context.Context) (_ []entities.LoginAttempt, err error) {
+	ctx, span := tracing.Start(ctx, "lockout.GetLockouts")
+	defer tracing.End(span, &err)
+
+	lockouts, err := s.repo.GetLocked(ctx, time.Now().UTC())
+	if err != nil {
+		s.logger.ERROR("Error getting lockouts:", err)
+		return nil, usecaseerr.ErrGettingLockouts
+	}
+	return lockouts, nil
+}
+
+func (s *service) ClearLockout(ctx context.Context, kind entities.LockoutKind, key string) (err error) {
+	ctx, span := tracing.Start(ctx, "lockout.ClearLockout")
+	defer tracing.End(span, &err)
+
+	if (kind != entities.LockoutByPhone && kind != entities.LockoutByIP) || key == "" {
+		return usecaseerr.ErrInvalidParams
+	}
+	if err := s.repo.Delete(ctx, kind, key); err != nil {
+		if errors.Is(err, repoerr.ErrLockoutNotFound) {
+			return usecaseerr.ErrLockoutNotFound
+		}
+		s.logger.ERROR("Error clearing lockout:", err)
+		return usecaseerr.ErrClearingLockout
+	}
+	s.logger.INFO("Lockout cleared for ", kind, ": ", key)
+	return nil
+}
+
+// lockoutDuration returns how long the key is locked after the given number of failures.
+func lockoutDuration(kind entities.LockoutKind, failures int) time.Duration {
+	limit := maxPhoneFailures
+	if kind == entities.LockoutByIP {
+		limit = maxIPFailures
+	}
+	if failures < limit {
+		return 0
+	}
+	lock := baseLockout
+	for range failures - limit {
+		lock *= 2
+		if lock >= maxLockout {
+			return maxLockout
+		}
+	}
+	return lock
+}
+
+// lockoutKeys lists the keys of an attempt, always in the same order so that concurrent attempts lock the
+// counters one after another.
+func lockoutKeys(phone, ip string) []reservation {
+	keys := []reservation{{kind: entities.LockoutByPhone, key: phone}}
+	if ip != "" {
+		keys = append(keys, reservation{kind: entities.LockoutByIP, key: ip})
+	}
+	return keys
+}
@@ -0,0 +1,130 @@
+package lockout
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/repository/lockout"
+	"ads-service/pkg/db"
+	customLogger "ads-service/pkg/logger"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func inTx() *db.MockTxManager {
+	m := &db.MockTxManager{}
+	m.On("WithinTx", mock.Anything).Return(nil).Maybe()
+	return m
+}
+
+func TestLockoutDuration(t *testing.T) {
+	assert.Equal(t, time.Duration(0), lockoutDuration(entities.LockoutByPhone, maxPhoneFailures-1))
+	assert.Equal(t, baseLockout, lockoutDuration(entities.LockoutByPhone, maxPhoneFailures))
+	assert.Equal(t, 2*baseLockout, lockoutDuration(entities.LockoutByPhone, maxPhoneFailures+1))
+	assert.Equal(t, maxLockout, lockoutDuration(entities.LockoutByPhone, maxPhoneFailures+30))
+	assert.Equal(t, time.Duration(0), lockoutDuration(entities.LockoutByIP, maxPhoneFailures))
+}
+
+func TestLockoutService_Reserve(t *testing.T) {
+	t.Run("limit reached locks the key", func(t *testing.T) {
+		mockRepo := &lockout.MockLockoutRepo{}
+		defer mockRepo.AssertExpectations(t)
+		service := NewLockoutService(mockRepo, inTx(), customLogger.Logger{})
+
+		mockRepo.On("Reserve", mock.Anything, entities.LockoutByPhone, "+79999999999", mock.Anything,
+			mock.Anything).Return(&entities.LoginAttempt{Failures: maxPhoneFailures}, nil)
+		mockRepo.On("Lock", mock.Anything, entities.LockoutByPhone, "+79999999999", mock.Anything).Return(nil)
+		mockRepo.On("Reserve", mock.Anything, entities.LockoutByIP, "10.0.0.1", mock.Anything,
+			mock.Anything).Return(&entities.LoginAttempt{Failures: 1}, nil)
+
+		attempt, err := service.Reserve(context.Background(), "+79999999999", "10.0.0.1")
+		assert.NoError(t, err)
+		assert.Len(t, attempt.reserved, 2)
+		assert.False(t, attempt.reserved[0].lockedUntil.IsZero())
+		assert.True(t, attempt.reserved[1].lockedUntil.IsZero())
+	})
+
+	t.Run("locked out", func(t *testing.T) {
+		mockRepo := &lockout.MockLockoutRepo{}
+		service := NewLockoutService(mockRepo, inTx(), customLogger.Logger{})
+
+		mockRepo.On("Reserve", mock.Anything, entities.LockoutByPhone, "+79999999999", mock.Anything,
+			mock.Anything).Return(nil, repoerr.ErrLockedOut)
+
+		attempt, err := service.Reserve(context.Background(), "+79999999999", "10.0.0.1")
+		assert.Nil(t, attempt)
+		assert.Equal(t, usecaseerr.ErrTooManyAttempts, err)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		mockRepo := &lockout.MockLockoutRepo{}
+		service := NewLockoutService(mockRepo, inTx(), customLogger.Logger{})
+
+		mockRepo.On("Reserve", mock.Anything, entities.LockoutByPhone, "+79999999999", mock.Anything,
+			mock.Anything).Return(nil, assert.AnError)
+
+		attempt, err := service.Reserve(context.Background(), "+79999999999", "")
+		assert.Nil(t, attempt)
+		assert.Equal(t, usecaseerr.ErrCheckLockout, err)
+	})
+}
+
+func TestLockoutService_ClearLockout(t *testing.T) {
+	t.Run("invalid kind", func(t *testing.T) {
+		service := NewLockoutService(&lockout.MockLockoutRepo{}, inTx(), customLogger.Logger{})
+
+		err := service.ClearLockout(context.Background(), "email", "x")
+		assert.Equal(t, usecaseerr.ErrInvalidParams, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockRepo := &lockout.MockLockoutRepo{}
+		defer mockRepo.AssertExpectations(t)
+		service := NewLockoutService(mockRepo, inTx(), customLogger.Logger{})
+
+		mockRepo.On("Delete", mock.Anything, entities.LockoutByIP, "10.0.0.1").
+			Return(repoerr.ErrLockoutNotFound)
+
+		err := service.ClearLockout(context.Background(), entities.LockoutByIP, "10.0.0.1")
+		assert.Equal(t, usecaseerr.ErrLockoutNotFound, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo := &lockout.MockLockoutRepo{}
+		defer mockRepo.AssertExpectations(t)
+		service := NewLockoutService(mockRepo, inTx(), customLogger.Logger{})
+
+		mockRepo.On("Delete", mock.Anything, entities.LockoutByPhone, "+79999999999").Return(nil)
+
+		err := service.ClearLockout(context.Background(), entities.LockoutByPhone, "+79999999999")
+		assert.NoError(t, err)
+	})
+}
+
+func TestLockoutService_GetLockouts(t *testing.T) {
+	t.Run("repo error", func(t *testing.T) {
+		mockRepo := &lockout.MockLockoutRepo{}
+		service := NewLockoutService(mockRepo, inTx(), customLogger.Logger{})
+
+		mockRepo.On("GetLocked", mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		lockouts, err := service.GetLockouts(context.Background())
+		assert.Nil(t, lockouts)
+		assert.Equal(t, usecaseerr.ErrGettingLockouts, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo := &lockout.MockLockoutRepo{}
+		service := NewLockoutService(mockRepo, inTx(), customLogger.Logger{})
+
+		mockRepo.On("GetLocked", mock.Anything, mock.Anything).
+			Return([]entities.LoginAttempt{{Kind: entities.LockoutByIP, Key: "10.0.0.1"}}, nil)
+
+		lockouts, err := service.GetLockouts(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, lockouts, 1)
+	})
+}
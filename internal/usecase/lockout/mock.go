@@ -0,0 +1,44 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package lockout
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLockoutService struct {
+	mock.Mock
+}
+
+func (m *MockLockoutService) Reserve(ctx context.Context, phone, ip string) (*Attempt, error) {
+	args := m.Called(ctx, phone, ip)
+	if attempt, ok := args.Get(0).(*Attempt); ok {
+		return attempt, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockLockoutService) Release(ctx context.Context, attempt *Attempt) {
+	m.Called(ctx, attempt)
+}
+
+func (m *MockLockoutService) Reset(ctx context.Context, phone string) {
+	m.Called(ctx, phone)
+}
+
+func (m *MockLockoutService) GetLockouts(ctx context.Context) ([]entities.LoginAttempt, error) {
+	args := m.Called(ctx)
+	if lockouts, ok := args.Get(0).([]entities.LoginAttempt); ok {
+		return lockouts, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockLockoutService) ClearLockout(ctx context.Context, kind entities.LockoutKind, key string) error {
+	args := m.Called(ctx, kind, key)
+	return args.Error(0)
+}
+
+var _ LockoutService = (*MockLockoutService)(nil)
@@ -0,0 +1,43 @@
+package lockout
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/repository/lockout"
+	"ads-service/pkg/db"
+	customLogger "ads-service/pkg/logger"
+	"context"
+)
+
+// LockoutService limits guessing of passwords and two-factor codes per phone and per IP.
+type LockoutService interface {
+	// Reserve counts an attempt for the phone and the IP before the credentials are checked, so parallel
+	// guesses can't all pass the lockout before any of them fails. A locked key gives ErrTooManyAttempts.
+	// The attempt stays counted as a failure unless it is released.
+	Reserve(ctx context.Context, phone, ip string) (*Attempt, error)
+	// Release takes back an attempt whose credentials were right, or that failed for another reason.
+	Release(ctx context.Context, attempt *Attempt)
+	// Reset forgets the failures of the phone after a successful login.
+	Reset(ctx context.Context, phone string)
+	GetLockouts(ctx context.Context) ([]entities.LoginAttempt, error)
+	ClearLockout(ctx context.Context, kind entities.LockoutKind, key string) error
+}
+
+// Attempt - the keys counted by Reserve, with the locks it set.
+type Attempt struct {
+	reserved []reservation
+}
+
+type service struct {
+	repo   lockout.LockoutRepository
+	tx     db.TxManager
+	logger customLogger.Logger
+}
+
+func NewLockoutService(repo lockout.LockoutRepository, tx db.TxManager,
+	logTool customLogger.Logger) LockoutService {
+	return &service{
+		repo:   repo,
+		tx:     tx,
+		logger: logTool,
+	}
+}
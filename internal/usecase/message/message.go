@@ -0,0 +1,269 @@
+package message
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/pkg/tracing"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	defaultPageSize  = 20
+	maxPageSize      = 100
+	maxMessageLength = 2000
+)
+
+func (s *service) StartConversation(ctx context.Context, buyerID string, adID int,
+	body string) (_ *entities.Conversation, _ *entities.Message, err error) {
+	ctx, span := tracing.Start(ctx, "message.StartConversation")
+	defer tracing.End(span, &err)
+
+	body, err = normalizeBody(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if adID <= 0 {
+		return nil, nil, usecaseerr.ErrInvalidParams
+	}
+
+	ad, err := s.adRepo.GetByID(ctx, adID)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrAdNotFound) {
+			return nil, nil, usecaseerr.ErrAdNotAvailable
+		}
+		s.logger.ERROR("error getting ad for conversation: ", err)
+		return nil, nil, fmt.Errorf("%w: %w", usecaseerr.ErrSendingMessage, err)
+	}
+	if ad.Status != entities.StatusApproved || !ad.IsActive {
+		return nil, nil, usecaseerr.ErrAdNotAvailable
+	}
+	if ad.AuthorID == buyerID {
+		return nil, nil, usecaseerr.ErrMessageOwnAd
+	}
+	if err = s.checkBlocked(ctx, ad.AuthorID, buyerID); err != nil {
+		return nil, nil, err
+	}
+
+	id, err := s.repo.GetOrCreateConversation(ctx, &entities.Conversation{
+		BuyerID:  buyerID,
+		SellerID: ad.AuthorID,
+		AdTitle:  ad.Title,
+		AdID:     ad.ID,
+	})
+	if err != nil {
+		s.logger.ERROR("error creating conversation: ", err)
+		return nil, nil, usecaseerr.ErrSendingMessage
+	}
+	conv, err := s.repo.GetConversation(ctx, id)
+	if err != nil {
+		s.logger.ERROR("error getting conversation: ", err)
+		return nil, nil, usecaseerr.ErrSendingMessage
+	}
+
+	msg := &entities.Message{ConversationID: id, SenderID: buyerID, Body: body}
+	if err = s.repo.AddMessage(ctx, msg); err != nil {
+		s.logger.ERROR("error adding message: ", err)
+		return nil, nil, usecaseerr.ErrSendingMessage
+	}
+	s.announceMessage(ctx, conv, msg)
+	return conv, msg, nil
+}
+
+func (s *service) SendMessage(ctx context.Context, userID string, conversationID int,
+	body string) (_ *entities.Message, err error) {
+	ctx, span := tracing.Start(ctx, "message.SendMessage")
+	defer tracing.End(span, &err)
+
+	body, err = normalizeBody(body)
+	if err != nil {
+		return nil, err
+	}
+	conv, err := s.participantConversation(ctx, userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if conv.Locked {
+		return nil, usecaseerr.ErrConversationLocked
+	}
+	if userID == conv.BuyerID && conv.Blocked {
+		return nil, usecaseerr.ErrUserBlocked
+	}
+
+	msg := &entities.Message{ConversationID: conversationID, SenderID: userID, Body: body}
+	if err = s.repo.AddMessage(ctx, msg); err != nil {
+		s.logger.ERROR("error adding message: ", err)
+		return nil, usecaseerr.ErrSendingMessage
+	}
+	s.announceMessage(ctx, conv, msg)
+	return msg, nil
+}
+
+func (s *service) ListConversations(ctx context.Context, userID string,
+	page, limit int) (_ []entities.Conversation, err error) {
+	ctx, span := tracing.Start(ctx, "message.ListConversations")
+	defer tracing.End(span, &err)
+
+	limit = pageSize(limit)
+	if page < 1 {
+		page = 1
+	}
+	conversations, err := s.repo.ListConversations(ctx, userID, limit, (page-1)*limit)
+	if err != nil {
+		s.logger.ERROR("error getting conversations: ", err)
+		return nil, usecaseerr.ErrGettingConversations
+	}
+	return conversations, nil
+}
+
+func (s *service) GetMessages(ctx context.Context, userID string, conversationID int,
+	beforeID int64, limit int) (_ []entities.Message, err error) {
+	ctx, span := tracing.Start(ctx, "message.GetMessages")
+	defer tracing.End(span, &err)
+
+	if beforeID < 0 {
+		return nil, usecaseerr.ErrInvalidParams
+	}
+	if _, err := s.participantConversation(ctx, userID, conversationID); err != nil {
+		return nil, err
+	}
+
+	messages, err := s.repo.ListMessages(ctx, conversationID, beforeID, pageSize(limit))
+	if err != nil {
+		s.logger.ERROR("error getting messages: ", err)
+		return nil, usecaseerr.ErrGettingMessages
+	}
+	// opening the conversation is what the sender sees as a read receipt
+	if _, err = s.repo.MarkRead(ctx, conversationID, userID); err != nil {
+		s.logger.ERROR("error marking messages as read: ", err)
+	}
+	return messages, nil
+}
+
+func (s *service) UnreadCount(ctx context.Context, userID string) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "message.UnreadCount")
+	defer tracing.End(span, &err)
+
+	count, err := s.repo.UnreadCount(ctx, userID)
+	if err != nil {
+		s.logger.ERROR("error counting unread messages: ", err)
+		return 0, usecaseerr.ErrGettingConversations
+	}
+	return count, nil
+}
+
+func (s *service) BlockBuyer(ctx context.Context, sellerID string, conversationID int) (err error) {
+	ctx, span := tracing.Start(ctx, "message.BlockBuyer")
+	defer tracing.End(span, &err)
+
+	conv, err := s.participantConversation(ctx, sellerID, conversationID)
+	if err != nil {
+		return err
+	}
+	if conv.SellerID != sellerID {
+		return usecaseerr.ErrAccessDenied
+	}
+	if err = s.repo.Block(ctx, sellerID, conv.BuyerID); err != nil {
+		s.logger.ERROR("error blocking buyer: ", err)
+		return usecaseerr.ErrBlockingUser
+	}
+	return nil
+}
+
+func (s *service) UnblockBuyer(ctx context.Context, sellerID string, conversationID int) (err error) {
+	ctx, span := tracing.Start(ctx, "message.UnblockBuyer")
+	defer tracing.End(span, &err)
+
+	conv, err := s.participantConversation(ctx, sellerID, conversationID)
+	if err != nil {
+		return err
+	}
+	if conv.SellerID != sellerID {
+		return usecaseerr.ErrAccessDenied
+	}
+	if err = s.repo.Unblock(ctx, sellerID, conv.BuyerID); err != nil {
+		if errors.Is(err, repoerr.ErrBlockNotFound) {
+			return usecaseerr.ErrNotBlocked
+		}
+		s.logger.ERROR("error unblocking buyer: ", err)
+		return usecaseerr.ErrBlockingUser
+	}
+	return nil
+}
+
+// participantConversation hides conversations of other users behind "not found".
+func (s *service) participantConversation(ctx context.Context, userID string,
+	conversationID int) (*entities.Conversation, error) {
+	if conversationID <= 0 {
+		return nil, usecaseerr.ErrInvalidParams
+	}
+	conv, err := s.repo.GetConversation(ctx, conversationID)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrConversationNotFound) {
+			return nil, usecaseerr.ErrConversationNotFound
+		}
+		s.logger.ERROR("error getting conversation: ", err)
+		return nil, usecaseerr.ErrGettingConversations
+	}
+	if !conv.HasParticipant(userID) {
+		return nil, usecaseerr.ErrConversationNotFound
+	}
+	return conv, nil
+}
+
+// announceMessage tells the recipient about a saved message, failures are only logged.
+func (s *service) announceMessage(ctx context.Context, conv *entities.Conversation, msg *entities.Message) {
+	recipient := conv.Counterpart(msg.SenderID)
+	s.events.Publish(ctx, entities.Event{
+		Type:   entities.EventNewMessage,
+		UserID: recipient,
+		Data: entities.EventData{
+			"conversation_id": conv.ID,
+			"message_id":      msg.ID,
+			"sender_id":       msg.SenderID,
+			"ad_title":        conv.AdTitle,
+			"body":            msg.Body,
+		},
+	})
+
+	err := s.notifier.Notify(ctx, entities.Notification{
+		Kind:    entities.NotificationMessage,
+		UserID:  recipient,
+		Subject: fmt.Sprintf("New message about %q", conv.AdTitle),
+		Body:    msg.Body,
+	})
+	if err != nil {
+		s.logger.ERROR("error notifying about message: ", err)
+	}
+}
+
+func (s *service) checkBlocked(ctx context.Context, sellerID, buyerID string) error {
+	blocked, err := s.repo.IsBlocked(ctx, sellerID, buyerID)
+	if err != nil {
+		s.logger.ERROR("error checking block: ", err)
+		return usecaseerr.ErrSendingMessage
+	}
+	if blocked {
+		return usecaseerr.ErrUserBlocked
+	}
+	return nil
+}
+
+func normalizeBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || utf8.RuneCountInString(body) > maxMessageLength {
+		return "", usecaseerr.ErrInvalidMessage
+	}
+	return body, nil
+}
+
+func pageSize(limit int) int {
+	if limit <= 0 {
+		return defaultPageSize
+	}
+	return min(limit, maxPageSize)
+}
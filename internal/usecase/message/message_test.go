@@ -0,0 +1,211 @@
+package message
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	adRepo "ads-service/internal/repository/ad"
+	"ads-service/internal/repository/message"
+	"ads-service/pkg/eventbus"
+	customLogger "ads-service/pkg/logger"
+	"ads-service/pkg/notifier"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestService() (*service, *message.MockMessageRepo, *adRepo.MockAdRepo) {
+	repo := &message.MockMessageRepo{}
+	ads := &adRepo.MockAdRepo{}
+	notify := &notifier.MockNotifier{}
+	notify.On("Notify", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return NewMessageService(repo, ads, eventbus.New(), notify, customLogger.Logger{}).(*service), repo, ads
+}
+
+func TestService_StartConversation(t *testing.T) {
+	published := &entities.Ad{ID: 1, AuthorID: "seller", Title: "Car", Status: entities.StatusApproved, IsActive: true}
+
+	t.Run("success", func(t *testing.T) {
+		svc, repo, ads := newTestService()
+		defer repo.AssertExpectations(t)
+
+		ads.On("GetByID", mock.Anything, 1).Return(published, nil)
+		repo.On("IsBlocked", mock.Anything, "seller", "buyer").Return(false, nil)
+		repo.On("GetOrCreateConversation", mock.Anything, mock.MatchedBy(func(c *entities.Conversation) bool {
+			return c.BuyerID == "buyer" && c.SellerID == "seller" && c.AdTitle == "Car"
+		})).Return(5, nil)
+		repo.On("GetConversation", mock.Anything, 5).
+			Return(&entities.Conversation{ID: 5, BuyerID: "buyer", SellerID: "seller", AdID: 1}, nil)
+		repo.On("AddMessage", mock.Anything, mock.MatchedBy(func(m *entities.Message) bool {
+			return m.ConversationID == 5 && m.Body == "Is it available?"
+		})).Return(nil)
+
+		conv, msg, err := svc.StartConversation(context.Background(), "buyer", 1, "  Is it available? ")
+		assert.NoError(t, err)
+		assert.Equal(t, 5, conv.ID)
+		assert.Equal(t, "buyer", msg.SenderID)
+	})
+
+	t.Run("own ad", func(t *testing.T) {
+		svc, _, ads := newTestService()
+		ads.On("GetByID", mock.Anything, 1).Return(published, nil)
+
+		_, _, err := svc.StartConversation(context.Background(), "seller", 1, "hi")
+		assert.Equal(t, usecaseerr.ErrMessageOwnAd, err)
+	})
+
+	t.Run("blocked buyer", func(t *testing.T) {
+		svc, repo, ads := newTestService()
+		ads.On("GetByID", mock.Anything, 1).Return(published, nil)
+		repo.On("IsBlocked", mock.Anything, "seller", "buyer").Return(true, nil)
+
+		_, _, err := svc.StartConversation(context.Background(), "buyer", 1, "hi")
+		assert.Equal(t, usecaseerr.ErrUserBlocked, err)
+	})
+
+	t.Run("deleted ad", func(t *testing.T) {
+		svc, _, ads := newTestService()
+		ads.On("GetByID", mock.Anything, 2).Return(nil, repoerr.ErrAdNotFound)
+
+		_, _, err := svc.StartConversation(context.Background(), "buyer", 2, "hi")
+		assert.Equal(t, usecaseerr.ErrAdNotAvailable, err)
+	})
+
+	t.Run("empty and long messages", func(t *testing.T) {
+		svc, _, _ := newTestService()
+
+		_, _, err := svc.StartConversation(context.Background(), "buyer", 1, "   ")
+		assert.Equal(t, usecaseerr.ErrInvalidMessage, err)
+		_, _, err = svc.StartConversation(context.Background(), "buyer", 1, strings.Repeat("я", maxMessageLength+1))
+		assert.Equal(t, usecaseerr.ErrInvalidMessage, err)
+	})
+}
+
+func TestService_SendMessage(t *testing.T) {
+	conv := &entities.Conversation{ID: 5, BuyerID: "buyer", SellerID: "seller", AdID: 1}
+
+	t.Run("seller replies, buyer gets an event", func(t *testing.T) {
+		svc, repo, _ := newTestService()
+		defer repo.AssertExpectations(t)
+		events, unsubscribe := svc.events.Subscribe("buyer")
+		defer unsubscribe()
+		notify := &notifier.MockNotifier{}
+		defer notify.AssertExpectations(t)
+		svc.notifier = notify
+		notify.On("Notify", mock.Anything, mock.MatchedBy(func(n entities.Notification) bool {
+			return n.UserID == "buyer" && n.Kind == entities.NotificationMessage && n.Body == "yes"
+		})).Return(nil)
+
+		repo.On("GetConversation", mock.Anything, 5).Return(conv, nil)
+		repo.On("AddMessage", mock.Anything, mock.Anything).Return(nil)
+
+		msg, err := svc.SendMessage(context.Background(), "seller", 5, "yes")
+		assert.NoError(t, err)
+		assert.Equal(t, "seller", msg.SenderID)
+
+		event := <-events
+		assert.Equal(t, entities.EventNewMessage, event.Type)
+		assert.Equal(t, 5, event.Data["conversation_id"])
+	})
+
+	t.Run("stranger gets not found", func(t *testing.T) {
+		svc, repo, _ := newTestService()
+		repo.On("GetConversation", mock.Anything, 5).Return(conv, nil)
+
+		_, err := svc.SendMessage(context.Background(), "stranger", 5, "hi")
+		assert.Equal(t, usecaseerr.ErrConversationNotFound, err)
+	})
+
+	t.Run("locked conversation", func(t *testing.T) {
+		svc, repo, _ := newTestService()
+		repo.On("GetConversation", mock.Anything, 6).
+			Return(&entities.Conversation{ID: 6, BuyerID: "buyer", SellerID: "seller", Locked: true}, nil)
+
+		_, err := svc.SendMessage(context.Background(), "buyer", 6, "hi")
+		assert.Equal(t, usecaseerr.ErrConversationLocked, err)
+	})
+
+	t.Run("blocked buyer", func(t *testing.T) {
+		svc, repo, _ := newTestService()
+		repo.On("GetConversation", mock.Anything, 7).
+			Return(&entities.Conversation{ID: 7, BuyerID: "buyer", SellerID: "seller", AdID: 1, Blocked: true}, nil)
+
+		_, err := svc.SendMessage(context.Background(), "buyer", 7, "hi")
+		assert.Equal(t, usecaseerr.ErrUserBlocked, err)
+	})
+}
+
+func TestService_GetMessages(t *testing.T) {
+	t.Run("marks incoming messages as read", func(t *testing.T) {
+		svc, repo, _ := newTestService()
+		defer repo.AssertExpectations(t)
+
+		repo.On("GetConversation", mock.Anything, 5).
+			Return(&entities.Conversation{ID: 5, BuyerID: "buyer", SellerID: "seller"}, nil)
+		repo.On("ListMessages", mock.Anything, 5, int64(0), defaultPageSize).
+			Return([]entities.Message{{ID: 2}, {ID: 1}}, nil)
+		repo.On("MarkRead", mock.Anything, 5, "seller").Return(int64(2), nil)
+
+		messages, err := svc.GetMessages(context.Background(), "seller", 5, 0, 0)
+		assert.NoError(t, err)
+		assert.Len(t, messages, 2)
+	})
+
+	t.Run("page size is capped", func(t *testing.T) {
+		svc, repo, _ := newTestService()
+		defer repo.AssertExpectations(t)
+
+		repo.On("GetConversation", mock.Anything, 5).
+			Return(&entities.Conversation{ID: 5, BuyerID: "buyer", SellerID: "seller"}, nil)
+		repo.On("ListMessages", mock.Anything, 5, int64(40), maxPageSize).Return([]entities.Message{}, nil)
+		repo.On("MarkRead", mock.Anything, 5, "buyer").Return(int64(0), nil)
+
+		_, err := svc.GetMessages(context.Background(), "buyer", 5, 40, 1000)
+		assert.NoError(t, err)
+	})
+}
+
+func TestService_ListConversations(t *testing.T) {
+	svc, repo, _ := newTestService()
+	defer repo.AssertExpectations(t)
+
+	repo.On("ListConversations", mock.Anything, "buyer", 10, 20).Return([]entities.Conversation{{ID: 1}}, nil)
+
+	conversations, err := svc.ListConversations(context.Background(), "buyer", 3, 10)
+	assert.NoError(t, err)
+	assert.Len(t, conversations, 1)
+}
+
+func TestService_BlockBuyer(t *testing.T) {
+	conv := &entities.Conversation{ID: 5, BuyerID: "buyer", SellerID: "seller"}
+
+	t.Run("seller blocks", func(t *testing.T) {
+		svc, repo, _ := newTestService()
+		defer repo.AssertExpectations(t)
+
+		repo.On("GetConversation", mock.Anything, 5).Return(conv, nil)
+		repo.On("Block", mock.Anything, "seller", "buyer").Return(nil)
+
+		assert.NoError(t, svc.BlockBuyer(context.Background(), "seller", 5))
+	})
+
+	t.Run("buyer cannot block", func(t *testing.T) {
+		svc, repo, _ := newTestService()
+		repo.On("GetConversation", mock.Anything, 5).Return(conv, nil)
+
+		err := svc.BlockBuyer(context.Background(), "buyer", 5)
+		assert.Equal(t, usecaseerr.ErrAccessDenied, err)
+	})
+
+	t.Run("unblock without block", func(t *testing.T) {
+		svc, repo, _ := newTestService()
+		repo.On("GetConversation", mock.Anything, 5).Return(conv, nil)
+		repo.On("Unblock", mock.Anything, "seller", "buyer").Return(repoerr.ErrBlockNotFound)
+
+		err := svc.UnblockBuyer(context.Background(), "seller", 5)
+		assert.Equal(t, usecaseerr.ErrNotBlocked, err)
+	})
+}
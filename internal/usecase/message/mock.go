@@ -0,0 +1,65 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package message
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMessageService struct {
+	mock.Mock
+}
+
+func (m *MockMessageService) StartConversation(ctx context.Context, buyerID string, adID int,
+	body string) (*entities.Conversation, *entities.Message, error) {
+	args := m.Called(ctx, buyerID, adID, body)
+	conv, _ := args.Get(0).(*entities.Conversation)
+	msg, _ := args.Get(1).(*entities.Message)
+	return conv, msg, args.Error(2)
+}
+
+func (m *MockMessageService) SendMessage(ctx context.Context, userID string, conversationID int,
+	body string) (*entities.Message, error) {
+	args := m.Called(ctx, userID, conversationID, body)
+	if msg, ok := args.Get(0).(*entities.Message); ok {
+		return msg, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockMessageService) ListConversations(ctx context.Context, userID string,
+	page, limit int) ([]entities.Conversation, error) {
+	args := m.Called(ctx, userID, page, limit)
+	if conversations, ok := args.Get(0).([]entities.Conversation); ok {
+		return conversations, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockMessageService) GetMessages(ctx context.Context, userID string, conversationID int,
+	beforeID int64, limit int) ([]entities.Message, error) {
+	args := m.Called(ctx, userID, conversationID, beforeID, limit)
+	if messages, ok := args.Get(0).([]entities.Message); ok {
+		return messages, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockMessageService) UnreadCount(ctx context.Context, userID string) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMessageService) BlockBuyer(ctx context.Context, sellerID string, conversationID int) error {
+	args := m.Called(ctx, sellerID, conversationID)
+	return args.Error(0)
+}
+
+func (m *MockMessageService) UnblockBuyer(ctx context.Context, sellerID string, conversationID int) error {
+	args := m.Called(ctx, sellerID, conversationID)
+	return args.Error(0)
+}
+
+var _ MessageService = (*MockMessageService)(nil)
@@ -0,0 +1,46 @@
+package message
+
+import (
+	"ads-service/internal/domain/entities"
+	adRepo "ads-service/internal/repository/ad"
+	"ads-service/internal/repository/message"
+	"ads-service/pkg/eventbus"
+	customLogger "ads-service/pkg/logger"
+	"ads-service/pkg/notifier"
+	"context"
+)
+
+type MessageService interface {
+	// StartConversation opens (or reuses) the buyer's conversation about a published ad
+	// and sends the first message to its author.
+	StartConversation(ctx context.Context, buyerID string, adID int,
+		body string) (*entities.Conversation, *entities.Message, error)
+	SendMessage(ctx context.Context, userID string, conversationID int, body string) (*entities.Message, error)
+	ListConversations(ctx context.Context, userID string, page, limit int) ([]entities.Conversation, error)
+	// GetMessages returns a page of messages, newest first, and marks the incoming ones as read.
+	GetMessages(ctx context.Context, userID string, conversationID int,
+		beforeID int64, limit int) ([]entities.Message, error)
+	UnreadCount(ctx context.Context, userID string) (int, error)
+	// BlockBuyer is available to the seller only and applies to all conversations with that buyer.
+	BlockBuyer(ctx context.Context, sellerID string, conversationID int) error
+	UnblockBuyer(ctx context.Context, sellerID string, conversationID int) error
+}
+
+type service struct {
+	repo     message.MessageRepository
+	adRepo   adRepo.AdRepository
+	events   eventbus.Bus
+	notifier notifier.Notifier
+	logger   customLogger.Logger
+}
+
+func NewMessageService(repo message.MessageRepository, adRepository adRepo.AdRepository,
+	events eventbus.Bus, notify notifier.Notifier, logTool customLogger.Logger) MessageService {
+	return &service{
+		repo:     repo,
+		adRepo:   adRepository,
+		events:   events,
+		notifier: notify,
+		logger:   logTool,
+	}
+}
@@ -0,0 +1,59 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package notification
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNotificationService struct {
+	mock.Mock
+}
+
+func (m *MockNotificationService) Notify(ctx context.Context, n entities.Notification) error {
+	args := m.Called(ctx, n)
+	return args.Error(0)
+}
+
+func (m *MockNotificationService) List(ctx context.Context, userID string, unreadOnly bool,
+	page, limit int) ([]entities.Notification, error) {
+	args := m.Called(ctx, userID, unreadOnly, page, limit)
+	if notifications, ok := args.Get(0).([]entities.Notification); ok {
+		return notifications, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockNotificationService) UnreadCount(ctx context.Context, userID string) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockNotificationService) MarkRead(ctx context.Context, userID string, id int) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockNotificationService) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationService) GetPreferences(ctx context.Context,
+	userID string) (*entities.NotificationPreferences, error) {
+	args := m.Called(ctx, userID)
+	if prefs, ok := args.Get(0).(*entities.NotificationPreferences); ok {
+		return prefs, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockNotificationService) UpdatePreferences(ctx context.Context,
+	prefs *entities.NotificationPreferences) error {
+	args := m.Called(ctx, prefs)
+	return args.Error(0)
+}
+
+var _ NotificationService = (*MockNotificationService)(nil)
@@ -0,0 +1,168 @@
+package notification
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/pkg/tracing"
+	"context"
+	"errors"
+	"net/mail"
+	"strings"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// Notify delivers n through every channel the user has enabled. It fails only when
+// nothing was delivered, so callers don't resend what already reached the user.
+func (s *service) Notify(ctx context.Context, n entities.Notification) (err error) {
+	ctx, span := tracing.Start(ctx, "notification.Notify")
+	defer tracing.End(span, &err)
+
+	prefs, err := s.repo.GetPreferences(ctx, n.UserID)
+	if err != nil {
+		// falling back to the defaults keeps the notification in the center at least
+		s.logger.ERROR("error getting notification preferences: ", err)
+		prefs = entities.DefaultNotificationPreferences(n.UserID)
+	}
+
+	var delivered, failed int
+	if prefs.InApp {
+		if err = s.repo.Create(ctx, &n); err != nil {
+			s.logger.ERROR("error saving notification: ", err)
+			failed++
+		} else {
+			delivered++
+		}
+	}
+	if prefs.SMS {
+		if err = s.sendSMS(ctx, n); err != nil {
+			s.logger.ERROR("error sending sms notification: ", err)
+			failed++
+		} else {
+			delivered++
+		}
+	}
+	if prefs.Email && prefs.EmailAddress != "" {
+		n.Email = prefs.EmailAddress
+		if err = s.channels.Email.Notify(ctx, n); err != nil {
+			s.logger.ERROR("error sending email notification: ", err)
+			failed++
+		} else {
+			delivered++
+		}
+	}
+
+	if delivered == 0 && failed > 0 {
+		return usecaseerr.ErrNotifying
+	}
+	return nil
+}
+
+func (s *service) sendSMS(ctx context.Context, n entities.Notification) error {
+	recipient, err := s.userRepo.GetUserByID(ctx, n.UserID)
+	if err != nil {
+		return err
+	}
+	n.Phone = recipient.Phone
+	return s.channels.SMS.Notify(ctx, n)
+}
+
+func (s *service) List(ctx context.Context, userID string, unreadOnly bool,
+	page, limit int) (_ []entities.Notification, err error) {
+	ctx, span := tracing.Start(ctx, "notification.List")
+	defer tracing.End(span, &err)
+
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	limit = min(limit, maxPageSize)
+	if page < 1 {
+		page = 1
+	}
+	notifications, err := s.repo.ListByUser(ctx, userID, unreadOnly, limit, (page-1)*limit)
+	if err != nil {
+		s.logger.ERROR("error getting notifications: ", err)
+		return nil, usecaseerr.ErrGettingNotifications
+	}
+	return notifications, nil
+}
+
+func (s *service) UnreadCount(ctx context.Context, userID string) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "notification.UnreadCount")
+	defer tracing.End(span, &err)
+
+	count, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		s.logger.ERROR("error counting unread notifications: ", err)
+		return 0, usecaseerr.ErrGettingNotifications
+	}
+	return count, nil
+}
+
+func (s *service) MarkRead(ctx context.Context, userID string, id int) (err error) {
+	ctx, span := tracing.Start(ctx, "notification.MarkRead")
+	defer tracing.End(span, &err)
+
+	if id <= 0 {
+		return usecaseerr.ErrInvalidParams
+	}
+	if err := s.repo.MarkRead(ctx, userID, id); err != nil {
+		if errors.Is(err, repoerr.ErrNotificationNotFound) {
+			return usecaseerr.ErrNotificationNotFound
+		}
+		s.logger.ERROR("error marking notification as read: ", err)
+		return usecaseerr.ErrUpdatingNotifications
+	}
+	return nil
+}
+
+func (s *service) MarkAllRead(ctx context.Context, userID string) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "notification.MarkAllRead")
+	defer tracing.End(span, &err)
+
+	n, err := s.repo.MarkAllRead(ctx, userID)
+	if err != nil {
+		s.logger.ERROR("error marking notifications as read: ", err)
+		return 0, usecaseerr.ErrUpdatingNotifications
+	}
+	return n, nil
+}
+
+func (s *service) GetPreferences(ctx context.Context, userID string) (_ *entities.NotificationPreferences, err error) {
+	ctx, span := tracing.Start(ctx, "notification.GetPreferences")
+	defer tracing.End(span, &err)
+
+	prefs, err := s.repo.GetPreferences(ctx, userID)
+	if err != nil {
+		s.logger.ERROR("error getting notification preferences: ", err)
+		return nil, usecaseerr.ErrGettingPreferences
+	}
+	return prefs, nil
+}
+
+func (s *service) UpdatePreferences(ctx context.Context, prefs *entities.NotificationPreferences) (err error) {
+	ctx, span := tracing.Start(ctx, "notification.UpdatePreferences")
+	defer tracing.End(span, &err)
+
+	prefs.EmailAddress = strings.TrimSpace(prefs.EmailAddress)
+	if prefs.EmailAddress != "" {
+		addr, err := mail.ParseAddress(prefs.EmailAddress)
+		// only a bare address is accepted, it ends up in the To header as is
+		if err != nil || addr.Address != prefs.EmailAddress {
+			return usecaseerr.ErrInvalidPreferences
+		}
+	}
+	if prefs.Email && prefs.EmailAddress == "" {
+		return usecaseerr.ErrInvalidPreferences
+	}
+
+	if err := s.repo.SavePreferences(ctx, prefs); err != nil {
+		s.logger.ERROR("error saving notification preferences: ", err)
+		return usecaseerr.ErrUpdatingPreferences
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+//nolint:all // testpackage
+package notification
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/repository/notification"
+	"ads-service/internal/repository/user"
+	customLogger "ads-service/pkg/logger"
+	"ads-service/pkg/notifier"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type testDeps struct {
+	repo  *notification.MockNotificationRepo
+	users *user.MockUserRepo
+	sms   *notifier.MockNotifier
+	email *notifier.MockNotifier
+}
+
+func newTestService() (NotificationService, testDeps) {
+	deps := testDeps{
+		repo:  &notification.MockNotificationRepo{},
+		users: &user.MockUserRepo{},
+		sms:   &notifier.MockNotifier{},
+		email: &notifier.MockNotifier{},
+	}
+	svc := NewNotificationService(deps.repo, deps.users,
+		notifier.Channels{SMS: deps.sms, Email: deps.email}, customLogger.Logger{})
+	return svc, deps
+}
+
+func (d testDeps) assertExpectations(t *testing.T) {
+	d.repo.AssertExpectations(t)
+	d.users.AssertExpectations(t)
+	d.sms.AssertExpectations(t)
+	d.email.AssertExpectations(t)
+}
+
+func TestNotificationService_Notify(t *testing.T) {
+	n := entities.Notification{UserID: "user", Kind: entities.NotificationModeration, Subject: "Ad rejected"}
+
+	t.Run("defaults keep it in-app only", func(t *testing.T) {
+		svc, deps := newTestService()
+		defer deps.assertExpectations(t)
+
+		deps.repo.On("GetPreferences", mock.Anything, "user").
+			Return(entities.DefaultNotificationPreferences("user"), nil)
+		deps.repo.On("Create", mock.Anything, mock.AnythingOfType("*entities.Notification")).Return(nil)
+
+		assert.NoError(t, svc.Notify(context.Background(), n))
+	})
+
+	t.Run("all channels", func(t *testing.T) {
+		svc, deps := newTestService()
+		defer deps.assertExpectations(t)
+
+		deps.repo.On("GetPreferences", mock.Anything, "user").Return(&entities.NotificationPreferences{
+			UserID: "user", InApp: true, SMS: true, Email: true, EmailAddress: "user@example.com",
+		}, nil)
+		deps.repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		deps.users.On("GetUserByID", mock.Anything, "user").Return(&entities.User{Phone: "+998901234567"}, nil)
+		deps.sms.On("Notify", mock.Anything, mock.MatchedBy(func(got entities.Notification) bool {
+			return got.Phone == "+998901234567" && got.Subject == "Ad rejected"
+		})).Return(nil)
+		deps.email.On("Notify", mock.Anything, mock.MatchedBy(func(got entities.Notification) bool {
+			return got.Email == "user@example.com"
+		})).Return(nil)
+
+		assert.NoError(t, svc.Notify(context.Background(), n))
+	})
+
+	t.Run("a failed channel does not fail delivered ones", func(t *testing.T) {
+		svc, deps := newTestService()
+		defer deps.assertExpectations(t)
+
+		deps.repo.On("GetPreferences", mock.Anything, "user").Return(&entities.NotificationPreferences{
+			UserID: "user", InApp: true, Email: true, EmailAddress: "user@example.com",
+		}, nil)
+		deps.repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		deps.email.On("Notify", mock.Anything, mock.Anything).Return(notifier.ErrSending)
+
+		assert.NoError(t, svc.Notify(context.Background(), n))
+	})
+
+	t.Run("nothing delivered", func(t *testing.T) {
+		svc, deps := newTestService()
+		defer deps.assertExpectations(t)
+
+		deps.repo.On("GetPreferences", mock.Anything, "user").Return(nil, repoerr.ErrPreferencesSelect)
+		deps.repo.On("Create", mock.Anything, mock.Anything).Return(repoerr.ErrNotificationInsert)
+
+		assert.Equal(t, usecaseerr.ErrNotifying, svc.Notify(context.Background(), n))
+	})
+
+	t.Run("all channels disabled", func(t *testing.T) {
+		svc, deps := newTestService()
+		defer deps.assertExpectations(t)
+
+		deps.repo.On("GetPreferences", mock.Anything, "user").
+			Return(&entities.NotificationPreferences{UserID: "user"}, nil)
+
+		assert.NoError(t, svc.Notify(context.Background(), n))
+	})
+}
+
+func TestNotificationService_List(t *testing.T) {
+	svc, deps := newTestService()
+	defer deps.assertExpectations(t)
+
+	deps.repo.On("ListByUser", mock.Anything, "user", true, 100, 100).
+		Return([]entities.Notification{{ID: 1}}, nil)
+
+	notifications, err := svc.List(context.Background(), "user", true, 2, 500)
+	assert.NoError(t, err)
+	assert.Len(t, notifications, 1)
+}
+
+func TestNotificationService_MarkRead(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		svc, deps := newTestService()
+		defer deps.assertExpectations(t)
+
+		deps.repo.On("MarkRead", mock.Anything, "user", 3).Return(repoerr.ErrNotificationNotFound)
+
+		assert.Equal(t, usecaseerr.ErrNotificationNotFound, svc.MarkRead(context.Background(), "user", 3))
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		svc, _ := newTestService()
+		assert.Equal(t, usecaseerr.ErrInvalidParams, svc.MarkRead(context.Background(), "user", 0))
+	})
+}
+
+func TestNotificationService_UpdatePreferences(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		svc, deps := newTestService()
+		defer deps.assertExpectations(t)
+
+		prefs := &entities.NotificationPreferences{UserID: "user", Email: true, EmailAddress: " user@example.com "}
+		deps.repo.On("SavePreferences", mock.Anything, prefs).Return(nil)
+
+		assert.NoError(t, svc.UpdatePreferences(context.Background(), prefs))
+		assert.Equal(t, "user@example.com", prefs.EmailAddress)
+	})
+
+	for name, prefs := range map[string]*entities.NotificationPreferences{
+		"email without address": {UserID: "user", Email: true},
+		"invalid address":       {UserID: "user", EmailAddress: "not an email"},
+		"address with name":     {UserID: "user", EmailAddress: "Eve <eve@example.com>"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			svc, deps := newTestService()
+			defer deps.assertExpectations(t)
+
+			assert.Equal(t, usecaseerr.ErrInvalidPreferences, svc.UpdatePreferences(context.Background(), prefs))
+		})
+	}
+
+	t.Run("save error", func(t *testing.T) {
+		svc, deps := newTestService()
+		defer deps.assertExpectations(t)
+
+		deps.repo.On("SavePreferences", mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+		err := svc.UpdatePreferences(context.Background(), &entities.NotificationPreferences{UserID: "user"})
+		assert.Equal(t, usecaseerr.ErrUpdatingPreferences, err)
+	})
+}
@@ -0,0 +1,40 @@
+package notification
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/repository/notification"
+	"ads-service/internal/repository/user"
+	customLogger "ads-service/pkg/logger"
+	"ads-service/pkg/notifier"
+	"context"
+)
+
+// NotificationService - the notification center. It is itself a notifier.Notifier:
+// other use cases hand notifications to it and it delivers them through the channels
+// the recipient has enabled.
+type NotificationService interface {
+	notifier.Notifier
+	List(ctx context.Context, userID string, unreadOnly bool, page, limit int) ([]entities.Notification, error)
+	UnreadCount(ctx context.Context, userID string) (int, error)
+	MarkRead(ctx context.Context, userID string, id int) error
+	MarkAllRead(ctx context.Context, userID string) (int64, error)
+	GetPreferences(ctx context.Context, userID string) (*entities.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, prefs *entities.NotificationPreferences) error
+}
+
+type service struct {
+	repo     notification.NotificationRepository
+	userRepo user.UserRepository
+	channels notifier.Channels
+	logger   customLogger.Logger
+}
+
+func NewNotificationService(repo notification.NotificationRepository, userRepo user.UserRepository,
+	channels notifier.Channels, logTool customLogger.Logger) NotificationService {
+	return &service{
+		repo:     repo,
+		userRepo: userRepo,
+		channels: channels,
+		logger:   logTool,
+	}
+}
@@ -0,0 +1,28 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package premoderation
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPreModerationService struct {
+	mock.Mock
+}
+
+func (m *MockPreModerationService) Screen(ctx context.Context, ad *entities.Ad) (entities.RuleDecision, error) {
+	args := m.Called(ctx, ad)
+	return args.Get(0).(entities.RuleDecision), args.Error(1)
+}
+
+func (m *MockPreModerationService) GetRuleHits(ctx context.Context, adID int) ([]entities.RuleHit, error) {
+	args := m.Called(ctx, adID)
+	if hits, ok := args.Get(0).([]entities.RuleHit); ok {
+		return hits, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+var _ PreModerationService = (*MockPreModerationService)(nil)
@@ -0,0 +1,113 @@
+package premoderation
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/usecase/admin"
+	"ads-service/pkg/tracing"
+	"context"
+	"errors"
+	"strings"
+)
+
+// maxScreenings - screenings of one submission, the author may keep editing the ad while it is screened.
+const maxScreenings = 3
+
+func (s *service) Screen(ctx context.Context, ad *entities.Ad) (_ entities.RuleDecision, err error) {
+	ctx, span := tracing.Start(ctx, "premoderation.Screen")
+	defer tracing.End(span, &err)
+
+	for screenings := 1; ; screenings++ {
+		var decision entities.RuleDecision
+		decision, err = s.screen(ctx, ad)
+		var stale *errs.StaleError
+		if !errors.As(err, &stale) {
+			return decision, err
+		}
+		current, ok := stale.Current.(*entities.Ad)
+		if !ok || current.Status != entities.StatusPending {
+			// a moderator decided or the author took the ad back meanwhile
+			s.logger.INFO("ad ", ad.ID, " changed while screened, left as it is")
+			return entities.DecisionReview, nil
+		}
+		if screenings == maxScreenings {
+			s.logger.ERROR("ad ", ad.ID, " kept changing while screened, left for a moderator")
+			return entities.DecisionReview, usecaseerr.ErrScreeningAd
+		}
+		// the decision was about content that is gone, the edited ad is screened instead
+		ad = current
+	}
+}
+
+// screen applies the decision to the version of the ad it was made on, a *errs.StaleError means the ad changed.
+func (s *service) screen(ctx context.Context, ad *entities.Ad) (entities.RuleDecision, error) {
+	others, err := s.adRepo.GetByUserID(ctx, ad.AuthorID)
+	if err != nil {
+		s.logger.ERROR("error getting author ads for screening: ", err)
+		return entities.DecisionReview, usecaseerr.ErrScreeningAd
+	}
+
+	hits := s.rules.check(ad, others)
+	score := 0
+	for _, hit := range hits {
+		score += hit.Score
+	}
+	decision := s.rules.decide(score, s.rules.trusted(ad, others))
+
+	// moderators see why the ad was rejected or flagged even if applying the decision fails
+	if err = s.repo.Save(ctx, ad.ID, decision, hits); err != nil {
+		s.logger.ERROR("error saving rule hits: ", err)
+		return entities.DecisionReview, usecaseerr.ErrScreeningAd
+	}
+
+	switch decision {
+	case entities.DecisionReject:
+		err = s.moderation.Reject(ctx, ad.ID, ad.Version, "", entities.Rejection{
+			Code: entities.RejectionRulesViolation,
+			Note: rejectionNote(hits),
+		})
+	case entities.DecisionApprove:
+		err = s.moderation.Approve(ctx, ad.ID, ad.Version, "")
+	case entities.DecisionReview:
+		// stays pending for a moderator
+	}
+	var stale *errs.StaleError
+	if errors.As(err, &stale) {
+		return entities.DecisionReview, stale
+	}
+	if err != nil {
+		s.logger.ERROR("error applying pre-moderation decision: ", err)
+		return entities.DecisionReview, usecaseerr.ErrScreeningAd
+	}
+	s.logger.INFO("ad ", ad.ID, " screened: ", decision, ", risk score ", score)
+	return decision, nil
+}
+
+func (s *service) GetRuleHits(ctx context.Context, adID int) (_ []entities.RuleHit, err error) {
+	ctx, span := tracing.Start(ctx, "premoderation.GetRuleHits")
+	defer tracing.End(span, &err)
+
+	if adID <= 0 {
+		return nil, usecaseerr.ErrInvalidParams
+	}
+	hits, err := s.repo.GetByAd(ctx, adID)
+	if err != nil {
+		s.logger.ERROR("error getting rule hits: ", err)
+		return nil, usecaseerr.ErrGettingRuleHits
+	}
+	return hits, nil
+}
+
+// rejectionNote lists the hits, cut to the length a rejection note may have.
+func rejectionNote(hits []entities.RuleHit) string {
+	details := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		details = append(details, hit.Detail)
+	}
+	note := []rune(strings.Join(details, "; "))
+	if len(note) <= admin.MaxRejectionNoteLength {
+		return string(note)
+	}
+	return string(note[:admin.MaxRejectionNoteLength-1]) + "…"
+}
@@ -0,0 +1,271 @@
+//nolint:all // testpackage
+package premoderation
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs"
+	"ads-service/internal/errs/usecaseerr"
+	adRepo "ads-service/internal/repository/ad"
+	"ads-service/internal/repository/rulehit"
+	"ads-service/internal/usecase/admin"
+	customLogger "ads-service/pkg/logger"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func testRules(t *testing.T) Rules {
+	rules, err := ParseRules([]byte(`{"banned_words": {"0": ["Casino"], "3": ["air gun"]}}`))
+	assert.NoError(t, err)
+	return rules
+}
+
+func ruleNames(hits []entities.RuleHit) []string {
+	names := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		names = append(names, hit.Rule)
+	}
+	return names
+}
+
+func TestParseRules(t *testing.T) {
+	t.Run("defaults are kept", func(t *testing.T) {
+		rules, err := ParseRules([]byte(`{"reject_score": 80}`))
+		assert.NoError(t, err)
+		assert.Equal(t, 80, rules.RejectScore)
+		assert.Equal(t, DefaultRules().PhoneScore, rules.PhoneScore)
+	})
+
+	for name, data := range map[string]string{
+		"not json":           `banned`,
+		"reject below ok":    `{"reject_score": 10, "approve_max_score": 10}`,
+		"caps ratio too big": `{"caps_ratio": 1.5}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseRules([]byte(data))
+			assert.ErrorIs(t, err, usecaseerr.ErrInvalidRules)
+		})
+	}
+}
+
+func TestRules_Check(t *testing.T) {
+	rules := testRules(t)
+
+	for name, tc := range map[string]struct {
+		ad   entities.Ad
+		hits []string
+	}{
+		"clean": {
+			ad:   entities.Ad{Title: "Bicycle", Description: "Good condition, 2 years old, price 1500000"},
+			hits: []string{},
+		},
+		"global banned word": {
+			ad:   entities.Ad{Title: "Best CASINO bonus", Description: "come and play", CategoryID: 1},
+			hits: []string{entities.RuleBannedWord},
+		},
+		"category banned phrase": {
+			ad:   entities.Ad{Title: "Air-gun for sale", Description: "almost new", CategoryID: 3},
+			hits: []string{entities.RuleBannedWord},
+		},
+		"phrase of another category": {
+			ad:   entities.Ad{Title: "Air gun for sale", Description: "almost new", CategoryID: 1},
+			hits: []string{},
+		},
+		"banned word inside another word": {
+			ad:   entities.Ad{Title: "Casinos history book", Description: "hardcover"},
+			hits: []string{},
+		},
+		"phone and link": {
+			ad:   entities.Ad{Title: "Sofa", Description: "call +998 (90) 123-45-67 or see www.example.com"},
+			hits: []string{entities.RulePhone, entities.RuleLink},
+		},
+		"caps": {
+			ad:   entities.Ad{Title: "SUPER DEAL TODAY", Description: "ONLY NOW buy it"},
+			hits: []string{entities.RuleCaps},
+		},
+		"duplicate title": {
+			ad:   entities.Ad{ID: 2, Title: "  bicycle!", Description: "good"},
+			hits: []string{entities.RuleDuplicateTitle},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			others := []entities.Ad{
+				{ID: 1, Title: "Bicycle", Status: entities.StatusApproved},
+				{ID: 2, Title: "Bicycle", Status: entities.StatusPending},
+			}
+			if tc.ad.ID == 0 {
+				others = nil
+			}
+			assert.Equal(t, tc.hits, ruleNames(rules.check(&tc.ad, others)))
+		})
+	}
+}
+
+func TestRules_Decide(t *testing.T) {
+	rules := DefaultRules()
+	ad := &entities.Ad{ID: 9}
+	approved := []entities.Ad{
+		{ID: 1, Status: entities.StatusApproved},
+		{ID: 2, Status: entities.StatusApproved},
+		{ID: 3, Status: entities.StatusApproved},
+	}
+
+	assert.True(t, rules.trusted(ad, approved))
+	assert.False(t, rules.trusted(ad, approved[:2]))
+	assert.False(t, rules.trusted(ad, append(approved, entities.Ad{ID: 4, Status: entities.StatusRejected})))
+
+	assert.Equal(t, entities.DecisionReject, rules.decide(100, true))
+	assert.Equal(t, entities.DecisionApprove, rules.decide(0, true))
+	assert.Equal(t, entities.DecisionReview, rules.decide(0, false))
+	assert.Equal(t, entities.DecisionReview, rules.decide(40, true))
+}
+
+func newTestService(t *testing.T) (PreModerationService, *rulehit.MockRuleHitRepo, *adRepo.MockAdRepo,
+	*admin.MockAdminService) {
+	repo, ads, moderation := &rulehit.MockRuleHitRepo{}, &adRepo.MockAdRepo{}, &admin.MockAdminService{}
+	t.Cleanup(func() {
+		repo.AssertExpectations(t)
+		ads.AssertExpectations(t)
+		moderation.AssertExpectations(t)
+	})
+	return NewPreModerationService(testRules(t), repo, ads, moderation, customLogger.Logger{}), repo, ads, moderation
+}
+
+func TestService_Screen(t *testing.T) {
+	trustedSeller := []entities.Ad{
+		{ID: 1, Title: "Chair", Status: entities.StatusApproved},
+		{ID: 2, Title: "Table", Status: entities.StatusApproved},
+		{ID: 3, Title: "Lamp", Status: entities.StatusApproved},
+	}
+
+	t.Run("reject", func(t *testing.T) {
+		service, repo, ads, moderation := newTestService(t)
+		ad := &entities.Ad{ID: 9, AuthorID: "seller", Title: "Casino", Description: "play now"}
+
+		ads.On("GetByUserID", mock.Anything, "seller").Return(trustedSeller, nil)
+		repo.On("Save", mock.Anything, 9, entities.DecisionReject, mock.MatchedBy(func(hits []entities.RuleHit) bool {
+			return len(hits) == 1 && hits[0].Rule == entities.RuleBannedWord
+		})).Return(nil)
+		moderation.On("Reject", mock.Anything, 9, 0, "", entities.Rejection{
+			Code: entities.RejectionRulesViolation,
+			Note: `banned word "casino"`,
+		}).Return(nil)
+
+		decision, err := service.Screen(context.Background(), ad)
+		assert.NoError(t, err)
+		assert.Equal(t, entities.DecisionReject, decision)
+	})
+
+	t.Run("approve trusted seller", func(t *testing.T) {
+		service, repo, ads, moderation := newTestService(t)
+		ad := &entities.Ad{ID: 9, AuthorID: "seller", Title: "Sofa", Description: "grey, three seats"}
+
+		ads.On("GetByUserID", mock.Anything, "seller").Return(trustedSeller, nil)
+		repo.On("Save", mock.Anything, 9, entities.DecisionApprove, []entities.RuleHit(nil)).Return(nil)
+		moderation.On("Approve", mock.Anything, 9, 0, "").Return(nil)
+
+		decision, err := service.Screen(context.Background(), ad)
+		assert.NoError(t, err)
+		assert.Equal(t, entities.DecisionApprove, decision)
+	})
+
+	t.Run("review", func(t *testing.T) {
+		service, repo, ads, _ := newTestService(t)
+		ad := &entities.Ad{ID: 9, AuthorID: "seller", Title: "Sofa", Description: "call 901234567"}
+
+		ads.On("GetByUserID", mock.Anything, "seller").Return(trustedSeller, nil)
+		repo.On("Save", mock.Anything, 9, entities.DecisionReview, mock.Anything).Return(nil)
+
+		decision, err := service.Screen(context.Background(), ad)
+		assert.NoError(t, err)
+		assert.Equal(t, entities.DecisionReview, decision)
+	})
+
+	t.Run("edited while screened", func(t *testing.T) {
+		service, repo, ads, moderation := newTestService(t)
+		ad := &entities.Ad{ID: 9, AuthorID: "seller", Title: "Sofa", Description: "grey", Version: 2}
+		edited := &entities.Ad{ID: 9, AuthorID: "seller", Title: "Casino", Description: "play now", Version: 3,
+			Status: entities.StatusPending}
+
+		ads.On("GetByUserID", mock.Anything, "seller").Return(trustedSeller, nil)
+		repo.On("Save", mock.Anything, 9, mock.Anything, mock.Anything).Return(nil)
+		moderation.On("Approve", mock.Anything, 9, 2, "").
+			Return(errs.NewStale(usecaseerr.ErrAdModified, edited, edited.Version)).Once()
+		moderation.On("Reject", mock.Anything, 9, 3, "", mock.Anything).Return(nil).Once()
+
+		decision, err := service.Screen(context.Background(), ad)
+		assert.NoError(t, err)
+		assert.Equal(t, entities.DecisionReject, decision)
+	})
+
+	t.Run("decided while screened", func(t *testing.T) {
+		service, repo, ads, moderation := newTestService(t)
+		ad := &entities.Ad{ID: 9, AuthorID: "seller", Title: "Sofa", Description: "grey", Version: 2}
+		approved := &entities.Ad{ID: 9, Version: 3, Status: entities.StatusApproved}
+
+		ads.On("GetByUserID", mock.Anything, "seller").Return(trustedSeller, nil)
+		repo.On("Save", mock.Anything, 9, entities.DecisionApprove, mock.Anything).Return(nil).Once()
+		moderation.On("Approve", mock.Anything, 9, 2, "").
+			Return(errs.NewStale(usecaseerr.ErrAdModified, approved, approved.Version)).Once()
+
+		decision, err := service.Screen(context.Background(), ad)
+		assert.NoError(t, err)
+		assert.Equal(t, entities.DecisionReview, decision)
+	})
+
+	t.Run("save error", func(t *testing.T) {
+		service, repo, ads, _ := newTestService(t)
+
+		ads.On("GetByUserID", mock.Anything, "seller").Return([]entities.Ad(nil), nil)
+		repo.On("Save", mock.Anything, 9, entities.DecisionReview, mock.Anything).Return(errors.New("db error"))
+
+		_, err := service.Screen(context.Background(), &entities.Ad{ID: 9, AuthorID: "seller", Title: "Sofa"})
+		assert.Equal(t, usecaseerr.ErrScreeningAd, err)
+	})
+
+	t.Run("author ads error", func(t *testing.T) {
+		service, _, ads, _ := newTestService(t)
+
+		ads.On("GetByUserID", mock.Anything, "seller").Return([]entities.Ad(nil), errors.New("db error"))
+
+		decision, err := service.Screen(context.Background(), &entities.Ad{ID: 9, AuthorID: "seller"})
+		assert.Equal(t, usecaseerr.ErrScreeningAd, err)
+		assert.Equal(t, entities.DecisionReview, decision)
+	})
+}
+
+func TestRejectionNote(t *testing.T) {
+	hits := make([]entities.RuleHit, 200)
+	for i := range hits {
+		hits[i] = entities.RuleHit{Detail: `banned word "casino"`}
+	}
+
+	note := rejectionNote(hits)
+	assert.Equal(t, admin.MaxRejectionNoteLength, utf8.RuneCountInString(note))
+	assert.True(t, strings.HasSuffix(note, "…"))
+	assert.Equal(t, `banned word "casino"`, rejectionNote(hits[:1]))
+}
+
+func TestService_GetRuleHits(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		service, repo, _, _ := newTestService(t)
+
+		repo.On("GetByAd", mock.Anything, 9).Return([]entities.RuleHit{{Rule: entities.RuleCaps}}, nil)
+
+		hits, err := service.GetRuleHits(context.Background(), 9)
+		assert.NoError(t, err)
+		assert.Len(t, hits, 1)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		service, _, _, _ := newTestService(t)
+
+		_, err := service.GetRuleHits(context.Background(), 0)
+		assert.Equal(t, usecaseerr.ErrInvalidParams, err)
+	})
+}
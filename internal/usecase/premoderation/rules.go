@@ -0,0 +1,166 @@
+package premoderation
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/pkg/simhash"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Rules - pre-moderation settings. Every hit adds its score to the ad's risk score, an ad that reaches
+// RejectScore is rejected, an ad of a trusted seller that stays at ApproveMaxScore or below is approved.
+type Rules struct {
+	// BannedWords - category ID to words and phrases, category 0 applies to every category.
+	BannedWords        map[int][]string `json:"banned_words"`
+	CapsRatio          float64          `json:"caps_ratio"`
+	RejectScore        int              `json:"reject_score"`
+	ApproveMaxScore    int              `json:"approve_max_score"`
+	TrustedMinApproved int              `json:"trusted_min_approved"` // approved ads without rejections
+	BannedWordScore    int              `json:"banned_word_score"`
+	PhoneScore         int              `json:"phone_score"`
+	LinkScore          int              `json:"link_score"`
+	CapsScore          int              `json:"caps_score"`
+	CapsMinLetters     int              `json:"caps_min_letters"` // shorter texts are not checked for caps
+	DuplicateScore     int              `json:"duplicate_score"`
+}
+
+func DefaultRules() Rules {
+	return Rules{
+		BannedWords:        map[int][]string{},
+		CapsRatio:          0.6,
+		RejectScore:        100,
+		ApproveMaxScore:    0,
+		TrustedMinApproved: 3,
+		BannedWordScore:    100,
+		PhoneScore:         40,
+		LinkScore:          40,
+		CapsScore:          20,
+		CapsMinLetters:     12,
+		DuplicateScore:     60,
+	}
+}
+
+// LoadRules reads the JSON file at path, missing fields keep their defaults.
+// Without the file the defaults are used, they have no banned words.
+func LoadRules(path string) (Rules, error) {
+	if path == "" {
+		return DefaultRules(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Rules{}, fmt.Errorf("reading moderation rules: %w", err)
+	}
+	return ParseRules(data)
+}
+
+func ParseRules(data []byte) (Rules, error) {
+	rules := DefaultRules()
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return Rules{}, fmt.Errorf("%w: %w", usecaseerr.ErrInvalidRules, err)
+	}
+	if rules.RejectScore <= rules.ApproveMaxScore || rules.CapsRatio <= 0 || rules.CapsRatio > 1 {
+		return Rules{}, usecaseerr.ErrInvalidRules
+	}
+	for category, words := range rules.BannedWords {
+		normalized := make([]string, 0, len(words))
+		for _, word := range words {
+			if word = simhash.Normalize(word); word != "" {
+				normalized = append(normalized, word)
+			}
+		}
+		rules.BannedWords[category] = normalized
+	}
+	return rules, nil
+}
+
+var (
+	// nine digits or more, possibly split by spaces, dashes and brackets: +998 (90) 123-45-67
+	phonePattern = regexp.MustCompile(`\+?\d(?:[\s\-()]*\d){8,}`)
+	linkPattern  = regexp.MustCompile(`(?i)(?:https?://|www\.|t\.me/)\S+|\b[a-z0-9-]+\.(?:com|net|org|ru|uz|me|io)\b`)
+)
+
+// check returns the hits of the ad, others are the rest of the author's ads.
+func (r Rules) check(ad *entities.Ad, others []entities.Ad) []entities.RuleHit {
+	var hits []entities.RuleHit
+	text := ad.Title + "\n" + ad.Description
+
+	words := " " + simhash.Normalize(text) + " "
+	for _, category := range []int{0, ad.CategoryID} {
+		for _, banned := range r.BannedWords[category] {
+			if strings.Contains(words, " "+banned+" ") {
+				hits = append(hits, entities.RuleHit{Rule: entities.RuleBannedWord, Score: r.BannedWordScore,
+					Detail: fmt.Sprintf("banned word %q", banned)})
+			}
+		}
+		if ad.CategoryID == 0 {
+			break
+		}
+	}
+
+	if phonePattern.MatchString(text) {
+		hits = append(hits, entities.RuleHit{Rule: entities.RulePhone, Score: r.PhoneScore,
+			Detail: "phone number in the text"})
+	}
+	if linkPattern.MatchString(text) {
+		hits = append(hits, entities.RuleHit{Rule: entities.RuleLink, Score: r.LinkScore,
+			Detail: "link in the text"})
+	}
+
+	var letters, upper int
+	for _, c := range text {
+		if unicode.IsLetter(c) {
+			letters++
+			if unicode.IsUpper(c) {
+				upper++
+			}
+		}
+	}
+	if letters >= r.CapsMinLetters && float64(upper) >= r.CapsRatio*float64(letters) {
+		hits = append(hits, entities.RuleHit{Rule: entities.RuleCaps, Score: r.CapsScore,
+			Detail: fmt.Sprintf("%d%% capital letters", upper*100/letters)})
+	}
+
+	title := simhash.Normalize(ad.Title)
+	for i := range others {
+		other := &others[i]
+		if other.ID != ad.ID && other.Status != entities.StatusRejected && simhash.Normalize(other.Title) == title {
+			hits = append(hits, entities.RuleHit{Rule: entities.RuleDuplicateTitle, Score: r.DuplicateScore,
+				Detail: fmt.Sprintf("same title as ad #%d", other.ID)})
+			break
+		}
+	}
+	return hits
+}
+
+// trusted - the seller has enough approved ads and none rejected.
+func (r Rules) trusted(ad *entities.Ad, others []entities.Ad) bool {
+	approved := 0
+	for i := range others {
+		if others[i].ID == ad.ID {
+			continue
+		}
+		switch others[i].Status {
+		case entities.StatusRejected:
+			return false
+		case entities.StatusApproved:
+			approved++
+		}
+	}
+	return approved >= r.TrustedMinApproved
+}
+
+func (r Rules) decide(score int, trusted bool) entities.RuleDecision {
+	switch {
+	case score >= r.RejectScore:
+		return entities.DecisionReject
+	case score <= r.ApproveMaxScore && trusted:
+		return entities.DecisionApprove
+	default:
+		return entities.DecisionReview
+	}
+}
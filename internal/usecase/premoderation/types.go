@@ -0,0 +1,37 @@
+package premoderation
+
+import (
+	"ads-service/internal/domain/entities"
+	adRepo "ads-service/internal/repository/ad"
+	"ads-service/internal/repository/rulehit"
+	"ads-service/internal/usecase/admin"
+	customLogger "ads-service/pkg/logger"
+	"context"
+)
+
+type PreModerationService interface {
+	// Screen runs the rules on a submitted ad, records the hits and applies the decision: risky ads are
+	// rejected, clean ads of trusted sellers approved, everything else waits for a moderator.
+	Screen(ctx context.Context, ad *entities.Ad) (entities.RuleDecision, error)
+	// GetRuleHits returns the hits of every submission of the ad, newest first.
+	GetRuleHits(ctx context.Context, adID int) ([]entities.RuleHit, error)
+}
+
+type service struct {
+	rules      Rules
+	repo       rulehit.RuleHitRepository
+	adRepo     adRepo.AdRepository
+	moderation admin.AdminAdvertisementService
+	logger     customLogger.Logger
+}
+
+func NewPreModerationService(rules Rules, repo rulehit.RuleHitRepository, adRepository adRepo.AdRepository,
+	moderation admin.AdminAdvertisementService, logTool customLogger.Logger) PreModerationService {
+	return &service{
+		rules:      rules,
+		repo:       repo,
+		adRepo:     adRepository,
+		moderation: moderation,
+		logger:     logTool,
+	}
+}
@@ -0,0 +1,33 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package rejection
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRejectionService struct {
+	mock.Mock
+}
+
+func (m *MockRejectionService) GetTemplates(ctx context.Context) ([]entities.RejectionTemplate, error) {
+	args := m.Called(ctx)
+	if templates, ok := args.Get(0).([]entities.RejectionTemplate); ok {
+		return templates, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRejectionService) CreateTemplate(ctx context.Context, template *entities.RejectionTemplate) error {
+	args := m.Called(ctx, template)
+	return args.Error(0)
+}
+
+func (m *MockRejectionService) UpdateTemplate(ctx context.Context, template *entities.RejectionTemplate) error {
+	args := m.Called(ctx, template)
+	return args.Error(0)
+}
+
+var _ RejectionService = (*MockRejectionService)(nil)
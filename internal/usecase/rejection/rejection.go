@@ -0,0 +1,83 @@
+package rejection
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/pkg/tracing"
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxTextLength = 500
+
+var (
+	codePattern     = regexp.MustCompile(`^[a-z][a-z0-9_]{1,49}$`)
+	languagePattern = regexp.MustCompile(`^[a-z]{2}$`)
+)
+
+func (s *service) GetTemplates(ctx context.Context) (_ []entities.RejectionTemplate, err error) {
+	ctx, span := tracing.Start(ctx, "rejection.GetTemplates")
+	defer tracing.End(span, &err)
+
+	templates, err := s.repo.GetTemplates(ctx)
+	if err != nil {
+		s.logger.ERROR("error getting rejection templates: ", err)
+		return nil, usecaseerr.ErrGettingRejectionTemplates
+	}
+	return templates, nil
+}
+
+func (s *service) CreateTemplate(ctx context.Context, template *entities.RejectionTemplate) (err error) {
+	ctx, span := tracing.Start(ctx, "rejection.CreateTemplate")
+	defer tracing.End(span, &err)
+
+	if !codePattern.MatchString(template.Code) || !validTexts(template.Texts) {
+		return usecaseerr.ErrInvalidRejectionTemplate
+	}
+	if err := s.repo.CreateTemplate(ctx, template); err != nil {
+		if errors.Is(err, repoerr.ErrRejectionTemplateExists) {
+			return usecaseerr.ErrRejectionTemplateExists
+		}
+		s.logger.ERROR("error creating rejection template: ", err)
+		return usecaseerr.ErrSavingRejectionTemplate
+	}
+	s.logger.INFO("rejection template created: ", template.Code)
+	return nil
+}
+
+func (s *service) UpdateTemplate(ctx context.Context, template *entities.RejectionTemplate) (err error) {
+	ctx, span := tracing.Start(ctx, "rejection.UpdateTemplate")
+	defer tracing.End(span, &err)
+
+	if !validTexts(template.Texts) {
+		return usecaseerr.ErrInvalidRejectionTemplate
+	}
+	if !template.IsActive && template.IsSystem() {
+		return usecaseerr.ErrSystemRejectionTemplate
+	}
+	if err := s.repo.UpdateTemplate(ctx, template); err != nil {
+		if errors.Is(err, repoerr.ErrRejectionTemplateNotFound) {
+			return usecaseerr.ErrRejectionTemplateNotFound
+		}
+		s.logger.ERROR("error updating rejection template: ", err)
+		return usecaseerr.ErrSavingRejectionTemplate
+	}
+	s.logger.INFO("rejection template updated: ", template.Code)
+	return nil
+}
+
+// validTexts trims the texts in place, every language needs a text and English is required.
+func validTexts(texts map[string]string) bool {
+	for lang, text := range texts {
+		text = strings.TrimSpace(text)
+		if !languagePattern.MatchString(lang) || text == "" || utf8.RuneCountInString(text) > maxTextLength {
+			return false
+		}
+		texts[lang] = text
+	}
+	return texts[entities.DefaultLanguage] != ""
+}
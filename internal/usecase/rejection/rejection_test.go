@@ -0,0 +1,112 @@
+//nolint:all // testpackage
+package rejection
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/repository/rejection"
+	customLogger "ads-service/pkg/logger"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestService(t *testing.T) (RejectionService, *rejection.MockRejectionRepo) {
+	repo := &rejection.MockRejectionRepo{}
+	t.Cleanup(func() { repo.AssertExpectations(t) })
+	return NewRejectionService(repo, customLogger.Logger{}), repo
+}
+
+func TestService_GetTemplates(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		service, repo := newTestService(t)
+		repo.On("GetTemplates", mock.Anything).Return([]entities.RejectionTemplate{{Code: "spam"}}, nil)
+
+		templates, err := service.GetTemplates(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, templates, 1)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		service, repo := newTestService(t)
+		repo.On("GetTemplates", mock.Anything).Return(nil, errors.New("db error"))
+
+		_, err := service.GetTemplates(context.Background())
+		assert.Equal(t, usecaseerr.ErrGettingRejectionTemplates, err)
+	})
+}
+
+func TestService_CreateTemplate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		service, repo := newTestService(t)
+		template := &entities.RejectionTemplate{
+			Code:     "no_price",
+			Texts:    map[string]string{"en": " The price is missing. ", "ru": "Не указана цена."},
+			IsActive: true,
+		}
+		repo.On("CreateTemplate", mock.Anything, template).Return(nil)
+
+		assert.NoError(t, service.CreateTemplate(context.Background(), template))
+		assert.Equal(t, "The price is missing.", template.Texts["en"])
+	})
+
+	for name, template := range map[string]entities.RejectionTemplate{
+		"bad code":        {Code: "No Price", Texts: map[string]string{"en": "The price is missing."}},
+		"no english":      {Code: "no_price", Texts: map[string]string{"ru": "Не указана цена."}},
+		"empty text":      {Code: "no_price", Texts: map[string]string{"en": "The price is missing.", "ru": " "}},
+		"bad language":    {Code: "no_price", Texts: map[string]string{"en": "The price is missing.", "rus": "Цена"}},
+		"no texts at all": {Code: "no_price"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			service, _ := newTestService(t)
+
+			err := service.CreateTemplate(context.Background(), &template)
+			assert.Equal(t, usecaseerr.ErrInvalidRejectionTemplate, err)
+		})
+	}
+
+	t.Run("already exists", func(t *testing.T) {
+		service, repo := newTestService(t)
+		repo.On("CreateTemplate", mock.Anything, mock.Anything).Return(repoerr.ErrRejectionTemplateExists)
+
+		err := service.CreateTemplate(context.Background(),
+			&entities.RejectionTemplate{Code: "duplicate", Texts: map[string]string{"en": "Duplicate."}})
+		assert.Equal(t, usecaseerr.ErrRejectionTemplateExists, err)
+	})
+}
+
+func TestService_UpdateTemplate(t *testing.T) {
+	t.Run("deactivate", func(t *testing.T) {
+		service, repo := newTestService(t)
+		template := &entities.RejectionTemplate{Code: "poor_photos", Texts: map[string]string{"en": "Bad photos."}}
+		repo.On("UpdateTemplate", mock.Anything, template).Return(nil)
+
+		assert.NoError(t, service.UpdateTemplate(context.Background(), template))
+	})
+
+	t.Run("system template stays active", func(t *testing.T) {
+		service, _ := newTestService(t)
+
+		err := service.UpdateTemplate(context.Background(), &entities.RejectionTemplate{
+			Code:  entities.RejectionRulesViolation,
+			Texts: map[string]string{"en": "Rules violated."},
+		})
+		assert.Equal(t, usecaseerr.ErrSystemRejectionTemplate, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		service, repo := newTestService(t)
+		repo.On("UpdateTemplate", mock.Anything, mock.Anything).Return(repoerr.ErrRejectionTemplateNotFound)
+
+		err := service.UpdateTemplate(context.Background(), &entities.RejectionTemplate{
+			Code:     "unknown",
+			Texts:    map[string]string{"en": "Unknown."},
+			IsActive: true,
+		})
+		assert.Equal(t, usecaseerr.ErrRejectionTemplateNotFound, err)
+	})
+}
@@ -0,0 +1,24 @@
+package rejection
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/repository/rejection"
+	customLogger "ads-service/pkg/logger"
+	"context"
+)
+
+type RejectionService interface {
+	GetTemplates(ctx context.Context) ([]entities.RejectionTemplate, error)
+	CreateTemplate(ctx context.Context, template *entities.RejectionTemplate) error
+	// UpdateTemplate replaces the texts and the active flag of an existing template.
+	UpdateTemplate(ctx context.Context, template *entities.RejectionTemplate) error
+}
+
+type service struct {
+	repo   rejection.RejectionRepository
+	logger customLogger.Logger
+}
+
+func NewRejectionService(repo rejection.RejectionRepository, logTool customLogger.Logger) RejectionService {
+	return &service{repo: repo, logger: logTool}
+}
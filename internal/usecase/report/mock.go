@@ -0,0 +1,38 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package report
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReportService struct {
+	mock.Mock
+}
+
+func (m *MockReportService) Report(ctx context.Context, reporterID string, adID int, reason entities.ReportReason,
+	comment string) (*entities.Report, error) {
+	args := m.Called(ctx, reporterID, adID, reason, comment)
+	if report, ok := args.Get(0).(*entities.Report); ok {
+		return report, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockReportService) List(ctx context.Context, status entities.ReportStatus) ([]entities.Report, error) {
+	args := m.Called(ctx, status)
+	if reports, ok := args.Get(0).([]entities.Report); ok {
+		return reports, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockReportService) Resolve(ctx context.Context, adminID string, reportID int,
+	resolution entities.ReportStatus, note string) error {
+	args := m.Called(ctx, adminID, reportID, resolution, note)
+	return args.Error(0)
+}
+
+var _ ReportService = (*MockReportService)(nil)
@@ -0,0 +1,196 @@
+package report
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/pkg/db"
+	"ads-service/pkg/tracing"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// hideThreshold - distinct open reports after which a published ad goes back to moderation.
+	hideThreshold    = 3
+	maxCommentLength = 1000
+)
+
+func (s *service) Report(ctx context.Context, reporterID string, adID int, reason entities.ReportReason,
+	comment string) (_ *entities.Report, err error) {
+	ctx, span := tracing.Start(ctx, "report.Report")
+	defer tracing.End(span, &err)
+
+	comment = strings.TrimSpace(comment)
+	if !reason.IsValid() || utf8.RuneCountInString(comment) > maxCommentLength {
+		return nil, usecaseerr.ErrInvalidReport
+	}
+	// "other" says nothing by itself
+	if reason == entities.ReasonOther && comment == "" {
+		return nil, usecaseerr.ErrInvalidReport
+	}
+	if adID <= 0 {
+		return nil, usecaseerr.ErrInvalidParams
+	}
+
+	ad, err := s.adRepo.GetByID(ctx, adID)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrAdNotFound) {
+			return nil, usecaseerr.ErrAdNotAvailable
+		}
+		s.logger.ERROR("error getting reported ad: ", err)
+		return nil, fmt.Errorf("%w: %w", usecaseerr.ErrCreatingReport, err)
+	}
+	if ad.Status != entities.StatusApproved || !ad.IsActive {
+		return nil, usecaseerr.ErrAdNotAvailable
+	}
+	if ad.AuthorID == reporterID {
+		return nil, usecaseerr.ErrReportOwnAd
+	}
+
+	report := &entities.Report{AdID: adID, ReporterID: reporterID, Reason: reason, Comment: comment}
+	var hidden bool
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		hidden, err = s.repo.Create(ctx, report, hideThreshold)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, repoerr.ErrReportDuplicate) {
+			return nil, usecaseerr.ErrAlreadyReported
+		}
+		s.logger.ERROR("error creating report: ", err)
+		return nil, usecaseerr.ErrCreatingReport
+	}
+	if hidden {
+		s.notifyAuthor(ctx, ad, fmt.Sprintf("Your ad %q is back in moderation", ad.Title),
+			"Several users reported it. The ad is hidden until a moderator reviews it.")
+	}
+	return report, nil
+}
+
+func (s *service) List(ctx context.Context, status entities.ReportStatus) (_ []entities.Report, err error) {
+	ctx, span := tracing.Start(ctx, "report.List")
+	defer tracing.End(span, &err)
+
+	if status == "" {
+		status = entities.ReportOpen
+	}
+	switch status {
+	case entities.ReportOpen, entities.ReportDismissed, entities.ReportAdRemoved, entities.ReportUserBanned:
+	default:
+		return nil, usecaseerr.ErrInvalidParams
+	}
+
+	reports, err := s.repo.List(ctx, status)
+	if err != nil {
+		s.logger.ERROR("error getting reports: ", err)
+		return nil, usecaseerr.ErrGettingReports
+	}
+	return reports, nil
+}
+
+func (s *service) Resolve(ctx context.Context, adminID string, reportID int,
+	resolution entities.ReportStatus, note string) (err error) {
+	ctx, span := tracing.Start(ctx, "report.Resolve")
+	defer tracing.End(span, &err)
+
+	if reportID <= 0 {
+		return usecaseerr.ErrInvalidParams
+	}
+	report, err := s.repo.GetByID(ctx, reportID)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrReportNotFound) {
+			return usecaseerr.ErrReportNotFound
+		}
+		s.logger.ERROR("error getting report: ", err)
+		return fmt.Errorf("%w: %w", usecaseerr.ErrResolvingReport, err)
+	}
+	if report.Status != entities.ReportOpen {
+		return usecaseerr.ErrReportResolved
+	}
+	// the author deleted the ad meanwhile, there is nothing to remove and the author is not joined
+	if report.AdAuthorID == "" && resolution != entities.ReportDismissed {
+		return usecaseerr.ErrAdNotAvailable
+	}
+
+	note = strings.TrimSpace(note)
+	if note == "" {
+		note = "reported by users: " + string(report.Reason)
+	}
+
+	// the decision and the closed reports commit together, a failed ban leaves the ad and the reports as they were
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		switch resolution {
+		case entities.ReportDismissed:
+			// an ad hidden by reports stays in the moderation queue and is approved as usual
+		case entities.ReportAdRemoved:
+			if err := s.removeAd(ctx, report, note); err != nil {
+				return err
+			}
+		case entities.ReportUserBanned:
+			if err := s.banAuthor(ctx, adminID, report, note); err != nil {
+				return err
+			}
+		default:
+			return usecaseerr.ErrInvalidResolution
+		}
+
+		if _, err := s.repo.Resolve(ctx, report.AdID, resolution, adminID); err != nil {
+			s.logger.ERROR("error resolving reports: ", err)
+			return usecaseerr.ErrResolvingReport
+		}
+		return nil
+	})
+}
+
+func (s *service) removeAd(ctx context.Context, report *entities.Report, note string) error {
+	ad, err := s.adRepo.GetByID(ctx, report.AdID)
+	if err != nil {
+		s.logger.ERROR("error getting reported ad: ", err)
+		return usecaseerr.AdLookup(err)
+	}
+	if err = s.moderation.DeleteAd(ctx, report.AdID); err != nil {
+		return err
+	}
+	db.AfterCommit(ctx, func(ctx context.Context) {
+		s.notifyAuthor(ctx, ad, fmt.Sprintf("Your ad %q was removed", ad.Title), "Reason: "+note)
+	})
+	return nil
+}
+
+// banAuthor bans the author and rejects the reported ad, the moderator's note becomes the rejection note.
+// It runs inside the transaction of Resolve, so the ad is never rejected for a ban that did not happen.
+func (s *service) banAuthor(ctx context.Context, adminID string, report *entities.Report, note string) error {
+	author, err := s.userRepo.GetUserByID(ctx, report.AdAuthorID)
+	if err != nil {
+		s.logger.ERROR("error getting ad author: ", err)
+		return usecaseerr.UserLookup(err)
+	}
+	if author.Role == entities.RoleAdmin {
+		return usecaseerr.ErrCannotBanAdmin
+	}
+	rejection := entities.Rejection{Code: entities.RejectionAuthorBanned, Note: note}
+	if err = s.moderation.Reject(ctx, report.AdID, 0, adminID, rejection); err != nil {
+		return err
+	}
+	if err = s.userRepo.Ban(ctx, author.ID); err != nil {
+		s.logger.ERROR("error banning user: ", err)
+		return usecaseerr.ErrResolvingReport
+	}
+	return nil
+}
+
+func (s *service) notifyAuthor(ctx context.Context, ad *entities.Ad, subject, body string) {
+	err := s.notifier.Notify(ctx, entities.Notification{
+		Kind:    entities.NotificationModeration,
+		UserID:  ad.AuthorID,
+		Subject: subject,
+		Body:    body,
+	})
+	if err != nil {
+		s.logger.ERROR("error notifying ad author: ", err)
+	}
+}
@@ -0,0 +1,254 @@
+//nolint:all // testpackage
+package report
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	adRepo "ads-service/internal/repository/ad"
+	"ads-service/internal/repository/report"
+	"ads-service/internal/repository/user"
+	"ads-service/internal/usecase/admin"
+	"ads-service/pkg/db"
+	customLogger "ads-service/pkg/logger"
+	"ads-service/pkg/notifier"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type testDeps struct {
+	repo       *report.MockReportRepo
+	ads        *adRepo.MockAdRepo
+	users      *user.MockUserRepo
+	moderation *admin.MockAdminService
+	notify     *notifier.MockNotifier
+}
+
+func newTestService() (ReportService, testDeps) {
+	deps := testDeps{
+		repo:       &report.MockReportRepo{},
+		ads:        &adRepo.MockAdRepo{},
+		users:      &user.MockUserRepo{},
+		moderation: &admin.MockAdminService{},
+		notify:     &notifier.MockNotifier{},
+	}
+	tx := &db.MockTxManager{}
+	tx.On("WithinTx", mock.Anything).Return(nil).Maybe()
+	svc := NewReportService(deps.repo, deps.ads, deps.users, deps.moderation, deps.notify, tx, customLogger.Logger{})
+	return svc, deps
+}
+
+func (d testDeps) assertExpectations(t *testing.T) {
+	d.repo.AssertExpectations(t)
+	d.ads.AssertExpectations(t)
+	d.users.AssertExpectations(t)
+	d.moderation.AssertExpectations(t)
+	d.notify.AssertExpectations(t)
+}
+
+var publishedAd = &entities.Ad{ID: 1, AuthorID: "seller", Title: "iPhone", Status: entities.StatusApproved, IsActive: true}
+
+func TestReportService_Report(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		svc, deps := newTestService()
+		defer deps.assertExpectations(t)
+
+		deps.ads.On("GetByID", mock.Anything, 1).Return(publishedAd, nil)
+		deps.repo.On("Create", mock.Anything, mock.MatchedBy(func(r *entities.Report) bool {
+			return r.ReporterID == "buyer" && r.Reason == entities.ReasonScam && r.Comment == "asks for prepayment"
+		}), hideThreshold).Return(false, nil)
+
+		report, err := svc.Report(context.Background(), "buyer", 1, entities.ReasonScam, " asks for prepayment ")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.AdID)
+	})
+
+	t.Run("threshold hides the ad and tells the author", func(t *testing.T) {
+		svc, deps := newTestService()
+		defer deps.assertExpectations(t)
+
+		deps.ads.On("GetByID", mock.Anything, 1).Return(publishedAd, nil)
+		deps.repo.On("Create", mock.Anything, mock.Anything, hideThreshold).Return(true, nil)
+		deps.notify.On("Notify", mock.Anything, mock.MatchedBy(func(n entities.Notification) bool {
+			return n.UserID == "seller" && n.Kind == entities.NotificationModeration
+		})).Return(nil)
+
+		_, err := svc.Report(context.Background(), "buyer", 1, entities.ReasonProhibited, "")
+		assert.NoError(t, err)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		svc, deps := newTestService()
+		defer deps.assertExpectations(t)
+
+		deps.ads.On("GetByID", mock.Anything, 1).Return(publishedAd, nil)
+		deps.repo.On("Create", mock.Anything, mock.Anything, hideThreshold).Return(false, repoerr.ErrReportDuplicate)
+
+		_, err := svc.Report(context.Background(), "buyer", 1, entities.ReasonScam, "")
+		assert.Equal(t, usecaseerr.ErrAlreadyReported, err)
+	})
+
+	t.Run("own ad", func(t *testing.T) {
+		svc, deps := newTestService()
+		defer deps.assertExpectations(t)
+
+		deps.ads.On("GetByID", mock.Anything, 1).Return(publishedAd, nil)
+
+		_, err := svc.Report(context.Background(), "seller", 1, entities.ReasonScam, "")
+		assert.Equal(t, usecaseerr.ErrReportOwnAd, err)
+	})
+
+	t.Run("unpublished ad", func(t *testing.T) {
+		svc, deps := newTestService()
+		defer deps.assertExpectations(t)
+
+		deps.ads.On("GetByID", mock.Anything, 2).Return(&entities.Ad{ID: 2, Status: entities.StatusPending}, nil)
+
+		_, err := svc.Report(context.Background(), "buyer", 2, entities.ReasonScam, "")
+		assert.Equal(t, usecaseerr.ErrAdNotAvailable, err)
+	})
+
+	for name, tc := range map[string]struct {
+		reason  entities.ReportReason
+		comment string
+	}{
+		"unknown reason":        {reason: "boring"},
+		"other without comment": {reason: entities.ReasonOther, comment: "  "},
+		"comment too long":      {reason: entities.ReasonScam, comment: strings.Repeat("a", maxCommentLength+1)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			svc, deps := newTestService()
+			defer deps.assertExpectations(t)
+
+			_, err := svc.Report(context.Background(), "buyer", 1, tc.reason, tc.comment)
+			assert.Equal(t, usecaseerr.ErrInvalidReport, err)
+		})
+	}
+}
+
+func TestReportService_List(t *testing.T) {
+	t.Run("open by default", func(t *testing.T) {
+		svc, deps := newTestService()
+		defer deps.assertExpectations(t)
+
+		deps.repo.On("List", mock.Anything, entities.ReportOpen).Return([]entities.Report{{ID: 1}}, nil)
+
+		reports, err := svc.List(context.Background(), "")
+		assert.NoError(t, err)
+		assert.Len(t, reports, 1)
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		svc, _ := newTestService()
+
+		_, err := svc.List(context.Background(), "closed")
+		assert.Equal(t, usecaseerr.ErrInvalidParams, err)
+	})
+}
+
+func TestReportService_Resolve(t *testing.T) {
+	openReport := func() *entities.Report {
+		return &entities.Report{ID: 5, AdID: 1, AdAuthorID: "seller", Reason: entities.ReasonScam,
+			Status: entities.ReportOpen}
+	}
+
+	t.Run("dismissed", func(t *testing.T) {
+		svc, deps := newTestService()
+		defer deps.assertExpectations(t)
+
+		deps.repo.On("GetByID", mock.Anything, 5).Return(openReport(), nil)
+		deps.repo.On("Resolve", mock.Anything, 1, entities.ReportDismissed, "admin").Return(int64(3), nil)
+
+		assert.NoError(t, svc.Resolve(context.Background(), "admin", 5, entities.ReportDismissed, ""))
+	})
+
+	t.Run("ad removed", func(t *testing.T) {
+		svc, deps := newTestService()
+		defer deps.assertExpectations(t)
+
+		deps.repo.On("GetByID", mock.Anything, 5).Return(openReport(), nil)
+		deps.ads.On("GetByID", mock.Anything, 1).Return(publishedAd, nil)
+		deps.moderation.On("DeleteAd", mock.Anything, 1).Return(nil)
+		deps.notify.On("Notify", mock.Anything, mock.MatchedBy(func(n entities.Notification) bool {
+			return n.UserID == "seller" && n.Body == "Reason: reported by users: scam"
+		})).Return(nil)
+		deps.repo.On("Resolve", mock.Anything, 1, entities.ReportAdRemoved, "admin").Return(int64(1), nil)
+
+		assert.NoError(t, svc.Resolve(context.Background(), "admin", 5, entities.ReportAdRemoved, ""))
+	})
+
+	t.Run("user banned", func(t *testing.T) {
+		svc, deps := newTestService()
+		defer deps.assertExpectations(t)
+
+		deps.repo.On("GetByID", mock.Anything, 5).Return(openReport(), nil)
+		deps.users.On("GetUserByID", mock.Anything, "seller").
+			Return(&entities.User{ID: "seller", Role: entities.RoleUser}, nil)
+		deps.moderation.On("Reject", mock.Anything, 1, 0, "admin",
+			entities.Rejection{Code: entities.RejectionAuthorBanned, Note: "fake shop"}).Return(nil)
+		deps.users.On("Ban", mock.Anything, "seller").Return(nil)
+		deps.repo.On("Resolve", mock.Anything, 1, entities.ReportUserBanned, "admin").Return(int64(1), nil)
+
+		assert.NoError(t, svc.Resolve(context.Background(), "admin", 5, entities.ReportUserBanned, "fake shop"))
+	})
+
+	t.Run("admins are not banned", func(t *testing.T) {
+		svc, deps := newTestService()
+		defer deps.assertExpectations(t)
+
+		deps.repo.On("GetByID", mock.Anything, 5).Return(openReport(), nil)
+		deps.users.On("GetUserByID", mock.Anything, "seller").
+			Return(&entities.User{ID: "seller", Role: entities.RoleAdmin}, nil)
+
+		err := svc.Resolve(context.Background(), "admin", 5, entities.ReportUserBanned, "")
+		assert.Equal(t, usecaseerr.ErrCannotBanAdmin, err)
+	})
+
+	t.Run("already resolved", func(t *testing.T) {
+		svc, deps := newTestService()
+		defer deps.assertExpectations(t)
+
+		resolved := openReport()
+		resolved.Status = entities.ReportDismissed
+		deps.repo.On("GetByID", mock.Anything, 5).Return(resolved, nil)
+
+		err := svc.Resolve(context.Background(), "admin", 5, entities.ReportAdRemoved, "")
+		assert.Equal(t, usecaseerr.ErrReportResolved, err)
+	})
+
+	t.Run("ad deleted by the author", func(t *testing.T) {
+		svc, deps := newTestService()
+		defer deps.assertExpectations(t)
+
+		gone := openReport()
+		gone.AdAuthorID = ""
+		deps.repo.On("GetByID", mock.Anything, 5).Return(gone, nil)
+
+		err := svc.Resolve(context.Background(), "admin", 5, entities.ReportUserBanned, "")
+		assert.Equal(t, usecaseerr.ErrAdNotAvailable, err)
+	})
+
+	t.Run("unknown resolution", func(t *testing.T) {
+		svc, deps := newTestService()
+		defer deps.assertExpectations(t)
+
+		deps.repo.On("GetByID", mock.Anything, 5).Return(openReport(), nil)
+
+		err := svc.Resolve(context.Background(), "admin", 5, entities.ReportOpen, "")
+		assert.Equal(t, usecaseerr.ErrInvalidResolution, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		svc, deps := newTestService()
+		defer deps.assertExpectations(t)
+
+		deps.repo.On("GetByID", mock.Anything, 5).Return(nil, repoerr.ErrReportNotFound)
+
+		err := svc.Resolve(context.Background(), "admin", 5, entities.ReportDismissed, "")
+		assert.Equal(t, usecaseerr.ErrReportNotFound, err)
+	})
+}
@@ -0,0 +1,47 @@
+package report
+
+import (
+	"ads-service/internal/domain/entities"
+	adRepo "ads-service/internal/repository/ad"
+	"ads-service/internal/repository/report"
+	"ads-service/internal/repository/user"
+	"ads-service/internal/usecase/admin"
+	"ads-service/pkg/db"
+	customLogger "ads-service/pkg/logger"
+	"ads-service/pkg/notifier"
+	"context"
+)
+
+type ReportService interface {
+	// Report flags a published ad of another user. Enough distinct reports send the ad back to moderation.
+	Report(ctx context.Context, reporterID string, adID int, reason entities.ReportReason,
+		comment string) (*entities.Report, error)
+	// List returns the moderation queue, open reports by default.
+	List(ctx context.Context, status entities.ReportStatus) ([]entities.Report, error)
+	// Resolve closes the report together with all other open reports on the same ad.
+	Resolve(ctx context.Context, adminID string, reportID int, resolution entities.ReportStatus, note string) error
+}
+
+type service struct {
+	repo       report.ReportRepository
+	adRepo     adRepo.AdRepository
+	userRepo   user.UserRepository
+	moderation admin.AdminAdvertisementService
+	notifier   notifier.Notifier
+	tx         db.TxManager
+	logger     customLogger.Logger
+}
+
+func NewReportService(repo report.ReportRepository, adRepository adRepo.AdRepository, userRepo user.UserRepository,
+	moderation admin.AdminAdvertisementService, notify notifier.Notifier, tx db.TxManager,
+	logTool customLogger.Logger) ReportService {
+	return &service{
+		repo:       repo,
+		adRepo:     adRepository,
+		userRepo:   userRepo,
+		moderation: moderation,
+		notifier:   notify,
+		tx:         tx,
+		logger:     logTool,
+	}
+}
@@ -0,0 +1,48 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package savedsearch
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSavedSearchService struct {
+	mock.Mock
+}
+
+func (m *MockSavedSearchService) Create(ctx context.Context, userID string, search *entities.SavedSearch) error {
+	args := m.Called(ctx, userID, search)
+	return args.Error(0)
+}
+
+func (m *MockSavedSearchService) List(ctx context.Context, userID string) ([]entities.SavedSearch, error) {
+	args := m.Called(ctx, userID)
+	if searches, ok := args.Get(0).([]entities.SavedSearch); ok {
+		return searches, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockSavedSearchService) Delete(ctx context.Context, userID string, id int) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockSavedSearchService) NotifyMatches(ctx context.Context, ad *entities.Ad) error {
+	args := m.Called(ctx, ad)
+	return args.Error(0)
+}
+
+func (m *MockSavedSearchService) SendDigests(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockSavedSearchService) RunDigests(ctx context.Context, interval time.Duration) {
+	m.Called(ctx, interval)
+}
+
+var _ SavedSearchService = (*MockSavedSearchService)(nil)
@@ -0,0 +1,215 @@
+package savedsearch
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/pkg/tracing"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxSavedSearches = 20
+	maxNameLength    = 100
+	maxFieldLength   = 255
+)
+
+func (s *service) Create(ctx context.Context, userID string, search *entities.SavedSearch) (err error) {
+	ctx, span := tracing.Start(ctx, "savedsearch.Create")
+	defer tracing.End(span, &err)
+
+	search.Name = strings.TrimSpace(search.Name)
+	search.Text = strings.TrimSpace(search.Text)
+	search.Location = strings.TrimSpace(search.Location)
+	if search.Mode == "" {
+		search.Mode = entities.NotifyInstant
+	}
+	if err := validate(search); err != nil {
+		return err
+	}
+
+	count, err := s.repo.CountByUser(ctx, userID)
+	if err != nil {
+		s.logger.ERROR("error counting saved searches: ", err)
+		return usecaseerr.ErrCreatingSavedSearch
+	}
+	if count >= maxSavedSearches {
+		return usecaseerr.ErrTooManySavedSearches
+	}
+
+	search.UserID = userID
+	if err = s.repo.Create(ctx, search); err != nil {
+		s.logger.ERROR("error creating saved search: ", err)
+		return usecaseerr.ErrCreatingSavedSearch
+	}
+	return nil
+}
+
+func (s *service) List(ctx context.Context, userID string) (_ []entities.SavedSearch, err error) {
+	ctx, span := tracing.Start(ctx, "savedsearch.List")
+	defer tracing.End(span, &err)
+
+	searches, err := s.repo.GetByUser(ctx, userID)
+	if err != nil {
+		s.logger.ERROR("error getting saved searches: ", err)
+		return nil, usecaseerr.ErrGettingSavedSearches
+	}
+	return searches, nil
+}
+
+func (s *service) Delete(ctx context.Context, userID string, id int) (err error) {
+	ctx, span := tracing.Start(ctx, "savedsearch.Delete")
+	defer tracing.End(span, &err)
+
+	if id <= 0 {
+		return usecaseerr.ErrInvalidParams
+	}
+	if err := s.repo.Delete(ctx, userID, id); err != nil {
+		if errors.Is(err, repoerr.ErrSavedSearchNotFound) {
+			return usecaseerr.ErrSavedSearchNotFound
+		}
+		s.logger.ERROR("error deleting saved search: ", err)
+		return usecaseerr.ErrDeletingSavedSearch
+	}
+	return nil
+}
+
+func (s *service) NotifyMatches(ctx context.Context, ad *entities.Ad) (err error) {
+	ctx, span := tracing.Start(ctx, "savedsearch.NotifyMatches")
+	defer tracing.End(span, &err)
+
+	searches, err := s.repo.MatchAd(ctx, ad)
+	if err != nil {
+		s.logger.ERROR("error matching saved searches: ", err)
+		return usecaseerr.ErrMatchingSavedSearches
+	}
+
+	for _, search := range searches {
+		if search.Mode != entities.NotifyInstant {
+			continue
+		}
+		err = s.notifier.Notify(ctx, entities.Notification{
+			Kind:    entities.NotificationSavedSearch,
+			UserID:  search.UserID,
+			Subject: fmt.Sprintf("New ad for %q", search.Name),
+			Body:    describeAd(ad.ID, ad.Title, ad.Price),
+		})
+		if err != nil {
+			// the match stays pending, it is not lost but it is not resent either
+			s.logger.ERROR("error sending saved search alert: ", err)
+			continue
+		}
+		if err = s.repo.MarkNotified(ctx, search.ID, []int{ad.ID}); err != nil {
+			s.logger.ERROR("error marking saved search alert: ", err)
+		}
+	}
+	s.logger.INFO("ad ", ad.ID, " matched ", len(searches), " saved searches")
+	return nil
+}
+
+func (s *service) SendDigests(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "savedsearch.SendDigests")
+	defer tracing.End(span, &err)
+
+	matches, err := s.repo.PendingDigest(ctx)
+	if err != nil {
+		s.logger.ERROR("error getting pending matches: ", err)
+		return usecaseerr.ErrSendingDigest
+	}
+
+	var failed bool
+	// matches are ordered by user, so every user is a contiguous run
+	for start := 0; start < len(matches); {
+		end := start
+		for end < len(matches) && matches[end].UserID == matches[start].UserID {
+			end++
+		}
+		if err = s.sendDigest(ctx, matches[start:end]); err != nil {
+			s.logger.ERROR("error sending digest to user ", matches[start].UserID, ": ", err)
+			failed = true
+		}
+		start = end
+	}
+	if failed {
+		return usecaseerr.ErrSendingDigest
+	}
+	return nil
+}
+
+func (s *service) RunDigests(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.SendDigests(ctx); err != nil {
+				s.logger.ERROR("saved search digest: ", err)
+			}
+		}
+	}
+}
+
+func (s *service) sendDigest(ctx context.Context, matches []entities.SearchMatch) error {
+	var (
+		body     strings.Builder
+		bySearch = make(map[int][]int)
+		order    []int
+	)
+	for _, m := range matches {
+		if _, ok := bySearch[m.SearchID]; !ok {
+			order = append(order, m.SearchID)
+			fmt.Fprintf(&body, "%s:\n", m.SearchName)
+		}
+		bySearch[m.SearchID] = append(bySearch[m.SearchID], m.AdID)
+		fmt.Fprintf(&body, "  %s\n", describeAd(m.AdID, m.AdTitle, m.AdPrice))
+	}
+
+	err := s.notifier.Notify(ctx, entities.Notification{
+		Kind:    entities.NotificationSavedSearch,
+		UserID:  matches[0].UserID,
+		Subject: fmt.Sprintf("%d new ads for your saved searches", len(matches)),
+		Body:    body.String(),
+	})
+	if err != nil {
+		return err
+	}
+	for _, searchID := range order {
+		if err = s.repo.MarkNotified(ctx, searchID, bySearch[searchID]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validate(search *entities.SavedSearch) error {
+	switch {
+	case search.Name == "" || utf8.RuneCountInString(search.Name) > maxNameLength:
+		return usecaseerr.ErrInvalidSavedSearch
+	case utf8.RuneCountInString(search.Text) > maxFieldLength,
+		utf8.RuneCountInString(search.Location) > maxFieldLength:
+		return usecaseerr.ErrInvalidSavedSearch
+	case search.CategoryID < 0 || search.PriceMin < 0 || search.PriceMax < 0:
+		return usecaseerr.ErrInvalidSavedSearch
+	case search.PriceMax > 0 && search.PriceMin > search.PriceMax:
+		return usecaseerr.ErrInvalidSavedSearch
+	case search.Mode != entities.NotifyInstant && search.Mode != entities.NotifyDaily:
+		return usecaseerr.ErrInvalidSavedSearch
+	}
+	// a search without criteria would match every published ad
+	if search.CategoryID == 0 && search.PriceMin == 0 && search.PriceMax == 0 &&
+		search.Text == "" && search.Location == "" {
+		return usecaseerr.ErrInvalidSavedSearch
+	}
+	return nil
+}
+
+func describeAd(id int, title string, price int) string {
+	return fmt.Sprintf("%s, price %d (ad #%d)", title, price, id)
+}
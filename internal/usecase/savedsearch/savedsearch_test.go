@@ -0,0 +1,172 @@
+package savedsearch
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/repository/savedsearch"
+	customLogger "ads-service/pkg/logger"
+	"ads-service/pkg/notifier"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestService_Create(t *testing.T) {
+	t.Run("success with default mode", func(t *testing.T) {
+		repo := &savedsearch.MockSavedSearchRepo{}
+		defer repo.AssertExpectations(t)
+		service := NewSavedSearchService(repo, &notifier.MockNotifier{}, customLogger.Logger{})
+
+		repo.On("CountByUser", mock.Anything, "buyer").Return(3, nil)
+		repo.On("Create", mock.Anything, mock.MatchedBy(func(s *entities.SavedSearch) bool {
+			return s.UserID == "buyer" && s.Mode == entities.NotifyInstant && s.Text == "bmw"
+		})).Return(nil)
+
+		err := service.Create(context.Background(), "buyer",
+			&entities.SavedSearch{Name: " Cars ", Text: " bmw ", PriceMax: 10000})
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid searches", func(t *testing.T) {
+		service := NewSavedSearchService(&savedsearch.MockSavedSearchRepo{}, &notifier.MockNotifier{},
+			customLogger.Logger{})
+
+		cases := map[string]entities.SavedSearch{
+			"no name":        {Text: "bmw"},
+			"no criteria":    {Name: "Everything"},
+			"price range":    {Name: "Cars", PriceMin: 500, PriceMax: 100},
+			"negative price": {Name: "Cars", PriceMin: -1},
+			"unknown mode":   {Name: "Cars", Text: "bmw", Mode: "weekly"},
+			"long name":      {Name: strings.Repeat("a", maxNameLength+1), Text: "bmw"},
+		}
+		for name, search := range cases {
+			err := service.Create(context.Background(), "buyer", &search)
+			assert.Equal(t, usecaseerr.ErrInvalidSavedSearch, err, name)
+		}
+	})
+
+	t.Run("limit reached", func(t *testing.T) {
+		repo := &savedsearch.MockSavedSearchRepo{}
+		defer repo.AssertExpectations(t)
+		service := NewSavedSearchService(repo, &notifier.MockNotifier{}, customLogger.Logger{})
+
+		repo.On("CountByUser", mock.Anything, "buyer").Return(maxSavedSearches, nil)
+
+		err := service.Create(context.Background(), "buyer", &entities.SavedSearch{Name: "Cars", CategoryID: 1})
+		assert.Equal(t, usecaseerr.ErrTooManySavedSearches, err)
+	})
+}
+
+func TestService_Delete(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		repo := &savedsearch.MockSavedSearchRepo{}
+		defer repo.AssertExpectations(t)
+		service := NewSavedSearchService(repo, &notifier.MockNotifier{}, customLogger.Logger{})
+
+		repo.On("Delete", mock.Anything, "buyer", 5).Return(repoerr.ErrSavedSearchNotFound)
+
+		err := service.Delete(context.Background(), "buyer", 5)
+		assert.Equal(t, usecaseerr.ErrSavedSearchNotFound, err)
+	})
+}
+
+func TestService_NotifyMatches(t *testing.T) {
+	ad := &entities.Ad{ID: 10, Title: "BMW X5", Price: 9000}
+
+	t.Run("instant searches are notified, daily wait", func(t *testing.T) {
+		repo := &savedsearch.MockSavedSearchRepo{}
+		notify := &notifier.MockNotifier{}
+		defer repo.AssertExpectations(t)
+		defer notify.AssertExpectations(t)
+		service := NewSavedSearchService(repo, notify, customLogger.Logger{})
+
+		repo.On("MatchAd", mock.Anything, ad).Return([]entities.SavedSearch{
+			{ID: 1, UserID: "buyer-1", Name: "Cars", Mode: entities.NotifyInstant},
+			{ID: 2, UserID: "buyer-2", Name: "BMW", Mode: entities.NotifyDaily},
+		}, nil)
+		notify.On("Notify", mock.Anything, mock.MatchedBy(func(n entities.Notification) bool {
+			return n.UserID == "buyer-1" && strings.Contains(n.Body, "BMW X5")
+		})).Return(nil).Once()
+		repo.On("MarkNotified", mock.Anything, 1, []int{10}).Return(nil).Once()
+
+		assert.NoError(t, service.NotifyMatches(context.Background(), ad))
+	})
+
+	t.Run("failed alert stays pending", func(t *testing.T) {
+		repo := &savedsearch.MockSavedSearchRepo{}
+		notify := &notifier.MockNotifier{}
+		defer repo.AssertExpectations(t)
+		service := NewSavedSearchService(repo, notify, customLogger.Logger{})
+
+		repo.On("MatchAd", mock.Anything, ad).Return([]entities.SavedSearch{
+			{ID: 1, UserID: "buyer-1", Mode: entities.NotifyInstant},
+		}, nil)
+		notify.On("Notify", mock.Anything, mock.Anything).Return(errors.New("smtp down"))
+
+		assert.NoError(t, service.NotifyMatches(context.Background(), ad))
+		repo.AssertNotCalled(t, "MarkNotified", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("match error", func(t *testing.T) {
+		repo := &savedsearch.MockSavedSearchRepo{}
+		service := NewSavedSearchService(repo, &notifier.MockNotifier{}, customLogger.Logger{})
+
+		repo.On("MatchAd", mock.Anything, ad).Return(nil, repoerr.ErrSavedSearchMatch)
+
+		err := service.NotifyMatches(context.Background(), ad)
+		assert.Equal(t, usecaseerr.ErrMatchingSavedSearches, err)
+	})
+}
+
+func TestService_SendDigests(t *testing.T) {
+	t.Run("one message per user", func(t *testing.T) {
+		repo := &savedsearch.MockSavedSearchRepo{}
+		notify := &notifier.MockNotifier{}
+		defer repo.AssertExpectations(t)
+		defer notify.AssertExpectations(t)
+		service := NewSavedSearchService(repo, notify, customLogger.Logger{})
+
+		repo.On("PendingDigest", mock.Anything).Return([]entities.SearchMatch{
+			{UserID: "buyer-1", SearchID: 1, SearchName: "Cars", AdID: 10, AdTitle: "BMW"},
+			{UserID: "buyer-1", SearchID: 1, SearchName: "Cars", AdID: 11, AdTitle: "Audi"},
+			{UserID: "buyer-1", SearchID: 2, SearchName: "Bikes", AdID: 12, AdTitle: "BMX"},
+			{UserID: "buyer-2", SearchID: 3, SearchName: "Cheap", AdID: 10, AdTitle: "BMW"},
+		}, nil)
+		notify.On("Notify", mock.Anything, mock.MatchedBy(func(n entities.Notification) bool {
+			return n.UserID == "buyer-1" && strings.Contains(n.Body, "Audi") && strings.Contains(n.Body, "BMX")
+		})).Return(nil).Once()
+		notify.On("Notify", mock.Anything, mock.MatchedBy(func(n entities.Notification) bool {
+			return n.UserID == "buyer-2"
+		})).Return(nil).Once()
+		repo.On("MarkNotified", mock.Anything, 1, []int{10, 11}).Return(nil).Once()
+		repo.On("MarkNotified", mock.Anything, 2, []int{12}).Return(nil).Once()
+		repo.On("MarkNotified", mock.Anything, 3, []int{10}).Return(nil).Once()
+
+		assert.NoError(t, service.SendDigests(context.Background()))
+	})
+
+	t.Run("failure for one user does not stop the rest", func(t *testing.T) {
+		repo := &savedsearch.MockSavedSearchRepo{}
+		notify := &notifier.MockNotifier{}
+		defer repo.AssertExpectations(t)
+		service := NewSavedSearchService(repo, notify, customLogger.Logger{})
+
+		repo.On("PendingDigest", mock.Anything).Return([]entities.SearchMatch{
+			{UserID: "buyer-1", SearchID: 1, AdID: 10},
+			{UserID: "buyer-2", SearchID: 3, AdID: 10},
+		}, nil)
+		notify.On("Notify", mock.Anything, mock.MatchedBy(func(n entities.Notification) bool {
+			return n.UserID == "buyer-1"
+		})).Return(errors.New("smtp down"))
+		notify.On("Notify", mock.Anything, mock.Anything).Return(nil)
+		repo.On("MarkNotified", mock.Anything, 3, []int{10}).Return(nil).Once()
+
+		err := service.SendDigests(context.Background())
+		assert.Equal(t, usecaseerr.ErrSendingDigest, err)
+	})
+}
@@ -0,0 +1,38 @@
+package savedsearch
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/repository/savedsearch"
+	customLogger "ads-service/pkg/logger"
+	"ads-service/pkg/notifier"
+	"context"
+	"time"
+)
+
+type SavedSearchService interface {
+	Create(ctx context.Context, userID string, search *entities.SavedSearch) error
+	List(ctx context.Context, userID string) ([]entities.SavedSearch, error)
+	Delete(ctx context.Context, userID string, id int) error
+	// NotifyMatches is called when an ad gets published. Owners of instant searches are notified
+	// right away, matches of daily searches wait for SendDigests.
+	NotifyMatches(ctx context.Context, ad *entities.Ad) error
+	// SendDigests sends every user one message with all pending matches of their daily searches.
+	SendDigests(ctx context.Context) error
+	// RunDigests calls SendDigests every interval until ctx is cancelled.
+	RunDigests(ctx context.Context, interval time.Duration)
+}
+
+type service struct {
+	repo     savedsearch.SavedSearchRepository
+	notifier notifier.Notifier
+	logger   customLogger.Logger
+}
+
+func NewSavedSearchService(repo savedsearch.SavedSearchRepository, notify notifier.Notifier,
+	logTool customLogger.Logger) SavedSearchService {
+	return &service{
+		repo:     repo,
+		notifier: notify,
+		logger:   logTool,
+	}
+}
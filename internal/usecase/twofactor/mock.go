@@ -0,0 +1,55 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package twofactor
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTwoFactorService struct {
+	mock.Mock
+}
+
+func (m *MockTwoFactorService) Enroll(ctx context.Context, userID string) (*entities.TOTPEnrollment, error) {
+	args := m.Called(ctx, userID)
+	if enrollment, ok := args.Get(0).(*entities.TOTPEnrollment); ok {
+		return enrollment, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTwoFactorService) Confirm(ctx context.Context, userID, code string) ([]string, error) {
+	args := m.Called(ctx, userID, code)
+	if codes, ok := args.Get(0).([]string); ok {
+		return codes, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTwoFactorService) Disable(ctx context.Context, userID, code, ip string) error {
+	args := m.Called(ctx, userID, code, ip)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID, code,
+	ip string) ([]string, error) {
+	args := m.Called(ctx, userID, code, ip)
+	if codes, ok := args.Get(0).([]string); ok {
+		return codes, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTwoFactorService) IsEnabled(ctx context.Context, userID string) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTwoFactorService) Verify(ctx context.Context, userID, code string) error {
+	args := m.Called(ctx, userID, code)
+	return args.Error(0)
+}
+
+var _ TwoFactorService = (*MockTwoFactorService)(nil)
@@ -0,0 +1,261 @@
+package twofactor
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/pkg/totp"
+	"ads-service/pkg/tracing"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	recoveryCodesCount = 10
+	recoveryCodeHalf   = 5
+	// Crockford's base32: 32 symbols without i, l, o, u, readable when written down
+	recoveryAlphabet = "0123456789abcdefghjkmnpqrstvwxyz"
+)
+
+func (s *service) Enroll(ctx context.Context, userID string) (_ *entities.TOTPEnrollment, err error) {
+	ctx, span := tracing.Start(ctx, "twofactor.Enroll")
+	defer tracing.End(span, &err)
+
+	current, err := s.repo.Get(ctx, userID)
+	if err != nil && !errors.Is(err, repoerr.ErrTOTPNotFound) {
+		s.logger.ERROR("Error getting two-factor settings:", err)
+		return nil, usecaseerr.ErrTwoFactorCheck
+	}
+	if current != nil && current.Enabled {
+		return nil, usecaseerr.ErrTwoFactorAlreadyEnabled
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		s.logger.ERROR("Error getting user for two-factor enrollment:", err)
+		return nil, usecaseerr.UserLookup(err)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		s.logger.ERROR("Error generating TOTP secret:", err)
+		return nil, usecaseerr.ErrTwoFactorSetup
+	}
+	if err = s.repo.Save(ctx, &entities.TOTP{
+		CreatedAt: time.Now().UTC(),
+		UserID:    userID,
+		Secret:    secret,
+	}); err != nil {
+		s.logger.ERROR("Error saving TOTP secret:", err)
+		return nil, usecaseerr.ErrTwoFactorSetup
+	}
+
+	s.logger.INFO("Two-factor enrollment started for user:", userID)
+	return &entities.TOTPEnrollment{
+		Secret: secret,
+		URI:    totp.URI(s.config.Issuer, user.Phone, secret),
+	}, nil
+}
+
+func (s *service) Confirm(ctx context.Context, userID, code string) (_ []string, err error) {
+	ctx, span := tracing.Start(ctx, "twofactor.Confirm")
+	defer tracing.End(span, &err)
+
+	current, err := s.repo.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrTOTPNotFound) {
+			return nil, usecaseerr.ErrTwoFactorNotEnabled
+		}
+		s.logger.ERROR("Error getting two-factor settings:", err)
+		return nil, usecaseerr.ErrTwoFactorCheck
+	}
+	if current.Enabled {
+		return nil, usecaseerr.ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := totp.Validate(current.Secret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return nil, usecaseerr.ErrInvalidTwoFactorCode
+	}
+	current.Enabled = true
+	current.ConfirmedAt = time.Now().UTC()
+	current.LastUsedStep = step
+	// enabled together with its recovery codes, 2FA without them would lock out a user who lost the device
+	var codes []string
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Save(ctx, current); err != nil {
+			s.logger.ERROR("Error enabling two-factor authentication:", err)
+			return usecaseerr.ErrTwoFactorSetup
+		}
+		issued, err := s.issueRecoveryCodes(ctx, userID)
+		codes = issued
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.logger.INFO("Two-factor authentication enabled for user:", userID)
+	return codes, nil
+}
+
+func (s *service) Disable(ctx context.Context, userID, code, ip string) (err error) {
+	ctx, span := tracing.Start(ctx, "twofactor.Disable")
+	defer tracing.End(span, &err)
+
+	if err := s.verifyLimited(ctx, userID, code, ip); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, userID); err != nil {
+		s.logger.ERROR("Error disabling two-factor authentication:", err)
+		return usecaseerr.ErrTwoFactorSetup
+	}
+	s.logger.INFO("Two-factor authentication disabled for user:", userID)
+	return nil
+}
+
+func (s *service) RegenerateRecoveryCodes(ctx context.Context, userID, code,
+	ip string) (_ []string, err error) {
+	ctx, span := tracing.Start(ctx, "twofactor.RegenerateRecoveryCodes")
+	defer tracing.End(span, &err)
+
+	if err := s.verifyLimited(ctx, userID, code, ip); err != nil {
+		return nil, err
+	}
+	return s.issueRecoveryCodes(ctx, userID)
+}
+
+func (s *service) IsEnabled(ctx context.Context, userID string) (_ bool, err error) {
+	ctx, span := tracing.Start(ctx, "twofactor.IsEnabled")
+	defer tracing.End(span, &err)
+
+	current, err := s.repo.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrTOTPNotFound) {
+			return false, nil
+		}
+		s.logger.ERROR("Error getting two-factor settings:", err)
+		return false, usecaseerr.ErrTwoFactorCheck
+	}
+	return current.Enabled, nil
+}
+
+func (s *service) Verify(ctx context.Context, userID, code string) (err error) {
+	ctx, span := tracing.Start(ctx, "twofactor.Verify")
+	defer tracing.End(span, &err)
+
+	current, err := s.repo.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repoerr.ErrTOTPNotFound) {
+			return usecaseerr.ErrTwoFactorNotEnabled
+		}
+		s.logger.ERROR("Error getting two-factor settings:", err)
+		return usecaseerr.ErrTwoFactorCheck
+	}
+	if !current.Enabled {
+		return usecaseerr.ErrTwoFactorNotEnabled
+	}
+
+	code = strings.ToLower(strings.TrimSpace(code))
+	if strings.Contains(code, "-") {
+		return s.useRecoveryCode(ctx, userID, code)
+	}
+
+	step, ok := totp.Validate(current.Secret, code, time.Now())
+	if !ok {
+		return usecaseerr.ErrInvalidTwoFactorCode
+	}
+	// Each code is accepted only once, even inside its validity window.
+	if err = s.repo.MarkUsed(ctx, userID, step); err != nil {
+		if errors.Is(err, repoerr.ErrTOTPCodeReused) {
+			s.logger.WARN("Reused TOTP code for user:", userID)
+			return usecaseerr.ErrInvalidTwoFactorCode
+		}
+		s.logger.ERROR("Error saving TOTP step:", err)
+		return usecaseerr.ErrTwoFactorCheck
+	}
+	return nil
+}
+
+// verifyLimited checks the code like Verify, counting it for the phone and the ip before it is checked.
+// The attempt stays counted only when the code is wrong.
+func (s *service) verifyLimited(ctx context.Context, userID, code, ip string) error {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		s.logger.ERROR("Error getting user for two-factor check:", err)
+		return usecaseerr.UserLookup(err)
+	}
+
+	attempt, err := s.lockouts.Reserve(ctx, user.Phone, ip)
+	if errors.Is(err, usecaseerr.ErrTooManyAttempts) {
+		s.logger.WARN("Two-factor attempt while locked out, phone: ", user.Phone, " ip: ", ip)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = s.Verify(ctx, userID, code)
+	if errors.Is(err, usecaseerr.ErrInvalidTwoFactorCode) {
+		s.logger.ERROR("Invalid two-factor code for phone:", user.Phone)
+		return err
+	}
+	s.lockouts.Release(ctx, attempt)
+	return err
+}
+
+func (s *service) useRecoveryCode(ctx context.Context, userID, code string) error {
+	if err := s.repo.UseRecoveryCode(ctx, userID, hashRecoveryCode(code)); err != nil {
+		if errors.Is(err, repoerr.ErrRecoveryCodeNotFound) {
+			return usecaseerr.ErrInvalidTwoFactorCode
+		}
+		s.logger.ERROR("Error using recovery code:", err)
+		return usecaseerr.ErrTwoFactorCheck
+	}
+	s.logger.WARN("Recovery code used by user:", userID)
+	return nil
+}
+
+func (s *service) issueRecoveryCodes(ctx context.Context, userID string) ([]string, error) {
+	codes := make([]string, 0, recoveryCodesCount)
+	hashes := make([]string, 0, recoveryCodesCount)
+	for range recoveryCodesCount {
+		code, err := newRecoveryCode()
+		if err != nil {
+			s.logger.ERROR("Error generating recovery code:", err)
+			return nil, usecaseerr.ErrTwoFactorSetup
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	if err := s.repo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		s.logger.ERROR("Error saving recovery codes:", err)
+		return nil, usecaseerr.ErrTwoFactorSetup
+	}
+	return codes, nil
+}
+
+// newRecoveryCode returns a code in the form xxxxx-xxxxx.
+func newRecoveryCode() (string, error) {
+	buf := make([]byte, 2*recoveryCodeHalf)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for i, b := range buf {
+		if i == recoveryCodeHalf {
+			sb.WriteByte('-')
+		}
+		sb.WriteByte(recoveryAlphabet[int(b)&(len(recoveryAlphabet)-1)])
+	}
+	return sb.String(), nil
+}
+
+// hashRecoveryCode - codes are random, so a plain SHA-256 is enough to keep them unusable if leaked.
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,295 @@
+package twofactor
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs/usecaseerr"
+	"ads-service/internal/repository/twofactor"
+	"ads-service/internal/repository/user"
+	"ads-service/internal/usecase/lockout"
+	"ads-service/pkg/db"
+	customLogger "ads-service/pkg/logger"
+	"ads-service/pkg/totp"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// inTx runs the unit of work right away, the transaction itself is covered in the db package.
+func inTx() *db.MockTxManager {
+	m := &db.MockTxManager{}
+	m.On("WithinTx", mock.Anything).Return(nil).Maybe()
+	return m
+}
+
+func newTestService() (*twofactor.MockTwoFactorRepo, *user.MockUserRepo, TwoFactorService) {
+	repo, userRepo, _, service := newLimitedService()
+	return repo, userRepo, service
+}
+
+// newLimitedService also returns the lockouts for the tests of the checks of a signed-in user.
+func newLimitedService() (*twofactor.MockTwoFactorRepo, *user.MockUserRepo, *lockout.MockLockoutService,
+	TwoFactorService) {
+	repo := &twofactor.MockTwoFactorRepo{}
+	userRepo := &user.MockUserRepo{}
+	lockouts := &lockout.MockLockoutService{}
+	return repo, userRepo, lockouts, NewTwoFactorService(repo, userRepo, lockouts, Config{Issuer: "GoAds"},
+		inTx(), customLogger.Logger{})
+}
+
+func TestService_Enroll(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		repo, userRepo, service := newTestService()
+		defer repo.AssertExpectations(t)
+		defer userRepo.AssertExpectations(t)
+
+		repo.On("Get", mock.Anything, "1").Return(nil, repoerr.ErrTOTPNotFound)
+		userRepo.On("GetUserByID", mock.Anything, "1").Return(&entities.User{Phone: "+998901234567"}, nil)
+		repo.On("Save", mock.Anything, mock.MatchedBy(func(t *entities.TOTP) bool {
+			return t.UserID == "1" && !t.Enabled && t.Secret != ""
+		})).Return(nil)
+
+		enrollment, err := service.Enroll(context.Background(), "1")
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(enrollment.URI, "otpauth://totp/GoAds:"))
+		assert.Contains(t, enrollment.URI, "998901234567")
+		assert.Contains(t, enrollment.URI, "secret="+enrollment.Secret)
+	})
+
+	t.Run("already enabled", func(t *testing.T) {
+		repo, _, service := newTestService()
+		defer repo.AssertExpectations(t)
+
+		repo.On("Get", mock.Anything, "1").Return(&entities.TOTP{Enabled: true}, nil)
+
+		enrollment, err := service.Enroll(context.Background(), "1")
+		assert.Nil(t, enrollment)
+		assert.Equal(t, usecaseerr.ErrTwoFactorAlreadyEnabled, err)
+	})
+
+	t.Run("save error", func(t *testing.T) {
+		repo, userRepo, service := newTestService()
+		defer repo.AssertExpectations(t)
+
+		repo.On("Get", mock.Anything, "1").Return(&entities.TOTP{}, nil)
+		userRepo.On("GetUserByID", mock.Anything, "1").Return(&entities.User{}, nil)
+		repo.On("Save", mock.Anything, mock.Anything).Return(repoerr.ErrTOTPSave)
+
+		enrollment, err := service.Enroll(context.Background(), "1")
+		assert.Nil(t, enrollment)
+		assert.Equal(t, usecaseerr.ErrTwoFactorSetup, err)
+	})
+}
+
+func TestService_Confirm(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		repo, _, service := newTestService()
+		defer repo.AssertExpectations(t)
+
+		code, _ := totp.Code(testSecret, time.Now())
+		repo.On("Get", mock.Anything, "1").Return(&entities.TOTP{UserID: "1", Secret: testSecret}, nil)
+		repo.On("Save", mock.Anything, mock.MatchedBy(func(t *entities.TOTP) bool {
+			return t.Enabled && t.LastUsedStep > 0
+		})).Return(nil)
+		repo.On("ReplaceRecoveryCodes", mock.Anything, "1", mock.MatchedBy(func(h []string) bool {
+			return len(h) == recoveryCodesCount
+		})).Return(nil)
+
+		codes, err := service.Confirm(context.Background(), "1", code)
+		assert.NoError(t, err)
+		assert.Len(t, codes, recoveryCodesCount)
+		assert.Regexp(t, `^[0-9a-z]{5}-[0-9a-z]{5}$`, codes[0])
+	})
+
+	t.Run("recovery codes error", func(t *testing.T) {
+		repo := &twofactor.MockTwoFactorRepo{}
+		tx := &db.MockTxManager{}
+		defer repo.AssertExpectations(t)
+		defer tx.AssertExpectations(t)
+		service := NewTwoFactorService(repo, &user.MockUserRepo{}, &lockout.MockLockoutService{},
+			Config{Issuer: "GoAds"}, tx, customLogger.Logger{})
+
+		code, _ := totp.Code(testSecret, time.Now())
+		repo.On("Get", mock.Anything, "1").Return(&entities.TOTP{UserID: "1", Secret: testSecret}, nil)
+		tx.On("WithinTx", mock.Anything).Return(nil).Once()
+		repo.On("Save", mock.Anything, mock.Anything).Return(nil)
+		repo.On("ReplaceRecoveryCodes", mock.Anything, "1", mock.Anything).Return(assert.AnError)
+
+		codes, err := service.Confirm(context.Background(), "1", code)
+		assert.Nil(t, codes)
+		assert.Equal(t, usecaseerr.ErrTwoFactorSetup, err)
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		repo, _, service := newTestService()
+		defer repo.AssertExpectations(t)
+
+		repo.On("Get", mock.Anything, "1").Return(&entities.TOTP{UserID: "1", Secret: testSecret}, nil)
+
+		codes, err := service.Confirm(context.Background(), "1", "abcdef")
+		assert.Nil(t, codes)
+		assert.Equal(t, usecaseerr.ErrInvalidTwoFactorCode, err)
+	})
+
+	t.Run("not enrolled", func(t *testing.T) {
+		repo, _, service := newTestService()
+		defer repo.AssertExpectations(t)
+
+		repo.On("Get", mock.Anything, "1").Return(nil, repoerr.ErrTOTPNotFound)
+
+		codes, err := service.Confirm(context.Background(), "1", "123456")
+		assert.Nil(t, codes)
+		assert.Equal(t, usecaseerr.ErrTwoFactorNotEnabled, err)
+	})
+}
+
+func TestService_Verify(t *testing.T) {
+	enabled := &entities.TOTP{UserID: "1", Secret: testSecret, Enabled: true}
+
+	t.Run("totp code", func(t *testing.T) {
+		repo, _, service := newTestService()
+		defer repo.AssertExpectations(t)
+
+		code, _ := totp.Code(testSecret, time.Now())
+		repo.On("Get", mock.Anything, "1").Return(enabled, nil)
+		repo.On("MarkUsed", mock.Anything, "1", mock.AnythingOfType("int64")).Return(nil)
+
+		assert.NoError(t, service.Verify(context.Background(), "1", code))
+	})
+
+	t.Run("reused totp code", func(t *testing.T) {
+		repo, _, service := newTestService()
+		defer repo.AssertExpectations(t)
+
+		code, _ := totp.Code(testSecret, time.Now())
+		repo.On("Get", mock.Anything, "1").Return(enabled, nil)
+		repo.On("MarkUsed", mock.Anything, "1", mock.Anything).Return(repoerr.ErrTOTPCodeReused)
+
+		err := service.Verify(context.Background(), "1", code)
+		assert.Equal(t, usecaseerr.ErrInvalidTwoFactorCode, err)
+	})
+
+	t.Run("recovery code", func(t *testing.T) {
+		repo, _, service := newTestService()
+		defer repo.AssertExpectations(t)
+
+		repo.On("Get", mock.Anything, "1").Return(enabled, nil)
+		repo.On("UseRecoveryCode", mock.Anything, "1", hashRecoveryCode("abcde-fghjk")).Return(nil)
+
+		assert.NoError(t, service.Verify(context.Background(), "1", " ABCDE-FGHJK "))
+	})
+
+	t.Run("unknown recovery code", func(t *testing.T) {
+		repo, _, service := newTestService()
+		defer repo.AssertExpectations(t)
+
+		repo.On("Get", mock.Anything, "1").Return(enabled, nil)
+		repo.On("UseRecoveryCode", mock.Anything, "1", mock.Anything).Return(repoerr.ErrRecoveryCodeNotFound)
+
+		err := service.Verify(context.Background(), "1", "abcde-fghjk")
+		assert.Equal(t, usecaseerr.ErrInvalidTwoFactorCode, err)
+	})
+
+	t.Run("not enabled", func(t *testing.T) {
+		repo, _, service := newTestService()
+		defer repo.AssertExpectations(t)
+
+		repo.On("Get", mock.Anything, "1").Return(&entities.TOTP{Secret: testSecret}, nil)
+
+		err := service.Verify(context.Background(), "1", "123456")
+		assert.Equal(t, usecaseerr.ErrTwoFactorNotEnabled, err)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		repo, _, service := newTestService()
+		defer repo.AssertExpectations(t)
+
+		repo.On("Get", mock.Anything, "1").Return(nil, errors.New("db error"))
+
+		err := service.Verify(context.Background(), "1", "123456")
+		assert.Equal(t, usecaseerr.ErrTwoFactorCheck, err)
+	})
+}
+
+func TestService_Disable(t *testing.T) {
+	const ip = "10.0.0.1"
+	userEntity := &entities.User{ID: "1", Phone: "+998901234567"}
+	attempt := &lockout.Attempt{}
+
+	t.Run("success releases the attempt", func(t *testing.T) {
+		repo, userRepo, lockouts, service := newLimitedService()
+		defer repo.AssertExpectations(t)
+		defer lockouts.AssertExpectations(t)
+
+		userRepo.On("GetUserByID", mock.Anything, "1").Return(userEntity, nil)
+		lockouts.On("Reserve", mock.Anything, userEntity.Phone, ip).Return(attempt, nil)
+		repo.On("Get", mock.Anything, "1").Return(&entities.TOTP{Secret: testSecret, Enabled: true}, nil)
+		repo.On("UseRecoveryCode", mock.Anything, "1", mock.Anything).Return(nil)
+		lockouts.On("Release", mock.Anything, attempt).Once()
+		repo.On("Delete", mock.Anything, "1").Return(nil)
+
+		assert.NoError(t, service.Disable(context.Background(), "1", "abcde-fghjk", ip))
+	})
+
+	t.Run("wrong code stays counted", func(t *testing.T) {
+		repo, userRepo, lockouts, service := newLimitedService()
+		defer repo.AssertExpectations(t)
+		defer lockouts.AssertExpectations(t)
+
+		userRepo.On("GetUserByID", mock.Anything, "1").Return(userEntity, nil)
+		lockouts.On("Reserve", mock.Anything, userEntity.Phone, ip).Return(attempt, nil)
+		repo.On("Get", mock.Anything, "1").Return(&entities.TOTP{Secret: testSecret, Enabled: true}, nil)
+		repo.On("UseRecoveryCode", mock.Anything, "1", mock.Anything).Return(repoerr.ErrRecoveryCodeNotFound)
+
+		err := service.Disable(context.Background(), "1", "abcde-fghjk", ip)
+		assert.Equal(t, usecaseerr.ErrInvalidTwoFactorCode, err)
+		lockouts.AssertNotCalled(t, "Release", mock.Anything, mock.Anything)
+	})
+
+	t.Run("locked out", func(t *testing.T) {
+		repo, userRepo, lockouts, service := newLimitedService()
+		defer repo.AssertExpectations(t)
+
+		userRepo.On("GetUserByID", mock.Anything, "1").Return(userEntity, nil)
+		lockouts.On("Reserve", mock.Anything, userEntity.Phone, ip).Return(nil, usecaseerr.ErrTooManyAttempts)
+
+		err := service.Disable(context.Background(), "1", "abcde-fghjk", ip)
+		assert.Equal(t, usecaseerr.ErrTooManyAttempts, err)
+	})
+}
+
+func TestService_RegenerateRecoveryCodes(t *testing.T) {
+	repo, userRepo, lockouts, service := newLimitedService()
+	defer repo.AssertExpectations(t)
+	defer lockouts.AssertExpectations(t)
+	attempt := &lockout.Attempt{}
+
+	userRepo.On("GetUserByID", mock.Anything, "1").Return(&entities.User{ID: "1", Phone: "+998901234567"}, nil)
+	lockouts.On("Reserve", mock.Anything, "+998901234567", "10.0.0.1").Return(attempt, nil)
+	repo.On("Get", mock.Anything, "1").Return(&entities.TOTP{Secret: testSecret, Enabled: true}, nil)
+	repo.On("UseRecoveryCode", mock.Anything, "1", mock.Anything).Return(nil)
+	lockouts.On("Release", mock.Anything, attempt).Once()
+	repo.On("ReplaceRecoveryCodes", mock.Anything, "1", mock.Anything).Return(nil)
+
+	codes, err := service.RegenerateRecoveryCodes(context.Background(), "1", "abcde-fghjk", "10.0.0.1")
+	assert.NoError(t, err)
+	assert.Len(t, codes, recoveryCodesCount)
+}
+
+func TestService_IsEnabled(t *testing.T) {
+	repo, _, service := newTestService()
+	defer repo.AssertExpectations(t)
+
+	repo.On("Get", mock.Anything, "1").Return(nil, repoerr.ErrTOTPNotFound)
+
+	enabled, err := service.IsEnabled(context.Background(), "1")
+	assert.NoError(t, err)
+	assert.False(t, enabled)
+}
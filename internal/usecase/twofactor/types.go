@@ -0,0 +1,51 @@
+package twofactor
+
+import (
+	"ads-service/internal/domain/entities"
+	"ads-service/internal/repository/twofactor"
+	"ads-service/internal/repository/user"
+	"ads-service/internal/usecase/lockout"
+	"ads-service/pkg/db"
+	customLogger "ads-service/pkg/logger"
+	"context"
+)
+
+type TwoFactorService interface {
+	// Enroll generates a new secret, it stays inactive until Confirm.
+	Enroll(ctx context.Context, userID string) (*entities.TOTPEnrollment, error)
+	// Confirm activates 2FA and returns one-time recovery codes, they are shown only once.
+	Confirm(ctx context.Context, userID, code string) ([]string, error)
+	// Disable and RegenerateRecoveryCodes take a code of the signed-in user, wrong codes count towards the
+	// lockout of the phone and the ip like in the second login step.
+	Disable(ctx context.Context, userID, code, ip string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID, code, ip string) ([]string, error)
+	IsEnabled(ctx context.Context, userID string) (bool, error)
+	// Verify accepts either a TOTP code or an unused recovery code.
+	Verify(ctx context.Context, userID, code string) error
+}
+
+// Config - Issuer is shown in authenticator apps next to the account.
+type Config struct {
+	Issuer string
+}
+
+type service struct {
+	repo     twofactor.TwoFactorRepository
+	userRepo user.UserRepository
+	lockouts lockout.LockoutService
+	config   Config
+	tx       db.TxManager
+	logger   customLogger.Logger
+}
+
+func NewTwoFactorService(repo twofactor.TwoFactorRepository, userRepo user.UserRepository,
+	lockouts lockout.LockoutService, config Config, tx db.TxManager, logTool customLogger.Logger) TwoFactorService {
+	return &service{
+		repo:     repo,
+		userRepo: userRepo,
+		lockouts: lockouts,
+		config:   config,
+		tx:       tx,
+		logger:   logTool,
+	}
+}
@@ -24,6 +24,14 @@ func (m *MockUserService) GetMyAds(ctx context.Context, userID string) ([]entiti
 	return nil, args.Error(1)
 }
 
+func (m *MockUserService) GetMyAd(ctx context.Context, userID string, adID int) (*entities.Ad, error) {
+	args := m.Called(ctx, userID, adID)
+	if ad, ok := args.Get(0).(*entities.Ad); ok {
+		return ad, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockUserService) UpdateMyAd(ctx context.Context, userID string, ad *entities.Ad) error {
 	args := m.Called(ctx, userID, ad)
 	return args.Error(0)
@@ -39,8 +47,9 @@ func (m *MockUserService) SubmitForModeration(ctx context.Context, userID string
 	return args.Error(0)
 }
 
-func (m *MockUserService) AddImageToMyAd(ctx context.Context, userID string, file *entities.AdFile) error {
-	args := m.Called(ctx, userID, file)
+func (m *MockUserService) AddImageToMyAd(ctx context.Context, userID string, file *entities.AdFile, version int,
+	save func(path string) error) error {
+	args := m.Called(ctx, userID, file, version, save)
 	return args.Error(0)
 }
 
@@ -52,8 +61,8 @@ func (m *MockUserService) GetImagesToMyAd(ctx context.Context, userID string, ad
 	return nil, args.Error(1)
 }
 
-func (m *MockUserService) DeleteMyAdImage(ctx context.Context, userID string, file *entities.AdFile) error {
-	args := m.Called(ctx, userID, file)
+func (m *MockUserService) DeleteMyAdImage(ctx context.Context, userID string, file *entities.AdFile, version int) error {
+	args := m.Called(ctx, userID, file, version)
 	return args.Error(0)
 }
 
@@ -4,6 +4,13 @@ import (
 	"ads-service/internal/domain/entities"
 	adRepo "ads-service/internal/repository/ad"
 	adfile "ads-service/internal/repository/adFile"
+	"ads-service/internal/repository/favorite"
+	"ads-service/internal/repository/history"
+	"ads-service/internal/repository/rejection"
+	"ads-service/internal/usecase/duplicate"
+	"ads-service/internal/usecase/premoderation"
+	"ads-service/pkg/db"
+	"ads-service/pkg/eventbus"
 	customLogger "ads-service/pkg/logger"
 	"context"
 )
@@ -11,26 +18,48 @@ import (
 type UserAdvertisementService interface {
 	CreateDraft(ctx context.Context, userID string, ad *entities.Ad) error
 	GetMyAds(ctx context.Context, userID string) ([]entities.Ad, error)
+	GetMyAd(ctx context.Context, userID string, adID int) (*entities.Ad, error)
+	// UpdateMyAd, AddImageToMyAd and DeleteMyAdImage write only over the version the client read, ad.Version
+	// or version, 0 skips the check. A stale write gives an *errs.StaleError with the current ad.
 	UpdateMyAd(ctx context.Context, userID string, ad *entities.Ad) error
 	DeleteMyAd(ctx context.Context, userID string, adID int) error
 	SubmitForModeration(ctx context.Context, userID string, adID int) error
-	AddImageToMyAd(ctx context.Context, userID string, file *entities.AdFile) error
+	// AddImageToMyAd calls save with the path of the image inside the transaction adding it, an error from save
+	// rolls the image back.
+	AddImageToMyAd(ctx context.Context, userID string, file *entities.AdFile, version int,
+		save func(path string) error) error
 	GetImagesToMyAd(ctx context.Context, userID string, adID int) ([]entities.AdFile, error)
-	DeleteMyAdImage(ctx context.Context, userID string, file *entities.AdFile) error
+	DeleteMyAdImage(ctx context.Context, userID string, file *entities.AdFile, version int) error
 	GetMyAdsByFilter(ctx context.Context, userID string, filter *entities.AdFilter) ([]entities.Ad, error)
 }
 
 type service struct {
-	repo     adRepo.AdRepository
-	fileRepo adfile.AdFileRepository
-	logger   customLogger.Logger
+	repo         adRepo.AdRepository
+	fileRepo     adfile.AdFileRepository
+	favoriteRepo favorite.FavoriteRepository
+	rejections   rejection.RejectionRepository
+	events       eventbus.Bus
+	screening    premoderation.PreModerationService
+	duplicates   duplicate.DuplicateService
+	history      history.HistoryRepository
+	tx           db.TxManager
+	logger       customLogger.Logger
 }
 
 func NewUserService(repo adRepo.AdRepository, fileRepo adfile.AdFileRepository,
-	logTool customLogger.Logger) UserAdvertisementService {
+	favoriteRepo favorite.FavoriteRepository, rejections rejection.RejectionRepository, events eventbus.Bus,
+	screening premoderation.PreModerationService, duplicates duplicate.DuplicateService,
+	historyRepo history.HistoryRepository, tx db.TxManager, logTool customLogger.Logger) UserAdvertisementService {
 	return &service{
-		repo:     repo,
-		fileRepo: fileRepo,
-		logger:   logTool,
+		repo:         repo,
+		fileRepo:     fileRepo,
+		favoriteRepo: favoriteRepo,
+		rejections:   rejections,
+		events:       events,
+		screening:    screening,
+		duplicates:   duplicates,
+		history:      historyRepo,
+		tx:           tx,
+		logger:       logTool,
 	}
 }
@@ -2,24 +2,33 @@ package user
 
 import (
 	"ads-service/internal/domain/entities"
-	"ads-service/internal/errs/repoerr"
+	"ads-service/internal/errs"
 	"ads-service/internal/errs/usecaseerr"
+	"ads-service/pkg/tracing"
 	"ads-service/pkg/utils"
 	"time"
 
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
 const fileDirPerm = 0o750 // Permissions for the directory where ad images are stored
 
-func (s *service) CreateDraft(ctx context.Context, userID string, adEntity *entities.Ad) error {
-	err := utils.ValidateAd(adEntity)
+// UploadsDir - directory of ad images, one subdirectory per ad.
+const UploadsDir = "storage/uploadings"
+
+func (s *service) CreateDraft(ctx context.Context, userID string, adEntity *entities.Ad) (err error) {
+	ctx, span := tracing.Start(ctx, "user.CreateDraft")
+	defer tracing.End(span, &err)
+
+	err = utils.ValidateAd(adEntity)
 	if err != nil {
 		s.logger.ERROR(err)
-		return usecaseerr.ErrInvalidParams
+		return fmt.Errorf("%w: %w", usecaseerr.ErrInvalidParams, err)
 	}
 
 	adEntity.AuthorID = userID
@@ -32,164 +41,351 @@ func (s *service) CreateDraft(ctx context.Context, userID string, adEntity *enti
 	err = s.repo.Create(ctx, adEntity)
 	if err != nil {
 		s.logger.ERROR("error creating ad:", err)
-		return repoerr.ErrInsert
+		return fmt.Errorf("%w: %w", usecaseerr.ErrCreatingAd, err)
 	}
 
 	return nil
 }
 
-func (s *service) GetMyAds(ctx context.Context, userID string) ([]entities.Ad, error) {
+func (s *service) GetMyAds(ctx context.Context, userID string) (_ []entities.Ad, err error) {
+	ctx, span := tracing.Start(ctx, "user.GetMyAds")
+	defer tracing.End(span, &err)
+
 	ads, err := s.repo.GetByUserID(ctx, userID)
 	if err != nil {
 		s.logger.ERROR("error getting my ads: ", err)
-		return nil, repoerr.ErrGettingAdsByUserID
+		return nil, fmt.Errorf("%w: %w", usecaseerr.ErrGettingUserAds, err)
 	}
 	if len(ads) == 0 {
 		s.logger.ERROR("user not found")
 		return nil, usecaseerr.ErrUserNotHaveAds
 	}
+	if err = s.fillFavoriteCounts(ctx, ads); err != nil {
+		return nil, err
+	}
+	if err = s.fillRejections(ctx, ads); err != nil {
+		return nil, err
+	}
 	s.logger.INFO("ads retrieved successfully: ")
 	return ads, nil
 }
 
-func (s *service) UpdateMyAd(ctx context.Context, userID string, adEntity *entities.Ad) error {
-	ad, err := s.repo.GetByID(ctx, adEntity.ID)
+func (s *service) GetMyAd(ctx context.Context, userID string, adID int) (_ *entities.Ad, err error) {
+	ctx, span := tracing.Start(ctx, "user.GetMyAd")
+	defer tracing.End(span, &err)
+
+	ad, err := s.repo.GetByID(ctx, adID)
 	if err != nil {
 		s.logger.ERROR("error getting my ad by ID: ", err)
-		return usecaseerr.ErrGettingAdByID
+		return nil, usecaseerr.AdLookup(err)
 	}
 	if ad == nil {
-		s.logger.ERROR("ad is nil")
-		return usecaseerr.ErrGettingAdByID
+		return nil, usecaseerr.ErrAdNotFound
 	}
 	if ad.AuthorID != userID {
-		s.logger.WARN("userID denied: ", userID)
-		s.logger.WARN("ad author denied: ", ad.AuthorID)
 		s.logger.ERROR("access denied")
-		return usecaseerr.ErrAccessDenied
+		return nil, usecaseerr.ErrAccessDenied
 	}
-
-	if err = utils.ValidateAd(adEntity); err != nil {
-		s.logger.ERROR(err)
-		return usecaseerr.ErrInvalidParams
+	ads := []entities.Ad{*ad}
+	if err = s.fillFavoriteCounts(ctx, ads); err != nil {
+		return nil, err
+	}
+	if err = s.fillRejections(ctx, ads); err != nil {
+		return nil, err
 	}
+	return &ads[0], nil
+}
 
-	ad.Title = adEntity.Title
-	ad.Description = adEntity.Description
-	ad.CategoryID = adEntity.CategoryID
-	ad.UpdatedAt = time.Now().UTC()
+func (s *service) UpdateMyAd(ctx context.Context, userID string, adEntity *entities.Ad) (err error) {
+	ctx, span := tracing.Start(ctx, "user.UpdateMyAd")
+	defer tracing.End(span, &err)
 
-	if err = s.repo.Update(ctx, ad); err != nil {
-		s.logger.ERROR("error updating my ad: ", err)
-		return repoerr.ErrUpdate
+	var (
+		ad       *entities.Ad
+		oldPrice int
+	)
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		ad, err = s.repo.GetByID(ctx, adEntity.ID)
+		if err != nil {
+			s.logger.ERROR("error getting my ad by ID: ", err)
+			return usecaseerr.AdLookup(err)
+		}
+		if ad == nil {
+			s.logger.ERROR("ad is nil")
+			return usecaseerr.ErrAdNotFound
+		}
+		if ad.AuthorID != userID {
+			s.logger.WARN("userID denied: ", userID)
+			s.logger.WARN("ad author denied: ", ad.AuthorID)
+			s.logger.ERROR("access denied")
+			return usecaseerr.ErrAccessDenied
+		}
+
+		if err = checkVersion(ad, adEntity.Version); err != nil {
+			return err
+		}
+
+		if err = utils.ValidateAd(adEntity); err != nil {
+			s.logger.ERROR(err)
+			return fmt.Errorf("%w: %w", usecaseerr.ErrInvalidParams, err)
+		}
+
+		oldPrice = ad.Price
+		ad.Title = adEntity.Title
+		ad.Description = adEntity.Description
+		ad.CategoryID = adEntity.CategoryID
+		ad.Price = adEntity.Price
+		ad.Location = adEntity.Location
+		ad.UpdatedAt = time.Now().UTC()
+
+		if err = s.repo.Update(ctx, ad); err != nil {
+			s.logger.ERROR("error updating my ad: ", err)
+			return usecaseerr.AdWrite(ctx, ad.ID, err, usecaseerr.ErrUpdatingAd, s.repo.GetByID)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// favorites are notified once the change is committed
+	if oldPrice != ad.Price && ad.Status == entities.StatusApproved && ad.IsActive {
+		s.notifyPriceChange(ctx, ad, oldPrice)
 	}
 	s.logger.INFO("my ad successfully updated")
 	return nil
 }
 
-func (s *service) DeleteMyAd(ctx context.Context, userID string, adID int) error {
-	ad, err := s.repo.GetByID(ctx, adID)
+// notifyPriceChange tells users who bookmarked a published ad that its price changed.
+func (s *service) notifyPriceChange(ctx context.Context, ad *entities.Ad, oldPrice int) {
+	userIDs, err := s.favoriteRepo.GetUserIDsByAd(ctx, ad.ID)
 	if err != nil {
-		s.logger.ERROR("error getting my ad by ID: ", err)
-		return usecaseerr.ErrGettingAdByID
+		s.logger.ERROR("error getting users to notify about price change: ", err)
+		return
 	}
-	if ad == nil || ad.AuthorID != userID {
-		s.logger.ERROR("user not found")
-		return usecaseerr.ErrAccessDenied
+	for _, userID := range userIDs {
+		s.events.Publish(ctx, entities.Event{
+			Type:   entities.EventPriceChanged,
+			UserID: userID,
+			Data: entities.EventData{
+				"ad_id":     ad.ID,
+				"title":     ad.Title,
+				"old_price": oldPrice,
+				"new_price": ad.Price,
+			},
+		})
 	}
+}
+
+func (s *service) DeleteMyAd(ctx context.Context, userID string, adID int) (err error) {
+	ctx, span := tracing.Start(ctx, "user.DeleteMyAd")
+	defer tracing.End(span, &err)
 
-	if err = s.repo.Delete(ctx, adID); err != nil {
-		s.logger.ERROR("error deleting my ad: ", err)
-		return repoerr.ErrDelete
+	var files []entities.AdFile
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		ad, err := s.repo.GetByID(ctx, adID)
+		if err != nil {
+			s.logger.ERROR("error getting my ad by ID: ", err)
+			return usecaseerr.AdLookup(err)
+		}
+		if ad == nil || ad.AuthorID != userID {
+			s.logger.ERROR("user not found")
+			return usecaseerr.ErrAccessDenied
+		}
+		if files, err = s.fileRepo.GetAll(ctx, adID); err != nil {
+			s.logger.ERROR("error getting images of my ad: ", err)
+			return fmt.Errorf("%w: %w", usecaseerr.ErrDeletingAd, err)
+		}
+		// the rows of the images go with the ad
+		if err = s.repo.Delete(ctx, adID); err != nil {
+			s.logger.ERROR("error deleting my ad: ", err)
+			return fmt.Errorf("%w: %w", usecaseerr.ErrDeletingAd, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
+	// the images are removed from the disk only after the commit, a rolled back delete keeps them
+	for _, file := range files {
+		s.removeImage(file.URL)
+	}
+	_ = os.Remove(filepath.Join(UploadsDir, fmt.Sprintf("ad_%d", adID))) // only when nothing else is left
 	s.logger.INFO("my ad successfully deleted")
 	return nil
 }
 
-func (s *service) SubmitForModeration(ctx context.Context, userID string, adID int) error {
-	ad, err := s.repo.GetByID(ctx, adID)
-	if err != nil {
-		s.logger.ERROR("error submitting ad:", err)
-		return usecaseerr.ErrGettingAdByID
-	}
-	if ad == nil || ad.AuthorID != userID {
-		s.logger.ERROR("error submitting ad: invalid user")
-		return usecaseerr.ErrAccessDenied
-	}
+func (s *service) SubmitForModeration(ctx context.Context, userID string, adID int) (err error) {
+	ctx, span := tracing.Start(ctx, "user.SubmitForModeration")
+	defer tracing.End(span, &err)
 
-	ad.Status = entities.StatusPending
-	ad.UpdatedAt = time.Now().UTC()
+	var ad *entities.Ad
+	// the fingerprint and the status change are one unit of work, a refused repost keeps neither
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		ad, err = s.repo.GetByID(ctx, adID)
+		if err != nil {
+			s.logger.ERROR("error submitting ad:", err)
+			return usecaseerr.AdLookup(err)
+		}
+		if ad == nil || ad.AuthorID != userID {
+			s.logger.ERROR("error submitting ad: invalid user")
+			return usecaseerr.ErrAccessDenied
+		}
+		// a failed query aborts the transaction, so any fingerprint error fails the submission
+		if err = s.duplicates.Fingerprint(ctx, ad); err != nil {
+			if errors.Is(err, usecaseerr.ErrRepost) {
+				return err
+			}
+			s.logger.ERROR("error fingerprinting ad:", err)
+			return fmt.Errorf("%w: %w", usecaseerr.ErrSubmittingAd, err)
+		}
 
-	err = s.repo.Update(ctx, ad)
+		ad.Status = entities.StatusPending
+		ad.UpdatedAt = time.Now().UTC()
+		if err = s.repo.Update(ctx, ad); err != nil {
+			s.logger.ERROR("error submitting ad:", err)
+			return fmt.Errorf("%w: %w", usecaseerr.ErrSubmittingAd, err)
+		}
+		// recorded with the status so the statistics count every submission, and before screening so an
+		// automatic decision follows the submission in the history
+		if err = s.history.Record(ctx, &entities.ModerationEvent{AdID: ad.ID, Action: entities.ActionSubmitted,
+			ActorID: userID}); err != nil {
+			s.logger.ERROR("error recording submission:", err)
+			return fmt.Errorf("%w: %w", usecaseerr.ErrSubmittingAd, err)
+		}
+		return nil
+	})
 	if err != nil {
-		s.logger.ERROR("error submitting ad:", err)
-		return usecaseerr.ErrApprovingAd
+		return err
+	}
+	// the ad is already submitted, if screening fails it simply waits for a moderator
+	if _, err = s.screening.Screen(ctx, ad); err != nil {
+		s.logger.ERROR("error screening ad:", err)
 	}
 	s.logger.INFO("my ad successfully submitted")
 	return nil
 }
 
-func (s *service) AddImageToMyAd(ctx context.Context, userID string, file *entities.AdFile) error {
-	ad, err := s.repo.GetByID(ctx, file.AdID)
-	if err != nil {
-		s.logger.ERROR("error getting ads by user ID: ", userID, "\n", err)
-		return repoerr.ErrSelection
-	}
-	if ad != nil && ad.AuthorID != userID {
-		s.logger.ERROR("error: user does not own the ad")
-		return usecaseerr.ErrAccessDenied
-	}
-	if !checkIfFileAllowed(file.FileName) {
-		s.logger.ERROR("invalid format of the file:", file.FileName)
-		return usecaseerr.ErrFileNotAllowed
-	}
+func (s *service) AddImageToMyAd(ctx context.Context, userID string, file *entities.AdFile, version int,
+	save func(path string) error) (err error) {
+	ctx, span := tracing.Start(ctx, "user.AddImageToMyAd")
+	defer tracing.End(span, &err)
 
-	dirPath := fmt.Sprintf("storage/uploadings/ad_%d", file.AdID)
+	dirPath := filepath.Join(UploadsDir, fmt.Sprintf("ad_%d", file.AdID))
+	saved := false
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		ad, err := s.repo.GetByID(ctx, file.AdID)
+		if err != nil {
+			s.logger.ERROR("error getting ads by user ID: ", userID, "\n", err)
+			return usecaseerr.AdLookup(err)
+		}
+		if ad == nil {
+			return usecaseerr.ErrAdNotFound
+		}
+		if ad.AuthorID != userID {
+			s.logger.ERROR("error: user does not own the ad")
+			return usecaseerr.ErrAccessDenied
+		}
+		if err = checkVersion(ad, version); err != nil {
+			return err
+		}
+		if !checkIfFileAllowed(file.FileName) {
+			s.logger.ERROR("invalid format of the file:", file.FileName)
+			return usecaseerr.ErrFileNotAllowed
+		}
 
-	if err := os.MkdirAll(dirPath, fileDirPerm); err != nil {
-		return fmt.Errorf("failed to create directory: %v", err)
-	}
+		if err = os.MkdirAll(dirPath, fileDirPerm); err != nil {
+			return fmt.Errorf("%w: failed to create directory: %w", usecaseerr.ErrAddingImage, err)
+		}
+		// the prefix keeps files with the same name apart
+		file.URL = filepath.Join(dirPath, fmt.Sprintf("%d_%s", time.Now().UnixNano(), filepath.Base(file.FileName)))
 
-	_, err = s.fileRepo.Create(ctx, file)
+		file.ID, err = s.fileRepo.Create(ctx, file)
+		if err != nil {
+			s.logger.ERROR("error adding image to ad ", file.AdID, "\n", err)
+			return fmt.Errorf("%w: %w", usecaseerr.ErrAddingImage, err)
+		}
+		ad.UpdatedAt = time.Now().UTC()
+		if err = s.repo.Touch(ctx, ad); err != nil {
+			s.logger.ERROR("error touching ad ", file.AdID, "\n", err)
+			return usecaseerr.AdWrite(ctx, ad.ID, err, usecaseerr.ErrAddingImage, s.repo.GetByID)
+		}
+		// saved last, a failed save rolls the row back and the ad never points to a missing file
+		if err = save(file.URL); err != nil {
+			s.logger.ERROR("error saving image of ad ", file.AdID, "\n", err)
+			return fmt.Errorf("%w: failed to save file: %w", usecaseerr.ErrAddingImage, err)
+		}
+		saved = true
+		return nil
+	})
 	if err != nil {
-		s.logger.ERROR("error adding image to ad ", file.AdID, "\n", err)
-		return repoerr.ErrFileInsertion
+		// the commit may still fail after the save, nothing refers to the file then
+		if saved {
+			s.removeImage(file.URL)
+		}
+		return err
 	}
-
 	s.logger.INFO("ad successfully added image to ad ", file.AdID)
 	return nil
 }
 
-func (s *service) DeleteMyAdImage(ctx context.Context, userID string, file *entities.AdFile) error {
-	ad, err := s.repo.GetByID(ctx, file.AdID)
-	if err != nil {
-		s.logger.ERROR("error getting ad by ID: ", file.AdID, "\n", err)
-		return repoerr.ErrSelection
-	}
-	if ad != nil && ad.AuthorID != userID {
-		s.logger.ERROR("error: user does not own the ad")
-		return usecaseerr.ErrAccessDenied
-	}
-	url, err := s.fileRepo.Delete(ctx, file)
-	if err != nil {
-		s.logger.ERROR("error deleting image from ad: ", file.AdID, "\n", err)
-		return repoerr.ErrFileDeletion
-	}
-	s.logger.ERROR("image deleted from file db successfully")
+func (s *service) DeleteMyAdImage(ctx context.Context, userID string, file *entities.AdFile, version int) (err error) {
+	ctx, span := tracing.Start(ctx, "user.DeleteMyAdImage")
+	defer tracing.End(span, &err)
 
-	err = os.Remove(url) // Remove the file from the filesystem
+	var url string
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		ad, err := s.repo.GetByID(ctx, file.AdID)
+		if err != nil {
+			s.logger.ERROR("error getting ad by ID: ", file.AdID, "\n", err)
+			return usecaseerr.AdLookup(err)
+		}
+		if ad == nil {
+			return usecaseerr.ErrAdNotFound
+		}
+		if ad.AuthorID != userID {
+			s.logger.ERROR("error: user does not own the ad")
+			return usecaseerr.ErrAccessDenied
+		}
+		if err = checkVersion(ad, version); err != nil {
+			return err
+		}
+		if url, err = s.fileRepo.Delete(ctx, file); err != nil {
+			s.logger.ERROR("error deleting image from ad: ", file.AdID, "\n", err)
+			return fmt.Errorf("%w: %w", usecaseerr.ErrDeletingImage, err)
+		}
+		ad.UpdatedAt = time.Now().UTC()
+		if err = s.repo.Touch(ctx, ad); err != nil {
+			s.logger.ERROR("error touching ad ", file.AdID, "\n", err)
+			return usecaseerr.AdWrite(ctx, ad.ID, err, usecaseerr.ErrDeletingImage, s.repo.GetByID)
+		}
+		return nil
+	})
 	if err != nil {
-		s.logger.ERROR("error removing file from filesystem: ", err)
+		return err
 	}
+	s.logger.INFO("image deleted from file db successfully")
+
+	s.removeImage(url)
 	s.logger.INFO("ad image successfully deleted")
 	return nil
 }
 
-func (s *service) GetImagesToMyAd(ctx context.Context, userID string, adID int) ([]entities.AdFile, error) {
+// removeImage deletes the stored upload, the row is already gone so a failure is only logged.
+func (s *service) removeImage(url string) {
+	if err := os.Remove(url); err != nil && !errors.Is(err, os.ErrNotExist) {
+		s.logger.ERROR("error removing file from filesystem: ", err)
+	}
+}
+
+func (s *service) GetImagesToMyAd(ctx context.Context, userID string, adID int) (_ []entities.AdFile, err error) {
+	ctx, span := tracing.Start(ctx, "user.GetImagesToMyAd")
+	defer tracing.End(span, &err)
+
 	ad, err := s.repo.GetByID(ctx, adID)
 	if err != nil {
 		s.logger.ERROR("error getting ad by ID ", adID, "\n", err)
-		return nil, repoerr.ErrSelection
+		return nil, usecaseerr.AdLookup(err)
 	}
 	if ad != nil && ad.AuthorID != userID {
 		s.logger.ERROR("error: user does not own the ad")
@@ -198,29 +394,87 @@ func (s *service) GetImagesToMyAd(ctx context.Context, userID string, adID int)
 	files, err := s.fileRepo.GetAll(ctx, adID)
 	if err != nil {
 		s.logger.ERROR("error getting images for ad with ID", adID, "\n", err)
-		return nil, repoerr.ErrSelection
+		return nil, fmt.Errorf("%w: %w", usecaseerr.ErrGettingImages, err)
 	}
 	s.logger.INFO("found ", len(files), " images for ad with id: ", adID)
 	return files, nil
 }
 
 func (s *service) GetMyAdsByFilter(ctx context.Context, userID string,
-	filter *entities.AdFilter) ([]entities.Ad, error) {
+	filter *entities.AdFilter) (_ []entities.Ad, err error) {
+	ctx, span := tracing.Start(ctx, "user.GetMyAdsByFilter")
+	defer tracing.End(span, &err)
+
 	filter.UserID = userID
 	ads, err := s.repo.Filter(ctx, filter)
 	if err != nil {
 		s.logger.ERROR("error getting my ads: ", err)
-		return nil, repoerr.ErrGettingAdsByUserID
+		return nil, fmt.Errorf("%w: %w", usecaseerr.ErrGettingUserAds, err)
 	}
 	if len(ads) == 0 {
 		s.logger.ERROR("user not found")
 		return nil, usecaseerr.ErrUserNotHaveAds
 	}
+	if err = s.fillFavoriteCounts(ctx, ads); err != nil {
+		return nil, err
+	}
+	if err = s.fillRejections(ctx, ads); err != nil {
+		return nil, err
+	}
 
 	s.logger.INFO("ads retrieved successfully: ")
 	return ads, nil
 }
 
+// checkVersion refuses a write based on another version than the stored one, 0 skips the check.
+func checkVersion(ad *entities.Ad, version int) error {
+	if version != 0 && version != ad.Version {
+		return errs.NewStale(usecaseerr.ErrAdModified, ad, ad.Version)
+	}
+	return nil
+}
+
+// fillFavoriteCounts shows the author how many users bookmarked each ad.
+func (s *service) fillFavoriteCounts(ctx context.Context, ads []entities.Ad) error {
+	ids := make([]int, 0, len(ads))
+	for i := range ads {
+		ids = append(ids, ads[i].ID)
+	}
+	counts, err := s.favoriteRepo.CountByAds(ctx, ids)
+	if err != nil {
+		s.logger.ERROR("error counting favorites: ", err)
+		return usecaseerr.ErrGettingFavorites
+	}
+	for i := range ads {
+		ads[i].FavoriteCount = counts[ads[i].ID]
+	}
+	return nil
+}
+
+// fillRejections tells the author why their ads were rejected and what to fix.
+func (s *service) fillRejections(ctx context.Context, ads []entities.Ad) error {
+	var ids []int
+	for i := range ads {
+		if ads[i].Status == entities.StatusRejected {
+			ids = append(ids, ads[i].ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	rejections, err := s.rejections.GetByAds(ctx, ids)
+	if err != nil {
+		s.logger.ERROR("error getting rejections: ", err)
+		return usecaseerr.ErrGettingRejections
+	}
+	for i := range ads {
+		if rejection, ok := rejections[ads[i].ID]; ok {
+			ads[i].Rejection = &rejection
+		}
+	}
+	return nil
+}
+
 func checkIfFileAllowed(fileName string) bool {
 	allowedExtensions := []string{".jpg", ".jpeg", ".png", ".svg"}
 	for _, ext := range allowedExtensions {
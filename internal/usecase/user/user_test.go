@@ -2,18 +2,68 @@ package user
 
 import (
 	"ads-service/internal/domain/entities"
+	"ads-service/internal/errs"
+	"ads-service/internal/errs/pkgerr/utilserr"
 	"ads-service/internal/errs/repoerr"
 	"ads-service/internal/errs/usecaseerr"
 	"ads-service/internal/repository/ad"
 	adfile "ads-service/internal/repository/adFile"
+	"ads-service/internal/repository/favorite"
+	"ads-service/internal/repository/history"
+	"ads-service/internal/repository/rejection"
+	"ads-service/internal/usecase/duplicate"
+	"ads-service/internal/usecase/premoderation"
+	"ads-service/pkg/db"
+	"ads-service/pkg/eventbus"
 	customLogger "ads-service/pkg/logger"
 	"context"
 	"errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
+func noFavorites() *favorite.MockFavoriteRepo {
+	m := &favorite.MockFavoriteRepo{}
+	m.On("CountByAds", mock.Anything, mock.Anything).Return(map[int]int{}, nil)
+	return m
+}
+
+func noRejections() *rejection.MockRejectionRepo {
+	m := &rejection.MockRejectionRepo{}
+	m.On("GetByAds", mock.Anything, mock.Anything).Return(map[int]entities.Rejection{}, nil).Maybe()
+	return m
+}
+
+func noHistory() *history.MockHistoryRepo {
+	m := &history.MockHistoryRepo{}
+	m.On("Record", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return m
+}
+
+func noScreening() *premoderation.MockPreModerationService {
+	m := &premoderation.MockPreModerationService{}
+	m.On("Screen", mock.Anything, mock.Anything).Return(entities.DecisionReview, nil).Maybe()
+	return m
+}
+
+func noDuplicates() *duplicate.MockDuplicateService {
+	m := &duplicate.MockDuplicateService{}
+	m.On("Fingerprint", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return m
+}
+
+// inTx runs the unit of work right away, the transaction itself is covered in the db package.
+func inTx() *db.MockTxManager {
+	m := &db.MockTxManager{}
+	m.On("WithinTx", mock.Anything).Return(nil).Maybe()
+	return m
+}
+
 func TestService_CreateDraft(t *testing.T) {
 	t.Run("title is empty", func(t *testing.T) {
 		mockRepo := ad.MockAdRepo{}
@@ -21,14 +71,16 @@ func TestService_CreateDraft(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := NewUserService(&mockRepo, &mockFileRepo, customLogger.Logger{})
+		service := NewUserService(&mockRepo, &mockFileRepo, noFavorites(), noRejections(), eventbus.New(), noScreening(),
+			noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
 
 		err := service.CreateDraft(context.Background(), "1", &entities.Ad{
 			Title: "",
 		})
 
 		assert.Error(t, err)
-		assert.Equal(t, usecaseerr.ErrInvalidParams, err)
+		assert.ErrorIs(t, err, usecaseerr.ErrInvalidParams)
+		assert.ErrorIs(t, err, utilserr.ErrTitleRequired)
 	})
 
 	t.Run("repo error", func(t *testing.T) {
@@ -37,14 +89,15 @@ func TestService_CreateDraft(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := NewUserService(&mockRepo, &mockFileRepo, customLogger.Logger{})
+		service := NewUserService(&mockRepo, &mockFileRepo, noFavorites(), noRejections(), eventbus.New(), noScreening(),
+			noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
 		mockRepo.On("Create", mock.Anything, mock.Anything).
 			Return(repoerr.ErrInsert)
 		err := service.CreateDraft(context.Background(), "1",
 			&entities.Ad{Title: "ok", Description: "desc", CategoryID: 1})
 
 		assert.Error(t, err)
-		assert.Equal(t, repoerr.ErrInsert, err)
+		assert.ErrorIs(t, err, usecaseerr.ErrCreatingAd)
 	})
 
 	t.Run("success", func(t *testing.T) {
@@ -53,7 +106,8 @@ func TestService_CreateDraft(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := NewUserService(&mockRepo, &mockFileRepo, customLogger.Logger{})
+		service := NewUserService(&mockRepo, &mockFileRepo, noFavorites(), noRejections(), eventbus.New(), noScreening(),
+			noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
 		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
 		err := service.CreateDraft(context.Background(), "1",
 			&entities.Ad{Title: "ok", Description: "desc", CategoryID: 1})
@@ -61,6 +115,29 @@ func TestService_CreateDraft(t *testing.T) {
 	})
 }
 
+func TestService_GetMyAd(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		service := NewUserService(&mockRepo, &adfile.MockAdFileRepository{}, noFavorites(), noRejections(),
+			eventbus.New(), noScreening(), noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
+		mockRepo.On("GetByID", mock.Anything, 1).Return(&entities.Ad{ID: 1, AuthorID: "1", Version: 2}, nil)
+
+		got, err := service.GetMyAd(context.Background(), "1", 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, got.Version)
+	})
+
+	t.Run("access denied", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		service := NewUserService(&mockRepo, &adfile.MockAdFileRepository{}, noFavorites(), noRejections(),
+			eventbus.New(), noScreening(), noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
+		mockRepo.On("GetByID", mock.Anything, 1).Return(&entities.Ad{ID: 1, AuthorID: "2"}, nil)
+
+		_, err := service.GetMyAd(context.Background(), "1", 1)
+		assert.Equal(t, usecaseerr.ErrAccessDenied, err)
+	})
+}
+
 func TestService_UpdateMyAd(t *testing.T) {
 	t.Run("get by id error", func(t *testing.T) {
 		mockRepo := ad.MockAdRepo{}
@@ -68,13 +145,27 @@ func TestService_UpdateMyAd(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := NewUserService(&mockRepo, &mockFileRepo, customLogger.Logger{})
+		service := NewUserService(&mockRepo, &mockFileRepo, noFavorites(), noRejections(), eventbus.New(), noScreening(),
+			noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
 		mockRepo.On("GetByID", mock.Anything, 1).
 			Return((*entities.Ad)(nil), errors.New("err"))
 		err := service.UpdateMyAd(context.Background(), "1", &entities.Ad{ID: 1})
 
 		assert.Error(t, err)
-		assert.Equal(t, usecaseerr.ErrGettingAdByID, err)
+		assert.ErrorIs(t, err, usecaseerr.ErrGettingAdByID)
+	})
+
+	t.Run("ad not found", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		defer mockRepo.AssertExpectations(t)
+
+		service := NewUserService(&mockRepo, &adfile.MockAdFileRepository{}, noFavorites(), noRejections(),
+			eventbus.New(), noScreening(), noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
+		mockRepo.On("GetByID", mock.Anything, 1).
+			Return((*entities.Ad)(nil), repoerr.ErrAdNotFound)
+		err := service.UpdateMyAd(context.Background(), "1", &entities.Ad{ID: 1})
+
+		assert.Equal(t, usecaseerr.ErrAdNotFound, err)
 	})
 
 	t.Run("access denied", func(t *testing.T) {
@@ -83,7 +174,8 @@ func TestService_UpdateMyAd(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := NewUserService(&mockRepo, &mockFileRepo, customLogger.Logger{})
+		service := NewUserService(&mockRepo, &mockFileRepo, noFavorites(), noRejections(), eventbus.New(), noScreening(),
+			noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
 		mockRepo.On("GetByID", mock.Anything, 1).
 			Return(&entities.Ad{AuthorID: "2"}, nil)
 		err := service.UpdateMyAd(context.Background(), "1", &entities.Ad{ID: 1})
@@ -98,10 +190,11 @@ func TestService_UpdateMyAd(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := NewUserService(&mockRepo, &mockFileRepo, customLogger.Logger{})
+		service := NewUserService(&mockRepo, &mockFileRepo, noFavorites(), noRejections(), eventbus.New(), noScreening(),
+			noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
 		mockRepo.On("GetByID", mock.Anything, 1).Return(&entities.Ad{AuthorID: "1"}, nil)
 		err := service.UpdateMyAd(context.Background(), "1", &entities.Ad{ID: 1, Title: ""})
-		assert.Equal(t, usecaseerr.ErrInvalidParams, err)
+		assert.ErrorIs(t, err, usecaseerr.ErrInvalidParams)
 	})
 
 	t.Run("update error", func(t *testing.T) {
@@ -110,7 +203,8 @@ func TestService_UpdateMyAd(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := NewUserService(&mockRepo, &mockFileRepo, customLogger.Logger{})
+		service := NewUserService(&mockRepo, &mockFileRepo, noFavorites(), noRejections(), eventbus.New(), noScreening(),
+			noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
 
 		adEntity := &entities.Ad{ID: 1, AuthorID: "1", Title: "ok", Description: "desc", CategoryID: 1}
 		mockRepo.On("GetByID", mock.Anything, 1).
@@ -120,7 +214,7 @@ func TestService_UpdateMyAd(t *testing.T) {
 		err := service.UpdateMyAd(context.Background(), "1", adEntity)
 
 		assert.Error(t, err)
-		assert.Equal(t, repoerr.ErrUpdate, err)
+		assert.ErrorIs(t, err, usecaseerr.ErrUpdatingAd)
 	})
 
 	t.Run("success", func(t *testing.T) {
@@ -129,7 +223,8 @@ func TestService_UpdateMyAd(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := NewUserService(&mockRepo, &mockFileRepo, customLogger.Logger{})
+		service := NewUserService(&mockRepo, &mockFileRepo, noFavorites(), noRejections(), eventbus.New(), noScreening(),
+			noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
 		adEntity := &entities.Ad{ID: 1, AuthorID: "1", Title: "ok", Description: "desc", CategoryID: 1}
 		mockRepo.On("GetByID", mock.Anything, 1).
 			Return(&entities.Ad{AuthorID: "1"}, nil)
@@ -139,6 +234,66 @@ func TestService_UpdateMyAd(t *testing.T) {
 
 		assert.NoError(t, err)
 	})
+
+	t.Run("stale version", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		defer mockRepo.AssertExpectations(t)
+
+		service := NewUserService(&mockRepo, &adfile.MockAdFileRepository{}, noFavorites(), noRejections(),
+			eventbus.New(), noScreening(), noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
+		current := &entities.Ad{ID: 1, AuthorID: "1", Title: "new", Version: 3}
+		mockRepo.On("GetByID", mock.Anything, 1).Return(current, nil)
+
+		err := service.UpdateMyAd(context.Background(), "1",
+			&entities.Ad{ID: 1, Title: "ok", CategoryID: 1, Version: 2})
+		var stale *errs.StaleError
+		assert.ErrorAs(t, err, &stale)
+		assert.ErrorIs(t, err, usecaseerr.ErrAdModified)
+		assert.Equal(t, 3, stale.Version)
+		assert.Same(t, current, stale.Current)
+	})
+
+	t.Run("modified between read and write", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		defer mockRepo.AssertExpectations(t)
+
+		service := NewUserService(&mockRepo, &adfile.MockAdFileRepository{}, noFavorites(), noRejections(),
+			eventbus.New(), noScreening(), noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
+		mockRepo.On("GetByID", mock.Anything, 1).Return(&entities.Ad{ID: 1, AuthorID: "1", Version: 2}, nil).Once()
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(repoerr.ErrAdModified)
+		mockRepo.On("GetByID", mock.Anything, 1).Return(&entities.Ad{ID: 1, AuthorID: "1", Version: 3}, nil).Once()
+
+		err := service.UpdateMyAd(context.Background(), "1",
+			&entities.Ad{ID: 1, Title: "ok", CategoryID: 1, Version: 2})
+		var stale *errs.StaleError
+		assert.ErrorAs(t, err, &stale)
+		assert.Equal(t, 3, stale.Version)
+	})
+
+	t.Run("price change of published ad notifies favoriters", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		favorites := &favorite.MockFavoriteRepo{}
+		bus := eventbus.New()
+		events, unsubscribe := bus.Subscribe("buyer")
+		defer unsubscribe()
+		defer favorites.AssertExpectations(t)
+
+		service := NewUserService(&mockRepo, &adfile.MockAdFileRepository{}, favorites, noRejections(), bus, noScreening(),
+			noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
+		mockRepo.On("GetByID", mock.Anything, 1).Return(&entities.Ad{ID: 1, AuthorID: "1", Price: 100,
+			Status: entities.StatusApproved, IsActive: true}, nil)
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+		favorites.On("GetUserIDsByAd", mock.Anything, 1).Return([]string{"buyer"}, nil)
+
+		err := service.UpdateMyAd(context.Background(), "1",
+			&entities.Ad{ID: 1, Title: "ok", CategoryID: 1, Price: 80})
+		assert.NoError(t, err)
+
+		event := <-events
+		assert.Equal(t, entities.EventPriceChanged, event.Type)
+		assert.Equal(t, 100, event.Data["old_price"])
+		assert.Equal(t, 80, event.Data["new_price"])
+	})
 }
 
 func TestService_DeleteMyAd(t *testing.T) {
@@ -148,14 +303,15 @@ func TestService_DeleteMyAd(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := NewUserService(&mockRepo, &mockFileRepo, customLogger.Logger{})
+		service := NewUserService(&mockRepo, &mockFileRepo, noFavorites(), noRejections(), eventbus.New(), noScreening(),
+			noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
 
 		mockRepo.On("GetByID", mock.Anything, 1).
 			Return((*entities.Ad)(nil), errors.New("err"))
 
 		err := service.DeleteMyAd(context.Background(), "1", 1)
 		assert.Error(t, err)
-		assert.Equal(t, usecaseerr.ErrGettingAdByID, err)
+		assert.ErrorIs(t, err, usecaseerr.ErrGettingAdByID)
 	})
 
 	t.Run("access denied", func(t *testing.T) {
@@ -164,7 +320,8 @@ func TestService_DeleteMyAd(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := NewUserService(&mockRepo, &mockFileRepo, customLogger.Logger{})
+		service := NewUserService(&mockRepo, &mockFileRepo, noFavorites(), noRejections(), eventbus.New(), noScreening(),
+			noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
 
 		mockRepo.On("GetByID", mock.Anything, 1).
 			Return(&entities.Ad{AuthorID: "2"}, nil)
@@ -180,33 +337,64 @@ func TestService_DeleteMyAd(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := NewUserService(&mockRepo, &mockFileRepo, customLogger.Logger{})
+		service := NewUserService(&mockRepo, &mockFileRepo, noFavorites(), noRejections(), eventbus.New(), noScreening(),
+			noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
 
 		mockRepo.On("GetByID", mock.Anything, 1).
 			Return(&entities.Ad{AuthorID: "1"}, nil)
+		mockFileRepo.On("GetAll", mock.Anything, 1).
+			Return([]entities.AdFile{}, nil)
 		mockRepo.On("Delete", mock.Anything, 1).
 			Return(errors.New("err"))
 
 		err := service.DeleteMyAd(context.Background(), "1", 1)
 		assert.Error(t, err)
-		assert.Equal(t, repoerr.ErrDelete, err)
+		assert.ErrorIs(t, err, usecaseerr.ErrDeletingAd)
 	})
 
-	t.Run("success", func(t *testing.T) {
+	t.Run("success removes the images", func(t *testing.T) {
 		mockRepo := ad.MockAdRepo{}
 		mockFileRepo := adfile.MockAdFileRepository{}
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := NewUserService(&mockRepo, &mockFileRepo, customLogger.Logger{})
+		service := NewUserService(&mockRepo, &mockFileRepo, noFavorites(), noRejections(), eventbus.New(), noScreening(),
+			noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
+
+		image := filepath.Join(t.TempDir(), "1_photo.jpg")
+		require.NoError(t, os.WriteFile(image, []byte("jpeg"), 0o600))
 
 		mockRepo.On("GetByID", mock.Anything, 1).
 			Return(&entities.Ad{AuthorID: "1"}, nil)
+		mockFileRepo.On("GetAll", mock.Anything, 1).
+			Return([]entities.AdFile{{ID: 1, AdID: 1, URL: image}}, nil)
 		mockRepo.On("Delete", mock.Anything, 1).
 			Return(nil)
 
 		err := service.DeleteMyAd(context.Background(), "1", 1)
 		assert.NoError(t, err)
+		assert.NoFileExists(t, image)
+	})
+
+	t.Run("rolled back delete keeps the images", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		mockFileRepo := adfile.MockAdFileRepository{}
+		tx := &db.MockTxManager{}
+		defer mockRepo.AssertExpectations(t)
+		defer mockFileRepo.AssertExpectations(t)
+
+		service := NewUserService(&mockRepo, &mockFileRepo, noFavorites(), noRejections(), eventbus.New(), noScreening(),
+			noDuplicates(), noHistory(), tx, customLogger.Logger{})
+
+		image := filepath.Join(t.TempDir(), "1_photo.jpg")
+		require.NoError(t, os.WriteFile(image, []byte("jpeg"), 0o600))
+
+		// fails as a commit would, after the statements
+		tx.On("WithinTx", mock.Anything).Return(errors.New("commit failed"))
+
+		err := service.DeleteMyAd(context.Background(), "1", 1)
+		assert.Error(t, err)
+		assert.FileExists(t, image)
 	})
 }
 
@@ -217,14 +405,15 @@ func TestService_GetMyAds(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := NewUserService(&mockRepo, &mockFileRepo, customLogger.Logger{})
+		service := NewUserService(&mockRepo, &mockFileRepo, noFavorites(), noRejections(), eventbus.New(), noScreening(),
+			noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
 
 		mockRepo.On("GetByUserID", mock.Anything, "1").
 			Return([]entities.Ad{}, errors.New("db error"))
 
 		ads, err := service.GetMyAds(context.Background(), "1")
 		assert.Nil(t, ads)
-		assert.Equal(t, repoerr.ErrGettingAdsByUserID, err)
+		assert.ErrorIs(t, err, usecaseerr.ErrGettingUserAds)
 	})
 
 	t.Run("user has no ads", func(t *testing.T) {
@@ -233,7 +422,8 @@ func TestService_GetMyAds(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := NewUserService(&mockRepo, &mockFileRepo, customLogger.Logger{})
+		service := NewUserService(&mockRepo, &mockFileRepo, noFavorites(), noRejections(), eventbus.New(), noScreening(),
+			noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
 
 		mockRepo.On("GetByUserID", mock.Anything, "1").
 			Return([]entities.Ad{}, nil)
@@ -249,7 +439,8 @@ func TestService_GetMyAds(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := NewUserService(&mockRepo, &mockFileRepo, customLogger.Logger{})
+		service := NewUserService(&mockRepo, &mockFileRepo, noFavorites(), noRejections(), eventbus.New(), noScreening(),
+			noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
 		expectedAds := []entities.Ad{
 			{ID: 1, AuthorID: "1", Title: "ad1"},
 			{ID: 2, AuthorID: "1", Title: "ad2"},
@@ -261,6 +452,74 @@ func TestService_GetMyAds(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, expectedAds, ads)
 	})
+
+	t.Run("with favorite counts", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		mockFavoriteRepo := favorite.MockFavoriteRepo{}
+		defer mockRepo.AssertExpectations(t)
+		defer mockFavoriteRepo.AssertExpectations(t)
+
+		service := NewUserService(&mockRepo, &adfile.MockAdFileRepository{}, &mockFavoriteRepo, noRejections(),
+			eventbus.New(), noScreening(), noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
+		mockRepo.On("GetByUserID", mock.Anything, "1").
+			Return([]entities.Ad{{ID: 1}, {ID: 2}}, nil)
+		mockFavoriteRepo.On("CountByAds", mock.Anything, []int{1, 2}).
+			Return(map[int]int{2: 4}, nil)
+
+		ads, err := service.GetMyAds(context.Background(), "1")
+		assert.NoError(t, err)
+		assert.Equal(t, 0, ads[0].FavoriteCount)
+		assert.Equal(t, 4, ads[1].FavoriteCount)
+	})
+
+	t.Run("favorite count error", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		mockFavoriteRepo := favorite.MockFavoriteRepo{}
+
+		service := NewUserService(&mockRepo, &adfile.MockAdFileRepository{}, &mockFavoriteRepo, noRejections(),
+			eventbus.New(), noScreening(), noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
+		mockRepo.On("GetByUserID", mock.Anything, "1").
+			Return([]entities.Ad{{ID: 1}}, nil)
+		mockFavoriteRepo.On("CountByAds", mock.Anything, mock.Anything).
+			Return(nil, repoerr.ErrFavoriteSelect)
+
+		ads, err := service.GetMyAds(context.Background(), "1")
+		assert.Nil(t, ads)
+		assert.Equal(t, usecaseerr.ErrGettingFavorites, err)
+	})
+
+	t.Run("with rejection reasons", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		mockRejectionRepo := rejection.MockRejectionRepo{}
+		defer mockRejectionRepo.AssertExpectations(t)
+
+		service := NewUserService(&mockRepo, &adfile.MockAdFileRepository{}, noFavorites(), &mockRejectionRepo,
+			eventbus.New(), noScreening(), noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
+		mockRepo.On("GetByUserID", mock.Anything, "1").
+			Return([]entities.Ad{{ID: 1, Status: entities.StatusApproved}, {ID: 2, Status: entities.StatusRejected}}, nil)
+		mockRejectionRepo.On("GetByAds", mock.Anything, []int{2}).
+			Return(map[int]entities.Rejection{2: {Code: "poor_photos", Texts: map[string]string{"en": "Bad photos."}}}, nil)
+
+		ads, err := service.GetMyAds(context.Background(), "1")
+		assert.NoError(t, err)
+		assert.Nil(t, ads[0].Rejection)
+		assert.Equal(t, "poor_photos", ads[1].Rejection.Code)
+	})
+
+	t.Run("rejection reasons error", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		mockRejectionRepo := rejection.MockRejectionRepo{}
+
+		service := NewUserService(&mockRepo, &adfile.MockAdFileRepository{}, noFavorites(), &mockRejectionRepo,
+			eventbus.New(), noScreening(), noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
+		mockRepo.On("GetByUserID", mock.Anything, "1").
+			Return([]entities.Ad{{ID: 2, Status: entities.StatusRejected}}, nil)
+		mockRejectionRepo.On("GetByAds", mock.Anything, []int{2}).Return(nil, repoerr.ErrRejectionSelect)
+
+		ads, err := service.GetMyAds(context.Background(), "1")
+		assert.Nil(t, ads)
+		assert.Equal(t, usecaseerr.ErrGettingRejections, err)
+	})
 }
 
 func TestService_SubmitForModeration(t *testing.T) {
@@ -270,14 +529,15 @@ func TestService_SubmitForModeration(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := NewUserService(&mockRepo, &mockFileRepo, customLogger.Logger{})
+		service := NewUserService(&mockRepo, &mockFileRepo, noFavorites(), noRejections(), eventbus.New(), noScreening(),
+			noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
 
 		mockRepo.On("GetByID", mock.Anything, 1).
 			Return((*entities.Ad)(nil), errors.New("err"))
 
 		err := service.SubmitForModeration(context.Background(), "1", 1)
 		assert.Error(t, err)
-		assert.Equal(t, usecaseerr.ErrGettingAdByID, err)
+		assert.ErrorIs(t, err, usecaseerr.ErrGettingAdByID)
 	})
 
 	t.Run("access denied", func(t *testing.T) {
@@ -286,7 +546,8 @@ func TestService_SubmitForModeration(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := NewUserService(&mockRepo, &mockFileRepo, customLogger.Logger{})
+		service := NewUserService(&mockRepo, &mockFileRepo, noFavorites(), noRejections(), eventbus.New(), noScreening(),
+			noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
 		mockRepo.On("GetByID", mock.Anything, 1).
 			Return(&entities.Ad{AuthorID: "2"}, nil)
 
@@ -301,7 +562,8 @@ func TestService_SubmitForModeration(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := NewUserService(&mockRepo, &mockFileRepo, customLogger.Logger{})
+		service := NewUserService(&mockRepo, &mockFileRepo, noFavorites(), noRejections(), eventbus.New(), noScreening(),
+			noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
 
 		adEntity := &entities.Ad{ID: 1, AuthorID: "1"}
 		mockRepo.On("GetByID", mock.Anything, 1).
@@ -311,7 +573,25 @@ func TestService_SubmitForModeration(t *testing.T) {
 
 		err := service.SubmitForModeration(context.Background(), "1", 1)
 		assert.Error(t, err)
-		assert.Equal(t, usecaseerr.ErrApprovingAd, err)
+		assert.ErrorIs(t, err, usecaseerr.ErrSubmittingAd)
+	})
+
+	t.Run("record error fails the submission", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		historyRepo := &history.MockHistoryRepo{}
+		defer historyRepo.AssertExpectations(t)
+
+		service := NewUserService(&mockRepo, &adfile.MockAdFileRepository{}, noFavorites(), noRejections(),
+			eventbus.New(), noScreening(), noDuplicates(), historyRepo, inTx(), customLogger.Logger{})
+
+		mockRepo.On("GetByID", mock.Anything, 1).Return(&entities.Ad{ID: 1, AuthorID: "1"}, nil)
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+		historyRepo.On("Record", mock.Anything, &entities.ModerationEvent{
+			AdID: 1, Action: entities.ActionSubmitted, ActorID: "1",
+		}).Return(assert.AnError)
+
+		err := service.SubmitForModeration(context.Background(), "1", 1)
+		assert.ErrorIs(t, err, usecaseerr.ErrSubmittingAd)
 	})
 
 	t.Run("success", func(t *testing.T) {
@@ -320,17 +600,76 @@ func TestService_SubmitForModeration(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := NewUserService(&mockRepo, &mockFileRepo, customLogger.Logger{})
+		screening := &premoderation.MockPreModerationService{}
+		defer screening.AssertExpectations(t)
+		service := NewUserService(&mockRepo, &mockFileRepo, noFavorites(), noRejections(), eventbus.New(), screening,
+			noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
 
 		adEntity := &entities.Ad{ID: 1, AuthorID: "1"}
 		mockRepo.On("GetByID", mock.Anything, 1).
 			Return(adEntity, nil)
 		mockRepo.On("Update", mock.Anything, mock.Anything).
 			Return(nil)
+		screening.On("Screen", mock.Anything, adEntity).Return(entities.DecisionReview, nil)
 
 		err := service.SubmitForModeration(context.Background(), "1", 1)
 		assert.NoError(t, err)
 	})
+
+	t.Run("repost is refused", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		defer mockRepo.AssertExpectations(t)
+
+		duplicates := &duplicate.MockDuplicateService{}
+		defer duplicates.AssertExpectations(t)
+		service := NewUserService(&mockRepo, &adfile.MockAdFileRepository{}, noFavorites(), noRejections(), eventbus.New(),
+			noScreening(), duplicates, noHistory(), inTx(), customLogger.Logger{})
+
+		adEntity := &entities.Ad{ID: 1, AuthorID: "1", Status: entities.StatusRejected}
+		mockRepo.On("GetByID", mock.Anything, 1).Return(adEntity, nil)
+		duplicates.On("Fingerprint", mock.Anything, adEntity).Return(usecaseerr.ErrRepost)
+
+		err := service.SubmitForModeration(context.Background(), "1", 1)
+		assert.Equal(t, usecaseerr.ErrRepost, err)
+		assert.Equal(t, entities.StatusRejected, adEntity.Status)
+	})
+
+	t.Run("fingerprint error fails the submission", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		defer mockRepo.AssertExpectations(t)
+
+		duplicates := &duplicate.MockDuplicateService{}
+		defer duplicates.AssertExpectations(t)
+		service := NewUserService(&mockRepo, &adfile.MockAdFileRepository{}, noFavorites(), noRejections(), eventbus.New(),
+			noScreening(), duplicates, noHistory(), inTx(), customLogger.Logger{})
+
+		adEntity := &entities.Ad{ID: 1, AuthorID: "1", Status: entities.StatusRejected}
+		mockRepo.On("GetByID", mock.Anything, 1).Return(adEntity, nil)
+		duplicates.On("Fingerprint", mock.Anything, adEntity).Return(usecaseerr.ErrFingerprinting)
+
+		err := service.SubmitForModeration(context.Background(), "1", 1)
+		assert.ErrorIs(t, err, usecaseerr.ErrSubmittingAd)
+		assert.Equal(t, entities.StatusRejected, adEntity.Status)
+	})
+
+	t.Run("screening error keeps the ad pending", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		defer mockRepo.AssertExpectations(t)
+
+		screening := &premoderation.MockPreModerationService{}
+		defer screening.AssertExpectations(t)
+		service := NewUserService(&mockRepo, &adfile.MockAdFileRepository{}, noFavorites(), noRejections(), eventbus.New(),
+			screening, noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
+
+		adEntity := &entities.Ad{ID: 1, AuthorID: "1"}
+		mockRepo.On("GetByID", mock.Anything, 1).Return(adEntity, nil)
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+		screening.On("Screen", mock.Anything, adEntity).Return(entities.DecisionReview, usecaseerr.ErrScreeningAd)
+
+		err := service.SubmitForModeration(context.Background(), "1", 1)
+		assert.NoError(t, err)
+		assert.Equal(t, entities.StatusPending, adEntity.Status)
+	})
 }
 
 func TestService_AddImageToMyAd(t *testing.T) {
@@ -340,14 +679,15 @@ func TestService_AddImageToMyAd(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := &service{repo: &mockRepo, fileRepo: &mockFileRepo}
+		service := &service{repo: &mockRepo, fileRepo: &mockFileRepo, tx: inTx()}
 
 		mockRepo.On("GetByID", mock.Anything, 1).
 			Return((*entities.Ad)(nil), errors.New("err"))
 
-		err := service.AddImageToMyAd(context.Background(), "1", &entities.AdFile{AdID: 1, FileName: "img.jpg"})
+		err := service.AddImageToMyAd(context.Background(), "1",
+			&entities.AdFile{AdID: 1, FileName: "img.jpg"}, 0, noSave)
 		assert.Error(t, err)
-		assert.Equal(t, repoerr.ErrSelection, err)
+		assert.ErrorIs(t, err, usecaseerr.ErrGettingAdByID)
 	})
 
 	t.Run("access denied", func(t *testing.T) {
@@ -356,12 +696,13 @@ func TestService_AddImageToMyAd(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := &service{repo: &mockRepo, fileRepo: &mockFileRepo}
+		service := &service{repo: &mockRepo, fileRepo: &mockFileRepo, tx: inTx()}
 
 		mockRepo.On("GetByID", mock.Anything, 1).
 			Return(&entities.Ad{AuthorID: "2"}, nil)
 
-		err := service.AddImageToMyAd(context.Background(), "1", &entities.AdFile{AdID: 1, FileName: "img.jpg"})
+		err := service.AddImageToMyAd(context.Background(), "1",
+			&entities.AdFile{AdID: 1, FileName: "img.jpg"}, 0, noSave)
 		assert.Error(t, err)
 		assert.Equal(t, usecaseerr.ErrAccessDenied, err)
 	})
@@ -372,12 +713,13 @@ func TestService_AddImageToMyAd(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := &service{repo: &mockRepo, fileRepo: &mockFileRepo}
+		service := &service{repo: &mockRepo, fileRepo: &mockFileRepo, tx: inTx()}
 
 		mockRepo.On("GetByID", mock.Anything, 1).
 			Return(&entities.Ad{AuthorID: "1"}, nil)
 
-		err := service.AddImageToMyAd(context.Background(), "1", &entities.AdFile{AdID: 1, FileName: "file.exe"})
+		err := service.AddImageToMyAd(context.Background(), "1",
+			&entities.AdFile{AdID: 1, FileName: "file.exe"}, 0, noSave)
 		assert.Error(t, err)
 		assert.Equal(t, usecaseerr.ErrFileNotAllowed, err)
 	})
@@ -388,7 +730,7 @@ func TestService_AddImageToMyAd(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := &service{repo: &mockRepo, fileRepo: &mockFileRepo}
+		service := &service{repo: &mockRepo, fileRepo: &mockFileRepo, tx: inTx()}
 
 		mockRepo.On("GetByID", mock.Anything, 1).
 			Return(&entities.Ad{AuthorID: "1"}, nil)
@@ -396,9 +738,9 @@ func TestService_AddImageToMyAd(t *testing.T) {
 			Return(-1, repoerr.ErrFileInsertion)
 
 		err := service.AddImageToMyAd(context.Background(), "1",
-			&entities.AdFile{AdID: 1, FileName: "img.jpg"})
+			&entities.AdFile{AdID: 1, FileName: "img.jpg"}, 0, noSave)
 		assert.Error(t, err)
-		assert.Equal(t, repoerr.ErrFileInsertion, err)
+		assert.ErrorIs(t, err, usecaseerr.ErrAddingImage)
 	})
 
 	t.Run("success", func(t *testing.T) {
@@ -407,19 +749,47 @@ func TestService_AddImageToMyAd(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := &service{repo: &mockRepo, fileRepo: &mockFileRepo}
+		service := &service{repo: &mockRepo, fileRepo: &mockFileRepo, tx: inTx()}
 
 		mockRepo.On("GetByID", mock.Anything, 1).
 			Return(&entities.Ad{AuthorID: "1"}, nil)
-		mockFileRepo.On("Create", mock.Anything, mock.Anything).
-			Return(1, nil)
-
-		err := service.AddImageToMyAd(context.Background(), "1",
-			&entities.AdFile{AdID: 1, FileName: "img.jpg"})
+		mockFileRepo.On("Create", mock.Anything, mock.MatchedBy(func(file *entities.AdFile) bool {
+			return strings.HasPrefix(file.URL, "storage/uploadings/ad_1/") && strings.HasSuffix(file.URL, "_img.jpg")
+		})).Return(1, nil)
+		mockRepo.On("Touch", mock.Anything, mock.AnythingOfType("*entities.Ad")).Return(nil)
+
+		file := &entities.AdFile{AdID: 1, FileName: "img.jpg"}
+		var saved string
+		err := service.AddImageToMyAd(context.Background(), "1", file, 0, func(path string) error {
+			saved = path
+			return nil
+		})
 		assert.NoError(t, err)
+		assert.Equal(t, 1, file.ID)
+		assert.Equal(t, file.URL, saved)
+	})
+
+	t.Run("save error", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		mockFileRepo := adfile.MockAdFileRepository{}
+		defer mockRepo.AssertExpectations(t)
+		defer mockFileRepo.AssertExpectations(t)
+
+		service := &service{repo: &mockRepo, fileRepo: &mockFileRepo, tx: inTx()}
+
+		mockRepo.On("GetByID", mock.Anything, 1).
+			Return(&entities.Ad{AuthorID: "1"}, nil)
+		mockFileRepo.On("Create", mock.Anything, mock.Anything).Return(1, nil)
+		mockRepo.On("Touch", mock.Anything, mock.AnythingOfType("*entities.Ad")).Return(nil)
+
+		err := service.AddImageToMyAd(context.Background(), "1", &entities.AdFile{AdID: 1, FileName: "img.jpg"}, 0,
+			func(string) error { return errors.New("disk full") })
+		assert.ErrorIs(t, err, usecaseerr.ErrAddingImage)
 	})
 }
 
+func noSave(string) error { return nil }
+
 func TestService_GetImagesToMyAd(t *testing.T) {
 	t.Run("get by id error", func(t *testing.T) {
 		mockRepo := ad.MockAdRepo{}
@@ -427,14 +797,14 @@ func TestService_GetImagesToMyAd(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := &service{repo: &mockRepo, fileRepo: &mockFileRepo}
+		service := &service{repo: &mockRepo, fileRepo: &mockFileRepo, tx: inTx()}
 
 		mockRepo.On("GetByID", mock.Anything, 1).
 			Return(nil, errors.New("db error"))
 
 		files, err := service.GetImagesToMyAd(context.Background(), "1", 1)
 		assert.Nil(t, files)
-		assert.Equal(t, repoerr.ErrSelection, err)
+		assert.ErrorIs(t, err, usecaseerr.ErrGettingAdByID)
 	})
 
 	t.Run("access denied", func(t *testing.T) {
@@ -443,7 +813,7 @@ func TestService_GetImagesToMyAd(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := &service{repo: &mockRepo, fileRepo: &mockFileRepo}
+		service := &service{repo: &mockRepo, fileRepo: &mockFileRepo, tx: inTx()}
 
 		mockRepo.On("GetByID", mock.Anything, 1).
 			Return(&entities.Ad{AuthorID: "2"}, nil)
@@ -459,7 +829,7 @@ func TestService_GetImagesToMyAd(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := &service{repo: &mockRepo, fileRepo: &mockFileRepo}
+		service := &service{repo: &mockRepo, fileRepo: &mockFileRepo, tx: inTx()}
 
 		mockRepo.On("GetByID", mock.Anything, 1).
 			Return(&entities.Ad{AuthorID: "1"}, nil)
@@ -468,7 +838,7 @@ func TestService_GetImagesToMyAd(t *testing.T) {
 
 		files, err := service.GetImagesToMyAd(context.Background(), "1", 1)
 		assert.Nil(t, files)
-		assert.Equal(t, repoerr.ErrSelection, err)
+		assert.ErrorIs(t, err, usecaseerr.ErrGettingImages)
 	})
 
 	t.Run("success", func(t *testing.T) {
@@ -477,7 +847,7 @@ func TestService_GetImagesToMyAd(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := &service{repo: &mockRepo, fileRepo: &mockFileRepo}
+		service := &service{repo: &mockRepo, fileRepo: &mockFileRepo, tx: inTx()}
 		expectedFiles := []entities.AdFile{
 			{ID: 1, AdID: 1, FileName: "img1.jpg"},
 			{ID: 2, AdID: 1, FileName: "img2.jpg"},
@@ -501,13 +871,13 @@ func TestService_DeleteMyAdImage(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFile.AssertExpectations(t)
 
-		service := &service{repo: &mockRepo, fileRepo: &mockFile}
+		service := &service{repo: &mockRepo, fileRepo: &mockFile, tx: inTx()}
 
 		mockRepo.On("GetByID", mock.Anything, 1).
 			Return(nil, errors.New("err"))
 
-		err := service.DeleteMyAdImage(context.Background(), "1", &entities.AdFile{AdID: 1})
-		assert.Equal(t, repoerr.ErrSelection, err)
+		err := service.DeleteMyAdImage(context.Background(), "1", &entities.AdFile{AdID: 1}, 0)
+		assert.ErrorIs(t, err, usecaseerr.ErrGettingAdByID)
 	})
 
 	t.Run("access denied", func(t *testing.T) {
@@ -516,12 +886,12 @@ func TestService_DeleteMyAdImage(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFile.AssertExpectations(t)
 
-		service := &service{repo: &mockRepo, fileRepo: &mockFile}
+		service := &service{repo: &mockRepo, fileRepo: &mockFile, tx: inTx()}
 
 		mockRepo.On("GetByID", mock.Anything, 1).
 			Return(&entities.Ad{AuthorID: "2"}, nil)
 
-		err := service.DeleteMyAdImage(context.Background(), "1", &entities.AdFile{AdID: 1})
+		err := service.DeleteMyAdImage(context.Background(), "1", &entities.AdFile{AdID: 1}, 0)
 		assert.Equal(t, usecaseerr.ErrAccessDenied, err)
 	})
 
@@ -531,15 +901,15 @@ func TestService_DeleteMyAdImage(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFile.AssertExpectations(t)
 
-		service := &service{repo: &mockRepo, fileRepo: &mockFile}
+		service := &service{repo: &mockRepo, fileRepo: &mockFile, tx: inTx()}
 
 		mockRepo.On("GetByID", mock.Anything, 1).
 			Return(&entities.Ad{AuthorID: "1"}, nil)
 		mockFile.On("Delete", mock.Anything, mock.Anything).
 			Return("", repoerr.ErrFileDeletion)
 
-		err := service.DeleteMyAdImage(context.Background(), "1", &entities.AdFile{AdID: 1})
-		assert.Equal(t, repoerr.ErrFileDeletion, err)
+		err := service.DeleteMyAdImage(context.Background(), "1", &entities.AdFile{AdID: 1}, 0)
+		assert.ErrorIs(t, err, usecaseerr.ErrDeletingImage)
 	})
 
 	t.Run("success", func(t *testing.T) {
@@ -548,16 +918,33 @@ func TestService_DeleteMyAdImage(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFile.AssertExpectations(t)
 
-		service := &service{repo: &mockRepo, fileRepo: &mockFile}
+		service := &service{repo: &mockRepo, fileRepo: &mockFile, tx: inTx()}
 
 		mockRepo.On("GetByID", mock.Anything, 1).
 			Return(&entities.Ad{AuthorID: "1"}, nil)
 		mockFile.On("Delete", mock.Anything, mock.Anything).
 			Return("file.jpg", nil)
+		mockRepo.On("Touch", mock.Anything, mock.AnythingOfType("*entities.Ad")).Return(nil)
 
-		err := service.DeleteMyAdImage(context.Background(), "1", &entities.AdFile{AdID: 1})
+		err := service.DeleteMyAdImage(context.Background(), "1", &entities.AdFile{AdID: 1}, 0)
 		assert.NoError(t, err)
 	})
+
+	t.Run("stale version", func(t *testing.T) {
+		mockRepo := ad.MockAdRepo{}
+		mockFile := adfile.MockAdFileRepository{}
+		defer mockFile.AssertExpectations(t)
+
+		service := &service{repo: &mockRepo, fileRepo: &mockFile, tx: inTx()}
+
+		mockRepo.On("GetByID", mock.Anything, 1).
+			Return(&entities.Ad{ID: 1, AuthorID: "1", Version: 3}, nil)
+
+		err := service.DeleteMyAdImage(context.Background(), "1", &entities.AdFile{AdID: 1}, 2)
+		var stale *errs.StaleError
+		assert.ErrorAs(t, err, &stale)
+		assert.Equal(t, 3, stale.Version)
+	})
 }
 
 func TestService_GetMyAdsByFilter(t *testing.T) {
@@ -567,14 +954,15 @@ func TestService_GetMyAdsByFilter(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := NewUserService(&mockRepo, &mockFileRepo, customLogger.Logger{})
+		service := NewUserService(&mockRepo, &mockFileRepo, noFavorites(), noRejections(), eventbus.New(), noScreening(),
+			noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
 		filter := entities.AdFilter{}
 		mockRepo.On("Filter", mock.Anything, mock.Anything).
 			Return([]entities.Ad{}, errors.New("db error"))
 
 		ads, err := service.GetMyAdsByFilter(context.Background(), "1", &filter)
 		assert.Nil(t, ads)
-		assert.Equal(t, repoerr.ErrGettingAdsByUserID, err)
+		assert.ErrorIs(t, err, usecaseerr.ErrGettingUserAds)
 	})
 
 	t.Run("user has no ads", func(t *testing.T) {
@@ -583,7 +971,8 @@ func TestService_GetMyAdsByFilter(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := NewUserService(&mockRepo, &mockFileRepo, customLogger.Logger{})
+		service := NewUserService(&mockRepo, &mockFileRepo, noFavorites(), noRejections(), eventbus.New(), noScreening(),
+			noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
 		filter := entities.AdFilter{}
 		mockRepo.On("Filter", mock.Anything, mock.Anything).
 			Return([]entities.Ad{}, nil)
@@ -599,7 +988,8 @@ func TestService_GetMyAdsByFilter(t *testing.T) {
 		defer mockRepo.AssertExpectations(t)
 		defer mockFileRepo.AssertExpectations(t)
 
-		service := NewUserService(&mockRepo, &mockFileRepo, customLogger.Logger{})
+		service := NewUserService(&mockRepo, &mockFileRepo, noFavorites(), noRejections(), eventbus.New(), noScreening(),
+			noDuplicates(), noHistory(), inTx(), customLogger.Logger{})
 		expectedAds := []entities.Ad{
 			{ID: 1, AuthorID: "1", Title: "ad1"},
 			{ID: 2, AuthorID: "1", Title: "ad2"},
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// DefaultFetchSize - rows fetched from a cursor at a time.
+const DefaultFetchSize = 500
+
+const (
+	// streamIdleTimeout - how long the server waits for the next fetch, i.e. for scan to get through a batch,
+	// before it ends the session and gives the connection back.
+	streamIdleTimeout = 30 * time.Second
+	// streamStatementTimeout bounds the declare and every fetch.
+	streamStatementTimeout = time.Minute
+)
+
+// Stream runs the query through a server-side cursor and calls scan for every row, fetching fetchSize rows
+// at a time, so a large result is never held in memory. The cursor lives in its own transaction, which is
+// rolled back at the end, and stops at the first error of scan. A reader too slow to take a batch within
+// streamIdleTimeout loses the cursor, the transaction holds a pooled connection for as long as it lasts.
+func Stream(ctx context.Context, pool Pool, fetchSize int, scan func(pgx.Rows) error,
+	sql string, args ...interface{}) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin cursor transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	timeouts := fmt.Sprintf("SET LOCAL statement_timeout = %d; SET LOCAL idle_in_transaction_session_timeout = %d",
+		streamStatementTimeout.Milliseconds(), streamIdleTimeout.Milliseconds())
+	if _, err = tx.Exec(ctx, timeouts); err != nil {
+		return fmt.Errorf("failed to set cursor timeouts: %w", err)
+	}
+
+	if _, err = tx.Exec(ctx, "DECLARE stream_cursor NO SCROLL CURSOR FOR "+sql, args...); err != nil {
+		return fmt.Errorf("failed to declare cursor: %w", err)
+	}
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM stream_cursor", fetchSize)
+	for {
+		fetched, err := fetchBatch(ctx, tx, fetch, scan)
+		if err != nil {
+			return err
+		}
+		if fetched < fetchSize {
+			return nil
+		}
+	}
+}
+
+func fetchBatch(ctx context.Context, tx pgx.Tx, fetch string, scan func(pgx.Rows) error) (int, error) {
+	rows, err := tx.Query(ctx, fetch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch from cursor: %w", err)
+	}
+	defer rows.Close()
+
+	fetched := 0
+	for rows.Next() {
+		fetched++
+		if err = scan(rows); err != nil {
+			return fetched, err
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return fetched, fmt.Errorf("failed to read cursor: %w", err)
+	}
+	return fetched, nil
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStream(t *testing.T) {
+	t.Run("fetches until a short batch", func(t *testing.T) {
+		pool := new(MockPool)
+		tx := new(MockTx)
+		full, short := new(MockRows), new(MockRows)
+		defer tx.AssertExpectations(t)
+
+		pool.On("Begin", mock.Anything).Return(tx, nil)
+		tx.On("Exec", mock.Anything,
+			"SET LOCAL statement_timeout = 60000; SET LOCAL idle_in_transaction_session_timeout = 30000",
+			[]interface{}(nil)).Return(pgconn.CommandTag{}, nil)
+		tx.On("Exec", mock.Anything, "DECLARE stream_cursor NO SCROLL CURSOR FOR SELECT id FROM ads WHERE status = $1",
+			[]interface{}{"approved"}).Return(pgconn.CommandTag{}, nil)
+		tx.On("Query", mock.Anything, "FETCH FORWARD 2 FROM stream_cursor", mock.Anything).Return(full, nil).Once()
+		tx.On("Query", mock.Anything, "FETCH FORWARD 2 FROM stream_cursor", mock.Anything).Return(short, nil).Once()
+		tx.On("Rollback", mock.Anything).Return(nil)
+		full.On("Next").Return(true).Twice()
+		full.On("Next").Return(false).Once()
+		full.On("Err").Return(nil)
+		full.On("Close").Return()
+		short.On("Next").Return(true).Once()
+		short.On("Next").Return(false).Once()
+		short.On("Err").Return(nil)
+		short.On("Close").Return()
+
+		scanned := 0
+		err := Stream(context.Background(), pool, 2, func(pgx.Rows) error {
+			scanned++
+			return nil
+		}, "SELECT id FROM ads WHERE status = $1", "approved")
+		assert.NoError(t, err)
+		assert.Equal(t, 3, scanned)
+	})
+
+	t.Run("scan error stops", func(t *testing.T) {
+		pool := new(MockPool)
+		tx := new(MockTx)
+		rows := new(MockRows)
+		defer tx.AssertExpectations(t)
+
+		pool.On("Begin", mock.Anything).Return(tx, nil)
+		tx.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(pgconn.CommandTag{}, nil)
+		tx.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(rows, nil).Once()
+		tx.On("Rollback", mock.Anything).Return(nil)
+		rows.On("Next").Return(true).Once()
+		rows.On("Close").Return()
+
+		failure := errors.New("client gone")
+		err := Stream(context.Background(), pool, 2, func(pgx.Rows) error {
+			return failure
+		}, "SELECT id FROM ads")
+		assert.ErrorIs(t, err, failure)
+	})
+
+	t.Run("declare error", func(t *testing.T) {
+		pool := new(MockPool)
+		tx := new(MockTx)
+		defer tx.AssertExpectations(t)
+
+		pool.On("Begin", mock.Anything).Return(tx, nil)
+		tx.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(pgconn.CommandTag{}, nil).Once()
+		tx.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(pgconn.CommandTag{}, errors.New("syntax"))
+		tx.On("Rollback", mock.Anything).Return(nil)
+
+		err := Stream(context.Background(), pool, 2, func(pgx.Rows) error { return nil }, "SELEC")
+		assert.Error(t, err)
+	})
+}
@@ -3,6 +3,8 @@ package db
 import (
 	"context"
 	"fmt"
+	"time"
+
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -16,12 +18,33 @@ type Pool interface {
 	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
 	Begin(ctx context.Context) (pgx.Tx, error)
 	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	Stats() Stats
+}
+
+// Stats - snapshot of the connection pool, the counters and durations are totals since the pool was created.
+type Stats struct {
+	AcquireDuration      time.Duration
+	AcquireCount         int64
+	EmptyAcquireCount    int64
+	CanceledAcquireCount int64
+	AcquiredConns        int32
+	IdleConns            int32
+	TotalConns           int32
+	MaxConns             int32
 }
 
 type conn struct {
 	*pgxpool.Pool
 }
 
+// querier - what the pool and a transaction have in common.
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+}
+
 /*// v1
 func NewDB(dsn string) (*pgxpool.Pool, error) {
 	config, err := pgxpool.ParseConfig(dsn)
@@ -50,6 +73,7 @@ func NewDB(dsn string) (Pool, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse dsn: %w", err)
 	}
+	config.ConnConfig.Tracer = queryTracer{}
 
 	pool, err := pgxpool.NewWithConfig(context.Background(), config)
 	if err != nil {
@@ -77,11 +101,11 @@ func (c *conn) Close() {
 }
 
 func (c *conn) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
-	return c.Pool.QueryRow(ctx, sql, args...)
+	return c.querier(ctx).QueryRow(ctx, sql, args...)
 }
 
 func (c *conn) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
-	rows, err := c.Pool.Query(ctx, sql, args...)
+	rows, err := c.querier(ctx).Query(ctx, sql, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query: %w", err)
 	}
@@ -89,7 +113,7 @@ func (c *conn) Query(ctx context.Context, sql string, args ...interface{}) (pgx.
 }
 
 func (c *conn) Begin(ctx context.Context) (pgx.Tx, error) {
-	tx, err := c.Pool.Begin(ctx)
+	tx, err := c.querier(ctx).Begin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -97,9 +121,32 @@ func (c *conn) Begin(ctx context.Context) (pgx.Tx, error) {
 }
 
 func (c *conn) Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
-	tag, err := c.Pool.Exec(ctx, sql, arguments...)
+	tag, err := c.querier(ctx).Exec(ctx, sql, arguments...)
 	if err != nil {
 		return tag, fmt.Errorf("failed to exec: %w", err)
 	}
 	return tag, nil
 }
+
+// querier is the transaction of the context if there is one, so repositories join it without knowing.
+// Begin inside a transaction starts a savepoint.
+func (c *conn) querier(ctx context.Context) querier {
+	if tx, ok := txFrom(ctx); ok {
+		return tx
+	}
+	return c.Pool
+}
+
+func (c *conn) Stats() Stats {
+	stat := c.Pool.Stat()
+	return Stats{
+		AcquireDuration:      stat.AcquireDuration(),
+		AcquireCount:         stat.AcquireCount(),
+		EmptyAcquireCount:    stat.EmptyAcquireCount(),
+		CanceledAcquireCount: stat.CanceledAcquireCount(),
+		AcquiredConns:        stat.AcquiredConns(),
+		IdleConns:            stat.IdleConns(),
+		TotalConns:           stat.TotalConns(),
+		MaxConns:             stat.MaxConns(),
+	}
+}
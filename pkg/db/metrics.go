@@ -0,0 +1,39 @@
+package db
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// RegisterMetrics exposes the statistics of the pool, read on every scrape.
+func RegisterMetrics(registerer prometheus.Registerer, pool Pool) error {
+	gauge := func(name, help string, value func(Stats) float64) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: name, Help: help},
+			func() float64 { return value(pool.Stats()) })
+	}
+	counter := func(name, help string, value func(Stats) float64) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{Name: name, Help: help},
+			func() float64 { return value(pool.Stats()) })
+	}
+
+	for _, collector := range []prometheus.Collector{
+		gauge("ads_db_pool_acquired_connections", "Connections currently in use.",
+			func(s Stats) float64 { return float64(s.AcquiredConns) }),
+		gauge("ads_db_pool_idle_connections", "Idle connections in the pool.",
+			func(s Stats) float64 { return float64(s.IdleConns) }),
+		gauge("ads_db_pool_total_connections", "All open connections of the pool.",
+			func(s Stats) float64 { return float64(s.TotalConns) }),
+		gauge("ads_db_pool_max_connections", "Maximum size of the pool.",
+			func(s Stats) float64 { return float64(s.MaxConns) }),
+		counter("ads_db_pool_acquires_total", "Connections acquired from the pool.",
+			func(s Stats) float64 { return float64(s.AcquireCount) }),
+		counter("ads_db_pool_empty_acquires_total", "Acquires that waited because the pool had no idle connection.",
+			func(s Stats) float64 { return float64(s.EmptyAcquireCount) }),
+		counter("ads_db_pool_canceled_acquires_total", "Acquires canceled by their context.",
+			func(s Stats) float64 { return float64(s.CanceledAcquireCount) }),
+		counter("ads_db_pool_acquire_seconds_total", "Time spent acquiring connections.",
+			func(s Stats) float64 { return s.AcquireDuration.Seconds() }),
+	} {
+		if err := registerer.Register(collector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package db
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterMetrics(t *testing.T) {
+	pool := new(MockPool)
+	pool.On("Stats").Return(Stats{AcquiredConns: 3, MaxConns: 10, AcquireDuration: 1500 * time.Millisecond})
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, RegisterMetrics(registry, pool))
+
+	err := testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP ads_db_pool_acquired_connections Connections currently in use.
+# TYPE ads_db_pool_acquired_connections gauge
+ads_db_pool_acquired_connections 3
+# HELP ads_db_pool_max_connections Maximum size of the pool.
+# TYPE ads_db_pool_max_connections gauge
+ads_db_pool_max_connections 10
+# HELP ads_db_pool_acquire_seconds_total Time spent acquiring connections.
+# TYPE ads_db_pool_acquire_seconds_total counter
+ads_db_pool_acquire_seconds_total 1.5
+`), "ads_db_pool_acquired_connections", "ads_db_pool_max_connections", "ads_db_pool_acquire_seconds_total")
+	assert.NoError(t, err)
+	assert.Error(t, RegisterMetrics(registry, pool), "registering twice")
+}
@@ -42,6 +42,11 @@ func (m *MockPool) Exec(ctx context.Context, sql string, arguments ...interface{
 	return called.Get(0).(pgconn.CommandTag), called.Error(1)
 }
 
+func (m *MockPool) Stats() Stats {
+	called := m.Called()
+	return called.Get(0).(Stats)
+}
+
 type MockRow struct {
 	mock.Mock
 }
@@ -219,3 +224,15 @@ func (m *MockRows) Conn() *pgx.Conn {
 	args := m.Called()
 	return args.Get(0).(*pgx.Conn)
 }
+
+type MockTxManager struct {
+	mock.Mock
+}
+
+// WithinTx runs fn unless the mock is told to fail before it, like a failed Begin.
+func (m *MockTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := m.Called(ctx).Error(0); err != nil {
+		return err
+	}
+	return fn(ctx)
+}
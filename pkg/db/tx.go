@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TxManager runs a unit of work in one transaction.
+type TxManager interface {
+	// WithinTx commits when fn returns nil and rolls back otherwise. Every query made through the pool with
+	// the context passed to fn joins the transaction, a nested call joins the outer one.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type txManager struct {
+	pool Pool
+}
+
+func NewTxManager(pool Pool) TxManager {
+	return &txManager{pool: pool}
+}
+
+type txKey struct{}
+
+// txState - the transaction of a WithinTx call and the work to run once it commits.
+type txState struct {
+	tx          pgx.Tx
+	afterCommit []func(ctx context.Context)
+}
+
+func (m *txManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := txFrom(ctx); ok {
+		return fn(ctx)
+	}
+	tx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	committed := false
+	// also rolls back when fn panics
+	defer func() {
+		if !committed {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	state := &txState{tx: tx}
+	if err = fn(context.WithValue(ctx, txKey{}, state)); err != nil {
+		return err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+	for _, hook := range state.afterCommit {
+		hook(ctx)
+	}
+	return nil
+}
+
+// AfterCommit runs fn once the transaction of ctx commits, and never when it rolls back. Outside of
+// a transaction fn runs right away. It is meant for side effects whose failure is only logged, such as
+// notifications, a failed query inside the transaction would abort it. fn gets a context without the
+// transaction.
+func AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.afterCommit = append(state.afterCommit, fn)
+		return
+	}
+	fn(ctx)
+}
+
+// txFrom returns the transaction WithinTx put in the context.
+func txFrom(ctx context.Context) (pgx.Tx, bool) {
+	state, ok := ctx.Value(txKey{}).(*txState)
+	if !ok {
+		return nil, false
+	}
+	return state.tx, true
+}
@@ -0,0 +1,135 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTxManager_WithinTx(t *testing.T) {
+	t.Run("commits", func(t *testing.T) {
+		pool := new(MockPool)
+		tx := new(MockTx)
+		defer tx.AssertExpectations(t)
+
+		pool.On("Begin", mock.Anything).Return(tx, nil)
+		tx.On("Commit", mock.Anything).Return(nil)
+
+		err := NewTxManager(pool).WithinTx(context.Background(), func(ctx context.Context) error {
+			got, ok := txFrom(ctx)
+			assert.True(t, ok)
+			assert.Same(t, tx, got)
+			return nil
+		})
+		assert.NoError(t, err)
+		tx.AssertNotCalled(t, "Rollback", mock.Anything)
+	})
+
+	t.Run("rolls back on error", func(t *testing.T) {
+		pool := new(MockPool)
+		tx := new(MockTx)
+		defer tx.AssertExpectations(t)
+
+		pool.On("Begin", mock.Anything).Return(tx, nil)
+		tx.On("Rollback", mock.Anything).Return(nil)
+
+		failure := errors.New("insert failed")
+		err := NewTxManager(pool).WithinTx(context.Background(), func(context.Context) error {
+			return failure
+		})
+		assert.ErrorIs(t, err, failure)
+		tx.AssertNotCalled(t, "Commit", mock.Anything)
+	})
+
+	t.Run("rolls back on panic", func(t *testing.T) {
+		pool := new(MockPool)
+		tx := new(MockTx)
+		defer tx.AssertExpectations(t)
+
+		pool.On("Begin", mock.Anything).Return(tx, nil)
+		tx.On("Rollback", mock.Anything).Return(nil)
+
+		assert.Panics(t, func() {
+			_ = NewTxManager(pool).WithinTx(context.Background(), func(context.Context) error {
+				panic("boom")
+			})
+		})
+	})
+
+	t.Run("nested call joins the outer transaction", func(t *testing.T) {
+		pool := new(MockPool)
+		tx := new(MockTx)
+		defer pool.AssertNumberOfCalls(t, "Begin", 1)
+
+		pool.On("Begin", mock.Anything).Return(tx, nil)
+		tx.On("Commit", mock.Anything).Return(nil)
+
+		manager := NewTxManager(pool)
+		err := manager.WithinTx(context.Background(), func(ctx context.Context) error {
+			return manager.WithinTx(ctx, func(ctx context.Context) error {
+				got, _ := txFrom(ctx)
+				assert.Same(t, tx, got)
+				return nil
+			})
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("begin error", func(t *testing.T) {
+		pool := new(MockPool)
+		pool.On("Begin", mock.Anything).Return(new(MockTx), errors.New("pool closed"))
+
+		called := false
+		err := NewTxManager(pool).WithinTx(context.Background(), func(context.Context) error {
+			called = true
+			return nil
+		})
+		assert.Error(t, err)
+		assert.False(t, called)
+	})
+}
+
+func TestAfterCommit(t *testing.T) {
+	t.Run("runs after the commit", func(t *testing.T) {
+		pool := new(MockPool)
+		tx := new(MockTx)
+		pool.On("Begin", mock.Anything).Return(tx, nil)
+		tx.On("Commit", mock.Anything).Return(nil)
+
+		ran := false
+		err := NewTxManager(pool).WithinTx(context.Background(), func(ctx context.Context) error {
+			AfterCommit(ctx, func(ctx context.Context) {
+				_, inTx := txFrom(ctx)
+				assert.False(t, inTx)
+				ran = true
+			})
+			assert.False(t, ran)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.True(t, ran)
+	})
+
+	t.Run("skipped on rollback", func(t *testing.T) {
+		pool := new(MockPool)
+		tx := new(MockTx)
+		pool.On("Begin", mock.Anything).Return(tx, nil)
+		tx.On("Rollback", mock.Anything).Return(nil)
+
+		ran := false
+		_ = NewTxManager(pool).WithinTx(context.Background(), func(ctx context.Context) error {
+			AfterCommit(ctx, func(context.Context) { ran = true })
+			return errors.New("insert failed")
+		})
+		assert.False(t, ran)
+	})
+
+	t.Run("runs right away outside a transaction", func(t *testing.T) {
+		ran := false
+		AfterCommit(context.Background(), func(context.Context) { ran = true })
+		assert.True(t, ran)
+	})
+}
@@ -0,0 +1,79 @@
+package eventbus
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+	"sync"
+	"time"
+)
+
+const bufferSize = 32
+
+// New returns an in-process bus. It works within a single instance of the service only.
+func New() Bus {
+	return &memoryBus{subscribers: make(map[string]map[chan entities.Event]struct{})}
+}
+
+type memoryBus struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan entities.Event]struct{}
+	closed      bool
+}
+
+func (b *memoryBus) Publish(_ context.Context, event entities.Event) {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for ch := range b.subscribers[event.UserID] {
+		select {
+		case ch <- event:
+		default:
+			// the client does not keep up, losing a notification is better than blocking the use case
+		}
+	}
+}
+
+func (b *memoryBus) Subscribe(userID string) (<-chan entities.Event, func()) {
+	ch := make(chan entities.Event, bufferSize)
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		close(ch)
+		return ch, func() {}
+	}
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[chan entities.Event]struct{})
+	}
+	b.subscribers[userID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		// already gone after a repeated call or Close
+		if _, ok := b.subscribers[userID][ch]; !ok {
+			return
+		}
+		delete(b.subscribers[userID], ch)
+		if len(b.subscribers[userID]) == 0 {
+			delete(b.subscribers, userID)
+		}
+		close(ch)
+	}
+}
+
+func (b *memoryBus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, channels := range b.subscribers {
+		for ch := range channels {
+			close(ch)
+		}
+	}
+	b.subscribers = make(map[string]map[chan entities.Event]struct{})
+	b.closed = true
+}
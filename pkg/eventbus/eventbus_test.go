@@ -0,0 +1,78 @@
+package eventbus
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryBus_DeliversToRecipientOnly(t *testing.T) {
+	bus := New()
+	mine, unsubscribeMine := bus.Subscribe("user-1")
+	defer unsubscribeMine()
+	other, unsubscribeOther := bus.Subscribe("user-2")
+	defer unsubscribeOther()
+
+	bus.Publish(context.Background(), entities.Event{UserID: "user-1", Type: entities.EventAdApproved})
+
+	require.Len(t, mine, 1)
+	event := <-mine
+	assert.Equal(t, entities.EventAdApproved, event.Type)
+	assert.False(t, event.CreatedAt.IsZero())
+	assert.Empty(t, other)
+}
+
+func TestMemoryBus_EverySubscriptionGetsACopy(t *testing.T) {
+	bus := New()
+	first, unsubscribeFirst := bus.Subscribe("user-1")
+	defer unsubscribeFirst()
+	second, unsubscribeSecond := bus.Subscribe("user-1")
+	defer unsubscribeSecond()
+
+	bus.Publish(context.Background(), entities.Event{UserID: "user-1"})
+
+	assert.Len(t, first, 1)
+	assert.Len(t, second, 1)
+}
+
+func TestMemoryBus_SlowSubscriberDoesNotBlock(t *testing.T) {
+	bus := New()
+	events, unsubscribe := bus.Subscribe("user-1")
+	defer unsubscribe()
+
+	for i := 0; i < bufferSize+10; i++ {
+		bus.Publish(context.Background(), entities.Event{UserID: "user-1"})
+	}
+	assert.Len(t, events, bufferSize)
+}
+
+func TestMemoryBus_Unsubscribe(t *testing.T) {
+	bus := New()
+	events, unsubscribe := bus.Subscribe("user-1")
+	unsubscribe()
+	unsubscribe() // safe to call twice
+
+	bus.Publish(context.Background(), entities.Event{UserID: "user-1"})
+
+	_, open := <-events
+	assert.False(t, open)
+	assert.Empty(t, bus.(*memoryBus).subscribers)
+}
+
+func TestMemoryBus_Close(t *testing.T) {
+	bus := New()
+	events, unsubscribe := bus.Subscribe("user-1")
+
+	bus.Close()
+	unsubscribe() // the subscriber still cleans up after Close
+
+	_, open := <-events
+	assert.False(t, open)
+	late, _ := bus.Subscribe("user-2")
+	_, open = <-late
+	assert.False(t, open)
+	bus.Publish(context.Background(), entities.Event{UserID: "user-1"})
+}
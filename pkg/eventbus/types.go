@@ -0,0 +1,18 @@
+package eventbus
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+)
+
+// Bus - delivers events from the use-case layer to connected clients.
+// The in-process implementation can be replaced with a broker-backed one behind the same interface.
+type Bus interface {
+	// Publish never blocks: events for slow or absent subscribers are dropped.
+	Publish(ctx context.Context, event entities.Event)
+	// Subscribe returns a channel with events for the user and a function that must be called
+	// when the subscriber goes away.
+	Subscribe(userID string) (<-chan entities.Event, func())
+	// Close ends every subscription, their channels are closed. Later subscriptions get a closed channel.
+	Close()
+}
@@ -0,0 +1,70 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+// flushEvery - rows buffered before they are written out, so a streamed export is neither held in memory nor
+// written a row at a time.
+const flushEvery = 100
+
+type csvWriter struct {
+	out    *csv.Writer
+	sheets int
+	rows   int
+}
+
+func NewCSVWriter(w io.Writer) Writer {
+	return &csvWriter{out: csv.NewWriter(w)}
+}
+
+func (w *csvWriter) Sheet(name string, header ...string) error {
+	if w.sheets > 0 {
+		if err := w.out.Write([]string{""}); err != nil {
+			return err
+		}
+		if err := w.out.Write([]string{name}); err != nil {
+			return err
+		}
+	}
+	w.sheets++
+	return w.out.Write(header)
+}
+
+func (w *csvWriter) Row(values ...interface{}) error {
+	if w.sheets == 0 {
+		return ErrNoSheet
+	}
+	record := make([]string, len(values))
+	for i, value := range values {
+		text, number := formatValue(value)
+		if !number {
+			text = escapeFormula(text)
+		}
+		record[i] = text
+	}
+	if err := w.out.Write(record); err != nil {
+		return err
+	}
+	w.rows++
+	if w.rows%flushEvery != 0 {
+		return nil
+	}
+	w.out.Flush()
+	return w.out.Error()
+}
+
+func (w *csvWriter) Close() error {
+	w.out.Flush()
+	return w.out.Error()
+}
+
+// escapeFormula keeps spreadsheets from evaluating user text such as "=HYPERLINK(...)" as a formula.
+func escapeFormula(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
@@ -0,0 +1,116 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := NewCSVWriter(&out)
+
+	require.NoError(t, w.Sheet("ads", "id", "title", "created_at", "rate"))
+	require.NoError(t, w.Row(1, "Bike, red", time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC), 0.5))
+	require.NoError(t, w.Row(2, "=HYPERLINK(\"x\")", time.Time{}, -1))
+	require.NoError(t, w.Sheet("backlog", "age", "ads"))
+	require.NoError(t, w.Row("<1h", 3))
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, "id,title,created_at,rate\n"+
+		"1,\"Bike, red\",2026-03-01 10:00:00,0.5\n"+
+		"2,\"'=HYPERLINK(\"\"x\"\")\",,-1\n"+
+		"\n"+
+		"backlog\n"+
+		"age,ads\n"+
+		"<1h,3\n", out.String())
+}
+
+type countingWriter struct {
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return len(p), nil
+}
+
+func TestCSVWriter_FlushesInBatches(t *testing.T) {
+	out := new(countingWriter)
+	w := NewCSVWriter(out)
+
+	require.NoError(t, w.Sheet("ads", "id"))
+	for i := 0; i < flushEvery-1; i++ {
+		require.NoError(t, w.Row(i))
+	}
+	assert.Zero(t, out.writes)
+	require.NoError(t, w.Row(flushEvery))
+	assert.Equal(t, 1, out.writes)
+	require.NoError(t, w.Row(0))
+	require.NoError(t, w.Close())
+	assert.Equal(t, 2, out.writes)
+}
+
+func TestCSVWriter_RowBeforeSheet(t *testing.T) {
+	assert.ErrorIs(t, NewCSVWriter(io.Discard).Row(1), ErrNoSheet)
+}
+
+func readZip(t *testing.T, data []byte) map[string]string {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	files := make(map[string]string)
+	for _, f := range reader.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		body, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		files[f.Name] = string(body)
+	}
+	return files
+}
+
+func TestXLSXWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := NewXLSXWriter(&out)
+
+	require.NoError(t, w.Sheet("Ads", "id", "title"))
+	require.NoError(t, w.Row(7, "Bike & <helmet>"))
+	require.NoError(t, w.Sheet("By category: all", "category", "approved"))
+	require.NoError(t, w.Row("cars", 3))
+	require.NoError(t, w.Close())
+
+	files := readZip(t, out.Bytes())
+	assert.Contains(t, files, "[Content_Types].xml")
+	assert.Contains(t, files, "_rels/.rels")
+	assert.Contains(t, files["xl/workbook.xml"], `<sheet name="Ads" sheetId="1" r:id="rId1"/>`)
+	assert.Contains(t, files["xl/workbook.xml"], `<sheet name="By category_ all" sheetId="2" r:id="rId2"/>`)
+	assert.Contains(t, files["xl/_rels/workbook.xml.rels"], `Target="worksheets/sheet2.xml"`)
+	assert.Contains(t, files["xl/worksheets/sheet1.xml"],
+		`<row><c><v>7</v></c><c t="inlineStr"><is><t xml:space="preserve">Bike &amp; &lt;helmet&gt;</t></is></c></row>`)
+	assert.True(t, strings.HasSuffix(files["xl/worksheets/sheet2.xml"], "</sheetData></worksheet>"))
+}
+
+func TestXLSXWriter_Empty(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, NewXLSXWriter(&out).Close())
+
+	files := readZip(t, out.Bytes())
+	assert.Contains(t, files, "xl/worksheets/sheet1.xml")
+}
+
+func TestNew(t *testing.T) {
+	_, err := New("pdf", io.Discard)
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+
+	w, err := New(XLSX, io.Discard)
+	assert.NoError(t, err)
+	assert.IsType(t, &xlsxWriter{}, w)
+	assert.Equal(t, "text/csv; charset=utf-8", CSV.ContentType())
+}
@@ -0,0 +1,84 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+var (
+	ErrUnknownFormat = Error("unknown export format, expected csv or xlsx")
+	ErrNoSheet       = Error("row written before the first sheet")
+	ErrTooManyRows   = Error("too many rows for an XLSX sheet")
+)
+
+// Format - file format of an export.
+type Format string
+
+const (
+	CSV  Format = "csv"
+	XLSX Format = "xlsx"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// Writer - writes tables row by row without keeping them in memory. Values are strings, numbers, bools
+// and time.Time, anything else is formatted with fmt.
+type Writer interface {
+	// Sheet starts a table with the header row: a worksheet in XLSX. In CSV tables follow each other,
+	// every table after the first is preceded by an empty line and a row with its name.
+	Sheet(name string, header ...string) error
+	Row(values ...interface{}) error
+	// Close completes the file, the writer can't be used after it.
+	Close() error
+}
+
+func New(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case CSV:
+		return NewCSVWriter(w), nil
+	case XLSX:
+		return NewXLSXWriter(w), nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+func (f Format) ContentType() string {
+	if f == XLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// formatValue returns the text of a value and whether it is a number.
+func formatValue(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, false
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), false
+	case time.Time:
+		if v.IsZero() {
+			return "", false
+		}
+		return v.UTC().Format(timeLayout), false
+	case nil:
+		return "", false
+	default:
+		return fmt.Sprint(v), false
+	}
+}
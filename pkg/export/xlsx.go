@@ -0,0 +1,166 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// maxSheetRows and maxCellLength are the limits of Excel, longer text is cut.
+	maxSheetRows  = 1048576
+	maxCellLength = 32767
+)
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+// xlsxWriter writes an Office Open XML workbook. Sheets are streamed into the zip one after another with
+// inline strings, the workbook parts that list them are written on Close.
+type xlsxWriter struct {
+	zip    *zip.Writer
+	sheet  *bufio.Writer
+	names  []string
+	rows   int
+	closed bool
+}
+
+func NewXLSXWriter(w io.Writer) Writer {
+	return &xlsxWriter{zip: zip.NewWriter(w)}
+}
+
+func (w *xlsxWriter) Sheet(name string, header ...string) error {
+	if err := w.endSheet(); err != nil {
+		return err
+	}
+	part, err := w.zip.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(w.names)+1))
+	if err != nil {
+		return err
+	}
+	w.names = append(w.names, name)
+	w.sheet = bufio.NewWriter(part)
+	w.rows = 0
+	if _, err = w.sheet.WriteString(xmlHeader +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return err
+	}
+	values := make([]interface{}, len(header))
+	for i := range header {
+		values[i] = header[i]
+	}
+	return w.Row(values...)
+}
+
+func (w *xlsxWriter) Row(values ...interface{}) error {
+	if w.sheet == nil {
+		return ErrNoSheet
+	}
+	if w.rows == maxSheetRows {
+		return ErrTooManyRows
+	}
+	w.rows++
+	var row strings.Builder
+	row.WriteString("<row>")
+	for _, value := range values {
+		text, number := formatValue(value)
+		if number {
+			row.WriteString("<c><v>" + text + "</v></c>")
+			continue
+		}
+		if len(text) > maxCellLength {
+			text = strings.ToValidUTF8(text[:maxCellLength], "")
+		}
+		row.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+		if err := xml.EscapeText(&row, []byte(text)); err != nil {
+			return err
+		}
+		row.WriteString("</t></is></c>")
+	}
+	row.WriteString("</row>")
+	_, err := w.sheet.WriteString(row.String())
+	return err
+}
+
+func (w *xlsxWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if len(w.names) == 0 {
+		// a workbook needs a sheet
+		if err := w.Sheet("Sheet1"); err != nil {
+			return err
+		}
+	}
+	if err := w.endSheet(); err != nil {
+		return err
+	}
+
+	var types, sheets, rels strings.Builder
+	for i, name := range w.names {
+		fmt.Fprintf(&types, `<Override PartName="/xl/worksheets/sheet%d.xml" `+
+			`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+		fmt.Fprintf(&sheets, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, sheetName(name), i+1, i+1)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" `+
+			`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" `+
+			`Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ` +
+			`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			types.String() + `</Types>`},
+		{"_rels/.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" ` +
+			`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" ` +
+			`Target="xl/workbook.xml"/></Relationships>`},
+		{"xl/workbook.xml", `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+			`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>` +
+			sheets.String() + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels",
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+				rels.String() + `</Relationships>`},
+	}
+	for _, part := range parts {
+		f, err := w.zip.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err = io.WriteString(f, xmlHeader+part.body); err != nil {
+			return err
+		}
+	}
+	return w.zip.Close()
+}
+
+func (w *xlsxWriter) endSheet() error {
+	if w.sheet == nil {
+		return nil
+	}
+	if _, err := w.sheet.WriteString("</sheetData></worksheet>"); err != nil {
+		return err
+	}
+	err := w.sheet.Flush()
+	w.sheet = nil
+	return err
+}
+
+// sheetName makes a name Excel accepts: at most 31 characters without []:*?/\.
+func sheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+	var escaped strings.Builder
+	_ = xml.EscapeText(&escaped, []byte(name))
+	return escaped.String()
+}
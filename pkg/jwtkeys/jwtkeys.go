@@ -0,0 +1,215 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// New builds the key set from the keys in dir, signingKeyID picks the signing one.
+// Without dir the service falls back to HS256 with secret.
+func New(dir, signingKeyID, secret string) (*KeySet, error) {
+	var legacy []byte
+	if secret != "" {
+		legacy = []byte(secret)
+	}
+	if dir == "" {
+		return NewHMAC(legacy)
+	}
+	return LoadDir(dir, signingKeyID, legacy)
+}
+
+// NewHMAC returns a key set that signs and verifies HS256 tokens only.
+func NewHMAC(secret []byte) (*KeySet, error) {
+	if len(secret) == 0 {
+		return nil, ErrNoKeys
+	}
+	return &KeySet{keys: map[string]*key{}, legacySecret: secret}, nil
+}
+
+// LoadDir reads every *.pem file in dir. The file name without extension is the kid.
+// Private keys (PKCS#8 or PKCS#1) can sign, public keys (PKIX) only verify tokens of retired keys.
+// legacySecret may be nil; when set, HS256 tokens without kid are still accepted.
+func LoadDir(dir, signingKeyID string, legacySecret []byte) (*KeySet, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReadingKeys, err)
+	}
+
+	keys := make([]*key, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != pemExt {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrReadingKeys, err)
+		}
+		k, err := parsePEM(strings.TrimSuffix(entry.Name(), pemExt), data)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return newKeySet(signingKeyID, keys, legacySecret)
+}
+
+func newKeySet(signingKeyID string, keys []*key, legacySecret []byte) (*KeySet, error) {
+	set := &KeySet{keys: make(map[string]*key, len(keys)), legacySecret: legacySecret}
+	for _, k := range keys {
+		if _, ok := set.keys[k.id]; ok {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateKeyID, k.id)
+		}
+		set.keys[k.id] = k
+	}
+	if len(set.keys) == 0 {
+		if len(legacySecret) == 0 {
+			return nil, ErrNoKeys
+		}
+		return set, nil
+	}
+	signing, ok := set.keys[signingKeyID]
+	if !ok || signing.private == nil {
+		return nil, fmt.Errorf("%w: %q", ErrSigningKey, signingKeyID)
+	}
+	set.signing = signing
+	return set, nil
+}
+
+// Sign signs the claims with the active key and puts its id into the kid header.
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	if s.signing == nil {
+		if len(s.legacySecret) == 0 {
+			return "", ErrLegacyKeyMissing
+		}
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.legacySecret)
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrSigningToken, err)
+		}
+		return signed, nil
+	}
+
+	token := jwt.NewWithClaims(s.signing.method, claims)
+	token.Header["kid"] = s.signing.id
+	signed, err := token.SignedString(s.signing.private)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrSigningToken, err)
+	}
+	return signed, nil
+}
+
+// Parse verifies the token signature against the key named by its kid header and fills claims.
+func (s *KeySet) Parse(raw string, claims jwt.Claims) (*jwt.Token, error) {
+	token, err := jwt.ParseWithClaims(raw, claims, s.keyFunc,
+		jwt.WithValidMethods([]string{
+			jwt.SigningMethodRS256.Alg(),
+			jwt.SigningMethodEdDSA.Alg(),
+			jwt.SigningMethodHS256.Alg(),
+		}))
+	if err != nil {
+		return nil, fmt.Errorf("parse token: %w", err)
+	}
+	return token, nil
+}
+
+func (s *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if len(s.legacySecret) == 0 || token.Method != jwt.SigningMethodHS256 {
+			return nil, ErrUnknownKey
+		}
+		return s.legacySecret, nil
+	}
+	k, ok := s.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	if token.Method.Alg() != k.method.Alg() {
+		return nil, ErrMethodMismatch
+	}
+	return k.public, nil
+}
+
+// JWKS returns public parts of all asymmetric keys, sorted by kid.
+func (s *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: make([]JWK, 0, len(s.keys))}
+	for _, k := range s.keys {
+		jwk := JWK{Kid: k.id, Use: "sig", Alg: k.method.Alg()}
+		switch pub := k.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
+
+func parsePEM(id string, data []byte) (*key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPEM, id)
+	}
+
+	var (
+		parsed interface{}
+		err    error
+	)
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%w: %s: unexpected block %q", ErrInvalidPEM, id, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidPEM, id, err)
+	}
+
+	k := &key{id: id}
+	switch typed := parsed.(type) {
+	case *rsa.PrivateKey:
+		k.private, k.public = typed, &typed.PublicKey
+	case ed25519.PrivateKey:
+		k.private, k.public = typed, typed.Public()
+	case *rsa.PublicKey, ed25519.PublicKey:
+		k.public = typed
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedKey, id)
+	}
+	return k, k.setMethod()
+}
+
+func (k *key) setMethod() error {
+	switch pub := k.public.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSABits {
+			return fmt.Errorf("%w: %s", ErrWeakRSAKey, k.id)
+		}
+		k.method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		k.method = jwt.SigningMethodEdDSA
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedKey, k.id)
+	}
+	return nil
+}
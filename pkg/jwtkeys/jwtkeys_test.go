@@ -0,0 +1,195 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePrivate(t *testing.T, dir, kid string, private interface{}) {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, kid+".pem"), data, 0o600))
+}
+
+func writePublic(t *testing.T, dir, kid string, public interface{}) {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(public)
+	require.NoError(t, err)
+	data := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, kid+".pem"), data, 0o600))
+}
+
+func claims() jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{
+		Subject:   "user-1",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	t.Run("ed25519 sign and verify", func(t *testing.T) {
+		dir := t.TempDir()
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		writePrivate(t, dir, "ed-1", private)
+
+		keys, err := LoadDir(dir, "ed-1", nil)
+		require.NoError(t, err)
+
+		raw, err := keys.Sign(claims())
+		require.NoError(t, err)
+
+		parsed := &jwt.RegisteredClaims{}
+		token, err := keys.Parse(raw, parsed)
+		require.NoError(t, err)
+		assert.Equal(t, "ed-1", token.Header["kid"])
+		assert.Equal(t, "EdDSA", token.Method.Alg())
+		assert.Equal(t, "user-1", parsed.Subject)
+	})
+
+	t.Run("rotation keeps old tokens valid", func(t *testing.T) {
+		dir := t.TempDir()
+		oldPublic, oldPrivate, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		writePrivate(t, dir, "old", oldPrivate)
+
+		oldKeys, err := LoadDir(dir, "old", nil)
+		require.NoError(t, err)
+		raw, err := oldKeys.Sign(claims())
+		require.NoError(t, err)
+
+		rsaKey, err := rsa.GenerateKey(rand.Reader, minRSABits)
+		require.NoError(t, err)
+		writePrivate(t, dir, "new", rsaKey)
+		writePublic(t, dir, "old", oldPublic)
+
+		keys, err := LoadDir(dir, "new", nil)
+		require.NoError(t, err)
+
+		_, err = keys.Parse(raw, &jwt.RegisteredClaims{})
+		assert.NoError(t, err)
+
+		fresh, err := keys.Sign(claims())
+		require.NoError(t, err)
+		token, err := keys.Parse(fresh, &jwt.RegisteredClaims{})
+		require.NoError(t, err)
+		assert.Equal(t, "RS256", token.Method.Alg())
+
+		jwks := keys.JWKS()
+		require.Len(t, jwks.Keys, 2)
+		assert.Equal(t, "new", jwks.Keys[0].Kid)
+		assert.Equal(t, "RSA", jwks.Keys[0].Kty)
+		assert.NotEmpty(t, jwks.Keys[0].N)
+		assert.Equal(t, "AQAB", jwks.Keys[0].E)
+		assert.Equal(t, "old", jwks.Keys[1].Kid)
+		assert.Equal(t, "OKP", jwks.Keys[1].Kty)
+		assert.Equal(t, "Ed25519", jwks.Keys[1].Crv)
+	})
+
+	t.Run("public key cannot sign", func(t *testing.T) {
+		dir := t.TempDir()
+		public, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		writePublic(t, dir, "retired", public)
+
+		_, err = LoadDir(dir, "retired", nil)
+		assert.ErrorIs(t, err, ErrSigningKey)
+	})
+
+	t.Run("missing dir", func(t *testing.T) {
+		_, err := LoadDir(filepath.Join(t.TempDir(), "nope"), "k", nil)
+		assert.ErrorIs(t, err, ErrReadingKeys)
+	})
+
+	t.Run("invalid pem", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.pem"), []byte("garbage"), 0o600))
+
+		_, err := LoadDir(dir, "bad", nil)
+		assert.ErrorIs(t, err, ErrInvalidPEM)
+	})
+
+	t.Run("empty dir without legacy secret", func(t *testing.T) {
+		_, err := LoadDir(t.TempDir(), "", nil)
+		assert.ErrorIs(t, err, ErrNoKeys)
+	})
+}
+
+func TestKeySet_Parse(t *testing.T) {
+	dir := t.TempDir()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	writePrivate(t, dir, "ed-1", private)
+
+	t.Run("legacy token accepted during migration", func(t *testing.T) {
+		legacy, err := NewHMAC([]byte("secret"))
+		require.NoError(t, err)
+		raw, err := legacy.Sign(claims())
+		require.NoError(t, err)
+
+		keys, err := LoadDir(dir, "ed-1", []byte("secret"))
+		require.NoError(t, err)
+		_, err = keys.Parse(raw, &jwt.RegisteredClaims{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("legacy token rejected without secret", func(t *testing.T) {
+		legacy, err := NewHMAC([]byte("secret"))
+		require.NoError(t, err)
+		raw, err := legacy.Sign(claims())
+		require.NoError(t, err)
+
+		keys, err := LoadDir(dir, "ed-1", nil)
+		require.NoError(t, err)
+		_, err = keys.Parse(raw, &jwt.RegisteredClaims{})
+		assert.ErrorIs(t, err, ErrUnknownKey)
+	})
+
+	t.Run("unknown kid", func(t *testing.T) {
+		otherDir := t.TempDir()
+		_, otherPrivate, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		writePrivate(t, otherDir, "other", otherPrivate)
+		other, err := LoadDir(otherDir, "other", nil)
+		require.NoError(t, err)
+		raw, err := other.Sign(claims())
+		require.NoError(t, err)
+
+		keys, err := LoadDir(dir, "ed-1", nil)
+		require.NoError(t, err)
+		_, err = keys.Parse(raw, &jwt.RegisteredClaims{})
+		assert.ErrorIs(t, err, ErrUnknownKey)
+	})
+
+	t.Run("hmac key set has empty jwks", func(t *testing.T) {
+		keys, err := NewHMAC([]byte("secret"))
+		require.NoError(t, err)
+		assert.Empty(t, keys.JWKS().Keys)
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Run("no keys", func(t *testing.T) {
+		_, err := New("", "", "")
+		assert.ErrorIs(t, err, ErrNoKeys)
+	})
+
+	t.Run("legacy secret", func(t *testing.T) {
+		keys, err := New("", "", "secret")
+		require.NoError(t, err)
+		assert.Nil(t, keys.signing)
+	})
+}
@@ -0,0 +1,67 @@
+package jwtkeys
+
+import (
+	"crypto"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+var (
+	ErrNoKeys           = Error("no JWT keys configured")
+	ErrSigningKey       = Error("signing key not found among private keys")
+	ErrUnknownKey       = Error("token signed with unknown key")
+	ErrMethodMismatch   = Error("token signing method does not match the key")
+	ErrUnsupportedKey   = Error("unsupported key type, use RSA (>= 2048 bits) or Ed25519")
+	ErrInvalidPEM       = Error("invalid PEM key file")
+	ErrReadingKeys      = Error("failed to read JWT keys directory")
+	ErrSigningToken     = Error("failed to sign token")
+	ErrDuplicateKeyID   = Error("duplicate key id")
+	ErrWeakRSAKey       = Error("RSA key is shorter than 2048 bits")
+	ErrLegacyKeyMissing = Error("legacy HS256 secret is not configured")
+)
+
+const (
+	minRSABits = 2048
+	pemExt     = ".pem"
+)
+
+// KeySet - keys used to sign and verify JWTs.
+// Only one key signs new tokens, every key in the set is accepted for verification,
+// so a key can be rotated without logging everyone out.
+type KeySet struct {
+	signing *key
+	keys    map[string]*key
+	// legacySecret verifies (and signs, when there is no asymmetric key) HS256 tokens
+	// without a kid header. It is never published.
+	legacySecret []byte
+}
+
+type key struct {
+	private crypto.Signer
+	public  crypto.PublicKey
+	method  jwt.SigningMethod
+	id      string
+}
+
+// JWK - public key in RFC 7517 format.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS - document served at /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
@@ -1,96 +1,129 @@
 package customLogger
 
 import (
-	"ads-service/pkg/utils"
 	"context"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"os"
-	"sync"
-	"time"
 )
 
-// Init - Инициализация логгера.
+// NewLogger - логгер с настройками по умолчанию.
 func NewLogger(ctx context.Context) (Logger, error) {
-	logFile := fmt.Sprintf("storage/logs/%s.log", time.Now().Format("2006-01-02"))
-	var wg sync.WaitGroup
-	logCh := make(chan string, chunkSize)
+	return New(ctx, Options{})
+}
 
-	if !utils.IsSafeLogPath(logFile) {
-		return Logger{}, ErrFileOpening
+// New - Инициализация логгера. После отмены ctx файл закрывается, см. Wait; записи после этого идут в stderr.
+func New(ctx context.Context, opts Options) (Logger, error) {
+	opts = opts.withDefaults()
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(opts.Level)); err != nil {
+		return Logger{}, fmt.Errorf("%w: %w", ErrInvalidOptions, err)
+	}
+	if opts.Format != FormatText && opts.Format != FormatJSON {
+		return Logger{}, fmt.Errorf("%w: unknown format %q", ErrInvalidOptions, opts.Format)
 	}
 
-	// #nosec G304 -- путь проверяется функцией IsSafeLogPath
-	file, err := os.OpenFile(logFile, os.O_CREATE|os.O_APPEND|os.O_RDWR, permission)
+	file, err := openRotating(opts)
 	if err != nil {
-		return Logger{}, ErrFileOpening
+		return Logger{}, err
+	}
+	var out io.Writer = file
+	if opts.Stdout {
+		out = io.MultiWriter(file, os.Stdout)
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler = slog.NewTextHandler(out, handlerOpts)
+	if opts.Format == FormatJSON {
+		handler = slog.NewJSONHandler(out, handlerOpts)
 	}
 
-	wg.Add(1)
+	done := make(chan struct{})
 	go func() {
-		defer wg.Done()
-		defer func() {
-			if cerr := file.Close(); cerr != nil {
-				log.Println("Error file closing") // log ERROR
-			}
-		}()
-
-		for {
-			select {
-			case msg := <-logCh:
-				if _, err := file.WriteString(msg); err != nil {
-					log.Printf("Ошибка записи в лог: %v\n", err)
-				}
-			case <-ctx.Done():
-				for {
-					select {
-					case msg := <-logCh:
-						_, err := file.WriteString(msg)
-						if err != nil {
-							return
-						}
-					default:
-						return
-					}
-				}
-			}
+		defer close(done)
+		<-ctx.Done()
+		if err := file.Close(); err != nil {
+			fmt.Fprintln(os.Stderr, "Error file closing:", err)
 		}
 	}()
 
 	return Logger{
-		logger:  log.New(os.Stdout, "", log.LstdFlags|log.Ltime),
-		file:    file,
-		logChan: logCh,
+		slog: slog.New(contextHandler{handler}),
+		done: done,
 	}, nil
 }
 
-func (l *Logger) ERROR(v ...interface{}) {
-	currentTime := time.Now().Format(time.RFC3339)
-	msg := fmt.Sprint(v...)
-	log.Println(currentTime, msg)
-	select {
-	case l.logChan <- fmt.Sprintf("[%v] [ERROR]: %v\n", currentTime, msg):
-	default:
-		log.Printf("[%v] [ERROR]: logging channel overflow", currentTime)
+func (o Options) withDefaults() Options {
+	defaults := DefaultOptions()
+	if o.Dir == "" {
+		o.Dir = defaults.Dir
+	}
+	if o.Level == "" {
+		o.Level = defaults.Level
+	}
+	if o.Format == "" {
+		o.Format = defaults.Format
 	}
+	if o.RotateEvery <= 0 {
+		o.RotateEvery = defaults.RotateEvery
+	}
+	if o.Retention <= 0 {
+		o.Retention = defaults.Retention
+	}
+	if o.MaxSize <= 0 {
+		o.MaxSize = defaults.MaxSize
+	}
+	return o
 }
 
-func (l *Logger) INFO(v ...interface{}) {
-	currentTime := time.Now().Format(time.RFC3339)
-	msg := fmt.Sprint(v...)
-	select {
-	case l.logChan <- fmt.Sprintf("[%v] [INFO]: %v\n", currentTime, msg):
-	default:
-		log.Printf("[%v] [INFO]: logging channel overflow", currentTime)
+// Wait - ждёт, пока после отмены ctx файл будет закрыт.
+func (l *Logger) Wait() {
+	if l.done != nil {
+		<-l.done
 	}
 }
 
+// Slog - структурированный логгер. Поля request_id и user_id берутся из контекста, см. WithRequestID.
+func (l *Logger) Slog() *slog.Logger {
+	if l.slog == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+	return l.slog
+}
+
+func (l *Logger) ERROR(v ...interface{}) {
+	l.log(slog.LevelError, v)
+}
+
+func (l *Logger) INFO(v ...interface{}) {
+	l.log(slog.LevelInfo, v)
+}
+
 func (l *Logger) WARN(v ...interface{}) {
-	currentTime := time.Now().Format(time.RFC3339)
-	msg := fmt.Sprint(v...)
-	select {
-	case l.logChan <- fmt.Sprintf("[%v] [WARN]: %v\n", currentTime, msg):
-	default:
-		log.Printf("[%v] [WARN]: logging channel overflow", currentTime)
+	l.log(slog.LevelWarn, v)
+}
+
+// ErrorContext - как ERROR, но запись получает request_id, user_id и trace_id из ctx.
+func (l *Logger) ErrorContext(ctx context.Context, v ...interface{}) {
+	l.logContext(ctx, slog.LevelError, v)
+}
+
+func (l *Logger) InfoContext(ctx context.Context, v ...interface{}) {
+	l.logContext(ctx, slog.LevelInfo, v)
+}
+
+func (l *Logger) WarnContext(ctx context.Context, v ...interface{}) {
+	l.logContext(ctx, slog.LevelWarn, v)
+}
+
+func (l *Logger) log(level slog.Level, v []interface{}) {
+	l.logContext(context.Background(), level, v)
+}
+
+func (l *Logger) logContext(ctx context.Context, level slog.Level, v []interface{}) {
+	if l.slog == nil {
+		return
 	}
+	l.slog.Log(ctx, level, fmt.Sprint(v...))
 }
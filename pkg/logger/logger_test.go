@@ -3,56 +3,137 @@ package customLogger
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
-	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// readLogs останавливает логгер и возвращает содержимое всех файлов каталога.
+func readLogs(t *testing.T, cancel context.CancelFunc, logger Logger, dir string) string {
+	t.Helper()
+	cancel()
+	logger.Wait()
+	files, err := filepath.Glob(filepath.Join(dir, "*.log"))
+	require.NoError(t, err)
+	var content string
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		content += string(data)
+	}
+	return content
+}
+
 func TestLoggerMethods(t *testing.T) {
+	dir := t.TempDir()
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	logger, err := NewLogger(ctx)
-	assert.NoError(t, err)
+	logger, err := New(ctx, Options{Dir: dir})
+	require.NoError(t, err)
 
-	// Проверяем, что методы не паникуют
-	assert.NotPanics(t, func() { logger.ERROR("test error") })
-	assert.NotPanics(t, func() { logger.INFO("test info") })
-	assert.NotPanics(t, func() { logger.WARN("test warn") })
+	logger.ERROR("test error ", 1)
+	logger.INFO("test info")
+	logger.WARN("test warn")
 
-	time.Sleep(10 * time.Millisecond)
+	content := readLogs(t, cancel, logger, dir)
+	assert.Contains(t, content, `level=ERROR msg="test error 1"`)
+	assert.Contains(t, content, `level=INFO msg="test info"`)
+	assert.Contains(t, content, `level=WARN msg="test warn"`)
 }
 
-func TestLogger_ErrorOverflow(t *testing.T) {
+func TestLogger_Level(t *testing.T) {
+	dir := t.TempDir()
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	logger, err := NewLogger(ctx)
-	assert.NoError(t, err)
+	logger, err := New(ctx, Options{Dir: dir, Level: "warn"})
+	require.NoError(t, err)
 
-	// Переполняем канал логов
-	for i := range chunkSize + 10 {
-		logger.ERROR("overflow test", i)
-	}
-	time.Sleep(10 * time.Millisecond)
+	logger.INFO("skipped")
+	logger.WARN("written")
+
+	content := readLogs(t, cancel, logger, dir)
+	assert.NotContains(t, content, "skipped")
+	assert.Contains(t, content, "written")
+}
+
+func TestLogger_ContextFields(t *testing.T) {
+	dir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger, err := New(ctx, Options{Dir: dir, Format: FormatJSON})
+	require.NoError(t, err)
+
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1}, SpanID: trace.SpanID{2}, TraceFlags: trace.FlagsSampled})
+	reqCtx := trace.ContextWithSpanContext(context.Background(), spanCtx)
+	reqCtx = WithUserID(WithRequestID(reqCtx, "req-1"), "user-1")
+	logger.Slog().With("component", "test").InfoContext(reqCtx, "ad created", "ad_id", 5)
+	assert.Equal(t, "req-1", RequestID(reqCtx))
+
+	content := readLogs(t, cancel, logger, dir)
+	assert.Contains(t, content, `"msg":"ad created"`)
+	assert.Contains(t, content, `"component":"test"`)
+	assert.Contains(t, content, `"ad_id":5`)
+	assert.Contains(t, content, `"request_id":"req-1"`)
+	assert.Contains(t, content, `"user_id":"user-1"`)
+	assert.Contains(t, content, `"trace_id":"`+spanCtx.TraceID().String()+`"`)
 }
 
-func TestLogger_INFO(t *testing.T) {
+func TestLogger_ContextMethods(t *testing.T) {
+	dir := t.TempDir()
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	logger, err := NewLogger(ctx)
-	assert.NoError(t, err)
-	assert.NotNil(t, logger)
+	logger, err := New(ctx, Options{Dir: dir})
+	require.NoError(t, err)
+
+	reqCtx := WithRequestID(context.Background(), "req-2")
+	logger.ErrorContext(reqCtx, "select failed: ", 7)
+	logger.InfoContext(reqCtx, "ad saved")
+	logger.WarnContext(reqCtx, "slow query")
+
+	content := readLogs(t, cancel, logger, dir)
+	assert.Contains(t, content, `level=ERROR msg="select failed: 7" request_id=req-2`)
+	assert.Contains(t, content, `level=INFO msg="ad saved" request_id=req-2`)
+	assert.Contains(t, content, `level=WARN msg="slow query" request_id=req-2`)
+}
+
+func TestLogger_InvalidOptions(t *testing.T) {
+	_, err := New(context.Background(), Options{Dir: t.TempDir(), Level: "loud"})
+	assert.ErrorIs(t, err, ErrInvalidOptions)
+
+	_, err = New(context.Background(), Options{Dir: t.TempDir(), Format: "xml"})
+	assert.ErrorIs(t, err, ErrInvalidOptions)
 
-	logger.INFO("test info message")
+	_, err = New(context.Background(), Options{Dir: filepath.Join(t.TempDir(), "missing")})
+	assert.ErrorIs(t, err, ErrFileOpening)
+}
+
+func TestLogger_ZeroValue(t *testing.T) {
+	var logger Logger
+	assert.NotPanics(t, func() {
+		logger.ERROR("dropped")
+		logger.Slog().Info("dropped")
+		logger.Wait()
+	})
+}
+
+func TestLogger_Wait(t *testing.T) {
+	dir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	logger, err := New(ctx, Options{Dir: dir})
+	require.NoError(t, err)
 
-	// Проверяем, что логгер не nil и канал логов создан
-	assert.NotNil(t, logger.logChan)
-	assert.NotNil(t, logger.file)
+	logger.INFO("written before shutdown")
+	content := readLogs(t, cancel, logger, dir)
+	assert.Contains(t, content, "written before shutdown")
 
-	// Проверяем, что файл существует
-	_, statErr := os.Stat(logger.file.Name())
-	assert.NoError(t, statErr)
+	// после закрытия файла записи уходят в stderr
+	assert.NotPanics(t, func() { logger.INFO("written to stderr") })
 }
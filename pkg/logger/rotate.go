@@ -0,0 +1,164 @@
+package customLogger
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rotatingFile - файл текущего периода, например 2026-10-19.log. После MaxSize байт пишется 2026-10-19.1.log
+// и так далее. При каждом открытии файла удаляются файлы старше Retention и сверх MaxBackups.
+type rotatingFile struct {
+	mu     sync.Mutex
+	now    func() time.Time
+	file   *os.File
+	period time.Time
+	opts   Options
+	size   int64
+	index  int
+	closed bool
+}
+
+func openRotating(opts Options) (*rotatingFile, error) {
+	r := &rotatingFile{opts: opts, now: time.Now}
+	now := r.now()
+	if err := r.open(now.Truncate(opts.RotateEvery), 0, now); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return os.Stderr.Write(p)
+	}
+
+	now := r.now()
+	period := now.Truncate(r.opts.RotateEvery)
+	switch {
+	case !period.Equal(r.period):
+		if err := r.reopen(period, 0, now); err != nil {
+			return 0, err
+		}
+	case r.size > 0 && r.size+int64(len(p)) > r.opts.MaxSize:
+		if err := r.reopen(period, r.index+1, now); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	if err != nil {
+		return n, fmt.Errorf("%w: %w", ErrFileWriting, err)
+	}
+	return n, nil
+}
+
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("%w: %w", ErrFileClosing, err)
+	}
+	return nil
+}
+
+func (r *rotatingFile) reopen(period time.Time, index int, now time.Time) error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("%w: %w", ErrFileClosing, err)
+	}
+	return r.open(period, index, now)
+}
+
+// open appends to the last file of the period from index on, or starts the next one when it is full.
+func (r *rotatingFile) open(period time.Time, index int, now time.Time) error {
+	for {
+		if _, err := os.Stat(r.path(period, index+1)); err != nil {
+			break
+		}
+		index++
+	}
+	for ; ; index++ {
+		path := r.path(period, index)
+		// #nosec G304 -- каталог задаётся администратором в конфигурации
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, permission)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrFileOpening, err)
+		}
+		info, err := file.Stat()
+		if err != nil {
+			_ = file.Close()
+			return fmt.Errorf("%w: %w", ErrFileOpening, err)
+		}
+		if info.Size() > 0 && info.Size() >= r.opts.MaxSize {
+			_ = file.Close()
+			continue
+		}
+		r.file, r.period, r.index, r.size = file, period, index, info.Size()
+		r.cleanup(now)
+		return nil
+	}
+}
+
+func (r *rotatingFile) path(period time.Time, index int) string {
+	return filepath.Join(filepath.Clean(r.opts.Dir), r.name(period, index))
+}
+
+func (r *rotatingFile) name(period time.Time, index int) string {
+	layout := "2006-01-02"
+	if r.opts.RotateEvery%(24*time.Hour) != 0 {
+		layout = "2006-01-02T15-04"
+	}
+	name := period.UTC().Format(layout)
+	if index > 0 {
+		name = fmt.Sprintf("%s.%d", name, index)
+	}
+	return name + ".log"
+}
+
+// cleanup removes old log files, errors are only reported, logging goes on.
+func (r *rotatingFile) cleanup(now time.Time) {
+	entries, err := os.ReadDir(r.opts.Dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error reading log dir:", err)
+		return
+	}
+	current := filepath.Base(r.file.Name())
+	var old []fs.FileInfo
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".log") || entry.Name() == current {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		old = append(old, info)
+	}
+	sort.Slice(old, func(i, j int) bool {
+		return old[i].ModTime().After(old[j].ModTime())
+	})
+
+	for i, info := range old {
+		expired := now.Sub(info.ModTime()) > r.opts.Retention
+		if !expired && (r.opts.MaxBackups == 0 || i < r.opts.MaxBackups) {
+			continue
+		}
+		err := os.Remove(filepath.Join(r.opts.Dir, info.Name()))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintln(os.Stderr, "Error removing old log file:", err)
+		}
+	}
+}
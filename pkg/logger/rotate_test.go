@@ -0,0 +1,93 @@
+package customLogger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRotating(t *testing.T, opts Options, now *time.Time) *rotatingFile {
+	t.Helper()
+	r := &rotatingFile{opts: opts.withDefaults(), now: func() time.Time { return *now }}
+	require.NoError(t, r.open((*now).Truncate(r.opts.RotateEvery), 0, *now))
+	t.Cleanup(func() { _ = r.Close() })
+	return r
+}
+
+func logNames(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func TestRotatingFile_Size(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	r := newTestRotating(t, Options{Dir: dir, MaxSize: 10}, &now)
+
+	for range 3 {
+		_, err := r.Write([]byte("12345678\n"))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"2026-10-19.1.log", "2026-10-19.2.log", "2026-10-19.log"}, logNames(t, dir))
+
+	// после перезапуска запись продолжается в незаполненный файл
+	require.NoError(t, r.Close())
+	r = newTestRotating(t, Options{Dir: dir, MaxSize: 10}, &now)
+	assert.Equal(t, 2, r.index)
+}
+
+func TestRotatingFile_Period(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 10, 19, 23, 59, 0, 0, time.UTC)
+	r := newTestRotating(t, Options{Dir: dir, RotateEvery: time.Hour}, &now)
+
+	_, err := r.Write([]byte("before\n"))
+	require.NoError(t, err)
+	now = now.Add(2 * time.Minute)
+	_, err = r.Write([]byte("after\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"2026-10-19T23-00.log", "2026-10-20T00-00.log"}, logNames(t, dir))
+	data, err := os.ReadFile(filepath.Join(dir, "2026-10-20T00-00.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "after\n", string(data))
+}
+
+func TestRotatingFile_Retention(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	for i, name := range []string{"2026-10-01.log", "2026-10-16.log", "2026-10-17.log", "2026-10-18.log"} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte("old\n"), 0o600))
+		modTime := now.AddDate(0, 0, -18)
+		if i > 0 {
+			modTime = now.AddDate(0, 0, i-4)
+		}
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0o600))
+
+	newTestRotating(t, Options{Dir: dir, MaxBackups: 2}, &now)
+	assert.Equal(t, []string{"2026-10-17.log", "2026-10-18.log", "2026-10-19.log", "notes.txt"}, logNames(t, dir))
+}
+
+func TestRotatingFile_Closed(t *testing.T) {
+	now := time.Now()
+	r := newTestRotating(t, Options{Dir: t.TempDir()}, &now)
+	require.NoError(t, r.Close())
+	require.NoError(t, r.Close())
+
+	n, err := r.Write([]byte("to stderr\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 10, n)
+}
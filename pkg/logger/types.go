@@ -1,8 +1,8 @@
 package customLogger
 
 import (
-	"log"
-	"os"
+	"log/slog"
+	"time"
 )
 
 type Error string
@@ -12,18 +12,52 @@ func (e Error) Error() string {
 }
 
 var (
-	ErrFileWriting = Error("ошибка записи в лог")
-	ErrFileOpening = Error("ошибка открытия лог файла")
-	ErrFileClosing = Error("ошибка закрытия лог файла")
+	ErrFileWriting    = Error("ошибка записи в лог")
+	ErrFileOpening    = Error("ошибка открытия лог файла")
+	ErrFileClosing    = Error("ошибка закрытия лог файла")
+	ErrInvalidOptions = Error("неверные настройки логгера")
 )
 
 const (
 	permission = 0o600
-	chunkSize  = 100
+
+	FormatText = "text"
+	FormatJSON = "json"
 )
 
+// Options - настройки логгера. Нулевые поля заменяются значениями по умолчанию, см. DefaultOptions.
+type Options struct {
+	// Dir - каталог лог файлов, должен существовать.
+	Dir string
+	// Level - debug, info, warn или error.
+	Level  string
+	Format string
+	// RotateEvery - новый файл начинается в начале каждого периода, файл назван датой периода.
+	RotateEvery time.Duration
+	// Retention - файлы старше удаляются при ротации.
+	Retention time.Duration
+	// MaxSize - размер файла в байтах, после которого начинается следующий файл того же периода.
+	MaxSize int64
+	// MaxBackups - сколько старых файлов хранить, 0 - без ограничения.
+	MaxBackups int
+	// Stdout - дублировать записи в stdout.
+	Stdout bool
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Dir:         "storage/logs",
+		Level:       "info",
+		Format:      FormatText,
+		RotateEvery: 24 * time.Hour,
+		Retention:   7 * 24 * time.Hour,
+		MaxSize:     100 << 20,
+	}
+}
+
+// Logger - адаптер старого интерфейса ERROR/INFO/WARN над slog. Новый код пишет через Slog с контекстом.
+// Нулевое значение ничего не пишет.
 type Logger struct {
-	logger  *log.Logger
-	file    *os.File
-	logChan chan string
+	slog *slog.Logger
+	done <-chan struct{}
 }
@@ -0,0 +1,118 @@
+package notifier
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+const defaultSender = "noreply@ads.local"
+
+// sendTimeout bounds the whole SMTP conversation when the context has no earlier deadline,
+// a stalled relay must not hold the caller.
+const sendTimeout = 10 * time.Second
+
+type sendMailFunc func(ctx context.Context, addr, from string, to []string, msg []byte) error
+
+// NewEmailNotifier sends plain text emails through the SMTP server at addr without authentication,
+// which is enough for a local relay or MailHog.
+func NewEmailNotifier(addr, from string) Notifier {
+	if from == "" {
+		from = defaultSender
+	}
+	return &emailNotifier{addr: addr, from: from, send: sendMail}
+}
+
+type emailNotifier struct {
+	send sendMailFunc
+	addr string
+	from string
+}
+
+func (n *emailNotifier) Notify(ctx context.Context, msg entities.Notification) error {
+	if msg.Email == "" {
+		return ErrNoAddress
+	}
+	if msg.CreatedAt.IsZero() {
+		msg.CreatedAt = time.Now().UTC()
+	}
+	if err := n.send(ctx, n.addr, n.from, []string{msg.Email}, n.compose(msg)); err != nil {
+		return fmt.Errorf("%w: %w", ErrSending, err)
+	}
+	return nil
+}
+
+func (n *emailNotifier) compose(msg entities.Notification) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.from)
+	fmt.Fprintf(&b, "To: %s\r\n", headerValue(msg.Email))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", headerValue(msg.Subject)))
+	fmt.Fprintf(&b, "Date: %s\r\n", msg.CreatedAt.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// sendMail is smtp.SendMail without authentication, bound to ctx: the dial and every read and write
+// stop at the deadline of ctx or after sendTimeout, and a canceled ctx breaks the connection.
+func sendMail(ctx context.Context, addr, from string, to []string, msg []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	dialer := &net.Dialer{Timeout: sendTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	if err = conn.SetDeadline(deadline); err != nil {
+		_ = conn.Close()
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	host, _, _ := net.SplitHostPort(addr)
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}); err != nil {
+			return err
+		}
+	}
+	if err = client.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err = client.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(msg); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// headerValue keeps user supplied text from adding headers of its own.
+func headerValue(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
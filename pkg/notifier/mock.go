@@ -0,0 +1,20 @@
+//nolint:all // файл содержит моки для тестов, проверки линтеров не требуются
+package notifier
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) Notify(ctx context.Context, n entities.Notification) error {
+	args := m.Called(ctx, n)
+	return args.Error(0)
+}
+
+var _ Notifier = (*MockNotifier)(nil)
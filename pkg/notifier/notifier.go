@@ -0,0 +1,120 @@
+package notifier
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const filePerm = 0o600
+
+const (
+	channelSMS   = "sms"
+	channelEmail = "email"
+)
+
+// New returns the development notifier: notifications are written as JSON lines
+// to the file at path, or to stdout when path is empty.
+func New(path string) (Notifier, error) {
+	out, err := openOutput(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewWriterNotifier(out), nil
+}
+
+// NewChannels returns the external channels. SMS is a stub written like New does.
+// Email is sent from smtpFrom to the SMTP server at smtpAddr (a local stand-in such as MailHog
+// in development) and is written like SMS when smtpAddr is empty.
+func NewChannels(path, smtpAddr, smtpFrom string) (Channels, error) {
+	out, err := openOutput(path)
+	if err != nil {
+		return Channels{}, err
+	}
+	channels := Channels{
+		SMS:   NewSMSNotifier(out),
+		Email: &writerNotifier{w: out, channel: channelEmail},
+	}
+	if smtpAddr != "" {
+		channels.Email = NewEmailNotifier(smtpAddr, smtpFrom)
+	}
+	return channels, nil
+}
+
+func openOutput(path string) (io.Writer, error) {
+	if path == "" {
+		return os.Stdout, nil
+	}
+	// #nosec G304 -- путь задаётся администратором в конфигурации
+	file, err := os.OpenFile(filepath.Clean(path), os.O_CREATE|os.O_APPEND|os.O_WRONLY, filePerm)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrOpeningFile, err)
+	}
+	return file, nil
+}
+
+// NewWriterNotifier writes every notification as a single JSON line to w.
+func NewWriterNotifier(w io.Writer) Notifier {
+	return &writerNotifier{w: w}
+}
+
+// NewSMSNotifier is an SMS gateway stub: the text that would be sent to the phone is written to w.
+func NewSMSNotifier(w io.Writer) Notifier {
+	return &writerNotifier{w: w, channel: channelSMS}
+}
+
+type writerNotifier struct {
+	mu      sync.Mutex
+	w       io.Writer
+	channel string
+}
+
+type record struct {
+	CreatedAt time.Time `json:"created_at"`
+	Channel   string    `json:"channel,omitempty"`
+	To        string    `json:"to,omitempty"`
+	UserID    string    `json:"user_id"`
+	Subject   string    `json:"subject"`
+	Body      string    `json:"body"`
+}
+
+func (n *writerNotifier) Notify(_ context.Context, msg entities.Notification) error {
+	if msg.CreatedAt.IsZero() {
+		msg.CreatedAt = time.Now().UTC()
+	}
+	rec := record{
+		CreatedAt: msg.CreatedAt,
+		Channel:   n.channel,
+		UserID:    msg.UserID,
+		Subject:   msg.Subject,
+		Body:      msg.Body,
+	}
+	switch n.channel {
+	case channelSMS:
+		rec.To = msg.Phone
+	case channelEmail:
+		rec.To = msg.Email
+	}
+	if n.channel != "" && rec.To == "" {
+		return ErrNoAddress
+	}
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrWriting, err)
+	}
+	line = append(line, '\n')
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if _, err = n.w.Write(line); err != nil {
+		return fmt.Errorf("%w: %w", ErrWriting, err)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package notifier
+
+import (
+	"ads-service/internal/domain/entities"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestWriterNotifier(t *testing.T) {
+	var buf bytes.Buffer
+	n := NewWriterNotifier(&buf)
+
+	for _, subject := range []string{"first", "second"} {
+		err := n.Notify(context.Background(), entities.Notification{UserID: "user-1", Subject: subject, Body: "text"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	var got record
+	if err := json.Unmarshal([]byte(lines[1]), &got); err != nil {
+		t.Fatalf("invalid json line: %v", err)
+	}
+	if got.UserID != "user-1" || got.Subject != "second" || got.CreatedAt.IsZero() {
+		t.Errorf("unexpected record: %+v", got)
+	}
+}
+
+func TestWriterNotifier_WriteError(t *testing.T) {
+	err := NewWriterNotifier(failingWriter{}).Notify(context.Background(), entities.Notification{})
+	if !errors.Is(err, ErrWriting) {
+		t.Errorf("expected %v, got %v", ErrWriting, err)
+	}
+}
+
+func TestNew_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.log")
+
+	n, err := New(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = n.Notify(context.Background(), entities.Notification{UserID: "user-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading file: %v", err)
+	}
+	if !strings.Contains(string(data), `"user_id":"user-1"`) {
+		t.Errorf("notification not written: %s", data)
+	}
+}
+
+func TestSMSNotifier(t *testing.T) {
+	var buf bytes.Buffer
+	n := NewSMSNotifier(&buf)
+
+	if err := n.Notify(context.Background(), entities.Notification{UserID: "user-1"}); !errors.Is(err, ErrNoAddress) {
+		t.Errorf("expected %v, got %v", ErrNoAddress, err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("nothing should be written without a phone: %s", buf.String())
+	}
+
+	err := n.Notify(context.Background(), entities.Notification{UserID: "user-1", Phone: "+998901234567"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got record
+	if err = json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid json line: %v", err)
+	}
+	if got.Channel != "sms" || got.To != "+998901234567" {
+		t.Errorf("unexpected record: %+v", got)
+	}
+}
+
+func TestEmailNotifier(t *testing.T) {
+	var (
+		sentTo  []string
+		message string
+	)
+	n := NewEmailNotifier("localhost:1025", "").(*emailNotifier)
+	n.send = func(_ context.Context, addr, from string, to []string, msg []byte) error {
+		if addr != "localhost:1025" || from != defaultSender {
+			t.Errorf("unexpected server %s or sender %s", addr, from)
+		}
+		sentTo, message = to, string(msg)
+		return nil
+	}
+
+	err := n.Notify(context.Background(), entities.Notification{
+		Email:   "buyer@example.com",
+		Subject: "Объявление опубликовано\r\nBcc: victim@example.com",
+		Body:    "line one\nline two",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sentTo) != 1 || sentTo[0] != "buyer@example.com" {
+		t.Errorf("unexpected recipients: %v", sentTo)
+	}
+	if strings.Contains(message, "\r\nBcc:") {
+		t.Errorf("subject injected a header: %q", message)
+	}
+	if !strings.Contains(message, "Subject: =?utf-8?q?") || !strings.HasSuffix(message, "line one\r\nline two") {
+		t.Errorf("unexpected message: %q", message)
+	}
+}
+
+func TestEmailNotifier_Errors(t *testing.T) {
+	n := NewEmailNotifier("localhost:1025", "ads@example.com").(*emailNotifier)
+	n.send = func(context.Context, string, string, []string, []byte) error {
+		return errors.New("connection refused")
+	}
+
+	if err := n.Notify(context.Background(), entities.Notification{}); !errors.Is(err, ErrNoAddress) {
+		t.Errorf("expected %v, got %v", ErrNoAddress, err)
+	}
+	err := n.Notify(context.Background(), entities.Notification{Email: "buyer@example.com"})
+	if !errors.Is(err, ErrSending) {
+		t.Errorf("expected %v, got %v", ErrSending, err)
+	}
+}
+
+func TestEmailNotifier_StalledServer(t *testing.T) {
+	// accepts the connection and never greets, the send must give up at the deadline of the context
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(time.Second)
+		}
+	}()
+
+	n := NewEmailNotifier(listener.Addr().String(), "")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	err = n.Notify(ctx, entities.Notification{Email: "buyer@example.com"})
+	if !errors.Is(err, ErrSending) {
+		t.Errorf("expected %v, got %v", ErrSending, err)
+	}
+	if elapsed := time.Since(started); elapsed > 500*time.Millisecond {
+		t.Errorf("send waited %v for a stalled server", elapsed)
+	}
+}
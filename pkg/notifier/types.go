@@ -0,0 +1,31 @@
+package notifier
+
+import (
+	"ads-service/internal/domain/entities"
+	"context"
+)
+
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+var (
+	ErrOpeningFile = Error("failed to open notifications file")
+	ErrWriting     = Error("failed to write notification")
+	ErrNoAddress   = Error("recipient has no address for this channel")
+	ErrSending     = Error("failed to send notification")
+)
+
+// Notifier - delivers a notification to its recipient.
+// Implementations must be safe for concurrent use.
+type Notifier interface {
+	Notify(ctx context.Context, n entities.Notification) error
+}
+
+// Channels - external delivery channels, the in-app one is backed by the database.
+type Channels struct {
+	SMS   Notifier
+	Email Notifier
+}
@@ -0,0 +1,108 @@
+// Package simhash builds 64-bit fingerprints that stay close for similar inputs: SimHash of character
+// shingles for texts and the difference hash (dHash) for images. Similarity of two fingerprints is
+// the share of equal bits.
+package simhash
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash/fnv"
+	"image"
+	"math/bits"
+	"strings"
+	"unicode"
+)
+
+const shingleSize = 4 // characters per shingle, short enough for ads of a few sentences
+
+// Normalize lowercases the text and keeps only words separated by single spaces,
+// punctuation and formatting do not change fingerprints.
+func Normalize(text string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(text), func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	}), " ")
+}
+
+// Exact returns a hex SHA-256 of the normalized text, equal only for the same words in the same order.
+func Exact(text string) string {
+	sum := sha256.Sum256([]byte(Normalize(text)))
+	return hex.EncodeToString(sum[:])
+}
+
+// Text returns the SimHash of the normalized text built from overlapping shingles of four characters.
+// Texts shorter than a shingle are hashed as a single shingle, an empty text gives 0.
+func Text(text string) uint64 {
+	chars := []rune(Normalize(text))
+	if len(chars) == 0 {
+		return 0
+	}
+	var weights [64]int
+	for i := 0; i == 0 || i+shingleSize <= len(chars); i++ {
+		end := min(i+shingleSize, len(chars))
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(string(chars[i:end])))
+		sum := h.Sum64()
+		for b := range 64 {
+			if sum&(1<<b) != 0 {
+				weights[b]++
+			} else {
+				weights[b]--
+			}
+		}
+	}
+	var hash uint64
+	for b, w := range weights {
+		if w > 0 {
+			hash |= 1 << b
+		}
+	}
+	return hash
+}
+
+// Image returns the dHash of the image: it is shrunk to 9x8 gray pixels and every bit tells
+// whether a pixel is brighter than its right neighbour. Resizing and recompression keep the hash.
+func Image(img image.Image) uint64 {
+	const width, height = 9, 8
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return 0
+	}
+
+	var gray [height][width]float64
+	for y := range height {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(bounds.Min.Y+(y+1)*bounds.Dy()/height, y0+1)
+		for x := range width {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(bounds.Min.X+(x+1)*bounds.Dx()/width, x0+1)
+			gray[y][x] = averageLuma(img, x0, y0, x1, y1)
+		}
+	}
+
+	var hash uint64
+	for y := range height {
+		for x := range width - 1 {
+			hash <<= 1
+			if gray[y][x] > gray[y][x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+func averageLuma(img image.Image, x0, y0, x1, y1 int) float64 {
+	var sum float64
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+		}
+	}
+	return sum / float64((x1-x0)*(y1-y0))
+}
+
+// Similarity returns the share of equal bits, 1 for equal fingerprints.
+func Similarity(a, b uint64) float64 {
+	return 1 - float64(bits.OnesCount64(a^b))/64
+}
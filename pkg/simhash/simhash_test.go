@@ -0,0 +1,60 @@
+package simhash
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func gradient(width, height int, invert bool) image.Image {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			v := uint8((x*7 + y*3) * 255 / (width*7 + height*3))
+			if (x/(width/4))%2 == 1 {
+				v = 255 - v
+			}
+			if invert {
+				v = 255 - v
+			}
+			img.SetGray(x, y, color.Gray{Y: v})
+		}
+	}
+	return img
+}
+
+func TestText(t *testing.T) {
+	original := "Selling iPhone 13 Pro, 256 GB, graphite. Battery 89%, no scratches, box and charger included."
+
+	assert.Equal(t, Text(original),
+		Text("selling IPHONE 13 pro 256 gb graphite battery 89 no scratches box and charger included"))
+	assert.Greater(t, Similarity(Text(original),
+		Text("Selling iPhone 13 Pro, 256 GB, graphite. Battery 88%, no scratches, box and charger included.")), 0.9)
+	assert.Less(t, Similarity(Text(original),
+		Text("Selling iPhone 12 mini, 128 GB, blue. Battery 80%, small scratch, no box.")), 0.8)
+	assert.Less(t, Similarity(Text(original),
+		Text("Wooden dining table for six people, oak, pick up in Chilanzar district only")), 0.8)
+	assert.Zero(t, Text(" ... "))
+	assert.NotZero(t, Text("bicycle"))
+}
+
+func TestExact(t *testing.T) {
+	assert.Equal(t, Exact("Red bicycle, like new!"), Exact("red  BICYCLE like new"))
+	assert.NotEqual(t, Exact("red bicycle like new"), Exact("new bicycle like red"))
+}
+
+func TestImage(t *testing.T) {
+	original := Image(gradient(400, 300, false))
+
+	assert.GreaterOrEqual(t, Similarity(original, Image(gradient(200, 150, false))), 0.95)
+	assert.Less(t, Similarity(original, Image(gradient(400, 300, true))), 0.5)
+	assert.Zero(t, Image(image.NewGray(image.Rect(0, 0, 0, 0))))
+}
+
+func TestSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, Similarity(42, 42))
+	assert.Equal(t, 0.0, Similarity(0, ^uint64(0)))
+	assert.Equal(t, 0.75, Similarity(0, 0xFFFF))
+}
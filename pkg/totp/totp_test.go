@@ -0,0 +1,78 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Secret "12345678901234567890" from RFC 6238 appendix B.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode_RFCVectors(t *testing.T) {
+	cases := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, want := range cases {
+		code, err := Code(rfcSecret, time.Unix(unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, want, code, "time %d", unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	code, err := Code(rfcSecret, now)
+	require.NoError(t, err)
+
+	t.Run("current step", func(t *testing.T) {
+		step, ok := Validate(rfcSecret, code, now)
+		assert.True(t, ok)
+		assert.Equal(t, Step(now), step)
+	})
+
+	t.Run("previous step within skew", func(t *testing.T) {
+		step, ok := Validate(rfcSecret, code, now.Add(Period*time.Second))
+		assert.True(t, ok)
+		assert.Equal(t, Step(now), step)
+	})
+
+	t.Run("outside skew", func(t *testing.T) {
+		_, ok := Validate(rfcSecret, code, now.Add(3*Period*time.Second))
+		assert.False(t, ok)
+	})
+
+	t.Run("wrong code", func(t *testing.T) {
+		_, ok := Validate(rfcSecret, "000000", now)
+		assert.False(t, ok)
+	})
+
+	t.Run("invalid secret", func(t *testing.T) {
+		_, ok := Validate("not base32!", code, now)
+		assert.False(t, ok)
+	})
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	_, err = Code(secret, time.Now())
+	assert.NoError(t, err)
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("GoAds", "+998901234567", rfcSecret)
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/GoAds:+998901234567?"))
+	assert.Contains(t, uri, "secret="+rfcSecret)
+	assert.Contains(t, uri, "issuer=GoAds")
+}
@@ -14,5 +14,9 @@ func ValidateAd(a *entities.Ad) error {
 		return utilserr.ErrCategoryRequired
 	}
 
+	if a.Price < 0 {
+		return utilserr.ErrNegativePrice
+	}
+
 	return nil
 }
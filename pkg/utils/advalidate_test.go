@@ -31,6 +31,18 @@ func TestValidateAd(t *testing.T) {
 		}
 	})
 
+	t.Run("valid ad with negative price", func(t *testing.T) {
+		ad := &entities.Ad{
+			Title:      "Valid Ad",
+			CategoryID: 1,
+			Price:      -1,
+		}
+		err := ValidateAd(ad)
+		if !errors.Is(err, utilserr.ErrNegativePrice) {
+			t.Errorf("expected %v, got %v", utilserr.ErrNegativePrice, err)
+		}
+	})
+
 	t.Run("valid ad with all fields", func(t *testing.T) {
 		ad := &entities.Ad{
 			Title:      "Valid Ad",
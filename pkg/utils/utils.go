@@ -3,7 +3,6 @@ package utils
 import (
 	"errors"
 	"log"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -17,22 +16,31 @@ const minPasswordLength = 8 // Minimum password length
 type CustomClaims struct {
 	jwt.RegisteredClaims
 	UserID string `json:"user_id"`
+	// Purpose is set for tokens that are not valid for API access, e.g. "mfa" for the login challenge.
+	Purpose string `json:"purpose,omitempty"`
+	// MFA - the session was opened with a second factor.
+	MFA bool `json:"mfa,omitempty"`
 }
 
-// Put it into env variable or config file in production
+const PurposeMFA = "mfa"
 
-func GenerateToken(userID string, duration int) (string, error) {
+// TokenSigner - signs JWT claims with the active key, implemented by jwtkeys.KeySet.
+type TokenSigner interface {
+	Sign(claims jwt.Claims) (string, error)
+}
+
+func GenerateToken(signer TokenSigner, userID string, duration int) (string, error) {
+	return SignClaims(signer, CustomClaims{UserID: userID}, duration)
+}
+
+// SignClaims sets iat and exp (duration in minutes) on the claims and signs them.
+func SignClaims(signer TokenSigner, claims CustomClaims, duration int) (string, error) {
 	expAt := time.Duration(duration) * time.Minute
-	// Create claims with user data
-	claims := CustomClaims{
-		UserID: userID,
-		RegisteredClaims: jwt.RegisteredClaims{
-			IssuedAt:  jwt.NewNumericDate(time.Now().Local()),            // iat
-			ExpiresAt: jwt.NewNumericDate(time.Now().Local().Add(expAt)), // exp
-		},
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		IssuedAt:  jwt.NewNumericDate(time.Now().Local()),            // iat
+		ExpiresAt: jwt.NewNumericDate(time.Now().Local().Add(expAt)), // exp
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte(os.Getenv("JWT_SECRET_KEY")))
+	signedToken, err := signer.Sign(claims)
 	if err != nil {
 		log.Printf("failed to sign token: %v", err)
 		return "", errors.New("failed to sign token")
@@ -1,13 +1,16 @@
 package utils
 
 import (
-	"os"
+	"ads-service/pkg/jwtkeys"
 	"testing"
 )
 
 func TestGenerateToken(t *testing.T) {
-	_ = os.Setenv("JWT_SECRET_KEY", "testsecret")
-	token, err := GenerateToken("user-1", 10)
+	keys, err := jwtkeys.NewHMAC([]byte("testsecret"))
+	if err != nil {
+		t.Fatalf("ошибка при создании ключей: %v", err)
+	}
+	token, err := GenerateToken(keys, "user-1", 10)
 	if err != nil {
 		t.Errorf("ошибка при генерации токена: %v", err)
 	}